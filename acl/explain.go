@@ -0,0 +1,229 @@
+package acl
+
+import (
+	"strings"
+)
+
+// ExplainedRule identifies the policy rule that rendered an enforcement
+// decision.
+type ExplainedRule struct {
+	// Resource is the resource the rule was defined for. It differs from the
+	// requested resource when the rule of another resource is used as a
+	// fallback, such as the operator rule for mesh requests.
+	Resource Resource
+
+	// Segment is the name or prefix the rule was defined for. It is empty for
+	// resources without segments such as acl or operator.
+	Segment string
+
+	// Prefix is true for prefix rules such as key_prefix.
+	Prefix bool
+
+	// Access is the access level of the rule.
+	Access string
+}
+
+// Explanation describes how an Authorizer reached an enforcement decision.
+type Explanation struct {
+	Decision EnforcementDecision
+
+	// Authorizer is the Authorizer that rendered the decision. For a
+	// ChainedAuthorizer, it is the first Authorizer in the chain making a
+	// decision.
+	Authorizer Authorizer
+
+	// Rule is the rule that rendered the decision. It is nil when the decision
+	// was not made by a single policy rule, for example when it comes from a
+	// default policy or covers every intention.
+	Rule *ExplainedRule
+}
+
+// explainer is implemented by the Authorizers able to tell which of their
+// rules rendered a decision.
+type explainer interface {
+	explainRule(rsc Resource, segment string, access string) *ExplainedRule
+}
+
+// Explain enforces the access like Enforce does and additionally reports the
+// Authorizer and the rule that rendered the decision.
+func Explain(authz Authorizer, rsc Resource, segment string, access string, ctx *AuthorizerContext) (*Explanation, error) {
+	decision, err := Enforce(authz, rsc, segment, access, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	explanation := &Explanation{Decision: decision}
+	explanation.Authorizer, explanation.Rule = explainDecision(authz, rsc, segment, access, ctx)
+	return explanation, nil
+}
+
+func explainDecision(authz Authorizer, rsc Resource, segment string, access string, ctx *AuthorizerContext) (Authorizer, *ExplainedRule) {
+	switch a := authz.(type) {
+	case *ChainedAuthorizer:
+		for _, link := range a.chain {
+			// Errors were already reported by Enforce on the whole chain.
+			if decision, _ := Enforce(link, rsc, segment, access, ctx); decision != Default {
+				return explainDecision(link, rsc, segment, access, ctx)
+			}
+		}
+		// The chain denies access when no Authorizer made a decision.
+		return authz, nil
+	case explainer:
+		return authz, a.explainRule(rsc, segment, strings.ToLower(access))
+	}
+	return authz, nil
+}
+
+// DefinedIn returns whether the rules contain the explained rule. As policies
+// are merged before being enforced, this tells which of the policies of a
+// token granted or denied access.
+func (r *ExplainedRule) DefinedIn(rules *PolicyRules) bool {
+	switch r.Resource {
+	case ResourceACL:
+		return r.hasAccess(rules.ACL)
+	case ResourceKeyring:
+		return r.hasAccess(rules.Keyring)
+	case ResourceMesh:
+		return r.hasAccess(rules.Mesh)
+	case ResourceOperator:
+		return r.hasAccess(rules.Operator)
+	case ResourceAgent:
+		list := rules.Agents
+		if r.Prefix {
+			list = rules.AgentPrefixes
+		}
+		for _, rule := range list {
+			if rule.Node == r.Segment && r.hasAccess(rule.Policy) {
+				return true
+			}
+		}
+	case ResourceEvent:
+		list := rules.Events
+		if r.Prefix {
+			list = rules.EventPrefixes
+		}
+		for _, rule := range list {
+			if rule.Event == r.Segment && r.hasAccess(rule.Policy) {
+				return true
+			}
+		}
+	case ResourceIntention:
+		list := rules.Services
+		if r.Prefix {
+			list = rules.ServicePrefixes
+		}
+		for _, rule := range list {
+			if rule.Name == r.Segment && r.hasAccess(rule.intentionPolicy()) {
+				return true
+			}
+		}
+	case ResourceKey:
+		list := rules.Keys
+		if r.Prefix {
+			list = rules.KeyPrefixes
+		}
+		for _, rule := range list {
+			if rule.Prefix == r.Segment && r.hasAccess(rule.Policy) {
+				return true
+			}
+		}
+	case ResourceNode:
+		list := rules.Nodes
+		if r.Prefix {
+			list = rules.NodePrefixes
+		}
+		for _, rule := range list {
+			if rule.Name == r.Segment && r.hasAccess(rule.Policy) {
+				return true
+			}
+		}
+	case ResourceQuery:
+		list := rules.PreparedQueries
+		if r.Prefix {
+			list = rules.PreparedQueryPrefixes
+		}
+		for _, rule := range list {
+			if rule.Prefix == r.Segment && r.hasAccess(rule.Policy) {
+				return true
+			}
+		}
+	case ResourceService:
+		list := rules.Services
+		if r.Prefix {
+			list = rules.ServicePrefixes
+		}
+		for _, rule := range list {
+			if rule.Name == r.Segment && r.hasAccess(rule.Policy) {
+				return true
+			}
+		}
+	case ResourceSession:
+		list := rules.Sessions
+		if r.Prefix {
+			list = rules.SessionPrefixes
+		}
+		for _, rule := range list {
+			if rule.Node == r.Segment && r.hasAccess(rule.Policy) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r *ExplainedRule) hasAccess(policy string) bool {
+	access, err := AccessLevelFromString(policy)
+	return err == nil && access.String() == r.Access
+}
+
+// explainRule returns the rule that the enforcement method for the resource
+// and access would use.
+func (p *policyAuthorizer) explainRule(rsc Resource, segment string, access string) *ExplainedRule {
+	var rule *policyAuthorizerRule
+	switch rsc {
+	case ResourceACL:
+		rule = p.aclRule
+	case ResourceAgent:
+		rule, _ = getPolicy(segment, p.agentRules)
+	case ResourceEvent:
+		rule, _ = getPolicy(segment, p.eventRules)
+	case ResourceIntention:
+		// Decisions for the wildcard depend on every intention rule.
+		if segment != WildcardName {
+			rule, _ = getPolicy(segment, p.intentionRules)
+		}
+	case ResourceKey:
+		if access == "write-prefix" {
+			rule = p.keyWritePrefixRule(segment)
+		} else {
+			rule, _ = getPolicy(segment, p.keyRules)
+		}
+	case ResourceKeyring:
+		rule = p.keyringRule
+	case ResourceMesh:
+		rule = p.meshRule
+		if rule == nil {
+			rsc, rule = ResourceOperator, p.operatorRule
+		}
+	case ResourceNode:
+		rule, _ = getPolicy(segment, p.nodeRules)
+	case ResourceOperator:
+		rule = p.operatorRule
+	case ResourceQuery:
+		rule, _ = getPolicy(segment, p.preparedQueryRules)
+	case ResourceService:
+		rule, _ = getPolicy(segment, p.serviceRules)
+	case ResourceSession:
+		rule, _ = getPolicy(segment, p.sessionRules)
+	}
+
+	if rule == nil {
+		return nil
+	}
+	return &ExplainedRule{
+		Resource: rsc,
+		Segment:  rule.segment,
+		Prefix:   rule.prefix,
+		Access:   rule.access.String(),
+	}
+}
@@ -0,0 +1,154 @@
+package acl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplain(t *testing.T) {
+	rules := `
+		key_prefix "" {
+			policy = "read"
+		}
+		key_prefix "app/" {
+			policy = "write"
+		}
+		key "app/secret" {
+			policy = "deny"
+		}
+		service_prefix "web" {
+			policy = "write"
+		}
+		operator = "read"
+	`
+	policy, err := NewPolicyFromSource(rules, SyntaxCurrent, nil, nil)
+	require.NoError(t, err)
+	policyAuthz, err := NewPolicyAuthorizer([]*Policy{policy}, nil)
+	require.NoError(t, err)
+	authz := NewChainedAuthorizer([]Authorizer{policyAuthz, DenyAll()})
+
+	type testCase struct {
+		resource Resource
+		segment  string
+		access   string
+		expect   *Explanation
+	}
+
+	cases := map[string]testCase{
+		"prefix rule allows": {
+			resource: ResourceKey,
+			segment:  "app/config",
+			access:   "write",
+			expect: &Explanation{
+				Decision: Allow,
+				Rule:     &ExplainedRule{Resource: ResourceKey, Segment: "app/", Prefix: true, Access: "write"},
+			},
+		},
+		"exact rule denies": {
+			resource: ResourceKey,
+			segment:  "app/secret",
+			access:   "read",
+			expect: &Explanation{
+				Decision: Deny,
+				Rule:     &ExplainedRule{Resource: ResourceKey, Segment: "app/secret", Access: "deny"},
+			},
+		},
+		"catch-all prefix rule denies write": {
+			resource: ResourceKey,
+			segment:  "other",
+			access:   "write",
+			expect: &Explanation{
+				Decision: Deny,
+				Rule:     &ExplainedRule{Resource: ResourceKey, Segment: "", Prefix: true, Access: "read"},
+			},
+		},
+		"sub-rule denies write-prefix": {
+			resource: ResourceKey,
+			segment:  "app/",
+			access:   "write-prefix",
+			expect: &Explanation{
+				Decision: Deny,
+				Rule:     &ExplainedRule{Resource: ResourceKey, Segment: "app/secret", Access: "deny"},
+			},
+		},
+		"intention rule derived from service rule": {
+			resource: ResourceIntention,
+			segment:  "web-api",
+			access:   "write",
+			expect: &Explanation{
+				Decision: Deny,
+				Rule:     &ExplainedRule{Resource: ResourceIntention, Segment: "web", Prefix: true, Access: "read"},
+			},
+		},
+		"mesh falls back to operator rule": {
+			resource: ResourceMesh,
+			access:   "read",
+			expect: &Explanation{
+				Decision: Allow,
+				Rule:     &ExplainedRule{Resource: ResourceOperator, Access: "read"},
+			},
+		},
+		"default authorizer decides": {
+			resource: ResourceNode,
+			segment:  "foo",
+			access:   "read",
+			expect:   &Explanation{Decision: Deny},
+		},
+	}
+
+	for name, tcase := range cases {
+		t.Run(name, func(t *testing.T) {
+			explanation, err := Explain(authz, tcase.resource, tcase.segment, tcase.access, nil)
+			require.NoError(t, err)
+			require.Equal(t, tcase.expect.Decision, explanation.Decision)
+			require.Equal(t, tcase.expect.Rule, explanation.Rule)
+			if tcase.expect.Rule != nil {
+				require.Equal(t, policyAuthz, explanation.Authorizer)
+				require.True(t, explanation.Rule.DefinedIn(&policy.PolicyRules))
+			} else {
+				require.Equal(t, DenyAll(), explanation.Authorizer)
+			}
+		})
+	}
+
+	t.Run("invalid access", func(t *testing.T) {
+		_, err := Explain(authz, ResourceKey, "foo", "manage", nil)
+		require.Error(t, err)
+	})
+}
+
+func TestExplainedRule_DefinedIn(t *testing.T) {
+	policy, err := NewPolicyFromSource(`
+		service "web" {
+			policy = "write"
+			intentions = "deny"
+		}
+		key_prefix "app/" {
+			policy = "list"
+		}
+		acl = "read"
+	`, SyntaxCurrent, nil, nil)
+	require.NoError(t, err)
+
+	cases := map[string]struct {
+		rule   ExplainedRule
+		expect bool
+	}{
+		"matching service rule":      {ExplainedRule{Resource: ResourceService, Segment: "web", Access: "write"}, true},
+		"different access":           {ExplainedRule{Resource: ResourceService, Segment: "web", Access: "read"}, false},
+		"prefix instead of exact":    {ExplainedRule{Resource: ResourceService, Segment: "web", Prefix: true, Access: "write"}, false},
+		"explicit intentions":        {ExplainedRule{Resource: ResourceIntention, Segment: "web", Access: "deny"}, true},
+		"matching key prefix rule":   {ExplainedRule{Resource: ResourceKey, Segment: "app/", Prefix: true, Access: "list"}, true},
+		"matching acl rule":          {ExplainedRule{Resource: ResourceACL, Access: "read"}, true},
+		"missing operator rule":      {ExplainedRule{Resource: ResourceOperator, Access: "read"}, false},
+		"missing node rule":          {ExplainedRule{Resource: ResourceNode, Segment: "web", Access: "write"}, false},
+		"matching segment elsewhere": {ExplainedRule{Resource: ResourceKey, Segment: "web", Access: "write"}, false},
+	}
+
+	for name, tcase := range cases {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tcase.expect, tcase.rule.DefinedIn(&policy.PolicyRules))
+		})
+	}
+}
@@ -129,6 +129,21 @@ type ServiceRule struct {
 	EnterpriseRule `hcl:",squash"`
 }
 
+// intentionPolicy returns the policy for intentions where this service is the
+// destination, defaulting to read for services that can be read or written
+// and to deny otherwise.
+func (sp *ServiceRule) intentionPolicy() string {
+	if sp.Intentions != "" {
+		return sp.Intentions
+	}
+	switch sp.Policy {
+	case PolicyRead, PolicyWrite:
+		return PolicyRead
+	default:
+		return PolicyDeny
+	}
+}
+
 // SessionRule represents a rule for making sessions tied to specific node
 // name prefixes.
 type SessionRule struct {
@@ -53,6 +53,11 @@ type policyAuthorizerRule struct {
 	// decision is the enforcement decision for this rule
 	access AccessLevel
 
+	// segment and prefix record the name or prefix the rule was defined
+	// for, so that decisions can be explained.
+	segment string
+	prefix  bool
+
 	// Embedded Consul Enterprise specific policy
 	EnterpriseRule
 }
@@ -96,7 +101,9 @@ func insertPolicyIntoRadix(segment string, policy string, ent *EnterpriseRule, t
 		return err
 	}
 	policyRule := policyAuthorizerRule{
-		access: al,
+		access:  al,
+		segment: segment,
+		prefix:  prefix,
 	}
 
 	if ent != nil {
@@ -208,17 +215,7 @@ func (p *policyAuthorizer) loadRules(policy *PolicyRules) error {
 			return err
 		}
 
-		intention := sp.Intentions
-		if intention == "" {
-			switch sp.Policy {
-			case PolicyRead, PolicyWrite:
-				intention = PolicyRead
-			default:
-				intention = PolicyDeny
-			}
-		}
-
-		if err := insertPolicyIntoRadix(sp.Name, intention, &sp.EnterpriseRule, p.intentionRules, false); err != nil {
+		if err := insertPolicyIntoRadix(sp.Name, sp.intentionPolicy(), &sp.EnterpriseRule, p.intentionRules, false); err != nil {
 			return err
 		}
 	}
@@ -229,17 +226,7 @@ func (p *policyAuthorizer) loadRules(policy *PolicyRules) error {
 			return err
 		}
 
-		intention := sp.Intentions
-		if intention == "" {
-			switch sp.Policy {
-			case PolicyRead, PolicyWrite:
-				intention = PolicyRead
-			default:
-				intention = PolicyDeny
-			}
-		}
-
-		if err := insertPolicyIntoRadix(sp.Name, intention, &sp.EnterpriseRule, p.intentionRules, true); err != nil {
+		if err := insertPolicyIntoRadix(sp.Name, sp.intentionPolicy(), &sp.EnterpriseRule, p.intentionRules, true); err != nil {
 			return err
 		}
 	}
@@ -601,59 +588,60 @@ func (p *policyAuthorizer) KeyWritePrefix(prefix string, _ *AuthorizerContext) E
 	//   * There are no rules (exact or prefix match) within/under the given prefix
 	//     that would NOT grant AccessWrite.
 
-	baseAccess := Default
+	rule := p.keyWritePrefixRule(prefix)
+	if rule == nil {
+		return Default
+	}
+	if rule.access != AccessWrite {
+		return Deny
+	}
+	return Allow
+}
 
+// keyWritePrefixRule returns the rule deciding whether a whole prefix can be
+// written: the longest prefix rule that applies to the prefix if it does not
+// grant AccessWrite, otherwise the first rule within/under the prefix that
+// does not grant AccessWrite, otherwise the longest prefix rule, if any.
+func (p *policyAuthorizer) keyWritePrefixRule(prefix string) *policyAuthorizerRule {
 	// Look for a prefix rule that would apply to the prefix we are checking
 	// WalkPath starts at the root and walks down to the given prefix.
 	// Therefore the last prefix rule we see is the one that matters
+	var base *policyAuthorizerRule
 	p.keyRules.WalkPath(prefix, func(path string, leaf interface{}) bool {
 		rule := leaf.(*policyAuthorizerRadixLeaf)
 
 		if rule.prefix != nil {
-			if rule.prefix.access != AccessWrite {
-				baseAccess = Deny
-			} else {
-				baseAccess = Allow
-			}
+			base = rule.prefix
 		}
 		return false
 	})
 
-	// baseAccess will be Deny only when a prefix rule was found and it didn't
-	// grant AccessWrite. Otherwise the access level will be Default or Allow
-	// neither of which should be returned right now.
-	if baseAccess == Deny {
-		return baseAccess
+	if base != nil && base.access != AccessWrite {
+		return base
 	}
 
 	// Look if any of our children do not allow write access. This loop takes
 	// into account both prefix and exact match rules.
-	withinPrefixAccess := Default
+	var denied *policyAuthorizerRule
 	p.keyRules.WalkPrefix(prefix, func(path string, leaf interface{}) bool {
 		rule := leaf.(*policyAuthorizerRadixLeaf)
 
 		if rule.prefix != nil && rule.prefix.access != AccessWrite {
-			withinPrefixAccess = Deny
+			denied = rule.prefix
 			return true
 		}
 		if rule.exact != nil && rule.exact.access != AccessWrite {
-			withinPrefixAccess = Deny
+			denied = rule.exact
 			return true
 		}
 
 		return false
 	})
 
-	// Deny the write if any sub-rules may be violated. If none are violated then
-	// we can defer to the baseAccess.
-	if withinPrefixAccess == Deny {
-		return Deny
+	if denied != nil {
+		return denied
 	}
-
-	// either Default or Allow at this point. Allow if there was a prefix rule
-	// that was applicable and it granted write access. Default if there was
-	// no applicable rule.
-	return baseAccess
+	return base
 }
 
 // KeyringRead is used to determine if the keyring can be
@@ -971,6 +971,84 @@ func (s *HTTPHandlers) ACLLogin(resp http.ResponseWriter, req *http.Request) (in
 	return &out, nil
 }
 
+func (s *HTTPHandlers) ACLLoginChallenge(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if s.checkACLDisabled() {
+		return nil, aclDisabled
+	}
+
+	args := &structs.ACLLoginChallengeRequest{
+		Datacenter: s.agent.config.Datacenter,
+		Auth:       &structs.ACLLoginChallengeParams{},
+	}
+	s.parseDC(req, &args.Datacenter)
+	if err := s.parseEntMeta(req, &args.Auth.EnterpriseMeta); err != nil {
+		return nil, err
+	}
+
+	if err := s.rewordUnknownEnterpriseFieldError(lib.DecodeJSON(req.Body, &args.Auth)); err != nil {
+		return nil, BadRequestError{Reason: fmt.Sprintf("Failed to decode request body: %v", err)}
+	}
+
+	var out structs.ACLLoginChallengeResponse
+	if err := s.agent.RPC("ACL.LoginChallenge", args, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+func (s *HTTPHandlers) ACLOIDCAuthURL(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if s.checkACLDisabled() {
+		return nil, aclDisabled
+	}
+
+	args := &structs.ACLOIDCAuthURLRequest{
+		Datacenter: s.agent.config.Datacenter,
+		Auth:       &structs.ACLOIDCAuthURLParams{},
+	}
+	s.parseDC(req, &args.Datacenter)
+	if err := s.parseEntMeta(req, &args.Auth.EnterpriseMeta); err != nil {
+		return nil, err
+	}
+
+	if err := s.rewordUnknownEnterpriseFieldError(lib.DecodeJSON(req.Body, &args.Auth)); err != nil {
+		return nil, BadRequestError{Reason: fmt.Sprintf("Failed to decode request body: %v", err)}
+	}
+
+	var out structs.ACLOIDCAuthURLResponse
+	if err := s.agent.RPC("ACL.OIDCAuthURL", args, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+func (s *HTTPHandlers) ACLOIDCCallback(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if s.checkACLDisabled() {
+		return nil, aclDisabled
+	}
+
+	args := &structs.ACLOIDCCallbackRequest{
+		Datacenter: s.agent.config.Datacenter,
+		Auth:       &structs.ACLOIDCCallbackParams{},
+	}
+	s.parseDC(req, &args.Datacenter)
+	if err := s.parseEntMeta(req, &args.Auth.EnterpriseMeta); err != nil {
+		return nil, err
+	}
+
+	if err := s.rewordUnknownEnterpriseFieldError(lib.DecodeJSON(req.Body, &args.Auth)); err != nil {
+		return nil, BadRequestError{Reason: fmt.Sprintf("Failed to decode request body: %v", err)}
+	}
+
+	var out structs.ACLToken
+	if err := s.agent.RPC("ACL.OIDCCallback", args, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
 func (s *HTTPHandlers) ACLLogout(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	if s.checkACLDisabled() {
 		return nil, aclDisabled
@@ -1084,3 +1162,52 @@ func (s *HTTPHandlers) ACLAuthorize(resp http.ResponseWriter, req *http.Request)
 
 	return responses, nil
 }
+
+// ACLExplain performs authorizations like ACLAuthorize does but also reports
+// the rule, policies, roles and identities that rendered each decision. The
+// token of the request is explained unless the accessor query parameter
+// names another token.
+func (s *HTTPHandlers) ACLExplain(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	const maxRequests = 64
+
+	if s.checkACLDisabled() {
+		return nil, aclDisabled
+	}
+
+	args := structs.ACLExplainRequest{
+		Datacenter: s.agent.config.Datacenter,
+		AccessorID: req.URL.Query().Get("accessor"),
+		QueryOptions: structs.QueryOptions{
+			AllowStale:        true,
+			RequireConsistent: false,
+		},
+	}
+
+	s.parseToken(req, &args.Token)
+	s.parseDC(req, &args.Datacenter)
+	if err := s.parseEntMeta(req, &args.EnterpriseMeta); err != nil {
+		return nil, err
+	}
+
+	if err := decodeBody(req.Body, &args.Requests); err != nil {
+		return nil, BadRequestError{Reason: fmt.Sprintf("Failed to decode request body: %v", err)}
+	}
+
+	if len(args.Requests) > maxRequests {
+		return nil, BadRequestError{Reason: fmt.Sprintf("Refusing to process more than %d authorizations at once", maxRequests)}
+	}
+
+	if len(args.Requests) == 0 {
+		return make([]structs.ACLAuthorizationExplanation, 0), nil
+	}
+
+	var out []structs.ACLAuthorizationExplanation
+	if err := s.agent.RPC("ACL.Explain", &args, &out); err != nil {
+		return nil, err
+	}
+
+	if out == nil {
+		out = make([]structs.ACLAuthorizationExplanation, 0)
+	}
+	return out, nil
+}
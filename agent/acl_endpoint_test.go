@@ -2,11 +2,14 @@ package agent
 
 import (
 	"bytes"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 	"time"
 
@@ -15,7 +18,9 @@ import (
 	"gopkg.in/square/go-jose.v2/jwt"
 
 	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/agent/consul/authmethod/ldapauth"
 	"github.com/hashicorp/consul/agent/consul/authmethod/testauth"
+	"github.com/hashicorp/consul/agent/consul/authmethod/x509auth"
 	"github.com/hashicorp/consul/agent/structs"
 	"github.com/hashicorp/consul/internal/go-sso/oidcauth/oidcauthtest"
 	"github.com/hashicorp/consul/sdk/testutil"
@@ -63,6 +68,7 @@ func TestACL_Disabled_Response(t *testing.T) {
 		{"ACLLogin", a.srv.ACLLogin},
 		{"ACLLogout", a.srv.ACLLogout},
 		{"ACLAuthorize", a.srv.ACLAuthorize},
+		{"ACLExplain", a.srv.ACLExplain},
 	}
 	testrpc.WaitForLeader(t, a.RPC, "dc1")
 	for _, tt := range tests {
@@ -1810,6 +1816,330 @@ func TestACLEndpoint_LoginLogout_jwt(t *testing.T) {
 	}
 }
 
+func TestACLEndpoint_LoginLogout_ldap(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := NewTestAgent(t, TestACLConfigWithParams(nil))
+	defer a.Shutdown()
+
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	// spin up a fake ldap server
+	ldapServer := ldapauth.StartTestLDAPServer(t)
+	defer ldapServer.Stop()
+	ldapServer.AddEntry("uid=alice,ou=users,dc=example,dc=com", "password", map[string][]string{
+		"uid": {"alice"},
+	})
+	ldapServer.AddEntry("cn=admins,ou=groups,dc=example,dc=com", "", map[string][]string{
+		"cn":     {"admins"},
+		"member": {"uid=alice,ou=users,dc=example,dc=com"},
+	})
+
+	method, err := upsertTestCustomizedAuthMethod(a.RPC, TestDefaultInitialManagementToken, "dc1", func(method *structs.ACLAuthMethod) {
+		method.Type = "ldap"
+		method.Config = map[string]interface{}{
+			"URL":     ldapServer.URL(),
+			"UserDN":  "ou=users,dc=example,dc=com",
+			"GroupDN": "ou=groups,dc=example,dc=com",
+		}
+	})
+	require.NoError(t, err)
+
+	_, err = upsertTestCustomizedBindingRule(a.RPC, TestDefaultInitialManagementToken, "dc1", func(rule *structs.ACLBindingRule) {
+		rule.AuthMethod = method.Name
+		rule.BindType = structs.BindingRuleBindTypeService
+		rule.BindName = "test--${username}"
+		rule.Selector = "admins in groups"
+	})
+	require.NoError(t, err)
+
+	t.Run("invalid credentials", func(t *testing.T) {
+		loginInput := &structs.ACLLoginParams{
+			AuthMethod:  method.Name,
+			BearerToken: "alice:wrong",
+		}
+
+		req, _ := http.NewRequest("POST", "/v1/acl/login", jsonBody(loginInput))
+		resp := httptest.NewRecorder()
+		_, err := a.srv.ACLLogin(resp, req)
+		testutil.RequireErrorContains(t, err, "invalid username or password")
+	})
+
+	t.Run("valid credentials", func(t *testing.T) {
+		loginInput := &structs.ACLLoginParams{
+			AuthMethod:  method.Name,
+			BearerToken: "alice:password",
+		}
+
+		req, _ := http.NewRequest("POST", "/v1/acl/login", jsonBody(loginInput))
+		resp := httptest.NewRecorder()
+		obj, err := a.srv.ACLLogin(resp, req)
+		require.NoError(t, err)
+
+		token, ok := obj.(*structs.ACLToken)
+		require.True(t, ok)
+		require.Equal(t, method.Name, token.AuthMethod)
+		require.Len(t, token.ServiceIdentities, 1)
+		require.Equal(t, "test--alice", token.ServiceIdentities[0].ServiceName)
+	})
+}
+
+func TestACLEndpoint_LoginLogout_x509(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := NewTestAgent(t, TestACLConfigWithParams(nil))
+	defer a.Shutdown()
+
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	ca := x509auth.NewTestCA(t)
+	spiffeID, err := url.Parse("spiffe://example.org/ns/default/svc/web")
+	require.NoError(t, err)
+	leaf := ca.Issue(t, &x509.Certificate{
+		Subject: pkix.Name{CommonName: "web"},
+		URIs:    []*url.URL{spiffeID},
+	})
+
+	method, err := upsertTestCustomizedAuthMethod(a.RPC, TestDefaultInitialManagementToken, "dc1", func(method *structs.ACLAuthMethod) {
+		method.Type = "x509"
+		method.Config = map[string]interface{}{
+			"CACerts": []string{ca.CertPEM},
+		}
+	})
+	require.NoError(t, err)
+
+	_, err = upsertTestCustomizedBindingRule(a.RPC, TestDefaultInitialManagementToken, "dc1", func(rule *structs.ACLBindingRule) {
+		rule.AuthMethod = method.Name
+		rule.BindType = structs.BindingRuleBindTypeService
+		rule.BindName = "${spiffe.segment.3}"
+		rule.Selector = "spiffe.trust_domain == example.org"
+	})
+	require.NoError(t, err)
+
+	challenge := func(t *testing.T) string {
+		challengeInput := &structs.ACLLoginChallengeParams{
+			AuthMethod: method.Name,
+		}
+
+		req, _ := http.NewRequest("POST", "/v1/acl/login/challenge", jsonBody(challengeInput))
+		resp := httptest.NewRecorder()
+		obj, err := a.srv.ACLLoginChallenge(resp, req)
+		require.NoError(t, err)
+
+		out, ok := obj.(*structs.ACLLoginChallengeResponse)
+		require.True(t, ok)
+		require.NotEmpty(t, out.Challenge)
+		return out.Challenge
+	}
+
+	t.Run("no login token", func(t *testing.T) {
+		loginInput := &structs.ACLLoginParams{
+			AuthMethod: method.Name,
+		}
+
+		req, _ := http.NewRequest("POST", "/v1/acl/login", jsonBody(loginInput))
+		resp := httptest.NewRecorder()
+		_, err := a.srv.ACLLogin(resp, req)
+		testutil.RequireErrorContains(t, err, "failed to decode login token")
+	})
+
+	t.Run("certificate without a challenge", func(t *testing.T) {
+		// Anyone may know the certificate, it must come with the proof that
+		// the client holds its private key.
+		token := &x509auth.LoginToken{
+			Certificates: [][]byte{leaf.DER},
+		}
+		bearerToken, err := token.Encode()
+		require.NoError(t, err)
+
+		loginInput := &structs.ACLLoginParams{
+			AuthMethod:  method.Name,
+			BearerToken: bearerToken,
+		}
+
+		req, _ := http.NewRequest("POST", "/v1/acl/login", jsonBody(loginInput))
+		resp := httptest.NewRecorder()
+		_, err = a.srv.ACLLogin(resp, req)
+		testutil.RequireErrorContains(t, err, "invalid or expired login challenge")
+	})
+
+	t.Run("valid client certificate", func(t *testing.T) {
+		loginInput := &structs.ACLLoginParams{
+			AuthMethod:  method.Name,
+			BearerToken: leaf.LoginToken(t, challenge(t)),
+		}
+
+		req, _ := http.NewRequest("POST", "/v1/acl/login", jsonBody(loginInput))
+		resp := httptest.NewRecorder()
+		obj, err := a.srv.ACLLogin(resp, req)
+		require.NoError(t, err)
+
+		token, ok := obj.(*structs.ACLToken)
+		require.True(t, ok)
+		require.Equal(t, method.Name, token.AuthMethod)
+		require.Len(t, token.ServiceIdentities, 1)
+		require.Equal(t, "web", token.ServiceIdentities[0].ServiceName)
+	})
+
+	t.Run("auth method without challenges", func(t *testing.T) {
+		testSessionID := testauth.StartSession()
+		defer testauth.ResetSession(testSessionID)
+
+		other, err := upsertTestCustomizedAuthMethod(a.RPC, TestDefaultInitialManagementToken, "dc1", func(method *structs.ACLAuthMethod) {
+			method.Config = map[string]interface{}{
+				"SessionID": testSessionID,
+			}
+		})
+		require.NoError(t, err)
+
+		challengeInput := &structs.ACLLoginChallengeParams{
+			AuthMethod: other.Name,
+		}
+
+		req, _ := http.NewRequest("POST", "/v1/acl/login/challenge", jsonBody(challengeInput))
+		resp := httptest.NewRecorder()
+		_, err = a.srv.ACLLoginChallenge(resp, req)
+		testutil.RequireErrorContains(t, err, "does not use login challenges")
+	})
+}
+
+func TestACLEndpoint_OIDCLogin(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := NewTestAgent(t, TestACLConfigWithParams(nil))
+	defer a.Shutdown()
+
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	// spin up a fake oidc server
+	const redirectURI = "http://localhost:8550/oidc/callback"
+	oidcServer := oidcauthtest.Start(t)
+	oidcServer.SetClientCreds("consul", "secret")
+	oidcServer.SetExpectedAuthCode("code")
+	oidcServer.SetAllowedRedirectURIs([]string{redirectURI})
+
+	method, err := upsertTestCustomizedAuthMethod(a.RPC, TestDefaultInitialManagementToken, "dc1", func(method *structs.ACLAuthMethod) {
+		method.Type = "oidc"
+		method.Config = map[string]interface{}{
+			"OIDCDiscoveryURL":    oidcServer.Addr(),
+			"OIDCDiscoveryCACert": oidcServer.CACert(),
+			"OIDCClientID":        "consul",
+			"OIDCClientSecret":    "secret",
+			"AllowedRedirectURIs": []string{redirectURI},
+			"BoundAudiences":      []string{"consul"},
+			"JWTSupportedAlgs":    []string{"ES256"},
+			"ClaimMappings": map[string]string{
+				"color": "color",
+			},
+		}
+	})
+	require.NoError(t, err)
+
+	_, err = upsertTestCustomizedBindingRule(a.RPC, TestDefaultInitialManagementToken, "dc1", func(rule *structs.ACLBindingRule) {
+		rule.AuthMethod = method.Name
+		rule.BindType = structs.BindingRuleBindTypeService
+		rule.BindName = "test--${value.color}"
+	})
+	require.NoError(t, err)
+
+	// authURL starts a login and returns the state and nonce of the provider
+	// from the auth URL, as a browser would send them to the provider.
+	authURL := func(t *testing.T, clientNonce string) (state string) {
+		t.Helper()
+		authInput := &structs.ACLOIDCAuthURLParams{
+			AuthMethod:  method.Name,
+			RedirectURI: redirectURI,
+			ClientNonce: clientNonce,
+			Meta:        map[string]string{"host": "laptop"},
+		}
+
+		req, _ := http.NewRequest("POST", "/v1/acl/oidc/auth-url", jsonBody(authInput))
+		resp := httptest.NewRecorder()
+		obj, err := a.srv.ACLOIDCAuthURL(resp, req)
+		require.NoError(t, err)
+
+		out, ok := obj.(*structs.ACLOIDCAuthURLResponse)
+		require.True(t, ok)
+		u, err := url.Parse(out.AuthURL)
+		require.NoError(t, err)
+		require.Equal(t, oidcServer.Addr()+"/auth", u.Scheme+"://"+u.Host+u.Path)
+		require.Equal(t, redirectURI, u.Query().Get("redirect_uri"))
+
+		oidcServer.SetCustomClaims(map[string]interface{}{
+			"nonce": u.Query().Get("nonce"),
+		})
+		return u.Query().Get("state")
+	}
+
+	callback := func(state, clientNonce string) (interface{}, error) {
+		callbackInput := &structs.ACLOIDCCallbackParams{
+			AuthMethod:  method.Name,
+			State:       state,
+			Code:        "code",
+			ClientNonce: clientNonce,
+		}
+
+		req, _ := http.NewRequest("POST", "/v1/acl/oidc/callback", jsonBody(callbackInput))
+		resp := httptest.NewRecorder()
+		return a.srv.ACLOIDCCallback(resp, req)
+	}
+
+	t.Run("unknown redirect URI", func(t *testing.T) {
+		authInput := &structs.ACLOIDCAuthURLParams{
+			AuthMethod:  method.Name,
+			RedirectURI: "https://example.com/oidc/callback",
+			ClientNonce: "nonce",
+		}
+
+		req, _ := http.NewRequest("POST", "/v1/acl/oidc/auth-url", jsonBody(authInput))
+		resp := httptest.NewRecorder()
+		_, err := a.srv.ACLOIDCAuthURL(resp, req)
+		testutil.RequireErrorContains(t, err, "unauthorized redirect_uri")
+	})
+
+	t.Run("wrong client nonce", func(t *testing.T) {
+		state := authURL(t, "nonce-1")
+		_, err := callback(state, "nonce-2")
+		testutil.RequireErrorContains(t, err, "Permission denied")
+	})
+
+	t.Run("unknown state", func(t *testing.T) {
+		_, err := callback("unknown", "nonce-1")
+		testutil.RequireErrorContains(t, err, "Expired or missing OAuth state")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		state := authURL(t, "nonce-1")
+		obj, err := callback(state, "nonce-1")
+		require.NoError(t, err)
+
+		token, ok := obj.(*structs.ACLToken)
+		require.True(t, ok)
+
+		require.Equal(t, method.Name, token.AuthMethod)
+		require.Equal(t, `token created via OIDC login: {"host":"laptop"}`, token.Description)
+		require.True(t, token.Local)
+		require.Len(t, token.ServiceIdentities, 1)
+		require.Equal(t, "test--red", token.ServiceIdentities[0].ServiceName)
+
+		// the state can only be used once
+		_, err = callback(state, "nonce-1")
+		testutil.RequireErrorContains(t, err, "Expired or missing OAuth state")
+	})
+}
+
 func TestACL_Authorize(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -0,0 +1,218 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/armon/go-metrics/prometheus"
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/hashicorp/consul/agent/config"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/agent/token"
+	"github.com/hashicorp/consul/lib/retry"
+)
+
+var ACLLoginCounters = []prometheus.CounterDefinition{
+	{
+		Name: metricsKeyACLLoginSuccess,
+		Help: "Increments when the agent logs in with the auth method of acl.login.",
+	},
+	{
+		Name: metricsKeyACLLoginFailure,
+		Help: "Increments when the agent fails to log in with the auth method of acl.login.",
+	},
+}
+
+var ACLLoginGauges = []prometheus.GaugeDefinition{
+	{
+		Name: metricsKeyACLLoginTokenExpiry,
+		Help: "Seconds until the token obtained with the auth method of acl.login expires. Updated on each login.",
+	},
+}
+
+var (
+	metricsKeyACLLoginSuccess     = []string{"agent", "acl", "login", "success"}
+	metricsKeyACLLoginFailure     = []string{"agent", "acl", "login", "failure"}
+	metricsKeyACLLoginTokenExpiry = []string{"agent", "acl", "login", "token", "expiry"}
+)
+
+// aclLoginRefreshFraction is the fraction of the lifetime of the token after
+// which the agent logs in again to replace it.
+const aclLoginRefreshFraction = 0.75
+
+// aclLogin logs the agent in with the auth method configured in acl.login and
+// keeps the tokens of the agent set to the token obtained, logging in again
+// before it expires.
+type aclLogin struct {
+	config     config.ACLLogin
+	datacenter string
+	tokens     *token.Store
+	logger     hclog.Logger
+
+	// rpc is used to call the ACL.Login and ACL.Logout endpoints.
+	rpc func(method string, args interface{}, reply interface{}) error
+
+	// waiter backs off between failed logins.
+	waiter *retry.Waiter
+
+	// lock protects current.
+	lock sync.Mutex
+
+	// current is the token obtained by the last login.
+	current *structs.ACLToken
+}
+
+func newACLLogin(a *Agent) *aclLogin {
+	return &aclLogin{
+		config:     a.config.ACLLogin,
+		datacenter: a.config.Datacenter,
+		tokens:     a.tokens,
+		logger:     a.logger.Named("acl_login"),
+		rpc:        a.RPC,
+		waiter: &retry.Waiter{
+			MinFailures: 1,
+			MinWait:     time.Second,
+			MaxWait:     5 * time.Minute,
+			Jitter:      retry.NewJitter(20),
+		},
+	}
+}
+
+// run logs in until the context is cancelled.
+func (l *aclLogin) run(ctx context.Context) {
+	for {
+		tok, err := l.login()
+		if err != nil {
+			metrics.IncrCounter(metricsKeyACLLoginFailure, 1)
+			l.logger.Error("failed to log in",
+				"auth_method", l.config.AuthMethod,
+				"error", err,
+			)
+			if err := l.waiter.Wait(ctx); err != nil {
+				return
+			}
+			continue
+		}
+		l.waiter.Reset()
+		metrics.IncrCounter(metricsKeyACLLoginSuccess, 1)
+
+		if tok.ExpirationTime == nil {
+			// The token does not expire so it never has to be replaced.
+			l.logger.Info("logged in, the token does not expire", "accessor_id", tok.AccessorID)
+			<-ctx.Done()
+			return
+		}
+
+		metrics.SetGauge(metricsKeyACLLoginTokenExpiry, float32(time.Until(*tok.ExpirationTime).Seconds()))
+		wait := aclLoginRefreshWait(tok)
+		l.logger.Info("logged in",
+			"accessor_id", tok.AccessorID,
+			"expiration_time", tok.ExpirationTime,
+			"refresh_in", wait,
+		)
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// aclLoginRefreshWait returns how long to wait before replacing the token.
+// The lifetime of the token is computed from the times set by the servers so
+// that a clock of the agent lagging behind the servers does not delay the
+// refresh past the expiration.
+func aclLoginRefreshWait(tok *structs.ACLToken) time.Duration {
+	remaining := time.Until(*tok.ExpirationTime)
+	wait := time.Duration(float64(remaining) * aclLoginRefreshFraction)
+
+	if !tok.CreateTime.IsZero() {
+		lifetime := tok.ExpirationTime.Sub(tok.CreateTime)
+		if byLifetime := time.Duration(float64(lifetime) * aclLoginRefreshFraction); remaining <= 0 || byLifetime < wait {
+			wait = byLifetime
+		}
+	}
+	if wait < 0 {
+		return 0
+	}
+	return wait
+}
+
+// login obtains a new token from the auth method, sets it as the token of
+// the agent and logs out the token it replaces.
+func (l *aclLogin) login() (*structs.ACLToken, error) {
+	raw, err := ioutil.ReadFile(l.config.BearerTokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bearer token file: %w", err)
+	}
+	bearerToken := strings.TrimSpace(string(raw))
+	if bearerToken == "" {
+		return nil, fmt.Errorf("bearer token file %q is empty", l.config.BearerTokenFile)
+	}
+
+	req := structs.ACLLoginRequest{
+		Auth: &structs.ACLLoginParams{
+			AuthMethod:  l.config.AuthMethod,
+			BearerToken: bearerToken,
+			Meta:        l.config.Meta,
+		},
+		Datacenter: l.datacenter,
+	}
+	var tok structs.ACLToken
+	if err := l.rpc("ACL.Login", &req, &tok); err != nil {
+		return nil, err
+	}
+
+	l.lock.Lock()
+	previous := l.current
+	l.current = &tok
+	l.applyLocked()
+	l.lock.Unlock()
+
+	if previous != nil {
+		l.logout(previous)
+	}
+	return &tok, nil
+}
+
+// apply sets the tokens of the agent to the token obtained by the last login,
+// if any. It is used to restore them after the tokens are reloaded from the
+// configuration.
+func (l *aclLogin) apply() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.applyLocked()
+}
+
+func (l *aclLogin) applyLocked() {
+	if l.current == nil {
+		return
+	}
+	l.tokens.UpdateAgentToken(l.current.SecretID, token.TokenSourceConfig)
+	if l.config.SetDefaultToken {
+		l.tokens.UpdateUserToken(l.current.SecretID, token.TokenSourceConfig)
+	}
+}
+
+// logout deletes a token that was replaced. Failures are only logged since
+// the token expires anyway.
+func (l *aclLogin) logout(tok *structs.ACLToken) {
+	req := structs.ACLLogoutRequest{
+		Datacenter:   l.datacenter,
+		WriteRequest: structs.WriteRequest{Token: tok.SecretID},
+	}
+	var ignored bool
+	if err := l.rpc("ACL.Logout", &req, &ignored); err != nil {
+		l.logger.Warn("failed to log out the replaced token",
+			"accessor_id", tok.AccessorID,
+			"error", err,
+		)
+	}
+}
@@ -0,0 +1,223 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/config"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/agent/token"
+	"github.com/hashicorp/consul/lib/retry"
+	"github.com/hashicorp/consul/sdk/testutil"
+	testretry "github.com/hashicorp/consul/sdk/testutil/retry"
+)
+
+// fakeACLLoginRPC issues tokens expiring after ttl for the ACL.Login calls,
+// and records the ACL.Logout calls.
+type fakeACLLoginRPC struct {
+	lock       sync.Mutex
+	ttl        time.Duration
+	fail       int
+	logins     []structs.ACLLoginRequest
+	logouts    []string
+	issued     int
+	lastBearer string
+}
+
+func (f *fakeACLLoginRPC) RPC(method string, args interface{}, reply interface{}) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	switch method {
+	case "ACL.Login":
+		req := args.(*structs.ACLLoginRequest)
+		f.logins = append(f.logins, *req)
+		if f.fail > 0 {
+			f.fail--
+			return errors.New("auth method unavailable")
+		}
+		f.issued++
+		f.lastBearer = req.Auth.BearerToken
+
+		now := time.Now()
+		tok := reply.(*structs.ACLToken)
+		tok.AccessorID = fmt.Sprintf("accessor-%d", f.issued)
+		tok.SecretID = fmt.Sprintf("secret-%d", f.issued)
+		tok.CreateTime = now
+		if f.ttl > 0 {
+			expiration := now.Add(f.ttl)
+			tok.ExpirationTime = &expiration
+		}
+		return nil
+	case "ACL.Logout":
+		req := args.(*structs.ACLLogoutRequest)
+		f.logouts = append(f.logouts, req.Token)
+		return nil
+	}
+	return fmt.Errorf("unexpected method %q", method)
+}
+
+func (f *fakeACLLoginRPC) state() (logins int, logouts []string, issued int) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return len(f.logins), append([]string(nil), f.logouts...), f.issued
+}
+
+func newTestACLLogin(t *testing.T, rpc *fakeACLLoginRPC, cfg config.ACLLogin) (*aclLogin, *token.Store) {
+	tokens := new(token.Store)
+	return &aclLogin{
+		config:     cfg,
+		datacenter: "dc1",
+		tokens:     tokens,
+		logger:     hclog.NewNullLogger(),
+		rpc:        rpc.RPC,
+		waiter: &retry.Waiter{
+			MinFailures: 1,
+			MinWait:     10 * time.Millisecond,
+			MaxWait:     50 * time.Millisecond,
+		},
+	}, tokens
+}
+
+func writeBearerToken(t *testing.T, dir, bearerToken string) string {
+	path := filepath.Join(dir, "bearer-token")
+	require.NoError(t, ioutil.WriteFile(path, []byte(bearerToken+"\n"), 0600))
+	return path
+}
+
+func TestACLLogin_Login(t *testing.T) {
+	dir := testutil.TempDir(t, "acl-login")
+	rpc := &fakeACLLoginRPC{ttl: time.Hour}
+	l, tokens := newTestACLLogin(t, rpc, config.ACLLogin{
+		AuthMethod:      "jwt",
+		BearerTokenFile: writeBearerToken(t, dir, "jwt-1"),
+		Meta:            map[string]string{"node": "node1"},
+	})
+	tokens.UpdateUserToken("default", token.TokenSourceConfig)
+
+	tok, err := l.login()
+	require.NoError(t, err)
+	require.Equal(t, "secret-1", tok.SecretID)
+	require.Equal(t, "secret-1", tokens.AgentToken())
+	require.Equal(t, "default", tokens.UserToken())
+
+	require.Len(t, rpc.logins, 1)
+	require.Equal(t, "dc1", rpc.logins[0].Datacenter)
+	require.Equal(t, "jwt", rpc.logins[0].Auth.AuthMethod)
+	require.Equal(t, "jwt-1", rpc.logins[0].Auth.BearerToken)
+	require.Equal(t, map[string]string{"node": "node1"}, rpc.logins[0].Auth.Meta)
+	require.Empty(t, rpc.logouts)
+
+	// The bearer token is read again and the replaced token logged out.
+	writeBearerToken(t, dir, "jwt-2")
+	_, err = l.login()
+	require.NoError(t, err)
+	require.Equal(t, "secret-2", tokens.AgentToken())
+	require.Equal(t, "jwt-2", rpc.lastBearer)
+	require.Equal(t, []string{"secret-1"}, rpc.logouts)
+
+	// Reloading the tokens from the configuration keeps the login token.
+	tokens.UpdateAgentToken("from-config", token.TokenSourceConfig)
+	l.apply()
+	require.Equal(t, "secret-2", tokens.AgentToken())
+}
+
+func TestACLLogin_SetDefaultToken(t *testing.T) {
+	dir := testutil.TempDir(t, "acl-login")
+	rpc := &fakeACLLoginRPC{}
+	l, tokens := newTestACLLogin(t, rpc, config.ACLLogin{
+		AuthMethod:      "jwt",
+		BearerTokenFile: writeBearerToken(t, dir, "jwt"),
+		SetDefaultToken: true,
+	})
+
+	_, err := l.login()
+	require.NoError(t, err)
+	require.Equal(t, "secret-1", tokens.AgentToken())
+	require.Equal(t, "secret-1", tokens.UserToken())
+}
+
+func TestACLLogin_Errors(t *testing.T) {
+	dir := testutil.TempDir(t, "acl-login")
+
+	rpc := &fakeACLLoginRPC{}
+	l, tokens := newTestACLLogin(t, rpc, config.ACLLogin{
+		AuthMethod:      "jwt",
+		BearerTokenFile: filepath.Join(dir, "missing"),
+	})
+	_, err := l.login()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to read bearer token file")
+	require.Empty(t, rpc.logins)
+
+	l.config.BearerTokenFile = writeBearerToken(t, dir, "  ")
+	_, err = l.login()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is empty")
+
+	rpc.fail = 1
+	l.config.BearerTokenFile = writeBearerToken(t, dir, "jwt")
+	_, err = l.login()
+	require.Error(t, err)
+	require.Equal(t, "", tokens.AgentToken())
+}
+
+func TestACLLogin_Run(t *testing.T) {
+	dir := testutil.TempDir(t, "acl-login")
+	rpc := &fakeACLLoginRPC{ttl: 200 * time.Millisecond, fail: 2}
+	l, tokens := newTestACLLogin(t, rpc, config.ACLLogin{
+		AuthMethod:      "jwt",
+		BearerTokenFile: writeBearerToken(t, dir, "jwt"),
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go l.run(ctx)
+
+	// The failed logins are retried, then the token is refreshed before it
+	// expires and the replaced tokens are logged out.
+	testretry.Run(t, func(r *testretry.R) {
+		logins, logouts, issued := rpc.state()
+		require.GreaterOrEqual(r, issued, 3)
+		require.Equal(r, issued+2, logins)
+		require.Equal(r, []string{"secret-1", "secret-2"}, logouts[:2])
+		require.Equal(r, fmt.Sprintf("secret-%d", issued), tokens.AgentToken())
+	})
+}
+
+func TestACLLoginRefreshWait(t *testing.T) {
+	now := time.Now()
+	expiration := now.Add(time.Hour)
+
+	wait := aclLoginRefreshWait(&structs.ACLToken{
+		CreateTime:     now,
+		ExpirationTime: &expiration,
+	})
+	require.InDelta(t, float64(45*time.Minute), float64(wait), float64(time.Second))
+
+	// A token created a while ago is refreshed before it expires.
+	wait = aclLoginRefreshWait(&structs.ACLToken{
+		CreateTime:     now.Add(-time.Hour),
+		ExpirationTime: &expiration,
+	})
+	require.InDelta(t, float64(45*time.Minute), float64(wait), float64(time.Second))
+
+	// The clock of the agent is ahead of the servers.
+	wait = aclLoginRefreshWait(&structs.ACLToken{
+		CreateTime:     now.Add(-2 * time.Hour),
+		ExpirationTime: &[]time.Time{now.Add(-time.Hour)}[0],
+	})
+	require.Equal(t, 45*time.Minute, wait)
+
+	wait = aclLoginRefreshWait(&structs.ACLToken{ExpirationTime: &expiration})
+	require.InDelta(t, float64(45*time.Minute), float64(wait), float64(time.Second))
+}
@@ -328,12 +328,20 @@ type Agent struct {
 	// the configuration directly.
 	tokens *token.Store
 
+	// aclLogin keeps the agent token set to a token obtained from the auth
+	// method of acl.login. It is nil when acl.login is not configured.
+	aclLogin *aclLogin
+
 	// proxyConfig is the manager for proxy service (Kind = connect-proxy)
 	// configuration state. This ensures all state needed by a proxy registration
 	// is maintained in cache and handles pushing updates to that state into XDS
 	// server to be pushed out to Envoy.
 	proxyConfig *proxycfg.Manager
 
+	// connectAuditDenials summarizes the connections that intentions would
+	// have denied in audit mode.
+	connectAuditDenials *connectAuditDenials
+
 	// serviceManager is the manager for combining local service registrations with
 	// the centrally configured proxy/service defaults.
 	serviceManager *ServiceManager
@@ -407,6 +415,8 @@ func New(bd BaseDeps) (*Agent, error) {
 		config:          bd.RuntimeConfig,
 		cache:           bd.Cache,
 		routineManager:  routine.NewManager(bd.Logger),
+
+		connectAuditDenials: newConnectAuditDenials(),
 	}
 
 	// TODO: create rpcClientHealth in BaseDeps once NetRPC is available without Agent
@@ -605,6 +615,7 @@ func (a *Agent) Start(ctx context.Context) error {
 		},
 		TLSConfigurator:       a.tlsConfigurator,
 		IntentionDefaultAllow: intentionDefaultAllow,
+		NodeMeta:              a.config.NodeMeta,
 	})
 	if err != nil {
 		return err
@@ -615,6 +626,12 @@ func (a *Agent) Start(ctx context.Context) error {
 		}
 	}()
 
+	// Log in with the auth method of acl.login to obtain the agent token.
+	if a.config.ACLLogin.AuthMethod != "" {
+		a.aclLogin = newACLLogin(a)
+		go a.aclLogin.run(&lib.StopChannelContext{StopCh: a.shutdownCh})
+	}
+
 	// Start watching for critical services to deregister, based on their
 	// checks.
 	go a.reapServices()
@@ -1261,6 +1278,8 @@ func newConsulConfig(runtimeCfg *config.RuntimeConfig, logger hclog.Logger) (*co
 
 	cfg.ConfigEntryBootstrap = runtimeCfg.ConfigEntryBootstrap
 	cfg.RaftBoltDBConfig = runtimeCfg.RaftBoltDBConfig
+	cfg.RaftLogStoreConfig = runtimeCfg.RaftLogStoreConfig
+	cfg.LeaderLeaseReads = runtimeCfg.LeaderLeaseReads
 
 	// Duplicate our own serf config once to make sure that the duplication
 	// function does not drift.
@@ -1469,6 +1488,11 @@ func (a *Agent) ShutdownEndpoints() {
 		a.logger.Error(err.Error())
 	}
 	a.logger.Info("Endpoints down")
+
+	// Close the audit sinks once no more requests can be served.
+	if err := a.baseDeps.Auditor.Close(); err != nil {
+		a.logger.Warn("failed to close audit sinks", "error", err)
+	}
 }
 
 // RetryJoinCh is a channel that transports errors
@@ -3737,6 +3761,9 @@ func (a *Agent) reloadConfigInternal(newCfg *config.RuntimeConfig) error {
 	// to ensure the correct tokens are available for attaching to
 	// the checks and service registrations.
 	a.tokens.Load(newCfg.ACLTokens, a.logger)
+	if a.aclLogin != nil {
+		a.aclLogin.apply()
+	}
 
 	if err := a.tlsConfigurator.Update(newCfg.ToTLSUtilConfig()); err != nil {
 		return fmt.Errorf("Failed reloading tls configuration: %s", err)
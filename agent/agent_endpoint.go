@@ -22,8 +22,10 @@ import (
 	cachetype "github.com/hashicorp/consul/agent/cache-types"
 	"github.com/hashicorp/consul/agent/consul"
 	"github.com/hashicorp/consul/agent/debug"
+	"github.com/hashicorp/consul/agent/proxycfg"
 	"github.com/hashicorp/consul/agent/structs"
 	token_store "github.com/hashicorp/consul/agent/token"
+	"github.com/hashicorp/consul/agent/xds"
 	"github.com/hashicorp/consul/agent/xds/proxysupport"
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/ipaddr"
@@ -1666,6 +1668,114 @@ type connectAuthorizeResp struct {
 	Reason     string // Reason for the Authorized value (whether true or false)
 }
 
+// AgentConnectAuthorizeAudit
+//
+// GET /v1/agent/connect/authorize/audit
+//
+// Returns the summary of the connections authorized by this agent that
+// intentions would have denied while in audit mode, per source and
+// destination service. Only the destinations the token can read are included.
+func (s *HTTPHandlers) AgentConnectAuthorizeAudit(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var token string
+	s.parseToken(req, &token)
+
+	var entMeta structs.EnterpriseMeta
+	if err := s.parseEntMetaPartition(req, &entMeta); err != nil {
+		return nil, err
+	}
+
+	authz, err := s.agent.delegate.ResolveTokenAndDefaultMeta(token, &entMeta, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.agent.connectAuditDenials.list(func(destination structs.ServiceName) bool {
+		var authzContext acl.AuthorizerContext
+		destination.FillAuthzContext(&authzContext)
+		return authz.ServiceRead(destination.Name, &authzContext) == acl.Allow
+	}), nil
+}
+
+// AgentConnectProxyXDS
+//
+// GET /v1/agent/connect/proxy/:proxy_id/xds
+//
+// Returns the listeners, routes, clusters and endpoints that would be sent to
+// the proxy over xDS, generated from its current config snapshot. This
+// requires service:write on the proxy, the same as opening an xDS stream.
+func (s *HTTPHandlers) AgentConnectProxyXDS(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	path, err := getPathSuffixUnescaped(req.URL.Path, "/v1/agent/connect/proxy/")
+	if err != nil {
+		return nil, err
+	}
+	id := strings.TrimSuffix(path, "/xds")
+	if id == path || id == "" {
+		resp.WriteHeader(http.StatusNotFound)
+		fmt.Fprintf(resp, "Invalid path, expected /v1/agent/connect/proxy/<proxy_id>/xds")
+		return nil, nil
+	}
+
+	var token string
+	s.parseToken(req, &token)
+
+	var entMeta structs.EnterpriseMeta
+	if err := s.parseEntMetaNoWildcard(req, &entMeta); err != nil {
+		return nil, err
+	}
+
+	authz, err := s.agent.delegate.ResolveTokenAndDefaultMeta(token, &entMeta, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.validateRequestPartition(resp, &entMeta) {
+		return nil, nil
+	}
+
+	sid := structs.NewServiceID(id, &entMeta)
+
+	svc := s.agent.State.Service(sid)
+	if svc == nil || svc.Kind == structs.ServiceKindTypical {
+		return nil, NotFoundError{Reason: fmt.Sprintf("unknown proxy service ID: %s", sid.String())}
+	}
+
+	var authzContext acl.AuthorizerContext
+	svc.FillAuthzContext(&authzContext)
+	if authz.ServiceWrite(svc.Service, &authzContext) != acl.Allow {
+		return nil, acl.ErrPermissionDenied
+	}
+
+	snap := s.agent.proxyConfig.CurrentSnapshot(sid)
+	if snap == nil {
+		return nil, NotFoundError{Reason: fmt.Sprintf("no config snapshot is available yet for proxy %s", sid.String())}
+	}
+	redactLeafPrivateKeys(snap)
+
+	return xds.MakeConfigDump(s.agent.logger.Named(logging.Envoy), s.agent, s.agent, snap)
+}
+
+// redactLeafPrivateKeys removes the private keys of any leaf certificates in
+// the snapshot so that they are not included in the rendered TLS contexts.
+// The snapshot must be a copy that is not shared with the proxy's state.
+func redactLeafPrivateKeys(snap *proxycfg.ConfigSnapshot) {
+	redact := func(leaf *structs.IssuedCert) {
+		if leaf != nil && leaf.PrivateKeyPEM != "" {
+			leaf.PrivateKeyPEM = "<hidden>"
+		}
+	}
+
+	switch snap.Kind {
+	case structs.ServiceKindConnectProxy:
+		redact(snap.ConnectProxy.Leaf)
+	case structs.ServiceKindIngressGateway:
+		redact(snap.IngressGateway.Leaf)
+	case structs.ServiceKindTerminatingGateway:
+		for _, leaf := range snap.TerminatingGateway.ServiceLeaves {
+			redact(leaf)
+		}
+	}
+}
+
 // AgentHost
 //
 // GET /v1/agent/host
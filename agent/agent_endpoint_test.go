@@ -37,6 +37,7 @@ import (
 	"github.com/hashicorp/consul/agent/structs"
 	"github.com/hashicorp/consul/agent/token"
 	tokenStore "github.com/hashicorp/consul/agent/token"
+	"github.com/hashicorp/consul/agent/xds"
 	"github.com/hashicorp/consul/agent/xds/proxysupport"
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/sdk/testutil"
@@ -6784,6 +6785,203 @@ func TestAgentConnectAuthorize_deny(t *testing.T) {
 	assert.Contains(t, obj.Reason, "Matched")
 }
 
+// Test that a denying intention is logged but not enforced when the mesh
+// config entry puts intentions in audit mode.
+func TestAgentConnectAuthorize_denyAuditMode(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+	target := "db"
+
+	// Create a denying intention
+	{
+		req := structs.IntentionRequest{
+			Datacenter: "dc1",
+			Op:         structs.IntentionOpCreate,
+			Intention:  structs.TestIntention(t),
+		}
+		req.Intention.SourceNS = structs.IntentionDefaultNamespace
+		req.Intention.SourceName = "web"
+		req.Intention.DestinationNS = structs.IntentionDefaultNamespace
+		req.Intention.DestinationName = target
+		req.Intention.Action = structs.IntentionActionDeny
+
+		var reply string
+		require.NoError(t, a.RPC("Intention.Apply", &req, &reply))
+	}
+
+	// Put intentions in audit mode
+	{
+		req := structs.ConfigEntryRequest{
+			Datacenter: "dc1",
+			Entry: &structs.MeshConfigEntry{
+				Intentions: structs.IntentionsMeshConfig{
+					AuditMode: true,
+				},
+			},
+		}
+		var reply bool
+		require.NoError(t, a.RPC("ConfigEntry.Apply", &req, &reply))
+	}
+
+	args := &structs.ConnectAuthorizeRequest{
+		Target:        target,
+		ClientCertURI: connect.TestSpiffeIDService(t, "web").URI().String(),
+	}
+	req, _ := http.NewRequest("POST", "/v1/agent/connect/authorize", jsonReader(args))
+	resp := httptest.NewRecorder()
+	a.srv.h.ServeHTTP(resp, req)
+	assert.Equal(t, 200, resp.Code)
+
+	dec := json.NewDecoder(resp.Body)
+	obj := &connectAuthorizeResp{}
+	require.NoError(t, dec.Decode(obj))
+	assert.True(t, obj.Authorized)
+	assert.Contains(t, obj.Reason, "Audit mode")
+	assert.Contains(t, obj.Reason, "Matched")
+
+	// The would-be denial is summarized per source and destination.
+	for i := 0; i < 2; i++ {
+		req, _ = http.NewRequest("POST", "/v1/agent/connect/authorize", jsonReader(args))
+		a.srv.h.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	req, _ = http.NewRequest("GET", "/v1/agent/connect/authorize/audit", nil)
+	resp = httptest.NewRecorder()
+	a.srv.h.ServeHTTP(resp, req)
+	require.Equal(t, 200, resp.Code)
+
+	var denials []structs.ConnectAuthorizeAuditDenial
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&denials))
+	require.Len(t, denials, 1)
+	require.Equal(t, "web", denials[0].SourceName)
+	require.Equal(t, target, denials[0].DestinationName)
+	require.Equal(t, uint64(3), denials[0].Count)
+	require.Contains(t, denials[0].Reason, "Matched")
+	require.False(t, denials[0].LastDenied.Before(denials[0].FirstDenied))
+}
+
+func TestAgentConnectAuthorizeAudit_ACLFilter(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := NewTestAgent(t, TestACLConfig())
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	web := structs.NewServiceName("web", nil)
+	a.connectAuditDenials.record(web, structs.NewServiceName("db", nil), "Matched L4 intention")
+	a.connectAuditDenials.record(web, structs.NewServiceName("api", nil), "Default behavior configured by ACLs")
+
+	token := testCreateToken(t, a, `
+		service "db" {
+			policy = "read"
+		}
+	`)
+
+	req, _ := http.NewRequest("GET", "/v1/agent/connect/authorize/audit?token="+token, nil)
+	resp := httptest.NewRecorder()
+	a.srv.h.ServeHTTP(resp, req)
+	require.Equal(t, 200, resp.Code)
+
+	var denials []structs.ConnectAuthorizeAuditDenial
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&denials))
+	require.Len(t, denials, 1)
+	require.Equal(t, "db", denials[0].DestinationName)
+
+	req, _ = http.NewRequest("GET", "/v1/agent/connect/authorize/audit?token=root", nil)
+	resp = httptest.NewRecorder()
+	a.srv.h.ServeHTTP(resp, req)
+	require.Equal(t, 200, resp.Code)
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&denials))
+	require.Len(t, denials, 2)
+	require.Equal(t, "api", denials[0].DestinationName)
+	require.Equal(t, "db", denials[1].DestinationName)
+}
+
+func TestAgentConnectProxyXDS(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	reg := &structs.ServiceDefinition{
+		Kind: structs.ServiceKindConnectProxy,
+		ID:   "web-sidecar-proxy",
+		Name: "web-sidecar-proxy",
+		Port: 21000,
+		Proxy: &structs.ConnectProxyConfig{
+			DestinationServiceName: "web",
+			DestinationServiceID:   "web",
+			LocalServicePort:       8080,
+			Upstreams: structs.Upstreams{
+				{
+					DestinationName: "db",
+					LocalBindPort:   9191,
+				},
+			},
+		},
+	}
+	req, _ := http.NewRequest("PUT", "/v1/agent/service/register", jsonReader(reg))
+	resp := httptest.NewRecorder()
+	a.srv.h.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	t.Run("unknown proxy", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/v1/agent/connect/proxy/nope/xds", nil)
+		resp := httptest.NewRecorder()
+		a.srv.h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusNotFound, resp.Code)
+	})
+
+	t.Run("invalid path", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/v1/agent/connect/proxy/web-sidecar-proxy", nil)
+		resp := httptest.NewRecorder()
+		a.srv.h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusNotFound, resp.Code)
+	})
+
+	t.Run("renders resources", func(t *testing.T) {
+		retry.Run(t, func(r *retry.R) {
+			req, _ := http.NewRequest("GET", "/v1/agent/connect/proxy/web-sidecar-proxy/xds", nil)
+			resp := httptest.NewRecorder()
+			a.srv.h.ServeHTTP(resp, req)
+			require.Equal(r, http.StatusOK, resp.Code, resp.Body.String())
+
+			body := resp.Body.String()
+			require.NotContains(r, body, "PRIVATE KEY")
+
+			var dump xds.ConfigDump
+			require.NoError(r, json.Unmarshal([]byte(body), &dump))
+
+			var listeners []string
+			for _, l := range dump.Listeners {
+				var decoded struct{ Name string }
+				require.NoError(r, json.Unmarshal(l, &decoded))
+				listeners = append(listeners, decoded.Name)
+			}
+			require.Contains(r, listeners, "db:127.0.0.1:9191")
+			require.NotEmpty(r, dump.Clusters)
+			require.NotEmpty(r, dump.Endpoints)
+		})
+	})
+}
+
 // Test when there is an intention allowing service with a different trust
 // domain. We allow this because migration between trust domains shouldn't cause
 // an outage even if we have stale info about current trusted domains. It's safe
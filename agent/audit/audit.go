@@ -0,0 +1,276 @@
+// Package audit records the requests made to the HTTP API of an agent and to
+// the write RPC endpoints of servers as structured events written to sinks.
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/go-uuid"
+
+	"github.com/hashicorp/consul/logging"
+)
+
+const (
+	// EventVersion is the version of the format of the events.
+	EventVersion = "1"
+
+	// EventTypeHTTP is the type of the events recorded for HTTP requests.
+	EventTypeHTTP = "HTTPEvent"
+
+	// EventTypeRPC is the type of the events recorded for RPC requests.
+	EventTypeRPC = "RPCEvent"
+
+	// MethodRPC is the method of the events recorded for RPC requests.
+	MethodRPC = "RPC"
+
+	// OutcomeSuccess is the outcome of the requests that succeeded.
+	OutcomeSuccess = "success"
+
+	// OutcomeDenied is the outcome of the requests rejected because of
+	// missing permissions.
+	OutcomeDenied = "denied"
+
+	// OutcomeError is the outcome of the requests that failed for another
+	// reason.
+	OutcomeError = "error"
+
+	SinkTypeFile   = "file"
+	SinkFormatJSON = "json"
+
+	DeliveryGuaranteeBestEffort = "best-effort"
+)
+
+// Config configures the audit logging of an agent.
+type Config struct {
+	Enabled bool
+
+	// Sinks are the destinations of the events, keyed by name.
+	Sinks map[string]SinkConfig
+
+	// IncludeEndpoints and ExcludeEndpoints filter the events by endpoint.
+	// Entries match endpoints exactly, or by prefix when they end with "*".
+	// When IncludeEndpoints is empty all the endpoints are included.
+	IncludeEndpoints []string
+	ExcludeEndpoints []string
+
+	// IncludeMethods and ExcludeMethods filter the events by method, such as
+	// "PUT" for HTTP requests or "RPC" for RPC requests. When IncludeMethods
+	// is empty all the methods are included.
+	IncludeMethods []string
+	ExcludeMethods []string
+}
+
+// SinkConfig configures a destination of the events.
+type SinkConfig struct {
+	Type              string
+	Format            string
+	Path              string
+	DeliveryGuarantee string
+	Mode              os.FileMode
+	RotateBytes       int
+	RotateDuration    time.Duration
+	RotateMaxFiles    int
+}
+
+// Validate returns an error when the configuration is not supported.
+func (c *Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if len(c.Sinks) == 0 {
+		return fmt.Errorf("audit.sink must be configured when audit is enabled")
+	}
+	for name, sink := range c.Sinks {
+		if sink.Type != SinkTypeFile {
+			return fmt.Errorf("audit.sink[%q].type must be %q", name, SinkTypeFile)
+		}
+		if sink.Format != SinkFormatJSON {
+			return fmt.Errorf("audit.sink[%q].format must be %q", name, SinkFormatJSON)
+		}
+		if sink.Path == "" {
+			return fmt.Errorf("audit.sink[%q].path is required", name)
+		}
+		if sink.DeliveryGuarantee != "" && sink.DeliveryGuarantee != DeliveryGuaranteeBestEffort {
+			return fmt.Errorf("audit.sink[%q].delivery_guarantee must be %q", name, DeliveryGuaranteeBestEffort)
+		}
+	}
+	return nil
+}
+
+// Event is a request recorded by the audit log.
+type Event struct {
+	ID        string    `json:"id"`
+	Version   string    `json:"version"`
+	Type      string    `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+	Auth      Auth      `json:"auth"`
+	Request   Request   `json:"request"`
+	Response  Response  `json:"response"`
+}
+
+// Auth identifies the token used for a request. The SecretID of the token is
+// never recorded.
+type Auth struct {
+	AccessorID string `json:"accessor_id,omitempty"`
+}
+
+// Request describes a recorded request.
+type Request struct {
+	// Endpoint is the registered path of HTTP requests, such as "/v1/kv/",
+	// or the method of RPC requests, such as "KVS.Apply".
+	Endpoint string `json:"endpoint"`
+
+	// Method is the HTTP method, or MethodRPC for RPC requests.
+	Method string `json:"method"`
+
+	// Resource is the resource the request was made for, such as the key of a
+	// KV request.
+	Resource string `json:"resource,omitempty"`
+
+	RemoteAddr string `json:"remote_addr,omitempty"`
+}
+
+// Response describes the outcome of a recorded request.
+type Response struct {
+	Outcome string `json:"outcome"`
+
+	// Status is the status code of HTTP requests.
+	Status int `json:"status,omitempty"`
+
+	// Error is the error returned by RPC requests.
+	Error string `json:"error,omitempty"`
+
+	// LatencyMS is the time spent serving the request in milliseconds.
+	LatencyMS float64 `json:"latency_ms"`
+}
+
+// Auditor writes the events of the requests matching its filters to its
+// sinks. A nil *Auditor records nothing.
+type Auditor struct {
+	config Config
+	logger hclog.Logger
+	sinks  []io.WriteCloser
+}
+
+// New returns an Auditor writing to the sinks of the configuration, or nil
+// when audit logging is not enabled.
+func New(config Config, logger hclog.Logger) (*Auditor, error) {
+	if !config.Enabled {
+		return nil, nil
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	a := &Auditor{config: config, logger: logger}
+	for name, sink := range config.Sinks {
+		file, err := logging.NewLogFile(sink.Path, "audit.json", sink.RotateDuration, sink.RotateBytes, sink.RotateMaxFiles, sink.Mode)
+		if err != nil {
+			a.Close()
+			return nil, fmt.Errorf("failed to open audit sink %q: %w", name, err)
+		}
+		a.sinks = append(a.sinks, file)
+	}
+	return a, nil
+}
+
+// Enabled returns whether the requests to the endpoint with the method are
+// recorded.
+func (a *Auditor) Enabled(endpoint, method string) bool {
+	if a == nil {
+		return false
+	}
+	if len(a.config.IncludeEndpoints) > 0 && !matchEndpoint(a.config.IncludeEndpoints, endpoint) {
+		return false
+	}
+	if matchEndpoint(a.config.ExcludeEndpoints, endpoint) {
+		return false
+	}
+	if len(a.config.IncludeMethods) > 0 && !matchMethod(a.config.IncludeMethods, method) {
+		return false
+	}
+	return !matchMethod(a.config.ExcludeMethods, method)
+}
+
+func matchEndpoint(patterns []string, endpoint string) bool {
+	for _, pattern := range patterns {
+		if strings.HasSuffix(pattern, "*") {
+			if strings.HasPrefix(endpoint, strings.TrimSuffix(pattern, "*")) {
+				return true
+			}
+		} else if pattern == endpoint {
+			return true
+		}
+	}
+	return false
+}
+
+func matchMethod(methods []string, method string) bool {
+	for _, m := range methods {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// Record writes the event to the sinks when its endpoint and method are
+// enabled. The ID, Version and Timestamp of the event are set when empty.
+// Delivery is best-effort, failures are logged.
+func (a *Auditor) Record(event *Event) {
+	if !a.Enabled(event.Request.Endpoint, event.Request.Method) {
+		return
+	}
+
+	if event.ID == "" {
+		id, err := uuid.GenerateUUID()
+		if err != nil {
+			a.logger.Warn("failed to generate audit event ID", "error", err)
+		}
+		event.ID = id
+	}
+	if event.Version == "" {
+		event.Version = EventVersion
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	buf, err := json.Marshal(event)
+	if err != nil {
+		a.logger.Warn("failed to encode audit event", "error", err)
+		return
+	}
+	buf = append(buf, '\n')
+	for _, sink := range a.sinks {
+		if _, err := sink.Write(buf); err != nil {
+			a.logger.Warn("failed to write audit event", "error", err)
+		}
+	}
+}
+
+// Close closes the sinks.
+func (a *Auditor) Close() error {
+	if a == nil {
+		return nil
+	}
+	var result error
+	for _, sink := range a.sinks {
+		if err := sink.Close(); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+	return result
+}
+
+// Latency returns the duration in milliseconds, as recorded in events.
+func Latency(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,176 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/sdk/testutil"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	sink := SinkConfig{Type: SinkTypeFile, Format: SinkFormatJSON, Path: "/tmp/audit.json"}
+
+	cases := map[string]struct {
+		config Config
+		err    string
+	}{
+		"disabled": {
+			config: Config{},
+		},
+		"valid": {
+			config: Config{Enabled: true, Sinks: map[string]SinkConfig{"main": sink}},
+		},
+		"no sinks": {
+			config: Config{Enabled: true},
+			err:    "audit.sink must be configured",
+		},
+		"invalid type": {
+			config: Config{Enabled: true, Sinks: map[string]SinkConfig{"main": {Type: "syslog", Format: SinkFormatJSON, Path: "/tmp/audit.json"}}},
+			err:    `audit.sink["main"].type must be "file"`,
+		},
+		"invalid format": {
+			config: Config{Enabled: true, Sinks: map[string]SinkConfig{"main": {Type: SinkTypeFile, Format: "text", Path: "/tmp/audit.json"}}},
+			err:    `audit.sink["main"].format must be "json"`,
+		},
+		"missing path": {
+			config: Config{Enabled: true, Sinks: map[string]SinkConfig{"main": {Type: SinkTypeFile, Format: SinkFormatJSON}}},
+			err:    `audit.sink["main"].path is required`,
+		},
+		"invalid delivery guarantee": {
+			config: Config{Enabled: true, Sinks: map[string]SinkConfig{"main": {Type: SinkTypeFile, Format: SinkFormatJSON, Path: "/tmp/audit.json", DeliveryGuarantee: "enforced"}}},
+			err:    `audit.sink["main"].delivery_guarantee must be "best-effort"`,
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			err := tc.config.Validate()
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
+
+func TestAuditor_Enabled(t *testing.T) {
+	var nilAuditor *Auditor
+	require.False(t, nilAuditor.Enabled("/v1/kv/", "PUT"))
+
+	a := &Auditor{config: Config{
+		IncludeEndpoints: []string{"/v1/kv/*", "/v1/catalog/register", "KVS.*"},
+		ExcludeEndpoints: []string{"/v1/kv/private*"},
+		IncludeMethods:   []string{"put", "DELETE", "RPC"},
+		ExcludeMethods:   []string{"DELETE"},
+	}}
+
+	cases := []struct {
+		endpoint string
+		method   string
+		expect   bool
+	}{
+		{"/v1/kv/", "PUT", true},
+		{"/v1/kv/", "GET", false},
+		{"/v1/kv/", "DELETE", false},
+		{"/v1/kv/private/", "PUT", false},
+		{"/v1/catalog/register", "PUT", true},
+		{"/v1/catalog/register/", "PUT", false},
+		{"/v1/acl/token", "PUT", false},
+		{"KVS.Apply", "RPC", true},
+		{"Catalog.Register", "RPC", false},
+	}
+	for _, tc := range cases {
+		require.Equal(t, tc.expect, a.Enabled(tc.endpoint, tc.method), "%s %s", tc.method, tc.endpoint)
+	}
+
+	all := &Auditor{}
+	require.True(t, all.Enabled("/v1/agent/self", "GET"))
+}
+
+func TestAuditor_Record(t *testing.T) {
+	dir := testutil.TempDir(t, "audit")
+
+	a, err := New(Config{
+		Enabled: true,
+		Sinks: map[string]SinkConfig{
+			"main": {
+				Type:   SinkTypeFile,
+				Format: SinkFormatJSON,
+				Path:   filepath.Join(dir, "audit.json"),
+				Mode:   0600,
+			},
+		},
+		ExcludeMethods: []string{"GET"},
+	}, hclog.NewNullLogger())
+	require.NoError(t, err)
+
+	a.Record(&Event{
+		Type: EventTypeHTTP,
+		Auth: Auth{AccessorID: "8f4e8f4c-9b5a-4d4e-8c2f-3b5e4a1f1f1f"},
+		Request: Request{
+			Endpoint: "/v1/kv/",
+			Method:   "PUT",
+			Resource: "foo",
+		},
+		Response: Response{
+			Outcome: OutcomeSuccess,
+			Status:  200,
+		},
+	})
+	// Filtered out.
+	a.Record(&Event{
+		Type:    EventTypeHTTP,
+		Request: Request{Endpoint: "/v1/kv/", Method: "GET"},
+	})
+	require.NoError(t, a.Close())
+
+	// The sink names the files after the path and their creation time.
+	files, err := filepath.Glob(filepath.Join(dir, "audit-*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	info, err := os.Stat(files[0])
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	f, err := os.Open(files[0])
+	require.NoError(t, err)
+	defer f.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var event Event
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		events = append(events, event)
+	}
+	require.NoError(t, scanner.Err())
+	require.Len(t, events, 1)
+
+	event := events[0]
+	require.NotEmpty(t, event.ID)
+	require.Equal(t, EventVersion, event.Version)
+	require.False(t, event.Timestamp.IsZero())
+	require.Equal(t, "8f4e8f4c-9b5a-4d4e-8c2f-3b5e4a1f1f1f", event.Auth.AccessorID)
+	require.Equal(t, "/v1/kv/", event.Request.Endpoint)
+	require.Equal(t, "PUT", event.Request.Method)
+	require.Equal(t, "foo", event.Request.Resource)
+	require.Equal(t, OutcomeSuccess, event.Response.Outcome)
+	require.Equal(t, 200, event.Response.Status)
+}
+
+func TestNew_Disabled(t *testing.T) {
+	a, err := New(Config{}, hclog.NewNullLogger())
+	require.NoError(t, err)
+	require.Nil(t, a)
+	require.NoError(t, a.Close())
+}
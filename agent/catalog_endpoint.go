@@ -124,6 +124,18 @@ var CatalogCounters = []prometheus.CounterDefinition{
 		Name: []string{"client", "api", "success", "catalog_gateway_services"},
 		Help: "Increments whenever a Consul agent successfully responds to a request to list services associated with a gateway.",
 	},
+	{
+		Name: []string{"client", "api", "catalog_renew_node"},
+		Help: "Increments whenever a Consul agent receives a request to renew the TTL of a node in the catalog.",
+	},
+	{
+		Name: []string{"client", "rpc", "error", "catalog_renew_node"},
+		Help: "Increments whenever a Consul agent receives an RPC error for a request to renew the TTL of a node in the catalog.",
+	},
+	{
+		Name: []string{"client", "api", "success", "catalog_renew_node"},
+		Help: "Increments whenever a Consul agent successfully responds to a request to renew the TTL of a node in the catalog.",
+	},
 }
 
 func (s *HTTPHandlers) CatalogRegister(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
@@ -191,6 +203,45 @@ func (s *HTTPHandlers) CatalogDeregister(resp http.ResponseWriter, req *http.Req
 	return true, nil
 }
 
+func (s *HTTPHandlers) CatalogRenewNode(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	metrics.IncrCounterWithLabels([]string{"client", "api", "catalog_renew_node"}, 1,
+		s.nodeMetricsLabels())
+
+	args := structs.NodeSpecificRequest{}
+	if err := s.parseEntMetaNoWildcard(req, &args.EnterpriseMeta); err != nil {
+		return nil, err
+	}
+	if done := s.parse(resp, req, &args.Datacenter, &args.QueryOptions); done {
+		return nil, nil
+	}
+
+	// Pull out the node name
+	var err error
+	args.Node, err = getPathSuffixUnescaped(req.URL.Path, "/v1/catalog/renew/")
+	if err != nil {
+		return nil, err
+	}
+	if args.Node == "" {
+		resp.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(resp, "Missing node name")
+		return nil, nil
+	}
+
+	var out structs.IndexedNodes
+	if err := s.agent.RPC("Catalog.RenewNode", &args, &out); err != nil {
+		metrics.IncrCounterWithLabels([]string{"client", "rpc", "error", "catalog_renew_node"}, 1,
+			s.nodeMetricsLabels())
+		return nil, err
+	} else if out.Nodes == nil {
+		resp.WriteHeader(http.StatusNotFound)
+		fmt.Fprintf(resp, "Node '%s' not found", args.Node)
+		return nil, nil
+	}
+	metrics.IncrCounterWithLabels([]string{"client", "api", "success", "catalog_renew_node"}, 1,
+		s.nodeMetricsLabels())
+	return out.Nodes, nil
+}
+
 func (s *HTTPHandlers) CatalogDatacenters(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	metrics.IncrCounterWithLabels([]string{"client", "api", "catalog_datacenters"}, 1,
 		s.nodeMetricsLabels())
@@ -69,6 +69,43 @@ func TestCatalogDeregister(t *testing.T) {
 	}
 }
 
+func TestCatalogRenewNode(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	// Register a node with a TTL
+	args := &structs.RegisterRequest{
+		Node:     "foo",
+		Address:  "127.0.0.1",
+		NodeMeta: map[string]string{structs.MetaNodeTTL: "30s"},
+	}
+	req, _ := http.NewRequest("PUT", "/v1/catalog/register", jsonReader(args))
+	_, err := a.srv.CatalogRegister(nil, req)
+	require.NoError(t, err)
+
+	req, _ = http.NewRequest("PUT", "/v1/catalog/renew/foo", nil)
+	resp := httptest.NewRecorder()
+	obj, err := a.srv.CatalogRenewNode(resp, req)
+	require.NoError(t, err)
+	nodes := obj.(structs.Nodes)
+	require.Len(t, nodes, 1)
+	require.Equal(t, "foo", nodes[0].Node)
+	require.Equal(t, "30s", nodes[0].Meta[structs.MetaNodeTTL])
+
+	req, _ = http.NewRequest("PUT", "/v1/catalog/renew/nope", nil)
+	resp = httptest.NewRecorder()
+	obj, err = a.srv.CatalogRenewNode(resp, req)
+	require.NoError(t, err)
+	require.Nil(t, obj)
+	require.Equal(t, http.StatusNotFound, resp.Code)
+}
+
 func TestCatalogDatacenters(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -26,6 +26,7 @@ import (
 	"github.com/hashicorp/memberlist"
 	"golang.org/x/time/rate"
 
+	"github.com/hashicorp/consul/agent/audit"
 	"github.com/hashicorp/consul/agent/cache"
 	"github.com/hashicorp/consul/agent/checks"
 	"github.com/hashicorp/consul/agent/connect/ca"
@@ -873,6 +874,13 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 			ACLReplicationToken:   stringVal(c.ACL.Tokens.Replication),
 		},
 
+		ACLLogin: ACLLogin{
+			AuthMethod:      stringVal(c.ACL.Login.AuthMethod),
+			BearerTokenFile: stringVal(c.ACL.Login.BearerTokenFile),
+			Meta:            c.ACL.Login.Meta,
+			SetDefaultToken: boolVal(c.ACL.Login.SetDefaultToken),
+		},
+
 		// Autopilot
 		AutopilotCleanupDeadServers:      boolVal(c.Autopilot.CleanupDeadServers),
 		AutopilotDisableUpgradeMigration: boolVal(c.Autopilot.DisableUpgradeMigration),
@@ -974,6 +982,7 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 		AutoEncryptDNSSAN:                      autoEncryptDNSSAN,
 		AutoEncryptIPSAN:                       autoEncryptIPSAN,
 		AutoEncryptAllowTLS:                    autoEncryptAllowTLS,
+		Audit:                                  b.auditVal(c.Audit),
 		AutoConfig:                             autoConfig,
 		ConnectEnabled:                         connectEnabled,
 		ConnectCAProvider:                      connectCAProvider,
@@ -1098,6 +1107,18 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 		rt.RaftBoltDBConfig = *c.RaftBoltDBConfig
 	}
 
+	rt.RaftLogStoreConfig = consul.RaftLogStoreConfig{
+		Backend: stringVal(c.RaftLogStore.Backend),
+		WAL: consul.RaftWALConfig{
+			SegmentSize: intVal(c.RaftLogStore.WAL.SegmentSizeMB) * 1024 * 1024,
+		},
+	}
+
+	rt.LeaderLeaseReads = consul.LeaderLeaseReadsConfig{
+		Enabled:      boolVal(c.LeaderLeaseReads.Enabled),
+		MaxClockSkew: b.durationVal("leader_lease_reads.max_clock_skew", c.LeaderLeaseReads.MaxClockSkew),
+	}
+
 	if rt.Cache.EntryFetchMaxBurst <= 0 {
 		return RuntimeConfig{}, fmt.Errorf("cache.entry_fetch_max_burst must be strictly positive, was: %v", rt.Cache.EntryFetchMaxBurst)
 	}
@@ -1294,6 +1315,9 @@ func (b *builder) validate(rt RuntimeConfig) error {
 	if rt.BootstrapExpect > 0 && rt.Bootstrap {
 		return fmt.Errorf("'bootstrap_expect > 0' and 'bootstrap = true' are mutually exclusive")
 	}
+	if rt.ReadReplica && rt.Bootstrap {
+		return fmt.Errorf("'read_replica = true' and 'bootstrap = true' are mutually exclusive")
+	}
 	if rt.CheckOutputMaxSize < 1 {
 		return fmt.Errorf("check_output_max_size must be positive, to discard check output use the discard_check_output flag")
 	}
@@ -1303,6 +1327,22 @@ func (b *builder) validate(rt RuntimeConfig) error {
 	if rt.AutopilotMaxTrailingLogs < 0 {
 		return fmt.Errorf("autopilot.max_trailing_logs cannot be %d. Must be greater than or equal to zero", rt.AutopilotMaxTrailingLogs)
 	}
+	switch rt.RaftLogStoreConfig.Backend {
+	case consul.RaftLogStoreBackendBoltDB, consul.RaftLogStoreBackendWAL:
+	default:
+		return fmt.Errorf("raft_logstore.backend must be %q or %q, got %q",
+			consul.RaftLogStoreBackendBoltDB, consul.RaftLogStoreBackendWAL, rt.RaftLogStoreConfig.Backend)
+	}
+	if size := rt.RaftLogStoreConfig.WAL.SegmentSize; size < 1024*1024 || size > 1024*1024*1024 {
+		return fmt.Errorf("raft_logstore.wal.segment_size_mb must be between 1 and 1024")
+	}
+	if rt.LeaderLeaseReads.MaxClockSkew < 0 {
+		return fmt.Errorf("leader_lease_reads.max_clock_skew cannot be negative, got %s", rt.LeaderLeaseReads.MaxClockSkew)
+	}
+	if rt.LeaderLeaseReads.Enabled && rt.LeaderLeaseReads.MaxClockSkew >= rt.ConsulRaftHeartbeatTimeout {
+		return fmt.Errorf("leader_lease_reads.max_clock_skew must be lower than the Raft heartbeat timeout of %s, got %s",
+			rt.ConsulRaftHeartbeatTimeout, rt.LeaderLeaseReads.MaxClockSkew)
+	}
 	if err := validateBasicName("primary_datacenter", rt.PrimaryDatacenter, true); err != nil {
 		return err
 	}
@@ -1493,6 +1533,14 @@ func (b *builder) validate(rt RuntimeConfig) error {
 		return err
 	}
 
+	if err := rt.Audit.Validate(); err != nil {
+		return err
+	}
+
+	if err := b.validateACLLogin(rt); err != nil {
+		return err
+	}
+
 	if err := validateRemoteScriptsChecks(rt); err != nil {
 		// TODO: make this an error in a future version
 		b.warn(err.Error())
@@ -2233,6 +2281,40 @@ func (b *builder) makeAddrs(pri []net.Addr, sec []*net.IPAddr, port int) []net.A
 	return x
 }
 
+func (b *builder) auditVal(raw Audit) audit.Config {
+	cfg := audit.Config{
+		Enabled:          boolVal(raw.Enabled),
+		IncludeEndpoints: raw.IncludeEndpoints,
+		ExcludeEndpoints: raw.ExcludeEndpoints,
+		IncludeMethods:   raw.IncludeMethods,
+		ExcludeMethods:   raw.ExcludeMethods,
+	}
+	if len(raw.Sinks) > 0 {
+		cfg.Sinks = make(map[string]audit.SinkConfig, len(raw.Sinks))
+	}
+	for name, sink := range raw.Sinks {
+		var mode os.FileMode
+		if m := stringVal(sink.Mode); m != "" {
+			parsed, err := strconv.ParseUint(m, 8, 32)
+			if err != nil {
+				b.err = multierror.Append(b.err, fmt.Errorf("audit.sink[%q].mode: invalid file mode %q", name, m))
+			}
+			mode = os.FileMode(parsed)
+		}
+		cfg.Sinks[name] = audit.SinkConfig{
+			Type:              stringVal(sink.Type),
+			Format:            stringVal(sink.Format),
+			Path:              stringVal(sink.Path),
+			DeliveryGuarantee: stringVal(sink.DeliveryGuarantee),
+			Mode:              mode,
+			RotateBytes:       intVal(sink.RotateBytes),
+			RotateDuration:    b.durationVal(fmt.Sprintf("audit.sink[%s].rotate_duration", name), sink.RotateDuration),
+			RotateMaxFiles:    intVal(sink.RotateMaxFiles),
+		}
+	}
+	return cfg
+}
+
 func (b *builder) autoConfigVal(raw AutoConfigRaw, agentPartition string) AutoConfig {
 	var val AutoConfig
 
@@ -2304,6 +2386,31 @@ func (b *builder) autoConfigAuthorizerVal(raw AutoConfigAuthorizationRaw, agentP
 	return val
 }
 
+func (b *builder) validateACLLogin(rt RuntimeConfig) error {
+	login := rt.ACLLogin
+	if login.AuthMethod == "" && login.BearerTokenFile == "" {
+		return nil
+	}
+
+	if !rt.ACLsEnabled {
+		return fmt.Errorf("acl.login cannot be set without enabling ACLs (acl.enabled)")
+	}
+	if login.AuthMethod == "" {
+		return fmt.Errorf("acl.login.auth_method must be set along with acl.login.bearer_token_file")
+	}
+	if login.BearerTokenFile == "" {
+		return fmt.Errorf("acl.login.bearer_token_file must be set along with acl.login.auth_method")
+	}
+
+	if rt.ACLTokens.ACLAgentToken != "" {
+		b.warn("acl.tokens.agent is replaced by the token obtained with acl.login")
+	}
+	if login.SetDefaultToken && rt.ACLTokens.ACLDefaultToken != "" {
+		b.warn("acl.tokens.default is replaced by the token obtained with acl.login")
+	}
+	return nil
+}
+
 func (b *builder) validateAutoConfig(rt RuntimeConfig) error {
 	autoconf := rt.AutoConfig
 
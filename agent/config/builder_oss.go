@@ -17,9 +17,6 @@ func validateEnterpriseConfigKeys(config *Config) []error {
 		result = append(result, enterpriseConfigKeyError{key: k})
 	}
 
-	if config.ReadReplica != nil {
-		add(`read_replica (or the deprecated non_voting_server)`)
-	}
 	if stringVal(config.SegmentName) != "" {
 		add("segment")
 	}
@@ -32,12 +29,6 @@ func validateEnterpriseConfigKeys(config *Config) []error {
 	if stringVal(config.Autopilot.RedundancyZoneTag) != "" {
 		add("autopilot.redundancy_zone_tag")
 	}
-	if stringVal(config.Autopilot.UpgradeVersionTag) != "" {
-		add("autopilot.upgrade_version_tag")
-	}
-	if config.Autopilot.DisableUpgradeMigration != nil {
-		add("autopilot.disable_upgrade_migration")
-	}
 	if config.DNS.PreferNamespace != nil {
 		add("dns_config.prefer_namespace")
 		config.DNS.PreferNamespace = nil
@@ -50,9 +41,6 @@ func validateEnterpriseConfigKeys(config *Config) []error {
 		add("acl.tokens.managed_service_provider")
 		config.ACL.Tokens.ManagedServiceProvider = nil
 	}
-	if boolVal(config.Audit.Enabled) || len(config.Audit.Sinks) > 0 {
-		add("audit")
-	}
 	if config.LicensePath != nil {
 		add("license_path")
 		config.LicensePath = nil
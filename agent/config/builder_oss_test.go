@@ -21,12 +21,6 @@ func TestValidateEnterpriseConfigKeys(t *testing.T) {
 	stringVal := "string"
 
 	cases := map[string]testCase{
-		"read_replica": {
-			config: Config{
-				ReadReplica: &boolVal,
-			},
-			badKeys: []string{"read_replica (or the deprecated non_voting_server)"},
-		},
 		"segment": {
 			config: Config{
 				SegmentName: &stringVal,
@@ -47,20 +41,6 @@ func TestValidateEnterpriseConfigKeys(t *testing.T) {
 			},
 			badKeys: []string{"autopilot.redundancy_zone_tag"},
 		},
-		"autopilot.upgrade_version_tag": {
-			config: Config{
-				Autopilot: Autopilot{
-					UpgradeVersionTag: &stringVal,
-				},
-			},
-			badKeys: []string{"autopilot.upgrade_version_tag"},
-		},
-		"autopilot.disable_upgrade_migration": {
-			config: Config{
-				Autopilot: Autopilot{DisableUpgradeMigration: &boolVal},
-			},
-			badKeys: []string{"autopilot.disable_upgrade_migration"},
-		},
 		"dns_config.prefer_namespace": {
 			config: Config{
 				DNS: DNS{PreferNamespace: &boolVal},
@@ -109,15 +89,15 @@ func TestValidateEnterpriseConfigKeys(t *testing.T) {
 		},
 		"multi": {
 			config: Config{
-				ReadReplica: &boolVal,
 				SegmentName: &stringVal,
+				Partition:   &stringVal,
 				ACL: ACL{
 					Tokens: Tokens{
 						DeprecatedTokens: DeprecatedTokens{AgentMaster: &stringVal},
 					},
 				},
 			},
-			badKeys: []string{"read_replica (or the deprecated non_voting_server)", "segment"},
+			badKeys: []string{"segment", "partition"},
 		},
 	}
 
@@ -260,6 +260,10 @@ type Config struct {
 
 	RaftBoltDBConfig *consul.RaftBoltDBConfig `mapstructure:"raft_boltdb"`
 
+	RaftLogStore RaftLogStoreConfig `mapstructure:"raft_logstore"`
+
+	LeaderLeaseReads LeaderLeaseReadsConfig `mapstructure:"leader_lease_reads"`
+
 	// UseStreamingBackend instead of blocking queries for service health and
 	// any other endpoints which support streaming.
 	UseStreamingBackend *bool `mapstructure:"use_streaming_backend"`
@@ -730,16 +734,17 @@ type Segment struct {
 }
 
 type ACL struct {
-	Enabled                *bool   `mapstructure:"enabled"`
-	TokenReplication       *bool   `mapstructure:"enable_token_replication"`
-	PolicyTTL              *string `mapstructure:"policy_ttl"`
-	RoleTTL                *string `mapstructure:"role_ttl"`
-	TokenTTL               *string `mapstructure:"token_ttl"`
-	DownPolicy             *string `mapstructure:"down_policy"`
-	DefaultPolicy          *string `mapstructure:"default_policy"`
-	EnableKeyListPolicy    *bool   `mapstructure:"enable_key_list_policy"`
-	Tokens                 Tokens  `mapstructure:"tokens"`
-	EnableTokenPersistence *bool   `mapstructure:"enable_token_persistence"`
+	Enabled                *bool       `mapstructure:"enabled"`
+	TokenReplication       *bool       `mapstructure:"enable_token_replication"`
+	PolicyTTL              *string     `mapstructure:"policy_ttl"`
+	RoleTTL                *string     `mapstructure:"role_ttl"`
+	TokenTTL               *string     `mapstructure:"token_ttl"`
+	DownPolicy             *string     `mapstructure:"down_policy"`
+	DefaultPolicy          *string     `mapstructure:"default_policy"`
+	EnableKeyListPolicy    *bool       `mapstructure:"enable_key_list_policy"`
+	Tokens                 Tokens      `mapstructure:"tokens"`
+	EnableTokenPersistence *bool       `mapstructure:"enable_token_persistence"`
+	Login                  ACLLoginRaw `mapstructure:"login"`
 
 	// Enterprise Only
 	MSPDisableBootstrap *bool `mapstructure:"msp_disable_bootstrap"`
@@ -765,6 +770,33 @@ type DeprecatedTokens struct {
 	AgentMaster *string `mapstructure:"agent_master"`
 }
 
+// ACLLoginRaw configures the auth method the agent logs in with to obtain its
+// agent token.
+type ACLLoginRaw struct {
+	AuthMethod      *string           `mapstructure:"auth_method"`
+	BearerTokenFile *string           `mapstructure:"bearer_token_file"`
+	Meta            map[string]string `mapstructure:"meta"`
+	SetDefaultToken *bool             `mapstructure:"set_default_token"`
+}
+
+// RaftLogStoreConfig selects and configures the backend storing the Raft
+// logs.
+type RaftLogStoreConfig struct {
+	Backend *string       `mapstructure:"backend"`
+	WAL     RaftWALConfig `mapstructure:"wal"`
+}
+
+type RaftWALConfig struct {
+	SegmentSizeMB *int `mapstructure:"segment_size_mb"`
+}
+
+// LeaderLeaseReadsConfig configures serving consistent reads from the leader
+// while it holds a lease on the leadership.
+type LeaderLeaseReadsConfig struct {
+	Enabled      *bool   `mapstructure:"enabled"`
+	MaxClockSkew *string `mapstructure:"max_clock_skew"`
+}
+
 // ServiceProviderToken groups an accessor and secret for a service provider token. Enterprise Only
 type ServiceProviderToken struct {
 	AccessorID *string `mapstructure:"accessor_id"`
@@ -782,8 +814,12 @@ type ConfigEntries struct {
 
 // Audit allows us to enable and define destinations for auditing
 type Audit struct {
-	Enabled *bool                `mapstructure:"enabled"`
-	Sinks   map[string]AuditSink `mapstructure:"sink"`
+	Enabled          *bool                `mapstructure:"enabled"`
+	Sinks            map[string]AuditSink `mapstructure:"sink"`
+	IncludeEndpoints []string             `mapstructure:"include_endpoints"`
+	ExcludeEndpoints []string             `mapstructure:"exclude_endpoints"`
+	IncludeMethods   []string             `mapstructure:"include_methods"`
+	ExcludeMethods   []string             `mapstructure:"exclude_methods"`
 }
 
 // AuditSink can be provided multiple times to define pipelines for auditing
@@ -127,6 +127,16 @@ func DefaultSource() Source {
 		raft_snapshot_threshold = ` + strconv.Itoa(int(cfg.RaftConfig.SnapshotThreshold)) + `
 		raft_snapshot_interval =  "` + cfg.RaftConfig.SnapshotInterval.String() + `"
 		raft_trailing_logs = ` + strconv.Itoa(int(cfg.RaftConfig.TrailingLogs)) + `
+		raft_logstore {
+			backend = "` + consul.RaftLogStoreBackendBoltDB + `"
+			wal {
+				segment_size_mb = 64
+			}
+		}
+		leader_lease_reads {
+			enabled = false
+			max_clock_skew = "500ms"
+		}
 
 	`,
 	}
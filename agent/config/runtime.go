@@ -10,6 +10,7 @@ import (
 	"github.com/hashicorp/go-uuid"
 	"golang.org/x/time/rate"
 
+	"github.com/hashicorp/consul/agent/audit"
 	"github.com/hashicorp/consul/agent/cache"
 	"github.com/hashicorp/consul/agent/consul"
 	"github.com/hashicorp/consul/agent/dns"
@@ -62,6 +63,13 @@ type RuntimeConfig struct {
 
 	ACLTokens token.Config
 
+	// ACLLogin configures the auth method the agent logs in with to obtain
+	// its agent token, which is then refreshed before it expires. Changes
+	// to this configuration require a restart.
+	//
+	// hcl: acl.login { auth_method = string bearer_token_file = string meta = map[string]string set_default_token = (true|false) }
+	ACLLogin ACLLogin
+
 	ACLResolverSettings consul.ACLResolverSettings
 
 	// ACLEnableKeyListPolicy is used to opt-in to the "list" policy added to
@@ -94,7 +102,7 @@ type RuntimeConfig struct {
 
 	// AutopilotDisableUpgradeMigration will disable Autopilot's upgrade migration
 	// strategy of waiting until enough newer-versioned servers have been added to the
-	// cluster before promoting them to voters. (Enterprise-only)
+	// cluster before promoting them to voters.
 	//
 	// hcl: autopilot { disable_upgrade_migration = (true|false)
 	AutopilotDisableUpgradeMigration bool
@@ -132,9 +140,8 @@ type RuntimeConfig struct {
 	AutopilotServerStabilizationTime time.Duration
 
 	// AutopilotUpgradeVersionTag is the node tag to use for version info when
-	// performing upgrade migrations. If left blank, the Consul version will be used.
-	//
-	// (Enterprise-only)
+	// performing upgrade migrations. If left blank, upgrade migrations are
+	// disabled.
 	//
 	// hcl: autopilot { upgrade_version_tag = string }
 	AutopilotUpgradeVersionTag string
@@ -478,6 +485,12 @@ type RuntimeConfig struct {
 	// AutoEncrypt.Sign requests.
 	AutoEncryptAllowTLS bool
 
+	// Audit configures the audit logging of the requests made to the HTTP API
+	// and, on servers, to the write RPC endpoints.
+	//
+	// hcl: audit { enabled = (true|false) sink "name" { type = "file" format = "json" path = string } }
+	Audit audit.Config
+
 	// AutoConfig is a grouping of the configurations around the agent auto configuration
 	// process including how servers can authorize requests.
 	AutoConfig AutoConfig
@@ -815,7 +828,8 @@ type RuntimeConfig struct {
 	NodeMeta map[string]string
 
 	// ReadReplica is whether this server will act as a non-voting member
-	// of the cluster to help provide read scalability. (Enterprise-only)
+	// of the cluster to help provide read scalability. Autopilot never promotes
+	// it and clients prefer it for stale reads.
 	//
 	// hcl: non_voting_server = (true|false)
 	// flag: -non-voting-server
@@ -945,6 +959,23 @@ type RuntimeConfig struct {
 
 	RaftBoltDBConfig consul.RaftBoltDBConfig
 
+	// RaftLogStoreConfig selects the backend storing the Raft logs and
+	// configures the write-ahead log backend. Changing the backend of an
+	// existing server requires migrating its logs with "consul operator raft
+	// migrate-logstore" while it is stopped.
+	//
+	// hcl: raft_logstore { backend = ("boltdb"|"wal") wal { segment_size_mb = int } }
+	RaftLogStoreConfig consul.RaftLogStoreConfig
+
+	// LeaderLeaseReads makes the leader serve consistent reads without a
+	// round trip to a quorum of servers while it holds a lease on the
+	// leadership. The lease is renewed in the background and lasts for the
+	// Raft heartbeat timeout, shortened by the maximum clock skew between the
+	// servers.
+	//
+	// hcl: leader_lease_reads { enabled = (true|false) max_clock_skew = "duration" }
+	LeaderLeaseReads consul.LeaderLeaseReadsConfig
+
 	// ReconnectTimeoutLAN specifies the amount of time to wait to reconnect with
 	// another agent before deciding it's permanently gone. This can be used to
 	// control the time it takes to reap failed nodes from the cluster.
@@ -1491,6 +1522,24 @@ type AutoConfig struct {
 	Authorizer      AutoConfigAuthorizer
 }
 
+type ACLLogin struct {
+	// AuthMethod is the name of the auth method to log in with. Logging in
+	// is disabled when empty.
+	AuthMethod string
+
+	// BearerTokenFile is the path of the file holding the bearer token
+	// presented to the auth method. It is read again on each login so that
+	// the bearer token can be rotated.
+	BearerTokenFile string
+
+	// Meta is set on the tokens created by the logins.
+	Meta map[string]string
+
+	// SetDefaultToken also uses the token obtained by logging in as the
+	// default token of the agent.
+	SetDefaultToken bool
+}
+
 type AutoConfigAuthorizer struct {
 	Enabled    bool
 	AuthMethod structs.ACLAuthMethod
@@ -1735,7 +1784,7 @@ func (c *RuntimeConfig) ToTLSUtilConfig() tlsutil.Config {
 // may contain a secret.
 func isSecret(name string) bool {
 	// special cases for AuthMethod locality and intro token file
-	if name == "TokenLocality" || name == "IntroTokenFile" {
+	if name == "TokenLocality" || name == "IntroTokenFile" || name == "BearerTokenFile" {
 		return false
 	}
 	name = strings.ToLower(name)
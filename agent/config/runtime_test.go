@@ -24,6 +24,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/agent/audit"
 	"github.com/hashicorp/consul/agent/cache"
 	"github.com/hashicorp/consul/agent/checks"
 	"github.com/hashicorp/consul/agent/consul"
@@ -1783,6 +1784,61 @@ func TestLoad_IntegrationWithFlags(t *testing.T) {
 		hcl:         []string{`autopilot = { max_trailing_logs = -1 }`},
 		expectedErr: "autopilot.max_trailing_logs cannot be -1. Must be greater than or equal to zero",
 	})
+	run(t, testCase{
+		desc: "raft_logstore.backend invalid",
+		args: []string{
+			`-datacenter=a`,
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "raft_logstore": { "backend": "leveldb" } }`},
+		hcl:         []string{`raft_logstore = { backend = "leveldb" }`},
+		expectedErr: `raft_logstore.backend must be "boltdb" or "wal", got "leveldb"`,
+	})
+	run(t, testCase{
+		desc: "raft_logstore.wal.segment_size_mb invalid",
+		args: []string{
+			`-datacenter=a`,
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "raft_logstore": { "backend": "wal", "wal": { "segment_size_mb": 0 } } }`},
+		hcl:         []string{`raft_logstore = { backend = "wal" wal { segment_size_mb = 0 } }`},
+		expectedErr: "raft_logstore.wal.segment_size_mb must be between 1 and 1024",
+	})
+	run(t, testCase{
+		desc: "leader_lease_reads.max_clock_skew negative",
+		args: []string{
+			`-datacenter=a`,
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "leader_lease_reads": { "max_clock_skew": "-1s" } }`},
+		hcl:         []string{`leader_lease_reads = { max_clock_skew = "-1s" }`},
+		expectedErr: "leader_lease_reads.max_clock_skew cannot be negative, got -1s",
+	})
+	run(t, testCase{
+		desc: "leader_lease_reads.max_clock_skew above heartbeat timeout",
+		args: []string{
+			`-datacenter=a`,
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "leader_lease_reads": { "enabled": true, "max_clock_skew": "5s" } }`},
+		hcl:         []string{`leader_lease_reads = { enabled = true max_clock_skew = "5s" }`},
+		expectedErr: "leader_lease_reads.max_clock_skew must be lower than the Raft heartbeat timeout of 5s, got 5s",
+	})
+	run(t, testCase{
+		desc: "leader_lease_reads enabled",
+		args: []string{
+			`-datacenter=a`,
+			`-data-dir=` + dataDir,
+		},
+		json: []string{`{ "leader_lease_reads": { "enabled": true } }`},
+		hcl:  []string{`leader_lease_reads = { enabled = true }`},
+		expected: func(rt *RuntimeConfig) {
+			rt.Datacenter = "a"
+			rt.PrimaryDatacenter = "a"
+			rt.DataDir = dataDir
+			rt.LeaderLeaseReads.Enabled = true
+		},
+	})
 	run(t, testCase{
 		desc:        "bind_addr cannot be empty",
 		args:        []string{`-data-dir=` + dataDir},
@@ -1855,6 +1911,16 @@ func TestLoad_IntegrationWithFlags(t *testing.T) {
 		hcl:         []string{`bootstrap = true bootstrap_expect = 3 server = true`},
 		expectedErr: "'bootstrap_expect > 0' and 'bootstrap = true' are mutually exclusive",
 	})
+	run(t, testCase{
+		desc: "read replica and bootstrap",
+		args: []string{
+			`-datacenter=a`,
+			`-data-dir=` + dataDir,
+		},
+		json:        []string{`{ "bootstrap": true, "read_replica": true, "server": true }`},
+		hcl:         []string{`bootstrap = true read_replica = true server = true`},
+		expectedErr: "'read_replica = true' and 'bootstrap = true' are mutually exclusive",
+	})
 	run(t, testCase{
 		desc: "bootstrap-expect=1 equals bootstrap",
 		args: []string{
@@ -4526,6 +4592,98 @@ func TestLoad_IntegrationWithFlags(t *testing.T) {
 		expectedErr: "auto_config.authorization.enabled cannot be set without providing a TLS certificate for the server",
 	})
 
+	run(t, testCase{
+		desc: "acl login",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		hcl: []string{`
+				acl {
+					enabled = true
+					login {
+						auth_method = "jwt"
+						bearer_token_file = "/var/run/jwt"
+						meta = {
+							foo = "bar"
+						}
+					}
+				}
+			`},
+		json: []string{`
+			{
+				"acl": {
+					"enabled": true,
+					"login": {
+						"auth_method": "jwt",
+						"bearer_token_file": "/var/run/jwt",
+						"meta": {
+							"foo": "bar"
+						}
+					}
+				}
+			}`},
+		expected: func(rt *RuntimeConfig) {
+			rt.DataDir = dataDir
+			rt.ACLsEnabled = true
+			rt.ACLResolverSettings.ACLsEnabled = true
+			rt.ACLLogin = ACLLogin{
+				AuthMethod:      "jwt",
+				BearerTokenFile: "/var/run/jwt",
+				Meta:            map[string]string{"foo": "bar"},
+			}
+		},
+	})
+
+	run(t, testCase{
+		desc: "acl login without ACLs",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		hcl: []string{`
+				acl {
+					login {
+						auth_method = "jwt"
+						bearer_token_file = "/var/run/jwt"
+					}
+				}
+			`},
+		json: []string{`
+			{
+				"acl": {
+					"login": {
+						"auth_method": "jwt",
+						"bearer_token_file": "/var/run/jwt"
+					}
+				}
+			}`},
+		expectedErr: "acl.login cannot be set without enabling ACLs (acl.enabled)",
+	})
+
+	run(t, testCase{
+		desc: "acl login without bearer token file",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		hcl: []string{`
+				acl {
+					enabled = true
+					login {
+						auth_method = "jwt"
+					}
+				}
+			`},
+		json: []string{`
+			{
+				"acl": {
+					"enabled": true,
+					"login": {
+						"auth_method": "jwt"
+					}
+				}
+			}`},
+		expectedErr: "acl.login.bearer_token_file must be set along with acl.login.auth_method",
+	})
+
 	run(t, testCase{
 		desc: "auto config no intro token",
 		args: []string{
@@ -5348,6 +5506,12 @@ func TestLoad_FullConfig(t *testing.T) {
 			ACLAgentRecoveryToken: "1dba6aba",
 			ACLReplicationToken:   "5795983a",
 		},
+		ACLLogin: ACLLogin{
+			AuthMethod:      "6ae0e31e",
+			BearerTokenFile: "/var/run/consul/bearer-token",
+			Meta:            map[string]string{"a1cc7f44": "0bda4e5d"},
+			SetDefaultToken: true,
+		},
 
 		ACLsEnabled:       true,
 		PrimaryDatacenter: "ejtmd43d",
@@ -5483,6 +5647,25 @@ func TestLoad_FullConfig(t *testing.T) {
 				},
 			},
 		},
+		Audit: audit.Config{
+			Enabled: true,
+			Sinks: map[string]audit.SinkConfig{
+				"main": {
+					Type:              "file",
+					Format:            "json",
+					Path:              "/tmp/audit/audit.json",
+					DeliveryGuarantee: "best-effort",
+					Mode:              0600,
+					RotateBytes:       1048576,
+					RotateDuration:    24 * time.Hour,
+					RotateMaxFiles:    7,
+				},
+			},
+			IncludeEndpoints: []string{"/v1/kv/*"},
+			ExcludeEndpoints: []string{"/v1/kv/private/*"},
+			IncludeMethods:   []string{"PUT", "DELETE"},
+			ExcludeMethods:   []string{"GET"},
+		},
 		AutoEncryptTLS:      false,
 		AutoEncryptDNSSAN:   []string{"a.com", "b.com"},
 		AutoEncryptIPSAN:    []net.IP{net.ParseIP("192.168.4.139"), net.ParseIP("192.168.4.140")},
@@ -5594,7 +5777,11 @@ func TestLoad_FullConfig(t *testing.T) {
 		KeyFile:                                "IEkkwgIA",
 		KVMaxValueSize:                         1234567800,
 		LeaveDrainTime:                         8265 * time.Second,
-		LeaveOnTerm:                            true,
+		LeaderLeaseReads: consul.LeaderLeaseReadsConfig{
+			Enabled:      true,
+			MaxClockSkew: 1400 * time.Millisecond,
+		},
+		LeaveOnTerm: true,
 		Logging: logging.Config{
 			LogLevel:       "k1zo9Spt",
 			LogJSON:        true,
@@ -6019,6 +6206,10 @@ func TestLoad_FullConfig(t *testing.T) {
 			},
 		},
 		RaftBoltDBConfig: consul.RaftBoltDBConfig{NoFreelistSync: true},
+		RaftLogStoreConfig: consul.RaftLogStoreConfig{
+			Backend: consul.RaftLogStoreBackendWAL,
+			WAL:     consul.RaftWALConfig{SegmentSize: 17 * 1024 * 1024},
+		},
 	}
 	entFullRuntimeConfig(expected)
 
@@ -6037,6 +6228,8 @@ func TestLoad_FullConfig(t *testing.T) {
 		deprecationWarning("acl_ttl", "acl.token_ttl"),
 		deprecationWarning("acl_enable_key_list_policy", "acl.enable_key_list_policy"),
 		`bootstrap_expect > 0: expecting 53 servers`,
+		"acl.tokens.agent is replaced by the token obtained with acl.login",
+		"acl.tokens.default is replaced by the token obtained with acl.login",
 	}
 	expectedWarns = append(expectedWarns, enterpriseConfigKeyWarnings...)
 
@@ -19,6 +19,14 @@ acl = {
     token_ttl = "3321s"
     enable_token_replication = true
     msp_disable_bootstrap = true
+    login = {
+        auth_method = "6ae0e31e"
+        bearer_token_file = "/var/run/consul/bearer-token"
+        meta = {
+            "a1cc7f44" = "0bda4e5d"
+        }
+        set_default_token = true
+    }
     tokens = {
         master = "8a19ac27",
         initial_management = "3820e09a",
@@ -50,6 +58,20 @@ advertise_addr_wan = "78.63.37.19"
 advertise_reconnect_timeout = "0s"
 audit = {
     enabled = true
+    sink "main" {
+        type = "file"
+        format = "json"
+        path = "/tmp/audit/audit.json"
+        delivery_guarantee = "best-effort"
+        mode = "0600"
+        rotate_bytes = 1048576
+        rotate_duration = "24h"
+        rotate_max_files = 7
+    }
+    include_endpoints = ["/v1/kv/*"]
+    exclude_endpoints = ["/v1/kv/private/*"]
+    include_methods = ["PUT", "DELETE"]
+    exclude_methods = ["GET"]
 }
 auto_config = {
     enabled = false
@@ -278,6 +300,10 @@ http_config {
     max_header_bytes = 10
 }
 key_file = "IEkkwgIA"
+leader_lease_reads {
+    enabled = true
+    max_clock_skew = "1400ms"
+}
 leave_on_terminate = true
 license_path = "/path/to/license.lic"
 limits {
@@ -331,6 +357,12 @@ raft_trailing_logs = 83749
 raft_boltdb {
     NoFreelistSync = true
 }
+raft_logstore {
+    backend = "wal"
+    wal {
+        segment_size_mb = 17
+    }
+}
 read_replica = true
 reconnect_timeout = "23739s"
 reconnect_timeout_wan = "26694s"
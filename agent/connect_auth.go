@@ -3,6 +3,12 @@ package agent
 import (
 	"context"
 	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/armon/go-metrics/prometheus"
 
 	"github.com/hashicorp/consul/acl"
 	"github.com/hashicorp/consul/agent/cache"
@@ -11,6 +17,13 @@ import (
 	"github.com/hashicorp/consul/agent/structs"
 )
 
+var ConnectAuthorizeCounters = []prometheus.CounterDefinition{
+	{
+		Name: []string{"client", "connect", "authorize", "audit_denied"},
+		Help: "Increments whenever intentions would have denied a connection that was allowed because intentions are in audit mode.",
+	},
+}
+
 // TODO(rb/intentions): this should move back into the agent endpoint since
 // there is no ext_authz implementation anymore.
 //
@@ -20,6 +33,10 @@ import (
 //
 // NOTE: This treats any L7 intentions as DENY.
 //
+// If the mesh config entry puts intentions in audit mode, connections that
+// would be denied are logged, counted and summarized per source and
+// destination but reported as authorized.
+//
 // The ACL token and the auth request are provided and the auth decision (true
 // means authorized) and reason string are returned.
 //
@@ -121,19 +138,153 @@ func (a *Agent) ConnectAuthorize(token string,
 		}
 	}
 
-	if ixnMatch != nil {
-		if len(ixnMatch.Permissions) == 0 {
-			// This is an L4 intention.
-			reason = fmt.Sprintf("Matched L4 intention: %s", ixnMatch.String())
-			auth := ixnMatch.Action == structs.IntentionActionAllow
-			return auth, reason, &meta, nil
-		}
+	switch {
+	case ixnMatch != nil && len(ixnMatch.Permissions) == 0:
+		// This is an L4 intention.
+		reason = fmt.Sprintf("Matched L4 intention: %s", ixnMatch.String())
+		allowed = ixnMatch.Action == structs.IntentionActionAllow
 
+	case ixnMatch != nil:
 		// This is an L7 intention, so DENY.
 		reason = fmt.Sprintf("Matched L7 intention: %s", ixnMatch.String())
-		return false, reason, &meta, nil
+		allowed = false
+
+	default:
+		reason = "Default behavior configured by ACLs"
+		allowed = authz.IntentionDefaultAllow(nil) == acl.Allow
+	}
+
+	if !allowed {
+		auditMode, err := a.intentionsAuditMode(token, req.TargetPartition())
+		if err != nil {
+			return returnErr(err)
+		}
+		if auditMode {
+			a.logger.Warn("connection would be denied by intentions, allowing due to audit mode",
+				"source", uriService.Service,
+				"source_namespace", uriService.NamespaceOrDefault(),
+				"destination", req.Target,
+				"destination_namespace", structs.NamespaceOrDefault(req.TargetNamespace()),
+				"reason", reason,
+			)
+			metrics.IncrCounterWithLabels([]string{"client", "connect", "authorize", "audit_denied"}, 1,
+				[]metrics.Label{
+					{Name: "source", Value: uriService.Service},
+					{Name: "destination", Value: req.Target},
+				})
+			a.connectAuditDenials.record(
+				structs.NewServiceName(uriService.Service, uriService.GetEnterpriseMeta()),
+				structs.NewServiceName(req.Target, &req.EnterpriseMeta),
+				reason,
+			)
+			return true, "Audit mode, would have denied: " + reason, &meta, nil
+		}
+	}
+	return allowed, reason, &meta, nil
+}
+
+// intentionsAuditMode returns whether the mesh config entry for the given
+// partition configures intentions to be audited rather than enforced.
+func (a *Agent) intentionsAuditMode(token, partition string) (bool, error) {
+	args := &structs.ConfigEntryQuery{
+		Kind:           structs.MeshConfig,
+		Name:           structs.MeshConfigMesh,
+		Datacenter:     a.config.Datacenter,
+		QueryOptions:   structs.QueryOptions{Token: token},
+		EnterpriseMeta: *structs.DefaultEnterpriseMetaInPartition(partition),
+	}
+	raw, _, err := a.cache.Get(context.TODO(), cachetype.ConfigEntryName, args)
+	if err != nil {
+		return false, err
 	}
 
-	reason = "Default behavior configured by ACLs"
-	return authz.IntentionDefaultAllow(nil) == acl.Allow, reason, &meta, nil
+	reply, ok := raw.(*structs.ConfigEntryResponse)
+	if !ok {
+		return false, fmt.Errorf("internal error: response type not correct")
+	}
+	if reply.Entry == nil {
+		return false, nil
+	}
+	meshConf, ok := reply.Entry.(*structs.MeshConfigEntry)
+	if !ok {
+		return false, fmt.Errorf("internal error: unexpected config entry type %T", reply.Entry)
+	}
+	return meshConf.IntentionsAuditMode(), nil
+}
+
+// maxConnectAuditDenials bounds the number of source and destination pairs
+// summarized by connectAuditDenials. The denials of the pairs beyond it are
+// still logged and counted in the audit_denied metric.
+const maxConnectAuditDenials = 1024
+
+type connectAuditPair struct {
+	source      structs.ServiceName
+	destination structs.ServiceName
+}
+
+// connectAuditDenials summarizes the connections authorized by the agent that
+// intentions would have denied in audit mode, per source and destination
+// service, so that they can be allowed before intentions are enforced.
+type connectAuditDenials struct {
+	lock  sync.Mutex
+	pairs map[connectAuditPair]*structs.ConnectAuthorizeAuditDenial
+}
+
+func newConnectAuditDenials() *connectAuditDenials {
+	return &connectAuditDenials{
+		pairs: make(map[connectAuditPair]*structs.ConnectAuthorizeAuditDenial),
+	}
+}
+
+func (d *connectAuditDenials) record(source, destination structs.ServiceName, reason string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	now := time.Now().UTC()
+	key := connectAuditPair{source: source, destination: destination}
+	denial, ok := d.pairs[key]
+	if !ok {
+		if len(d.pairs) >= maxConnectAuditDenials {
+			return
+		}
+		denial = &structs.ConnectAuthorizeAuditDenial{
+			SourceName:           source.Name,
+			SourceNS:             source.NamespaceOrEmpty(),
+			SourcePartition:      source.PartitionOrEmpty(),
+			DestinationName:      destination.Name,
+			DestinationNS:        destination.NamespaceOrEmpty(),
+			DestinationPartition: destination.PartitionOrEmpty(),
+			FirstDenied:          now,
+		}
+		d.pairs[key] = denial
+	}
+	denial.Reason = reason
+	denial.Count++
+	denial.LastDenied = now
+}
+
+// list returns the denials of the pairs whose destination is allowed by the
+// filter, sorted by destination and source.
+func (d *connectAuditDenials) list(allow func(destination structs.ServiceName) bool) []structs.ConnectAuthorizeAuditDenial {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	keys := make([]connectAuditPair, 0, len(d.pairs))
+	for key := range d.pairs {
+		if allow(key.destination) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].destination != keys[j].destination {
+			return keys[i].destination.String() < keys[j].destination.String()
+		}
+		return keys[i].source.String() < keys[j].source.String()
+	})
+
+	result := make([]structs.ConnectAuthorizeAuditDenial, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, *d.pairs[key])
+	}
+	return result
 }
@@ -104,6 +104,10 @@ func (id *missingIdentity) NodeIdentityList() []*structs.ACLNodeIdentity {
 	return nil
 }
 
+func (id *missingIdentity) PolicyTemplateList() []*structs.ACLPolicyTemplateLink {
+	return nil
+}
+
 func (id *missingIdentity) IsExpired(asOf time.Time) bool {
 	return false
 }
@@ -599,9 +603,10 @@ func (r *ACLResolver) resolvePoliciesForIdentity(identity structs.ACLIdentity) (
 		roleIDs           = identity.RoleIDs()
 		serviceIdentities = identity.ServiceIdentityList()
 		nodeIdentities    = identity.NodeIdentityList()
+		templateLinks     = identity.PolicyTemplateList()
 	)
 
-	if len(policyIDs) == 0 && len(serviceIdentities) == 0 && len(roleIDs) == 0 && len(nodeIdentities) == 0 {
+	if len(policyIDs) == 0 && len(serviceIdentities) == 0 && len(roleIDs) == 0 && len(nodeIdentities) == 0 && len(templateLinks) == 0 {
 		// In this case the default policy will be all that is in effect.
 		return nil, nil
 	}
@@ -619,6 +624,7 @@ func (r *ACLResolver) resolvePoliciesForIdentity(identity structs.ACLIdentity) (
 		}
 		serviceIdentities = append(serviceIdentities, role.ServiceIdentities...)
 		nodeIdentities = append(nodeIdentities, role.NodeIdentityList()...)
+		templateLinks = append(templateLinks, role.PolicyTemplates...)
 	}
 
 	// Now deduplicate any policies or service identities that occur more than once.
@@ -631,17 +637,115 @@ func (r *ACLResolver) resolvePoliciesForIdentity(identity structs.ACLIdentity) (
 	syntheticPolicies = append(syntheticPolicies, r.synthesizePoliciesForNodeIdentities(nodeIdentities, identity.EnterpriseMetadata())...)
 
 	// For the new ACLs policy replication is mandatory for correct operation on servers. Therefore
-	// we only attempt to resolve policies locally
-	policies, err := r.collectPoliciesForIdentity(identity, policyIDs, len(syntheticPolicies))
+	// we only attempt to resolve policies locally. The policy templates are
+	// fetched and cached along with the policies.
+	templateIDs := policyTemplateIDs(templateLinks)
+	fetched, err := r.collectPoliciesForIdentity(identity, mergeStringSlice(policyIDs, templateIDs), len(syntheticPolicies)+len(templateLinks))
 	if err != nil {
 		return nil, err
 	}
 
+	policies := r.renderPolicyTemplates(identity, policyIDs, templateLinks, fetched)
 	policies = append(policies, syntheticPolicies...)
 	filtered := r.filterPoliciesByScope(policies)
 	return filtered, nil
 }
 
+// renderPolicyTemplates returns the policies linked by ID along with the
+// policies rendered from the policy template links, given the policies and
+// templates fetched for them. Templates linked as policies, and links whose
+// variables no longer match the template, are ignored like missing policies.
+func (r *ACLResolver) renderPolicyTemplates(identity structs.ACLIdentity, policyIDs []string, templateLinks []*structs.ACLPolicyTemplateLink, fetched []*structs.ACLPolicy) []*structs.ACLPolicy {
+	if len(templateLinks) == 0 {
+		// Templates only apply through policy template links.
+		return filterPolicyTemplates(fetched)
+	}
+
+	byID := make(map[string]*structs.ACLPolicy, len(fetched))
+	for _, policy := range fetched {
+		byID[policy.ID] = policy
+	}
+
+	policies := make([]*structs.ACLPolicy, 0, len(fetched)+len(templateLinks))
+	for _, policyID := range policyIDs {
+		if policy, ok := byID[policyID]; ok && !policy.IsTemplate() {
+			policies = append(policies, policy)
+		}
+	}
+
+	rendered := make(map[string]struct{}, len(templateLinks))
+	for _, link := range templateLinks {
+		template, ok := byID[link.ID]
+		if !ok {
+			continue
+		}
+		policy, err := template.RenderTemplate(link.Vars)
+		if err != nil {
+			r.logger.Warn("ignoring policy template link of identity",
+				"policy_template", template.Name,
+				"accessorID", identity.ID(),
+				"error", err,
+			)
+			continue
+		}
+		if _, ok := rendered[policy.ID]; !ok {
+			rendered[policy.ID] = struct{}{}
+			policies = append(policies, policy)
+		}
+	}
+	return policies
+}
+
+// policyTemplatesForIdentity returns the IDs of the policy templates linked by
+// the identity or its roles along with the templates that still exist.
+func (r *ACLResolver) policyTemplatesForIdentity(identity structs.ACLIdentity) ([]string, []*structs.ACLPolicy, error) {
+	links := identity.PolicyTemplateList()
+
+	roles, err := r.collectRolesForIdentity(identity, identity.RoleIDs())
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, role := range roles {
+		links = append(links, role.PolicyTemplates...)
+	}
+
+	ids := policyTemplateIDs(links)
+	if len(ids) == 0 {
+		return nil, nil, nil
+	}
+	templates, err := r.collectPoliciesForIdentity(identity, ids, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ids, templates, nil
+}
+
+func filterPolicyTemplates(policies []*structs.ACLPolicy) []*structs.ACLPolicy {
+	for _, policy := range policies {
+		if policy.IsTemplate() {
+			out := make([]*structs.ACLPolicy, 0, len(policies))
+			for _, policy := range policies {
+				if !policy.IsTemplate() {
+					out = append(out, policy)
+				}
+			}
+			return out
+		}
+	}
+	return policies
+}
+
+func policyTemplateIDs(links []*structs.ACLPolicyTemplateLink) []string {
+	if len(links) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(links))
+	for _, link := range links {
+		ids = append(ids, link.ID)
+	}
+	return dedupeStringSlice(ids)
+}
+
 func (r *ACLResolver) synthesizePoliciesForServiceIdentities(serviceIdentities []*structs.ACLServiceIdentity, entMeta *structs.EnterpriseMeta) []*structs.ACLPolicy {
 	if len(serviceIdentities) == 0 {
 		return nil
@@ -1,6 +1,8 @@
 package consul
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 
 	"github.com/hashicorp/consul/agent/consul/authmethod"
@@ -9,7 +11,9 @@ import (
 
 	// register these as a builtin auth method
 	_ "github.com/hashicorp/consul/agent/consul/authmethod/kubeauth"
+	_ "github.com/hashicorp/consul/agent/consul/authmethod/ldapauth"
 	_ "github.com/hashicorp/consul/agent/consul/authmethod/ssoauth"
+	_ "github.com/hashicorp/consul/agent/consul/authmethod/x509auth"
 )
 
 type authMethodValidatorEntry struct {
@@ -36,6 +40,50 @@ func (s *Server) loadAuthMethodValidator(idx uint64, method *structs.ACLAuthMeth
 	return v, nil
 }
 
+// setLoginChallengeKey sets the key signing the login challenges on the
+// validator, creating the key if needed when create is set.
+func (s *Server) setLoginChallengeKey(v challengeValidator, create bool) error {
+	key, err := s.loginChallengeKey(create)
+	if err != nil {
+		return err
+	}
+	v.SetChallengeKey(key)
+	return nil
+}
+
+// loginChallengeKey returns the key signing the login challenges issued by the
+// servers of the datacenter, or nil if it doesn't exist yet. When create is
+// set the key is created if needed, which only the leader may do.
+//
+// The key is stored in the system metadata rather than held in memory so that
+// the challenges remain valid across leader changes.
+func (s *Server) loginChallengeKey(create bool) ([]byte, error) {
+	if create {
+		s.loginChallengeKeyLock.Lock()
+		defer s.loginChallengeKeyLock.Unlock()
+	}
+
+	val, err := s.getSystemMetadata(structs.SystemMetadataLoginChallengeKey)
+	if err != nil {
+		return nil, err
+	}
+	if val != "" {
+		return hex.DecodeString(val)
+	}
+	if !create {
+		return nil, nil
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	if err := s.setSystemMetadataKey(structs.SystemMetadataLoginChallengeKey, hex.EncodeToString(key)); err != nil {
+		return nil, fmt.Errorf("failed to create the login challenge key: %v", err)
+	}
+	return key, nil
+}
+
 type aclBindings struct {
 	roles             []structs.ACLTokenRoleLink
 	serviceIdentities []*structs.ACLServiceIdentity
@@ -2,6 +2,7 @@ package consul
 
 import (
 	"context"
+	"crypto/subtle"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -89,6 +90,10 @@ var ACLEndpointSummaries = []prometheus.SummaryDefinition{
 		Name: []string{"acl", "login"},
 		Help: "",
 	},
+	{
+		Name: []string{"acl", "login", "challenge"},
+		Help: "",
+	},
 	{
 		Name: []string{"acl", "logout"},
 		Help: "",
@@ -97,6 +102,14 @@ var ACLEndpointSummaries = []prometheus.SummaryDefinition{
 		Name: []string{"acl", "logout"},
 		Help: "",
 	},
+	{
+		Name: []string{"acl", "oidc", "auth_url"},
+		Help: "",
+	},
+	{
+		Name: []string{"acl", "oidc", "callback"},
+		Help: "",
+	},
 }
 
 // Regex for matching
@@ -381,6 +394,7 @@ func (a *ACL) TokenClone(args *structs.ACLTokenSetRequest, reply *structs.ACLTok
 			Roles:             token.Roles,
 			ServiceIdentities: token.ServiceIdentities,
 			NodeIdentities:    token.NodeIdentities,
+			PolicyTemplates:   token.PolicyTemplates,
 			Local:             token.Local,
 			Description:       token.Description,
 			ExpirationTime:    token.ExpirationTime,
@@ -605,6 +619,9 @@ func (a *ACL) tokenSetInternal(args *structs.ACLTokenSetRequest, reply *structs.
 			if policy == nil {
 				return fmt.Errorf("No such ACL policy with name %q", link.Name)
 			}
+			if policy.IsTemplate() {
+				return fmt.Errorf("ACL policy %q is a template and must be linked as a policy template", link.Name)
+			}
 			link.ID = policy.ID
 		} else {
 			_, policy, err := state.ACLPolicyGetByID(nil, link.ID, &token.EnterpriseMeta)
@@ -615,6 +632,9 @@ func (a *ACL) tokenSetInternal(args *structs.ACLTokenSetRequest, reply *structs.
 			if policy == nil {
 				return fmt.Errorf("No such ACL policy with ID %q", link.ID)
 			}
+			if policy.IsTemplate() {
+				return fmt.Errorf("ACL policy %q is a template and must be linked as a policy template", policy.Name)
+			}
 		}
 
 		// Do not store the policy name within raft/memdb as the policy could be renamed in the future.
@@ -664,6 +684,11 @@ func (a *ACL) tokenSetInternal(args *structs.ACLTokenSetRequest, reply *structs.
 	}
 	token.Roles = roles
 
+	token.PolicyTemplates, err = validatePolicyTemplateLinks(state, token.PolicyTemplates, &token.EnterpriseMeta)
+	if err != nil {
+		return err
+	}
+
 	for _, svcid := range token.ServiceIdentities {
 		if svcid.ServiceName == "" {
 			return fmt.Errorf("Service identity is missing the service name field on this token")
@@ -782,6 +807,65 @@ func computeBindingRuleBindName(bindType, bindName string, projectedVars map[str
 	return bindName, valid, nil
 }
 
+// validatePolicyTemplateLinks checks that the policy templates of the links
+// exist and that the links set valid values for their variables. It returns
+// the links deduplicated, with the template names converted to IDs.
+func validatePolicyTemplateLinks(state *state.Store, links []*structs.ACLPolicyTemplateLink, entMeta *structs.EnterpriseMeta) ([]*structs.ACLPolicyTemplateLink, error) {
+	var out []*structs.ACLPolicyTemplateLink
+	seen := make(map[string]struct{})
+
+	for _, link := range links {
+		if link == nil {
+			continue
+		}
+
+		var template *structs.ACLPolicy
+		var err error
+		if link.ID == "" {
+			if link.Template == "" {
+				return nil, fmt.Errorf("Policy template link is missing the template name")
+			}
+			_, template, err = state.ACLPolicyGetByName(nil, link.Template, entMeta)
+			if err != nil {
+				return nil, fmt.Errorf("Error looking up policy template for name %q: %v", link.Template, err)
+			}
+			if template == nil {
+				return nil, fmt.Errorf("No such ACL policy template with name %q", link.Template)
+			}
+		} else {
+			_, template, err = state.ACLPolicyGetByID(nil, link.ID, entMeta)
+			if err != nil {
+				return nil, fmt.Errorf("Error looking up policy template for id %q: %v", link.ID, err)
+			}
+			if template == nil {
+				return nil, fmt.Errorf("No such ACL policy template with ID %q", link.ID)
+			}
+		}
+		if !template.IsTemplate() {
+			return nil, fmt.Errorf("ACL policy %q is not a template, it declares no variables", template.Name)
+		}
+		if err := template.ValidateTemplateVars(link.Vars); err != nil {
+			return nil, err
+		}
+
+		// Do not store the template name within raft/memdb as the template could be renamed in the future.
+		link = link.Clone()
+		link.ID = template.ID
+		link.Template = ""
+
+		// dedup links by id and variables
+		encoded, err := json.Marshal(link)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := seen[string(encoded)]; !ok {
+			out = append(out, link)
+			seen[string(encoded)] = struct{}{}
+		}
+	}
+	return out, nil
+}
+
 // isValidServiceIdentityName returns true if the provided name can be used as
 // an ACLServiceIdentity ServiceName. This is more restrictive than standard
 // catalog registration, which basically takes the view that "everything is
@@ -885,6 +969,46 @@ func (a *ACL) TokenDelete(args *structs.ACLTokenDeleteRequest, reply *string) er
 	return nil
 }
 
+// TokenUsageUpdate is used by servers to report to the leader when tokens were
+// used. The leader batches this usage along with its own before committing it.
+//
+// Servers make the request with their replication token, which requires acl
+// write permission. Unknown and expired tokens are ignored.
+func (a *ACL) TokenUsageUpdate(args *structs.ACLTokenUsageUpdateRequest, reply *struct{}) error {
+	if err := a.aclPreCheck(); err != nil {
+		return err
+	}
+
+	if done, err := a.srv.ForwardRPC("ACL.TokenUsageUpdate", args, reply); done {
+		return err
+	}
+
+	if authz, err := a.srv.ResolveToken(args.Token); err != nil {
+		return err
+	} else if authz.ACLWrite(nil) != acl.Allow {
+		return acl.ErrPermissionDenied
+	}
+
+	state := a.srv.fsm.State()
+	now := time.Now()
+	for accessorID, lastUsed := range args.Usage {
+		_, token, err := state.ACLTokenGetByAccessor(nil, accessorID, nil)
+		if err != nil {
+			return err
+		}
+		if token == nil || token.IsExpired(now) {
+			continue
+		}
+
+		if lastUsed.After(now) {
+			lastUsed = now
+		}
+		a.srv.aclTokenUsage.record(accessorID, lastUsed)
+	}
+
+	return nil
+}
+
 func (a *ACL) TokenList(args *structs.ACLTokenListRequest, reply *structs.ACLTokenListResponse) error {
 	if err := a.aclPreCheck(); err != nil {
 		return err
@@ -1171,11 +1295,28 @@ func (a *ACL) PolicySet(args *structs.ACLPolicySetRequest, reply *structs.ACLPol
 			if policy.Rules != idMatch.Rules {
 				return fmt.Errorf("Changing the Rules for the builtin global-management policy is not permitted")
 			}
+
+			if policy.IsTemplate() {
+				return fmt.Errorf("Changing the Variables of the builtin global-management policy is not permitted")
+			}
+		}
+
+		// Policies and policy templates are linked differently, so the links
+		// of the tokens and roles would silently stop granting the policy.
+		if policy.IsTemplate() != idMatch.IsTemplate() {
+			return fmt.Errorf("Invalid Policy: cannot add or remove all the Variables of an existing policy, create a new policy instead")
 		}
 	}
 
 	// validate the rules
-	_, err = acl.NewPolicyFromSource(policy.Rules, policy.Syntax, a.srv.aclConfig, policy.EnterprisePolicyMeta())
+	if policy.IsTemplate() {
+		if err := policy.ValidateTemplate(); err != nil {
+			return fmt.Errorf("Invalid Policy Template: %v", err)
+		}
+		err = policy.ValidateTemplateRules(a.srv.aclConfig)
+	} else {
+		_, err = acl.NewPolicyFromSource(policy.Rules, policy.Syntax, a.srv.aclConfig, policy.EnterprisePolicyMeta())
+	}
 	if err != nil {
 		return err
 	}
@@ -1345,6 +1486,19 @@ func (a *ACL) PolicyResolve(args *structs.ACLPolicyBatchGetRequest, reply *struc
 		idMap[policy.ID] = policy
 	}
 
+	// The policy templates are resolved like the policies and rendered by
+	// the caller with the variables of the links.
+	templateIDs, templates, err := a.srv.acls.policyTemplatesForIdentity(identity)
+	if err != nil {
+		return err
+	}
+	for _, templateID := range templateIDs {
+		idMap[templateID] = nil
+	}
+	for _, template := range templates {
+		idMap[template.ID] = template
+	}
+
 	for _, policyID := range args.PolicyIDs {
 		if policy, ok := idMap[policyID]; ok {
 			// only add non-deleted policies
@@ -1559,6 +1713,9 @@ func (a *ACL) RoleSet(args *structs.ACLRoleSetRequest, reply *structs.ACLRole) e
 			if policy == nil {
 				return fmt.Errorf("No such ACL policy with name %q", link.Name)
 			}
+			if policy.IsTemplate() {
+				return fmt.Errorf("ACL policy %q is a template and must be linked as a policy template", link.Name)
+			}
 			link.ID = policy.ID
 		}
 
@@ -1573,6 +1730,12 @@ func (a *ACL) RoleSet(args *structs.ACLRoleSetRequest, reply *structs.ACLRole) e
 	}
 	role.Policies = policies
 
+	templateLinks, err := validatePolicyTemplateLinks(state, role.PolicyTemplates, &role.EnterpriseMeta)
+	if err != nil {
+		return err
+	}
+	role.PolicyTemplates = templateLinks
+
 	for _, svcid := range role.ServiceIdentities {
 		if svcid.ServiceName == "" {
 			return fmt.Errorf("Service identity is missing the service name field on this role")
@@ -2325,6 +2488,11 @@ func (a *ACL) Login(args *structs.ACLLoginRequest, reply *structs.ACLToken) erro
 	if err != nil {
 		return err
 	}
+	if v, ok := validator.(challengeValidator); ok {
+		if err := a.srv.setLoginChallengeKey(v, false); err != nil {
+			return err
+		}
+	}
 
 	// 2. Send args.Data.BearerToken to method validator and get back a fields map
 	verifiedIdentity, err := validator.ValidateLogin(context.Background(), auth.BearerToken)
@@ -2347,6 +2515,247 @@ func (a *ACL) Login(args *structs.ACLLoginRequest, reply *structs.ACLToken) erro
 	)
 }
 
+// challengeValidator is implemented by the validators of auth methods that
+// require the client to sign a challenge issued by the server to log in.
+type challengeValidator interface {
+	authmethod.Validator
+	Challenge() (string, error)
+	SetChallengeKey(key []byte)
+}
+
+// LoginChallenge returns a challenge the client must sign to log in with an
+// auth method requiring one, such as the x509 auth method.
+//
+// The request is forwarded to the leader, which creates the key signing the
+// challenges the first time one is requested. The challenge isn't stored, any
+// server holding the key verifies it when the client logs in.
+func (a *ACL) LoginChallenge(args *structs.ACLLoginChallengeRequest, reply *structs.ACLLoginChallengeResponse) error {
+	if err := a.aclPreCheck(); err != nil {
+		return err
+	}
+
+	if !a.srv.LocalTokensEnabled() {
+		return errAuthMethodsRequireTokenReplication
+	}
+
+	if args.Auth == nil {
+		return fmt.Errorf("Invalid login challenge request: Missing auth parameters")
+	}
+
+	if err := a.srv.validateEnterpriseRequest(&args.Auth.EnterpriseMeta, true); err != nil {
+		return err
+	}
+
+	if args.Token != "" { // This shouldn't happen.
+		return errors.New("do not provide a token when logging in")
+	}
+
+	if done, err := a.srv.ForwardRPC("ACL.LoginChallenge", args, reply); done {
+		return err
+	}
+
+	defer metrics.MeasureSince([]string{"acl", "login", "challenge"}, time.Now())
+
+	auth := args.Auth
+
+	idx, method, err := a.srv.fsm.State().ACLAuthMethodGetByName(nil, auth.AuthMethod, &auth.EnterpriseMeta)
+	if err != nil {
+		return err
+	} else if method == nil {
+		return fmt.Errorf("%w: auth method %q not found", acl.ErrNotFound, auth.AuthMethod)
+	}
+
+	if err := a.enterpriseAuthMethodTypeValidation(method.Type); err != nil {
+		return err
+	}
+
+	validator, err := a.srv.loadAuthMethodValidator(idx, method)
+	if err != nil {
+		return err
+	}
+	v, ok := validator.(challengeValidator)
+	if !ok {
+		return fmt.Errorf("auth method %q of type %q does not use login challenges", method.Name, method.Type)
+	}
+	if err := a.srv.setLoginChallengeKey(v, true); err != nil {
+		return err
+	}
+
+	challenge, err := v.Challenge()
+	if err != nil {
+		return err
+	}
+
+	reply.Challenge = challenge
+	return nil
+}
+
+// oidcValidator is implemented by the validators of auth methods that log in
+// through the OIDC authorization code flow.
+type oidcValidator interface {
+	authmethod.Validator
+	GetAuthCodeURL(ctx context.Context, redirectURI string, payload interface{}) (string, error)
+	ValidateAuthCode(ctx context.Context, state, code string) (*authmethod.Identity, interface{}, error)
+}
+
+// oidcLoginState is kept by the validator between the OIDCAuthURL and
+// OIDCCallback requests of an OIDC login.
+type oidcLoginState struct {
+	clientNonce string
+	meta        map[string]string
+}
+
+// loadOIDCValidator returns the validator of the named auth method, which must
+// be of type "oidc".
+func (a *ACL) loadOIDCValidator(name string, entMeta *structs.EnterpriseMeta) (*structs.ACLAuthMethod, oidcValidator, error) {
+	idx, method, err := a.srv.fsm.State().ACLAuthMethodGetByName(nil, name, entMeta)
+	if err != nil {
+		return nil, nil, err
+	} else if method == nil {
+		return nil, nil, fmt.Errorf("%w: auth method %q not found", acl.ErrNotFound, name)
+	}
+
+	if err := a.enterpriseAuthMethodTypeValidation(method.Type); err != nil {
+		return nil, nil, err
+	}
+	if method.Type != "oidc" {
+		return nil, nil, fmt.Errorf("auth method %q is of type %q and does not support OIDC login", name, method.Type)
+	}
+
+	validator, err := a.srv.loadAuthMethodValidator(idx, method)
+	if err != nil {
+		return nil, nil, err
+	}
+	v, ok := validator.(oidcValidator)
+	if !ok {
+		return nil, nil, fmt.Errorf("auth method %q does not support OIDC login", name)
+	}
+	return method, v, nil
+}
+
+// OIDCAuthURL starts an OIDC login and returns the URL of the provider the
+// user must visit to log in.
+//
+// The request is forwarded to the leader, which keeps the state of the login
+// until OIDCCallback completes it.
+func (a *ACL) OIDCAuthURL(args *structs.ACLOIDCAuthURLRequest, reply *structs.ACLOIDCAuthURLResponse) error {
+	if err := a.aclPreCheck(); err != nil {
+		return err
+	}
+
+	if !a.srv.LocalTokensEnabled() {
+		return errAuthMethodsRequireTokenReplication
+	}
+
+	if args.Auth == nil {
+		return fmt.Errorf("Invalid OIDC auth URL request: Missing auth parameters")
+	}
+	if args.Auth.RedirectURI == "" {
+		return fmt.Errorf("Invalid OIDC auth URL request: Missing redirect URI")
+	}
+	if args.Auth.ClientNonce == "" {
+		return fmt.Errorf("Invalid OIDC auth URL request: Missing client nonce")
+	}
+
+	if err := a.srv.validateEnterpriseRequest(&args.Auth.EnterpriseMeta, true); err != nil {
+		return err
+	}
+
+	if args.Token != "" { // This shouldn't happen.
+		return errors.New("do not provide a token when logging in")
+	}
+
+	if done, err := a.srv.ForwardRPC("ACL.OIDCAuthURL", args, reply); done {
+		return err
+	}
+
+	defer metrics.MeasureSince([]string{"acl", "oidc", "auth_url"}, time.Now())
+
+	auth := args.Auth
+
+	_, validator, err := a.loadOIDCValidator(auth.AuthMethod, &auth.EnterpriseMeta)
+	if err != nil {
+		return err
+	}
+
+	authURL, err := validator.GetAuthCodeURL(context.Background(), auth.RedirectURI, &oidcLoginState{
+		clientNonce: auth.ClientNonce,
+		meta:        auth.Meta,
+	})
+	if err != nil {
+		return err
+	}
+
+	reply.AuthURL = authURL
+	return nil
+}
+
+// OIDCCallback completes an OIDC login started by OIDCAuthURL by exchanging
+// the authorization code returned by the provider for a new token.
+func (a *ACL) OIDCCallback(args *structs.ACLOIDCCallbackRequest, reply *structs.ACLToken) error {
+	if err := a.aclPreCheck(); err != nil {
+		return err
+	}
+
+	if !a.srv.LocalTokensEnabled() {
+		return errAuthMethodsRequireTokenReplication
+	}
+
+	if args.Auth == nil {
+		return fmt.Errorf("Invalid OIDC callback request: Missing auth parameters")
+	}
+	if args.Auth.ClientNonce == "" {
+		return fmt.Errorf("Invalid OIDC callback request: Missing client nonce")
+	}
+
+	if err := a.srv.validateEnterpriseRequest(&args.Auth.EnterpriseMeta, true); err != nil {
+		return err
+	}
+
+	if args.Token != "" { // This shouldn't happen.
+		return errors.New("do not provide a token when logging in")
+	}
+
+	if done, err := a.srv.ForwardRPC("ACL.OIDCCallback", args, reply); done {
+		return err
+	}
+
+	defer metrics.MeasureSince([]string{"acl", "oidc", "callback"}, time.Now())
+
+	auth := args.Auth
+
+	method, validator, err := a.loadOIDCValidator(auth.AuthMethod, &auth.EnterpriseMeta)
+	if err != nil {
+		return err
+	}
+
+	verifiedIdentity, payload, err := validator.ValidateAuthCode(context.Background(), auth.State, auth.Code)
+	if err != nil {
+		return err
+	}
+
+	// Only the client that started the login may complete it, which prevents
+	// an intercepted authorization code from being exchanged by someone else.
+	loginState, ok := payload.(*oidcLoginState)
+	if !ok || subtle.ConstantTimeCompare([]byte(loginState.clientNonce), []byte(auth.ClientNonce)) != 1 {
+		return acl.ErrPermissionDenied
+	}
+
+	return a.tokenSetFromAuthMethod(
+		method,
+		&auth.EnterpriseMeta,
+		"token created via OIDC login",
+		loginState.meta,
+		validator,
+		verifiedIdentity,
+		&structs.ACLTokenSetRequest{
+			Datacenter:   args.Datacenter,
+			WriteRequest: args.WriteRequest,
+		},
+		reply,
+	)
+}
+
 func (a *ACL) tokenSetFromAuthMethod(
 	method *structs.ACLAuthMethod,
 	entMeta *structs.EnterpriseMeta,
@@ -2510,3 +2919,48 @@ func (a *ACL) Authorize(args *structs.RemoteACLAuthorizationRequest, reply *[]st
 	*reply = responses
 	return nil
 }
+
+// Explain performs authorizations like Authorize does but also reports the
+// rule, policies, roles and identities that rendered each decision.
+func (a *ACL) Explain(args *structs.ACLExplainRequest, reply *[]structs.ACLAuthorizationExplanation) error {
+	if err := a.aclPreCheck(); err != nil {
+		return err
+	}
+
+	if err := a.srv.validateEnterpriseRequest(&args.EnterpriseMeta, false); err != nil {
+		return err
+	}
+
+	if done, err := a.srv.ForwardRPC("ACL.Explain", args, reply); done {
+		return err
+	}
+
+	token := args.Token
+	if args.AccessorID != "" {
+		// Explaining another token reveals its rules, which requires the same
+		// privileges as reading its policies.
+		var authzContext acl.AuthorizerContext
+		authz, err := a.srv.ResolveTokenAndDefaultMeta(args.Token, &args.EnterpriseMeta, &authzContext)
+		if err != nil {
+			return err
+		} else if authz.ACLRead(&authzContext) != acl.Allow {
+			return acl.ErrPermissionDenied
+		}
+
+		_, explained, err := a.srv.fsm.State().ACLTokenGetByAccessor(nil, args.AccessorID, &args.EnterpriseMeta)
+		if err != nil {
+			return err
+		} else if explained == nil || explained.IsExpired(time.Now()) {
+			return fmt.Errorf("%w: token %q not found", acl.ErrNotFound, args.AccessorID)
+		}
+		token = explained.SecretID
+	}
+
+	explanations, err := a.srv.acls.ExplainToken(token, args.Requests)
+	if err != nil {
+		return err
+	}
+
+	*reply = explanations
+	return nil
+}
@@ -1,6 +1,8 @@
 package consul
 
 import (
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"fmt"
 	"io/ioutil"
 	"net/rpc"
@@ -18,6 +20,7 @@ import (
 	"github.com/hashicorp/consul/acl"
 	"github.com/hashicorp/consul/agent/consul/authmethod/kubeauth"
 	"github.com/hashicorp/consul/agent/consul/authmethod/testauth"
+	"github.com/hashicorp/consul/agent/consul/authmethod/x509auth"
 	"github.com/hashicorp/consul/agent/structs"
 	"github.com/hashicorp/consul/internal/go-sso/oidcauth/oidcauthtest"
 	"github.com/hashicorp/consul/sdk/testutil"
@@ -2144,6 +2147,208 @@ func TestACLEndpoint_PolicyResolve(t *testing.T) {
 	require.ElementsMatch(t, gatherIDs(t, resp.Policies), policies)
 }
 
+func TestACLEndpoint_PolicyTemplates(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	_, srv, codec := testACLServerWithConfig(t, nil, false)
+	waitForLeaderEstablishment(t, srv)
+
+	aclEp := ACL{srv: srv}
+
+	var tmpl *structs.ACLPolicy
+
+	t.Run("Create the template", func(t *testing.T) {
+		req := structs.ACLPolicySetRequest{
+			Datacenter: "dc1",
+			Policy: structs.ACLPolicy{
+				Name:  "team",
+				Rules: `service_prefix "${team}-" { policy = "write" }`,
+				Variables: []*structs.ACLPolicyVariable{
+					{Name: "team", Type: structs.ACLPolicyVariableTypeString},
+				},
+			},
+			WriteRequest: structs.WriteRequest{Token: TestDefaultInitialManagementToken},
+		}
+		resp := structs.ACLPolicy{}
+		require.NoError(t, aclEp.PolicySet(&req, &resp))
+
+		policyResp, err := retrieveTestPolicy(codec, TestDefaultInitialManagementToken, "dc1", resp.ID)
+		require.NoError(t, err)
+		tmpl = policyResp.Policy
+		require.True(t, tmpl.IsTemplate())
+	})
+
+	t.Run("Reject an undeclared variable", func(t *testing.T) {
+		req := structs.ACLPolicySetRequest{
+			Datacenter: "dc1",
+			Policy: structs.ACLPolicy{
+				Name:  "bad-template",
+				Rules: `service_prefix "${other}-" { policy = "write" }`,
+				Variables: []*structs.ACLPolicyVariable{
+					{Name: "team", Type: structs.ACLPolicyVariableTypeString},
+				},
+			},
+			WriteRequest: structs.WriteRequest{Token: TestDefaultInitialManagementToken},
+		}
+		resp := structs.ACLPolicy{}
+		err := aclEp.PolicySet(&req, &resp)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Invalid Policy Template")
+	})
+
+	t.Run("Reject turning a policy into a template", func(t *testing.T) {
+		req := structs.ACLPolicySetRequest{
+			Datacenter: "dc1",
+			Policy: structs.ACLPolicy{
+				Name:  "plain",
+				Rules: `service_prefix "payments-" { policy = "write" }`,
+			},
+			WriteRequest: structs.WriteRequest{Token: TestDefaultInitialManagementToken},
+		}
+		plain := structs.ACLPolicy{}
+		require.NoError(t, aclEp.PolicySet(&req, &plain))
+
+		req.Policy = structs.ACLPolicy{
+			ID:    plain.ID,
+			Name:  "plain",
+			Rules: `service_prefix "${team}-" { policy = "write" }`,
+			Variables: []*structs.ACLPolicyVariable{
+				{Name: "team", Type: structs.ACLPolicyVariableTypeString},
+			},
+		}
+		resp := structs.ACLPolicy{}
+		err := aclEp.PolicySet(&req, &resp)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "cannot add or remove all the Variables of an existing policy")
+	})
+
+	t.Run("Reject turning a template into a policy", func(t *testing.T) {
+		req := structs.ACLPolicySetRequest{
+			Datacenter: "dc1",
+			Policy: structs.ACLPolicy{
+				ID:    tmpl.ID,
+				Name:  "team",
+				Rules: `service_prefix "payments-" { policy = "write" }`,
+			},
+			WriteRequest: structs.WriteRequest{Token: TestDefaultInitialManagementToken},
+		}
+		resp := structs.ACLPolicy{}
+		err := aclEp.PolicySet(&req, &resp)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "cannot add or remove all the Variables of an existing policy")
+	})
+
+	t.Run("Reject linking the template as a policy", func(t *testing.T) {
+		req := structs.ACLTokenSetRequest{
+			Datacenter: "dc1",
+			ACLToken: structs.ACLToken{
+				Policies: []structs.ACLTokenPolicyLink{{ID: tmpl.ID}},
+			},
+			WriteRequest: structs.WriteRequest{Token: TestDefaultInitialManagementToken},
+		}
+		resp := structs.ACLToken{}
+		err := aclEp.TokenSet(&req, &resp)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "must be linked as a policy template")
+	})
+
+	t.Run("Reject invalid variables", func(t *testing.T) {
+		req := structs.ACLTokenSetRequest{
+			Datacenter: "dc1",
+			ACLToken: structs.ACLToken{
+				PolicyTemplates: []*structs.ACLPolicyTemplateLink{
+					{Template: "team", Vars: map[string]string{"team": `x" { policy = "write" }`}},
+				},
+			},
+			WriteRequest: structs.WriteRequest{Token: TestDefaultInitialManagementToken},
+		}
+		resp := structs.ACLToken{}
+		require.Error(t, aclEp.TokenSet(&req, &resp))
+
+		req.ACLToken = structs.ACLToken{
+			PolicyTemplates: []*structs.ACLPolicyTemplateLink{
+				{Template: "team", Vars: map[string]string{"team": ""}},
+			},
+		}
+		err := aclEp.TokenSet(&req, &resp)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "cannot be empty")
+	})
+
+	t.Run("Token with a template link", func(t *testing.T) {
+		req := structs.ACLTokenSetRequest{
+			Datacenter: "dc1",
+			ACLToken: structs.ACLToken{
+				PolicyTemplates: []*structs.ACLPolicyTemplateLink{
+					{Template: "team", Vars: map[string]string{"team": "payments"}},
+					{ID: tmpl.ID, Vars: map[string]string{"team": "payments"}},
+				},
+			},
+			WriteRequest: structs.WriteRequest{Token: TestDefaultInitialManagementToken},
+		}
+		resp := structs.ACLToken{}
+		require.NoError(t, aclEp.TokenSet(&req, &resp))
+
+		tokenResp, err := retrieveTestToken(codec, TestDefaultInitialManagementToken, "dc1", resp.AccessorID)
+		require.NoError(t, err)
+		token := tokenResp.Token
+		require.Len(t, token.PolicyTemplates, 1)
+		require.Equal(t, tmpl.ID, token.PolicyTemplates[0].ID)
+		require.Equal(t, "team", token.PolicyTemplates[0].Template)
+
+		_, authz, err := srv.acls.ResolveTokenToIdentityAndAuthorizer(token.SecretID)
+		require.NoError(t, err)
+		require.Equal(t, acl.Allow, authz.ServiceWrite("payments-api", nil))
+		require.Equal(t, acl.Deny, authz.ServiceWrite("billing-api", nil))
+
+		// Clients resolve the templates linked by the token.
+		policyReq := structs.ACLPolicyBatchGetRequest{
+			Datacenter:   "dc1",
+			PolicyIDs:    []string{tmpl.ID},
+			QueryOptions: structs.QueryOptions{Token: token.SecretID},
+		}
+		policyResp := structs.ACLPolicyBatchResponse{}
+		require.NoError(t, aclEp.PolicyResolve(&policyReq, &policyResp))
+		require.ElementsMatch(t, []string{tmpl.ID}, gatherIDs(t, policyResp.Policies))
+	})
+
+	t.Run("Role with a template link", func(t *testing.T) {
+		roleReq := structs.ACLRoleSetRequest{
+			Datacenter: "dc1",
+			Role: structs.ACLRole{
+				Name: "billing",
+				PolicyTemplates: []*structs.ACLPolicyTemplateLink{
+					{Template: "team", Vars: map[string]string{"team": "billing"}},
+				},
+			},
+			WriteRequest: structs.WriteRequest{Token: TestDefaultInitialManagementToken},
+		}
+		role := structs.ACLRole{}
+		require.NoError(t, aclEp.RoleSet(&roleReq, &role))
+		require.Len(t, role.PolicyTemplates, 1)
+		require.Equal(t, tmpl.ID, role.PolicyTemplates[0].ID)
+
+		req := structs.ACLTokenSetRequest{
+			Datacenter: "dc1",
+			ACLToken: structs.ACLToken{
+				Roles: []structs.ACLTokenRoleLink{{ID: role.ID}},
+			},
+			WriteRequest: structs.WriteRequest{Token: TestDefaultInitialManagementToken},
+		}
+		token := structs.ACLToken{}
+		require.NoError(t, aclEp.TokenSet(&req, &token))
+
+		_, authz, err := srv.acls.ResolveTokenToIdentityAndAuthorizer(token.SecretID)
+		require.NoError(t, err)
+		require.Equal(t, acl.Allow, authz.ServiceWrite("billing-api", nil))
+		require.Equal(t, acl.Deny, authz.ServiceWrite("payments-api", nil))
+	})
+}
+
 func TestACLEndpoint_RoleRead(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -4854,6 +5059,89 @@ func TestACLEndpoint_Login_k8s(t *testing.T) {
 	})
 }
 
+func TestACLEndpoint_Login_x509(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	_, srv, codec := testACLServerWithConfig(t, nil, false)
+	waitForLeaderEstablishment(t, srv)
+
+	acl := ACL{srv: srv}
+
+	ca := x509auth.NewTestCA(t)
+	leaf := ca.Issue(t, &x509.Certificate{
+		Subject: pkix.Name{CommonName: "web"},
+	})
+
+	methodReq := structs.ACLAuthMethodSetRequest{
+		Datacenter: "dc1",
+		AuthMethod: structs.ACLAuthMethod{
+			Name:   "test-x509",
+			Type:   "x509",
+			Config: map[string]interface{}{"CACerts": []string{ca.CertPEM}},
+		},
+		WriteRequest: structs.WriteRequest{Token: TestDefaultInitialManagementToken},
+	}
+	var method structs.ACLAuthMethod
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.AuthMethodSet", &methodReq, &method))
+
+	_, err := upsertTestBindingRule(
+		codec, TestDefaultInitialManagementToken, "dc1", method.Name,
+		"",
+		structs.BindingRuleBindTypeService,
+		"${subject.common_name}",
+	)
+	require.NoError(t, err)
+
+	challenge := func(t *testing.T) string {
+		req := structs.ACLLoginChallengeRequest{
+			Auth:       &structs.ACLLoginChallengeParams{AuthMethod: method.Name},
+			Datacenter: "dc1",
+		}
+		var resp structs.ACLLoginChallengeResponse
+		require.NoError(t, acl.LoginChallenge(&req, &resp))
+		return resp.Challenge
+	}
+	login := func(challenge string) error {
+		req := structs.ACLLoginRequest{
+			Auth: &structs.ACLLoginParams{
+				AuthMethod:  method.Name,
+				BearerToken: leaf.LoginToken(t, challenge),
+			},
+			Datacenter: "dc1",
+		}
+		var resp structs.ACLToken
+		return acl.Login(&req, &resp)
+	}
+
+	t.Run("challenges are not lost with the validators", func(t *testing.T) {
+		c := challenge(t)
+
+		key, err := srv.getSystemMetadata(structs.SystemMetadataLoginChallengeKey)
+		require.NoError(t, err)
+		require.NotEmpty(t, key)
+
+		srv.aclAuthMethodValidators.Purge()
+		require.NoError(t, login(c))
+
+		// The challenge can only be used once.
+		testutil.RequireErrorContains(t, login(c), "invalid or expired login challenge")
+
+		// The key is created once.
+		challenge(t)
+		again, err := srv.getSystemMetadata(structs.SystemMetadataLoginChallengeKey)
+		require.NoError(t, err)
+		require.Equal(t, key, again)
+	})
+
+	t.Run("forged challenge", func(t *testing.T) {
+		testutil.RequireErrorContains(t, login("forged"), "invalid or expired login challenge")
+	})
+}
+
 func TestACLEndpoint_Login_jwt(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -5238,6 +5526,131 @@ func TestValidateBindingRuleBindName(t *testing.T) {
 	}
 }
 
+func TestACLEndpoint_Explain(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	_, srv, codec := testACLServerWithConfig(t, nil, false)
+	waitForLeaderEstablishment(t, srv)
+
+	kvPolicy, err := upsertTestPolicyWithRules(codec, TestDefaultInitialManagementToken, "dc1", `
+		key_prefix "app/" {
+			policy = "write"
+		}
+	`)
+	require.NoError(t, err)
+
+	denyPolicy, err := upsertTestPolicyWithRules(codec, TestDefaultInitialManagementToken, "dc1", `
+		key "app/secret" {
+			policy = "deny"
+		}
+	`)
+	require.NoError(t, err)
+
+	role, err := upsertTestCustomizedRole(codec, TestDefaultInitialManagementToken, "dc1", func(role *structs.ACLRole) {
+		role.Policies = []structs.ACLRolePolicyLink{{ID: denyPolicy.ID}}
+		role.ServiceIdentities = []*structs.ACLServiceIdentity{{ServiceName: "web"}}
+	})
+	require.NoError(t, err)
+
+	token, err := upsertTestToken(codec, TestDefaultInitialManagementToken, "dc1", func(token *structs.ACLToken) {
+		token.Policies = []structs.ACLTokenPolicyLink{{ID: kvPolicy.ID}}
+		token.Roles = []structs.ACLTokenRoleLink{{ID: role.ID}}
+	})
+	require.NoError(t, err)
+
+	requests := []structs.ACLAuthorizationRequest{
+		{Resource: "key", Segment: "app/config", Access: "write"},
+		{Resource: "key", Segment: "app/secret", Access: "read"},
+		{Resource: "service", Segment: "web", Access: "write"},
+		{Resource: "operator", Access: "read"},
+	}
+
+	expect := []structs.ACLAuthorizationExplanation{
+		{
+			ACLAuthorizationRequest: requests[0],
+			Allow:                   true,
+			Rule:                    &acl.ExplainedRule{Resource: "key", Segment: "app/", Prefix: true, Access: "write"},
+			Sources: []structs.ACLExplainSource{
+				{Type: structs.ACLExplainSourcePolicy, ID: kvPolicy.ID, Name: kvPolicy.Name},
+			},
+		},
+		{
+			ACLAuthorizationRequest: requests[1],
+			Allow:                   false,
+			Rule:                    &acl.ExplainedRule{Resource: "key", Segment: "app/secret", Access: "deny"},
+			Sources: []structs.ACLExplainSource{
+				{Type: structs.ACLExplainSourcePolicy, ID: denyPolicy.ID, Name: denyPolicy.Name, RoleID: role.ID, RoleName: role.Name},
+			},
+		},
+		{
+			ACLAuthorizationRequest: requests[2],
+			Allow:                   true,
+			Rule:                    &acl.ExplainedRule{Resource: "service", Segment: "web", Access: "write"},
+			Sources: []structs.ACLExplainSource{
+				{Type: structs.ACLExplainSourceServiceIdentity, Name: "web", RoleID: role.ID, RoleName: role.Name},
+			},
+		},
+		{
+			ACLAuthorizationRequest: requests[3],
+			Allow:                   false,
+			DefaultPolicy:           "deny",
+		},
+	}
+
+	aclEp := ACL{srv: srv}
+
+	t.Run("token of the request", func(t *testing.T) {
+		req := structs.ACLExplainRequest{
+			Datacenter:   "dc1",
+			Requests:     requests,
+			QueryOptions: structs.QueryOptions{Token: token.SecretID},
+		}
+		var resp []structs.ACLAuthorizationExplanation
+		require.NoError(t, aclEp.Explain(&req, &resp))
+		require.Equal(t, expect, resp)
+	})
+
+	t.Run("other token", func(t *testing.T) {
+		req := structs.ACLExplainRequest{
+			Datacenter:   "dc1",
+			AccessorID:   token.AccessorID,
+			Requests:     requests,
+			QueryOptions: structs.QueryOptions{Token: TestDefaultInitialManagementToken},
+		}
+		var resp []structs.ACLAuthorizationExplanation
+		require.NoError(t, aclEp.Explain(&req, &resp))
+		require.Equal(t, expect, resp)
+	})
+
+	t.Run("other token requires acl read", func(t *testing.T) {
+		req := structs.ACLExplainRequest{
+			Datacenter:   "dc1",
+			AccessorID:   token.AccessorID,
+			Requests:     requests,
+			QueryOptions: structs.QueryOptions{Token: token.SecretID},
+		}
+		var resp []structs.ACLAuthorizationExplanation
+		err := aclEp.Explain(&req, &resp)
+		require.True(t, acl.IsErrPermissionDenied(err), "unexpected error: %v", err)
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		req := structs.ACLExplainRequest{
+			Datacenter:   "dc1",
+			AccessorID:   "e3d1b2a5-7a0b-4cf6-a1b2-3d4e5f6a7b8c",
+			Requests:     requests,
+			QueryOptions: structs.QueryOptions{Token: TestDefaultInitialManagementToken},
+		}
+		var resp []structs.ACLAuthorizationExplanation
+		err := aclEp.Explain(&req, &resp)
+		require.True(t, acl.IsErrNotFound(err), "unexpected error: %v", err)
+	})
+}
+
 // upsertTestToken creates a token for testing purposes
 func upsertTestTokenInEntMeta(codec rpc.ClientCodec, initialManagementToken string, datacenter string,
 	tokenModificationFn func(token *structs.ACLToken), entMeta *structs.EnterpriseMeta) (*structs.ACLToken, error) {
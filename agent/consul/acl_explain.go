@@ -0,0 +1,162 @@
+package consul
+
+import (
+	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+// explainSource is a policy of a token along with where it comes from, used to
+// attribute the rule rendering an authorization decision.
+type explainSource struct {
+	source structs.ACLExplainSource
+	policy *structs.ACLPolicy
+}
+
+// ExplainToken performs the authorizations like ACL.Authorize does and also
+// reports, for each of them, the rule that rendered the decision and the
+// policies, roles and identities of the token defining that rule.
+func (r *ACLResolver) ExplainToken(token string, requests []structs.ACLAuthorizationRequest) ([]structs.ACLAuthorizationExplanation, error) {
+	identity, authz, err := r.ResolveTokenToIdentityAndAuthorizer(token)
+	if err != nil {
+		return nil, err
+	}
+
+	var sources []explainSource
+	if identity != nil {
+		sources, err = r.explainSourcesForIdentity(identity)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var conf acl.Config
+	if r.aclConf != nil {
+		conf = *r.aclConf
+	}
+	if identity != nil {
+		setEnterpriseConf(identity.EnterpriseMetadata(), &conf)
+	}
+
+	parsed := make(map[*structs.ACLPolicy]*acl.Policy)
+	explanations := make([]structs.ACLAuthorizationExplanation, len(requests))
+	for idx, req := range requests {
+		var ctx acl.AuthorizerContext
+		req.FillAuthzContext(&ctx)
+
+		explanation, err := acl.Explain(authz, req.Resource, req.Segment, req.Access, &ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		out := &explanations[idx]
+		out.ACLAuthorizationRequest = req
+		out.Allow = explanation.Decision == acl.Allow
+		out.Rule = explanation.Rule
+		if explanation.Authorizer == acl.RootAuthorizer(r.config.ACLDefaultPolicy) {
+			out.DefaultPolicy = r.config.ACLDefaultPolicy
+		}
+		if out.Rule == nil {
+			continue
+		}
+
+		for _, s := range sources {
+			policy, ok := parsed[s.policy]
+			if !ok {
+				policy, err = acl.NewPolicyFromSource(s.policy.Rules, s.policy.Syntax, &conf, s.policy.EnterprisePolicyMeta())
+				if err != nil {
+					return nil, err
+				}
+				parsed[s.policy] = policy
+			}
+			if out.Rule.DefinedIn(&policy.PolicyRules) {
+				out.Sources = append(out.Sources, s.source)
+			}
+		}
+	}
+
+	return explanations, nil
+}
+
+// explainSourcesForIdentity collects the policies in effect for the identity
+// like resolvePoliciesForIdentity does, but keeps track of the role or the
+// identity each of them comes from.
+func (r *ACLResolver) explainSourcesForIdentity(identity structs.ACLIdentity) ([]explainSource, error) {
+	var sources []explainSource
+
+	add := func(source structs.ACLExplainSource, policy *structs.ACLPolicy, role *structs.ACLRole) {
+		if len(r.filterPoliciesByScope(structs.ACLPolicies{policy})) == 0 {
+			return
+		}
+		if role != nil {
+			source.RoleID = role.ID
+			source.RoleName = role.Name
+		}
+		sources = append(sources, explainSource{source: source, policy: policy})
+	}
+
+	addAll := func(policyIDs []string, serviceIdentities []*structs.ACLServiceIdentity, nodeIdentities []*structs.ACLNodeIdentity, templateLinks []*structs.ACLPolicyTemplateLink, role *structs.ACLRole) error {
+		policies, err := r.collectPoliciesForIdentity(identity, mergeStringSlice(policyIDs, policyTemplateIDs(templateLinks)), 0)
+		if err != nil {
+			return err
+		}
+		templates := make(map[string]*structs.ACLPolicy)
+		for _, policy := range policies {
+			if policy.IsTemplate() {
+				templates[policy.ID] = policy
+				continue
+			}
+			add(structs.ACLExplainSource{
+				Type: structs.ACLExplainSourcePolicy,
+				ID:   policy.ID,
+				Name: policy.Name,
+			}, policy, role)
+		}
+		for _, link := range templateLinks {
+			template, ok := templates[link.ID]
+			if !ok {
+				continue
+			}
+			if rendered, err := template.RenderTemplate(link.Vars); err == nil {
+				add(structs.ACLExplainSource{
+					Type: structs.ACLExplainSourcePolicyTemplate,
+					ID:   template.ID,
+					Name: template.Name,
+				}, rendered, role)
+			}
+		}
+		for _, s := range serviceIdentities {
+			add(structs.ACLExplainSource{
+				Type: structs.ACLExplainSourceServiceIdentity,
+				Name: s.ServiceName,
+			}, s.SyntheticPolicy(identity.EnterpriseMetadata()), role)
+		}
+		for _, n := range nodeIdentities {
+			add(structs.ACLExplainSource{
+				Type: structs.ACLExplainSourceNodeIdentity,
+				Name: n.NodeName,
+			}, n.SyntheticPolicy(identity.EnterpriseMetadata()), role)
+		}
+		return nil
+	}
+
+	err := addAll(identity.PolicyIDs(), identity.ServiceIdentityList(), identity.NodeIdentityList(), identity.PolicyTemplateList(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := r.collectRolesForIdentity(identity, identity.RoleIDs())
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range roles {
+		policyIDs := make([]string, 0, len(role.Policies))
+		for _, link := range role.Policies {
+			policyIDs = append(policyIDs, link.ID)
+		}
+		if err := addAll(policyIDs, role.ServiceIdentities, role.NodeIdentityList(), role.PolicyTemplates, role); err != nil {
+			return nil, err
+		}
+	}
+
+	return sources, nil
+}
@@ -130,7 +130,8 @@ func (s *Server) ResolveIdentityFromToken(token string) (bool, structs.ACLIdenti
 	index, aclToken, err := s.fsm.State().ACLTokenGetBySecret(nil, token, nil)
 	if err != nil {
 		return true, nil, err
-	} else if aclToken != nil && !aclToken.IsExpired(time.Now()) {
+	} else if now := time.Now(); aclToken != nil && !aclToken.IsExpired(now) {
+		s.aclTokenUsage.record(aclToken.AccessorID, now)
 		return true, aclToken, nil
 	}
 
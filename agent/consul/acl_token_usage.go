@@ -0,0 +1,166 @@
+package consul
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+// aclTokenUsageBatchSize is the number of tokens whose usage is committed in
+// a single Raft entry.
+const aclTokenUsageBatchSize = 4096
+
+// aclTokenUsageTracker keeps track of when tokens were last used, keyed by
+// their AccessorID, until the usage is committed through Raft.
+type aclTokenUsageTracker struct {
+	lock  sync.Mutex
+	usage map[string]time.Time
+}
+
+func newACLTokenUsageTracker() *aclTokenUsageTracker {
+	return &aclTokenUsageTracker{usage: make(map[string]time.Time)}
+}
+
+// record notes that the token was used at the given time.
+func (t *aclTokenUsageTracker) record(accessorID string, at time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if last, ok := t.usage[accessorID]; !ok || at.After(last) {
+		t.usage[accessorID] = at
+	}
+}
+
+// merge records the usage previously returned by drain.
+func (t *aclTokenUsageTracker) merge(usage map[string]time.Time) {
+	for accessorID, at := range usage {
+		t.record(accessorID, at)
+	}
+}
+
+// drain returns the usage recorded so far and resets the tracker.
+func (t *aclTokenUsageTracker) drain() map[string]time.Time {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	usage := t.usage
+	t.usage = make(map[string]time.Time)
+	return usage
+}
+
+// updateACLTokenUsage is a long-running routine that periodically commits the
+// token usage recorded by this server. Followers report their usage to the
+// leader which batches it along with its own. In secondary datacenters the
+// leader also reports the usage of global tokens to the primary datacenter so
+// that it knows about tokens used anywhere. The reports are made with the
+// replication token, which must have acl write permission.
+func (s *Server) updateACLTokenUsage() {
+	for {
+		select {
+		case <-time.After(s.config.ACLTokenUsageUpdatePeriod):
+			if err := s.flushACLTokenUsage(); err != nil {
+				s.logger.Warn("failed to update ACL token usage", "error", err)
+			}
+		case <-s.shutdownCh:
+			return
+		}
+	}
+}
+
+func (s *Server) flushACLTokenUsage() error {
+	usage := s.aclTokenUsage.drain()
+	if len(usage) == 0 {
+		return nil
+	}
+
+	token := s.tokens.ReplicationToken()
+
+	var err error
+	if s.IsLeader() {
+		var global map[string]time.Time
+		global, err = s.applyACLTokenUsage(usage)
+		// Global tokens are only replicated with a replication token, which is
+		// also required to report their usage.
+		if err == nil && len(global) > 0 && token != "" {
+			req := structs.ACLTokenUsageUpdateRequest{
+				Datacenter:   s.config.PrimaryDatacenter,
+				Usage:        global,
+				WriteRequest: structs.WriteRequest{Token: token},
+			}
+			var reply struct{}
+			if err = s.RPC("ACL.TokenUsageUpdate", &req, &reply); err != nil {
+				// The local usage has been committed, only the global usage
+				// still has to be reported.
+				usage = global
+			}
+		}
+	} else if token == "" {
+		// Without a replication token the leader can't tell this server apart
+		// from any other caller, so the usage can't be reported.
+		s.logger.Debug("dropping ACL token usage, no replication token is configured to report it to the leader")
+		return nil
+	} else {
+		req := structs.ACLTokenUsageUpdateRequest{
+			Datacenter:   s.config.Datacenter,
+			Usage:        usage,
+			WriteRequest: structs.WriteRequest{Token: token},
+		}
+		var reply struct{}
+		err = s.RPC("ACL.TokenUsageUpdate", &req, &reply)
+	}
+	if err != nil {
+		// Keep the usage around to commit it along with the next batch.
+		s.aclTokenUsage.merge(usage)
+	}
+	return err
+}
+
+// applyACLTokenUsage commits the usage of the tokens through Raft. Tokens
+// deleted since they were used are skipped. In secondary datacenters it returns
+// the usage of the global tokens, which must also be reported to the primary
+// datacenter.
+func (s *Server) applyACLTokenUsage(usage map[string]time.Time) (map[string]time.Time, error) {
+	state := s.fsm.State()
+	primary := s.InPrimaryDatacenter()
+	global := make(map[string]time.Time)
+
+	req := structs.ACLTokenUsageSetRequest{Usage: make(map[string]time.Time)}
+	apply := func() error {
+		// We set the "safe to ignore" flag on this update type so old
+		// servers don't crash if they see one of these.
+		t := structs.ACLTokenUsageSetRequestType | structs.IgnoreUnknownTypeFlag
+		if _, err := s.raftApply(t, &req); err != nil {
+			return err
+		}
+		req.Usage = make(map[string]time.Time)
+		return nil
+	}
+
+	for accessorID, lastUsed := range usage {
+		_, token, err := state.ACLTokenGetByAccessor(nil, accessorID, nil)
+		if err != nil {
+			return nil, err
+		}
+		if token == nil {
+			continue
+		}
+		if !primary && !token.Local {
+			global[accessorID] = lastUsed
+		}
+
+		req.Usage[token.AccessorID] = lastUsed
+		if len(req.Usage) >= aclTokenUsageBatchSize {
+			if err := apply(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(req.Usage) > 0 {
+		if err := apply(); err != nil {
+			return nil, err
+		}
+	}
+	return global, nil
+}
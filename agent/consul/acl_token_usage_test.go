@@ -0,0 +1,170 @@
+package consul
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/agent/structs"
+	tokenStore "github.com/hashicorp/consul/agent/token"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
+	"github.com/hashicorp/consul/testrpc"
+)
+
+func TestACLTokenUsage(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	dir1, s1 := testServerWithConfig(t, testServerACLConfig, func(c *Config) {
+		c.ACLTokenUsageUpdatePeriod = 50 * time.Millisecond
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+
+	dir2, s2 := testServerWithConfig(t, testServerACLConfig, func(c *Config) {
+		c.Bootstrap = false
+		c.ACLTokenUsageUpdatePeriod = 50 * time.Millisecond
+	})
+	defer os.RemoveAll(dir2)
+	defer s2.Shutdown()
+	s2.tokens.UpdateReplicationToken(TestDefaultInitialManagementToken, tokenStore.TokenSourceConfig)
+
+	joinLAN(t, s2, s1)
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+	waitForLeaderEstablishment(t, s1)
+
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	lastUsedTime := func(t require.TestingT, accessorID string) *time.Time {
+		_, token, err := s1.fsm.State().ACLTokenGetByAccessor(nil, accessorID, nil)
+		require.NoError(t, err)
+		require.NotNil(t, token)
+		return token.LastUsedTime
+	}
+
+	t.Run("leader", func(t *testing.T) {
+		token, err := upsertTestToken(codec, TestDefaultInitialManagementToken, "dc1", nil)
+		require.NoError(t, err)
+		require.Nil(t, lastUsedTime(t, token.AccessorID))
+
+		before := time.Now()
+		_, err = s1.ResolveToken(token.SecretID)
+		require.NoError(t, err)
+
+		retry.Run(t, func(r *retry.R) {
+			lastUsed := lastUsedTime(r, token.AccessorID)
+			require.NotNil(r, lastUsed)
+			require.False(r, lastUsed.Before(before))
+		})
+	})
+
+	t.Run("follower", func(t *testing.T) {
+		token, err := upsertTestToken(codec, TestDefaultInitialManagementToken, "dc1", nil)
+		require.NoError(t, err)
+
+		retry.Run(t, func(r *retry.R) {
+			_, err := s2.ResolveToken(token.SecretID)
+			require.NoError(r, err)
+		})
+
+		retry.Run(t, func(r *retry.R) {
+			require.NotNil(r, lastUsedTime(r, token.AccessorID))
+		})
+	})
+
+	t.Run("reported usage", func(t *testing.T) {
+		token, err := upsertTestToken(codec, TestDefaultInitialManagementToken, "dc1", nil)
+		require.NoError(t, err)
+
+		future := time.Now().Add(time.Hour)
+		req := structs.ACLTokenUsageUpdateRequest{
+			Datacenter: "dc1",
+			Usage: map[string]time.Time{
+				token.AccessorID:                       future,
+				"ce5ec578-1ad4-4b2f-bbe6-6fc20bda5ff2": future,
+			},
+		}
+		var reply struct{}
+
+		// Reporting usage requires acl write permission, holding the token
+		// isn't enough.
+		req.Token = token.SecretID
+		err = msgpackrpc.CallWithCodec(codec, "ACL.TokenUsageUpdate", &req, &reply)
+		require.True(t, acl.IsErrPermissionDenied(err), "unexpected error: %v", err)
+
+		req.Token = TestDefaultInitialManagementToken
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.TokenUsageUpdate", &req, &reply))
+
+		retry.Run(t, func(r *retry.R) {
+			lastUsed := lastUsedTime(r, token.AccessorID)
+			require.NotNil(r, lastUsed)
+			// Usage reported in the future is recorded as of now.
+			require.True(r, lastUsed.Before(future))
+		})
+	})
+}
+
+func TestACLTokenUsage_GlobalTokensReportedToPrimary(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.PrimaryDatacenter = "dc1"
+		c.ACLsEnabled = true
+		c.ACLInitialManagementToken = "root"
+		c.ACLTokenUsageUpdatePeriod = 50 * time.Millisecond
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	dir2, s2 := testServerWithConfig(t, func(c *Config) {
+		c.Datacenter = "dc2"
+		c.PrimaryDatacenter = "dc1"
+		c.ACLsEnabled = true
+		c.ACLTokenReplication = true
+		c.ACLTokenUsageUpdatePeriod = 50 * time.Millisecond
+	})
+	defer os.RemoveAll(dir2)
+	defer s2.Shutdown()
+	s2.tokens.UpdateReplicationToken("root", tokenStore.TokenSourceConfig)
+	testrpc.WaitForLeader(t, s2.RPC, "dc2")
+
+	joinWAN(t, s2, s1)
+	testrpc.WaitForLeader(t, s1.RPC, "dc2")
+
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	token, err := upsertTestToken(codec, "root", "dc1", nil)
+	require.NoError(t, err)
+
+	retry.Run(t, func(r *retry.R) {
+		_, replicated, err := s2.fsm.State().ACLTokenGetByAccessor(nil, token.AccessorID, nil)
+		require.NoError(r, err)
+		require.NotNil(r, replicated)
+	})
+
+	_, err = s2.ResolveToken(token.SecretID)
+	require.NoError(t, err)
+
+	for _, s := range []*Server{s1, s2} {
+		retry.Run(t, func(r *retry.R) {
+			_, token, err := s.fsm.State().ACLTokenGetByAccessor(nil, token.AccessorID, nil)
+			require.NoError(r, err)
+			require.NotNil(r, token)
+			require.NotNil(r, token.LastUsedTime)
+		})
+	}
+}
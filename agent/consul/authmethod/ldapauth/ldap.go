@@ -0,0 +1,353 @@
+package ldapauth
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/hashicorp/consul/agent/consul/authmethod"
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+func init() {
+	// register this as an available auth method type
+	authmethod.Register("ldap", func(logger hclog.Logger, method *structs.ACLAuthMethod) (authmethod.Validator, error) {
+		v, err := NewValidator(logger, method)
+		if err != nil {
+			return nil, err
+		}
+		return v, nil
+	})
+}
+
+const (
+	usernameField = "username"
+	dnField       = "dn"
+	groupsField   = "groups"
+
+	defaultUserAttr    = "uid"
+	defaultGroupAttr   = "cn"
+	defaultGroupFilter = "(|(memberUid={{.Username}})(member={{.UserDN}})(uniqueMember={{.UserDN}}))"
+
+	// requestTimeout bounds every request made to the LDAP server.
+	requestTimeout = 10 * time.Second
+)
+
+type Config struct {
+	// URL is the URL of the LDAP server, using either the ldap:// or the
+	// ldaps:// scheme.
+	URL string `json:",omitempty"`
+
+	// CACert is the PEM encoded CA certificate used to verify the LDAP
+	// server's certificate. If not set, system certificates are used.
+	CACert string `json:",omitempty"`
+
+	// StartTLS upgrades ldap:// connections to TLS before authenticating.
+	StartTLS bool `json:",omitempty"`
+
+	// BindDN and BindPassword are the credentials used to search for users
+	// and groups. An anonymous bind is used if BindDN is empty.
+	BindDN       string `json:",omitempty"`
+	BindPassword string `json:",omitempty"`
+
+	// UserDN is the base DN under which users are searched for.
+	UserDN string `json:",omitempty"`
+
+	// UserAttr is the attribute matched against the username when
+	// searching for users. Defaults to "uid".
+	UserAttr string `json:",omitempty"`
+
+	// UserFilter is an optional filter that users must also match, for
+	// example "(objectClass=person)".
+	UserFilter string `json:",omitempty"`
+
+	// GroupDN is the base DN under which groups are searched for. Groups are
+	// not looked up if it is empty.
+	GroupDN string `json:",omitempty"`
+
+	// GroupFilter is the Go template of the filter used to search for the
+	// groups of a user. The {{.Username}} and {{.UserDN}} fields are escaped
+	// before being substituted. Defaults to a filter matching the memberUid,
+	// member and uniqueMember attributes.
+	GroupFilter string `json:",omitempty"`
+
+	// GroupAttr is the attribute of the group entries holding their name.
+	// Defaults to "cn".
+	GroupAttr string `json:",omitempty"`
+}
+
+// Validator authenticates users against an LDAP directory and conforms to
+// the authmethod.Validator interface.
+type Validator struct {
+	name        string
+	config      *Config
+	logger      hclog.Logger
+	url         *url.URL
+	tlsConfig   *tls.Config
+	groupFilter *template.Template
+}
+
+func NewValidator(logger hclog.Logger, method *structs.ACLAuthMethod) (*Validator, error) {
+	if method.Type != "ldap" {
+		return nil, fmt.Errorf("%q is not an ldap auth method", method.Name)
+	}
+
+	var config Config
+	if err := authmethod.ParseConfig(method.Config, &config); err != nil {
+		return nil, err
+	}
+
+	if config.URL == "" {
+		return nil, fmt.Errorf("Config.URL is required")
+	}
+	u, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("Config.URL is invalid: %v", err)
+	}
+	switch u.Scheme {
+	case "ldap":
+	case "ldaps":
+		if config.StartTLS {
+			return nil, fmt.Errorf("Config.StartTLS cannot be used with an ldaps:// URL")
+		}
+	default:
+		return nil, fmt.Errorf("Config.URL must use the ldap:// or ldaps:// scheme")
+	}
+
+	if config.UserDN == "" {
+		return nil, fmt.Errorf("Config.UserDN is required")
+	}
+	if config.BindDN == "" && config.BindPassword != "" {
+		return nil, fmt.Errorf("Config.BindPassword requires Config.BindDN")
+	}
+	if config.UserAttr == "" {
+		config.UserAttr = defaultUserAttr
+	}
+	if config.GroupAttr == "" {
+		config.GroupAttr = defaultGroupAttr
+	}
+	if config.GroupFilter == "" {
+		config.GroupFilter = defaultGroupFilter
+	}
+	if config.UserFilter != "" {
+		if _, err := ldap.CompileFilter(config.UserFilter); err != nil {
+			return nil, fmt.Errorf("Config.UserFilter is invalid: %v", err)
+		}
+	}
+
+	groupFilter, err := template.New("GroupFilter").Option("missingkey=error").Parse(config.GroupFilter)
+	if err != nil {
+		return nil, fmt.Errorf("Config.GroupFilter is not a valid template: %v", err)
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName: u.Hostname(),
+	}
+	if config.CACert != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(config.CACert)) {
+			return nil, fmt.Errorf("Config.CACert does not contain a valid PEM certificate")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return &Validator{
+		name:        method.Name,
+		config:      &config,
+		logger:      logger,
+		url:         u,
+		tlsConfig:   tlsConfig,
+		groupFilter: groupFilter,
+	}, nil
+}
+
+func (v *Validator) Name() string { return v.name }
+
+func (v *Validator) Stop() {}
+
+// ValidateLogin authenticates the user against the LDAP directory. The login
+// token holds the credentials of the user as "username:password".
+func (v *Validator) ValidateLogin(ctx context.Context, loginToken string) (*authmethod.Identity, error) {
+	parts := strings.SplitN(loginToken, ":", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return nil, errors.New("login token must be of the form username:password")
+	}
+	username, password := parts[0], parts[1]
+	// An empty password would result in an unauthenticated bind, which most
+	// servers accept for any DN.
+	if password == "" {
+		return nil, errors.New("password is required")
+	}
+
+	conn, err := v.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := v.bindSearch(conn); err != nil {
+		return nil, err
+	}
+
+	userDN, err := v.findUser(conn, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := conn.Bind(userDN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, errors.New("invalid username or password")
+		}
+		return nil, fmt.Errorf("failed to bind as user: %v", err)
+	}
+
+	var groups []string
+	if v.config.GroupDN != "" {
+		// Search for the groups with the configured credentials, as users
+		// are not always allowed to read group entries.
+		if err := v.bindSearch(conn); err != nil {
+			return nil, err
+		}
+		groups, err = v.findGroups(conn, username, userDN)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	id := v.NewIdentity()
+	id.SelectableFields = &ldapFieldDetails{
+		Username: username,
+		DN:       userDN,
+		Groups:   groups,
+	}
+	id.ProjectedVars[usernameField] = username
+	id.ProjectedVars[dnField] = userDN
+	id.ProjectedVars[groupsField] = strings.Join(groups, ",")
+	return id, nil
+}
+
+func (v *Validator) dial() (*ldap.Conn, error) {
+	conn, err := ldap.DialURL(v.url.String(), ldap.DialWithTLSConfig(v.tlsConfig))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to LDAP server: %v", err)
+	}
+	conn.SetTimeout(requestTimeout)
+
+	if v.config.StartTLS {
+		if err := conn.StartTLS(v.tlsConfig); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to start TLS with LDAP server: %v", err)
+		}
+	}
+	return conn, nil
+}
+
+// bindSearch binds with the credentials used to search the directory.
+func (v *Validator) bindSearch(conn *ldap.Conn) error {
+	if v.config.BindDN == "" {
+		if err := conn.UnauthenticatedBind(""); err != nil {
+			return fmt.Errorf("failed to bind anonymously: %v", err)
+		}
+		return nil
+	}
+	if err := conn.Bind(v.config.BindDN, v.config.BindPassword); err != nil {
+		return fmt.Errorf("failed to bind as %q: %v", v.config.BindDN, err)
+	}
+	return nil
+}
+
+// findUser returns the DN of the single user entry matching the username.
+func (v *Validator) findUser(conn *ldap.Conn, username string) (string, error) {
+	filter := fmt.Sprintf("(%s=%s)", v.config.UserAttr, ldap.EscapeFilter(username))
+	if v.config.UserFilter != "" {
+		filter = fmt.Sprintf("(&%s%s)", filter, v.config.UserFilter)
+	}
+
+	result, err := conn.Search(ldap.NewSearchRequest(
+		v.config.UserDN,
+		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases,
+		0, int(requestTimeout.Seconds()), false,
+		filter,
+		[]string{"dn"},
+		nil,
+	))
+	if err != nil {
+		return "", fmt.Errorf("failed to search for user: %v", err)
+	}
+
+	switch {
+	case len(result.Entries) > 1:
+		return "", fmt.Errorf("found more than one user matching %q", username)
+	case len(result.Entries) == 0:
+		return "", errors.New("invalid username or password")
+	}
+	return result.Entries[0].DN, nil
+}
+
+// findGroups returns the sorted names of the groups the user belongs to.
+func (v *Validator) findGroups(conn *ldap.Conn, username, userDN string) ([]string, error) {
+	var buf bytes.Buffer
+	err := v.groupFilter.Execute(&buf, struct {
+		Username string
+		UserDN   string
+	}{
+		Username: ldap.EscapeFilter(username),
+		UserDN:   ldap.EscapeFilter(userDN),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render group filter: %v", err)
+	}
+
+	result, err := conn.Search(ldap.NewSearchRequest(
+		v.config.GroupDN,
+		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases,
+		0, int(requestTimeout.Seconds()), false,
+		buf.String(),
+		[]string{v.config.GroupAttr},
+		nil,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search for groups: %v", err)
+	}
+
+	var groups []string
+	for _, entry := range result.Entries {
+		groups = append(groups, entry.GetAttributeValues(v.config.GroupAttr)...)
+	}
+	sort.Strings(groups)
+	return groups, nil
+}
+
+func (v *Validator) NewIdentity() *authmethod.Identity {
+	id := &authmethod.Identity{
+		SelectableFields: &ldapFieldDetails{},
+		ProjectedVars:    map[string]string{},
+	}
+	for _, f := range availableFields {
+		id.ProjectedVars[f] = ""
+	}
+	return id
+}
+
+var availableFields = []string{
+	usernameField,
+	dnField,
+	groupsField,
+}
+
+type ldapFieldDetails struct {
+	Username string   `bexpr:"username"`
+	DN       string   `bexpr:"dn"`
+	Groups   []string `bexpr:"groups"`
+}
@@ -0,0 +1,229 @@
+package ldapauth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/consul/authmethod"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/sdk/testutil"
+)
+
+func startTestDirectory(t *testing.T) *TestLDAPServer {
+	t.Helper()
+
+	srv := StartTestLDAPServer(t)
+	t.Cleanup(srv.Stop)
+
+	srv.AddEntry("cn=consul,ou=services,dc=example,dc=com", "consul-password", map[string][]string{
+		"objectClass": {"person"},
+		"cn":          {"consul"},
+	})
+	srv.AddEntry("uid=alice,ou=users,dc=example,dc=com", "alice-password", map[string][]string{
+		"objectClass": {"person"},
+		"uid":         {"alice"},
+	})
+	srv.AddEntry("uid=bob,ou=users,dc=example,dc=com", "bob-password", map[string][]string{
+		"objectClass": {"person"},
+		"uid":         {"bob"},
+	})
+	srv.AddEntry("uid=printer,ou=users,dc=example,dc=com", "printer-password", map[string][]string{
+		"objectClass": {"device"},
+		"uid":         {"printer"},
+	})
+	srv.AddEntry("cn=admins,ou=groups,dc=example,dc=com", "", map[string][]string{
+		"cn":     {"admins"},
+		"member": {"uid=alice,ou=users,dc=example,dc=com"},
+	})
+	srv.AddEntry("cn=developers,ou=groups,dc=example,dc=com", "", map[string][]string{
+		"cn":        {"developers"},
+		"memberUid": {"alice", "bob"},
+	})
+	return srv
+}
+
+func testMethod(srv *TestLDAPServer, f func(config map[string]interface{})) *structs.ACLAuthMethod {
+	method := &structs.ACLAuthMethod{
+		Name:        "test-ldap",
+		Description: "ldap test",
+		Type:        "ldap",
+		Config: map[string]interface{}{
+			"URL":          srv.URL(),
+			"BindDN":       "cn=consul,ou=services,dc=example,dc=com",
+			"BindPassword": "consul-password",
+			"UserDN":       "ou=users,dc=example,dc=com",
+			"UserFilter":   "(objectClass=person)",
+			"GroupDN":      "ou=groups,dc=example,dc=com",
+		},
+	}
+	if f != nil {
+		f(method.Config)
+	}
+	return method
+}
+
+func TestNewValidator(t *testing.T) {
+	srv := startTestDirectory(t)
+
+	cases := map[string]struct {
+		method    *structs.ACLAuthMethod
+		expectErr string
+	}{
+		"wrong type": {func() *structs.ACLAuthMethod {
+			method := testMethod(srv, nil)
+			method.Type = "jwt"
+			return method
+		}(), "is not an ldap auth method"},
+		"missing URL": {testMethod(srv, func(config map[string]interface{}) {
+			delete(config, "URL")
+		}), "Config.URL is required"},
+		"invalid scheme": {testMethod(srv, func(config map[string]interface{}) {
+			config["URL"] = "http://127.0.0.1:389"
+		}), "Config.URL must use the ldap:// or ldaps:// scheme"},
+		"StartTLS with ldaps": {testMethod(srv, func(config map[string]interface{}) {
+			config["URL"] = "ldaps://127.0.0.1:636"
+			config["StartTLS"] = true
+		}), "Config.StartTLS cannot be used with an ldaps:// URL"},
+		"missing user DN": {testMethod(srv, func(config map[string]interface{}) {
+			delete(config, "UserDN")
+		}), "Config.UserDN is required"},
+		"bind password without DN": {testMethod(srv, func(config map[string]interface{}) {
+			delete(config, "BindDN")
+		}), "Config.BindPassword requires Config.BindDN"},
+		"invalid user filter": {testMethod(srv, func(config map[string]interface{}) {
+			config["UserFilter"] = "objectClass=person("
+		}), "Config.UserFilter is invalid"},
+		"invalid group filter": {testMethod(srv, func(config map[string]interface{}) {
+			config["GroupFilter"] = "(member={{.UserDN)"
+		}), "Config.GroupFilter is not a valid template"},
+		"invalid CA cert": {testMethod(srv, func(config map[string]interface{}) {
+			config["CACert"] = "garbage"
+		}), "Config.CACert does not contain a valid PEM certificate"},
+		"unknown config": {testMethod(srv, func(config map[string]interface{}) {
+			config["Extra"] = "config"
+		}), "has invalid keys"},
+	}
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			_, err := NewValidator(hclog.NewNullLogger(), tc.method)
+			testutil.RequireErrorContains(t, err, tc.expectErr)
+		})
+	}
+}
+
+func TestNewIdentity(t *testing.T) {
+	srv := startTestDirectory(t)
+
+	validator, err := NewValidator(hclog.NewNullLogger(), testMethod(srv, nil))
+	require.NoError(t, err)
+
+	id := validator.NewIdentity()
+	authmethod.RequireIdentityMatch(t, id, map[string]string{
+		"username": "",
+		"dn":       "",
+		"groups":   "",
+	},
+		`username == ""`,
+		`dn == ""`,
+		`groups is empty`,
+	)
+}
+
+func TestValidateLogin(t *testing.T) {
+	srv := startTestDirectory(t)
+
+	validator, err := NewValidator(hclog.NewNullLogger(), testMethod(srv, nil))
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		loginToken string
+		expectErr  string
+	}{
+		"malformed token":  {"alice", "login token must be of the form username:password"},
+		"missing username": {":alice-password", "login token must be of the form username:password"},
+		"missing password": {"alice:", "password is required"},
+		"wrong password":   {"alice:bob-password", "invalid username or password"},
+		"unknown user":     {"carol:carol-password", "invalid username or password"},
+		"filtered user":    {"printer:printer-password", "invalid username or password"},
+		"filter injection": {"*:alice-password", "invalid username or password"},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			_, err := validator.ValidateLogin(context.Background(), tc.loginToken)
+			testutil.RequireErrorContains(t, err, tc.expectErr)
+		})
+	}
+
+	t.Run("valid credentials", func(t *testing.T) {
+		id, err := validator.ValidateLogin(context.Background(), "alice:alice-password")
+		require.NoError(t, err)
+
+		authmethod.RequireIdentityMatch(t, id, map[string]string{
+			"username": "alice",
+			"dn":       "uid=alice,ou=users,dc=example,dc=com",
+			"groups":   "admins,developers",
+		},
+			`username == alice`,
+			`dn == "uid=alice,ou=users,dc=example,dc=com"`,
+			`admins in groups`,
+			`developers in groups`,
+			`operators not in groups`,
+		)
+	})
+
+	t.Run("valid credentials with colon in password", func(t *testing.T) {
+		srv.AddEntry("uid=carol,ou=users,dc=example,dc=com", "carol:password", map[string][]string{
+			"objectClass": {"person"},
+			"uid":         {"carol"},
+		})
+
+		id, err := validator.ValidateLogin(context.Background(), "carol:carol:password")
+		require.NoError(t, err)
+
+		authmethod.RequireIdentityMatch(t, id, map[string]string{
+			"username": "carol",
+			"dn":       "uid=carol,ou=users,dc=example,dc=com",
+			"groups":   "",
+		},
+			`username == carol`,
+			`groups is empty`,
+		)
+	})
+}
+
+func TestValidateLogin_StartTLS(t *testing.T) {
+	srv := startTestDirectory(t)
+
+	t.Run("trusted certificate", func(t *testing.T) {
+		validator, err := NewValidator(hclog.NewNullLogger(), testMethod(srv, func(config map[string]interface{}) {
+			config["StartTLS"] = true
+			config["CACert"] = srv.CACert()
+		}))
+		require.NoError(t, err)
+
+		id, err := validator.ValidateLogin(context.Background(), "bob:bob-password")
+		require.NoError(t, err)
+		authmethod.RequireIdentityMatch(t, id, map[string]string{
+			"username": "bob",
+			"dn":       "uid=bob,ou=users,dc=example,dc=com",
+			"groups":   "developers",
+		},
+			`developers in groups`,
+			`admins not in groups`,
+		)
+	})
+
+	t.Run("untrusted certificate", func(t *testing.T) {
+		validator, err := NewValidator(hclog.NewNullLogger(), testMethod(srv, func(config map[string]interface{}) {
+			config["StartTLS"] = true
+		}))
+		require.NoError(t, err)
+
+		_, err = validator.ValidateLogin(context.Background(), "bob:bob-password")
+		testutil.RequireErrorContains(t, err, "failed to start TLS with LDAP server")
+	})
+}
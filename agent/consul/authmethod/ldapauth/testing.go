@@ -0,0 +1,312 @@
+package ldapauth
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/mitchellh/go-testing-interface"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLDAPServer is an in-process LDAP server holding a static directory, to
+// be used by the tests of the ldap auth method. It supports the subset of the
+// protocol used by the auth method:
+//
+//   - simple binds
+//   - searches with and, or, not, equality and presence filters
+//   - StartTLS
+type TestLDAPServer struct {
+	ln     net.Listener
+	tls    *tls.Config
+	caCert string
+
+	mu       sync.Mutex
+	entries  map[string]map[string][]string // DN -> attributes
+	password map[string]string              // DN -> password
+}
+
+// StartTestLDAPServer creates a disposable TestLDAPServer and binds it to a
+// random free port.
+func StartTestLDAPServer(t testing.T) *TestLDAPServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s := &TestLDAPServer{
+		ln:       ln,
+		entries:  make(map[string]map[string][]string),
+		password: make(map[string]string),
+	}
+	s.tls, s.caCert = generateTestTLSConfig(t)
+
+	go s.serve()
+	return s
+}
+
+// AddEntry adds an entry to the directory. If password is not empty, it can
+// be used to bind as the entry.
+func (s *TestLDAPServer) AddEntry(dn, password string, attributes map[string][]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	dn = strings.ToLower(dn)
+	s.entries[dn] = attributes
+	if password != "" {
+		s.password[dn] = password
+	}
+}
+
+// Stop stops the server.
+func (s *TestLDAPServer) Stop() { s.ln.Close() }
+
+// URL returns the ldap:// URL of the server.
+func (s *TestLDAPServer) URL() string { return "ldap://" + s.ln.Addr().String() }
+
+// CACert returns the PEM encoded certificate presented after StartTLS.
+func (s *TestLDAPServer) CACert() string { return s.caCert }
+
+func (s *TestLDAPServer) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *TestLDAPServer) handle(conn net.Conn) {
+	defer func() { conn.Close() }()
+
+	for {
+		packet, err := ber.ReadPacket(conn)
+		if err != nil {
+			return
+		}
+		if len(packet.Children) < 2 {
+			return
+		}
+		messageID := packet.Children[0].Value
+		op := packet.Children[1]
+
+		switch op.Tag {
+		case ldap.ApplicationBindRequest:
+			code := s.bind(op)
+			s.reply(conn, messageID, ldap.ApplicationBindResponse, code)
+
+		case ldap.ApplicationUnbindRequest:
+			return
+
+		case ldap.ApplicationSearchRequest:
+			for _, entry := range s.search(op) {
+				if _, err := conn.Write(entry.encode(messageID).Bytes()); err != nil {
+					return
+				}
+			}
+			s.reply(conn, messageID, ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess)
+
+		case ldap.ApplicationExtendedRequest:
+			if len(op.Children) == 0 || string(op.Children[0].Data.Bytes()) != "1.3.6.1.4.1.1466.20037" {
+				s.reply(conn, messageID, ldap.ApplicationExtendedResponse, ldap.LDAPResultProtocolError)
+				continue
+			}
+			s.reply(conn, messageID, ldap.ApplicationExtendedResponse, ldap.LDAPResultSuccess)
+			tlsConn := tls.Server(conn, s.tls)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn = tlsConn
+
+		default:
+			s.reply(conn, messageID, ldap.ApplicationExtendedResponse, ldap.LDAPResultUnwillingToPerform)
+		}
+	}
+}
+
+func (s *TestLDAPServer) reply(w io.Writer, messageID interface{}, tag ber.Tag, code uint16) {
+	packet := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, "MessageID"))
+	result := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Response")
+	result.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), "resultCode"))
+	result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "matchedDN"))
+	result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "diagnosticMessage"))
+	packet.AppendChild(result)
+	w.Write(packet.Bytes())
+}
+
+func (s *TestLDAPServer) bind(op *ber.Packet) uint16 {
+	if len(op.Children) < 3 {
+		return ldap.LDAPResultProtocolError
+	}
+	dn := strings.ToLower(packetString(op.Children[1]))
+	password := packetString(op.Children[2])
+
+	// Anonymous bind.
+	if dn == "" && password == "" {
+		return ldap.LDAPResultSuccess
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if expected, ok := s.password[dn]; ok && expected == password {
+		return ldap.LDAPResultSuccess
+	}
+	return ldap.LDAPResultInvalidCredentials
+}
+
+type testEntry struct {
+	dn         string
+	attributes map[string][]string
+}
+
+func (s *TestLDAPServer) search(op *ber.Packet) []testEntry {
+	if len(op.Children) < 8 {
+		return nil
+	}
+	base := strings.ToLower(packetString(op.Children[0]))
+	filter := op.Children[6]
+
+	var wanted []string
+	for _, attr := range op.Children[7].Children {
+		wanted = append(wanted, packetString(attr))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var entries []testEntry
+	for dn, attributes := range s.entries {
+		if dn != base && !strings.HasSuffix(dn, ","+base) {
+			continue
+		}
+		if !matchFilter(filter, attributes) {
+			continue
+		}
+		entry := testEntry{dn: dn, attributes: make(map[string][]string)}
+		for name, values := range attributes {
+			for _, w := range wanted {
+				if strings.EqualFold(w, name) {
+					entry.attributes[name] = values
+				}
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func (e testEntry) encode(messageID interface{}) *ber.Packet {
+	packet := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, "MessageID"))
+	result := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+	result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, e.dn, "objectName"))
+	attributes := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "attributes")
+	for name, values := range e.attributes {
+		attr := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "attribute")
+		attr.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "type"))
+		vals := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "vals")
+		for _, value := range values {
+			vals.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, "value"))
+		}
+		attr.AppendChild(vals)
+		attributes.AppendChild(attr)
+	}
+	result.AppendChild(attributes)
+	packet.AppendChild(result)
+	return packet
+}
+
+// matchFilter evaluates an encoded search filter against the attributes of
+// an entry. Attribute names and values are compared case insensitively.
+func matchFilter(filter *ber.Packet, attributes map[string][]string) bool {
+	switch filter.Tag {
+	case ldap.FilterAnd:
+		for _, child := range filter.Children {
+			if !matchFilter(child, attributes) {
+				return false
+			}
+		}
+		return true
+	case ldap.FilterOr:
+		for _, child := range filter.Children {
+			if matchFilter(child, attributes) {
+				return true
+			}
+		}
+		return false
+	case ldap.FilterNot:
+		return len(filter.Children) == 1 && !matchFilter(filter.Children[0], attributes)
+	case ldap.FilterEqualityMatch:
+		if len(filter.Children) != 2 {
+			return false
+		}
+		name, value := packetString(filter.Children[0]), packetString(filter.Children[1])
+		for _, v := range attributeValues(attributes, name) {
+			if strings.EqualFold(v, value) {
+				return true
+			}
+		}
+		return false
+	case ldap.FilterPresent:
+		return len(attributeValues(attributes, string(filter.Data.Bytes()))) > 0
+	}
+	return false
+}
+
+func attributeValues(attributes map[string][]string, name string) []string {
+	for n, values := range attributes {
+		if strings.EqualFold(n, name) {
+			return values
+		}
+	}
+	return nil
+}
+
+func packetString(p *ber.Packet) string {
+	if s, ok := p.Value.(string); ok {
+		return s
+	}
+	return string(p.Data.Bytes())
+}
+
+func generateTestTLSConfig(t testing.T) (*tls.Config, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, pem.Encode(&buf, &pem.Block{Type: "CERTIFICATE", Bytes: der}))
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{{
+			Certificate: [][]byte{der},
+			PrivateKey:  key,
+		}},
+	}, buf.String()
+}
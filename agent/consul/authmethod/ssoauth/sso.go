@@ -11,13 +11,15 @@ import (
 )
 
 func init() {
-	authmethod.Register("jwt", func(logger hclog.Logger, method *structs.ACLAuthMethod) (authmethod.Validator, error) {
+	factory := func(logger hclog.Logger, method *structs.ACLAuthMethod) (authmethod.Validator, error) {
 		v, err := NewValidator(logger, method)
 		if err != nil {
 			return nil, err
 		}
 		return v, nil
-	})
+	}
+	authmethod.Register(oidcauth.TypeJWT, factory)
+	authmethod.Register(oidcauth.TypeOIDC, factory)
 }
 
 // Validator is the wrapper around the go-sso library that also conforms to the
@@ -75,6 +77,29 @@ func (v *Validator) ValidateLogin(ctx context.Context, loginToken string) (*auth
 	return v.identityFromClaims(c), nil
 }
 
+// GetAuthCodeURL starts the OIDC authorization code flow and returns the URL
+// of the provider the user must visit to log in. The payload is returned by
+// ValidateAuthCode once the provider redirected the user to redirectURI.
+//
+// This is only supported by validators of type "oidc".
+func (v *Validator) GetAuthCodeURL(ctx context.Context, redirectURI string, payload interface{}) (string, error) {
+	return v.oa.GetAuthCodeURL(ctx, redirectURI, payload)
+}
+
+// ValidateAuthCode completes the OIDC authorization code flow by exchanging
+// the code for the claims of the user. It returns the identity of the user
+// along with the payload given to GetAuthCodeURL.
+//
+// This is only supported by validators of type "oidc".
+func (v *Validator) ValidateAuthCode(ctx context.Context, state, code string) (*authmethod.Identity, interface{}, error) {
+	c, payload, err := v.oa.ClaimsFromAuthCode(ctx, state, code)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return v.identityFromClaims(c), payload, nil
+}
+
 func (v *Validator) identityFromClaims(c *oidcauth.Claims) *authmethod.Identity {
 	id := v.NewIdentity()
 	id.SelectableFields = &fieldDetails{
@@ -127,6 +152,14 @@ type Config struct {
 	OIDCDiscoveryURL    string            `json:",omitempty"`
 	OIDCDiscoveryCACert string            `json:",omitempty"`
 
+	// just for type=oidc
+	OIDCClientID        string   `json:",omitempty"`
+	OIDCClientSecret    string   `json:",omitempty"`
+	OIDCScopes          []string `json:",omitempty"`
+	OIDCACRValues       []string `json:",omitempty"`
+	AllowedRedirectURIs []string `json:",omitempty"`
+	VerboseOIDCLogging  bool     `json:",omitempty"`
+
 	// just for type=jwt
 	JWKSURL              string        `json:",omitempty"`
 	JWKSCACert           string        `json:",omitempty"`
@@ -151,6 +184,14 @@ func (c *Config) convertForLibrary(methodType string) *oidcauth.Config {
 		OIDCDiscoveryURL:    c.OIDCDiscoveryURL,
 		OIDCDiscoveryCACert: c.OIDCDiscoveryCACert,
 
+		// just for type=oidc
+		OIDCClientID:        c.OIDCClientID,
+		OIDCClientSecret:    c.OIDCClientSecret,
+		OIDCScopes:          c.OIDCScopes,
+		OIDCACRValues:       c.OIDCACRValues,
+		AllowedRedirectURIs: c.AllowedRedirectURIs,
+		VerboseOIDCLogging:  c.VerboseOIDCLogging,
+
 		// just for type=jwt
 		JWKSURL:              c.JWKSURL,
 		JWKSCACert:           c.JWKSCACert,
@@ -11,10 +11,12 @@ import (
 )
 
 func validateType(typ string) error {
-	if typ != "jwt" {
-		return fmt.Errorf("type should be %q", "jwt")
+	switch typ {
+	case oidcauth.TypeJWT, oidcauth.TypeOIDC:
+		return nil
+	default:
+		return fmt.Errorf("type should be %q or %q", oidcauth.TypeJWT, oidcauth.TypeOIDC)
 	}
-	return nil
 }
 
 func (v *Validator) ssoEntMetaFromClaims(_ *oidcauth.Claims) *structs.EnterpriseMeta {
@@ -61,6 +61,18 @@ func TestJWT_NewValidator(t *testing.T) {
 			method.Config["OIDCDiscoveryURL"] = oidcServer.Addr()
 			method.Config["OIDCDiscoveryCACert"] = oidcServer.CACert()
 		}), ""},
+		"normal oidc": {makeAuthMethod("oidc", func(method AM) {
+			method.Config["OIDCDiscoveryURL"] = oidcServer.Addr()
+			method.Config["OIDCDiscoveryCACert"] = oidcServer.CACert()
+			method.Config["OIDCClientID"] = "consul"
+			method.Config["OIDCClientSecret"] = "secret"
+			method.Config["AllowedRedirectURIs"] = []string{"http://localhost:8550/oidc/callback"}
+		}), ""},
+		"oidc without client id": {makeAuthMethod("oidc", func(method AM) {
+			method.Config["OIDCDiscoveryURL"] = oidcServer.Addr()
+			method.Config["OIDCDiscoveryCACert"] = oidcServer.CACert()
+			method.Config["AllowedRedirectURIs"] = []string{"http://localhost:8550/oidc/callback"}
+		}), `'OIDCClientID' must be set for type "oidc"`},
 	} {
 		tc := tc
 		t.Run(name, func(t *testing.T) {
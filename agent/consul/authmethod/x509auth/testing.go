@@ -0,0 +1,122 @@
+package x509auth
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"sync/atomic"
+	"time"
+
+	"github.com/mitchellh/go-testing-interface"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/api"
+)
+
+// TestCA is a disposable certificate authority issuing client certificates,
+// to be used by the tests of the x509 auth method.
+type TestCA struct {
+	cert   *x509.Certificate
+	key    *ecdsa.PrivateKey
+	serial int64
+
+	// CertPEM is the PEM encoded certificate of the CA, suitable for
+	// Config.CACerts.
+	CertPEM string
+}
+
+// TestLeaf is a client certificate issued by a TestCA.
+type TestLeaf struct {
+	// DER is the DER encoded certificate.
+	DER []byte
+
+	// CertPEM and KeyPEM are the PEM encoded certificate and private key.
+	CertPEM string
+	KeyPEM  string
+}
+
+// NewTestCA creates a self-signed TestCA.
+func NewTestCA(t testing.T) *TestCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &TestCA{
+		cert:    cert,
+		key:     key,
+		serial:  1,
+		CertPEM: encodePEM(t, "CERTIFICATE", der),
+	}
+}
+
+// Issue signs a client certificate using the subject, SANs and validity
+// period of template. Missing validity bounds default to one hour around the
+// current time.
+func (ca *TestCA) Issue(t testing.T, template *x509.Certificate) *TestLeaf {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	leaf := *template
+	leaf.SerialNumber = big.NewInt(atomic.AddInt64(&ca.serial, 1))
+	if leaf.NotBefore.IsZero() {
+		leaf.NotBefore = time.Now().Add(-time.Minute)
+	}
+	if leaf.NotAfter.IsZero() {
+		leaf.NotAfter = time.Now().Add(time.Hour)
+	}
+	leaf.KeyUsage = x509.KeyUsageDigitalSignature
+	if leaf.ExtKeyUsage == nil {
+		leaf.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &leaf, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return &TestLeaf{
+		DER:     der,
+		CertPEM: encodePEM(t, "CERTIFICATE", der),
+		KeyPEM:  encodePEM(t, "EC PRIVATE KEY", keyDER),
+	}
+}
+
+// KeyPair returns the certificate along with its private key.
+func (l *TestLeaf) KeyPair(t testing.T) tls.Certificate {
+	cert, err := tls.X509KeyPair([]byte(l.CertPEM), []byte(l.KeyPEM))
+	require.NoError(t, err)
+	return cert
+}
+
+// LoginToken returns the bearer token to log in with the certificate, signing
+// the given challenge.
+func (l *TestLeaf) LoginToken(t testing.T, challenge string) string {
+	token, err := api.NewX509LoginToken(challenge, l.KeyPair(t))
+	require.NoError(t, err)
+	return token
+}
+
+func encodePEM(t testing.T, typ string, der []byte) string {
+	var buf bytes.Buffer
+	require.NoError(t, pem.Encode(&buf, &pem.Block{Type: typ, Bytes: der}))
+	return buf.String()
+}
@@ -0,0 +1,443 @@
+package x509auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/hashicorp/consul/agent/consul/authmethod"
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+func init() {
+	// register this as an available auth method type
+	authmethod.Register("x509", func(logger hclog.Logger, method *structs.ACLAuthMethod) (authmethod.Validator, error) {
+		v, err := NewValidator(logger, method)
+		if err != nil {
+			return nil, err
+		}
+		return v, nil
+	})
+}
+
+const (
+	subjectCommonNameField         = "subject.common_name"
+	subjectOrganizationField       = "subject.organization"
+	subjectOrganizationalUnitField = "subject.organizational_unit"
+
+	sanDNSField   = "san.dns"
+	sanEmailField = "san.email"
+	sanURIField   = "san.uri"
+
+	spiffeIDField          = "spiffe.id"
+	spiffeTrustDomainField = "spiffe.trust_domain"
+	spiffePathField        = "spiffe.path"
+	spiffeSegmentPrefix    = "spiffe.segment."
+
+	// maxSPIFFESegments is the number of path segments of a SPIFFE ID that are
+	// projected as spiffe.segment.N variables. Bind names are validated
+	// against the variables of a blank identity, so they must be bounded.
+	maxSPIFFESegments = 8
+
+	// challengeTTL is how long a client has to log in after requesting a
+	// challenge.
+	challengeTTL = 5 * time.Minute
+
+	// challengeNonceSize is the number of random bytes of a challenge. They
+	// follow its 8 bytes timestamp and precede its HMAC-SHA256.
+	challengeNonceSize = 16
+
+	// ChallengeSignaturePrefix is prepended to the challenge before it is
+	// signed so that the signature cannot be used for anything else.
+	ChallengeSignaturePrefix = "consul-x509-login:"
+)
+
+type Config struct {
+	// CACerts are the PEM encoded CA certificates that client certificates
+	// must chain up to. Each element may hold several certificates.
+	CACerts []string `json:",omitempty"`
+}
+
+// Validator verifies the certificate chain presented by the client when
+// logging in, along with the proof that the client holds its private key, and
+// conforms to the authmethod.Validator interface.
+type Validator struct {
+	name   string
+	config *Config
+	logger hclog.Logger
+	roots  *x509.CertPool
+
+	challengeLock sync.Mutex
+
+	// challengeKey signs the challenges issued by the servers, so that they
+	// can be verified without being kept around until the client logs in.
+	challengeKey []byte
+
+	// redeemed maps the challenges used to log in to their expiration time,
+	// so that each of them can only be used once.
+	redeemed map[string]time.Time
+}
+
+// LoginToken is the bearer token used to log in with an x509 auth method.
+//
+// It holds the certificate chain of the client and proves that the client
+// holds the private key of the leaf by signing a challenge issued by the
+// server. It must be kept in sync with api.NewX509LoginToken.
+type LoginToken struct {
+	// Challenge is the value returned by Challenge.
+	Challenge string
+
+	// Certificates is the DER encoded certificate chain, leaf first.
+	Certificates [][]byte
+
+	// Signature is the signature of ChallengeSignaturePrefix followed by the
+	// challenge, made with the private key of the leaf certificate.
+	Signature []byte
+}
+
+// Encode returns the LoginToken in the form expected by ValidateLogin.
+func (t *LoginToken) Encode() (string, error) {
+	buf, err := json.Marshal(t)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func decodeLoginToken(s string) (*LoginToken, error) {
+	buf, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	var t LoginToken
+	if err := json.Unmarshal(buf, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func NewValidator(logger hclog.Logger, method *structs.ACLAuthMethod) (*Validator, error) {
+	if method.Type != "x509" {
+		return nil, fmt.Errorf("%q is not an x509 auth method", method.Name)
+	}
+
+	var config Config
+	if err := authmethod.ParseConfig(method.Config, &config); err != nil {
+		return nil, err
+	}
+
+	if len(config.CACerts) == 0 {
+		return nil, fmt.Errorf("Config.CACerts is required")
+	}
+	roots := x509.NewCertPool()
+	for i, pem := range config.CACerts {
+		if !roots.AppendCertsFromPEM([]byte(pem)) {
+			return nil, fmt.Errorf("Config.CACerts[%d] does not contain a valid PEM certificate", i)
+		}
+	}
+
+	return &Validator{
+		name:     method.Name,
+		config:   &config,
+		logger:   logger,
+		roots:    roots,
+		redeemed: make(map[string]time.Time),
+	}, nil
+}
+
+func (v *Validator) Name() string { return v.name }
+
+func (v *Validator) Stop() {}
+
+// SetChallengeKey sets the key signing the challenges. It must be the same on
+// every server of the datacenter.
+func (v *Validator) SetChallengeKey(key []byte) {
+	v.challengeLock.Lock()
+	defer v.challengeLock.Unlock()
+	v.challengeKey = key
+}
+
+// Challenge returns a new challenge that the client must sign to log in. The
+// challenge holds the time it was issued at and a random nonce, and is signed
+// with the challenge key so that it can be verified by any server holding the
+// key without keeping any state.
+func (v *Validator) Challenge() (string, error) {
+	return v.newChallenge(time.Now())
+}
+
+func (v *Validator) newChallenge(issuedAt time.Time) (string, error) {
+	key := v.getChallengeKey()
+	if len(key) == 0 {
+		return "", errors.New("the login challenge key is not initialized yet")
+	}
+
+	buf := make([]byte, 8+challengeNonceSize, 8+challengeNonceSize+sha256.Size)
+	binary.BigEndian.PutUint64(buf, uint64(issuedAt.UnixNano()))
+	if _, err := rand.Read(buf[8:]); err != nil {
+		return "", err
+	}
+	buf = append(buf, v.challengeMAC(key, buf)...)
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func (v *Validator) getChallengeKey() []byte {
+	v.challengeLock.Lock()
+	defer v.challengeLock.Unlock()
+	return v.challengeKey
+}
+
+// challengeMAC signs the timestamp and nonce of a challenge, along with the
+// name of the auth method so that it cannot be used with another one.
+func (v *Validator) challengeMAC(key, payload []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(v.name))
+	mac.Write([]byte{0})
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// verifyChallenge returns when the challenge expires if it was signed with the
+// challenge key and has not expired yet.
+func (v *Validator) verifyChallenge(challenge string) (time.Time, bool) {
+	key := v.getChallengeKey()
+	if len(key) == 0 {
+		return time.Time{}, false
+	}
+	buf, err := base64.RawURLEncoding.DecodeString(challenge)
+	if err != nil || len(buf) != 8+challengeNonceSize+sha256.Size {
+		return time.Time{}, false
+	}
+	payload, sum := buf[:8+challengeNonceSize], buf[8+challengeNonceSize:]
+	if !hmac.Equal(sum, v.challengeMAC(key, payload)) {
+		return time.Time{}, false
+	}
+
+	// Servers' clocks may drift apart, so challenges issued slightly in the
+	// future by another server are accepted too.
+	issuedAt := time.Unix(0, int64(binary.BigEndian.Uint64(payload)))
+	if age := time.Since(issuedAt); age > challengeTTL || age < -challengeTTL {
+		return time.Time{}, false
+	}
+	return issuedAt.Add(challengeTTL), true
+}
+
+// redeemChallenge records that the challenge was used to log in and reports
+// whether it was not used before. Only challenges proven to be signed by the
+// holder of a trusted certificate are recorded, and only until they expire.
+func (v *Validator) redeemChallenge(challenge string, expiresAt time.Time) bool {
+	v.challengeLock.Lock()
+	defer v.challengeLock.Unlock()
+
+	now := time.Now()
+	for c, exp := range v.redeemed {
+		if now.After(exp) {
+			delete(v.redeemed, c)
+		}
+	}
+	if _, ok := v.redeemed[challenge]; ok {
+		return false
+	}
+	v.redeemed[challenge] = expiresAt
+	return true
+}
+
+// ValidateLogin verifies the certificate chain of the login token against the
+// configured CA certificates, and that the challenge of the token was issued
+// by a server of the datacenter, has not been used yet and is signed with the
+// private key of the leaf certificate.
+func (v *Validator) ValidateLogin(_ context.Context, loginToken string) (*authmethod.Identity, error) {
+	token, err := decodeLoginToken(loginToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode login token: %v", err)
+	}
+	if len(token.Certificates) == 0 {
+		return nil, errors.New("no client certificate was presented")
+	}
+	expiresAt, ok := v.verifyChallenge(token.Challenge)
+	if !ok {
+		return nil, errors.New("invalid or expired login challenge")
+	}
+
+	certs := make([]*x509.Certificate, 0, len(token.Certificates))
+	for _, der := range token.Certificates {
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse client certificate: %v", err)
+		}
+		certs = append(certs, cert)
+	}
+
+	leaf := certs[0]
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err = leaf.Verify(x509.VerifyOptions{
+		Roots:         v.roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify client certificate: %v", err)
+	}
+
+	algorithm, err := signatureAlgorithm(leaf.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	signed := []byte(ChallengeSignaturePrefix + token.Challenge)
+	if err := leaf.CheckSignature(algorithm, signed, token.Signature); err != nil {
+		return nil, fmt.Errorf("failed to verify login challenge signature: %v", err)
+	}
+	if !v.redeemChallenge(token.Challenge, expiresAt) {
+		return nil, errors.New("invalid or expired login challenge")
+	}
+
+	fields := &x509FieldDetails{
+		Subject: x509FieldDetailsSubject{
+			CommonName:         leaf.Subject.CommonName,
+			Organization:       leaf.Subject.Organization,
+			OrganizationalUnit: leaf.Subject.OrganizationalUnit,
+		},
+		SAN: x509FieldDetailsSAN{
+			DNS:   leaf.DNSNames,
+			Email: leaf.EmailAddresses,
+		},
+	}
+	for _, ip := range leaf.IPAddresses {
+		fields.SAN.IP = append(fields.SAN.IP, ip.String())
+	}
+	for _, uri := range leaf.URIs {
+		fields.SAN.URI = append(fields.SAN.URI, uri.String())
+		if uri.Scheme == "spiffe" && fields.SPIFFE.ID == "" {
+			fields.SPIFFE = spiffeFieldDetails(uri)
+		}
+	}
+
+	id := v.NewIdentity()
+	id.SelectableFields = fields
+	id.ProjectedVars[subjectCommonNameField] = fields.Subject.CommonName
+	id.ProjectedVars[subjectOrganizationField] = first(fields.Subject.Organization)
+	id.ProjectedVars[subjectOrganizationalUnitField] = first(fields.Subject.OrganizationalUnit)
+	id.ProjectedVars[sanDNSField] = first(fields.SAN.DNS)
+	id.ProjectedVars[sanEmailField] = first(fields.SAN.Email)
+	id.ProjectedVars[sanURIField] = first(fields.SAN.URI)
+	id.ProjectedVars[spiffeIDField] = fields.SPIFFE.ID
+	id.ProjectedVars[spiffeTrustDomainField] = fields.SPIFFE.TrustDomain
+	id.ProjectedVars[spiffePathField] = fields.SPIFFE.Path
+	for i, segment := range fields.SPIFFE.Segments {
+		if i >= maxSPIFFESegments {
+			break
+		}
+		id.ProjectedVars[spiffeSegmentPrefix+strconv.Itoa(i)] = segment
+	}
+	return id, nil
+}
+
+// signatureAlgorithm returns the algorithm used to sign the challenge with the
+// private key matching the public key.
+func signatureAlgorithm(pub interface{}) (x509.SignatureAlgorithm, error) {
+	switch pub.(type) {
+	case *ecdsa.PublicKey:
+		return x509.ECDSAWithSHA256, nil
+	case *rsa.PublicKey:
+		return x509.SHA256WithRSA, nil
+	case ed25519.PublicKey:
+		return x509.PureEd25519, nil
+	default:
+		return x509.UnknownSignatureAlgorithm, fmt.Errorf("unsupported client certificate key type %T", pub)
+	}
+}
+
+// spiffeFieldDetails splits a SPIFFE ID such as
+// spiffe://example.org/ns/default/sa/web into its trust domain and path
+// segments.
+func spiffeFieldDetails(uri *url.URL) x509FieldDetailsSPIFFE {
+	details := x509FieldDetailsSPIFFE{
+		ID:          uri.String(),
+		TrustDomain: uri.Host,
+		Path:        uri.Path,
+	}
+	for _, segment := range strings.Split(strings.Trim(uri.Path, "/"), "/") {
+		if segment != "" {
+			details.Segments = append(details.Segments, segment)
+		}
+	}
+	return details
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func (v *Validator) NewIdentity() *authmethod.Identity {
+	id := &authmethod.Identity{
+		SelectableFields: &x509FieldDetails{},
+		ProjectedVars:    map[string]string{},
+	}
+	for _, f := range availableFields {
+		id.ProjectedVars[f] = ""
+	}
+	for i := 0; i < maxSPIFFESegments; i++ {
+		id.ProjectedVars[spiffeSegmentPrefix+strconv.Itoa(i)] = ""
+	}
+	return id
+}
+
+var availableFields = []string{
+	subjectCommonNameField,
+	subjectOrganizationField,
+	subjectOrganizationalUnitField,
+	sanDNSField,
+	sanEmailField,
+	sanURIField,
+	spiffeIDField,
+	spiffeTrustDomainField,
+	spiffePathField,
+}
+
+type x509FieldDetails struct {
+	Subject x509FieldDetailsSubject `bexpr:"subject"`
+	SAN     x509FieldDetailsSAN     `bexpr:"san"`
+	SPIFFE  x509FieldDetailsSPIFFE  `bexpr:"spiffe"`
+}
+
+type x509FieldDetailsSubject struct {
+	CommonName         string   `bexpr:"common_name"`
+	Organization       []string `bexpr:"organization"`
+	OrganizationalUnit []string `bexpr:"organizational_unit"`
+}
+
+type x509FieldDetailsSAN struct {
+	DNS   []string `bexpr:"dns"`
+	Email []string `bexpr:"email"`
+	URI   []string `bexpr:"uri"`
+	IP    []string `bexpr:"ip"`
+}
+
+type x509FieldDetailsSPIFFE struct {
+	ID          string   `bexpr:"id"`
+	TrustDomain string   `bexpr:"trust_domain"`
+	Path        string   `bexpr:"path"`
+	Segments    []string `bexpr:"segments"`
+}
@@ -0,0 +1,341 @@
+package x509auth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/consul/authmethod"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/sdk/testutil"
+)
+
+func testMethod(ca *TestCA, f func(config map[string]interface{})) *structs.ACLAuthMethod {
+	method := &structs.ACLAuthMethod{
+		Name:        "test-x509",
+		Description: "x509 test",
+		Type:        "x509",
+		Config: map[string]interface{}{
+			"CACerts": []string{ca.CertPEM},
+		},
+	}
+	if f != nil {
+		f(method.Config)
+	}
+	return method
+}
+
+func TestNewValidator(t *testing.T) {
+	ca := NewTestCA(t)
+
+	cases := map[string]struct {
+		method    *structs.ACLAuthMethod
+		expectErr string
+	}{
+		"wrong type": {func() *structs.ACLAuthMethod {
+			method := testMethod(ca, nil)
+			method.Type = "jwt"
+			return method
+		}(), "is not an x509 auth method"},
+		"missing CA certs": {testMethod(ca, func(config map[string]interface{}) {
+			delete(config, "CACerts")
+		}), "Config.CACerts is required"},
+		"invalid CA cert": {testMethod(ca, func(config map[string]interface{}) {
+			config["CACerts"] = []string{ca.CertPEM, "garbage"}
+		}), "Config.CACerts[1] does not contain a valid PEM certificate"},
+		"unknown config": {testMethod(ca, func(config map[string]interface{}) {
+			config["Extra"] = "config"
+		}), "has invalid keys"},
+	}
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			_, err := NewValidator(hclog.NewNullLogger(), tc.method)
+			testutil.RequireErrorContains(t, err, tc.expectErr)
+		})
+	}
+}
+
+func TestNewIdentity(t *testing.T) {
+	validator, err := NewValidator(hclog.NewNullLogger(), testMethod(NewTestCA(t), nil))
+	require.NoError(t, err)
+
+	id := validator.NewIdentity()
+	authmethod.RequireIdentityMatch(t, id, map[string]string{
+		"subject.common_name":         "",
+		"subject.organization":        "",
+		"subject.organizational_unit": "",
+		"san.dns":                     "",
+		"san.email":                   "",
+		"san.uri":                     "",
+		"spiffe.id":                   "",
+		"spiffe.trust_domain":         "",
+		"spiffe.path":                 "",
+		"spiffe.segment.0":            "",
+		"spiffe.segment.1":            "",
+		"spiffe.segment.2":            "",
+		"spiffe.segment.3":            "",
+		"spiffe.segment.4":            "",
+		"spiffe.segment.5":            "",
+		"spiffe.segment.6":            "",
+		"spiffe.segment.7":            "",
+	},
+		`subject.common_name == ""`,
+		`subject.organization is empty`,
+		`san.dns is empty`,
+		`spiffe.id == ""`,
+		`spiffe.segments is empty`,
+	)
+}
+
+func TestValidateLogin(t *testing.T) {
+	ca := NewTestCA(t)
+
+	validator, err := NewValidator(hclog.NewNullLogger(), testMethod(ca, nil))
+	require.NoError(t, err)
+	validator.SetChallengeKey([]byte("challenge-key"))
+
+	spiffeID, err := url.Parse("spiffe://example.org/ns/default/sa/web")
+	require.NoError(t, err)
+
+	leaf := ca.Issue(t, &x509.Certificate{
+		Subject: pkix.Name{
+			CommonName:         "web",
+			Organization:       []string{"Example"},
+			OrganizationalUnit: []string{"payments", "platform"},
+		},
+		DNSNames:       []string{"web.example.org", "web"},
+		EmailAddresses: []string{"web@example.org"},
+		IPAddresses:    []net.IP{net.ParseIP("10.0.0.1")},
+		URIs:           []*url.URL{spiffeID},
+	})
+
+	challenge := func(t *testing.T) string {
+		challenge, err := validator.Challenge()
+		require.NoError(t, err)
+		return challenge
+	}
+	login := func(t *testing.T, leaf *TestLeaf) (*authmethod.Identity, error) {
+		return validator.ValidateLogin(context.Background(), leaf.LoginToken(t, challenge(t)))
+	}
+	loginWithToken := func(t *testing.T, token *LoginToken) error {
+		encoded, err := token.Encode()
+		require.NoError(t, err)
+		_, err = validator.ValidateLogin(context.Background(), encoded)
+		return err
+	}
+
+	t.Run("malformed login token", func(t *testing.T) {
+		_, err := validator.ValidateLogin(context.Background(), "garbage")
+		testutil.RequireErrorContains(t, err, "failed to decode login token")
+	})
+
+	t.Run("no certificate", func(t *testing.T) {
+		err := loginWithToken(t, &LoginToken{Challenge: challenge(t)})
+		testutil.RequireErrorContains(t, err, "no client certificate was presented")
+	})
+
+	t.Run("malformed certificate", func(t *testing.T) {
+		err := loginWithToken(t, &LoginToken{
+			Challenge:    challenge(t),
+			Certificates: [][]byte{[]byte("garbage")},
+		})
+		testutil.RequireErrorContains(t, err, "failed to parse client certificate")
+	})
+
+	t.Run("unknown challenge", func(t *testing.T) {
+		_, err := validator.ValidateLogin(context.Background(), leaf.LoginToken(t, "unknown"))
+		testutil.RequireErrorContains(t, err, "invalid or expired login challenge")
+	})
+
+	t.Run("challenge used twice", func(t *testing.T) {
+		token := leaf.LoginToken(t, challenge(t))
+		_, err := validator.ValidateLogin(context.Background(), token)
+		require.NoError(t, err)
+
+		_, err = validator.ValidateLogin(context.Background(), token)
+		testutil.RequireErrorContains(t, err, "invalid or expired login challenge")
+	})
+
+	t.Run("expired challenge", func(t *testing.T) {
+		c, err := validator.newChallenge(time.Now().Add(-challengeTTL - time.Second))
+		require.NoError(t, err)
+
+		_, err = validator.ValidateLogin(context.Background(), leaf.LoginToken(t, c))
+		testutil.RequireErrorContains(t, err, "invalid or expired login challenge")
+	})
+
+	t.Run("challenge signed with another key", func(t *testing.T) {
+		other, err := NewValidator(hclog.NewNullLogger(), testMethod(ca, nil))
+		require.NoError(t, err)
+		other.SetChallengeKey([]byte("other-key"))
+		c, err := other.Challenge()
+		require.NoError(t, err)
+
+		_, err = validator.ValidateLogin(context.Background(), leaf.LoginToken(t, c))
+		testutil.RequireErrorContains(t, err, "invalid or expired login challenge")
+	})
+
+	t.Run("challenge of another auth method", func(t *testing.T) {
+		method := testMethod(ca, nil)
+		method.Name = "other-x509"
+		other, err := NewValidator(hclog.NewNullLogger(), method)
+		require.NoError(t, err)
+		other.SetChallengeKey([]byte("challenge-key"))
+		c, err := other.Challenge()
+		require.NoError(t, err)
+
+		_, err = validator.ValidateLogin(context.Background(), leaf.LoginToken(t, c))
+		testutil.RequireErrorContains(t, err, "invalid or expired login challenge")
+	})
+
+	t.Run("challenge verified by another validator", func(t *testing.T) {
+		// Any validator holding the key verifies the challenge, such as the
+		// one of a new leader.
+		other, err := NewValidator(hclog.NewNullLogger(), testMethod(ca, nil))
+		require.NoError(t, err)
+		other.SetChallengeKey([]byte("challenge-key"))
+
+		_, err = other.ValidateLogin(context.Background(), leaf.LoginToken(t, challenge(t)))
+		require.NoError(t, err)
+	})
+
+	t.Run("challenge signed by another key", func(t *testing.T) {
+		other := ca.Issue(t, &x509.Certificate{
+			Subject: pkix.Name{CommonName: "other"},
+		})
+		stolen := tls.Certificate{
+			Certificate: [][]byte{leaf.DER},
+			PrivateKey:  other.KeyPair(t).PrivateKey,
+		}
+		token, err := api.NewX509LoginToken(challenge(t), stolen)
+		require.NoError(t, err)
+
+		_, err = validator.ValidateLogin(context.Background(), token)
+		testutil.RequireErrorContains(t, err, "failed to verify login challenge signature")
+	})
+
+	t.Run("untrusted CA", func(t *testing.T) {
+		other := NewTestCA(t).Issue(t, &x509.Certificate{
+			Subject: pkix.Name{CommonName: "web"},
+		})
+		_, err := login(t, other)
+		testutil.RequireErrorContains(t, err, "failed to verify client certificate")
+	})
+
+	t.Run("expired certificate", func(t *testing.T) {
+		expired := ca.Issue(t, &x509.Certificate{
+			Subject:   pkix.Name{CommonName: "web"},
+			NotBefore: time.Now().Add(-2 * time.Hour),
+			NotAfter:  time.Now().Add(-time.Hour),
+		})
+		_, err := login(t, expired)
+		testutil.RequireErrorContains(t, err, "failed to verify client certificate")
+	})
+
+	t.Run("server certificate", func(t *testing.T) {
+		server := ca.Issue(t, &x509.Certificate{
+			Subject:     pkix.Name{CommonName: "web"},
+			ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		})
+		_, err := login(t, server)
+		testutil.RequireErrorContains(t, err, "failed to verify client certificate")
+	})
+
+	t.Run("valid certificate", func(t *testing.T) {
+		id, err := login(t, leaf)
+		require.NoError(t, err)
+
+		authmethod.RequireIdentityMatch(t, id, map[string]string{
+			"subject.common_name":         "web",
+			"subject.organization":        "Example",
+			"subject.organizational_unit": "payments",
+			"san.dns":                     "web.example.org",
+			"san.email":                   "web@example.org",
+			"san.uri":                     "spiffe://example.org/ns/default/sa/web",
+			"spiffe.id":                   "spiffe://example.org/ns/default/sa/web",
+			"spiffe.trust_domain":         "example.org",
+			"spiffe.path":                 "/ns/default/sa/web",
+			"spiffe.segment.0":            "ns",
+			"spiffe.segment.1":            "default",
+			"spiffe.segment.2":            "sa",
+			"spiffe.segment.3":            "web",
+			"spiffe.segment.4":            "",
+			"spiffe.segment.5":            "",
+			"spiffe.segment.6":            "",
+			"spiffe.segment.7":            "",
+		},
+			`subject.common_name == web`,
+			`platform in subject.organizational_unit`,
+			`web in san.dns`,
+			`"10.0.0.1" in san.ip`,
+			`spiffe.trust_domain == "example.org"`,
+			`sa in spiffe.segments`,
+		)
+	})
+
+	t.Run("valid certificate without SPIFFE ID", func(t *testing.T) {
+		plain := ca.Issue(t, &x509.Certificate{
+			Subject: pkix.Name{CommonName: "db"},
+		})
+		id, err := login(t, plain)
+		require.NoError(t, err)
+
+		require.Equal(t, "db", id.ProjectedVars["subject.common_name"])
+		require.Equal(t, "", id.ProjectedVars["spiffe.id"])
+		require.Equal(t, "", id.ProjectedVars["spiffe.segment.0"])
+	})
+}
+
+func TestValidator_Challenge(t *testing.T) {
+	ca := NewTestCA(t)
+	validator, err := NewValidator(hclog.NewNullLogger(), testMethod(ca, nil))
+	require.NoError(t, err)
+
+	_, err = validator.Challenge()
+	testutil.RequireErrorContains(t, err, "the login challenge key is not initialized yet")
+
+	validator.SetChallengeKey([]byte("challenge-key"))
+	first, err := validator.Challenge()
+	require.NoError(t, err)
+	second, err := validator.Challenge()
+	require.NoError(t, err)
+	require.NotEqual(t, first, second)
+
+	// Challenges are not kept by the validator.
+	require.Empty(t, validator.redeemed)
+
+	t.Run("tampered challenge", func(t *testing.T) {
+		buf, err := base64.RawURLEncoding.DecodeString(first)
+		require.NoError(t, err)
+		buf[0] ^= 1
+		_, ok := validator.verifyChallenge(base64.RawURLEncoding.EncodeToString(buf))
+		require.False(t, ok)
+	})
+
+	t.Run("redeemed challenges are pruned", func(t *testing.T) {
+		leaf := ca.Issue(t, &x509.Certificate{
+			Subject: pkix.Name{CommonName: "web"},
+		})
+		_, err := validator.ValidateLogin(context.Background(), leaf.LoginToken(t, first))
+		require.NoError(t, err)
+		require.Len(t, validator.redeemed, 1)
+
+		for c := range validator.redeemed {
+			validator.redeemed[c] = time.Now().Add(-time.Second)
+		}
+		_, err = validator.ValidateLogin(context.Background(), leaf.LoginToken(t, second))
+		require.NoError(t, err)
+		require.Len(t, validator.redeemed, 1)
+	})
+}
@@ -25,6 +25,10 @@ var AutopilotGauges = []prometheus.GaugeDefinition{
 		Name: []string{"autopilot", "healthy"},
 		Help: "Tracks the overall health of the local server cluster. 1 if all servers are healthy, 0 if one or more are unhealthy.",
 	},
+	{
+		Name: []string{"autopilot", "replication_lag"},
+		Help: "Tracks the number of logs committed by the leader that each server has yet to apply.",
+	},
 }
 
 // AutopilotDelegate is a Consul delegate for autopilot operations.
@@ -53,6 +57,13 @@ func (d *AutopilotDelegate) NotifyState(state *autopilot.State) {
 		} else {
 			metrics.SetGauge([]string{"autopilot", "healthy"}, 0)
 		}
+
+		for _, srv := range state.Servers {
+			if ext, ok := srv.Server.Ext.(*structs.AutopilotServerExt); ok && ext != nil {
+				metrics.SetGaugeWithLabels([]string{"autopilot", "replication_lag"}, float32(ext.ReplicationLag),
+					[]metrics.Label{{Name: "server", Value: srv.Server.Name}})
+			}
+		}
 	} else {
 
 		// if we are not a leader, emit NaN per
@@ -78,7 +89,7 @@ func (s *Server) initAutopilot(config *Config) {
 	apDelegate := &AutopilotDelegate{s}
 
 	s.autopilot = autopilot.New(
-		s.raft,
+		&autopilotRaft{Raft: s.raft, srv: s},
 		apDelegate,
 		autopilot.WithLogger(s.logger),
 		autopilot.WithReconcileInterval(config.AutopilotInterval),
@@ -90,6 +101,34 @@ func (s *Server) initAutopilot(config *Config) {
 	metrics.SetGauge([]string{"autopilot", "failure_tolerance"}, float32(math.NaN()))
 }
 
+// autopilotRaft gives up the leader lease before autopilot transfers the
+// leadership.
+type autopilotRaft struct {
+	*raft.Raft
+	srv *Server
+}
+
+func (r *autopilotRaft) LeadershipTransferToServer(id raft.ServerID, address raft.ServerAddress) raft.Future {
+	return r.srv.transferLeadership(id, address)
+}
+
+// replicationLag returns the number of logs committed by the leader that a
+// server has yet to apply, as of the last stats fetched from the servers.
+func (s *Server) replicationLag(id raft.ServerID) (uint64, bool) {
+	leader, ok := s.statsFetcher.LastStats(raft.ServerID(s.config.NodeID))
+	if !ok {
+		return 0, false
+	}
+	srv, ok := s.statsFetcher.LastStats(id)
+	if !ok {
+		return 0, false
+	}
+	if srv.AppliedIndex >= leader.CommitIndex {
+		return 0, true
+	}
+	return leader.CommitIndex - srv.AppliedIndex, true
+}
+
 func (s *Server) autopilotServers() map[raft.ServerID]*autopilot.Server {
 	servers := make(map[raft.ServerID]*autopilot.Server)
 	for _, member := range s.serfLAN.Members() {
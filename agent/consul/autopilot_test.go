@@ -522,3 +522,66 @@ func TestAutopilot_MinQuorum(t *testing.T) {
 		}
 	})
 }
+
+func TestAutopilot_ReadReplica(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.Datacenter = "dc1"
+		c.Bootstrap = true
+		c.AutopilotConfig.ServerStabilizationTime = 200 * time.Millisecond
+		c.ServerHealthInterval = 100 * time.Millisecond
+		c.AutopilotInterval = 100 * time.Millisecond
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	dir2, s2 := testServerWithConfig(t, func(c *Config) {
+		c.Datacenter = "dc1"
+		c.Bootstrap = false
+		c.ReadReplica = true
+	})
+	defer os.RemoveAll(dir2)
+	defer s2.Shutdown()
+	joinLAN(t, s2, s1)
+
+	// Wait for the read replica to be healthy and stable for long enough
+	// that it would have been promoted if it was a regular server.
+	retry.Run(t, func(r *retry.R) {
+		health := s1.autopilot.GetServerHealth(s2.config.RaftConfig.LocalID)
+		if health == nil {
+			r.Fatal("nil health")
+		}
+		if !health.Healthy {
+			r.Fatalf("bad: %v", health)
+		}
+		if time.Since(health.StableSince) < 4*s1.config.AutopilotConfig.ServerStabilizationTime {
+			r.Fatal("stable period not elapsed")
+		}
+	})
+
+	future := s1.raft.GetConfiguration()
+	require.NoError(t, future.Error())
+	servers := future.Configuration().Servers
+	require.Len(t, servers, 2)
+	for _, srv := range servers {
+		if srv.ID == s2.config.RaftConfig.LocalID {
+			require.Equal(t, raft.Nonvoter, srv.Suffrage)
+		}
+	}
+
+	state := s1.autopilot.GetState()
+	srv, ok := state.Servers[s2.config.RaftConfig.LocalID]
+	require.True(t, ok)
+	require.Equal(t, autopilotNodeTypeReadReplica, srv.Server.NodeType)
+	ext, ok := srv.Server.Ext.(*structs.AutopilotServerExt)
+	require.True(t, ok)
+	require.True(t, ext.ReadReplica)
+
+	_, ok = s1.replicationLag(s2.config.RaftConfig.LocalID)
+	require.True(t, ok)
+}
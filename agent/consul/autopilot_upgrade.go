@@ -0,0 +1,220 @@
+package consul
+
+import (
+	"sort"
+	"time"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/raft"
+	autopilot "github.com/hashicorp/raft-autopilot"
+
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+// upgradePromoter is an autopilot promoter performing upgrade migrations.
+// Servers of a newer version than the current voters are kept as non-voters
+// until there are as many of them as voters of other versions, they are then
+// promoted together, the voters of other versions are demoted and leadership
+// is transferred to a server of the newer version. Migrations only happen
+// when an upgrade version tag is configured, outside of them it promotes
+// stable servers like the default promoter of autopilot. Read replicas are
+// never promoted.
+type upgradePromoter struct {
+	autopilot.StablePromoter
+
+	// replicationLag returns the replication lag of a server, it is optional.
+	replicationLag func(id raft.ServerID) (uint64, bool)
+}
+
+// autopilotNodeTypeReadReplica is the node type of read replicas.
+const autopilotNodeTypeReadReplica autopilot.NodeType = "read-replica"
+
+func (p *upgradePromoter) GetServerExt(c *autopilot.Config, srv *autopilot.ServerState) interface{} {
+	ext := &structs.AutopilotServerExt{
+		ReadReplica:    isReadReplica(&srv.Server),
+		UpgradeVersion: upgradeVersion(upgradeConfig(c), &srv.Server),
+	}
+	if p.replicationLag != nil {
+		ext.ReplicationLag, _ = p.replicationLag(srv.Server.ID)
+	}
+	return ext
+}
+
+func (p *upgradePromoter) GetNodeTypes(_ *autopilot.Config, s *autopilot.State) map[raft.ServerID]autopilot.NodeType {
+	types := make(map[raft.ServerID]autopilot.NodeType)
+	for id, srv := range s.Servers {
+		if isReadReplica(&srv.Server) {
+			types[id] = autopilotNodeTypeReadReplica
+		} else {
+			types[id] = autopilot.NodeVoter
+		}
+	}
+	return types
+}
+
+func (p *upgradePromoter) GetStateExt(c *autopilot.Config, s *autopilot.State) interface{} {
+	upgrade, _ := planUpgrade(c, s, time.Now())
+	return &structs.AutopilotStateExt{Upgrade: upgrade}
+}
+
+func (p *upgradePromoter) CalculatePromotionsAndDemotions(c *autopilot.Config, s *autopilot.State) autopilot.RaftChanges {
+	_, changes := planUpgrade(c, s, time.Now())
+	return changes
+}
+
+func upgradeConfig(c *autopilot.Config) *structs.AutopilotUpgradeConfig {
+	if c != nil {
+		if conf, ok := c.Ext.(*structs.AutopilotUpgradeConfig); ok && conf != nil {
+			return conf
+		}
+	}
+	return &structs.AutopilotUpgradeConfig{}
+}
+
+// isReadReplica returns whether the server is a read replica.
+func isReadReplica(srv *autopilot.Server) bool {
+	ext, ok := srv.Ext.(*structs.AutopilotServerExt)
+	return ok && ext != nil && ext.ReadReplica
+}
+
+// upgradeVersion returns the version of a server used by upgrade migrations.
+func upgradeVersion(conf *structs.AutopilotUpgradeConfig, srv *autopilot.Server) string {
+	if conf.UpgradeVersionTag != "" {
+		return srv.Meta[conf.UpgradeVersionTag]
+	}
+	return srv.Version
+}
+
+// planUpgrade returns the state of the upgrade migration along with the
+// changes to the Raft configuration it requires. The target version of the
+// migration is the highest version of the alive servers, servers without a
+// valid version are never part of it. Migrations are disabled unless an
+// upgrade version tag is configured. Read replicas are kept as non-voters and
+// don't take part in the migration.
+func planUpgrade(c *autopilot.Config, s *autopilot.State, now time.Time) (*structs.AutopilotUpgrade, autopilot.RaftChanges) {
+	conf := upgradeConfig(c)
+
+	versions := make(map[raft.ServerID]*version.Version)
+	var target *version.Version
+	for id, srv := range s.Servers {
+		v, err := version.NewVersion(upgradeVersion(conf, &srv.Server))
+		if err != nil {
+			continue
+		}
+		versions[id] = v
+		if isReadReplica(&srv.Server) {
+			continue
+		}
+		if srv.Server.NodeStatus == autopilot.NodeAlive && (target == nil || v.GreaterThan(target)) {
+			target = v
+		}
+	}
+
+	upgrade := &structs.AutopilotUpgrade{Status: structs.AutopilotUpgradeIdle}
+	if target != nil {
+		upgrade.TargetVersion = target.Original()
+	}
+
+	minStableDuration := s.ServerStabilizationTime(c)
+	var stableTargetNonVoters, stableNonVoters []raft.ServerID
+	targetVotersHealthy := true
+	for id, srv := range s.Servers {
+		isTarget := target != nil && versions[id] != nil && versions[id].Equal(target)
+		if isReadReplica(&srv.Server) && !srv.HasVotingRights() {
+			if isTarget {
+				upgrade.TargetVersionReadReplicas = append(upgrade.TargetVersionReadReplicas, string(id))
+			} else {
+				upgrade.OtherVersionReadReplicas = append(upgrade.OtherVersionReadReplicas, string(id))
+			}
+			continue
+		}
+
+		stable := srv.State == autopilot.RaftNonVoter && srv.Health.IsStable(now, minStableDuration)
+		if stable {
+			stableNonVoters = append(stableNonVoters, id)
+		}
+
+		switch {
+		case isTarget && srv.HasVotingRights():
+			upgrade.TargetVersionVoters = append(upgrade.TargetVersionVoters, string(id))
+			if !srv.Health.Healthy {
+				targetVotersHealthy = false
+			}
+		case isTarget:
+			upgrade.TargetVersionNonVoters = append(upgrade.TargetVersionNonVoters, string(id))
+			if stable {
+				stableTargetNonVoters = append(stableTargetNonVoters, id)
+			}
+		case srv.HasVotingRights():
+			upgrade.OtherVersionVoters = append(upgrade.OtherVersionVoters, string(id))
+		default:
+			upgrade.OtherVersionNonVoters = append(upgrade.OtherVersionNonVoters, string(id))
+		}
+	}
+	sort.Strings(upgrade.TargetVersionVoters)
+	sort.Strings(upgrade.TargetVersionNonVoters)
+	sort.Strings(upgrade.TargetVersionReadReplicas)
+	sort.Strings(upgrade.OtherVersionVoters)
+	sort.Strings(upgrade.OtherVersionNonVoters)
+	sort.Strings(upgrade.OtherVersionReadReplicas)
+
+	var changes autopilot.RaftChanges
+	switch {
+	case conf.DisableUpgradeMigration || conf.UpgradeVersionTag == "":
+		// Upgrade migrations are opt-in, servers upgraded in place keep
+		// their voting rights and new servers are promoted once stable.
+		upgrade.Status = structs.AutopilotUpgradeDisabled
+		changes.Promotions = stableNonVoters
+		return upgrade, changes
+
+	case target == nil:
+		// Without any version to upgrade to there is nothing to migrate.
+		changes.Promotions = stableNonVoters
+		return upgrade, changes
+
+	case len(upgrade.OtherVersionVoters) == 0:
+		// Only the servers of the target version can vote, the others
+		// must be replaced rather than promoted.
+		if len(upgrade.OtherVersionNonVoters) > 0 {
+			upgrade.Status = structs.AutopilotUpgradeAwaitServerRemoval
+		}
+		changes.Promotions = stableTargetNonVoters
+		return upgrade, changes
+	}
+
+	// The servers of the target version replace the voters of the other
+	// versions once there are enough of them to take over.
+	replacements := len(upgrade.TargetVersionVoters) + len(stableTargetNonVoters)
+	if replacements < len(upgrade.OtherVersionVoters) {
+		upgrade.Status = structs.AutopilotUpgradeAwaitNewVoters
+		return upgrade, changes
+	}
+
+	if len(stableTargetNonVoters) > 0 {
+		upgrade.Status = structs.AutopilotUpgradePromoting
+		changes.Promotions = stableTargetNonVoters
+		return upgrade, changes
+	}
+
+	// Wait for the new voters to be healthy before giving up on the old ones.
+	if !targetVotersHealthy {
+		upgrade.Status = structs.AutopilotUpgradePromoting
+		return upgrade, changes
+	}
+
+	for _, id := range upgrade.OtherVersionVoters {
+		if raft.ServerID(id) != s.Leader {
+			changes.Demotions = append(changes.Demotions, raft.ServerID(id))
+		}
+	}
+	if len(changes.Demotions) > 0 {
+		upgrade.Status = structs.AutopilotUpgradeDemoting
+		return upgrade, changes
+	}
+
+	// Only the leader is left to demote, it must first hand over leadership
+	// to a server of the target version.
+	upgrade.Status = structs.AutopilotUpgradeLeaderTransfer
+	changes.Leader = raft.ServerID(upgrade.TargetVersionVoters[0])
+	return upgrade, changes
+}
@@ -0,0 +1,352 @@
+package consul
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/raft"
+	autopilot "github.com/hashicorp/raft-autopilot"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+func testUpgradeState(leader raft.ServerID, servers ...*autopilot.ServerState) *autopilot.State {
+	state := &autopilot.State{
+		Leader:  leader,
+		Servers: make(map[raft.ServerID]*autopilot.ServerState),
+	}
+	for _, srv := range servers {
+		if srv.Server.ID == leader {
+			srv.State = autopilot.RaftLeader
+		}
+		if srv.HasVotingRights() {
+			state.Voters = append(state.Voters, srv.Server.ID)
+		}
+		state.Servers[srv.Server.ID] = srv
+	}
+	return state
+}
+
+func testUpgradeServer(id, version string, raftState autopilot.RaftState) *autopilot.ServerState {
+	return &autopilot.ServerState{
+		Server: autopilot.Server{
+			ID:         raft.ServerID(id),
+			NodeStatus: autopilot.NodeAlive,
+			Version:    version,
+			Meta:       map[string]string{"upgrade_version": version},
+		},
+		State: raftState,
+		Health: autopilot.ServerHealth{
+			Healthy:     true,
+			StableSince: time.Now().Add(-time.Hour),
+		},
+	}
+}
+
+func TestUpgradePromoter_PlanUpgrade(t *testing.T) {
+	config := &autopilot.Config{
+		ServerStabilizationTime: 10 * time.Second,
+		Ext:                     &structs.AutopilotUpgradeConfig{UpgradeVersionTag: "upgrade_version"},
+	}
+
+	type testcase struct {
+		config   *autopilot.Config
+		state    *autopilot.State
+		expected *structs.AutopilotUpgrade
+		changes  autopilot.RaftChanges
+	}
+
+	cases := map[string]testcase{
+		"idle": {
+			state: testUpgradeState("a",
+				testUpgradeServer("a", "1.9.0", autopilot.RaftVoter),
+				testUpgradeServer("b", "1.9.0", autopilot.RaftVoter),
+				testUpgradeServer("c", "1.9.0", autopilot.RaftNonVoter),
+			),
+			expected: &structs.AutopilotUpgrade{
+				Status:                 structs.AutopilotUpgradeIdle,
+				TargetVersion:          "1.9.0",
+				TargetVersionVoters:    []string{"a", "b"},
+				TargetVersionNonVoters: []string{"c"},
+			},
+			changes: autopilot.RaftChanges{Promotions: []raft.ServerID{"c"}},
+		},
+		"await new voters": {
+			state: testUpgradeState("a",
+				testUpgradeServer("a", "1.9.0", autopilot.RaftVoter),
+				testUpgradeServer("b", "1.9.0", autopilot.RaftVoter),
+				testUpgradeServer("c", "1.9.0", autopilot.RaftVoter),
+				testUpgradeServer("d", "1.10.0", autopilot.RaftNonVoter),
+				testUpgradeServer("e", "1.10.0", autopilot.RaftNonVoter),
+			),
+			expected: &structs.AutopilotUpgrade{
+				Status:                 structs.AutopilotUpgradeAwaitNewVoters,
+				TargetVersion:          "1.10.0",
+				TargetVersionNonVoters: []string{"d", "e"},
+				OtherVersionVoters:     []string{"a", "b", "c"},
+			},
+		},
+		"promoting": {
+			state: testUpgradeState("a",
+				testUpgradeServer("a", "1.9.0", autopilot.RaftVoter),
+				testUpgradeServer("b", "1.9.0", autopilot.RaftVoter),
+				testUpgradeServer("c", "1.9.0", autopilot.RaftVoter),
+				testUpgradeServer("d", "1.10.0", autopilot.RaftNonVoter),
+				testUpgradeServer("e", "1.10.0", autopilot.RaftNonVoter),
+				testUpgradeServer("f", "1.10.0", autopilot.RaftNonVoter),
+			),
+			expected: &structs.AutopilotUpgrade{
+				Status:                 structs.AutopilotUpgradePromoting,
+				TargetVersion:          "1.10.0",
+				TargetVersionNonVoters: []string{"d", "e", "f"},
+				OtherVersionVoters:     []string{"a", "b", "c"},
+			},
+			changes: autopilot.RaftChanges{Promotions: []raft.ServerID{"d", "e", "f"}},
+		},
+		"demoting": {
+			state: testUpgradeState("a",
+				testUpgradeServer("a", "1.9.0", autopilot.RaftVoter),
+				testUpgradeServer("b", "1.9.0", autopilot.RaftVoter),
+				testUpgradeServer("c", "1.9.0", autopilot.RaftVoter),
+				testUpgradeServer("d", "1.10.0", autopilot.RaftVoter),
+				testUpgradeServer("e", "1.10.0", autopilot.RaftVoter),
+				testUpgradeServer("f", "1.10.0", autopilot.RaftVoter),
+			),
+			expected: &structs.AutopilotUpgrade{
+				Status:              structs.AutopilotUpgradeDemoting,
+				TargetVersion:       "1.10.0",
+				TargetVersionVoters: []string{"d", "e", "f"},
+				OtherVersionVoters:  []string{"a", "b", "c"},
+			},
+			changes: autopilot.RaftChanges{Demotions: []raft.ServerID{"b", "c"}},
+		},
+		"leader transfer": {
+			state: testUpgradeState("a",
+				testUpgradeServer("a", "1.9.0", autopilot.RaftVoter),
+				testUpgradeServer("b", "1.9.0", autopilot.RaftNonVoter),
+				testUpgradeServer("c", "1.9.0", autopilot.RaftNonVoter),
+				testUpgradeServer("d", "1.10.0", autopilot.RaftVoter),
+				testUpgradeServer("e", "1.10.0", autopilot.RaftVoter),
+				testUpgradeServer("f", "1.10.0", autopilot.RaftVoter),
+			),
+			expected: &structs.AutopilotUpgrade{
+				Status:                structs.AutopilotUpgradeLeaderTransfer,
+				TargetVersion:         "1.10.0",
+				TargetVersionVoters:   []string{"d", "e", "f"},
+				OtherVersionVoters:    []string{"a"},
+				OtherVersionNonVoters: []string{"b", "c"},
+			},
+			changes: autopilot.RaftChanges{Leader: "d"},
+		},
+		"await server removal": {
+			state: testUpgradeState("d",
+				testUpgradeServer("a", "1.9.0", autopilot.RaftNonVoter),
+				testUpgradeServer("d", "1.10.0", autopilot.RaftVoter),
+				testUpgradeServer("e", "1.10.0", autopilot.RaftVoter),
+				testUpgradeServer("f", "1.10.0", autopilot.RaftVoter),
+			),
+			expected: &structs.AutopilotUpgrade{
+				Status:                structs.AutopilotUpgradeAwaitServerRemoval,
+				TargetVersion:         "1.10.0",
+				TargetVersionVoters:   []string{"d", "e", "f"},
+				OtherVersionNonVoters: []string{"a"},
+			},
+		},
+		"version tag": {
+			config: &autopilot.Config{
+				ServerStabilizationTime: 10 * time.Second,
+				Ext:                     &structs.AutopilotUpgradeConfig{UpgradeVersionTag: "upgrade_version"},
+			},
+			state: func() *autopilot.State {
+				a := testUpgradeServer("a", "1", autopilot.RaftVoter)
+				b := testUpgradeServer("b", "2", autopilot.RaftNonVoter)
+				// The Consul version is ignored when using a tag.
+				a.Server.Version = "1.10.0"
+				b.Server.Version = "1.9.0"
+				return testUpgradeState("a", a, b)
+			}(),
+			expected: &structs.AutopilotUpgrade{
+				Status:                 structs.AutopilotUpgradePromoting,
+				TargetVersion:          "2",
+				TargetVersionNonVoters: []string{"b"},
+				OtherVersionVoters:     []string{"a"},
+			},
+			changes: autopilot.RaftChanges{Promotions: []raft.ServerID{"b"}},
+		},
+		"no version tag": {
+			config: &autopilot.Config{
+				ServerStabilizationTime: 10 * time.Second,
+				Ext:                     &structs.AutopilotUpgradeConfig{},
+			},
+			state: testUpgradeState("a",
+				testUpgradeServer("a", "1.9.0", autopilot.RaftVoter),
+				testUpgradeServer("b", "1.10.0", autopilot.RaftNonVoter),
+			),
+			expected: &structs.AutopilotUpgrade{
+				Status:                 structs.AutopilotUpgradeDisabled,
+				TargetVersion:          "1.10.0",
+				TargetVersionNonVoters: []string{"b"},
+				OtherVersionVoters:     []string{"a"},
+			},
+			changes: autopilot.RaftChanges{Promotions: []raft.ServerID{"b"}},
+		},
+		"disabled": {
+			config: &autopilot.Config{
+				ServerStabilizationTime: 10 * time.Second,
+				Ext: &structs.AutopilotUpgradeConfig{
+					DisableUpgradeMigration: true,
+					UpgradeVersionTag:       "upgrade_version",
+				},
+			},
+			state: testUpgradeState("a",
+				testUpgradeServer("a", "1.9.0", autopilot.RaftVoter),
+				testUpgradeServer("b", "1.10.0", autopilot.RaftNonVoter),
+			),
+			expected: &structs.AutopilotUpgrade{
+				Status:                 structs.AutopilotUpgradeDisabled,
+				TargetVersion:          "1.10.0",
+				TargetVersionNonVoters: []string{"b"},
+				OtherVersionVoters:     []string{"a"},
+			},
+			changes: autopilot.RaftChanges{Promotions: []raft.ServerID{"b"}},
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			conf := tc.config
+			if conf == nil {
+				conf = config
+			}
+			upgrade, changes := planUpgrade(conf, tc.state, time.Now())
+			require.Equal(t, tc.expected, upgrade)
+			require.ElementsMatch(t, tc.changes.Promotions, changes.Promotions)
+			require.ElementsMatch(t, tc.changes.Demotions, changes.Demotions)
+			require.Equal(t, tc.changes.Leader, changes.Leader)
+		})
+	}
+}
+
+func TestUpgradePromoter_UnstableServers(t *testing.T) {
+	config := &autopilot.Config{
+		ServerStabilizationTime: 10 * time.Second,
+		Ext:                     &structs.AutopilotUpgradeConfig{UpgradeVersionTag: "upgrade_version"},
+	}
+
+	a := testUpgradeServer("a", "1.9.0", autopilot.RaftVoter)
+	b := testUpgradeServer("b", "1.10.0", autopilot.RaftNonVoter)
+	b.Health.StableSince = time.Now()
+	state := testUpgradeState("a", a, b)
+
+	// A server of the target version only counts once it is stable.
+	upgrade, changes := planUpgrade(config, state, time.Now())
+	require.Equal(t, structs.AutopilotUpgradeAwaitNewVoters, upgrade.Status)
+	require.Empty(t, changes.Promotions)
+
+	// Voters of other versions are kept until the new voters are healthy.
+	b.State = autopilot.RaftVoter
+	b.Health.Healthy = false
+	upgrade, changes = planUpgrade(config, state, time.Now())
+	require.Equal(t, structs.AutopilotUpgradePromoting, upgrade.Status)
+	require.Empty(t, changes.Demotions)
+	require.Empty(t, changes.Leader)
+}
+
+func TestUpgradePromoter_InPlaceUpgrade(t *testing.T) {
+	// Without an upgrade version tag the servers of a cluster upgraded in
+	// place, one at a time, keep their voting rights.
+	config := &autopilot.Config{
+		ServerStabilizationTime: 10 * time.Second,
+		Ext:                     &structs.AutopilotUpgradeConfig{},
+	}
+
+	servers := []*autopilot.ServerState{
+		testUpgradeServer("a", "1.9.0", autopilot.RaftVoter),
+		testUpgradeServer("b", "1.9.0", autopilot.RaftVoter),
+		testUpgradeServer("c", "1.9.0", autopilot.RaftVoter),
+	}
+	for _, srv := range servers {
+		srv.Server.Version = "1.10.0"
+		srv.Server.Meta["upgrade_version"] = "1.10.0"
+
+		upgrade, changes := planUpgrade(config, testUpgradeState("a", servers...), time.Now())
+		require.Equal(t, structs.AutopilotUpgradeDisabled, upgrade.Status)
+		require.Len(t, upgrade.TargetVersionVoters, len(servers)-len(upgrade.OtherVersionVoters))
+		require.Empty(t, changes.Demotions, "server %s", srv.Server.ID)
+		require.Empty(t, changes.Leader, "server %s", srv.Server.ID)
+	}
+}
+
+func TestUpgradePromoter_ReadReplicas(t *testing.T) {
+	config := &autopilot.Config{
+		ServerStabilizationTime: 10 * time.Second,
+		Ext:                     &structs.AutopilotUpgradeConfig{UpgradeVersionTag: "upgrade_version"},
+	}
+
+	readReplica := func(id, version string) *autopilot.ServerState {
+		srv := testUpgradeServer(id, version, autopilot.RaftNonVoter)
+		srv.Server.Ext = &structs.AutopilotServerExt{ReadReplica: true}
+		return srv
+	}
+
+	t.Run("never promoted", func(t *testing.T) {
+		state := testUpgradeState("a",
+			testUpgradeServer("a", "1.9.0", autopilot.RaftVoter),
+			testUpgradeServer("b", "1.9.0", autopilot.RaftNonVoter),
+			readReplica("c", "1.9.0"),
+		)
+
+		upgrade, changes := planUpgrade(config, state, time.Now())
+		require.Equal(t, structs.AutopilotUpgradeIdle, upgrade.Status)
+		require.Equal(t, []string{"c"}, upgrade.TargetVersionReadReplicas)
+		require.Equal(t, []raft.ServerID{"b"}, changes.Promotions)
+
+		types := new(upgradePromoter).GetNodeTypes(config, state)
+		require.Equal(t, autopilot.NodeVoter, types["b"])
+		require.Equal(t, autopilotNodeTypeReadReplica, types["c"])
+	})
+
+	t.Run("not a target version", func(t *testing.T) {
+		// A newer read replica doesn't start a migration of the voters.
+		state := testUpgradeState("a",
+			testUpgradeServer("a", "1.9.0", autopilot.RaftVoter),
+			testUpgradeServer("b", "1.9.0", autopilot.RaftNonVoter),
+			readReplica("c", "1.10.0"),
+		)
+
+		upgrade, changes := planUpgrade(config, state, time.Now())
+		require.Equal(t, structs.AutopilotUpgradeIdle, upgrade.Status)
+		require.Equal(t, "1.9.0", upgrade.TargetVersion)
+		require.Equal(t, []string{"c"}, upgrade.OtherVersionReadReplicas)
+		require.Equal(t, []raft.ServerID{"b"}, changes.Promotions)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		state := testUpgradeState("a",
+			testUpgradeServer("a", "1.9.0", autopilot.RaftVoter),
+			readReplica("c", "1.9.0"),
+		)
+
+		for _, conf := range []*structs.AutopilotUpgradeConfig{
+			{DisableUpgradeMigration: true, UpgradeVersionTag: "upgrade_version"},
+			{},
+		} {
+			_, changes := planUpgrade(&autopilot.Config{Ext: conf}, state, time.Now())
+			require.Empty(t, changes.Promotions)
+		}
+	})
+
+	t.Run("server ext", func(t *testing.T) {
+		p := &upgradePromoter{replicationLag: func(id raft.ServerID) (uint64, bool) {
+			return 7, true
+		}}
+		ext := p.GetServerExt(config, readReplica("c", "1.9.0"))
+		require.Equal(t, &structs.AutopilotServerExt{
+			ReadReplica:    true,
+			UpgradeVersion: "1.9.0",
+			ReplicationLag: 7,
+		}, ext)
+	})
+}
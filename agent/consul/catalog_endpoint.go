@@ -63,6 +63,10 @@ var CatalogSummaries = []prometheus.SummaryDefinition{
 		Name: []string{"catalog", "register"},
 		Help: "Measures the time it takes to complete a catalog register operation.",
 	},
+	{
+		Name: []string{"catalog", "renew_node"},
+		Help: "Measures the time it takes to complete a catalog node renew operation.",
+	},
 }
 
 // Catalog endpoint is used to manipulate the service catalog
@@ -104,6 +108,11 @@ func (c *Catalog) Register(args *structs.RegisterRequest, reply *struct{}) error
 	if args.Address == "" && !args.SkipNodeUpdate {
 		return fmt.Errorf("Must provide address if SkipNodeUpdate is not set")
 	}
+	if !args.SkipNodeUpdate {
+		if err := validateNodeTTL(args.NodeMeta); err != nil {
+			return err
+		}
+	}
 
 	// Handle a service registration.
 	if args.Service != nil {
@@ -140,8 +149,19 @@ func (c *Catalog) Register(args *structs.RegisterRequest, reply *struct{}) error
 		return err
 	}
 
-	_, err = c.srv.raftApply(structs.RegisterRequestType, args)
-	return err
+	if _, err := c.srv.raftApply(structs.RegisterRequestType, args); err != nil {
+		return err
+	}
+
+	// Every registration renews the TTL of the node, if it has one.
+	_, node, err := state.GetNode(args.Node, args.GetEnterpriseMeta())
+	if err != nil {
+		return fmt.Errorf("Node lookup failed: %v", err)
+	}
+	if node != nil {
+		return c.srv.resetNodeTimer(node)
+	}
+	return nil
 }
 
 // nodePreApply does the verification of a node before it is applied to Raft.
@@ -382,8 +402,68 @@ func (c *Catalog) Deregister(args *structs.DeregisterRequest, reply *struct{}) e
 		return err
 	}
 
-	_, err = c.srv.raftApply(structs.DeregisterRequestType, args)
-	return err
+	if _, err := c.srv.raftApply(structs.DeregisterRequestType, args); err != nil {
+		return err
+	}
+
+	if args.ServiceID == "" && args.CheckID == "" {
+		c.srv.clearNodeTimer(args.Node, &args.EnterpriseMeta)
+	}
+	return nil
+}
+
+// RenewNode is used to renew the TTL of a node registered with one.
+func (c *Catalog) RenewNode(args *structs.NodeSpecificRequest, reply *structs.IndexedNodes) error {
+	// The node timers are only tracked by the leader, so this must be sent
+	// to it even though we re-use a structure that supports stale reads.
+	args.AllowStale = false
+	if done, err := c.srv.ForwardRPC("Catalog.RenewNode", args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"catalog", "renew_node"}, time.Now())
+
+	// Verify the args
+	if args.Node == "" {
+		return fmt.Errorf("Must provide node")
+	}
+
+	// Fetch the ACL token, if any, and apply the policy.
+	var authzContext acl.AuthorizerContext
+	authz, err := c.srv.ResolveTokenAndDefaultMeta(args.Token, &args.EnterpriseMeta, &authzContext)
+	if err != nil {
+		return err
+	}
+
+	if err := c.srv.validateEnterpriseRequest(&args.EnterpriseMeta, true); err != nil {
+		return err
+	}
+
+	if authz.NodeWrite(args.Node, &authzContext) != acl.Allow {
+		return acl.ErrPermissionDenied
+	}
+
+	// Get the node, from local state.
+	index, node, err := c.srv.fsm.State().GetNode(args.Node, &args.EnterpriseMeta)
+	if err != nil {
+		return err
+	}
+
+	reply.Index = index
+	if node == nil {
+		return nil
+	}
+
+	ttl, err := nodeTTL(node.Meta)
+	if err != nil {
+		return err
+	}
+	if ttl == 0 {
+		return fmt.Errorf("Node %q has no TTL", args.Node)
+	}
+
+	// Reset the node TTL timer.
+	reply.Nodes = structs.Nodes{node}
+	return c.srv.resetNodeTimer(node)
 }
 
 // vetDeregisterWithACL applies the given ACL's policy to the catalog update and
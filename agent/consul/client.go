@@ -14,6 +14,7 @@ import (
 	"github.com/hashicorp/serf/serf"
 	"golang.org/x/time/rate"
 
+	"github.com/hashicorp/consul/agent/metadata"
 	"github.com/hashicorp/consul/agent/pool"
 	"github.com/hashicorp/consul/agent/router"
 	"github.com/hashicorp/consul/agent/structs"
@@ -270,8 +271,21 @@ func (c *Client) RPC(method string, args interface{}, reply interface{}) error {
 	// TODO (slackpad) Plumb a deadline here with a context.
 	firstCheck := time.Now()
 
+	// Use the zero value for RPCInfo if the request doesn't implement RPCInfo
+	info, _ := args.(structs.RPCInfo)
+
+	// Stale reads within the local datacenter are sent to a read replica of
+	// the LAN segment when there is one, until one of them fails.
+	preferReadReplica := c.isLocalStaleRead(info)
+
 TRY:
-	manager, server := c.router.FindLANRoute()
+	var manager *router.Manager
+	var server *metadata.Server
+	if preferReadReplica {
+		manager, server = c.router.FindLANReadReplicaRoute()
+	} else {
+		manager, server = c.router.FindLANRoute()
+	}
 	if server == nil {
 		return structs.ErrNoServers
 	}
@@ -297,9 +311,10 @@ TRY:
 	)
 	metrics.IncrCounterWithLabels([]string{"client", "rpc", "failed"}, 1, []metrics.Label{{Name: "server", Value: server.Name}})
 	manager.NotifyFailedServer(server)
+	if server.ReadReplica {
+		preferReadReplica = false
+	}
 
-	// Use the zero value for RPCInfo if the request doesn't implement RPCInfo
-	info, _ := args.(structs.RPCInfo)
 	if retry := canRetry(info, rpcErr, firstCheck, c.config); !retry {
 		return rpcErr
 	}
@@ -314,6 +329,16 @@ TRY:
 	return rpcErr
 }
 
+// isLocalStaleRead returns whether the request is a read of the local
+// datacenter that can be served by any server.
+func (c *Client) isLocalStaleRead(info structs.RPCInfo) bool {
+	if info == nil || !info.IsRead() || !info.AllowStaleRead() {
+		return false
+	}
+	dc := info.RequestDatacenter()
+	return dc == "" || dc == c.config.Datacenter
+}
+
 // SnapshotRPC sends the snapshot request to one of the servers, reading from
 // the streaming input and writing to the streaming output depending on the
 // operation.
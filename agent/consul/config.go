@@ -107,8 +107,8 @@ type Config struct {
 	// RaftConfig is the configuration used for Raft in the local DC
 	RaftConfig *raft.Config
 
-	// (Enterprise-only) ReadReplica is used to prevent this server from being added
-	// as a voting member of the Raft cluster.
+	// ReadReplica is used to prevent this server from being added as a voting
+	// member of the Raft cluster.
 	ReadReplica bool
 
 	// NotifyListen is called after the RPC listener has been configured.
@@ -294,6 +294,11 @@ type Config struct {
 	// used to limit the amount of Raft bandwidth used for replication.
 	FederationStateReplicationApplyLimit int
 
+	// ACLTokenUsageUpdatePeriod controls how long a server batches the usage
+	// of ACL tokens before committing it. A larger period leads to fewer Raft
+	// transactions, but also the recorded last used times being more stale.
+	ACLTokenUsageUpdatePeriod time.Duration
+
 	// CoordinateUpdatePeriod controls how long a server batches coordinate
 	// updates before applying them in a Raft transaction. A larger period
 	// leads to fewer Raft transactions, but also the stored coordinates
@@ -393,6 +398,14 @@ type Config struct {
 
 	RaftBoltDBConfig RaftBoltDBConfig
 
+	// RaftLogStoreConfig selects and configures the backend storing the
+	// Raft logs.
+	RaftLogStoreConfig RaftLogStoreConfig
+
+	// LeaderLeaseReads configures serving consistent reads from the leader
+	// without confirming its leadership for each of them.
+	LeaderLeaseReads LeaderLeaseReadsConfig
+
 	// Embedded Consul Enterprise specific configuration
 	*EnterpriseConfig
 }
@@ -469,6 +482,7 @@ func DefaultConfig() *Config {
 		SessionTTLMin:                        10 * time.Second,
 		ACLTokenMinExpirationTTL:             1 * time.Minute,
 		ACLTokenMaxExpirationTTL:             24 * time.Hour,
+		ACLTokenUsageUpdatePeriod:            1 * time.Minute,
 
 		// These are tuned to provide a total throughput of 128 updates
 		// per second. If you update these, you should update the client-
@@ -609,3 +623,28 @@ type ReloadableConfig struct {
 type RaftBoltDBConfig struct {
 	NoFreelistSync bool
 }
+
+type RaftLogStoreConfig struct {
+	// Backend is the store of the Raft logs, either "boltdb" or "wal".
+	Backend string
+
+	WAL RaftWALConfig
+}
+
+type RaftWALConfig struct {
+	// SegmentSize is the size in bytes above which the write-ahead log starts
+	// a new segment file.
+	SegmentSize int
+}
+
+type LeaderLeaseReadsConfig struct {
+	// Enabled makes the leader serve consistent reads while it holds a lease
+	// on the leadership, renewed in the background, instead of confirming
+	// its leadership with a quorum of servers for each read.
+	Enabled bool
+
+	// MaxClockSkew bounds the difference between the clocks of the servers
+	// over a Raft heartbeat timeout. The lease is shortened by this much, it
+	// is never held if it is greater than the heartbeat timeout.
+	MaxClockSkew time.Duration
+}
@@ -90,6 +90,19 @@ func (c *ConfigEntry) Apply(args *structs.ConfigEntryRequest, reply *bool) error
 		return acl.ErrPermissionDenied
 	}
 
+	// The status of a canary is managed by the canary controller, so keep the
+	// current status unless the rollout itself changed, which restarts it.
+	if canary, ok := args.Entry.(*structs.CanaryConfigEntry); ok {
+		canary.Status = structs.CanaryStatus{}
+		_, existing, err := c.srv.fsm.State().ConfigEntry(nil, structs.Canary, canary.Name, &canary.EnterpriseMeta)
+		if err != nil {
+			return err
+		}
+		if prev, ok := existing.(*structs.CanaryConfigEntry); ok && prev.SameRollout(canary) {
+			canary.Status = prev.Status
+		}
+	}
+
 	if args.Op != structs.ConfigEntryUpsert && args.Op != structs.ConfigEntryUpsertCAS {
 		args.Op = structs.ConfigEntryUpsert
 	}
@@ -383,6 +396,7 @@ func (c *ConfigEntry) ResolveServiceConfig(args *structs.ServiceConfigRequest, r
 				thisReply.TransparentProxy = proxyConf.TransparentProxy
 				thisReply.MeshGateway = proxyConf.MeshGateway
 				thisReply.Expose = proxyConf.Expose
+				thisReply.AccessLogs = proxyConf.AccessLogs
 
 				// Extract the global protocol from proxyConf for upstream configs.
 				rawProtocol := proxyConf.Config["protocol"]
@@ -430,6 +444,9 @@ func (c *ConfigEntry) ResolveServiceConfig(args *structs.ServiceConfigRequest, r
 				if serviceConf.Mode != structs.ProxyModeDefault {
 					thisReply.Mode = serviceConf.Mode
 				}
+				if !serviceConf.AccessLogs.IsZero() {
+					thisReply.AccessLogs = serviceConf.AccessLogs
+				}
 			}
 
 			// First collect all upstreams into a set of seen upstreams.
@@ -993,6 +993,100 @@ func TestConfigEntry_ResolveServiceConfig_TransparentProxy(t *testing.T) {
 	}
 }
 
+func TestConfigEntry_ResolveServiceConfig_AccessLogs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	tt := []struct {
+		name    string
+		entries []structs.ConfigEntry
+		expect  structs.ServiceConfigResponse
+	}{
+		{
+			name: "from proxy-defaults",
+			entries: []structs.ConfigEntry{
+				&structs.ProxyConfigEntry{
+					Kind: structs.ProxyDefaults,
+					Name: structs.ProxyConfigGlobal,
+					AccessLogs: structs.AccessLogsConfig{
+						Enabled: true,
+						Type:    structs.StdErrLogSinkType,
+					},
+				},
+			},
+			expect: structs.ServiceConfigResponse{
+				AccessLogs: structs.AccessLogsConfig{
+					Enabled: true,
+					Type:    structs.StdErrLogSinkType,
+				},
+			},
+		},
+		{
+			name: "service-defaults overrides proxy-defaults",
+			entries: []structs.ConfigEntry{
+				&structs.ProxyConfigEntry{
+					Kind: structs.ProxyDefaults,
+					Name: structs.ProxyConfigGlobal,
+					AccessLogs: structs.AccessLogsConfig{
+						Enabled: true,
+						Type:    structs.StdErrLogSinkType,
+					},
+				},
+				&structs.ServiceConfigEntry{
+					Kind: structs.ServiceDefaults,
+					Name: "foo",
+					AccessLogs: structs.AccessLogsConfig{
+						Enabled:    true,
+						Type:       structs.FileLogSinkType,
+						Path:       "/var/log/foo.log",
+						TextFormat: "%RESPONSE_CODE%",
+					},
+				},
+			},
+			expect: structs.ServiceConfigResponse{
+				AccessLogs: structs.AccessLogsConfig{
+					Enabled:    true,
+					Type:       structs.FileLogSinkType,
+					Path:       "/var/log/foo.log",
+					TextFormat: "%RESPONSE_CODE%",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			dir1, s1 := testServer(t)
+			defer os.RemoveAll(dir1)
+			defer s1.Shutdown()
+
+			codec := rpcClient(t, s1)
+			defer codec.Close()
+
+			idx := uint64(1)
+			for _, conf := range tc.entries {
+				require.NoError(t, s1.fsm.State().EnsureConfigEntry(idx, conf))
+				idx++
+			}
+
+			args := structs.ServiceConfigRequest{
+				Name:       "foo",
+				Datacenter: "dc1",
+			}
+			var out structs.ServiceConfigResponse
+			require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConfigEntry.ResolveServiceConfig", &args, &out))
+
+			// Don't know what this is deterministically, so we grab it from the response
+			tc.expect.QueryMeta = out.QueryMeta
+
+			require.Equal(t, tc.expect, out)
+		})
+	}
+}
+
 func TestConfigEntry_ResolveServiceConfig_Upstreams(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
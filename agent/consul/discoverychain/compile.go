@@ -145,6 +145,10 @@ type compiler struct {
 	// or splitting appear in the compiled chain
 	usesAdvancedRoutingFeatures bool
 
+	// usesGRPCRouteMatches is set to true if a service-router uses gRPC
+	// match criteria, which are only valid for the grpc protocol.
+	usesGRPCRouteMatches bool
+
 	// disableAdvancedRoutingFeatures is set to true if overrideProtocol is set to tcp
 	disableAdvancedRoutingFeatures bool
 
@@ -275,6 +279,10 @@ func (c *compiler) compile() (*structs.CompiledDiscoveryChain, error) {
 		return nil, err
 	}
 
+	if err := c.validateSplitterMirrors(); err != nil {
+		return nil, err
+	}
+
 	for targetID := range c.loadedTargets {
 		if _, ok := c.retainedTargets[targetID]; !ok {
 			delete(c.loadedTargets, targetID)
@@ -290,6 +298,15 @@ func (c *compiler) compile() (*structs.CompiledDiscoveryChain, error) {
 		}
 	}
 
+	if c.usesGRPCRouteMatches && c.protocol != "grpc" {
+		return nil, &structs.ConfigEntryGraphError{
+			Message: fmt.Sprintf(
+				"discovery chain %q uses a protocol %q that does not permit gRPC route matches",
+				c.serviceName, c.protocol,
+			),
+		}
+	}
+
 	if c.overrideProtocol != "" {
 		if c.overrideProtocol != c.protocol {
 			c.protocol = c.overrideProtocol
@@ -421,6 +438,10 @@ func (c *compiler) flattenAdjacentSplitterNodes() error {
 						Definition: newDef,
 						Weight:     structs.NormalizeServiceSplitWeight(effectiveWeight),
 						NextNode:   innerSplit.NextNode,
+						MirrorNode: innerSplit.MirrorNode,
+					}
+					if newDiscoverySplit.MirrorNode == "" {
+						newDiscoverySplit.MirrorNode = split.MirrorNode
 					}
 
 					fixedSplits = append(fixedSplits, newDiscoverySplit)
@@ -439,6 +460,38 @@ func (c *compiler) flattenAdjacentSplitterNodes() error {
 	}
 }
 
+// validateSplitterMirrors checks that all the splits of a splitter node mirror
+// requests the same way. Envoy mirrors the requests of a whole route, not the
+// ones sent to a single split, which splits of nested splitters could differ on
+// once flattened.
+func (c *compiler) validateSplitterMirrors() error {
+	mirrorPercentage := func(split *structs.DiscoverySplit) float32 {
+		if split.Definition == nil || split.Definition.Mirror == nil {
+			return 0
+		}
+		return split.Definition.Mirror.Percentage
+	}
+
+	for _, node := range c.nodes {
+		if node.Type != structs.DiscoveryGraphNodeTypeSplitter || len(node.Splits) == 0 {
+			continue
+		}
+		first := node.Splits[0]
+		for _, split := range node.Splits[1:] {
+			if split.MirrorNode != first.MirrorNode || mirrorPercentage(split) != mirrorPercentage(first) {
+				return &structs.ConfigEntryGraphError{
+					Message: fmt.Sprintf(
+						"discovery chain %q mirrors the requests of only some of the splits of %q; "+
+							"requests are mirrored regardless of the split they are sent to so every split must have the same mirror",
+						c.serviceName, node.Name,
+					),
+				}
+			}
+		}
+	}
+	return nil
+}
+
 // removeUnusedNodes walks the chain from the start and prunes any nodes that
 // are no longer referenced. This can happen as a result of operations like
 // flattenAdjacentSplitterNodes().
@@ -480,10 +533,16 @@ func (c *compiler) removeUnusedNodes() error {
 		case structs.DiscoveryGraphNodeTypeRouter:
 			for _, route := range node.Routes {
 				todo[route.NextNode] = struct{}{}
+				if route.MirrorNode != "" {
+					todo[route.MirrorNode] = struct{}{}
+				}
 			}
 		case structs.DiscoveryGraphNodeTypeSplitter:
 			for _, split := range node.Splits {
 				todo[split.NextNode] = struct{}{}
+				if split.MirrorNode != "" {
+					todo[split.MirrorNode] = struct{}{}
+				}
 			}
 		case structs.DiscoveryGraphNodeTypeResolver:
 			// nothing special
@@ -562,6 +621,10 @@ func (c *compiler) assembleChain() error {
 		compiledRoute := &structs.DiscoveryRoute{Definition: &route}
 		routeNode.Routes = append(routeNode.Routes, compiledRoute)
 
+		if route.Match != nil && route.Match.GRPC != nil {
+			c.usesGRPCRouteMatches = true
+		}
+
 		dest := route.Destination
 		if dest == nil {
 			dest = &structs.ServiceRouteDestination{
@@ -593,6 +656,14 @@ func (c *compiler) assembleChain() error {
 			return err
 		}
 		compiledRoute.NextNode = node.MapKey()
+
+		if dest.Mirror != nil {
+			mirrorNode, err := c.getMirrorNode(dest.Mirror, c.serviceName, destNamespace, destPartition)
+			if err != nil {
+				return err
+			}
+			compiledRoute.MirrorNode = mirrorNode.MapKey()
+		}
 	}
 
 	// If we have a router, we'll add a catch-all route at the end to send
@@ -740,6 +811,14 @@ func (c *compiler) getSplitterNode(sid structs.ServiceID) (*structs.DiscoveryGra
 		}
 		splitNode.Splits = append(splitNode.Splits, compiledSplit)
 
+		if split.Mirror != nil {
+			mirrorNode, err := c.getMirrorNode(split.Mirror, sid.ID, sid.NamespaceOrDefault(), sid.PartitionOrDefault())
+			if err != nil {
+				return nil, err
+			}
+			compiledSplit.MirrorNode = mirrorNode.MapKey()
+		}
+
 		svc := defaultIfEmpty(split.Service, sid.ID)
 		splitID := structs.ServiceID{
 			ID:             svc,
@@ -783,6 +862,21 @@ func (c *compiler) getSplitterNode(sid structs.ServiceID) (*structs.DiscoveryGra
 	return splitNode, nil
 }
 
+// getMirrorNode returns the resolver node for the shadow destination of a
+// request mirror. Mirrors always resolve directly and are never split.
+func (c *compiler) getMirrorNode(mirror *structs.RequestMirror, defaultService, defaultNamespace, defaultPartition string) (*structs.DiscoveryGraphNode, error) {
+	return c.getResolverNode(
+		c.newTarget(
+			defaultIfEmpty(mirror.Service, defaultService),
+			mirror.ServiceSubset,
+			defaultIfEmpty(mirror.Namespace, defaultNamespace),
+			defaultIfEmpty(mirror.Partition, defaultPartition),
+			"",
+		),
+		false,
+	)
+}
+
 // getResolverNode handles most of the code to handle redirection/rewriting
 // capabilities from a resolver config entry. It recurses into itself to
 // _generate_ targets used for failover out of convenience.
@@ -30,29 +30,32 @@ func TestCompile(t *testing.T) {
 		"router with defaults and noop split and resolver": testcase_RouterWithDefaults_WithNoopSplit_WithResolver(),
 		"router with no destination":                       testcase_JustRouterWithNoDestination(),
 		"route bypasses splitter":                          testcase_RouteBypassesSplit(),
-		"noop split":                                       testcase_NoopSplit_DefaultResolver(),
-		"noop split with protocol from proxy defaults":     testcase_NoopSplit_DefaultResolver_ProtocolFromProxyDefaults(),
-		"noop split with resolver":                         testcase_NoopSplit_WithResolver(),
-		"subset split":                                     testcase_SubsetSplit(),
-		"service split":                                    testcase_ServiceSplit(),
-		"split bypasses next splitter":                     testcase_SplitBypassesSplit(),
-		"service redirect":                                 testcase_ServiceRedirect(),
-		"service and subset redirect":                      testcase_ServiceAndSubsetRedirect(),
-		"datacenter redirect":                              testcase_DatacenterRedirect(),
-		"datacenter redirect with mesh gateways":           testcase_DatacenterRedirect_WithMeshGateways(),
-		"service failover":                                 testcase_ServiceFailover(),
-		"service failover through redirect":                testcase_ServiceFailoverThroughRedirect(),
-		"circular resolver failover":                       testcase_Resolver_CircularFailover(),
-		"service and subset failover":                      testcase_ServiceAndSubsetFailover(),
-		"datacenter failover":                              testcase_DatacenterFailover(),
-		"datacenter failover with mesh gateways":           testcase_DatacenterFailover_WithMeshGateways(),
-		"noop split to resolver with default subset":       testcase_NoopSplit_WithDefaultSubset(),
-		"resolver with default subset":                     testcase_Resolve_WithDefaultSubset(),
-		"default resolver with external sni":               testcase_DefaultResolver_ExternalSNI(),
-		"resolver with no entries and inferring defaults":  testcase_DefaultResolver(),
-		"default resolver with proxy defaults":             testcase_DefaultResolver_WithProxyDefaults(),
-		"loadbalancer splitter and resolver":               testcase_LBSplitterAndResolver(),
-		"loadbalancer resolver":                            testcase_LBResolver(),
+		"route with request mirror":                        testcase_RouteWithMirror(),
+		"split with request mirror":                        testcase_SplitWithMirror(),
+		"nested split with partial request mirror":         testcase_NestedSplitWithPartialMirror(),
+		"noop split": testcase_NoopSplit_DefaultResolver(),
+		"noop split with protocol from proxy defaults":    testcase_NoopSplit_DefaultResolver_ProtocolFromProxyDefaults(),
+		"noop split with resolver":                        testcase_NoopSplit_WithResolver(),
+		"subset split":                                    testcase_SubsetSplit(),
+		"service split":                                   testcase_ServiceSplit(),
+		"split bypasses next splitter":                    testcase_SplitBypassesSplit(),
+		"service redirect":                                testcase_ServiceRedirect(),
+		"service and subset redirect":                     testcase_ServiceAndSubsetRedirect(),
+		"datacenter redirect":                             testcase_DatacenterRedirect(),
+		"datacenter redirect with mesh gateways":          testcase_DatacenterRedirect_WithMeshGateways(),
+		"service failover":                                testcase_ServiceFailover(),
+		"service failover through redirect":               testcase_ServiceFailoverThroughRedirect(),
+		"circular resolver failover":                      testcase_Resolver_CircularFailover(),
+		"service and subset failover":                     testcase_ServiceAndSubsetFailover(),
+		"datacenter failover":                             testcase_DatacenterFailover(),
+		"datacenter failover with mesh gateways":          testcase_DatacenterFailover_WithMeshGateways(),
+		"noop split to resolver with default subset":      testcase_NoopSplit_WithDefaultSubset(),
+		"resolver with default subset":                    testcase_Resolve_WithDefaultSubset(),
+		"default resolver with external sni":              testcase_DefaultResolver_ExternalSNI(),
+		"resolver with no entries and inferring defaults": testcase_DefaultResolver(),
+		"default resolver with proxy defaults":            testcase_DefaultResolver_WithProxyDefaults(),
+		"loadbalancer splitter and resolver":              testcase_LBSplitterAndResolver(),
+		"loadbalancer resolver":                           testcase_LBResolver(),
 		"service redirect to service with default resolver is not a default chain": testcase_RedirectToDefaultResolverIsNotDefaultChain(),
 
 		"all the bells and whistles": testcase_AllBellsAndWhistles(),
@@ -61,6 +64,7 @@ func TestCompile(t *testing.T) {
 		// various errors
 		"splitter requires valid protocol":        testcase_SplitterRequiresValidProtocol(),
 		"router requires valid protocol":          testcase_RouterRequiresValidProtocol(),
+		"grpc match requires grpc protocol":       testcase_GRPCMatchRequiresGRPCProtocol(),
 		"split to unsplittable protocol":          testcase_SplitToUnsplittableProtocol(),
 		"route to unroutable protocol":            testcase_RouteToUnroutableProtocol(),
 		"failover crosses protocols":              testcase_FailoverCrossesProtocols(),
@@ -589,6 +593,227 @@ func testcase_RouteBypassesSplit() compileTestCase {
 	return compileTestCase{entries: entries, expect: expect}
 }
 
+func testcase_RouteWithMirror() compileTestCase {
+	entries := newEntries()
+	setServiceProtocol(entries, "main", "http")
+	setServiceProtocol(entries, "shadow", "http")
+
+	entries.AddRouters(
+		&structs.ServiceRouterConfigEntry{
+			Kind: "service-router",
+			Name: "main",
+			Routes: []structs.ServiceRoute{
+				newSimpleRoute("main", func(r *structs.ServiceRoute) {
+					r.Destination.Mirror = &structs.RequestMirror{
+						Service:    "shadow",
+						Percentage: 25,
+					}
+				}),
+			},
+		},
+	)
+
+	router := entries.GetRouter(structs.NewServiceID("main", nil))
+
+	expect := &structs.CompiledDiscoveryChain{
+		Protocol:  "http",
+		StartNode: "router:main.default.default",
+		Nodes: map[string]*structs.DiscoveryGraphNode{
+			"router:main.default.default": {
+				Type: structs.DiscoveryGraphNodeTypeRouter,
+				Name: "main.default.default",
+				Routes: []*structs.DiscoveryRoute{
+					{
+						Definition: &router.Routes[0],
+						NextNode:   "resolver:main.default.default.dc1",
+						MirrorNode: "resolver:shadow.default.default.dc1",
+					},
+					{
+						Definition: newDefaultServiceRoute("main", "default", "default"),
+						NextNode:   "resolver:main.default.default.dc1",
+					},
+				},
+			},
+			"resolver:main.default.default.dc1": {
+				Type: structs.DiscoveryGraphNodeTypeResolver,
+				Name: "main.default.default.dc1",
+				Resolver: &structs.DiscoveryResolver{
+					Default:        true,
+					ConnectTimeout: 5 * time.Second,
+					Target:         "main.default.default.dc1",
+				},
+			},
+			"resolver:shadow.default.default.dc1": {
+				Type: structs.DiscoveryGraphNodeTypeResolver,
+				Name: "shadow.default.default.dc1",
+				Resolver: &structs.DiscoveryResolver{
+					Default:        true,
+					ConnectTimeout: 5 * time.Second,
+					Target:         "shadow.default.default.dc1",
+				},
+			},
+		},
+		Targets: map[string]*structs.DiscoveryTarget{
+			"main.default.default.dc1":   newTarget("main", "", "default", "default", "dc1", nil),
+			"shadow.default.default.dc1": newTarget("shadow", "", "default", "default", "dc1", nil),
+		},
+	}
+
+	return compileTestCase{entries: entries, expect: expect}
+}
+
+func testcase_SplitWithMirror() compileTestCase {
+	entries := newEntries()
+	setServiceProtocol(entries, "main", "http")
+
+	entries.AddSplitters(
+		&structs.ServiceSplitterConfigEntry{
+			Kind: "service-splitter",
+			Name: "main",
+			Splits: []structs.ServiceSplit{
+				{
+					Weight:        60,
+					ServiceSubset: "v1",
+					Mirror: &structs.RequestMirror{
+						ServiceSubset: "v2",
+					},
+				},
+				{
+					Weight:        40,
+					ServiceSubset: "v2",
+					Mirror: &structs.RequestMirror{
+						ServiceSubset: "v2",
+					},
+				},
+			},
+		},
+	)
+	entries.AddResolvers(
+		&structs.ServiceResolverConfigEntry{
+			Kind: "service-resolver",
+			Name: "main",
+			Subsets: map[string]structs.ServiceResolverSubset{
+				"v1": {
+					Filter: "Service.Meta.version == 1",
+				},
+				"v2": {
+					Filter: "Service.Meta.version == 2",
+				},
+			},
+		},
+	)
+
+	splitter := entries.GetSplitter(structs.NewServiceID("main", nil))
+
+	expect := &structs.CompiledDiscoveryChain{
+		Protocol:  "http",
+		StartNode: "splitter:main.default.default",
+		Nodes: map[string]*structs.DiscoveryGraphNode{
+			"splitter:main.default.default": {
+				Type: structs.DiscoveryGraphNodeTypeSplitter,
+				Name: "main.default.default",
+				Splits: []*structs.DiscoverySplit{
+					{
+						Definition: &splitter.Splits[0],
+						Weight:     60,
+						NextNode:   "resolver:v1.main.default.default.dc1",
+						MirrorNode: "resolver:v2.main.default.default.dc1",
+					},
+					{
+						Definition: &splitter.Splits[1],
+						Weight:     40,
+						NextNode:   "resolver:v2.main.default.default.dc1",
+						MirrorNode: "resolver:v2.main.default.default.dc1",
+					},
+				},
+			},
+			"resolver:v1.main.default.default.dc1": {
+				Type: structs.DiscoveryGraphNodeTypeResolver,
+				Name: "v1.main.default.default.dc1",
+				Resolver: &structs.DiscoveryResolver{
+					ConnectTimeout: 5 * time.Second,
+					Target:         "v1.main.default.default.dc1",
+				},
+			},
+			"resolver:v2.main.default.default.dc1": {
+				Type: structs.DiscoveryGraphNodeTypeResolver,
+				Name: "v2.main.default.default.dc1",
+				Resolver: &structs.DiscoveryResolver{
+					ConnectTimeout: 5 * time.Second,
+					Target:         "v2.main.default.default.dc1",
+				},
+			},
+		},
+		Targets: map[string]*structs.DiscoveryTarget{
+			"v1.main.default.default.dc1": newTarget("main", "v1", "default", "default", "dc1", func(t *structs.DiscoveryTarget) {
+				t.Subset = structs.ServiceResolverSubset{
+					Filter: "Service.Meta.version == 1",
+				}
+			}),
+			"v2.main.default.default.dc1": newTarget("main", "v2", "default", "default", "dc1", func(t *structs.DiscoveryTarget) {
+				t.Subset = structs.ServiceResolverSubset{
+					Filter: "Service.Meta.version == 2",
+				}
+			}),
+		},
+	}
+
+	return compileTestCase{entries: entries, expect: expect}
+}
+
+func testcase_NestedSplitWithPartialMirror() compileTestCase {
+	entries := newEntries()
+	setServiceProtocol(entries, "main", "http")
+	setServiceProtocol(entries, "child", "http")
+	setServiceProtocol(entries, "shadow", "http")
+
+	// The splits of the child splitter all have the same mirror but it only
+	// applies to some of the splits once they are flattened into the parent.
+	entries.AddSplitters(
+		&structs.ServiceSplitterConfigEntry{
+			Kind: "service-splitter",
+			Name: "main",
+			Splits: []structs.ServiceSplit{
+				{Weight: 50, Service: "child"},
+				{Weight: 50, Service: "other"},
+			},
+		},
+		&structs.ServiceSplitterConfigEntry{
+			Kind: "service-splitter",
+			Name: "child",
+			Splits: []structs.ServiceSplit{
+				{
+					Weight:        50,
+					ServiceSubset: "v1",
+					Mirror:        &structs.RequestMirror{Service: "shadow"},
+				},
+				{
+					Weight:        50,
+					ServiceSubset: "v2",
+					Mirror:        &structs.RequestMirror{Service: "shadow"},
+				},
+			},
+		},
+	)
+	entries.AddResolvers(
+		&structs.ServiceResolverConfigEntry{
+			Kind: "service-resolver",
+			Name: "child",
+			Subsets: map[string]structs.ServiceResolverSubset{
+				"v1": {Filter: "Service.Meta.version == 1"},
+				"v2": {Filter: "Service.Meta.version == 2"},
+			},
+		},
+	)
+	setServiceProtocol(entries, "other", "http")
+
+	return compileTestCase{
+		entries:        entries,
+		expectErr:      `discovery chain "main" mirrors the requests of only some of the splits of "main.default.default"`,
+		expectGraphErr: true,
+	}
+}
+
 func testcase_NoopSplit_DefaultResolver() compileTestCase {
 	entries := newEntries()
 	setServiceProtocol(entries, "main", "http")
@@ -2117,6 +2342,36 @@ func testcase_RouterRequiresValidProtocol() compileTestCase {
 	}
 }
 
+func testcase_GRPCMatchRequiresGRPCProtocol() compileTestCase {
+	entries := newEntries()
+	setServiceProtocol(entries, "main", "http")
+	setServiceProtocol(entries, "other", "http")
+
+	entries.AddRouters(
+		&structs.ServiceRouterConfigEntry{
+			Kind: structs.ServiceRouter,
+			Name: "main",
+			Routes: []structs.ServiceRoute{
+				{
+					Match: &structs.ServiceRouteMatch{
+						GRPC: &structs.ServiceRouteGRPCMatch{
+							Service: "pkg.Service",
+						},
+					},
+					Destination: &structs.ServiceRouteDestination{
+						Service: "other",
+					},
+				},
+			},
+		},
+	)
+	return compileTestCase{
+		entries:        entries,
+		expectErr:      "does not permit gRPC route matches",
+		expectGraphErr: true,
+	}
+}
+
 func testcase_SplitToUnsplittableProtocol() compileTestCase {
 	entries := newEntries()
 	setServiceProtocol(entries, "main", "tcp")
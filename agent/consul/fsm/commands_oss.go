@@ -118,6 +118,7 @@ func init() {
 	registerCommand(structs.ConnectCARequestType, (*FSM).applyConnectCAOperation)
 	registerCommand(structs.ACLTokenSetRequestType, (*FSM).applyACLTokenSetOperation)
 	registerCommand(structs.ACLTokenDeleteRequestType, (*FSM).applyACLTokenDeleteOperation)
+	registerCommand(structs.ACLTokenUsageSetRequestType, (*FSM).applyACLTokenUsageSetOperation)
 	registerCommand(structs.ACLBootstrapRequestType, (*FSM).applyACLTokenBootstrap)
 	registerCommand(structs.ACLPolicySetRequestType, (*FSM).applyACLPolicySetOperation)
 	registerCommand(structs.ACLPolicyDeleteRequestType, (*FSM).applyACLPolicyDeleteOperation)
@@ -495,6 +496,17 @@ func (c *FSM) applyACLTokenDeleteOperation(buf []byte, index uint64) interface{}
 	return c.state.ACLTokenBatchDelete(index, req.TokenIDs)
 }
 
+func (c *FSM) applyACLTokenUsageSetOperation(buf []byte, index uint64) interface{} {
+	var req structs.ACLTokenUsageSetRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+	defer metrics.MeasureSinceWithLabels([]string{"fsm", "acl", "token"}, time.Now(),
+		[]metrics.Label{{Name: "op", Value: "usage"}})
+
+	return c.state.ACLTokenUsageSet(index, req.Usage)
+}
+
 func (c *FSM) applyACLTokenBootstrap(buf []byte, index uint64) interface{} {
 	var req structs.ACLTokenBootstrapRequest
 	if err := structs.Decode(buf, &req); err != nil {
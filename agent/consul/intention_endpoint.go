@@ -757,6 +757,17 @@ func (s *Intention) Check(args *structs.IntentionQueryRequest, reply *structs.In
 	}
 	reply.Allowed = decision.Allowed
 
+	// The decision is reported as is in audit mode, only the proxies and the
+	// connect authorization endpoint let denied connections through.
+	_, meshEntry, err := store.ConfigEntry(nil, structs.MeshConfig, structs.MeshConfigMesh,
+		structs.DefaultEnterpriseMetaInPartition(query.DestinationPartition))
+	if err != nil {
+		return fmt.Errorf("failed to get mesh config entry: %v", err)
+	}
+	if meshConf, ok := meshEntry.(*structs.MeshConfigEntry); ok {
+		reply.AuditMode = meshConf.IntentionsAuditMode()
+	}
+
 	return nil
 }
 
@@ -1894,6 +1894,59 @@ func TestIntentionCheck_defaultACLDeny(t *testing.T) {
 	require.False(t, resp.Allowed)
 }
 
+// Test the Check method reports denied connections as such when intentions are
+// in audit mode, along with the audit mode.
+func TestIntentionCheck_defaultACLDenyAuditMode(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.PrimaryDatacenter = "dc1"
+		c.ACLsEnabled = true
+		c.ACLInitialManagementToken = "root"
+		c.ACLResolverSettings.ACLDefaultPolicy = "deny"
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	waitForLeaderEstablishment(t, s1)
+
+	// Put intentions in audit mode
+	{
+		args := &structs.ConfigEntryRequest{
+			Datacenter: "dc1",
+			Entry: &structs.MeshConfigEntry{
+				Intentions: structs.IntentionsMeshConfig{
+					AuditMode: true,
+				},
+			},
+		}
+		args.Token = "root"
+		var out bool
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConfigEntry.Apply", args, &out))
+	}
+
+	// Check
+	req := &structs.IntentionQueryRequest{
+		Datacenter: "dc1",
+		Check: &structs.IntentionQueryCheck{
+			SourceName:      "bar",
+			DestinationName: "qux",
+			SourceType:      structs.IntentionSourceConsul,
+		},
+	}
+	req.Token = "root"
+	var resp structs.IntentionQueryCheckResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Intention.Check", req, &resp))
+	require.False(t, resp.Allowed)
+	require.True(t, resp.AuditMode)
+}
+
 // Test the Check method defaults to deny with denylist ACLs.
 func TestIntentionCheck_defaultACLAllow(t *testing.T) {
 	if testing.Short() {
@@ -41,6 +41,10 @@ var LeaderSummaries = []prometheus.SummaryDefinition{
 		Name: []string{"leader", "reapTombstones"},
 		Help: "Measures the time spent clearing tombstones.",
 	},
+	{
+		Name: []string{"leader", "lease", "renew"},
+		Help: "Measures the time spent confirming the leadership with a quorum of servers to renew the leader lease.",
+	},
 }
 
 const (
@@ -108,7 +112,7 @@ func (s *Server) monitorLeadership() {
 func (s *Server) leadershipTransfer() error {
 	retryCount := 3
 	for i := 0; i < retryCount; i++ {
-		future := s.raft.LeadershipTransfer()
+		future := s.transferLeadership("", "")
 		if err := future.Error(); err != nil {
 			s.logger.Error("failed to transfer leadership attempt, will retry",
 				"attempt", i,
@@ -291,6 +295,11 @@ func (s *Server) establishLeadership(ctx context.Context) error {
 		return err
 	}
 
+	// The same goes for the TTL of the nodes registered in the catalog.
+	if err := s.initializeNodeTimers(); err != nil {
+		return err
+	}
+
 	if err := s.establishEnterpriseLeadership(ctx); err != nil {
 		return err
 	}
@@ -304,6 +313,10 @@ func (s *Server) establishLeadership(ctx context.Context) error {
 
 	s.startFederationStateAntiEntropy(ctx)
 
+	s.startCanaryController(ctx)
+
+	s.startLeaderLease(ctx)
+
 	if err := s.startConnectLeader(ctx); err != nil {
 		return err
 	}
@@ -331,10 +344,18 @@ func (s *Server) revokeLeadership() {
 	// are no longer responsible for session expirations.
 	s.clearAllSessionTimers()
 
+	// Clear the node timers as well, since we are no longer responsible for
+	// node expirations.
+	s.clearAllNodeTimers()
+
 	s.revokeEnterpriseLeadership()
 
 	s.stopFederationStateAntiEntropy()
 
+	s.stopCanaryController()
+
+	s.stopLeaderLease()
+
 	s.stopFederationStateReplication()
 
 	s.stopConfigReplication()
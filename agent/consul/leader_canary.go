@@ -0,0 +1,335 @@
+package consul
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-bexpr"
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
+)
+
+const (
+	// canaryReconcileInterval is how often the canary controller evaluates
+	// the health of canaries and advances or rolls back their rollouts.
+	canaryReconcileInterval = 5 * time.Second
+)
+
+func (s *Server) startCanaryController(ctx context.Context) {
+	// Config entries are only written in the primary datacenter and are
+	// replicated to the others, so the controller only runs there.
+	if s.config.PrimaryDatacenter != s.config.Datacenter {
+		return
+	}
+	s.leaderRoutineManager.Start(ctx, canaryControllerRoutineName, s.runCanaryController)
+}
+
+func (s *Server) stopCanaryController() {
+	s.leaderRoutineManager.Stop(canaryControllerRoutineName)
+}
+
+func (s *Server) runCanaryController(ctx context.Context) error {
+	ticker := time.NewTicker(canaryReconcileInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := s.reconcileCanaries(time.Now()); err != nil {
+				s.logger.Error("error reconciling canary rollouts", "error", err)
+			}
+		}
+	}
+}
+
+// reconcileCanaries advances or rolls back every canary rollout that is in
+// progress, as of the given time, and deletes the service-splitters managed
+// for canaries that were deleted.
+func (s *Server) reconcileCanaries(now time.Time) error {
+	_, entries, err := s.fsm.State().ConfigEntriesByKind(nil, structs.Canary, structs.WildcardEnterpriseMetaInDefaultPartition())
+	if err != nil {
+		return err
+	}
+
+	var merr error
+	canaries := make(map[structs.ServiceID]struct{}, len(entries))
+	for _, raw := range entries {
+		entry, ok := raw.(*structs.CanaryConfigEntry)
+		if !ok {
+			continue
+		}
+		canaries[structs.NewServiceID(entry.Name, &entry.EnterpriseMeta)] = struct{}{}
+		if err := s.reconcileCanary(entry, now); err != nil {
+			merr = multierror.Append(merr, fmt.Errorf("canary %q: %v", entry.Name, err))
+		}
+	}
+
+	if err := s.deleteOrphanedCanarySplitters(canaries); err != nil {
+		merr = multierror.Append(merr, err)
+	}
+	return merr
+}
+
+// deleteOrphanedCanarySplitters deletes the service-splitters managed by the
+// canary controller whose canary no longer exists, so that traffic isn't left
+// pinned to the weights the rollout had reached. Rolled back canaries keep
+// their service-splitter, which sends all the traffic to the stable subset,
+// until they are deleted.
+func (s *Server) deleteOrphanedCanarySplitters(canaries map[structs.ServiceID]struct{}) error {
+	_, entries, err := s.fsm.State().ConfigEntriesByKind(nil, structs.ServiceSplitter, structs.WildcardEnterpriseMetaInDefaultPartition())
+	if err != nil {
+		return err
+	}
+
+	var merr error
+	for _, splitter := range entries {
+		if splitter.GetMeta()[structs.CanaryManagedByMetaKey] != structs.Canary {
+			continue
+		}
+		if _, ok := canaries[structs.NewServiceID(splitter.GetName(), splitter.GetEnterpriseMeta())]; ok {
+			continue
+		}
+
+		// Use a check-and-set so that a service-splitter taken over by hand
+		// since it was read is not deleted.
+		_, err := s.raftApply(structs.ConfigEntryRequestType, &structs.ConfigEntryRequest{
+			Op:         structs.ConfigEntryDeleteCAS,
+			Datacenter: s.config.Datacenter,
+			Entry:      splitter,
+		})
+		if err != nil {
+			merr = multierror.Append(merr, fmt.Errorf("failed to delete service-splitter %q of deleted canary: %v", splitter.GetName(), err))
+			continue
+		}
+		s.logger.Info("deleted the service-splitter of a deleted canary", "service", splitter.GetName())
+	}
+	return merr
+}
+
+func (s *Server) reconcileCanary(entry *structs.CanaryConfigEntry, now time.Time) error {
+	switch entry.Status.State {
+	case structs.CanaryStatePromoted, structs.CanaryStateRolledBack:
+		return nil
+	}
+
+	health, err := s.canaryHealth(entry)
+	if err != nil {
+		return err
+	}
+
+	next := nextCanaryStatus(entry, health, now)
+	if next == entry.Status {
+		return nil
+	}
+
+	if next.State != "" && (entry.Status.State == "" || next.CanaryWeight != entry.Status.CanaryWeight) {
+		written, err := s.applyCanarySplitter(entry, next.CanaryWeight)
+		if err != nil {
+			return err
+		}
+		if !written {
+			// The rollout is held until the service-splitter is deleted or
+			// marked as managed by the controller.
+			next = entry.Status
+			next.Reason = fmt.Sprintf("service-splitter %q is not managed by the canary controller", entry.Name)
+			if next == entry.Status {
+				return nil
+			}
+		}
+	}
+
+	// Use a check-and-set so that a concurrent write of the canary, which
+	// may have restarted the rollout, is not overwritten. The rollout will
+	// be reconciled again with the new entry.
+	updated := *entry
+	updated.Status = next
+	resp, err := s.raftApply(structs.ConfigEntryRequestType, &structs.ConfigEntryRequest{
+		Op:         structs.ConfigEntryUpsertCAS,
+		Datacenter: s.config.Datacenter,
+		Entry:      &updated,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update status: %v", err)
+	}
+	if ok, _ := resp.(bool); !ok {
+		return nil
+	}
+
+	if next.State != entry.Status.State || next.Step != entry.Status.Step {
+		s.logger.Info("canary rollout updated",
+			"service", entry.Name,
+			"state", next.State,
+			"canary_weight", next.CanaryWeight,
+			"reason", next.Reason,
+		)
+	}
+	return nil
+}
+
+// applyCanarySplitter writes the service-splitter sending the given
+// percentage of traffic to the canary subset. It returns false without writing
+// it if a service-splitter that isn't managed by the canary controller exists,
+// since it was written by hand and must not be overwritten.
+func (s *Server) applyCanarySplitter(entry *structs.CanaryConfigEntry, canaryWeight float32) (bool, error) {
+	_, existing, err := s.fsm.State().ConfigEntry(nil, structs.ServiceSplitter, entry.Name, &entry.EnterpriseMeta)
+	if err != nil {
+		return false, err
+	}
+
+	splitter := entry.Splitter(canaryWeight)
+	if existing != nil {
+		if existing.GetMeta()[structs.CanaryManagedByMetaKey] != structs.Canary {
+			return false, nil
+		}
+		splitter.ModifyIndex = existing.GetRaftIndex().ModifyIndex
+	}
+	if err := splitter.Normalize(); err != nil {
+		return false, err
+	}
+	if err := splitter.Validate(); err != nil {
+		return false, err
+	}
+
+	// Use a check-and-set so that a service-splitter written by hand since
+	// it was read is not overwritten.
+	resp, err := s.raftApply(structs.ConfigEntryRequestType, &structs.ConfigEntryRequest{
+		Op:         structs.ConfigEntryUpsertCAS,
+		Datacenter: s.config.Datacenter,
+		Entry:      splitter,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to update service-splitter: %v", err)
+	}
+	if ok, _ := resp.(bool); !ok {
+		return false, fmt.Errorf("service-splitter %q was modified concurrently", entry.Name)
+	}
+	return true, nil
+}
+
+// canaryHealthResult is the health of the instances in a canary subset.
+type canaryHealthResult struct {
+	// Instances is the number of instances in the canary subset.
+	Instances int
+
+	// Failing describes the first critical check found on a canary
+	// instance, or is empty if none are critical.
+	Failing string
+}
+
+// canaryHealth evaluates the health checks of the instances in the canary
+// subset, as defined by the filter of the subset in the service-resolver.
+func (s *Server) canaryHealth(entry *structs.CanaryConfigEntry) (canaryHealthResult, error) {
+	var result canaryHealthResult
+
+	state := s.fsm.State()
+	_, raw, err := state.ConfigEntry(nil, structs.ServiceResolver, entry.Name, &entry.EnterpriseMeta)
+	if err != nil {
+		return result, err
+	}
+	resolver, ok := raw.(*structs.ServiceResolverConfigEntry)
+	if !ok {
+		return result, fmt.Errorf("service %q has no service-resolver defining the canary subset", entry.Name)
+	}
+	subset, ok := resolver.Subsets[entry.CanarySubset]
+	if !ok {
+		return result, fmt.Errorf("service-resolver %q does not define subset %q", entry.Name, entry.CanarySubset)
+	}
+
+	_, nodes, err := state.CheckServiceNodes(nil, entry.Name, &entry.EnterpriseMeta)
+	if err != nil {
+		return result, err
+	}
+	if subset.Filter != "" {
+		filter, err := bexpr.CreateFilter(subset.Filter, nil, nodes)
+		if err != nil {
+			return result, err
+		}
+		filtered, err := filter.Execute(nodes)
+		if err != nil {
+			return result, err
+		}
+		nodes = filtered.(structs.CheckServiceNodes)
+	}
+
+	gated := make(map[string]struct{}, len(entry.HealthChecks))
+	for _, check := range entry.HealthChecks {
+		gated[check] = struct{}{}
+	}
+
+	result.Instances = len(nodes)
+	for _, node := range nodes {
+		for _, check := range node.Checks {
+			if len(gated) > 0 {
+				_, byID := gated[string(check.CheckID)]
+				_, byName := gated[check.Name]
+				if !byID && !byName {
+					continue
+				}
+			}
+			if check.Status == api.HealthCritical {
+				result.Failing = fmt.Sprintf("check %q of instance %q on node %q is critical",
+					check.CheckID, node.Service.ID, node.Node.Node)
+				return result, nil
+			}
+		}
+	}
+	return result, nil
+}
+
+// nextCanaryStatus returns the status the rollout should move to given the
+// health of the canary. A rollout starts once canary instances exist, rolls
+// back as soon as a canary instance has a critical check, and otherwise
+// advances one step every StepInterval until the canary is promoted.
+func nextCanaryStatus(entry *structs.CanaryConfigEntry, health canaryHealthResult, now time.Time) structs.CanaryStatus {
+	status := entry.Status
+
+	if health.Failing != "" {
+		return structs.CanaryStatus{
+			State:              structs.CanaryStateRolledBack,
+			Step:               status.Step,
+			CanaryWeight:       0,
+			Reason:             "rolled back: " + health.Failing,
+			LastTransitionTime: now,
+		}
+	}
+
+	if health.Instances == 0 {
+		status.Reason = fmt.Sprintf("waiting for instances in subset %q", entry.CanarySubset)
+		return status
+	}
+
+	switch status.State {
+	case "":
+		status = structs.CanaryStatus{
+			State:              structs.CanaryStateProgressing,
+			Step:               0,
+			CanaryWeight:       entry.Steps[0],
+			Reason:             "started rollout",
+			LastTransitionTime: now,
+		}
+	case structs.CanaryStateProgressing:
+		if now.Sub(status.LastTransitionTime) < entry.StepInterval || status.Step+1 >= len(entry.Steps) {
+			return status
+		}
+		status = structs.CanaryStatus{
+			State:              structs.CanaryStateProgressing,
+			Step:               status.Step + 1,
+			CanaryWeight:       entry.Steps[status.Step+1],
+			Reason:             fmt.Sprintf("advanced to step %d of %d", status.Step+2, len(entry.Steps)),
+			LastTransitionTime: now,
+		}
+	default:
+		return status
+	}
+
+	if status.CanaryWeight >= 100 {
+		status.State = structs.CanaryStatePromoted
+		status.Reason = "promoted"
+	}
+	return status
+}
@@ -0,0 +1,381 @@
+package consul
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/testrpc"
+	"github.com/hashicorp/consul/types"
+)
+
+func TestNextCanaryStatus(t *testing.T) {
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	entry := func(status structs.CanaryStatus) *structs.CanaryConfigEntry {
+		return &structs.CanaryConfigEntry{
+			Name:         "web",
+			StableSubset: "v1",
+			CanarySubset: "v2",
+			Steps:        []float32{10, 50, 100},
+			StepInterval: time.Minute,
+			Status:       status,
+		}
+	}
+	progressing := structs.CanaryStatus{
+		State:              structs.CanaryStateProgressing,
+		Step:               0,
+		CanaryWeight:       10,
+		Reason:             "started rollout",
+		LastTransitionTime: start,
+	}
+
+	cases := map[string]struct {
+		entry  *structs.CanaryConfigEntry
+		health canaryHealthResult
+		now    time.Time
+		expect structs.CanaryStatus
+	}{
+		"waiting for instances": {
+			entry:  entry(structs.CanaryStatus{}),
+			health: canaryHealthResult{},
+			now:    start,
+			expect: structs.CanaryStatus{Reason: `waiting for instances in subset "v2"`},
+		},
+		"start": {
+			entry:  entry(structs.CanaryStatus{}),
+			health: canaryHealthResult{Instances: 1},
+			now:    start,
+			expect: progressing,
+		},
+		"step not elapsed": {
+			entry:  entry(progressing),
+			health: canaryHealthResult{Instances: 1},
+			now:    start.Add(30 * time.Second),
+			expect: progressing,
+		},
+		"advance": {
+			entry:  entry(progressing),
+			health: canaryHealthResult{Instances: 1},
+			now:    start.Add(time.Minute),
+			expect: structs.CanaryStatus{
+				State:              structs.CanaryStateProgressing,
+				Step:               1,
+				CanaryWeight:       50,
+				Reason:             "advanced to step 2 of 3",
+				LastTransitionTime: start.Add(time.Minute),
+			},
+		},
+		"promote": {
+			entry: entry(structs.CanaryStatus{
+				State:              structs.CanaryStateProgressing,
+				Step:               1,
+				CanaryWeight:       50,
+				LastTransitionTime: start,
+			}),
+			health: canaryHealthResult{Instances: 1},
+			now:    start.Add(time.Minute),
+			expect: structs.CanaryStatus{
+				State:              structs.CanaryStatePromoted,
+				Step:               2,
+				CanaryWeight:       100,
+				Reason:             "promoted",
+				LastTransitionTime: start.Add(time.Minute),
+			},
+		},
+		"roll back": {
+			entry:  entry(progressing),
+			health: canaryHealthResult{Instances: 1, Failing: "check is critical"},
+			now:    start.Add(10 * time.Second),
+			expect: structs.CanaryStatus{
+				State:              structs.CanaryStateRolledBack,
+				Step:               0,
+				CanaryWeight:       0,
+				Reason:             "rolled back: check is critical",
+				LastTransitionTime: start.Add(10 * time.Second),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.expect, nextCanaryStatus(tc.entry, tc.health, tc.now))
+		})
+	}
+}
+
+func TestLeader_CanaryController(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.PrimaryDatacenter = "dc1"
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	applyEntry := func(entry structs.ConfigEntry) {
+		t.Helper()
+		var out bool
+		args := structs.ConfigEntryRequest{
+			Datacenter: "dc1",
+			Entry:      entry,
+		}
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConfigEntry.Apply", &args, &out))
+		require.True(t, out)
+	}
+
+	register := func(id, version, status string) {
+		t.Helper()
+		var out struct{}
+		args := structs.RegisterRequest{
+			Datacenter: "dc1",
+			Node:       "node-" + id,
+			Address:    "127.0.0.1",
+			Service: &structs.NodeService{
+				ID:      id,
+				Service: "web",
+				Port:    8080,
+				Meta:    map[string]string{"version": version},
+			},
+			Check: &structs.HealthCheck{
+				CheckID:   types.CheckID("check-" + id),
+				Name:      "web check",
+				Status:    status,
+				ServiceID: id,
+			},
+		}
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "Catalog.Register", &args, &out))
+	}
+
+	getCanary := func() *structs.CanaryConfigEntry {
+		t.Helper()
+		_, entry, err := s1.fsm.State().ConfigEntry(nil, structs.Canary, "web", nil)
+		require.NoError(t, err)
+		// Copy the entry so that the tests can modify it without changing
+		// the state store.
+		canary := *entry.(*structs.CanaryConfigEntry)
+		return &canary
+	}
+
+	getWeights := func() []float32 {
+		t.Helper()
+		_, entry, err := s1.fsm.State().ConfigEntry(nil, structs.ServiceSplitter, "web", nil)
+		require.NoError(t, err)
+		splitter := entry.(*structs.ServiceSplitterConfigEntry)
+		require.Equal(t, structs.Canary, splitter.Meta[structs.CanaryManagedByMetaKey])
+		var weights []float32
+		for _, split := range splitter.Splits {
+			weights = append(weights, split.Weight)
+		}
+		return weights
+	}
+
+	applyEntry(&structs.ServiceConfigEntry{
+		Kind:     structs.ServiceDefaults,
+		Name:     "web",
+		Protocol: "http",
+	})
+	applyEntry(&structs.ServiceResolverConfigEntry{
+		Kind:          structs.ServiceResolver,
+		Name:          "web",
+		DefaultSubset: "v1",
+		Subsets: map[string]structs.ServiceResolverSubset{
+			"v1": {Filter: `Service.Meta.version == "v1"`},
+			"v2": {Filter: `Service.Meta.version == "v2"`},
+		},
+	})
+	applyEntry(&structs.CanaryConfigEntry{
+		Name:         "web",
+		StableSubset: "v1",
+		CanarySubset: "v2",
+		Steps:        []float32{25, 100},
+		StepInterval: time.Hour,
+		// Any status provided by the user is ignored.
+		Status: structs.CanaryStatus{State: structs.CanaryStatePromoted},
+	})
+	register("web-v1", "v1", api.HealthPassing)
+
+	start := time.Now()
+
+	// Without canary instances the rollout does not start.
+	require.NoError(t, s1.reconcileCanaries(start))
+	canary := getCanary()
+	require.Equal(t, structs.CanaryState(""), canary.Status.State)
+	require.Equal(t, `waiting for instances in subset "v2"`, canary.Status.Reason)
+
+	// Once a canary instance exists the first step is applied.
+	register("web-v2", "v2", api.HealthPassing)
+	require.NoError(t, s1.reconcileCanaries(start))
+	canary = getCanary()
+	require.Equal(t, structs.CanaryStateProgressing, canary.Status.State)
+	require.Equal(t, []float32{75, 25}, getWeights())
+
+	// Rewriting the same rollout keeps its progress.
+	canary.Status = structs.CanaryStatus{}
+	canary.Meta = map[string]string{"owner": "team"}
+	applyEntry(canary)
+	require.Equal(t, structs.CanaryStateProgressing, getCanary().Status.State)
+
+	// The rollout is promoted once the last step interval elapsed.
+	require.NoError(t, s1.reconcileCanaries(start.Add(time.Hour)))
+	canary = getCanary()
+	require.Equal(t, structs.CanaryStatePromoted, canary.Status.State)
+	require.Equal(t, []float32{0, 100}, getWeights())
+
+	// Changing the rollout restarts it.
+	canary.Steps = []float32{50, 100}
+	applyEntry(canary)
+	require.Equal(t, structs.CanaryState(""), getCanary().Status.State)
+	require.NoError(t, s1.reconcileCanaries(start))
+	require.Equal(t, []float32{50, 50}, getWeights())
+
+	// A critical check on a canary instance rolls back the rollout.
+	register("web-v2", "v2", api.HealthCritical)
+	require.NoError(t, s1.reconcileCanaries(start.Add(time.Minute)))
+	canary = getCanary()
+	require.Equal(t, structs.CanaryStateRolledBack, canary.Status.State)
+	require.Contains(t, canary.Status.Reason, `check "check-web-v2"`)
+	require.Equal(t, []float32{100, 0}, getWeights())
+
+	// Rolled back rollouts are left alone.
+	register("web-v2", "v2", api.HealthPassing)
+	require.NoError(t, s1.reconcileCanaries(start.Add(2*time.Hour)))
+	require.Equal(t, structs.CanaryStateRolledBack, getCanary().Status.State)
+	require.Equal(t, []float32{100, 0}, getWeights())
+
+	// Deleting the canary deletes the service-splitter it managed.
+	var out structs.ConfigEntryDeleteResponse
+	args := structs.ConfigEntryRequest{
+		Op:         structs.ConfigEntryDelete,
+		Datacenter: "dc1",
+		Entry:      &structs.CanaryConfigEntry{Name: "web"},
+	}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConfigEntry.Delete", &args, &out))
+	require.NoError(t, s1.reconcileCanaries(start.Add(2*time.Hour)))
+	_, splitter, err := s1.fsm.State().ConfigEntry(nil, structs.ServiceSplitter, "web", nil)
+	require.NoError(t, err)
+	require.Nil(t, splitter)
+}
+
+func TestLeader_CanaryController_UnmanagedSplitter(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.PrimaryDatacenter = "dc1"
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	applyEntry := func(entry structs.ConfigEntry) {
+		t.Helper()
+		var out bool
+		args := structs.ConfigEntryRequest{
+			Datacenter: "dc1",
+			Entry:      entry,
+		}
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConfigEntry.Apply", &args, &out))
+		require.True(t, out)
+	}
+
+	getCanary := func() *structs.CanaryConfigEntry {
+		t.Helper()
+		_, entry, err := s1.fsm.State().ConfigEntry(nil, structs.Canary, "web", nil)
+		require.NoError(t, err)
+		return entry.(*structs.CanaryConfigEntry)
+	}
+
+	getSplitter := func() *structs.ServiceSplitterConfigEntry {
+		t.Helper()
+		_, entry, err := s1.fsm.State().ConfigEntry(nil, structs.ServiceSplitter, "web", nil)
+		require.NoError(t, err)
+		return entry.(*structs.ServiceSplitterConfigEntry)
+	}
+
+	applyEntry(&structs.ServiceConfigEntry{
+		Kind:     structs.ServiceDefaults,
+		Name:     "web",
+		Protocol: "http",
+	})
+	applyEntry(&structs.ServiceResolverConfigEntry{
+		Kind:          structs.ServiceResolver,
+		Name:          "web",
+		DefaultSubset: "v1",
+		Subsets: map[string]structs.ServiceResolverSubset{
+			"v1": {Filter: `Service.Meta.version == "v1"`},
+			"v2": {Filter: `Service.Meta.version == "v2"`},
+		},
+	})
+	applyEntry(&structs.ServiceSplitterConfigEntry{
+		Kind: structs.ServiceSplitter,
+		Name: "web",
+		Splits: []structs.ServiceSplit{
+			{Weight: 90, ServiceSubset: "v1"},
+			{Weight: 10, ServiceSubset: "v2"},
+		},
+	})
+	applyEntry(&structs.CanaryConfigEntry{
+		Name:         "web",
+		StableSubset: "v1",
+		CanarySubset: "v2",
+		Steps:        []float32{25, 100},
+		StepInterval: time.Hour,
+	})
+
+	var out struct{}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Catalog.Register", &structs.RegisterRequest{
+		Datacenter: "dc1",
+		Node:       "node-web-v2",
+		Address:    "127.0.0.1",
+		Service: &structs.NodeService{
+			ID:      "web-v2",
+			Service: "web",
+			Port:    8080,
+			Meta:    map[string]string{"version": "v2"},
+		},
+	}, &out))
+
+	// The service-splitter written by hand is left alone and the rollout
+	// doesn't start.
+	start := time.Now()
+	require.NoError(t, s1.reconcileCanaries(start))
+	canary := getCanary()
+	require.Equal(t, structs.CanaryState(""), canary.Status.State)
+	require.Equal(t, `service-splitter "web" is not managed by the canary controller`, canary.Status.Reason)
+	splitter := getSplitter()
+	require.Empty(t, splitter.Meta)
+	require.Equal(t, float32(10), splitter.Splits[1].Weight)
+
+	// Nothing is written until the service-splitter changes.
+	index := canary.ModifyIndex
+	require.NoError(t, s1.reconcileCanaries(start))
+	require.Equal(t, index, getCanary().ModifyIndex)
+
+	// Once the service-splitter is handed over to the controller the
+	// rollout starts.
+	splitter.Meta = map[string]string{structs.CanaryManagedByMetaKey: structs.Canary}
+	applyEntry(splitter)
+	require.NoError(t, s1.reconcileCanaries(start))
+	require.Equal(t, structs.CanaryStateProgressing, getCanary().Status.State)
+	require.Equal(t, float32(25), getSplitter().Splits[1].Weight)
+}
@@ -0,0 +1,185 @@
+package consul
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/hashicorp/raft"
+)
+
+// leaderLease tracks the window during which this server is known to be the
+// only leader of the cluster, so that consistent reads can be served without
+// a round trip to a quorum of servers.
+//
+// A follower does not vote for another candidate, nor becomes one, until it
+// has not heard from its leader for at least the Raft heartbeat timeout. Once
+// a quorum of servers has acknowledged a heartbeat sent at time t, no other
+// leader can therefore be elected before t plus the heartbeat timeout. The
+// lease covers this window, shortened by the maximum clock skew so that
+// servers measuring time at different rates can't elect a new leader while
+// the lease is still held. This assumes all the servers use the same
+// heartbeat timeout.
+type leaderLease struct {
+	// duration is how long a lease is held after leadership was confirmed.
+	// The lease is never held when it isn't positive.
+	duration time.Duration
+
+	// now returns the current time, it is overridden in tests.
+	now func() time.Time
+
+	lock sync.Mutex
+
+	// expires is the time at which the lease expires, the lease is not held
+	// when it is zero.
+	expires time.Time
+
+	// epoch is incremented every time the lease is revoked so that
+	// confirmations started before can't extend it.
+	epoch uint64
+
+	// transfers is the number of leadership transfers in progress, the
+	// lease can't be extended while there are any since the target of the
+	// transfer starts an election without waiting for a heartbeat timeout.
+	transfers int
+}
+
+func newLeaderLease(heartbeatTimeout, maxClockSkew time.Duration) *leaderLease {
+	return &leaderLease{
+		duration: heartbeatTimeout - maxClockSkew,
+		now:      time.Now,
+	}
+}
+
+// begin returns the epoch and the time at which a confirmation of the
+// leadership starts. Both must be given to extend once a quorum confirmed it.
+func (l *leaderLease) begin() (uint64, time.Time) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.epoch, l.now()
+}
+
+// extend extends the lease after a quorum of servers confirmed the leadership
+// of this server. The confirmation must have started at the given time and
+// epoch, as returned by begin.
+func (l *leaderLease) extend(epoch uint64, start time.Time) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.duration <= 0 || l.transfers > 0 || epoch != l.epoch {
+		return
+	}
+	if expires := start.Add(l.duration); expires.After(l.expires) {
+		l.expires = expires
+	}
+}
+
+// valid returns whether the lease is currently held.
+func (l *leaderLease) valid() bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return !l.expires.IsZero() && l.now().Before(l.expires)
+}
+
+// revoke gives up the lease, it has to be confirmed again before being held.
+func (l *leaderLease) revoke() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.revokeLocked()
+}
+
+func (l *leaderLease) revokeLocked() {
+	l.expires = time.Time{}
+	l.epoch++
+}
+
+// transfer runs a leadership transfer. The lease is revoked and can't be
+// extended until the transfer completes.
+func (l *leaderLease) transfer(fn func() raft.Future) raft.Future {
+	l.lock.Lock()
+	l.transfers++
+	l.revokeLocked()
+	l.lock.Unlock()
+
+	defer func() {
+		l.lock.Lock()
+		l.transfers--
+		l.revokeLocked()
+		l.lock.Unlock()
+	}()
+
+	future := fn()
+	future.Error()
+	return future
+}
+
+func (s *Server) startLeaderLease(ctx context.Context) {
+	if !s.config.LeaderLeaseReads.Enabled {
+		return
+	}
+	s.leaderRoutineManager.Start(ctx, leaderLeaseRoutineName, s.runLeaderLease)
+}
+
+func (s *Server) stopLeaderLease() {
+	s.leaderRoutineManager.Stop(leaderLeaseRoutineName)
+	s.leaderLease.revoke()
+}
+
+// runLeaderLease keeps confirming the leadership of this server with a quorum
+// of servers to keep the lease held. The lease is renewed three times per
+// period so that a single slow confirmation doesn't let it expire.
+func (s *Server) runLeaderLease(ctx context.Context) error {
+	interval := s.leaderLease.duration / 3
+	if interval <= 0 {
+		s.logger.Warn("leader lease reads are disabled, the maximum clock skew must be lower than the Raft heartbeat timeout")
+		return nil
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.renewLeaderLease()
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// renewLeaderLease extends the lease once a quorum of servers has confirmed
+// the leadership of this server, or revokes it if they didn't.
+func (s *Server) renewLeaderLease() {
+	defer metrics.MeasureSince([]string{"leader", "lease", "renew"}, time.Now())
+
+	epoch, start := s.leaderLease.begin()
+	if err := s.raft.VerifyLeader().Error(); err != nil {
+		s.logger.Warn("failed to renew the leader lease", "error", err)
+		s.leaderLease.revoke()
+		return
+	}
+	s.leaderLease.extend(epoch, start)
+}
+
+// holdsLeaderLease returns whether consistent reads can be served without
+// confirming the leadership of this server first.
+func (s *Server) holdsLeaderLease() bool {
+	if !s.config.LeaderLeaseReads.Enabled {
+		return false
+	}
+	return s.isReadyForConsistentReads() && s.raft.State() == raft.Leader && s.leaderLease.valid()
+}
+
+// transferLeadership transfers the leadership of the cluster, to the given
+// server if its ID isn't empty, after giving up the leader lease.
+func (s *Server) transferLeadership(id raft.ServerID, address raft.ServerAddress) raft.Future {
+	return s.leaderLease.transfer(func() raft.Future {
+		if id == "" {
+			return s.raft.LeadershipTransfer()
+		}
+		return s.raft.LeadershipTransferToServer(id, address)
+	})
+}
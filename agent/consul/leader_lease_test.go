@@ -0,0 +1,204 @@
+package consul
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/raft"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/sdk/testutil/retry"
+	"github.com/hashicorp/consul/testrpc"
+)
+
+func TestLeaderLease(t *testing.T) {
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// newLease returns a lease using a clock that is only moved forward
+	// by the returned function.
+	newLease := func(heartbeatTimeout, maxClockSkew time.Duration) (*leaderLease, func(time.Duration)) {
+		now := start
+		l := newLeaderLease(heartbeatTimeout, maxClockSkew)
+		l.now = func() time.Time { return now }
+		return l, func(d time.Duration) { now = now.Add(d) }
+	}
+
+	t.Run("not held before confirmation", func(t *testing.T) {
+		l, _ := newLease(200*time.Millisecond, 50*time.Millisecond)
+		require.False(t, l.valid())
+	})
+
+	t.Run("expires after heartbeat timeout minus clock skew", func(t *testing.T) {
+		l, advance := newLease(200*time.Millisecond, 50*time.Millisecond)
+
+		epoch, confirmStart := l.begin()
+		// The lease starts when the confirmation was sent, not when the
+		// quorum replied.
+		advance(20 * time.Millisecond)
+		l.extend(epoch, confirmStart)
+		require.True(t, l.valid())
+
+		advance(129 * time.Millisecond)
+		require.True(t, l.valid())
+
+		advance(time.Millisecond)
+		require.False(t, l.valid())
+	})
+
+	t.Run("renewed", func(t *testing.T) {
+		l, advance := newLease(200*time.Millisecond, 50*time.Millisecond)
+
+		epoch, confirmStart := l.begin()
+		l.extend(epoch, confirmStart)
+		advance(100 * time.Millisecond)
+
+		epoch, confirmStart = l.begin()
+		l.extend(epoch, confirmStart)
+		advance(100 * time.Millisecond)
+		require.True(t, l.valid())
+
+		// A confirmation completing late doesn't shorten the lease.
+		l.extend(epoch, start)
+		require.True(t, l.valid())
+
+		advance(50 * time.Millisecond)
+		require.False(t, l.valid())
+	})
+
+	t.Run("clock skew greater than heartbeat timeout", func(t *testing.T) {
+		l, _ := newLease(200*time.Millisecond, 200*time.Millisecond)
+
+		epoch, confirmStart := l.begin()
+		l.extend(epoch, confirmStart)
+		require.False(t, l.valid())
+	})
+
+	t.Run("revoked", func(t *testing.T) {
+		l, _ := newLease(200*time.Millisecond, 50*time.Millisecond)
+
+		epoch, confirmStart := l.begin()
+		l.extend(epoch, confirmStart)
+		require.True(t, l.valid())
+
+		// A confirmation started before the lease was revoked can't extend
+		// it.
+		epoch, confirmStart = l.begin()
+		l.revoke()
+		require.False(t, l.valid())
+		l.extend(epoch, confirmStart)
+		require.False(t, l.valid())
+
+		epoch, confirmStart = l.begin()
+		l.extend(epoch, confirmStart)
+		require.True(t, l.valid())
+	})
+
+	t.Run("leadership transfer", func(t *testing.T) {
+		l, _ := newLease(200*time.Millisecond, 50*time.Millisecond)
+
+		epoch, confirmStart := l.begin()
+		l.extend(epoch, confirmStart)
+		require.True(t, l.valid())
+
+		var duringEpoch uint64
+		var duringStart time.Time
+		l.transfer(func() raft.Future {
+			require.False(t, l.valid())
+
+			// The lease can't be extended while the transfer is in
+			// progress.
+			duringEpoch, duringStart = l.begin()
+			l.extend(duringEpoch, duringStart)
+			require.False(t, l.valid())
+			return raft.Future(&testFuture{})
+		})
+		require.False(t, l.valid())
+
+		// Nor by a confirmation started during the transfer.
+		l.extend(duringEpoch, duringStart)
+		require.False(t, l.valid())
+	})
+}
+
+// testFuture is a raft.Future that has already completed successfully.
+type testFuture struct{}
+
+func (f *testFuture) Error() error { return nil }
+
+func TestServer_LeaderLeaseReads(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	t.Run("disabled", func(t *testing.T) {
+		dir1, s1 := testServerWithConfig(t, func(c *Config) {
+			c.LeaderLeaseReads.MaxClockSkew = 50 * time.Millisecond
+		})
+		defer os.RemoveAll(dir1)
+		defer s1.Shutdown()
+		testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+		require.NoError(t, s1.consistentRead())
+		require.False(t, s1.holdsLeaderLease())
+	})
+
+	t.Run("leadership transfer", func(t *testing.T) {
+		conf := func(c *Config) {
+			c.Bootstrap = false
+			c.BootstrapExpect = 3
+			c.LeaderLeaseReads.Enabled = true
+			c.LeaderLeaseReads.MaxClockSkew = 50 * time.Millisecond
+		}
+		dir1, s1 := testServerWithConfig(t, conf)
+		defer os.RemoveAll(dir1)
+		defer s1.Shutdown()
+		dir2, s2 := testServerWithConfig(t, conf)
+		defer os.RemoveAll(dir2)
+		defer s2.Shutdown()
+		dir3, s3 := testServerWithConfig(t, conf)
+		defer os.RemoveAll(dir3)
+		defer s3.Shutdown()
+
+		servers := []*Server{s1, s2, s3}
+		joinLAN(t, s2, s1)
+		joinLAN(t, s3, s1)
+		testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+		leader := func(r require.TestingT) *Server {
+			for _, s := range servers {
+				if s.IsLeader() {
+					return s
+				}
+			}
+			require.Fail(r, "no leader")
+			return nil
+		}
+
+		var oldLeader *Server
+		retry.Run(t, func(r *retry.R) {
+			oldLeader = leader(r)
+			require.True(r, oldLeader.holdsLeaderLease())
+		})
+		require.NoError(t, oldLeader.consistentRead())
+
+		// The followers never hold the lease.
+		for _, s := range servers {
+			if s != oldLeader {
+				require.False(t, s.holdsLeaderLease())
+			}
+		}
+
+		require.NoError(t, oldLeader.transferLeadership("", "").Error())
+		require.False(t, oldLeader.holdsLeaderLease())
+
+		retry.Run(t, func(r *retry.R) {
+			newLeader := leader(r)
+			require.True(r, newLeader != oldLeader, "leadership was not transferred")
+			require.True(r, newLeader.holdsLeaderLease())
+		})
+		require.False(t, oldLeader.holdsLeaderLease())
+	})
+}
@@ -0,0 +1,175 @@
+package consul
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/armon/go-metrics/prometheus"
+	"github.com/hashicorp/go-uuid"
+
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+var NodeTTLGauges = []prometheus.GaugeDefinition{
+	{
+		Name: []string{"node_ttl", "active"},
+		Help: "Tracks the active number of catalog nodes with a TTL being tracked.",
+	},
+}
+
+var NodeTTLSummaries = []prometheus.SummaryDefinition{
+	{
+		Name: []string{"node_ttl", "expire"},
+		Help: "Measures the time spent deregistering a catalog node whose TTL expired.",
+	},
+}
+
+// nodeTTLExpiredEvent is the name of the user event fired when a node is
+// deregistered because its TTL expired. Its payload is the name of the node.
+const nodeTTLExpiredEvent = "node-ttl-expired"
+
+// nodeTTL returns the TTL of a node from its metadata, it is zero if the node
+// has none.
+func nodeTTL(meta map[string]string) (time.Duration, error) {
+	raw, ok := meta[structs.MetaNodeTTL]
+	if !ok {
+		return 0, nil
+	}
+	ttl, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid Node TTL '%s': %v", raw, err)
+	}
+	return ttl, nil
+}
+
+// validateNodeTTL checks the TTL a node is registered with, if any.
+func validateNodeTTL(meta map[string]string) error {
+	if _, ok := meta[structs.MetaNodeTTL]; !ok {
+		return nil
+	}
+	ttl, err := nodeTTL(meta)
+	if err != nil {
+		return err
+	}
+	if ttl < structs.NodeTTLMin || ttl > structs.NodeTTLMax {
+		return fmt.Errorf("Node TTL '%s' must be between [%v=%v]",
+			meta[structs.MetaNodeTTL], structs.NodeTTLMin, structs.NodeTTLMax)
+	}
+	return nil
+}
+
+// initializeNodeTimers is used when a leader is newly elected to reset the
+// timers of all the nodes registered with a TTL.
+func (s *Server) initializeNodeTimers() error {
+	_, nodes, err := s.fsm.State().Nodes(nil, structs.WildcardEnterpriseMetaInPartition(structs.WildcardSpecifier))
+	if err != nil {
+		return err
+	}
+	for _, node := range nodes {
+		if err := s.resetNodeTimer(node); err != nil {
+			s.logger.Error("Failed to reset node TTL", "node", node.Node, "error", err)
+		}
+	}
+	return nil
+}
+
+// resetNodeTimer is used to renew the TTL of a node. The timer of a node that
+// has no TTL is stopped.
+func (s *Server) resetNodeTimer(node *structs.Node) error {
+	ttl, err := nodeTTL(node.Meta)
+	if err != nil {
+		return err
+	}
+
+	name, entMeta := node.Node, node.GetEnterpriseMeta()
+	id := structs.NodeNameString(name, entMeta)
+	if ttl == 0 {
+		s.nodeTimers.Stop(id)
+		return nil
+	}
+
+	// As for sessions, the TTL is adjusted by a multiplier to give the
+	// registrar a grace period.
+	ttl = ttl * structs.NodeTTLMultiplier
+	s.nodeTimers.ResetOrCreate(id, ttl, func() { s.expireNode(name, entMeta) })
+	return nil
+}
+
+// expireNode is invoked when the TTL of a node is reached and we need to
+// deregister it along with its services and checks.
+func (s *Server) expireNode(name string, entMeta *structs.EnterpriseMeta) {
+	defer metrics.MeasureSince([]string{"node_ttl", "expire"}, time.Now())
+
+	// Clear the node timer
+	id := structs.NodeNameString(name, entMeta)
+	s.nodeTimers.Del(id)
+
+	// The node may have been registered again without a TTL meanwhile.
+	_, node, err := s.fsm.State().GetNode(name, entMeta)
+	if err != nil {
+		s.logger.Error("Node lookup failed", "node", id, "error", err)
+		return
+	}
+	if node == nil {
+		return
+	}
+	if ttl, _ := nodeTTL(node.Meta); ttl == 0 {
+		return
+	}
+
+	args := structs.DeregisterRequest{
+		Datacenter:     s.config.Datacenter,
+		Node:           name,
+		EnterpriseMeta: *entMeta,
+	}
+
+	// Retry with exponential backoff to deregister the node
+	for attempt := uint(0); attempt < maxInvalidateAttempts; attempt++ {
+		_, err := s.raftApply(structs.DeregisterRequestType, &args)
+		if err == nil {
+			s.logger.Info("Node TTL expired, deregistered node", "node", id)
+			if err := s.fireNodeTTLExpiredEvent(id); err != nil {
+				s.logger.Warn("Failed to fire node TTL expired event", "node", id, "error", err)
+			}
+			return
+		}
+
+		s.logger.Error("Node deregistration failed", "node", id, "error", err)
+		time.Sleep((1 << attempt) * invalidateRetryBase)
+	}
+	s.logger.Error("maximum deregistration attempts reached for node", "node", id)
+}
+
+// clearNodeTimer is used to clear the timer of a node that was deregistered
+// explicitly.
+func (s *Server) clearNodeTimer(name string, entMeta *structs.EnterpriseMeta) {
+	s.nodeTimers.Stop(structs.NodeNameString(name, entMeta))
+}
+
+// clearAllNodeTimers is used when a leader is stepping down and we no longer
+// need to track any node timers.
+func (s *Server) clearAllNodeTimers() {
+	s.nodeTimers.StopAll()
+}
+
+// fireNodeTTLExpiredEvent fires a user event in the datacenter to let the
+// registrars know that a node was deregistered.
+func (s *Server) fireNodeTTLExpiredEvent(node string) error {
+	id, err := uuid.GenerateUUID()
+	if err != nil {
+		return err
+	}
+
+	event := structs.UserEvent{
+		ID:      id,
+		Name:    nodeTTLExpiredEvent,
+		Payload: []byte(node),
+		Version: structs.UserEventMaxVersion,
+	}
+	payload, err := structs.EncodeMsgPackUserEvent(&event)
+	if err != nil {
+		return err
+	}
+	return s.LANSendUserEvent(userEventName(nodeTTLExpiredEvent), payload, false)
+}
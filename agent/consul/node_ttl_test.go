@@ -0,0 +1,353 @@
+package consul
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/serf/serf"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
+	"github.com/hashicorp/consul/testrpc"
+)
+
+func TestValidateNodeTTL(t *testing.T) {
+	cases := map[string]struct {
+		meta map[string]string
+		err  string
+	}{
+		"no TTL":    {meta: map[string]string{"foo": "bar"}},
+		"valid":     {meta: map[string]string{structs.MetaNodeTTL: "30s"}},
+		"invalid":   {meta: map[string]string{structs.MetaNodeTTL: "soon"}, err: "Invalid Node TTL 'soon'"},
+		"too short": {meta: map[string]string{structs.MetaNodeTTL: "500ms"}, err: "Node TTL '500ms' must be between"},
+		"too long":  {meta: map[string]string{structs.MetaNodeTTL: "48h"}, err: "Node TTL '48h' must be between"},
+		"zero":      {meta: map[string]string{structs.MetaNodeTTL: "0s"}, err: "Node TTL '0s' must be between"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := validateNodeTTL(tc.meta)
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+			}
+		})
+	}
+}
+
+func TestInitializeNodeTimers(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	dir1, s1 := testServer(t)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	state := s1.fsm.State()
+	require.NoError(t, state.EnsureNode(1, &structs.Node{
+		Node:    "foo",
+		Address: "127.0.0.1",
+		Meta:    map[string]string{structs.MetaNodeTTL: "10s"},
+	}))
+	require.NoError(t, state.EnsureNode(2, &structs.Node{Node: "bar", Address: "127.0.0.2"}))
+
+	// Reset the node timers
+	require.NoError(t, s1.initializeNodeTimers())
+
+	require.NotNil(t, s1.nodeTimers.Get("foo"))
+	require.Nil(t, s1.nodeTimers.Get("bar"))
+
+	s1.clearAllNodeTimers()
+	require.Equal(t, 0, s1.nodeTimers.Len())
+}
+
+func TestCatalog_Register_NodeTTL(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	dir1, s1 := testServer(t)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	register := func(ttl string) error {
+		arg := structs.RegisterRequest{
+			Datacenter: "dc1",
+			Node:       "foo",
+			Address:    "127.0.0.1",
+			Service: &structs.NodeService{
+				Service: "db",
+				Port:    8000,
+			},
+		}
+		if ttl != "" {
+			arg.NodeMeta = map[string]string{structs.MetaNodeTTL: ttl}
+		}
+		var out struct{}
+		return msgpackrpc.CallWithCodec(codec, "Catalog.Register", &arg, &out)
+	}
+
+	err := register("soon")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Invalid Node TTL")
+	require.Nil(t, s1.nodeTimers.Get("foo"))
+
+	// Registering the node with a TTL starts its timer.
+	require.NoError(t, register("10s"))
+	require.NotNil(t, s1.nodeTimers.Get("foo"))
+
+	// Registering it again without a TTL stops it.
+	require.NoError(t, register(""))
+	require.Nil(t, s1.nodeTimers.Get("foo"))
+
+	// Deregistering the node stops it as well.
+	require.NoError(t, register("10s"))
+	require.NotNil(t, s1.nodeTimers.Get("foo"))
+
+	dereg := structs.DeregisterRequest{
+		Datacenter: "dc1",
+		Node:       "foo",
+	}
+	var out struct{}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Catalog.Deregister", &dereg, &out))
+	require.Nil(t, s1.nodeTimers.Get("foo"))
+}
+
+func TestTxn_Apply_NodeTTL(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	dir1, s1 := testServer(t)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	apply := func(verb api.NodeOp, ttl string) structs.TxnResponse {
+		node := structs.Node{Node: "foo", Address: "127.0.0.1"}
+		if ttl != "" {
+			node.Meta = map[string]string{structs.MetaNodeTTL: ttl}
+		}
+		arg := structs.TxnRequest{
+			Datacenter: "dc1",
+			Ops: structs.TxnOps{
+				&structs.TxnOp{Node: &structs.TxnNodeOp{Verb: verb, Node: node}},
+			},
+		}
+		var out structs.TxnResponse
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "Txn.Apply", &arg, &out))
+		return out
+	}
+
+	out := apply(api.NodeSet, "48h")
+	require.Len(t, out.Errors, 1)
+	require.Contains(t, out.Errors[0].What, "Node TTL '48h' must be between")
+	require.Nil(t, s1.nodeTimers.Get("foo"))
+
+	// Writing the node with a TTL starts its timer.
+	require.Empty(t, apply(api.NodeSet, "10s").Errors)
+	require.NotNil(t, s1.nodeTimers.Get("foo"))
+
+	// Writing it again without a TTL stops it.
+	require.Empty(t, apply(api.NodeSet, "").Errors)
+	require.Nil(t, s1.nodeTimers.Get("foo"))
+
+	// Deleting the node stops it as well.
+	require.Empty(t, apply(api.NodeSet, "10s").Errors)
+	require.NotNil(t, s1.nodeTimers.Get("foo"))
+	require.Empty(t, apply(api.NodeDelete, "").Errors)
+	require.Nil(t, s1.nodeTimers.Get("foo"))
+}
+
+func TestCatalog_RenewNode(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	dir1, s1 := testServer(t)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	state := s1.fsm.State()
+	require.NoError(t, state.EnsureNode(1, &structs.Node{
+		Node:    "foo",
+		Address: "127.0.0.1",
+		Meta:    map[string]string{structs.MetaNodeTTL: "10s"},
+	}))
+	require.NoError(t, state.EnsureNode(2, &structs.Node{Node: "bar", Address: "127.0.0.2"}))
+
+	renew := func(node string) (structs.IndexedNodes, error) {
+		arg := structs.NodeSpecificRequest{
+			Datacenter: "dc1",
+			Node:       node,
+		}
+		var out structs.IndexedNodes
+		err := msgpackrpc.CallWithCodec(codec, "Catalog.RenewNode", &arg, &out)
+		return out, err
+	}
+
+	out, err := renew("foo")
+	require.NoError(t, err)
+	require.Len(t, out.Nodes, 1)
+	require.Equal(t, "foo", out.Nodes[0].Node)
+	require.NotNil(t, s1.nodeTimers.Get("foo"))
+
+	_, err = renew("bar")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `Node "bar" has no TTL`)
+	require.Nil(t, s1.nodeTimers.Get("bar"))
+
+	out, err = renew("nope")
+	require.NoError(t, err)
+	require.Nil(t, out.Nodes)
+}
+
+func TestExpireNode(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	events := make(chan serf.UserEvent, 8)
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.UserEventHandler = func(e serf.UserEvent) {
+			if e.Name == nodeTTLExpiredEvent {
+				events <- e
+			}
+		}
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	client := rpcClient(t, s1)
+	defer client.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	arg := structs.RegisterRequest{
+		Datacenter: "dc1",
+		Node:       "foo",
+		Address:    "127.0.0.1",
+		NodeMeta:   map[string]string{structs.MetaNodeTTL: "1s"},
+		Service: &structs.NodeService{
+			Service: "db",
+			Port:    8000,
+		},
+	}
+	var out struct{}
+	require.NoError(t, msgpackrpc.CallWithCodec(client, "Catalog.Register", &arg, &out))
+
+	// The node is deregistered along with its services once the TTL,
+	// adjusted by the multiplier, expires.
+	state := s1.fsm.State()
+	retry.Run(t, func(r *retry.R) {
+		_, node, err := state.GetNode("foo", nil)
+		require.NoError(r, err)
+		require.Nil(r, node)
+	})
+	_, services, err := state.ServiceNodes(nil, "db", nil)
+	require.NoError(t, err)
+	require.Empty(t, services)
+	require.Nil(t, s1.nodeTimers.Get("foo"))
+
+	select {
+	case e := <-events:
+		var event structs.UserEvent
+		require.NoError(t, structs.DecodeMsgPackUserEvent(e.Payload, &event))
+		require.Equal(t, nodeTTLExpiredEvent, event.Name)
+		require.Equal(t, "foo", string(event.Payload))
+		require.NotEmpty(t, event.ID)
+	case <-time.After(5 * time.Second):
+		t.Fatal("missing node TTL expired event")
+	}
+}
+
+func TestExpireNode_TTLRemoved(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	dir1, s1 := testServer(t)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	// A node registered again without a TTL right before its timer fired
+	// must not be deregistered.
+	state := s1.fsm.State()
+	require.NoError(t, state.EnsureNode(1, &structs.Node{Node: "foo", Address: "127.0.0.1"}))
+	s1.expireNode("foo", structs.NodeEnterpriseMetaInDefaultPartition())
+
+	_, node, err := state.GetNode("foo", nil)
+	require.NoError(t, err)
+	require.NotNil(t, node)
+}
+
+func TestCatalog_RenewNode_ForwardedToLeader(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	dir1, s1 := testServer(t)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	dir2, s2 := testServerWithConfig(t, func(c *Config) {
+		c.Bootstrap = false
+	})
+	defer os.RemoveAll(dir2)
+	defer s2.Shutdown()
+
+	joinLAN(t, s2, s1)
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+	testrpc.WaitForLeader(t, s2.RPC, "dc1")
+
+	arg := structs.RegisterRequest{
+		Datacenter: "dc1",
+		Node:       "foo",
+		Address:    "127.0.0.1",
+		NodeMeta:   map[string]string{structs.MetaNodeTTL: "10s"},
+	}
+	var out struct{}
+	require.NoError(t, s1.RPC("Catalog.Register", &arg, &out))
+	s1.clearAllNodeTimers()
+
+	// A stale renewal sent to the follower must still reach the leader,
+	// which is the only one tracking the node timers.
+	renew := structs.NodeSpecificRequest{
+		Datacenter:   "dc1",
+		Node:         "foo",
+		QueryOptions: structs.QueryOptions{AllowStale: true},
+	}
+	retry.Run(t, func(r *retry.R) {
+		var nodes structs.IndexedNodes
+		require.NoError(r, s2.RPC("Catalog.RenewNode", &renew, &nodes))
+		require.Len(r, nodes.Nodes, 1)
+	})
+	require.NotNil(t, s1.nodeTimers.Get("foo"))
+	require.Nil(t, s2.nodeTimers.Get("foo"))
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net"
 
+	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/raft"
 	"github.com/hashicorp/serf/serf"
 
@@ -183,3 +184,69 @@ REMOVE:
 	op.logger.Warn("Removed Raft peer with id", "peer_id", args.ID)
 	return nil
 }
+
+// RaftLeaderTransfer is used to transfer the Raft leadership away from the
+// current leader, to the server with the given ID if one is set. It allows the
+// leader to be restarted with a single election. The reply argument is not
+// used, but is required to fulfill the RPC interface.
+func (op *Operator) RaftLeaderTransfer(args *structs.RaftLeaderTransferRequest, reply *struct{}) error {
+	if done, err := op.srv.ForwardRPC("Operator.RaftLeaderTransfer", args, reply); done {
+		return err
+	}
+
+	// This action requires operator write access.
+	identity, authz, err := op.srv.acls.ResolveTokenToIdentityAndAuthorizer(args.Token)
+	if err != nil {
+		return err
+	}
+	if err := op.srv.validateEnterpriseToken(identity); err != nil {
+		return err
+	}
+	if authz.OperatorWrite(nil) != acl.Allow {
+		return acl.ErrPermissionDenied
+	}
+
+	minVersion := version.Must(version.NewVersion(LeaderTransferMinVersion))
+	if ok, _ := ServersInDCMeetMinimumVersion(op.srv, op.srv.config.Datacenter, minVersion); !ok {
+		return fmt.Errorf("all servers must be running Consul %s or later to transfer the leadership",
+			LeaderTransferMinVersion)
+	}
+
+	if args.ID == "" {
+		if err := op.srv.transferLeadership("", "").Error(); err != nil {
+			op.logger.Warn("Failed to transfer Raft leadership", "error", err)
+			return err
+		}
+		op.logger.Info("Transferred Raft leadership")
+		return nil
+	}
+
+	// As with the removal of peers, return an error if the supplied id isn't
+	// among the voters since it's likely the operator made a mistake.
+	future := op.srv.raft.GetConfiguration()
+	if err := future.Error(); err != nil {
+		return err
+	}
+	for _, s := range future.Configuration().Servers {
+		if s.ID != args.ID {
+			continue
+		}
+		if s.Suffrage != raft.Voter {
+			return fmt.Errorf("server with id %q is not a voter and cannot become the leader", args.ID)
+		}
+		if s.ID == raft.ServerID(op.srv.config.NodeID) {
+			return fmt.Errorf("server with id %q is already the leader", args.ID)
+		}
+
+		if err := op.srv.transferLeadership(s.ID, s.Address).Error(); err != nil {
+			op.logger.Warn("Failed to transfer Raft leadership",
+				"peer_id", args.ID,
+				"error", err,
+			)
+			return err
+		}
+		op.logger.Info("Transferred Raft leadership", "peer_id", args.ID)
+		return nil
+	}
+	return fmt.Errorf("id %q was not found in the Raft configuration", args.ID)
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/hashicorp/consul/acl"
 	"github.com/hashicorp/consul/agent/structs"
 	"github.com/hashicorp/consul/sdk/freeport"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
 	"github.com/hashicorp/consul/testrpc"
 )
 
@@ -337,3 +338,141 @@ func TestOperator_RaftRemovePeerByID_ACLDeny(t *testing.T) {
 		t.Fatalf("err: %v", err)
 	}
 }
+
+func TestOperator_RaftLeaderTransfer(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	dir1, s1 := testServer(t)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+
+	dir2, s2 := testServerDCBootstrap(t, "dc1", false)
+	defer os.RemoveAll(dir2)
+	defer s2.Shutdown()
+
+	dir3, s3 := testServerDCBootstrap(t, "dc1", false)
+	defer os.RemoveAll(dir3)
+	defer s3.Shutdown()
+
+	servers := []*Server{s1, s2, s3}
+	joinLAN(t, s2, s1)
+	joinLAN(t, s3, s1)
+	for _, s := range servers {
+		retry.Run(t, func(r *retry.R) { r.Check(wantPeers(s, 3)) })
+	}
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	leader := func(t require.TestingT) *Server {
+		for _, s := range servers {
+			if s.IsLeader() {
+				return s
+			}
+		}
+		require.Fail(t, "no leader")
+		return nil
+	}
+
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	t.Run("unknown id", func(t *testing.T) {
+		arg := structs.RaftLeaderTransferRequest{
+			Datacenter: "dc1",
+			ID:         "nope",
+		}
+		var reply struct{}
+		err := msgpackrpc.CallWithCodec(codec, "Operator.RaftLeaderTransfer", &arg, &reply)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `id "nope" was not found in the Raft configuration`)
+	})
+
+	t.Run("current leader", func(t *testing.T) {
+		arg := structs.RaftLeaderTransferRequest{
+			Datacenter: "dc1",
+			ID:         raft.ServerID(leader(t).config.NodeID),
+		}
+		var reply struct{}
+		err := msgpackrpc.CallWithCodec(codec, "Operator.RaftLeaderTransfer", &arg, &reply)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is already the leader")
+	})
+
+	t.Run("to a server", func(t *testing.T) {
+		var target *Server
+		for _, s := range servers {
+			if !s.IsLeader() {
+				target = s
+				break
+			}
+		}
+
+		arg := structs.RaftLeaderTransferRequest{
+			Datacenter: "dc1",
+			ID:         raft.ServerID(target.config.NodeID),
+		}
+		var reply struct{}
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "Operator.RaftLeaderTransfer", &arg, &reply))
+
+		retry.Run(t, func(r *retry.R) {
+			require.Equal(r, target, leader(r))
+		})
+	})
+
+	t.Run("to any server", func(t *testing.T) {
+		previous := leader(t)
+
+		arg := structs.RaftLeaderTransferRequest{
+			Datacenter: "dc1",
+		}
+		var reply struct{}
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "Operator.RaftLeaderTransfer", &arg, &reply))
+
+		retry.Run(t, func(r *retry.R) {
+			require.NotEqual(r, previous, leader(r))
+		})
+	})
+}
+
+func TestOperator_RaftLeaderTransfer_ACLDeny(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.PrimaryDatacenter = "dc1"
+		c.ACLsEnabled = true
+		c.ACLInitialManagementToken = "root"
+		c.ACLResolverSettings.ACLDefaultPolicy = "deny"
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	// Make a request with no token to make sure it gets denied.
+	arg := structs.RaftLeaderTransferRequest{
+		Datacenter: "dc1",
+		ID:         "nope",
+	}
+	var reply struct{}
+	err := msgpackrpc.CallWithCodec(codec, "Operator.RaftLeaderTransfer", &arg, &reply)
+	if !acl.IsErrPermissionDenied(err) {
+		t.Fatalf("err: %v", err)
+	}
+
+	token := createToken(t, codec, `operator = "write"`)
+
+	// Now it should kick back for an unknown server, which means it tried
+	// to do the operation.
+	arg.Token = token
+	err = msgpackrpc.CallWithCodec(codec, "Operator.RaftLeaderTransfer", &arg, &reply)
+	if err == nil || !strings.Contains(err.Error(), "was not found in the Raft configuration") {
+		t.Fatalf("err: %v", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/hashicorp/go-hclog"
 	"google.golang.org/grpc"
 
+	"github.com/hashicorp/consul/agent/audit"
 	"github.com/hashicorp/consul/agent/pool"
 	"github.com/hashicorp/consul/agent/router"
 	"github.com/hashicorp/consul/agent/token"
@@ -18,6 +19,9 @@ type Deps struct {
 	ConnPool        *pool.ConnPool
 	GRPCConnPool    GRPCClientConner
 	LeaderForwarder LeaderForwarder
+	// Auditor records the requests made to the agent. It is nil when audit
+	// logging is not enabled.
+	Auditor *audit.Auditor
 	EnterpriseDeps
 }
 
@@ -0,0 +1,279 @@
+package consul
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/raft"
+	raftboltdb "github.com/hashicorp/raft-boltdb/v2"
+	"go.etcd.io/bbolt"
+
+	"github.com/hashicorp/consul/agent/consul/wal"
+)
+
+const (
+	// RaftLogStoreBackendBoltDB stores the Raft logs in a BoltDB file.
+	RaftLogStoreBackendBoltDB = "boltdb"
+
+	// RaftLogStoreBackendWAL stores the Raft logs in the segment files of a
+	// write-ahead log.
+	RaftLogStoreBackendWAL = "wal"
+)
+
+var raftLogStoreBackends = []string{RaftLogStoreBackendBoltDB, RaftLogStoreBackendWAL}
+
+// raftStableKeys are the keys Raft persists in its stable store.
+var raftStableKeys = []string{"CurrentTerm", "LastVoteTerm", "LastVoteCand"}
+
+// raftLogStoreBackendFile is the file within the Raft directory that records
+// the backend holding the Raft state of the server.
+const raftLogStoreBackendFile = "logstore-backend"
+
+// raftLogStoreMigrateBatchSize is the number of logs copied at once by
+// MigrateRaftLogStore.
+const raftLogStoreMigrateBatchSize = 1024
+
+// RaftLogStore stores the Raft logs and the stable state of a server. Both
+// backends emit the same getLog, storeLogs, logSize, logsPerBatch and
+// logBatchSize metrics under their own prefix.
+type RaftLogStore interface {
+	raft.LogStore
+	raft.StableStore
+
+	// RunMetrics periodically emits the gauges of the backend until ctx is
+	// done.
+	RunMetrics(ctx context.Context, interval time.Duration)
+
+	Close() error
+}
+
+// RaftLogStorePath returns the path of the store of the given backend within
+// the Raft directory of a server.
+func RaftLogStorePath(raftDir, backend string) string {
+	if backend == RaftLogStoreBackendWAL {
+		return filepath.Join(raftDir, "wal")
+	}
+	return filepath.Join(raftDir, "raft.db")
+}
+
+// openRaftLogStore opens the store of the configured backend within raftDir.
+// It refuses to start when the configured backend isn't the one recorded as
+// holding the Raft state of the server, or when nothing is recorded and the
+// store of another backend exists, since the server would otherwise come up
+// without its logs or with stale ones.
+func openRaftLogStore(raftDir string, config *Config) (RaftLogStore, error) {
+	backend := config.RaftLogStoreConfig.Backend
+	if backend == "" {
+		backend = RaftLogStoreBackendBoltDB
+	}
+
+	active, err := readRaftLogStoreBackend(raftDir)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case active != "" && active != backend:
+		return nil, fmt.Errorf("the Raft log store backend is %q but the Raft state was migrated to the %q backend in %s, "+
+			"set raft_logstore.backend to %q or migrate it back with \"consul operator raft migrate-logstore\" while the server is stopped",
+			backend, active, RaftLogStorePath(raftDir, active), active)
+	case active == "":
+		for _, other := range raftLogStoreBackends {
+			if other == backend {
+				continue
+			}
+			if _, err := os.Stat(RaftLogStorePath(raftDir, other)); err == nil {
+				return nil, fmt.Errorf("the Raft log store backend is %q but the Raft state may be stored by the %q backend in %s, "+
+					"migrate it with \"consul operator raft migrate-logstore\" while the server is stopped, "+
+					"or remove the store of the backend that isn't in use",
+					backend, other, RaftLogStorePath(raftDir, other))
+			}
+		}
+	}
+
+	store, err := newRaftLogStore(raftDir, backend, config, false)
+	if err != nil {
+		return nil, err
+	}
+	if active == "" {
+		if err := writeRaftLogStoreBackend(raftDir, backend); err != nil {
+			store.Close()
+			return nil, err
+		}
+	}
+	return store, nil
+}
+
+// readRaftLogStoreBackend returns the backend recorded as holding the Raft
+// state within raftDir, or an empty string if none is recorded.
+func readRaftLogStoreBackend(raftDir string) (string, error) {
+	buf, err := ioutil.ReadFile(filepath.Join(raftDir, raftLogStoreBackendFile))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read the Raft log store backend: %v", err)
+	}
+	backend := strings.TrimSpace(string(buf))
+	for _, b := range raftLogStoreBackends {
+		if b == backend {
+			return backend, nil
+		}
+	}
+	return "", fmt.Errorf("unknown Raft log store backend %q in %s", backend, filepath.Join(raftDir, raftLogStoreBackendFile))
+}
+
+// writeRaftLogStoreBackend records the backend holding the Raft state within
+// raftDir. The file is replaced atomically so that it is never left partially
+// written.
+func writeRaftLogStoreBackend(raftDir, backend string) error {
+	path := filepath.Join(raftDir, raftLogStoreBackendFile)
+	if err := ioutil.WriteFile(path+".tmp", []byte(backend+"\n"), 0600); err != nil {
+		return fmt.Errorf("failed to record the Raft log store backend: %v", err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("failed to record the Raft log store backend: %v", err)
+	}
+	return nil
+}
+
+func newRaftLogStore(raftDir, backend string, config *Config, readOnly bool) (RaftLogStore, error) {
+	switch backend {
+	case RaftLogStoreBackendBoltDB:
+		opts := raftboltdb.Options{
+			BoltOptions: &bbolt.Options{},
+			Path:        RaftLogStorePath(raftDir, backend),
+		}
+		if readOnly {
+			// Fail instead of waiting for a running server to release the
+			// lock of the file.
+			opts.BoltOptions.ReadOnly = true
+			opts.BoltOptions.Timeout = time.Second
+		}
+		if config != nil {
+			opts.BoltOptions.NoFreelistSync = config.RaftBoltDBConfig.NoFreelistSync
+		}
+		return raftboltdb.New(opts)
+	case RaftLogStoreBackendWAL:
+		opts := wal.Options{
+			Path: RaftLogStorePath(raftDir, backend),
+		}
+		if config != nil {
+			opts.SegmentSize = config.RaftLogStoreConfig.WAL.SegmentSize
+		}
+		return wal.New(opts)
+	default:
+		return nil, fmt.Errorf("unknown Raft log store backend %q", backend)
+	}
+}
+
+// MigrateRaftLogStore copies the Raft logs and stable state of a stopped
+// server from the store of one backend to a new store of another backend,
+// both within raftDir, and records the new backend as the one holding the Raft
+// state. The source store is left untouched, it returns the number of logs
+// copied.
+func MigrateRaftLogStore(raftDir, from, to string) (uint64, error) {
+	if from == to {
+		return 0, fmt.Errorf("the source and destination backends are both %q", from)
+	}
+	srcPath := RaftLogStorePath(raftDir, from)
+	if _, err := os.Stat(srcPath); err != nil {
+		return 0, fmt.Errorf("failed to find the %q Raft log store: %v", from, err)
+	}
+	dstPath := RaftLogStorePath(raftDir, to)
+	if _, err := os.Stat(dstPath); err == nil {
+		return 0, fmt.Errorf("the %q Raft log store already exists in %s", to, dstPath)
+	}
+	active, err := readRaftLogStoreBackend(raftDir)
+	if err != nil {
+		return 0, err
+	}
+	if active != "" && active != from {
+		return 0, fmt.Errorf("the Raft state is held by the %q backend, not %q", active, from)
+	}
+
+	src, err := newRaftLogStore(raftDir, from, nil, true)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open the %q Raft log store, is the server stopped? %v", from, err)
+	}
+	defer src.Close()
+
+	// Write the destination under a temporary path so that an interrupted
+	// migration doesn't leave a partial store the server would start from.
+	tmpDir := filepath.Join(raftDir, "migrate.tmp")
+	if err := os.RemoveAll(tmpDir); err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(tmpDir, 0700); err != nil {
+		return 0, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	dst, err := newRaftLogStore(tmpDir, to, nil, false)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create the %q Raft log store: %v", to, err)
+	}
+	n, err := copyRaftLogStore(src, dst)
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if err := os.Rename(RaftLogStorePath(tmpDir, to), dstPath); err != nil {
+		return 0, err
+	}
+	if err := writeRaftLogStoreBackend(raftDir, to); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func copyRaftLogStore(src, dst RaftLogStore) (uint64, error) {
+	for _, key := range raftStableKeys {
+		v, err := src.Get([]byte(key))
+		if err != nil {
+			if err.Error() == "not found" {
+				continue
+			}
+			return 0, fmt.Errorf("failed to read %q: %v", key, err)
+		}
+		if err := dst.Set([]byte(key), v); err != nil {
+			return 0, fmt.Errorf("failed to write %q: %v", key, err)
+		}
+	}
+
+	first, err := src.FirstIndex()
+	if err != nil {
+		return 0, err
+	}
+	last, err := src.LastIndex()
+	if err != nil {
+		return 0, err
+	}
+	if first == 0 {
+		return 0, nil
+	}
+
+	batch := make([]*raft.Log, 0, raftLogStoreMigrateBatchSize)
+	for index := first; index <= last; index++ {
+		log := new(raft.Log)
+		if err := src.GetLog(index, log); err != nil {
+			return 0, fmt.Errorf("failed to read log %d: %v", index, err)
+		}
+		batch = append(batch, log)
+
+		if len(batch) == cap(batch) || index == last {
+			if err := dst.StoreLogs(batch); err != nil {
+				return 0, fmt.Errorf("failed to write logs up to %d: %v", index, err)
+			}
+			batch = batch[:0]
+		}
+	}
+	return last - first + 1, nil
+}
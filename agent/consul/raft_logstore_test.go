@@ -0,0 +1,183 @@
+package consul
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/raft"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/sdk/testutil"
+	"github.com/hashicorp/consul/testrpc"
+)
+
+func testRaftLogStoreLogs() []*raft.Log {
+	var logs []*raft.Log
+	for i := uint64(1); i <= 2*raftLogStoreMigrateBatchSize+10; i++ {
+		logs = append(logs, &raft.Log{
+			Index: i,
+			Term:  i / 100,
+			Type:  raft.LogCommand,
+			Data:  []byte{byte(i), byte(i >> 8)},
+		})
+	}
+	return logs
+}
+
+func requireRaftLogStore(t *testing.T, store RaftLogStore, logs []*raft.Log) {
+	t.Helper()
+	first, err := store.FirstIndex()
+	require.NoError(t, err)
+	require.Equal(t, logs[0].Index, first)
+	last, err := store.LastIndex()
+	require.NoError(t, err)
+	require.Equal(t, logs[len(logs)-1].Index, last)
+
+	for _, expected := range logs {
+		var log raft.Log
+		require.NoError(t, store.GetLog(expected.Index, &log))
+		require.Equal(t, expected.Term, log.Term)
+		require.Equal(t, expected.Data, log.Data)
+	}
+
+	term, err := store.GetUint64([]byte("CurrentTerm"))
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), term)
+	cand, err := store.Get([]byte("LastVoteCand"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("server-1"), cand)
+}
+
+func TestMigrateRaftLogStore(t *testing.T) {
+	raftDir := testutil.TempDir(t, "raft")
+	logs := testRaftLogStoreLogs()
+
+	store, err := newRaftLogStore(raftDir, RaftLogStoreBackendBoltDB, nil, false)
+	require.NoError(t, err)
+	require.NoError(t, store.StoreLogs(logs))
+	require.NoError(t, store.SetUint64([]byte("CurrentTerm"), 7))
+	require.NoError(t, store.Set([]byte("LastVoteCand"), []byte("server-1")))
+	require.NoError(t, store.Close())
+
+	_, err = MigrateRaftLogStore(raftDir, RaftLogStoreBackendBoltDB, RaftLogStoreBackendBoltDB)
+	require.Error(t, err)
+
+	n, err := MigrateRaftLogStore(raftDir, RaftLogStoreBackendBoltDB, RaftLogStoreBackendWAL)
+	require.NoError(t, err)
+	require.Equal(t, uint64(len(logs)), n)
+	_, err = os.Stat(filepath.Join(raftDir, "migrate.tmp"))
+	require.True(t, os.IsNotExist(err))
+
+	// The destination is never overwritten.
+	_, err = MigrateRaftLogStore(raftDir, RaftLogStoreBackendBoltDB, RaftLogStoreBackendWAL)
+	require.Error(t, err)
+
+	store, err = newRaftLogStore(raftDir, RaftLogStoreBackendWAL, nil, false)
+	require.NoError(t, err)
+	requireRaftLogStore(t, store, logs)
+	require.NoError(t, store.Close())
+
+	// Migrate back to a fresh BoltDB store.
+	require.NoError(t, os.Remove(RaftLogStorePath(raftDir, RaftLogStoreBackendBoltDB)))
+	n, err = MigrateRaftLogStore(raftDir, RaftLogStoreBackendWAL, RaftLogStoreBackendBoltDB)
+	require.NoError(t, err)
+	require.Equal(t, uint64(len(logs)), n)
+
+	store, err = newRaftLogStore(raftDir, RaftLogStoreBackendBoltDB, nil, false)
+	require.NoError(t, err)
+	requireRaftLogStore(t, store, logs)
+	require.NoError(t, store.Close())
+}
+
+func TestOpenRaftLogStore_BackendMismatch(t *testing.T) {
+	raftDir := testutil.TempDir(t, "raft")
+
+	store, err := newRaftLogStore(raftDir, RaftLogStoreBackendBoltDB, nil, false)
+	require.NoError(t, err)
+	require.NoError(t, store.Close())
+
+	config := DefaultConfig()
+	config.RaftLogStoreConfig.Backend = RaftLogStoreBackendWAL
+	_, err = openRaftLogStore(raftDir, config)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "migrate-logstore")
+
+	config.RaftLogStoreConfig.Backend = RaftLogStoreBackendBoltDB
+	store, err = openRaftLogStore(raftDir, config)
+	require.NoError(t, err)
+	require.NoError(t, store.Close())
+
+	// The backend holding the Raft state is recorded on the first start.
+	active, err := readRaftLogStoreBackend(raftDir)
+	require.NoError(t, err)
+	require.Equal(t, RaftLogStoreBackendBoltDB, active)
+}
+
+func TestOpenRaftLogStore_AfterMigration(t *testing.T) {
+	raftDir := testutil.TempDir(t, "raft")
+
+	config := DefaultConfig()
+	config.RaftLogStoreConfig.Backend = RaftLogStoreBackendBoltDB
+	store, err := openRaftLogStore(raftDir, config)
+	require.NoError(t, err)
+	require.NoError(t, store.Close())
+
+	_, err = MigrateRaftLogStore(raftDir, RaftLogStoreBackendBoltDB, RaftLogStoreBackendWAL)
+	require.NoError(t, err)
+	active, err := readRaftLogStoreBackend(raftDir)
+	require.NoError(t, err)
+	require.Equal(t, RaftLogStoreBackendWAL, active)
+
+	// Switching back to the stale BoltDB store is refused.
+	_, err = openRaftLogStore(raftDir, config)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `migrated to the "wal" backend`)
+
+	// So is migrating from it again.
+	require.NoError(t, os.RemoveAll(RaftLogStorePath(raftDir, RaftLogStoreBackendWAL)))
+	_, err = MigrateRaftLogStore(raftDir, RaftLogStoreBackendBoltDB, RaftLogStoreBackendWAL)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `held by the "wal" backend`)
+}
+
+func TestOpenRaftLogStore_BothStoresWithoutBackendFile(t *testing.T) {
+	raftDir := testutil.TempDir(t, "raft")
+
+	for _, backend := range raftLogStoreBackends {
+		store, err := newRaftLogStore(raftDir, backend, nil, false)
+		require.NoError(t, err)
+		require.NoError(t, store.Close())
+	}
+
+	config := DefaultConfig()
+	for _, backend := range raftLogStoreBackends {
+		config.RaftLogStoreConfig.Backend = backend
+		_, err := openRaftLogStore(raftDir, config)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "remove the store of the backend that isn't in use")
+	}
+}
+
+func TestServer_RaftLogStoreWAL(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	dir, s := testServerWithConfig(t, func(c *Config) {
+		c.RaftLogStoreConfig.Backend = RaftLogStoreBackendWAL
+	})
+	defer os.RemoveAll(dir)
+	defer s.Shutdown()
+	testrpc.WaitForLeader(t, s.RPC, "dc1")
+
+	raftDir := filepath.Join(dir, raftState)
+	require.DirExists(t, RaftLogStorePath(raftDir, RaftLogStoreBackendWAL))
+	_, err := os.Stat(RaftLogStorePath(raftDir, RaftLogStoreBackendBoltDB))
+	require.True(t, os.IsNotExist(err))
+
+	last, err := s.raftStore.LastIndex()
+	require.NoError(t, err)
+	require.NotZero(t, last)
+}
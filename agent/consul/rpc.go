@@ -59,6 +59,10 @@ var RPCCounters = []prometheus.CounterDefinition{
 		Name: []string{"rpc", "query"},
 		Help: "Increments when a server receives a read request, indicating the rate of new read queries.",
 	},
+	{
+		Name: []string{"rpc", "consistentRead", "lease"},
+		Help: "Increments when a consistent read is served under the leader lease, without confirming the leadership.",
+	},
 }
 
 var RPCGauges = []prometheus.GaugeDefinition{
@@ -406,7 +410,7 @@ func (s *Server) handleMultiplexV2(conn net.Conn) {
 // handleConsulConn is used to service a single Consul RPC connection
 func (s *Server) handleConsulConn(conn net.Conn) {
 	defer conn.Close()
-	rpcCodec := msgpackrpc.NewCodecFromHandle(true, true, conn, structs.MsgpackHandle)
+	rpcCodec := newAuditCodec(s, msgpackrpc.NewCodecFromHandle(true, true, conn, structs.MsgpackHandle), conn)
 	for {
 		select {
 		case <-s.shutdownCh:
@@ -1015,9 +1019,14 @@ func (s *Server) setQueryMeta(m structs.QueryMetaCompat, token string) {
 }
 
 // consistentRead is used to ensure we do not perform a stale
-// read. This is done by verifying leadership before the read.
+// read. This is done by verifying leadership before the read, unless the
+// leader holds a lease on the leadership.
 func (s *Server) consistentRead() error {
 	defer metrics.MeasureSince([]string{"rpc", "consistentRead"}, time.Now())
+	if s.holdsLeaderLease() {
+		metrics.IncrCounter([]string{"rpc", "consistentRead", "lease"}, 1)
+		return nil
+	}
 	future := s.raft.VerifyLeader()
 	if err := future.Error(); err != nil {
 		return err //fail fast if leader verification fails
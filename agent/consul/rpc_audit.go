@@ -0,0 +1,151 @@
+package consul
+
+import (
+	"errors"
+	"net"
+	"net/rpc"
+	"time"
+
+	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/agent/audit"
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+// auditCodec wraps the codec of an RPC connection to record the write
+// requests it serves in the audit log. Requests forwarded to another server
+// are recorded by each of the servers they go through.
+type auditCodec struct {
+	rpc.ServerCodec
+
+	server     *Server
+	auditor    *audit.Auditor
+	remoteAddr string
+
+	// pending is the request being served, set when it is to be recorded.
+	// The RPC server serves the requests of a connection one at a time.
+	pending *audit.Event
+	method  string
+	start   time.Time
+}
+
+func newAuditCodec(s *Server, codec rpc.ServerCodec, conn net.Conn) rpc.ServerCodec {
+	if s.auditor == nil {
+		return codec
+	}
+	return &auditCodec{
+		ServerCodec: codec,
+		server:      s,
+		auditor:     s.auditor,
+		remoteAddr:  conn.RemoteAddr().String(),
+	}
+}
+
+func (c *auditCodec) ReadRequestHeader(req *rpc.Request) error {
+	if err := c.ServerCodec.ReadRequestHeader(req); err != nil {
+		return err
+	}
+	c.pending = nil
+	c.method = req.ServiceMethod
+	c.start = time.Now()
+	return nil
+}
+
+func (c *auditCodec) ReadRequestBody(body interface{}) error {
+	if err := c.ServerCodec.ReadRequestBody(body); err != nil {
+		return err
+	}
+
+	info, ok := body.(structs.RPCInfo)
+	if !ok || info.IsRead() || !c.auditor.Enabled(c.method, audit.MethodRPC) {
+		return nil
+	}
+
+	var accessorID string
+	if ident, err := c.server.ResolveTokenToIdentity(info.TokenSecret()); err == nil && ident != nil {
+		accessorID = ident.ID()
+	}
+
+	c.pending = &audit.Event{
+		Type: audit.EventTypeRPC,
+		Auth: audit.Auth{AccessorID: accessorID},
+		Request: audit.Request{
+			Endpoint:   c.method,
+			Method:     audit.MethodRPC,
+			Resource:   auditRPCResource(body),
+			RemoteAddr: c.remoteAddr,
+		},
+	}
+	return nil
+}
+
+func (c *auditCodec) WriteResponse(resp *rpc.Response, body interface{}) error {
+	err := c.ServerCodec.WriteResponse(resp, body)
+
+	if event := c.pending; event != nil && resp.ServiceMethod == c.method {
+		c.pending = nil
+
+		event.Response.LatencyMS = audit.Latency(time.Since(c.start))
+		switch {
+		case resp.Error == "":
+			event.Response.Outcome = audit.OutcomeSuccess
+		case acl.IsErrPermissionDenied(errors.New(resp.Error)):
+			event.Response.Outcome = audit.OutcomeDenied
+			event.Response.Error = resp.Error
+		default:
+			event.Response.Outcome = audit.OutcomeError
+			event.Response.Error = resp.Error
+		}
+		c.auditor.Record(event)
+	}
+
+	return err
+}
+
+// auditRPCResource returns the resource a write request is made for, or an
+// empty string when the request is not specific to a resource.
+func auditRPCResource(body interface{}) string {
+	switch req := body.(type) {
+	case *structs.KVSRequest:
+		return req.DirEnt.Key
+	case *structs.RegisterRequest:
+		if req.Service != nil {
+			return req.Node + "/" + req.Service.ID
+		}
+		return req.Node
+	case *structs.DeregisterRequest:
+		if req.ServiceID != "" {
+			return req.Node + "/" + req.ServiceID
+		}
+		if req.CheckID != "" {
+			return req.Node + "/" + string(req.CheckID)
+		}
+		return req.Node
+	case *structs.SessionRequest:
+		return req.Session.ID
+	case *structs.IntentionRequest:
+		if req.Intention != nil {
+			return req.Intention.ID
+		}
+	case *structs.ConfigEntryRequest:
+		if req.Entry != nil {
+			return req.Entry.GetKind() + "/" + req.Entry.GetName()
+		}
+	case *structs.PreparedQueryRequest:
+		if req.Query != nil {
+			return req.Query.ID
+		}
+	case *structs.ACLTokenSetRequest:
+		return req.ACLToken.AccessorID
+	case *structs.ACLTokenDeleteRequest:
+		return req.TokenID
+	case *structs.ACLPolicySetRequest:
+		return req.Policy.ID
+	case *structs.ACLPolicyDeleteRequest:
+		return req.PolicyID
+	case *structs.ACLRoleSetRequest:
+		return req.Role.ID
+	case *structs.ACLRoleDeleteRequest:
+		return req.RoleID
+	}
+	return ""
+}
@@ -0,0 +1,126 @@
+package consul
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/audit"
+	"github.com/hashicorp/consul/agent/structs"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/sdk/testutil"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
+	"github.com/hashicorp/consul/testrpc"
+)
+
+func TestRPC_Audit(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	dir, config := testServerConfig(t)
+	defer os.RemoveAll(dir)
+	testServerACLConfig(config)
+	config.ACLResolverSettings.ACLsEnabled = config.ACLsEnabled
+	config.ACLResolverSettings.NodeName = config.NodeName
+	config.ACLResolverSettings.Datacenter = config.Datacenter
+	config.ACLResolverSettings.EnterpriseMeta = *config.AgentEnterpriseMeta()
+
+	auditDir := testutil.TempDir(t, "audit")
+	deps := newDefaultDeps(t, config)
+	auditor, err := audit.New(audit.Config{
+		Enabled: true,
+		Sinks: map[string]audit.SinkConfig{
+			"main": {
+				Type:   audit.SinkTypeFile,
+				Format: audit.SinkFormatJSON,
+				Path:   filepath.Join(auditDir, "audit.json"),
+			},
+		},
+		IncludeEndpoints: []string{"KVS.*"},
+	}, deps.Logger)
+	require.NoError(t, err)
+	defer auditor.Close()
+	deps.Auditor = auditor
+
+	s1, err := NewServer(config, deps)
+	require.NoError(t, err)
+	defer s1.Shutdown()
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	apply := structs.KVSRequest{
+		Datacenter:   "dc1",
+		Op:           api.KVSet,
+		DirEnt:       structs.DirEntry{Key: "foo", Value: []byte("bar")},
+		WriteRequest: structs.WriteRequest{Token: TestDefaultInitialManagementToken},
+	}
+	var out bool
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "KVS.Apply", &apply, &out))
+
+	// Reads are not recorded.
+	get := structs.KeyRequest{
+		Datacenter:   "dc1",
+		Key:          "foo",
+		QueryOptions: structs.QueryOptions{Token: TestDefaultInitialManagementToken},
+	}
+	var dirent structs.IndexedDirEntries
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "KVS.Get", &get, &dirent))
+
+	denied := structs.KVSRequest{
+		Datacenter: "dc1",
+		Op:         api.KVSet,
+		DirEnt:     structs.DirEntry{Key: "secret", Value: []byte("bar")},
+	}
+	err = msgpackrpc.CallWithCodec(codec, "KVS.Apply", &denied, &out)
+	require.Error(t, err)
+
+	_, management, err := s1.fsm.State().ACLTokenGetBySecret(nil, TestDefaultInitialManagementToken, nil)
+	require.NoError(t, err)
+	require.NotNil(t, management)
+
+	retry.Run(t, func(r *retry.R) {
+		events := readAuditEvents(r, auditDir)
+		require.Len(r, events, 2)
+
+		require.Equal(r, audit.EventTypeRPC, events[0].Type)
+		require.Equal(r, management.AccessorID, events[0].Auth.AccessorID)
+		require.Equal(r, "KVS.Apply", events[0].Request.Endpoint)
+		require.Equal(r, audit.MethodRPC, events[0].Request.Method)
+		require.Equal(r, "foo", events[0].Request.Resource)
+		require.Equal(r, audit.OutcomeSuccess, events[0].Response.Outcome)
+
+		require.Equal(r, "secret", events[1].Request.Resource)
+		require.Equal(r, audit.OutcomeDenied, events[1].Response.Outcome)
+		require.NotEmpty(r, events[1].Response.Error)
+	})
+}
+
+func readAuditEvents(t require.TestingT, dir string) []audit.Event {
+	files, err := filepath.Glob(filepath.Join(dir, "audit-*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	f, err := os.Open(files[0])
+	require.NoError(t, err)
+	defer f.Close()
+
+	var events []audit.Event
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var event audit.Event
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		events = append(events, event)
+	}
+	require.NoError(t, scanner.Err())
+	return events
+}
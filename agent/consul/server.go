@@ -18,7 +18,6 @@ import (
 	"time"
 
 	"github.com/hashicorp/go-version"
-	"go.etcd.io/bbolt"
 
 	"github.com/armon/go-metrics"
 	connlimit "github.com/hashicorp/go-connlimit"
@@ -26,12 +25,12 @@ import (
 	"github.com/hashicorp/go-memdb"
 	"github.com/hashicorp/raft"
 	autopilot "github.com/hashicorp/raft-autopilot"
-	raftboltdb "github.com/hashicorp/raft-boltdb/v2"
 	"github.com/hashicorp/serf/serf"
 	"golang.org/x/time/rate"
 	"google.golang.org/grpc"
 
 	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/agent/audit"
 	"github.com/hashicorp/consul/agent/consul/authmethod"
 	"github.com/hashicorp/consul/agent/consul/authmethod/ssoauth"
 	"github.com/hashicorp/consul/agent/consul/fsm"
@@ -108,6 +107,8 @@ const (
 	caRootPruningRoutineName              = "CA root pruning"
 	caRootMetricRoutineName               = "CA root expiration metric"
 	caSigningMetricRoutineName            = "CA signing expiration metric"
+	canaryControllerRoutineName           = "canary controller"
+	leaderLeaseRoutineName                = "leader lease"
 	configReplicationRoutineName          = "config entry replication"
 	federationStateReplicationRoutineName = "federation state replication"
 	federationStateAntiEntropyRoutineName = "federation state anti-entropy"
@@ -145,6 +146,18 @@ type Server struct {
 
 	aclAuthMethodValidators authmethod.Cache
 
+	// loginChallengeKeyLock serializes the creation of the key signing the
+	// login challenges.
+	loginChallengeKeyLock sync.Mutex
+
+	// auditor records the write requests served by this server. It is nil
+	// when audit logging is not enabled.
+	auditor *audit.Auditor
+
+	// aclTokenUsage tracks when tokens were last used until the usage is
+	// committed through Raft.
+	aclTokenUsage *aclTokenUsageTracker
+
 	// autopilot is the Autopilot instance for this server.
 	autopilot *autopilot.Autopilot
 
@@ -209,7 +222,7 @@ type Server struct {
 	// the state directly.
 	raft          *raft.Raft
 	raftLayer     *RaftLayer
-	raftStore     *raftboltdb.BoltStore
+	raftStore     RaftLogStore
 	raftTransport *raft.NetworkTransport
 	raftInmem     *raft.InmemStore
 
@@ -227,6 +240,10 @@ type Server struct {
 	// barrier. This is updated atomically.
 	readyForConsistentReads int32
 
+	// leaderLease tracks when this server can serve consistent reads without
+	// confirming its leadership first, see LeaderLeaseReadsConfig.
+	leaderLease *leaderLease
+
 	// leaveCh is used to signal that the server is leaving the cluster
 	// and trying to shed its RPC traffic onto other Consul servers. This
 	// is only ever closed.
@@ -294,6 +311,11 @@ type Server struct {
 	// destroy the session via standard session destroy processing
 	sessionTimers *SessionTimers
 
+	// nodeTimers track the expiration time of each catalog node that has a
+	// TTL. On expiration, the node is deregistered along with its services
+	// and checks.
+	nodeTimers *SessionTimers
+
 	// statsFetcher is used by autopilot to check the status of the other
 	// Consul router.
 	statsFetcher *StatsFetcher
@@ -367,6 +389,7 @@ func NewServer(config *Config, flat Deps) (*Server, error) {
 	s := &Server{
 		config:                  config,
 		tokens:                  flat.Tokens,
+		auditor:                 flat.Auditor,
 		connPool:                flat.ConnPool,
 		grpcConnPool:            flat.GRPCConnPool,
 		eventChLAN:              make(chan serf.Event, serfEventChSize),
@@ -381,11 +404,13 @@ func NewServer(config *Config, flat Deps) (*Server, error) {
 		tlsConfigurator:         flat.TLSConfigurator,
 		reassertLeaderCh:        make(chan chan error),
 		sessionTimers:           NewSessionTimers(),
+		nodeTimers:              NewSessionTimers(),
 		tombstoneGC:             gc,
 		serverLookup:            NewServerLookup(),
 		shutdownCh:              shutdownCh,
 		leaderRoutineManager:    routine.NewManager(logger.Named(logging.Leader)),
 		aclAuthMethodValidators: authmethod.NewCache(),
+		aclTokenUsage:           newACLTokenUsageTracker(),
 		fsm:                     newFSMFromConfig(flat.Logger, gc, config),
 	}
 
@@ -443,6 +468,8 @@ func NewServer(config *Config, flat Deps) (*Server, error) {
 		return nil, err
 	}
 
+	s.leaderLease = newLeaderLease(s.config.RaftConfig.HeartbeatTimeout, s.config.LeaderLeaseReads.MaxClockSkew)
+
 	// Initialize the stats fetcher that autopilot will use.
 	s.statsFetcher = NewStatsFetcher(logger, s.connPool, s.config.Datacenter)
 
@@ -627,6 +654,11 @@ func NewServer(config *Config, flat Deps) (*Server, error) {
 	// Start the metrics handlers.
 	go s.updateMetrics()
 
+	// Start committing the usage of ACL tokens.
+	if s.config.ACLsEnabled {
+		go s.updateACLTokenUsage()
+	}
+
 	return s, nil
 }
 
@@ -738,19 +770,14 @@ func (s *Server) setupRaft() error {
 		}
 
 		// Create the backend raft store for logs and stable storage.
-		store, err := raftboltdb.New(raftboltdb.Options{
-			BoltOptions: &bbolt.Options{
-				NoFreelistSync: s.config.RaftBoltDBConfig.NoFreelistSync,
-			},
-			Path: filepath.Join(path, "raft.db"),
-		})
+		store, err := openRaftLogStore(path, s.config)
 		if err != nil {
 			return err
 		}
 		s.raftStore = store
 		stable = store
 
-		// start publishing boltdb metrics
+		// start publishing the metrics of the log store
 		go store.RunMetrics(&lib.StopChannelContext{StopCh: s.shutdownCh}, 0)
 
 		// Wrap the store in a LogCache to improve performance.
@@ -1013,7 +1040,7 @@ func (s *Server) attemptLeadershipTransfer() (success bool) {
 		return false
 	}
 
-	future := s.raft.LeadershipTransfer()
+	future := s.transferLeadership("", "")
 	if err := future.Error(); err != nil {
 		s.logger.Error("failed to transfer leadership, removing the server", "error", err)
 		return false
@@ -148,6 +148,7 @@ func (s *Server) updateMetrics() {
 		select {
 		case <-time.After(time.Second):
 			metrics.SetGauge([]string{"session_ttl", "active"}, float32(s.sessionTimers.Len()))
+			metrics.SetGauge([]string{"node_ttl", "active"}, float32(s.nodeTimers.Len()))
 
 			metrics.SetGauge([]string{"raft", "applied_index"}, float32(s.raft.AppliedIndex()))
 			metrics.SetGauge([]string{"raft", "last_index"}, float32(s.raft.LastIndex()))
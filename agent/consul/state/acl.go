@@ -411,6 +411,97 @@ func fixupRolePolicyLinks(tx ReadTxn, original *structs.ACLRole) (*structs.ACLRo
 	return role, nil
 }
 
+func resolvePolicyTemplateLinks(tx ReadTxn, links []*structs.ACLPolicyTemplateLink, entMeta *structs.EnterpriseMeta, allowMissing bool) (int, error) {
+	var numValid int
+	for _, link := range links {
+		if link.ID == "" {
+			return 0, fmt.Errorf("Encountered a policy template linked by Name in the state store")
+		}
+
+		template, err := getPolicyWithTxn(tx, nil, link.ID, aclPolicyGetByID, entMeta)
+		if err != nil {
+			return 0, err
+		}
+
+		if template != nil {
+			// the name doesn't matter here
+			link.Template = template.Name
+			numValid++
+		} else if !allowMissing {
+			return 0, fmt.Errorf("No such policy template with ID: %s", link.ID)
+		}
+	}
+	return numValid, nil
+}
+
+// fixupPolicyTemplateLinks is to be used when retrieving tokens and roles from memdb. The policy template
+// links could have gotten stale when a linked template was deleted or renamed. This will correct them and
+// return newly allocated links only when fixes are needed, as indicated by the second return value.
+func fixupPolicyTemplateLinks(tx ReadTxn, original []*structs.ACLPolicyTemplateLink, entMeta *structs.EnterpriseMeta) ([]*structs.ACLPolicyTemplateLink, bool, error) {
+	owned := false
+	links := original
+
+	cloneLinks := func(l []*structs.ACLPolicyTemplateLink, copyNumLinks int) []*structs.ACLPolicyTemplateLink {
+		clone := make([]*structs.ACLPolicyTemplateLink, copyNumLinks)
+		copy(clone, l[:copyNumLinks])
+		return clone
+	}
+
+	for linkIndex, link := range original {
+		if link.ID == "" {
+			return nil, false, fmt.Errorf("Detected corrupted policy template link within the state store - missing ID")
+		}
+
+		template, err := getPolicyWithTxn(tx, nil, link.ID, aclPolicyGetByID, entMeta)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if template == nil {
+			if !owned {
+				// clone the links as we cannot touch the original
+				links = cloneLinks(original, linkIndex)
+				owned = true
+			}
+			// if already owned then we just don't append it.
+		} else if template.Name != link.Template {
+			if !owned {
+				links = cloneLinks(original, linkIndex)
+				owned = true
+			}
+
+			// append the corrected link
+			corrected := link.Clone()
+			corrected.Template = template.Name
+			links = append(links, corrected)
+		} else if owned {
+			links = append(links, link)
+		}
+	}
+
+	return links, owned, nil
+}
+
+func fixupTokenPolicyTemplateLinks(tx ReadTxn, original *structs.ACLToken) (*structs.ACLToken, error) {
+	links, owned, err := fixupPolicyTemplateLinks(tx, original.PolicyTemplates, &original.EnterpriseMeta)
+	if err != nil || !owned {
+		return original, err
+	}
+	token := *original
+	token.PolicyTemplates = links
+	return &token, nil
+}
+
+func fixupRolePolicyTemplateLinks(tx ReadTxn, original *structs.ACLRole) (*structs.ACLRole, error) {
+	links, owned, err := fixupPolicyTemplateLinks(tx, original.PolicyTemplates, &original.EnterpriseMeta)
+	if err != nil || !owned {
+		return original, err
+	}
+	role := *original
+	role.PolicyTemplates = links
+	return &role, nil
+}
+
 // ACLTokenSet is used in many tests to set a single ACL token. It is now a shim
 // for calling ACLTokenBatchSet with default options.
 func (s *Store) ACLTokenSet(idx uint64, token *structs.ACLToken) error {
@@ -515,6 +606,11 @@ func aclTokenSetTxn(tx WriteTxn, idx uint64, token *structs.ACLToken, opts ACLTo
 		return err
 	}
 
+	var numValidTemplates int
+	if numValidTemplates, err = resolvePolicyTemplateLinks(tx, token.PolicyTemplates, &token.EnterpriseMeta, opts.AllowMissingPolicyAndRoleIDs); err != nil {
+		return err
+	}
+
 	if token.AuthMethod != "" && !opts.FromReplication {
 		methodMeta := token.ACLAuthMethodEnterpriseMeta.ToEnterpriseMeta()
 		methodMeta.Merge(&token.EnterpriseMeta)
@@ -542,7 +638,7 @@ func aclTokenSetTxn(tx WriteTxn, idx uint64, token *structs.ACLToken, opts ACLTo
 	}
 
 	if opts.ProhibitUnprivileged {
-		if numValidRoles == 0 && numValidPolicies == 0 && len(token.ServiceIdentities) == 0 && len(token.NodeIdentities) == 0 {
+		if numValidRoles == 0 && numValidPolicies == 0 && numValidTemplates == 0 && len(token.ServiceIdentities) == 0 && len(token.NodeIdentities) == 0 {
 			return ErrTokenHasNoPrivileges
 		}
 	}
@@ -559,9 +655,11 @@ func aclTokenSetTxn(tx WriteTxn, idx uint64, token *structs.ACLToken, opts ACLTo
 
 		token.CreateIndex = original.CreateIndex
 		token.ModifyIndex = idx
+		token.LastUsedTime = original.LastUsedTime
 	} else {
 		token.CreateIndex = idx
 		token.ModifyIndex = idx
+		token.LastUsedTime = nil
 	}
 
 	// ensure that a hash is set
@@ -633,6 +731,10 @@ func aclTokenGetTxn(tx ReadTxn, ws memdb.WatchSet, value, index string, entMeta
 		if err != nil {
 			return nil, err
 		}
+		token, err = fixupTokenPolicyTemplateLinks(tx, token)
+		if err != nil {
+			return nil, err
+		}
 		return token, nil
 	}
 
@@ -710,6 +812,10 @@ func (s *Store) ACLTokenList(ws memdb.WatchSet, local, global bool, policy, role
 		if err != nil {
 			return 0, nil, err
 		}
+		token, err = fixupTokenPolicyTemplateLinks(tx, token)
+		if err != nil {
+			return 0, nil, err
+		}
 		result = append(result, token)
 	}
 
@@ -797,6 +903,44 @@ func (s *Store) expiresIndexName(local bool) string {
 	return indexExpiresGlobal
 }
 
+// ACLTokenUsageSet records when the tokens, identified by their AccessorID,
+// were last used. Unknown tokens are ignored and the recorded times only move
+// forward. The ModifyIndex of the tokens is left untouched as their content
+// does not change.
+//
+// The index of the tokens table is not updated either: usage is committed
+// periodically and bumping it would wake up the blocking queries watching the
+// tokens, including ACL replication, every time.
+func (s *Store) ACLTokenUsageSet(idx uint64, usage map[string]time.Time) error {
+	tx := s.db.WriteTxn(idx)
+	defer tx.Abort()
+
+	for accessor, lastUsed := range usage {
+		_, existing, err := aclTokenGetFromIndex(tx, accessor, indexAccessor, nil)
+		if err != nil {
+			return fmt.Errorf("failed acl token lookup: %v", err)
+		}
+		if existing == nil {
+			continue
+		}
+
+		original := existing.(*structs.ACLToken)
+		if original.LastUsedTime != nil && !lastUsed.After(*original.LastUsedTime) {
+			continue
+		}
+
+		token := original.Clone()
+		lastUsed := lastUsed
+		token.LastUsedTime = &lastUsed
+
+		if err := tx.Insert(tableACLTokens, token); err != nil {
+			return fmt.Errorf("failed inserting acl token: %v", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
 // ACLTokenDeleteByAccessor is used to remove an existing ACL from the state store. If
 // the ACL does not exist this is a no-op and no error is returned.
 func (s *Store) ACLTokenDeleteByAccessor(idx uint64, accessor string, entMeta *structs.EnterpriseMeta) error {
@@ -1166,6 +1310,10 @@ func aclRoleSetTxn(tx WriteTxn, idx uint64, role *structs.ACLRole, allowMissing
 		return err
 	}
 
+	if _, err := resolvePolicyTemplateLinks(tx, role.PolicyTemplates, &role.EnterpriseMeta, allowMissing); err != nil {
+		return err
+	}
+
 	for _, svcid := range role.ServiceIdentities {
 		if svcid.ServiceName == "" {
 			return fmt.Errorf("Encountered a Role with an empty service identity name in the state store")
@@ -1250,6 +1398,10 @@ func getRoleWithTxn(tx ReadTxn, ws memdb.WatchSet, value string, fn aclRoleGetFn
 		if err != nil {
 			return nil, err
 		}
+		role, err = fixupRolePolicyTemplateLinks(tx, role)
+		if err != nil {
+			return nil, err
+		}
 		return role, nil
 	}
 
@@ -1301,6 +1453,10 @@ func (s *Store) ACLRoleList(ws memdb.WatchSet, policy string, entMeta *structs.E
 		if err != nil {
 			return 0, nil, err
 		}
+		role, err = fixupRolePolicyTemplateLinks(tx, role)
+		if err != nil {
+			return 0, nil, err
+		}
 		result = append(result, role)
 	}
 
@@ -1488,6 +1488,76 @@ func TestStateStore_ACLToken_Delete(t *testing.T) {
 	})
 }
 
+func TestStateStore_ACLTokenUsageSet(t *testing.T) {
+	t.Parallel()
+	s := testACLTokensStateStore(t)
+
+	const accessorID = "f1093997-b6c7-496d-bfb8-6b1b1895641b"
+	token := &structs.ACLToken{
+		AccessorID:  accessorID,
+		SecretID:    "34ec8eb3-095d-417a-a937-b439af7a8e8b",
+		Description: "usage",
+		Policies: []structs.ACLTokenPolicyLink{
+			{
+				ID: structs.ACLPolicyGlobalManagementID,
+			},
+		},
+	}
+	require.NoError(t, s.ACLTokenSet(10, token.Clone()))
+
+	lastUsed := time.Date(2020, 5, 22, 18, 52, 31, 0, time.UTC)
+	require.NoError(t, s.ACLTokenUsageSet(11, map[string]time.Time{
+		accessorID:                             lastUsed,
+		"a0bfe8d4-b2f3-4b48-b387-f28afb820eab": lastUsed,
+	}))
+
+	// The index of the tokens table is not bumped by usage.
+	idx, rtoken, err := s.ACLTokenGetByAccessor(nil, accessorID, nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), idx)
+	require.Equal(t, lastUsed, *rtoken.LastUsedTime)
+	require.Equal(t, uint64(10), rtoken.ModifyIndex)
+
+	t.Run("does not move backwards", func(t *testing.T) {
+		require.NoError(t, s.ACLTokenUsageSet(12, map[string]time.Time{
+			accessorID: lastUsed.Add(-time.Hour),
+		}))
+
+		_, rtoken, err := s.ACLTokenGetByAccessor(nil, accessorID, nil)
+		require.NoError(t, err)
+		require.Equal(t, lastUsed, *rtoken.LastUsedTime)
+	})
+
+	t.Run("preserved on update", func(t *testing.T) {
+		updated := token.Clone()
+		updated.Description = "updated"
+		require.NoError(t, s.ACLTokenSet(13, updated))
+
+		_, rtoken, err := s.ACLTokenGetByAccessor(nil, accessorID, nil)
+		require.NoError(t, err)
+		require.Equal(t, "updated", rtoken.Description)
+		require.Equal(t, lastUsed, *rtoken.LastUsedTime)
+	})
+
+	t.Run("ignored on creation", func(t *testing.T) {
+		created := &structs.ACLToken{
+			AccessorID:   "a0bfe8d4-b2f3-4b48-b387-f28afb820eab",
+			SecretID:     "be444e46-fb95-4ccc-80d5-c873f34e6fa6",
+			LastUsedTime: &lastUsed,
+			Policies: []structs.ACLTokenPolicyLink{
+				{
+					ID: structs.ACLPolicyGlobalManagementID,
+				},
+			},
+		}
+		require.NoError(t, s.ACLTokenSet(14, created))
+
+		_, rtoken, err := s.ACLTokenGetByAccessor(nil, created.AccessorID, nil)
+		require.NoError(t, err)
+		require.Nil(t, rtoken.LastUsedTime)
+	})
+}
+
 func TestStateStore_ACLPolicy_SetGet(t *testing.T) {
 	t.Parallel()
 
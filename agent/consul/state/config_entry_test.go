@@ -735,6 +735,126 @@ func TestStore_ConfigEntry_GraphValidation(t *testing.T) {
 			expectErr:      "does not permit advanced routing or splitting behavior",
 			expectGraphErr: true,
 		},
+		"router with grpc match fails with http protocol": {
+			entries: []structs.ConfigEntry{
+				&structs.ServiceConfigEntry{
+					Kind:     structs.ServiceDefaults,
+					Name:     "main",
+					Protocol: "http",
+				},
+				&structs.ServiceResolverConfigEntry{
+					Kind: structs.ServiceResolver,
+					Name: "main",
+					Subsets: map[string]structs.ServiceResolverSubset{
+						"other": {
+							Filter: "Service.Meta.version == other",
+						},
+					},
+				},
+			},
+			op: func(t *testing.T, s *Store) error {
+				entry := &structs.ServiceRouterConfigEntry{
+					Kind: structs.ServiceRouter,
+					Name: "main",
+					Routes: []structs.ServiceRoute{
+						{
+							Match: &structs.ServiceRouteMatch{
+								GRPC: &structs.ServiceRouteGRPCMatch{
+									Service: "pkg.Main",
+								},
+							},
+							Destination: &structs.ServiceRouteDestination{
+								ServiceSubset: "other",
+							},
+						},
+					},
+				}
+				return s.EnsureConfigEntry(0, entry)
+			},
+			expectErr:      "does not permit gRPC route matches",
+			expectGraphErr: true,
+		},
+		"router with grpc match works with grpc protocol": {
+			entries: []structs.ConfigEntry{
+				&structs.ServiceConfigEntry{
+					Kind:     structs.ServiceDefaults,
+					Name:     "main",
+					Protocol: "grpc",
+				},
+				&structs.ServiceResolverConfigEntry{
+					Kind: structs.ServiceResolver,
+					Name: "main",
+					Subsets: map[string]structs.ServiceResolverSubset{
+						"other": {
+							Filter: "Service.Meta.version == other",
+						},
+					},
+				},
+			},
+			op: func(t *testing.T, s *Store) error {
+				entry := &structs.ServiceRouterConfigEntry{
+					Kind: structs.ServiceRouter,
+					Name: "main",
+					Routes: []structs.ServiceRoute{
+						{
+							Match: &structs.ServiceRouteMatch{
+								GRPC: &structs.ServiceRouteGRPCMatch{
+									Service: "pkg.Main",
+								},
+							},
+							Destination: &structs.ServiceRouteDestination{
+								ServiceSubset: "other",
+							},
+						},
+					},
+				}
+				return s.EnsureConfigEntry(0, entry)
+			},
+		},
+		"cannot change to http protocol after router with grpc match created": {
+			entries: []structs.ConfigEntry{
+				&structs.ServiceConfigEntry{
+					Kind:     structs.ServiceDefaults,
+					Name:     "main",
+					Protocol: "grpc",
+				},
+				&structs.ServiceResolverConfigEntry{
+					Kind: structs.ServiceResolver,
+					Name: "main",
+					Subsets: map[string]structs.ServiceResolverSubset{
+						"other": {
+							Filter: "Service.Meta.version == other",
+						},
+					},
+				},
+				&structs.ServiceRouterConfigEntry{
+					Kind: structs.ServiceRouter,
+					Name: "main",
+					Routes: []structs.ServiceRoute{
+						{
+							Match: &structs.ServiceRouteMatch{
+								GRPC: &structs.ServiceRouteGRPCMatch{
+									Service: "pkg.Main",
+								},
+							},
+							Destination: &structs.ServiceRouteDestination{
+								ServiceSubset: "other",
+							},
+						},
+					},
+				},
+			},
+			op: func(t *testing.T, s *Store) error {
+				entry := &structs.ServiceConfigEntry{
+					Kind:     structs.ServiceDefaults,
+					Name:     "main",
+					Protocol: "http",
+				}
+				return s.EnsureConfigEntry(0, entry)
+			},
+			expectErr:      "does not permit gRPC route matches",
+			expectGraphErr: true,
+		},
 		/////////////////////////////////////////////////
 		"cannot split to a service using tcp": {
 			entries: []structs.ConfigEntry{
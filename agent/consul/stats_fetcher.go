@@ -25,6 +25,11 @@ type StatsFetcher struct {
 	datacenter   string
 	inflight     map[raft.ServerID]struct{}
 	inflightLock sync.Mutex
+
+	// lastStats are the last stats fetched from each server, they hold more
+	// than the stats given to autopilot.
+	lastStats     map[raft.ServerID]*structs.RaftStats
+	lastStatsLock sync.Mutex
 }
 
 // NewStatsFetcher returns a stats fetcher.
@@ -34,6 +39,8 @@ func NewStatsFetcher(logger hclog.Logger, pool *pool.ConnPool, datacenter string
 		pool:       pool,
 		datacenter: datacenter,
 		inflight:   make(map[raft.ServerID]struct{}),
+
+		lastStats: make(map[raft.ServerID]*structs.RaftStats),
 	}
 }
 
@@ -70,9 +77,21 @@ func (f *StatsFetcher) fetch(server *autopilot.Server, replyCh chan *autopilot.S
 		return
 	}
 
+	f.lastStatsLock.Lock()
+	f.lastStats[server.ID] = &reply
+	f.lastStatsLock.Unlock()
+
 	replyCh <- reply.ToAutopilotServerStats()
 }
 
+// LastStats returns the last stats fetched from a server.
+func (f *StatsFetcher) LastStats(id raft.ServerID) (*structs.RaftStats, bool) {
+	f.lastStatsLock.Lock()
+	defer f.lastStatsLock.Unlock()
+	stats, ok := f.lastStats[id]
+	return stats, ok
+}
+
 // Fetch will attempt to query all the servers in parallel.
 func (f *StatsFetcher) Fetch(ctx context.Context, servers map[raft.ServerID]*autopilot.Server) map[raft.ServerID]*autopilot.ServerStats {
 	type workItem struct {
@@ -80,6 +99,15 @@ func (f *StatsFetcher) Fetch(ctx context.Context, servers map[raft.ServerID]*aut
 		replyCh chan *autopilot.ServerStats
 	}
 
+	// Forget the servers that are gone.
+	f.lastStatsLock.Lock()
+	for id := range f.lastStats {
+		if _, ok := servers[id]; !ok {
+			delete(f.lastStats, id)
+		}
+	}
+	f.lastStatsLock.Unlock()
+
 	// Skip any servers that have inflight requests.
 	var work []*workItem
 	f.inflightLock.Lock()
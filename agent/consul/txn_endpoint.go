@@ -66,6 +66,15 @@ func (t *Txn) preCheck(authorizer acl.Authorizer, ops structs.TxnOps) structs.Tx
 				})
 				break
 			}
+			if op.Node.Verb == api.NodeSet || op.Node.Verb == api.NodeCAS {
+				if err := validateNodeTTL(node.Meta); err != nil {
+					errors = append(errors, &structs.TxnError{
+						OpIndex: i,
+						What:    err.Error(),
+					})
+					break
+				}
+			}
 
 			// Check that the token has permissions for the given operation.
 			if err := vetNodeTxnOp(op.Node, authorizer); err != nil {
@@ -164,6 +173,11 @@ func (t *Txn) Apply(args *structs.TxnRequest, reply *structs.TxnResponse) error
 	// Convert the return type. This should be a cheap copy since we are
 	// just taking the two slices.
 	if txnResp, ok := resp.(structs.TxnResponse); ok {
+		if len(txnResp.Errors) == 0 {
+			if err := t.updateNodeTimers(args.Ops); err != nil {
+				return err
+			}
+		}
 		txnResp.Results = FilterTxnResults(authz, txnResp.Results)
 		*reply = txnResp
 	} else {
@@ -172,6 +186,33 @@ func (t *Txn) Apply(args *structs.TxnRequest, reply *structs.TxnResponse) error
 	return nil
 }
 
+// updateNodeTimers renews the TTL of the nodes written by a transaction, and
+// clears the timers of the nodes it deleted, as catalog registrations do.
+func (t *Txn) updateNodeTimers(ops structs.TxnOps) error {
+	state := t.srv.fsm.State()
+	for _, op := range ops {
+		if op.Node == nil {
+			continue
+		}
+		name, entMeta := op.Node.Node.Node, op.Node.Node.GetEnterpriseMeta()
+		switch op.Node.Verb {
+		case api.NodeSet, api.NodeCAS:
+			_, node, err := state.GetNode(name, entMeta)
+			if err != nil {
+				return fmt.Errorf("Node lookup failed: %v", err)
+			}
+			if node != nil {
+				if err := t.srv.resetNodeTimer(node); err != nil {
+					return err
+				}
+			}
+		case api.NodeDelete, api.NodeDeleteCAS:
+			t.srv.clearNodeTimer(name, entMeta)
+		}
+	}
+	return nil
+}
+
 // Read is used to perform a read-only transaction that doesn't modify the state
 // store. This is much more scalable since it doesn't go through Raft and
 // supports staleness, so this should be preferred if you're just performing
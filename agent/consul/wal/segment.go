@@ -0,0 +1,335 @@
+package wal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/raft"
+)
+
+const (
+	// segmentMagic starts every segment file so that unrelated or truncated
+	// files are not mistaken for segments.
+	segmentMagic = "CSLWAL01"
+
+	segmentExt = ".wal"
+
+	// recordHeaderSize is the size of the header preceding the payload of each
+	// record: the length of the payload followed by its CRC.
+	recordHeaderSize = 8
+
+	// maxRecordSize bounds the length read from a record header, a larger
+	// length can only come from a corrupted header.
+	maxRecordSize = 1 << 30
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// errTornRecord is returned when a record is only partially written or its CRC
+// doesn't match, which is expected at the tail of the last segment after a
+// crash.
+var errTornRecord = errors.New("torn record")
+
+// segment is a file holding the records of consecutive logs, starting with the
+// log at index base.
+type segment struct {
+	base uint64
+	path string
+	f    *os.File
+
+	// offsets are the offsets of the records within the file, the record at
+	// offsets[i] holds the log at index base+i.
+	offsets []int64
+
+	// size is the offset at which the next record is written.
+	size int64
+}
+
+func segmentName(base uint64) string {
+	return fmt.Sprintf("%020d%s", base, segmentExt)
+}
+
+func parseSegmentName(name string) (uint64, bool) {
+	if !strings.HasSuffix(name, segmentExt) {
+		return 0, false
+	}
+	base, err := strconv.ParseUint(strings.TrimSuffix(name, segmentExt), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return base, true
+}
+
+// createSegment creates the segment file for the logs starting at index base.
+func createSegment(dir string, base uint64) (*segment, error) {
+	path := filepath.Join(dir, segmentName(base))
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.Write([]byte(segmentMagic)); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := syncDir(dir); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &segment{base: base, path: path, f: f, size: int64(len(segmentMagic))}, nil
+}
+
+// openSegment opens an existing segment file and indexes its records. When
+// repair is set a torn record at the end of the file is truncated away,
+// otherwise it is reported as an error.
+func openSegment(path string, base uint64, repair bool) (*segment, error) {
+	f, err := os.OpenFile(path, os.O_RDWR, 0600)
+	if err != nil {
+		return nil, err
+	}
+	seg := &segment{base: base, path: path, f: f}
+	if err := seg.scan(repair); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to read segment %s: %v", path, err)
+	}
+	return seg, nil
+}
+
+func (s *segment) scan(repair bool) error {
+	r := bufio.NewReader(io.NewSectionReader(s.f, 0, 1<<62))
+
+	magic := make([]byte, len(segmentMagic))
+	if _, err := io.ReadFull(r, magic); err != nil || string(magic) != segmentMagic {
+		return fmt.Errorf("not a segment file")
+	}
+	offset := int64(len(segmentMagic))
+
+	for {
+		payload, err := readRecord(r)
+		if err == io.EOF {
+			break
+		}
+		if err == errTornRecord && repair {
+			if err := s.f.Truncate(offset); err != nil {
+				return err
+			}
+			if err := s.f.Sync(); err != nil {
+				return err
+			}
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("record at offset %d: %v", offset, err)
+		}
+
+		if len(payload) < 8 {
+			return fmt.Errorf("record at offset %d is too short", offset)
+		}
+		expected := s.base + uint64(len(s.offsets))
+		if index := binary.BigEndian.Uint64(payload); index != expected {
+			return fmt.Errorf("record at offset %d holds index %d, expected %d", offset, index, expected)
+		}
+		s.offsets = append(s.offsets, offset)
+		offset += recordHeaderSize + int64(len(payload))
+	}
+	s.size = offset
+	return nil
+}
+
+// last returns the index of the last log of the segment, or base-1 when it is
+// empty.
+func (s *segment) last() uint64 {
+	return s.base + uint64(len(s.offsets)) - 1
+}
+
+func (s *segment) empty() bool {
+	return len(s.offsets) == 0
+}
+
+func (s *segment) getLog(index uint64, log *raft.Log) error {
+	offset := s.offsets[index-s.base]
+	end := s.size
+	if i := index - s.base + 1; i < uint64(len(s.offsets)) {
+		end = s.offsets[i]
+	}
+
+	buf := make([]byte, end-offset)
+	if _, err := s.f.ReadAt(buf, offset); err != nil {
+		return err
+	}
+	payload, err := readRecord(bytes.NewReader(buf))
+	if err != nil {
+		return fmt.Errorf("failed to read log %d from segment %s: %v", index, s.path, err)
+	}
+	return decodeLog(payload, log)
+}
+
+// write appends the encoded records to the segment and syncs it to disk.
+// offsets are relative to the start of records.
+func (s *segment) write(records []byte, offsets []int64) error {
+	if _, err := s.f.WriteAt(records, s.size); err != nil {
+		return err
+	}
+	if err := s.f.Sync(); err != nil {
+		return err
+	}
+	for _, offset := range offsets {
+		s.offsets = append(s.offsets, s.size+offset)
+	}
+	s.size += int64(len(records))
+	return nil
+}
+
+// truncate removes the logs starting at index from the segment.
+func (s *segment) truncate(index uint64) error {
+	offset := s.offsets[index-s.base]
+	if err := s.f.Truncate(offset); err != nil {
+		return err
+	}
+	if err := s.f.Sync(); err != nil {
+		return err
+	}
+	s.offsets = s.offsets[:index-s.base]
+	s.size = offset
+	return nil
+}
+
+func (s *segment) close() error {
+	return s.f.Close()
+}
+
+func (s *segment) remove() error {
+	s.f.Close()
+	return os.Remove(s.path)
+}
+
+func readRecord(r io.Reader) ([]byte, error) {
+	var header [recordHeaderSize]byte
+	if n, err := io.ReadFull(r, header[:]); err != nil {
+		if err == io.EOF && n == 0 {
+			return nil, io.EOF
+		}
+		return nil, errTornRecord
+	}
+	length := binary.BigEndian.Uint32(header[:4])
+	if length > maxRecordSize {
+		return nil, errTornRecord
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, errTornRecord
+	}
+	if crc32.Checksum(payload, crcTable) != binary.BigEndian.Uint32(header[4:]) {
+		return nil, errTornRecord
+	}
+	return payload, nil
+}
+
+// appendRecord appends the record of the log to buf.
+func appendRecord(buf []byte, log *raft.Log) []byte {
+	payload := encodeLog(log)
+
+	var header [recordHeaderSize]byte
+	binary.BigEndian.PutUint32(header[:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(header[4:], crc32.Checksum(payload, crcTable))
+	buf = append(buf, header[:]...)
+	return append(buf, payload...)
+}
+
+// encodeLog encodes a log as its index, term, type and time of append
+// followed by its length prefixed data and extensions. The index comes first
+// so that records can be checked without decoding them.
+func encodeLog(log *raft.Log) []byte {
+	buf := make([]byte, 0, 8+8+1+8+4+len(log.Data)+4+len(log.Extensions))
+	buf = appendUint64(buf, log.Index)
+	buf = appendUint64(buf, log.Term)
+	buf = append(buf, byte(log.Type))
+
+	var appendedAt int64
+	if !log.AppendedAt.IsZero() {
+		appendedAt = log.AppendedAt.UnixNano()
+	}
+	buf = appendUint64(buf, uint64(appendedAt))
+
+	buf = appendUint32(buf, uint32(len(log.Data)))
+	buf = append(buf, log.Data...)
+	buf = appendUint32(buf, uint32(len(log.Extensions)))
+	buf = append(buf, log.Extensions...)
+	return buf
+}
+
+func decodeLog(buf []byte, log *raft.Log) error {
+	if len(buf) < 8+8+1+8+4 {
+		return fmt.Errorf("log record is too short")
+	}
+	log.Index = binary.BigEndian.Uint64(buf)
+	log.Term = binary.BigEndian.Uint64(buf[8:])
+	log.Type = raft.LogType(buf[16])
+	log.AppendedAt = time.Time{}
+	if appendedAt := int64(binary.BigEndian.Uint64(buf[17:])); appendedAt != 0 {
+		log.AppendedAt = time.Unix(0, appendedAt)
+	}
+	buf = buf[25:]
+
+	var err error
+	if log.Data, buf, err = readBytes(buf); err != nil {
+		return err
+	}
+	if log.Extensions, _, err = readBytes(buf); err != nil {
+		return err
+	}
+	return nil
+}
+
+func readBytes(buf []byte) ([]byte, []byte, error) {
+	if len(buf) < 4 {
+		return nil, nil, fmt.Errorf("log record is too short")
+	}
+	n := binary.BigEndian.Uint32(buf)
+	buf = buf[4:]
+	if uint64(len(buf)) < uint64(n) {
+		return nil, nil, fmt.Errorf("log record is too short")
+	}
+	if n == 0 {
+		return nil, buf, nil
+	}
+	out := make([]byte, n)
+	copy(out, buf[:n])
+	return out, buf[n:], nil
+}
+
+func appendUint64(buf []byte, v uint64) []byte {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], v)
+	return append(buf, b[:]...)
+}
+
+func appendUint32(buf []byte, v uint32) []byte {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], v)
+	return append(buf, b[:]...)
+}
+
+// syncDir syncs a directory so that the creation, removal or renaming of the
+// files it holds is durable.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
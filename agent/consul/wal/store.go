@@ -0,0 +1,583 @@
+// Package wal implements a Raft log store persisting the logs in append-only
+// segment files, as an alternative to BoltDB that avoids the freelist growth
+// and the page rewrites of a B+tree on write-heavy clusters.
+package wal
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/armon/go-metrics/prometheus"
+	"github.com/hashicorp/raft"
+)
+
+var Gauges = []prometheus.GaugeDefinition{
+	{
+		Name: []string{"raft", "wal", "segments"},
+		Help: "Represents the number of segment files of the write-ahead log.",
+	},
+	{
+		Name: []string{"raft", "wal", "segmentBytes"},
+		Help: "Represents the total size in bytes of the segment files of the write-ahead log.",
+	},
+	{
+		Name: []string{"raft", "wal", "logs"},
+		Help: "Represents the number of logs stored in the write-ahead log.",
+	},
+}
+
+var Summaries = []prometheus.SummaryDefinition{
+	{
+		Name: []string{"raft", "wal", "getLog"},
+		Help: "Measures the amount of time spent reading logs from the write-ahead log.",
+	},
+	{
+		Name: []string{"raft", "wal", "storeLogs"},
+		Help: "Measures the amount of time spent writing logs to the write-ahead log.",
+	},
+	{
+		Name: []string{"raft", "wal", "logSize"},
+		Help: "Measures the size of logs being written to the write-ahead log.",
+	},
+	{
+		Name: []string{"raft", "wal", "logsPerBatch"},
+		Help: "Measures the number of logs being written per batch to the write-ahead log.",
+	},
+	{
+		Name: []string{"raft", "wal", "logBatchSize"},
+		Help: "Measures the total size in bytes of logs being written to the write-ahead log in a single batch.",
+	},
+}
+
+const (
+	// DefaultSegmentSize is the size above which a new segment file is
+	// started.
+	DefaultSegmentSize = 64 * 1024 * 1024
+
+	metaFile = "meta.json"
+)
+
+var (
+	// ErrKeyNotFound is returned by the stable store for a missing key. Raft
+	// matches it by its message.
+	ErrKeyNotFound = errors.New("not found")
+
+	errClosed = errors.New("wal store is closed")
+)
+
+// Options configures a Store.
+type Options struct {
+	// Path is the directory holding the segment files.
+	Path string
+
+	// SegmentSize is the size above which a new segment file is started, it
+	// defaults to DefaultSegmentSize.
+	SegmentSize int
+}
+
+// meta holds the state that isn't part of the segments, it is rewritten
+// atomically on each change.
+type meta struct {
+	// FirstIndex is the first index of the log once the logs before it were
+	// deleted but their segment still holds later logs.
+	FirstIndex uint64
+
+	// Stable holds the keys of the stable store.
+	Stable map[string][]byte
+}
+
+// Store is a raft.LogStore and raft.StableStore backed by a write-ahead log
+// of segment files. Logs are appended to the last segment and synced once per
+// batch, logs at the head are deleted a whole segment at a time.
+type Store struct {
+	dir         string
+	segmentSize int64
+
+	l        sync.RWMutex
+	segments []*segment
+	first    uint64
+	last     uint64
+	meta     meta
+	closed   bool
+}
+
+var (
+	_ raft.LogStore    = (*Store)(nil)
+	_ raft.StableStore = (*Store)(nil)
+)
+
+// New opens the store in the given directory, creating it if needed. A torn
+// write at the end of the last segment, left by a crash, is truncated away.
+func New(opts Options) (*Store, error) {
+	if opts.Path == "" {
+		return nil, fmt.Errorf("a path is required")
+	}
+	segmentSize := int64(opts.SegmentSize)
+	if segmentSize <= 0 {
+		segmentSize = DefaultSegmentSize
+	}
+	if err := os.MkdirAll(opts.Path, 0700); err != nil {
+		return nil, err
+	}
+
+	s := &Store{
+		dir:         opts.Path,
+		segmentSize: segmentSize,
+		meta:        meta{Stable: make(map[string][]byte)},
+	}
+	if err := s.readMeta(); err != nil {
+		return nil, err
+	}
+	if err := s.openSegments(); err != nil {
+		s.closeSegments()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Store) readMeta() error {
+	raw, err := ioutil.ReadFile(filepath.Join(s.dir, metaFile))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(raw, &s.meta); err != nil {
+		return fmt.Errorf("failed to decode %s: %v", metaFile, err)
+	}
+	if s.meta.Stable == nil {
+		s.meta.Stable = make(map[string][]byte)
+	}
+	return nil
+}
+
+// writeMeta atomically replaces the meta file.
+func (s *Store) writeMeta() error {
+	raw, err := json.Marshal(s.meta)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(s.dir, metaFile)
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(raw); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+	return syncDir(s.dir)
+}
+
+func (s *Store) openSegments() error {
+	entries, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+	var bases []uint64
+	for _, entry := range entries {
+		if base, ok := parseSegmentName(entry.Name()); ok && !entry.IsDir() {
+			bases = append(bases, base)
+		}
+	}
+	sort.Slice(bases, func(i, j int) bool { return bases[i] < bases[j] })
+
+	for i, base := range bases {
+		// Only the last segment is appended to, so only it can hold a torn
+		// write.
+		last := i == len(bases)-1
+		seg, err := openSegment(filepath.Join(s.dir, segmentName(base)), base, last)
+		if err != nil {
+			return err
+		}
+		if n := len(s.segments); n > 0 && s.segments[n-1].last()+1 != base {
+			seg.close()
+			return fmt.Errorf("segment %s doesn't follow the log of the previous segment", seg.path)
+		}
+		s.segments = append(s.segments, seg)
+	}
+
+	// A trailing segment may be empty when the store stopped right after
+	// starting it, or after the logs it held were deleted.
+	if n := len(s.segments); n > 0 && s.segments[n-1].empty() {
+		if err := s.segments[n-1].remove(); err != nil {
+			return err
+		}
+		s.segments = s.segments[:n-1]
+	}
+
+	if len(s.segments) == 0 {
+		if s.meta.FirstIndex != 0 {
+			s.meta.FirstIndex = 0
+			return s.writeMeta()
+		}
+		return nil
+	}
+
+	s.first = s.segments[0].base
+	s.last = s.segments[len(s.segments)-1].last()
+	if s.meta.FirstIndex > s.first {
+		s.first = s.meta.FirstIndex
+	}
+	if s.first > s.last {
+		// Every log was deleted before the segments were removed.
+		return s.deleteAll()
+	}
+	return nil
+}
+
+// FirstIndex returns the first index written. 0 for no entries.
+func (s *Store) FirstIndex() (uint64, error) {
+	s.l.RLock()
+	defer s.l.RUnlock()
+	if s.closed {
+		return 0, errClosed
+	}
+	return s.first, nil
+}
+
+// LastIndex returns the last index written. 0 for no entries.
+func (s *Store) LastIndex() (uint64, error) {
+	s.l.RLock()
+	defer s.l.RUnlock()
+	if s.closed {
+		return 0, errClosed
+	}
+	return s.last, nil
+}
+
+// GetLog gets a log entry at a given index.
+func (s *Store) GetLog(index uint64, log *raft.Log) error {
+	defer metrics.MeasureSince([]string{"raft", "wal", "getLog"}, time.Now())
+
+	s.l.RLock()
+	defer s.l.RUnlock()
+	if s.closed {
+		return errClosed
+	}
+	if s.first == 0 || index < s.first || index > s.last {
+		return raft.ErrLogNotFound
+	}
+	return s.segmentFor(index).getLog(index, log)
+}
+
+// segmentFor returns the segment holding the log at index, which must be
+// within the log.
+func (s *Store) segmentFor(index uint64) *segment {
+	i := sort.Search(len(s.segments), func(i int) bool {
+		return s.segments[i].base > index
+	})
+	return s.segments[i-1]
+}
+
+// StoreLog stores a log entry.
+func (s *Store) StoreLog(log *raft.Log) error {
+	return s.StoreLogs([]*raft.Log{log})
+}
+
+// StoreLogs stores multiple log entries. They must follow the last log of
+// the store, or start past it in which case the previous logs are deleted.
+// The logs are synced to disk before returning.
+func (s *Store) StoreLogs(logs []*raft.Log) error {
+	defer metrics.MeasureSince([]string{"raft", "wal", "storeLogs"}, time.Now())
+	if len(logs) == 0 {
+		return nil
+	}
+
+	s.l.Lock()
+	defer s.l.Unlock()
+	if s.closed {
+		return errClosed
+	}
+
+	next := logs[0].Index
+	if s.last != 0 && next > s.last+1 {
+		// Raft may restore a snapshot past the last log without truncating
+		// the log when it keeps enough trailing logs, the logs then resume
+		// after the snapshot. The segments can't have gaps so the log is
+		// started over from the new index.
+		if err := s.deleteAll(); err != nil {
+			return err
+		}
+	}
+	if s.last != 0 {
+		next = s.last + 1
+	}
+
+	var (
+		records   []byte
+		offsets   []int64
+		batchSize int
+	)
+	seg := s.activeSegment()
+	for _, log := range logs {
+		if log.Index != next {
+			return fmt.Errorf("non-contiguous log index %d, expected %d", log.Index, next)
+		}
+
+		// Start a new segment once the active one is full, keeping at least
+		// one log per segment however large it is.
+		if seg != nil && seg.size+int64(len(records)) >= s.segmentSize && (!seg.empty() || len(records) > 0) {
+			if err := s.flush(seg, records, offsets); err != nil {
+				return err
+			}
+			records, offsets = nil, nil
+			seg = nil
+		}
+		if seg == nil {
+			var err error
+			if seg, err = createSegment(s.dir, log.Index); err != nil {
+				return err
+			}
+			s.segments = append(s.segments, seg)
+		}
+
+		offsets = append(offsets, int64(len(records)))
+		n := len(records)
+		records = appendRecord(records, log)
+		batchSize += len(records) - n
+		metrics.AddSample([]string{"raft", "wal", "logSize"}, float32(len(records)-n))
+		next++
+	}
+	if err := s.flush(seg, records, offsets); err != nil {
+		return err
+	}
+
+	metrics.AddSample([]string{"raft", "wal", "logsPerBatch"}, float32(len(logs)))
+	metrics.AddSample([]string{"raft", "wal", "logBatchSize"}, float32(batchSize))
+	return nil
+}
+
+// activeSegment returns the segment the logs are appended to, or nil if
+// there is none yet.
+func (s *Store) activeSegment() *segment {
+	if len(s.segments) == 0 {
+		return nil
+	}
+	return s.segments[len(s.segments)-1]
+}
+
+func (s *Store) flush(seg *segment, records []byte, offsets []int64) error {
+	if len(offsets) == 0 {
+		return nil
+	}
+	if err := seg.write(records, offsets); err != nil {
+		return err
+	}
+	if s.first == 0 {
+		s.first = seg.base
+	}
+	s.last = seg.last()
+	return nil
+}
+
+// DeleteRange deletes a range of log entries. The range is inclusive and must
+// include either the first or the last log, which is what Raft does when it
+// compacts the log after a snapshot or truncates conflicting logs.
+func (s *Store) DeleteRange(min, max uint64) error {
+	s.l.Lock()
+	defer s.l.Unlock()
+	if s.closed {
+		return errClosed
+	}
+	if s.first == 0 || max < s.first || min > s.last {
+		return nil
+	}
+
+	switch {
+	case min <= s.first && max >= s.last:
+		return s.deleteAll()
+	case min <= s.first:
+		return s.deleteHead(max)
+	case max >= s.last:
+		return s.deleteTail(min)
+	default:
+		return fmt.Errorf("cannot delete logs %d to %d within the log %d to %d", min, max, s.first, s.last)
+	}
+}
+
+func (s *Store) deleteAll() error {
+	for i := len(s.segments) - 1; i >= 0; i-- {
+		if err := s.segments[i].remove(); err != nil {
+			return err
+		}
+		s.segments = s.segments[:i]
+	}
+	s.first, s.last = 0, 0
+	if err := syncDir(s.dir); err != nil {
+		return err
+	}
+
+	// The first index is reset once no segment remains so that the next logs
+	// can't be hidden by it.
+	if s.meta.FirstIndex != 0 {
+		s.meta.FirstIndex = 0
+		return s.writeMeta()
+	}
+	return nil
+}
+
+// deleteHead deletes the logs up to max, which is before the last log.
+func (s *Store) deleteHead(max uint64) error {
+	// Record the new first index before removing any segment so that the
+	// deleted logs don't come back after a crash.
+	s.meta.FirstIndex = max + 1
+	if err := s.writeMeta(); err != nil {
+		return err
+	}
+	s.first = max + 1
+
+	for len(s.segments) > 0 && s.segments[0].last() < s.first {
+		if err := s.segments[0].remove(); err != nil {
+			return err
+		}
+		s.segments = s.segments[1:]
+	}
+	return syncDir(s.dir)
+}
+
+// deleteTail deletes the logs starting at min, which is after the first log.
+func (s *Store) deleteTail(min uint64) error {
+	for i := len(s.segments) - 1; i >= 0; i-- {
+		seg := s.segments[i]
+		if seg.base < min {
+			if err := seg.truncate(min); err != nil {
+				return err
+			}
+			break
+		}
+		if err := seg.remove(); err != nil {
+			return err
+		}
+		s.segments = s.segments[:i]
+	}
+	s.last = min - 1
+	return syncDir(s.dir)
+}
+
+// Set is used to set a key/value set outside of the raft log.
+func (s *Store) Set(k, v []byte) error {
+	s.l.Lock()
+	defer s.l.Unlock()
+	if s.closed {
+		return errClosed
+	}
+
+	s.meta.Stable[string(k)] = append([]byte(nil), v...)
+	return s.writeMeta()
+}
+
+// Get is used to retrieve a value from the k/v store by key.
+func (s *Store) Get(k []byte) ([]byte, error) {
+	s.l.RLock()
+	defer s.l.RUnlock()
+	if s.closed {
+		return nil, errClosed
+	}
+
+	v, ok := s.meta.Stable[string(k)]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	return append([]byte(nil), v...), nil
+}
+
+// SetUint64 is like Set, but handles uint64 values.
+func (s *Store) SetUint64(key []byte, val uint64) error {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], val)
+	return s.Set(key, buf[:])
+}
+
+// GetUint64 returns the uint64 value for key, or an error if key was not
+// found.
+func (s *Store) GetUint64(key []byte) (uint64, error) {
+	v, err := s.Get(key)
+	if err != nil {
+		return 0, err
+	}
+	if len(v) != 8 {
+		return 0, fmt.Errorf("value of key %q is not a uint64", key)
+	}
+	return binary.BigEndian.Uint64(v), nil
+}
+
+// Close closes the segment files.
+func (s *Store) Close() error {
+	s.l.Lock()
+	defer s.l.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	return s.closeSegments()
+}
+
+func (s *Store) closeSegments() error {
+	var firstErr error
+	for _, seg := range s.segments {
+		if err := seg.close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// RunMetrics periodically emits the gauges of the store until ctx is done.
+// An interval of 0 uses the default of one second.
+func (s *Store) RunMetrics(ctx context.Context, interval time.Duration) {
+	if interval == 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.emitMetrics()
+		}
+	}
+}
+
+func (s *Store) emitMetrics() {
+	s.l.RLock()
+	defer s.l.RUnlock()
+
+	var size int64
+	for _, seg := range s.segments {
+		size += seg.size
+	}
+	metrics.SetGauge([]string{"raft", "wal", "segments"}, float32(len(s.segments)))
+	metrics.SetGauge([]string{"raft", "wal", "segmentBytes"}, float32(size))
+	if s.first != 0 {
+		metrics.SetGauge([]string{"raft", "wal", "logs"}, float32(s.last-s.first+1))
+	} else {
+		metrics.SetGauge([]string{"raft", "wal", "logs"}, 0)
+	}
+}
@@ -0,0 +1,249 @@
+package wal
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/raft"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/sdk/testutil"
+)
+
+func testStore(t *testing.T, dir string, segmentSize int) *Store {
+	t.Helper()
+	s, err := New(Options{Path: dir, SegmentSize: segmentSize})
+	require.NoError(t, err)
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func testLogs(first, last uint64) []*raft.Log {
+	var logs []*raft.Log
+	for i := first; i <= last; i++ {
+		logs = append(logs, &raft.Log{
+			Index:      i,
+			Term:       1,
+			Type:       raft.LogCommand,
+			Data:       []byte(fmt.Sprintf("log-%d", i)),
+			AppendedAt: time.Unix(0, int64(i)),
+		})
+	}
+	return logs
+}
+
+func requireLogs(t *testing.T, s *Store, first, last uint64) {
+	t.Helper()
+	idx, err := s.FirstIndex()
+	require.NoError(t, err)
+	require.Equal(t, first, idx)
+	idx, err = s.LastIndex()
+	require.NoError(t, err)
+	require.Equal(t, last, idx)
+
+	if first == 0 {
+		return
+	}
+	for i := first; i <= last; i++ {
+		var log raft.Log
+		require.NoError(t, s.GetLog(i, &log))
+		require.Equal(t, *testLogs(i, i)[0], log)
+	}
+
+	var log raft.Log
+	require.Equal(t, raft.ErrLogNotFound, s.GetLog(first-1, &log))
+	require.Equal(t, raft.ErrLogNotFound, s.GetLog(last+1, &log))
+}
+
+func TestStore_StoreLogs(t *testing.T) {
+	dir := filepath.Join(testutil.TempDir(t, "wal"), "wal")
+	s := testStore(t, dir, 0)
+	requireLogs(t, s, 0, 0)
+
+	require.NoError(t, s.StoreLogs(testLogs(1, 10)))
+	require.NoError(t, s.StoreLog(testLogs(11, 11)[0]))
+	requireLogs(t, s, 1, 11)
+
+	// The logs can't overwrite previous logs nor have gaps.
+	require.Error(t, s.StoreLogs(testLogs(5, 6)))
+	require.Error(t, s.StoreLogs([]*raft.Log{testLogs(12, 12)[0], testLogs(14, 14)[0]}))
+
+	// Logs survive a restart.
+	require.NoError(t, s.Close())
+	s = testStore(t, dir, 0)
+	requireLogs(t, s, 1, 11)
+}
+
+func TestStore_StoreLogs_Gap(t *testing.T) {
+	// Raft stores logs past the last one after restoring a snapshot without
+	// compacting the log, the store then starts over from them.
+	t.Run("single segment", func(t *testing.T) {
+		dir := filepath.Join(testutil.TempDir(t, "wal"), "wal")
+		s := testStore(t, dir, 0)
+
+		require.NoError(t, s.StoreLogs(testLogs(1, 5)))
+		require.NoError(t, s.StoreLogs(testLogs(100, 100)))
+		requireLogs(t, s, 100, 100)
+
+		require.NoError(t, s.StoreLogs(testLogs(101, 105)))
+		requireLogs(t, s, 100, 105)
+
+		require.NoError(t, s.Close())
+		s = testStore(t, dir, 0)
+		requireLogs(t, s, 100, 105)
+	})
+
+	t.Run("compacted segments", func(t *testing.T) {
+		dir := filepath.Join(testutil.TempDir(t, "wal"), "wal")
+		s := testStore(t, dir, 256)
+
+		require.NoError(t, s.StoreLogs(testLogs(1, 50)))
+		require.NoError(t, s.DeleteRange(1, 20))
+		require.NoError(t, s.StoreLogs(testLogs(100, 110)))
+		requireLogs(t, s, 100, 110)
+
+		require.NoError(t, s.Close())
+		s = testStore(t, dir, 256)
+		requireLogs(t, s, 100, 110)
+	})
+}
+
+func TestStore_Segments(t *testing.T) {
+	dir := filepath.Join(testutil.TempDir(t, "wal"), "wal")
+	s := testStore(t, dir, 256)
+
+	for i := uint64(1); i <= 100; i += 10 {
+		require.NoError(t, s.StoreLogs(testLogs(i, i+9)))
+	}
+	requireLogs(t, s, 1, 100)
+	require.True(t, len(s.segments) > 1)
+
+	require.NoError(t, s.Close())
+	s = testStore(t, dir, 256)
+	requireLogs(t, s, 1, 100)
+}
+
+func TestStore_DeleteRange(t *testing.T) {
+	t.Run("head", func(t *testing.T) {
+		dir := filepath.Join(testutil.TempDir(t, "wal"), "wal")
+		s := testStore(t, dir, 256)
+		require.NoError(t, s.StoreLogs(testLogs(1, 100)))
+		numSegments := len(s.segments)
+
+		require.NoError(t, s.DeleteRange(1, 50))
+		requireLogs(t, s, 51, 100)
+		require.True(t, len(s.segments) < numSegments)
+
+		require.NoError(t, s.Close())
+		s = testStore(t, dir, 256)
+		requireLogs(t, s, 51, 100)
+	})
+
+	t.Run("tail", func(t *testing.T) {
+		dir := filepath.Join(testutil.TempDir(t, "wal"), "wal")
+		s := testStore(t, dir, 256)
+		require.NoError(t, s.StoreLogs(testLogs(1, 100)))
+
+		require.NoError(t, s.DeleteRange(40, 100))
+		requireLogs(t, s, 1, 39)
+
+		// Conflicting logs are replaced after the truncation.
+		require.NoError(t, s.StoreLogs(testLogs(40, 60)))
+		requireLogs(t, s, 1, 60)
+
+		require.NoError(t, s.Close())
+		s = testStore(t, dir, 256)
+		requireLogs(t, s, 1, 60)
+	})
+
+	t.Run("all", func(t *testing.T) {
+		dir := filepath.Join(testutil.TempDir(t, "wal"), "wal")
+		s := testStore(t, dir, 256)
+		require.NoError(t, s.StoreLogs(testLogs(1, 100)))
+		require.NoError(t, s.DeleteRange(1, 50))
+
+		require.NoError(t, s.DeleteRange(0, 100))
+		requireLogs(t, s, 0, 0)
+
+		// The log can start anywhere once empty, as after restoring a
+		// snapshot.
+		require.NoError(t, s.StoreLogs(testLogs(20, 30)))
+		requireLogs(t, s, 20, 30)
+
+		require.NoError(t, s.Close())
+		s = testStore(t, dir, 256)
+		requireLogs(t, s, 20, 30)
+	})
+
+	t.Run("middle", func(t *testing.T) {
+		s := testStore(t, filepath.Join(testutil.TempDir(t, "wal"), "wal"), 0)
+		require.NoError(t, s.StoreLogs(testLogs(1, 100)))
+		require.Error(t, s.DeleteRange(10, 20))
+		requireLogs(t, s, 1, 100)
+	})
+}
+
+func TestStore_TornWrite(t *testing.T) {
+	dir := filepath.Join(testutil.TempDir(t, "wal"), "wal")
+	s := testStore(t, dir, 0)
+	require.NoError(t, s.StoreLogs(testLogs(1, 10)))
+	path := s.activeSegment().path
+	require.NoError(t, s.Close())
+
+	// Simulate a crash in the middle of writing the next batch.
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	require.NoError(t, err)
+	record := appendRecord(nil, testLogs(11, 11)[0])
+	_, err = f.Write(record[:len(record)-3])
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	s = testStore(t, dir, 0)
+	requireLogs(t, s, 1, 10)
+	require.NoError(t, s.StoreLogs(testLogs(11, 12)))
+	requireLogs(t, s, 1, 12)
+}
+
+func TestStore_Corruption(t *testing.T) {
+	dir := filepath.Join(testutil.TempDir(t, "wal"), "wal")
+	s := testStore(t, dir, 256)
+	require.NoError(t, s.StoreLogs(testLogs(1, 100)))
+	path := s.segments[0].path
+	require.NoError(t, s.Close())
+
+	// Corruption within a sealed segment can't be a torn write.
+	raw, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	raw[len(segmentMagic)+recordHeaderSize] ^= 0xff
+	require.NoError(t, ioutil.WriteFile(path, raw, 0600))
+
+	_, err = New(Options{Path: dir, SegmentSize: 256})
+	require.Error(t, err)
+}
+
+func TestStore_StableStore(t *testing.T) {
+	dir := filepath.Join(testutil.TempDir(t, "wal"), "wal")
+	s := testStore(t, dir, 0)
+
+	_, err := s.Get([]byte("missing"))
+	require.Equal(t, ErrKeyNotFound, err)
+	_, err = s.GetUint64([]byte("missing"))
+	require.Equal(t, ErrKeyNotFound, err)
+
+	require.NoError(t, s.Set([]byte("LastVoteCand"), []byte("server-1")))
+	require.NoError(t, s.SetUint64([]byte("CurrentTerm"), 42))
+
+	require.NoError(t, s.Close())
+	s = testStore(t, dir, 0)
+
+	v, err := s.Get([]byte("LastVoteCand"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("server-1"), v)
+	term, err := s.GetUint64([]byte("CurrentTerm"))
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), term)
+}
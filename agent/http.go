@@ -23,6 +23,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/agent/audit"
 	"github.com/hashicorp/consul/agent/cache"
 	"github.com/hashicorp/consul/agent/config"
 	"github.com/hashicorp/consul/agent/consul"
@@ -282,7 +283,7 @@ func (s *HTTPHandlers) handler(enableDebug bool) http.Handler {
 		bound := func(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 			return thisFn(s, resp, req)
 		}
-		handleFuncMetrics(pattern, s.wrap(bound, methods))
+		handleFuncMetrics(pattern, s.wrapAudit(pattern, s.wrap(bound, methods)))
 	}
 
 	// Register wrapped pprof handlers
@@ -367,6 +368,74 @@ var (
 	aclEndpointRE = regexp.MustCompile("^(/v1/acl/(create|update|destroy|info|clone|list)/)([^?]+)([?]?.*)$")
 )
 
+// wrapAudit records the requests served by the handler registered for the
+// pattern in the audit log, when audit logging is enabled.
+func (s *HTTPHandlers) wrapAudit(pattern string, handler http.HandlerFunc) http.HandlerFunc {
+	auditor := s.agent.baseDeps.Auditor
+	if auditor == nil {
+		return handler
+	}
+
+	return func(resp http.ResponseWriter, req *http.Request) {
+		if !auditor.Enabled(pattern, req.Method) {
+			handler(resp, req)
+			return
+		}
+
+		// The handler may clear the token from the request, so it has to be
+		// parsed beforehand.
+		var token string
+		s.parseToken(req, &token)
+
+		start := time.Now()
+		recorder := &auditResponseWriter{ResponseWriter: resp, status: http.StatusOK}
+		handler(recorder, req)
+
+		outcome := audit.OutcomeError
+		switch {
+		case recorder.status < 400:
+			outcome = audit.OutcomeSuccess
+		case recorder.status == http.StatusUnauthorized || recorder.status == http.StatusForbidden:
+			outcome = audit.OutcomeDenied
+		}
+
+		auditor.Record(&audit.Event{
+			Type: audit.EventTypeHTTP,
+			Auth: audit.Auth{AccessorID: s.agent.aclAccessorID(token)},
+			Request: audit.Request{
+				Endpoint:   pattern,
+				Method:     req.Method,
+				Resource:   strings.TrimPrefix(req.URL.Path, pattern),
+				RemoteAddr: req.RemoteAddr,
+			},
+			Response: audit.Response{
+				Outcome:   outcome,
+				Status:    recorder.status,
+				LatencyMS: audit.Latency(time.Since(start)),
+			},
+		})
+	}
+}
+
+// auditResponseWriter captures the status code of a response for the audit
+// log.
+type auditResponseWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *auditResponseWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Flush implements http.Flusher so that streaming endpoints keep working.
+func (w *auditResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
 // wrap is used to wrap functions to make them more convenient
 func (s *HTTPHandlers) wrap(handler endpoint, methods []string) http.HandlerFunc {
 	httpLogger := s.agent.logger.Named(logging.HTTP)
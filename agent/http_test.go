@@ -25,6 +25,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"golang.org/x/net/http2"
 
+	"github.com/hashicorp/consul/agent/audit"
 	"github.com/hashicorp/consul/agent/config"
 	"github.com/hashicorp/consul/agent/structs"
 	tokenStore "github.com/hashicorp/consul/agent/token"
@@ -367,6 +368,76 @@ func TestHTTPAPI_BlockEndpoints(t *testing.T) {
 	}
 }
 
+func TestHTTPAPI_Audit(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	dir := testutil.TempDir(t, "audit")
+	a := NewTestAgent(t, TestACLConfig()+`
+		audit {
+			enabled = true
+			sink "main" {
+				type = "file"
+				format = "json"
+				path = "`+filepath.Join(dir, "audit.json")+`"
+			}
+			include_endpoints = ["/v1/kv/*"]
+			exclude_methods = ["GET"]
+		}
+	`)
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	put := func(path, token string) int {
+		req, _ := http.NewRequest("PUT", path, strings.NewReader("bar"))
+		if token != "" {
+			req.Header.Add("X-Consul-Token", token)
+		}
+		resp := httptest.NewRecorder()
+		a.srv.handler(true).ServeHTTP(resp, req)
+		return resp.Code
+	}
+	require.Equal(t, http.StatusOK, put("/v1/kv/foo", "root"))
+	require.Equal(t, http.StatusForbidden, put("/v1/kv/bar", ""))
+
+	// Excluded by the filters.
+	req, _ := http.NewRequest("GET", "/v1/kv/foo?token=root", nil)
+	a.srv.handler(true).ServeHTTP(httptest.NewRecorder(), req)
+	put("/v1/catalog/register", "root")
+
+	files, err := filepath.Glob(filepath.Join(dir, "audit-*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	raw, err := ioutil.ReadFile(files[0])
+	require.NoError(t, err)
+
+	var events []audit.Event
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	for dec.More() {
+		var event audit.Event
+		require.NoError(t, dec.Decode(&event))
+		events = append(events, event)
+	}
+	require.Len(t, events, 2)
+
+	require.Equal(t, audit.EventTypeHTTP, events[0].Type)
+	require.Equal(t, a.aclAccessorID("root"), events[0].Auth.AccessorID)
+	require.NotEmpty(t, events[0].Auth.AccessorID)
+	require.Equal(t, "/v1/kv/", events[0].Request.Endpoint)
+	require.Equal(t, "PUT", events[0].Request.Method)
+	require.Equal(t, "foo", events[0].Request.Resource)
+	require.Equal(t, audit.OutcomeSuccess, events[0].Response.Outcome)
+	require.Equal(t, http.StatusOK, events[0].Response.Status)
+
+	require.Equal(t, structs.ACLTokenAnonymousID, events[1].Auth.AccessorID)
+	require.Equal(t, "bar", events[1].Request.Resource)
+	require.Equal(t, audit.OutcomeDenied, events[1].Response.Outcome)
+	require.Equal(t, http.StatusForbidden, events[1].Response.Status)
+}
+
 func TestHTTPAPI_Ban_Nonprintable_Characters(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -3,12 +3,15 @@ package agent
 import (
 	"fmt"
 	"net/http"
+	"reflect"
+	"sort"
 	"strconv"
 	"time"
 
 	multierror "github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/raft"
 	autopilot "github.com/hashicorp/raft-autopilot"
+	"github.com/mitchellh/mapstructure"
 
 	"github.com/hashicorp/consul/agent/structs"
 	"github.com/hashicorp/consul/api"
@@ -66,6 +69,25 @@ func (s *HTTPHandlers) OperatorRaftPeer(resp http.ResponseWriter, req *http.Requ
 	return nil, nil
 }
 
+// OperatorRaftTransferLeader transfers the Raft leadership away from the
+// current leader, optionally to the server given by ?id.
+func (s *HTTPHandlers) OperatorRaftTransferLeader(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var args structs.RaftLeaderTransferRequest
+	s.parseDC(req, &args.Datacenter)
+	s.parseToken(req, &args.Token)
+
+	if id := req.URL.Query().Get("id"); id != "" {
+		args.ID = raft.ServerID(id)
+	}
+
+	var reply struct{}
+	if err := s.agent.RPC("Operator.RaftLeaderTransfer", &args, &reply); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
 type keyringArgs struct {
 	Key         string
 	Token       string
@@ -340,7 +362,26 @@ func autopilotToAPIState(state *autopilot.State) *api.AutopilotState {
 	}
 
 	for id, srv := range state.Servers {
-		out.Servers[string(id)] = autopilotToAPIServer(srv)
+		apiSrv := autopilotToAPIServer(srv)
+		if apiSrv.ReadReplica {
+			out.ReadReplicas = append(out.ReadReplicas, apiSrv.ID)
+		}
+		out.Servers[string(id)] = apiSrv
+	}
+	sort.Strings(out.ReadReplicas)
+
+	var ext structs.AutopilotStateExt
+	if decodeAutopilotExt(state.Ext, &ext) && ext.Upgrade != nil {
+		out.Upgrade = &api.AutopilotUpgrade{
+			Status:                    api.AutopilotUpgradeStatus(ext.Upgrade.Status),
+			TargetVersion:             ext.Upgrade.TargetVersion,
+			TargetVersionVoters:       ext.Upgrade.TargetVersionVoters,
+			TargetVersionNonVoters:    ext.Upgrade.TargetVersionNonVoters,
+			TargetVersionReadReplicas: ext.Upgrade.TargetVersionReadReplicas,
+			OtherVersionVoters:        ext.Upgrade.OtherVersionVoters,
+			OtherVersionNonVoters:     ext.Upgrade.OtherVersionNonVoters,
+			OtherVersionReadReplicas:  ext.Upgrade.OtherVersionReadReplicas,
+		}
 	}
 
 	autopilotToAPIStateEnterprise(state, out)
@@ -365,7 +406,32 @@ func autopilotToAPIServer(srv *autopilot.ServerState) api.AutopilotServer {
 		NodeType:    api.AutopilotServerType(srv.Server.NodeType),
 	}
 
+	var ext structs.AutopilotServerExt
+	if decodeAutopilotExt(srv.Server.Ext, &ext) {
+		apiSrv.ReadReplica = ext.ReadReplica
+		apiSrv.UpgradeVersion = ext.UpgradeVersion
+		apiSrv.ReplicationLag = ext.ReplicationLag
+	}
+
 	autopilotToAPIServerEnterprise(srv, &apiSrv)
 
 	return apiSrv
 }
+
+// decodeAutopilotExt decodes the promoter specific state of autopilot into
+// out, which must be a pointer to the type of ext. The state only keeps its
+// type when it was read from the local server, it is a map when the request
+// was forwarded to the leader.
+func decodeAutopilotExt(ext interface{}, out interface{}) bool {
+	if ext == nil {
+		return false
+	}
+	if v := reflect.ValueOf(ext); v.Type() == reflect.TypeOf(out) {
+		if v.IsNil() {
+			return false
+		}
+		reflect.ValueOf(out).Elem().Set(v.Elem())
+		return true
+	}
+	return mapstructure.Decode(ext, out) == nil
+}
@@ -88,6 +88,26 @@ func TestOperator_RaftPeer(t *testing.T) {
 	})
 }
 
+func TestOperator_RaftTransferLeader(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+
+	body := bytes.NewBuffer(nil)
+	req, _ := http.NewRequest("POST", "/v1/operator/raft/transfer-leader?id=nope", body)
+	// If we get this error, it proves we sent the ID all the way through.
+	resp := httptest.NewRecorder()
+	_, err := a.srv.OperatorRaftTransferLeader(resp, req)
+	if err == nil || !strings.Contains(err.Error(),
+		"id \"nope\" was not found in the Raft configuration") {
+		t.Fatalf("err: %v", err)
+	}
+}
+
 func TestOperator_KeyringInstall(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -641,7 +661,11 @@ func TestOperator_AutopilotState(t *testing.T) {
 		require.True(r, ok)
 		require.True(r, srv.Healthy)
 		require.Equal(r, a.config.NodeName, srv.Name)
+		require.NotEmpty(r, srv.UpgradeVersion)
 
+		require.NotNil(r, state.Upgrade)
+		require.Equal(r, api.AutopilotUpgradeDisabled, state.Upgrade.Status)
+		require.Equal(r, []string{string(a.config.NodeID)}, state.Upgrade.TargetVersionVoters)
 	})
 }
 
@@ -798,3 +822,67 @@ func TestAutopilotStateToAPIConversion(t *testing.T) {
 
 	require.Equal(t, &expected, autopilotToAPIState(&input))
 }
+
+func TestAutopilotStateToAPIConversion_Upgrade(t *testing.T) {
+	var leaderID raft.ServerID = "79324811-9588-4311-b208-f272e38aaabf"
+	var followerID raft.ServerID = "ef8aee9a-f9d6-4ec4-b383-aac956bdb80f"
+
+	expected := &api.AutopilotUpgrade{
+		Status:                 api.AutopilotUpgradeAwaitNewVoters,
+		TargetVersion:          "1.10.0",
+		TargetVersionNonVoters: []string{string(followerID)},
+		OtherVersionVoters:     []string{string(leaderID)},
+	}
+
+	// The ext of the state is a map rather than the promoter type when the
+	// state was read from another server.
+	cases := map[string]struct {
+		stateExt  interface{}
+		serverExt interface{}
+	}{
+		"local": {
+			stateExt: &structs.AutopilotStateExt{
+				Upgrade: &structs.AutopilotUpgrade{
+					Status:                 structs.AutopilotUpgradeAwaitNewVoters,
+					TargetVersion:          "1.10.0",
+					TargetVersionNonVoters: []string{string(followerID)},
+					OtherVersionVoters:     []string{string(leaderID)},
+				},
+			},
+			serverExt: &structs.AutopilotServerExt{UpgradeVersion: "1.10.0"},
+		},
+		"forwarded": {
+			stateExt: map[string]interface{}{
+				"Upgrade": map[string]interface{}{
+					"Status":                 "await-new-voters",
+					"TargetVersion":          "1.10.0",
+					"TargetVersionVoters":    nil,
+					"TargetVersionNonVoters": []interface{}{string(followerID)},
+					"OtherVersionVoters":     []interface{}{string(leaderID)},
+				},
+			},
+			serverExt: map[string]interface{}{"UpgradeVersion": "1.10.0"},
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			input := autopilot.State{
+				Leader: leaderID,
+				Voters: []raft.ServerID{leaderID},
+				Servers: map[raft.ServerID]*autopilot.ServerState{
+					followerID: {
+						Server: autopilot.Server{ID: followerID, Ext: tc.serverExt},
+						State:  autopilot.RaftNonVoter,
+					},
+				},
+				Ext: tc.stateExt,
+			}
+
+			out := autopilotToAPIState(&input)
+			require.Equal(t, expected, out.Upgrade)
+			require.Equal(t, "1.10.0", out.Servers[string(followerID)].UpgradeVersion)
+		})
+	}
+}
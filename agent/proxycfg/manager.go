@@ -82,6 +82,10 @@ type ManagerConfig struct {
 	// information to proxies that need to make intention decisions on their
 	// own.
 	IntentionDefaultAllow bool
+
+	// NodeMeta is the node metadata of the local agent. It is used to find
+	// the zone of the proxies for locality-aware load balancing.
+	NodeMeta map[string]string
 }
 
 // NewManager constructs a manager from the provided agent cache.
@@ -209,6 +213,7 @@ func (m *Manager) ensureProxyServiceLocked(ns *structs.NodeService, notifyBroadc
 		source:                m.Source,
 		dnsConfig:             m.DNSConfig,
 		intentionDefaultAllow: m.IntentionDefaultAllow,
+		nodeMeta:              m.NodeMeta,
 	}
 	if m.TLSConfigurator != nil {
 		stateConfig.serverSNIFn = m.TLSConfigurator.ServerSNI
@@ -310,6 +315,18 @@ OUTER:
 	}
 }
 
+// CurrentSnapshot returns a copy of the current valid snapshot for the proxy,
+// or nil if the proxy is not registered or its snapshot is not yet valid.
+func (m *Manager) CurrentSnapshot(proxyID structs.ServiceID) *ConfigSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if state, ok := m.proxies[proxyID]; ok {
+		return state.CurrentSnapshot()
+	}
+	return nil
+}
+
 // Watch registers a watch on a proxy. It might not exist yet in which case this
 // will not fail, but no updates will be delivered until the proxy is
 // registered. If there is already a valid snapshot in memory, it will be
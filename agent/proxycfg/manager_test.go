@@ -240,6 +240,7 @@ func TestManager_BasicLifecycle(t *testing.T) {
 					WatchedServiceChecks:   map[structs.ServiceID][]structs.CheckType{},
 					Intentions:             TestIntentions().Matches[0],
 					IntentionsSet:          true,
+					JWTProviders:           map[string]*structs.JWTProviderConfigEntry{},
 				},
 				Datacenter: "dc1",
 				Locality:   GatewayKey{Datacenter: "dc1", Partition: structs.PartitionOrDefault("")},
@@ -298,6 +299,7 @@ func TestManager_BasicLifecycle(t *testing.T) {
 					WatchedServiceChecks:   map[structs.ServiceID][]structs.CheckType{},
 					Intentions:             TestIntentions().Matches[0],
 					IntentionsSet:          true,
+					JWTProviders:           map[string]*structs.JWTProviderConfigEntry{},
 				},
 				Datacenter: "dc1",
 				Locality:   GatewayKey{Datacenter: "dc1", Partition: structs.PartitionOrDefault("")},
@@ -117,6 +117,11 @@ type configSnapshotConnectProxy struct {
 
 	MeshConfig    *structs.MeshConfigEntry
 	MeshConfigSet bool
+
+	// JWTProviders holds the jwt-provider config entries keyed by name so
+	// that intentions requiring a JWT can be enforced on the public
+	// listener.
+	JWTProviders map[string]*structs.JWTProviderConfigEntry
 }
 
 func (c *configSnapshotConnectProxy) IsEmpty() bool {
@@ -136,7 +141,8 @@ func (c *configSnapshotConnectProxy) IsEmpty() bool {
 		len(c.UpstreamConfig) == 0 &&
 		len(c.PassthroughUpstreams) == 0 &&
 		len(c.IntentionUpstreams) == 0 &&
-		!c.MeshConfigSet
+		!c.MeshConfigSet &&
+		len(c.JWTProviders) == 0
 }
 
 type configSnapshotTerminatingGateway struct {
@@ -204,6 +210,10 @@ type configSnapshotTerminatingGateway struct {
 	// HostnameServices is a map of service name to service instances with a hostname as the address.
 	// If hostnames are configured they must be provided to Envoy via CDS not EDS.
 	HostnameServices map[structs.ServiceName]structs.CheckServiceNodes
+
+	// MeshConfig is the mesh config entry, used to determine whether
+	// intentions are enforced or only audited.
+	MeshConfig *structs.MeshConfigEntry
 }
 
 // ValidServices returns the list of service keys that have enough data to be emitted.
@@ -417,6 +427,7 @@ type ConfigSnapshot struct {
 	Address               string
 	Port                  int
 	ServiceMeta           map[string]string
+	NodeMeta              map[string]string
 	TaggedAddresses       map[string]structs.ServiceAddress
 	Proxy                 structs.ConnectProxyConfig
 	Datacenter            string
@@ -516,3 +527,16 @@ func (s *ConfigSnapshot) Leaf() *structs.IssuedCert {
 		return nil
 	}
 }
+
+// IntentionsAuditMode returns whether intentions should only be audited
+// rather than enforced, as configured by the mesh config entry.
+func (s *ConfigSnapshot) IntentionsAuditMode() bool {
+	switch s.Kind {
+	case structs.ServiceKindConnectProxy:
+		return s.ConnectProxy.MeshConfig.IntentionsAuditMode()
+	case structs.ServiceKindTerminatingGateway:
+		return s.TerminatingGateway.MeshConfig.IntentionsAuditMode()
+	default:
+		return false
+	}
+}
@@ -45,6 +45,7 @@ const (
 	serviceIntentionsIDPrefix          = "service-intentions:"
 	intentionUpstreamsID               = "intention-upstreams"
 	meshConfigEntryID                  = "mesh"
+	jwtProviderID                      = "jwt-provider"
 	svcChecksWatchIDPrefix             = cachetype.ServiceHTTPChecksName + ":"
 	preparedQueryIDPrefix              = string(structs.UpstreamDestTypePreparedQuery) + ":"
 	defaultPreparedQueryPollInterval   = 30 * time.Second
@@ -58,6 +59,7 @@ type stateConfig struct {
 	dnsConfig             DNSConfig
 	serverSNIFn           ServerSNIFunc
 	intentionDefaultAllow bool
+	nodeMeta              map[string]string
 }
 
 // state holds all the state needed to maintain the config for a registered
@@ -256,6 +258,7 @@ func newConfigSnapshotFromServiceInstance(s serviceInstance, config stateConfig)
 		Address:               s.address,
 		Port:                  s.port,
 		ServiceMeta:           s.meta,
+		NodeMeta:              config.nodeMeta,
 		TaggedAddresses:       s.taggedAddresses,
 		Proxy:                 s.proxyCfg,
 		Datacenter:            config.source.Datacenter,
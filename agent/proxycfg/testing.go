@@ -33,6 +33,8 @@ type TestCacheTypes struct {
 	query             *ControllableCacheType
 	compiledChain     *ControllableCacheType
 	serviceHTTPChecks *ControllableCacheType
+	configEntries     *ControllableCacheType
+	configEntry       *ControllableCacheType
 }
 
 // NewTestCacheTypes creates a set of ControllableCacheTypes for all types that
@@ -47,6 +49,8 @@ func NewTestCacheTypes(t testing.T) *TestCacheTypes {
 		query:             NewControllableCacheType(t),
 		compiledChain:     NewControllableCacheType(t),
 		serviceHTTPChecks: NewControllableCacheType(t),
+		configEntries:     NewControllableCacheType(t),
+		configEntry:       NewControllableCacheType(t),
 	}
 	ct.query.blocking = false
 	return ct
@@ -63,6 +67,8 @@ func TestCacheWithTypes(t testing.T, types *TestCacheTypes) *cache.Cache {
 	c.RegisterType(cachetype.PreparedQueryName, types.query)
 	c.RegisterType(cachetype.CompiledDiscoveryChainName, types.compiledChain)
 	c.RegisterType(cachetype.ServiceHTTPChecksName, types.serviceHTTPChecks)
+	c.RegisterType(cachetype.ConfigEntriesName, types.configEntries)
+	c.RegisterType(cachetype.ConfigEntryName, types.configEntry)
 
 	return c
 }
@@ -789,10 +795,22 @@ func TestConfigSnapshotDiscoveryChainWithGRPCRouter(t testing.T) *ConfigSnapshot
 	return testConfigSnapshotDiscoveryChain(t, "grpc-router")
 }
 
+func TestConfigSnapshotDiscoveryChainWithGRPCMatch(t testing.T) *ConfigSnapshot {
+	return testConfigSnapshotDiscoveryChain(t, "grpc-router-with-grpc-match")
+}
+
 func TestConfigSnapshotDiscoveryChainWithRouter(t testing.T) *ConfigSnapshot {
 	return testConfigSnapshotDiscoveryChain(t, "chain-and-router")
 }
 
+func TestConfigSnapshotDiscoveryChainWithFaultInjection(t testing.T) *ConfigSnapshot {
+	return testConfigSnapshotDiscoveryChain(t, "chain-and-router-with-fault")
+}
+
+func TestConfigSnapshotDiscoveryChainWithMirror(t testing.T) *ConfigSnapshot {
+	return testConfigSnapshotDiscoveryChain(t, "chain-and-router-with-mirror")
+}
+
 func TestConfigSnapshotDiscoveryChainWithLB(t testing.T) *ConfigSnapshot {
 	return testConfigSnapshotDiscoveryChain(t, "lb-resolver")
 }
@@ -1108,6 +1126,160 @@ func setupTestVariationConfigEntriesAndSnapshot(
 				},
 			},
 		)
+	case "grpc-router-with-grpc-match":
+		entries = append(entries,
+			&structs.ProxyConfigEntry{
+				Kind: structs.ProxyDefaults,
+				Name: structs.ProxyConfigGlobal,
+				Config: map[string]interface{}{
+					"protocol": "grpc",
+				},
+			},
+			&structs.ServiceResolverConfigEntry{
+				Kind: structs.ServiceResolver,
+				Name: "db",
+				Subsets: map[string]structs.ServiceResolverSubset{
+					"v2": {
+						Filter: "Service.Meta.version == 2",
+					},
+				},
+			},
+			&structs.ServiceRouterConfigEntry{
+				Kind: structs.ServiceRouter,
+				Name: "db",
+				Routes: []structs.ServiceRoute{
+					{
+						Match: &structs.ServiceRouteMatch{
+							GRPC: &structs.ServiceRouteGRPCMatch{
+								Service: "fgrpc.PingServer",
+								Method:  "Ping",
+							},
+						},
+						Destination: &structs.ServiceRouteDestination{
+							ServiceSubset: "v2",
+						},
+					},
+					{
+						Match: &structs.ServiceRouteMatch{
+							GRPC: &structs.ServiceRouteGRPCMatch{
+								Service: "fgrpc.AdminServer",
+								Metadata: []structs.ServiceRouteHTTPMatchHeader{
+									{Name: "x-debug", Exact: "1"},
+								},
+							},
+						},
+						Destination: &structs.ServiceRouteDestination{
+							Service: "admin",
+						},
+					},
+				},
+			},
+		)
+	case "chain-and-router-with-fault":
+		entries = append(entries,
+			&structs.ProxyConfigEntry{
+				Kind: structs.ProxyDefaults,
+				Name: structs.ProxyConfigGlobal,
+				Config: map[string]interface{}{
+					"protocol": "http",
+				},
+			},
+			&structs.ServiceRouterConfigEntry{
+				Kind: structs.ServiceRouter,
+				Name: "db",
+				Routes: []structs.ServiceRoute{
+					{
+						Match: httpMatch(&structs.ServiceRouteHTTPMatch{
+							PathPrefix: "/delay",
+						}),
+						Destination: &structs.ServiceRouteDestination{
+							Service: "delay",
+							Fault: &structs.ServiceRouteFault{
+								Delay: &structs.ServiceRouteFaultDelay{
+									Duration:   3 * time.Second,
+									Percentage: 12.5,
+								},
+							},
+						},
+					},
+					{
+						Match: httpMatch(&structs.ServiceRouteHTTPMatch{
+							PathPrefix: "/abort",
+						}),
+						Destination: &structs.ServiceRouteDestination{
+							Service: "abort",
+							Fault: &structs.ServiceRouteFault{
+								Abort: &structs.ServiceRouteFaultAbort{
+									HTTPStatus: 503,
+								},
+								Header: []structs.ServiceRouteHTTPMatchHeader{
+									{
+										Name:  "x-chaos",
+										Exact: "abort",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		)
+	case "chain-and-router-with-mirror":
+		entries = append(entries,
+			&structs.ProxyConfigEntry{
+				Kind: structs.ProxyDefaults,
+				Name: structs.ProxyConfigGlobal,
+				Config: map[string]interface{}{
+					"protocol": "http",
+				},
+			},
+			&structs.ServiceSplitterConfigEntry{
+				Kind: structs.ServiceSplitter,
+				Name: "split",
+				Splits: []structs.ServiceSplit{
+					{
+						Weight:  80,
+						Service: "v1",
+						Mirror: &structs.RequestMirror{
+							Service:    "v2-shadow",
+							Percentage: 50,
+						},
+					},
+					{
+						Weight:  20,
+						Service: "v2",
+						Mirror: &structs.RequestMirror{
+							Service:    "v2-shadow",
+							Percentage: 50,
+						},
+					},
+				},
+			},
+			&structs.ServiceRouterConfigEntry{
+				Kind: structs.ServiceRouter,
+				Name: "db",
+				Routes: []structs.ServiceRoute{
+					{
+						Match: httpMatch(&structs.ServiceRouteHTTPMatch{
+							PathPrefix: "/mirror",
+						}),
+						Destination: &structs.ServiceRouteDestination{
+							Service: "mirrored",
+							Mirror: &structs.RequestMirror{
+								Service:    "shadow",
+								Percentage: 12.5,
+							},
+						},
+					},
+					{
+						Match: httpMatch(&structs.ServiceRouteHTTPMatch{
+							PathPrefix: "/split",
+						}),
+						Destination: toService("split"),
+					},
+				},
+			},
+		)
 	case "chain-and-router":
 		entries = append(entries,
 			&structs.ServiceResolverConfigEntry{
@@ -1484,7 +1656,10 @@ func setupTestVariationConfigEntriesAndSnapshot(
 		}
 	case "chain-and-splitter":
 	case "grpc-router":
+	case "grpc-router-with-grpc-match":
 	case "chain-and-router":
+	case "chain-and-router-with-fault":
+	case "chain-and-router-with-mirror":
 	case "http-multiple-services":
 		snap.WatchedUpstreamEndpoints[UpstreamIDFromString("foo")] = map[string]structs.CheckServiceNodes{
 			"foo.default.default.dc1": TestUpstreamNodes(t, "foo"),
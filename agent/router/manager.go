@@ -202,6 +202,19 @@ func (m *Manager) FindServer() *metadata.Server {
 	return l.servers[0]
 }
 
+// FindReadReplica returns the first read replica of the list of servers. The
+// list is shuffled when rebalancing, so that the agents spread their requests
+// over the read replicas. If there are no read replicas, return nil.
+func (m *Manager) FindReadReplica() *metadata.Server {
+	l := m.getServerList()
+	for _, srv := range l.servers {
+		if srv.ReadReplica {
+			return srv
+		}
+	}
+	return nil
+}
+
 func (m *Manager) checkServers(fn func(srv *metadata.Server) bool) bool {
 	if m == nil {
 		return true
@@ -194,6 +194,32 @@ func TestServers_FindServer(t *testing.T) {
 	}
 }
 
+func TestServers_FindReadReplica(t *testing.T) {
+	m := testManager(t)
+
+	m.AddServer(&metadata.Server{Name: "s1"})
+	if m.FindReadReplica() != nil {
+		t.Fatalf("Expected nil return")
+	}
+
+	m.AddServer(&metadata.Server{Name: "r1", ReadReplica: true})
+	m.AddServer(&metadata.Server{Name: "r2", ReadReplica: true})
+
+	r1 := m.FindReadReplica()
+	if r1 == nil || r1.Name != "r1" {
+		t.Fatalf("Expected r1 server")
+	}
+	if s1 := m.FindServer(); s1 == nil || s1.Name != "s1" {
+		t.Fatalf("Expected s1 server")
+	}
+
+	m.RemoveServer(r1)
+	r2 := m.FindReadReplica()
+	if r2 == nil || r2.Name != "r2" {
+		t.Fatalf("Expected r2 server")
+	}
+}
+
 func TestServers_New(t *testing.T) {
 	logger := testutil.Logger(t)
 	shutdownCh := make(chan struct{})
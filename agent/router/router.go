@@ -387,6 +387,24 @@ func (r *Router) FindLANRoute() (*Manager, *metadata.Server) {
 	return mgr, mgr.FindServer()
 }
 
+// FindLANReadReplicaRoute returns a read replica within the local datacenter,
+// or a server like FindLANRoute when there are none. The LAN manager only
+// knows about the servers in the LAN segment of the agent, so a read replica
+// it returns is close to the agent and can serve its stale reads without
+// going through the voters.
+func (r *Router) FindLANReadReplicaRoute() (*Manager, *metadata.Server) {
+	mgr := r.GetLANManager()
+
+	if mgr == nil {
+		return nil, nil
+	}
+
+	if srv := mgr.FindReadReplica(); srv != nil {
+		return mgr, srv
+	}
+	return mgr, mgr.FindServer()
+}
+
 // FindLANServer will look for a server in the local datacenter.
 // This function may return a nil value if no server is available.
 func (r *Router) FindLANServer() *metadata.Server {
@@ -527,4 +527,9 @@ func TestRouter_FindLANServer(t *testing.T) {
 	mgr, srv2 := r.FindLANRoute()
 	require.NotNil(t, mgr)
 	require.Equal(t, srv, srv2)
+
+	// Without a read replica any server is used.
+	mgr, srv3 := r.FindLANReadReplicaRoute()
+	require.NotNil(t, mgr)
+	require.Equal(t, srv, srv3)
 }
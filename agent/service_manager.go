@@ -386,6 +386,9 @@ func mergeServiceConfig(defaults *structs.ServiceConfigResponse, service *struct
 	if !ns.Proxy.TransparentProxy.DialedDirectly {
 		ns.Proxy.TransparentProxy.DialedDirectly = defaults.TransparentProxy.DialedDirectly
 	}
+	if ns.Proxy.AccessLogs.IsZero() {
+		ns.Proxy.AccessLogs = defaults.AccessLogs
+	}
 
 	// remoteUpstreams contains synthetic Upstreams generated from central config (service-defaults.UpstreamConfigs).
 	remoteUpstreams := make(map[structs.ServiceID]structs.Upstream)
@@ -14,12 +14,14 @@ import (
 	"github.com/hashicorp/go-hclog"
 	"google.golang.org/grpc/grpclog"
 
+	"github.com/hashicorp/consul/agent/audit"
 	autoconf "github.com/hashicorp/consul/agent/auto-config"
 	"github.com/hashicorp/consul/agent/cache"
 	"github.com/hashicorp/consul/agent/config"
 	"github.com/hashicorp/consul/agent/consul"
 	"github.com/hashicorp/consul/agent/consul/fsm"
 	"github.com/hashicorp/consul/agent/consul/usagemetrics"
+	"github.com/hashicorp/consul/agent/consul/wal"
 	"github.com/hashicorp/consul/agent/grpc"
 	"github.com/hashicorp/consul/agent/grpc/resolver"
 	"github.com/hashicorp/consul/agent/local"
@@ -101,6 +103,11 @@ func NewBaseDeps(configLoader ConfigLoader, logOut io.Writer) (BaseDeps, error)
 	d.RuntimeConfig = cfg
 	d.Tokens = new(token.Store)
 
+	d.Auditor, err = audit.New(cfg.Audit, d.Logger.Named(logging.Audit))
+	if err != nil {
+		return d, fmt.Errorf("failed to setup audit logging: %w", err)
+	}
+
 	cfg.Cache.Logger = d.Logger.Named("cache")
 	// cache-types are not registered yet, but they won't be used until the components are started.
 	d.Cache = cache.New(cfg.Cache)
@@ -200,6 +207,10 @@ func getPrometheusDefs(cfg lib.TelemetryConfig, isServer bool) ([]prometheus.Gau
 			Name: []string{"raft", "leader", "oldestLogAge"},
 			Help: "This measures how old the oldest log in the leader's log store is.",
 		},
+		{
+			Name: []string{"raft", "boltdb", "freelistBytes"},
+			Help: "Represents the number of bytes necessary to encode the freelist metadata of the BoltDB log store.",
+		},
 	}
 
 	// Build slice of slices for all gauge definitions
@@ -207,11 +218,13 @@ func getPrometheusDefs(cfg lib.TelemetryConfig, isServer bool) ([]prometheus.Gau
 		cache.Gauges,
 		consul.RPCGauges,
 		consul.SessionGauges,
+		consul.NodeTTLGauges,
 		grpc.StatsGauges,
 		xds.StatsGauges,
 		usagemetrics.Gauges,
 		consul.ReplicationGauges,
 		CertExpirationGauges,
+		ACLLoginGauges,
 		Gauges,
 		raftGauges,
 	}
@@ -220,7 +233,8 @@ func getPrometheusDefs(cfg lib.TelemetryConfig, isServer bool) ([]prometheus.Gau
 	if isServer {
 		gauges = append(gauges,
 			consul.AutopilotGauges,
-			consul.LeaderCertExpirationGauges)
+			consul.LeaderCertExpirationGauges,
+			wal.Gauges)
 	}
 
 	// Flatten definitions
@@ -255,7 +269,9 @@ func getPrometheusDefs(cfg lib.TelemetryConfig, isServer bool) ([]prometheus.Gau
 	}
 
 	var counters = [][]prometheus.CounterDefinition{
+		ACLLoginCounters,
 		CatalogCounters,
+		ConnectAuthorizeCounters,
 		cache.Counters,
 		consul.ACLCounters,
 		consul.CatalogCounters,
@@ -297,6 +313,18 @@ func getPrometheusDefs(cfg lib.TelemetryConfig, isServer bool) ([]prometheus.Gau
 			Name: []string{"raft", "rpc", "installSnapshot"},
 			Help: "Measures the time it takes the raft leader to install a snapshot on a follower that is catching up after being down or has just joined the cluster.",
 		},
+		{
+			Name: []string{"raft", "boltdb", "getLog"},
+			Help: "Measures the amount of time spent reading logs from the BoltDB log store.",
+		},
+		{
+			Name: []string{"raft", "boltdb", "storeLogs"},
+			Help: "Measures the amount of time spent writing logs to the BoltDB log store.",
+		},
+		{
+			Name: []string{"raft", "boltdb", "logsPerBatch"},
+			Help: "Measures the number of logs being written per batch to the BoltDB log store.",
+		},
 	}
 
 	var summaries = [][]prometheus.SummaryDefinition{
@@ -308,6 +336,7 @@ func getPrometheusDefs(cfg lib.TelemetryConfig, isServer bool) ([]prometheus.Gau
 		consul.IntentionSummaries,
 		consul.KVSummaries,
 		consul.LeaderSummaries,
+		consul.NodeTTLSummaries,
 		consul.PreparedQuerySummaries,
 		consul.RPCSummaries,
 		consul.SegmentOSSSummaries,
@@ -317,6 +346,7 @@ func getPrometheusDefs(cfg lib.TelemetryConfig, isServer bool) ([]prometheus.Gau
 		fsm.CommandsSummaries,
 		fsm.SnapshotSummaries,
 		raftSummaries,
+		wal.Summaries,
 	}
 	// Flatten definitions
 	// NOTE(kit): Do we actually want to create a set here so we can ensure definition names are unique?
@@ -97,6 +97,7 @@ type ACLIdentity interface {
 	RoleIDs() []string
 	ServiceIdentityList() []*ACLServiceIdentity
 	NodeIdentityList() []*ACLNodeIdentity
+	PolicyTemplateList() []*ACLPolicyTemplateLink
 	IsExpired(asOf time.Time) bool
 	IsLocal() bool
 	EnterpriseMetadata() *EnterpriseMeta
@@ -237,6 +238,10 @@ type ACLToken struct {
 	// The node identities that this token should be allowed to manage.
 	NodeIdentities []*ACLNodeIdentity `json:",omitempty"`
 
+	// List of policy template links. Prior to token creation the template
+	// names get validated and the template IDs get stored herein.
+	PolicyTemplates []*ACLPolicyTemplateLink `json:",omitempty"`
+
 	// Type is the V1 Token Type
 	// DEPRECATED (ACL-Legacy-Compat) - remove once we no longer support v1 ACL compat
 	// Even though we are going to auto upgrade management tokens we still
@@ -278,6 +283,21 @@ type ACLToken struct {
 	// The time when this token was created
 	CreateTime time.Time `json:",omitempty"`
 
+	// LastUsedTime is the approximate time when this token was last resolved
+	// by a server of this datacenter. In the primary datacenter it also
+	// accounts for the usage of global tokens reported by the secondary
+	// datacenters. Servers batch usage before committing it through Raft so it
+	// can lag behind by a few minutes. It is nil when the token has not been
+	// used since usage tracking was introduced.
+	//
+	// Followers report the usage they see to the leader with their
+	// replication token, so it only accounts for the usage seen by followers
+	// when they have one.
+	//
+	// This is only updated through ACLTokenUsageSetRequest and is preserved
+	// across token updates and replication.
+	LastUsedTime *time.Time `json:",omitempty"`
+
 	// Hash of the contents of the token
 	//
 	// This is needed mainly for replication purposes. When replicating from
@@ -328,6 +348,7 @@ func (t *ACLToken) Clone() *ACLToken {
 	t2.Roles = nil
 	t2.ServiceIdentities = nil
 	t2.NodeIdentities = nil
+	t2.PolicyTemplates = cloneACLPolicyTemplateLinks(t.PolicyTemplates)
 
 	if len(t.Policies) > 0 {
 		t2.Policies = make([]ACLTokenPolicyLink, len(t.Policies))
@@ -397,6 +418,10 @@ func (t *ACLToken) ServiceIdentityList() []*ACLServiceIdentity {
 	return out
 }
 
+func (t *ACLToken) PolicyTemplateList() []*ACLPolicyTemplateLink {
+	return cloneACLPolicyTemplateLinks(t.PolicyTemplates)
+}
+
 func (t *ACLToken) IsExpired(asOf time.Time) bool {
 	if asOf.IsZero() || !t.HasExpirationTime() {
 		return false
@@ -427,7 +452,8 @@ func (t *ACLToken) SetHash(force bool) []byte {
 		// Any non-immutable "content" fields should be involved with the
 		// overall hash. The IDs are immutable which is why they aren't here.
 		// The raft indices are metadata similar to the hash which is why they
-		// aren't incorporated. CreateTime is similarly immutable
+		// aren't incorporated. CreateTime is similarly immutable and
+		// LastUsedTime is usage metadata that is tracked in each datacenter.
 		//
 		// The Hash is really only used for replication to determine if a token
 		// has changed and should be updated locally.
@@ -459,6 +485,10 @@ func (t *ACLToken) SetHash(force bool) []byte {
 			nodeID.AddToHash(hash)
 		}
 
+		for _, link := range t.PolicyTemplates {
+			link.AddToHash(hash)
+		}
+
 		t.EnterpriseMeta.addToHash(hash, false)
 
 		// Finalize the hash
@@ -485,6 +515,9 @@ func (t *ACLToken) EstimateSize() int {
 	for _, nodeID := range t.NodeIdentities {
 		size += nodeID.EstimateSize()
 	}
+	for _, link := range t.PolicyTemplates {
+		size += link.EstimateSize()
+	}
 	return size + t.EnterpriseMeta.estimateSize()
 }
 
@@ -495,14 +528,16 @@ type ACLTokenListStub struct {
 	AccessorID        string
 	SecretID          string
 	Description       string
-	Policies          []ACLTokenPolicyLink  `json:",omitempty"`
-	Roles             []ACLTokenRoleLink    `json:",omitempty"`
-	ServiceIdentities []*ACLServiceIdentity `json:",omitempty"`
-	NodeIdentities    []*ACLNodeIdentity    `json:",omitempty"`
+	Policies          []ACLTokenPolicyLink     `json:",omitempty"`
+	Roles             []ACLTokenRoleLink       `json:",omitempty"`
+	ServiceIdentities []*ACLServiceIdentity    `json:",omitempty"`
+	NodeIdentities    []*ACLNodeIdentity       `json:",omitempty"`
+	PolicyTemplates   []*ACLPolicyTemplateLink `json:",omitempty"`
 	Local             bool
 	AuthMethod        string     `json:",omitempty"`
 	ExpirationTime    *time.Time `json:",omitempty"`
 	CreateTime        time.Time  `json:",omitempty"`
+	LastUsedTime      *time.Time `json:",omitempty"`
 	Hash              []byte
 	CreateIndex       uint64
 	ModifyIndex       uint64
@@ -522,10 +557,12 @@ func (token *ACLToken) Stub() *ACLTokenListStub {
 		Roles:                       token.Roles,
 		ServiceIdentities:           token.ServiceIdentities,
 		NodeIdentities:              token.NodeIdentities,
+		PolicyTemplates:             token.PolicyTemplates,
 		Local:                       token.Local,
 		AuthMethod:                  token.AuthMethod,
 		ExpirationTime:              token.ExpirationTime,
 		CreateTime:                  token.CreateTime,
+		LastUsedTime:                token.LastUsedTime,
 		Hash:                        token.Hash,
 		CreateIndex:                 token.CreateIndex,
 		ModifyIndex:                 token.ModifyIndex,
@@ -562,6 +599,12 @@ type ACLPolicy struct {
 	// The rule set (using the updated rule syntax)
 	Rules string
 
+	// Variables declares the variables of a policy template. A policy with
+	// variables is a template: its rules reference the variables as ${name}
+	// and it only applies through the policy template links of tokens and
+	// roles, which set the values of the variables.
+	Variables []*ACLPolicyVariable `json:",omitempty"`
+
 	// DEPRECATED (ACL-Legacy-Compat) - This is only needed while we support the legacy ACLs
 	Syntax acl.SyntaxVersion `json:"-"`
 
@@ -607,6 +650,13 @@ func (t *ACLPolicy) UnmarshalJSON(data []byte) error {
 func (p *ACLPolicy) Clone() *ACLPolicy {
 	p2 := *p
 	p2.Datacenters = CloneStringSlice(p.Datacenters)
+	if len(p.Variables) > 0 {
+		p2.Variables = make([]*ACLPolicyVariable, len(p.Variables))
+		for i, v := range p.Variables {
+			v2 := *v
+			p2.Variables[i] = &v2
+		}
+	}
 	return &p2
 }
 
@@ -615,6 +665,7 @@ type ACLPolicyListStub struct {
 	Name        string
 	Description string
 	Datacenters []string
+	Template    bool `json:",omitempty"`
 	Hash        []byte
 	CreateIndex uint64
 	ModifyIndex uint64
@@ -627,6 +678,7 @@ func (p *ACLPolicy) Stub() *ACLPolicyListStub {
 		Name:           p.Name,
 		Description:    p.Description,
 		Datacenters:    p.Datacenters,
+		Template:       p.IsTemplate(),
 		Hash:           p.Hash,
 		CreateIndex:    p.CreateIndex,
 		ModifyIndex:    p.ModifyIndex,
@@ -660,6 +712,10 @@ func (p *ACLPolicy) SetHash(force bool) []byte {
 		for _, dc := range p.Datacenters {
 			hash.Write([]byte(dc))
 		}
+		for _, v := range p.Variables {
+			hash.Write([]byte(v.Name))
+			hash.Write([]byte(v.Type))
+		}
 
 		p.EnterpriseMeta.addToHash(hash, false)
 
@@ -681,6 +737,9 @@ func (p *ACLPolicy) EstimateSize() int {
 	for _, dc := range p.Datacenters {
 		size += len(dc)
 	}
+	for _, v := range p.Variables {
+		size += len(v.Name) + len(v.Type)
+	}
 
 	return size + p.EnterpriseMeta.estimateSize()
 }
@@ -811,6 +870,10 @@ type ACLRole struct {
 	// List of nodes to generate synthetic policies for.
 	NodeIdentities []*ACLNodeIdentity `json:",omitempty"`
 
+	// List of policy template links. Prior to role creation the template
+	// names get validated and the template IDs get stored herein.
+	PolicyTemplates []*ACLPolicyTemplateLink `json:",omitempty"`
+
 	// Hash of the contents of the role
 	// This does not take into account the ID (which is immutable)
 	// nor the raft metadata.
@@ -850,6 +913,7 @@ func (r *ACLRole) Clone() *ACLRole {
 	r2.Policies = nil
 	r2.ServiceIdentities = nil
 	r2.NodeIdentities = nil
+	r2.PolicyTemplates = cloneACLPolicyTemplateLinks(r.PolicyTemplates)
 
 	if len(r.Policies) > 0 {
 		r2.Policies = make([]ACLRolePolicyLink, len(r.Policies))
@@ -898,6 +962,9 @@ func (r *ACLRole) SetHash(force bool) []byte {
 		for _, nodeID := range r.NodeIdentities {
 			nodeID.AddToHash(hash)
 		}
+		for _, link := range r.PolicyTemplates {
+			link.AddToHash(hash)
+		}
 
 		r.EnterpriseMeta.addToHash(hash, false)
 
@@ -925,6 +992,9 @@ func (r *ACLRole) EstimateSize() int {
 	for _, nodeID := range r.NodeIdentities {
 		size += nodeID.EstimateSize()
 	}
+	for _, link := range r.PolicyTemplates {
+		size += link.EstimateSize()
+	}
 
 	return size + r.EnterpriseMeta.estimateSize()
 }
@@ -1297,6 +1367,26 @@ type ACLTokenBatchDeleteRequest struct {
 	TokenIDs []string // Tokens to delete
 }
 
+// ACLTokenUsageUpdateRequest is used by servers to report to the leader when
+// tokens were used. It requires acl write permission.
+type ACLTokenUsageUpdateRequest struct {
+	// Usage maps the AccessorID of tokens to the time they were last used.
+	Usage      map[string]time.Time
+	Datacenter string
+	WriteRequest
+}
+
+func (r *ACLTokenUsageUpdateRequest) RequestDatacenter() string {
+	return r.Datacenter
+}
+
+// ACLTokenUsageSetRequest is used only at the Raft layer to record when
+// tokens were last used.
+type ACLTokenUsageSetRequest struct {
+	// Usage maps the AccessorID of tokens to the time they were last used.
+	Usage map[string]time.Time
+}
+
 // ACLTokenBootstrapRequest is used only at the Raft layer
 // for ACL bootstrapping
 //
@@ -1666,6 +1756,7 @@ type ACLLoginParams struct {
 	AuthMethod  string
 	BearerToken string
 	Meta        map[string]string `json:",omitempty"`
+
 	EnterpriseMeta
 }
 
@@ -1679,6 +1770,71 @@ func (r *ACLLoginRequest) RequestDatacenter() string {
 	return r.Datacenter
 }
 
+type ACLLoginChallengeParams struct {
+	AuthMethod string
+	EnterpriseMeta
+}
+
+type ACLLoginChallengeRequest struct {
+	Auth       *ACLLoginChallengeParams
+	Datacenter string // The datacenter to perform the request within
+	WriteRequest
+}
+
+func (r *ACLLoginChallengeRequest) RequestDatacenter() string {
+	return r.Datacenter
+}
+
+// ACLLoginChallengeResponse holds the challenge the client must sign to log
+// in with an auth method requiring one.
+type ACLLoginChallengeResponse struct {
+	Challenge string
+}
+
+type ACLOIDCAuthURLParams struct {
+	AuthMethod  string
+	RedirectURI string
+	// ClientNonce is a random value chosen by the client starting the login.
+	// The same value must be provided to the callback to complete it.
+	ClientNonce string
+	Meta        map[string]string `json:",omitempty"`
+	EnterpriseMeta
+}
+
+type ACLOIDCAuthURLRequest struct {
+	Auth       *ACLOIDCAuthURLParams
+	Datacenter string // The datacenter to perform the request within
+	WriteRequest
+}
+
+func (r *ACLOIDCAuthURLRequest) RequestDatacenter() string {
+	return r.Datacenter
+}
+
+// ACLOIDCAuthURLResponse holds the URL of the OIDC provider the user must
+// visit to start an OIDC login.
+type ACLOIDCAuthURLResponse struct {
+	AuthURL string
+}
+
+type ACLOIDCCallbackParams struct {
+	AuthMethod  string
+	State       string
+	Code        string
+	ClientNonce string
+	EnterpriseMeta
+}
+
+type ACLOIDCCallbackRequest struct {
+	Auth       *ACLOIDCCallbackParams
+	Datacenter string // The datacenter to perform the request within
+	WriteRequest
+}
+
+func (r *ACLOIDCCallbackRequest) RequestDatacenter() string {
+	return r.Datacenter
+}
+
 type ACLLogoutRequest struct {
 	Datacenter string // The datacenter to perform the request within
 	WriteRequest
@@ -1710,6 +1866,69 @@ func (r *RemoteACLAuthorizationRequest) RequestDatacenter() string {
 	return r.Datacenter
 }
 
+const (
+	ACLExplainSourcePolicy          = "policy"
+	ACLExplainSourceServiceIdentity = "service-identity"
+	ACLExplainSourceNodeIdentity    = "node-identity"
+	ACLExplainSourcePolicyTemplate  = "policy-template"
+)
+
+// ACLExplainRequest is used to explain the authorization decisions made for
+// a token.
+type ACLExplainRequest struct {
+	Datacenter string
+
+	// AccessorID is the accessor of the token to explain. Explaining another
+	// token than the one of the request requires acl:read. When empty, the
+	// token of the request is explained.
+	AccessorID string
+
+	Requests []ACLAuthorizationRequest
+	EnterpriseMeta
+	QueryOptions
+}
+
+func (r *ACLExplainRequest) RequestDatacenter() string {
+	return r.Datacenter
+}
+
+// ACLAuthorizationExplanation describes how an authorization decision was
+// reached.
+type ACLAuthorizationExplanation struct {
+	ACLAuthorizationRequest
+	Allow bool
+
+	// Rule is the policy rule that rendered the decision. It is nil when no
+	// single rule did, for example when the default policy applies.
+	Rule *acl.ExplainedRule `json:",omitempty"`
+
+	// Sources are the policies and identities of the token, directly or
+	// through a role, that define Rule.
+	Sources []ACLExplainSource `json:",omitempty"`
+
+	// DefaultPolicy is set to the ACL default policy when no rule of the
+	// token applied and the default policy rendered the decision.
+	DefaultPolicy string `json:",omitempty"`
+}
+
+// ACLExplainSource is a policy or an identity of a token defining the rule
+// that rendered an authorization decision.
+type ACLExplainSource struct {
+	// Type is one of ACLExplainSourcePolicy, ACLExplainSourceServiceIdentity,
+	// ACLExplainSourceNodeIdentity or ACLExplainSourcePolicyTemplate.
+	Type string
+
+	// ID and Name identify the policy. For identities, Name is the name of the
+	// service or node.
+	ID   string `json:",omitempty"`
+	Name string
+
+	// RoleID and RoleName are set when the source is linked to the token
+	// through a role.
+	RoleID   string `json:",omitempty"`
+	RoleName string `json:",omitempty"`
+}
+
 func CreateACLAuthorizationResponses(authz acl.Authorizer, requests []ACLAuthorizationRequest) ([]ACLAuthorizationResponse, error) {
 	responses := make([]ACLAuthorizationResponse, len(requests))
 	var ctx acl.AuthorizerContext
@@ -1764,6 +1983,10 @@ func (id *AgentRecoveryTokenIdentity) NodeIdentityList() []*ACLNodeIdentity {
 	return nil
 }
 
+func (id *AgentRecoveryTokenIdentity) PolicyTemplateList() []*ACLPolicyTemplateLink {
+	return nil
+}
+
 func (id *AgentRecoveryTokenIdentity) IsExpired(asOf time.Time) bool {
 	return false
 }
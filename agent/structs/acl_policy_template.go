@@ -0,0 +1,252 @@
+package structs
+
+import (
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/consul/acl"
+)
+
+const (
+	ACLPolicyVariableTypeString = "string"
+	ACLPolicyVariableTypeInt    = "int"
+	ACLPolicyVariableTypeBool   = "bool"
+)
+
+var (
+	validACLPolicyVariableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]{0,63}$`)
+
+	// aclPolicyTemplateReference matches the references to the variables
+	// within the rules of a policy template, like ${team}.
+	aclPolicyTemplateReference = regexp.MustCompile(`\$\{([^}]*)\}`)
+)
+
+// ACLPolicyVariable declares a variable of a policy template.
+type ACLPolicyVariable struct {
+	// Name is referenced as ${Name} within the rules of the template.
+	Name string
+
+	// Type is the type of the values accepted for the variable, one of
+	// "string", "int" or "bool".
+	Type string
+}
+
+func (v *ACLPolicyVariable) validateValue(value string) error {
+	switch v.Type {
+	case ACLPolicyVariableTypeString:
+		// An empty value would widen the rules to match everything, like
+		// service "" or the service_prefix "${team}".
+		if value == "" {
+			return fmt.Errorf("value of variable %q cannot be empty", v.Name)
+		}
+		// The values are interpolated within the quoted strings of the rules
+		// so they must not be able to terminate the string.
+		if strings.ContainsAny(value, "\"\\") || strings.Contains(value, "${") {
+			return fmt.Errorf("value of variable %q cannot contain '\"', '\\' or '${'", v.Name)
+		}
+		for _, r := range value {
+			if r < 0x20 || r == 0x7f {
+				return fmt.Errorf("value of variable %q cannot contain control characters", v.Name)
+			}
+		}
+	case ACLPolicyVariableTypeInt:
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return fmt.Errorf("value of variable %q must be an integer", v.Name)
+		}
+	case ACLPolicyVariableTypeBool:
+		if value != "true" && value != "false" {
+			return fmt.Errorf("value of variable %q must be true or false", v.Name)
+		}
+	default:
+		return fmt.Errorf("variable %q has an invalid type %q", v.Name, v.Type)
+	}
+	return nil
+}
+
+// placeholder returns a valid value for the variable, used to check that the
+// rules of a template parse once rendered.
+func (v *ACLPolicyVariable) placeholder() string {
+	switch v.Type {
+	case ACLPolicyVariableTypeInt:
+		return "0"
+	case ACLPolicyVariableTypeBool:
+		return "false"
+	default:
+		return v.Name
+	}
+}
+
+// ACLPolicyTemplateLink links a token or a role to a policy template and sets
+// the values of the variables of the template.
+type ACLPolicyTemplateLink struct {
+	ID string
+
+	// Template is the name of the policy template.
+	Template string `hash:"ignore"`
+
+	// Vars are the values of the variables of the template by name.
+	Vars map[string]string `json:",omitempty"`
+}
+
+func (l *ACLPolicyTemplateLink) Clone() *ACLPolicyTemplateLink {
+	l2 := *l
+	if l.Vars != nil {
+		l2.Vars = make(map[string]string, len(l.Vars))
+		for k, v := range l.Vars {
+			l2.Vars[k] = v
+		}
+	}
+	return &l2
+}
+
+func (l *ACLPolicyTemplateLink) AddToHash(h hash.Hash) {
+	h.Write([]byte(l.ID))
+	// The values cannot contain control characters so they are terminated by
+	// a NUL byte to keep the hash unambiguous.
+	for _, name := range l.sortedVarNames() {
+		h.Write([]byte(name))
+		h.Write([]byte{0})
+		h.Write([]byte(l.Vars[name]))
+		h.Write([]byte{0})
+	}
+}
+
+func (l *ACLPolicyTemplateLink) EstimateSize() int {
+	size := len(l.ID) + len(l.Template)
+	for k, v := range l.Vars {
+		size += len(k) + len(v)
+	}
+	return size
+}
+
+func (l *ACLPolicyTemplateLink) sortedVarNames() []string {
+	names := make([]string, 0, len(l.Vars))
+	for name := range l.Vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func cloneACLPolicyTemplateLinks(links []*ACLPolicyTemplateLink) []*ACLPolicyTemplateLink {
+	if len(links) == 0 {
+		return nil
+	}
+	out := make([]*ACLPolicyTemplateLink, len(links))
+	for i, link := range links {
+		out[i] = link.Clone()
+	}
+	return out
+}
+
+// IsTemplate returns whether the policy is a template. The rules of a template
+// reference its variables and it only applies through the policy template
+// links of tokens and roles.
+func (p *ACLPolicy) IsTemplate() bool {
+	return len(p.Variables) > 0
+}
+
+// ValidateTemplate checks the variable declarations of a policy template and
+// that its rules only reference declared variables.
+func (p *ACLPolicy) ValidateTemplate() error {
+	declared := make(map[string]struct{}, len(p.Variables))
+	for _, v := range p.Variables {
+		if !validACLPolicyVariableName.MatchString(v.Name) {
+			return fmt.Errorf("invalid variable name %q. Only alphanumeric characters and '_' are allowed and it cannot start with a digit", v.Name)
+		}
+		if _, ok := declared[v.Name]; ok {
+			return fmt.Errorf("variable %q is declared more than once", v.Name)
+		}
+		switch v.Type {
+		case ACLPolicyVariableTypeString, ACLPolicyVariableTypeInt, ACLPolicyVariableTypeBool:
+		default:
+			return fmt.Errorf("variable %q has an invalid type %q. It must be one of %q, %q or %q",
+				v.Name, v.Type, ACLPolicyVariableTypeString, ACLPolicyVariableTypeInt, ACLPolicyVariableTypeBool)
+		}
+		declared[v.Name] = struct{}{}
+	}
+
+	for _, match := range aclPolicyTemplateReference.FindAllStringSubmatch(p.Rules, -1) {
+		if _, ok := declared[match[1]]; !ok {
+			return fmt.Errorf("rules reference the undeclared variable %q", match[1])
+		}
+	}
+	return nil
+}
+
+// ValidateTemplateVars checks that vars sets a valid value for each of the
+// variables of the policy template, and only for those.
+func (p *ACLPolicy) ValidateTemplateVars(vars map[string]string) error {
+	declared := make(map[string]struct{}, len(p.Variables))
+	for _, v := range p.Variables {
+		declared[v.Name] = struct{}{}
+		value, ok := vars[v.Name]
+		if !ok {
+			return fmt.Errorf("missing value for variable %q of policy template %q", v.Name, p.Name)
+		}
+		if err := v.validateValue(value); err != nil {
+			return fmt.Errorf("policy template %q: %v", p.Name, err)
+		}
+	}
+	for name := range vars {
+		if _, ok := declared[name]; !ok {
+			return fmt.Errorf("policy template %q has no variable %q", p.Name, name)
+		}
+	}
+	return nil
+}
+
+// ValidateTemplateRules checks that the rules of the policy template parse
+// once rendered.
+func (p *ACLPolicy) ValidateTemplateRules(conf *acl.Config) error {
+	vars := make(map[string]string, len(p.Variables))
+	for _, v := range p.Variables {
+		vars[v.Name] = v.placeholder()
+	}
+	rendered, err := p.RenderTemplate(vars)
+	if err != nil {
+		return err
+	}
+	_, err = acl.NewPolicyFromSource(rendered.Rules, rendered.Syntax, conf, rendered.EnterprisePolicyMeta())
+	return err
+}
+
+// RenderTemplate returns the synthetic policy obtained by substituting the
+// values of vars for the variables referenced in the rules of the template.
+// The ID of the synthetic policy is derived from its contents so that it is
+// parsed once and cached like the other policies.
+func (p *ACLPolicy) RenderTemplate(vars map[string]string) (*ACLPolicy, error) {
+	if err := p.ValidateTemplateVars(vars); err != nil {
+		return nil, err
+	}
+
+	// The values were validated so they can be interpolated as they are.
+	rules := aclPolicyTemplateReference.ReplaceAllStringFunc(p.Rules, func(ref string) string {
+		return vars[ref[2:len(ref)-1]]
+	})
+
+	hasher := fnv.New128a()
+	hasher.Write([]byte(p.ID))
+	hasher.Write([]byte(rules))
+	for _, dc := range p.Datacenters {
+		hasher.Write([]byte(dc))
+	}
+	hashID := fmt.Sprintf("%x", hasher.Sum(nil))
+
+	policy := &ACLPolicy{}
+	policy.ID = hashID
+	policy.Name = p.Name
+	policy.Description = fmt.Sprintf("synthetic policy rendered from the policy template %q", p.Name)
+	policy.Rules = rules
+	policy.Syntax = acl.SyntaxCurrent
+	policy.Datacenters = CloneStringSlice(p.Datacenters)
+	policy.EnterpriseMeta = p.EnterpriseMeta
+	policy.ModifyIndex = p.ModifyIndex
+	policy.SetHash(true)
+	return policy, nil
+}
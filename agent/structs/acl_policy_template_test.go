@@ -0,0 +1,197 @@
+package structs
+
+import (
+	"testing"
+
+	"github.com/hashicorp/consul/acl"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testACLPolicyTemplate() *ACLPolicy {
+	return &ACLPolicy{
+		ID:   "5a3a9ba3-5b38-4e4f-8b48-0e7ba9c5e5a7",
+		Name: "team",
+		Rules: `
+service_prefix "${team}-" { policy = "write" }
+key_prefix "teams/${team}/" { policy = "read" }
+`,
+		Variables: []*ACLPolicyVariable{
+			{Name: "team", Type: ACLPolicyVariableTypeString},
+		},
+	}
+}
+
+func TestStructs_ACLPolicy_ValidateTemplate(t *testing.T) {
+	type testcase struct {
+		name      string
+		variables []*ACLPolicyVariable
+		rules     string
+		err       string
+	}
+
+	cases := []testcase{
+		{
+			name:      "valid",
+			variables: []*ACLPolicyVariable{{Name: "team", Type: "string"}, {Name: "n", Type: "int"}},
+			rules:     `service "${team}-${n}" { policy = "read" }`,
+		},
+		{
+			name:      "invalid name",
+			variables: []*ACLPolicyVariable{{Name: "1team", Type: "string"}},
+			err:       "invalid variable name",
+		},
+		{
+			name:      "duplicate",
+			variables: []*ACLPolicyVariable{{Name: "team", Type: "string"}, {Name: "team", Type: "int"}},
+			err:       "declared more than once",
+		},
+		{
+			name:      "invalid type",
+			variables: []*ACLPolicyVariable{{Name: "team", Type: "float"}},
+			err:       "invalid type",
+		},
+		{
+			name:      "undeclared reference",
+			variables: []*ACLPolicyVariable{{Name: "team", Type: "string"}},
+			rules:     `service "${other}" { policy = "read" }`,
+			err:       `undeclared variable "other"`,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			p := &ACLPolicy{Name: "tmpl", Rules: tc.rules, Variables: tc.variables}
+			err := p.ValidateTemplate()
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+			}
+		})
+	}
+}
+
+func TestStructs_ACLPolicy_ValidateTemplateVars(t *testing.T) {
+	p := &ACLPolicy{
+		Name: "tmpl",
+		Variables: []*ACLPolicyVariable{
+			{Name: "team", Type: ACLPolicyVariableTypeString},
+			{Name: "count", Type: ACLPolicyVariableTypeInt},
+			{Name: "enabled", Type: ACLPolicyVariableTypeBool},
+		},
+	}
+
+	type testcase struct {
+		name string
+		vars map[string]string
+		err  string
+	}
+
+	cases := []testcase{
+		{
+			name: "valid",
+			vars: map[string]string{"team": "payments", "count": "-3", "enabled": "true"},
+		},
+		{
+			name: "missing",
+			vars: map[string]string{"team": "payments", "count": "3"},
+			err:  `missing value for variable "enabled"`,
+		},
+		{
+			name: "unknown",
+			vars: map[string]string{"team": "payments", "count": "3", "enabled": "true", "other": "x"},
+			err:  `has no variable "other"`,
+		},
+		{
+			name: "bad int",
+			vars: map[string]string{"team": "payments", "count": "three", "enabled": "true"},
+			err:  "must be an integer",
+		},
+		{
+			name: "bad bool",
+			vars: map[string]string{"team": "payments", "count": "3", "enabled": "yes"},
+			err:  "must be true or false",
+		},
+		{
+			name: "empty string",
+			vars: map[string]string{"team": "", "count": "3", "enabled": "true"},
+			err:  `value of variable "team" cannot be empty`,
+		},
+		{
+			name: "string escaping the quotes",
+			vars: map[string]string{"team": `x" { policy = "write" } service "y`, "count": "3", "enabled": "true"},
+			err:  "cannot contain",
+		},
+		{
+			name: "string with a reference",
+			vars: map[string]string{"team": "${count}", "count": "3", "enabled": "true"},
+			err:  "cannot contain",
+		},
+		{
+			name: "string with a newline",
+			vars: map[string]string{"team": "a\nb", "count": "3", "enabled": "true"},
+			err:  "control characters",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := p.ValidateTemplateVars(tc.vars)
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+			}
+		})
+	}
+}
+
+func TestStructs_ACLPolicy_RenderTemplate(t *testing.T) {
+	tmpl := testACLPolicyTemplate()
+	require.NoError(t, tmpl.ValidateTemplate())
+	require.NoError(t, tmpl.ValidateTemplateRules(nil))
+
+	payments, err := tmpl.RenderTemplate(map[string]string{"team": "payments"})
+	require.NoError(t, err)
+	require.False(t, payments.IsTemplate())
+	require.Equal(t, "team", payments.Name)
+	require.NotEqual(t, tmpl.ID, payments.ID)
+	require.Equal(t, `
+service_prefix "payments-" { policy = "write" }
+key_prefix "teams/payments/" { policy = "read" }
+`, payments.Rules)
+
+	// Rendering the same values is stable so the compiled policy is cached.
+	again, err := tmpl.RenderTemplate(map[string]string{"team": "payments"})
+	require.NoError(t, err)
+	require.Equal(t, payments.ID, again.ID)
+	require.Equal(t, payments.Hash, again.Hash)
+
+	billing, err := tmpl.RenderTemplate(map[string]string{"team": "billing"})
+	require.NoError(t, err)
+	require.NotEqual(t, payments.ID, billing.ID)
+
+	policy, err := acl.NewPolicyFromSource(payments.Rules, payments.Syntax, nil, nil)
+	require.NoError(t, err)
+	authz, err := acl.NewPolicyAuthorizerWithDefaults(acl.DenyAll(), []*acl.Policy{policy}, nil)
+	require.NoError(t, err)
+	require.Equal(t, acl.Allow, authz.ServiceWrite("payments-api", nil))
+	require.Equal(t, acl.Deny, authz.ServiceWrite("billing-api", nil))
+	require.Equal(t, acl.Allow, authz.KeyRead("teams/payments/x", nil))
+
+	_, err = tmpl.RenderTemplate(map[string]string{})
+	require.Error(t, err)
+}
+
+func TestStructs_ACLPolicyTemplateLink_AddToHash(t *testing.T) {
+	token1 := &ACLToken{PolicyTemplates: []*ACLPolicyTemplateLink{{ID: "a", Vars: map[string]string{"a": "b", "b": ""}}}}
+	token2 := &ACLToken{PolicyTemplates: []*ACLPolicyTemplateLink{{ID: "a", Vars: map[string]string{"a": "", "b": "b"}}}}
+	token1.SetHash(true)
+	token2.SetHash(true)
+	require.NotEqual(t, token1.Hash, token2.Hash)
+}
@@ -34,13 +34,14 @@ type AutopilotConfig struct {
 	// servers into zones for redundancy. If left blank, this feature will be disabled.
 	RedundancyZoneTag string
 
-	// (Enterprise-only) DisableUpgradeMigration will disable Autopilot's upgrade migration
+	// DisableUpgradeMigration will disable Autopilot's upgrade migration
 	// strategy of waiting until enough newer-versioned servers have been added to the
 	// cluster before promoting them to voters.
 	DisableUpgradeMigration bool
 
-	// (Enterprise-only) UpgradeVersionTag is the node tag to use for version info when
-	// performing upgrade migrations. If left blank, the Consul version will be used.
+	// UpgradeVersionTag is the node tag to use for version info when
+	// performing upgrade migrations. If left blank, upgrade migrations are
+	// disabled.
 	UpgradeVersionTag string
 
 	// CreateIndex/ModifyIndex store the create/modify indexes of this configuration.
@@ -62,6 +63,83 @@ func (c *AutopilotConfig) ToAutopilotLibraryConfig() *autopilot.Config {
 	}
 }
 
+// AutopilotUpgradeConfig holds the settings of upgrade migrations. It is the
+// Ext of the autopilot library config given to the promoter.
+type AutopilotUpgradeConfig struct {
+	// DisableUpgradeMigration disables upgrade migrations, new servers are
+	// then promoted as soon as they are stable whatever their version.
+	DisableUpgradeMigration bool
+
+	// UpgradeVersionTag is the node meta tag holding the upgrade version of
+	// each server. If left blank, upgrade migrations are disabled and the
+	// Consul version is only reported.
+	UpgradeVersionTag string
+}
+
+// AutopilotServerExt is the promoter specific state of a server.
+type AutopilotServerExt struct {
+	// ReadReplica is whether the server is a read replica, which is never
+	// promoted to a voter.
+	ReadReplica bool
+
+	// UpgradeVersion is the version of the server used by upgrade
+	// migrations.
+	UpgradeVersion string
+
+	// ReplicationLag is the number of logs committed by the leader that the
+	// server has yet to apply.
+	ReplicationLag uint64
+}
+
+// AutopilotStateExt is the promoter specific state of the cluster.
+type AutopilotStateExt struct {
+	Upgrade *AutopilotUpgrade
+}
+
+type AutopilotUpgradeStatus string
+
+const (
+	// AutopilotUpgradeIdle is the status when no upgrade is in progress.
+	AutopilotUpgradeIdle AutopilotUpgradeStatus = "idle"
+
+	// AutopilotUpgradeAwaitNewVoters is the status when more servers of the
+	// target version must be added before they can be promoted.
+	AutopilotUpgradeAwaitNewVoters AutopilotUpgradeStatus = "await-new-voters"
+
+	// AutopilotUpgradePromoting is the status when the servers of the target
+	// version are being promoted.
+	AutopilotUpgradePromoting AutopilotUpgradeStatus = "promoting"
+
+	// AutopilotUpgradeDemoting is the status when the voters of other versions
+	// are being demoted.
+	AutopilotUpgradeDemoting AutopilotUpgradeStatus = "demoting"
+
+	// AutopilotUpgradeLeaderTransfer is the status when leadership is being
+	// transferred to a server of the target version.
+	AutopilotUpgradeLeaderTransfer AutopilotUpgradeStatus = "leader-transfer"
+
+	// AutopilotUpgradeAwaitServerRemoval is the status when only servers of the
+	// target version are voters and the servers of other versions can be
+	// removed.
+	AutopilotUpgradeAwaitServerRemoval AutopilotUpgradeStatus = "await-server-removal"
+
+	// AutopilotUpgradeDisabled is the status when upgrade migrations are
+	// disabled in the autopilot configuration.
+	AutopilotUpgradeDisabled AutopilotUpgradeStatus = "disabled"
+)
+
+// AutopilotUpgrade is the state of an upgrade migration.
+type AutopilotUpgrade struct {
+	Status                    AutopilotUpgradeStatus
+	TargetVersion             string
+	TargetVersionVoters       []string
+	TargetVersionNonVoters    []string
+	TargetVersionReadReplicas []string
+	OtherVersionVoters        []string
+	OtherVersionNonVoters     []string
+	OtherVersionReadReplicas  []string
+}
+
 // AutopilotHealthReply is a representation of the overall health of the cluster
 type AutopilotHealthReply struct {
 	// Healthy is true if all the servers in the cluster are healthy.
@@ -125,6 +203,14 @@ type RaftStats struct {
 
 	// LastIndex is the last log index this server has a record of in its Raft log.
 	LastIndex uint64
+
+	// CommitIndex is the index of the last log this server knows to be
+	// committed.
+	CommitIndex uint64
+
+	// AppliedIndex is the index of the last log applied to the FSM of this
+	// server.
+	AppliedIndex uint64
 }
 
 func (s *RaftStats) ToAutopilotServerStats() *autopilot.ServerStats {
@@ -4,5 +4,8 @@
 package structs
 
 func (c *AutopilotConfig) autopilotConfigExt() interface{} {
-	return nil
+	return &AutopilotUpgradeConfig{
+		DisableUpgradeMigration: c.DisableUpgradeMigration,
+		UpgradeVersionTag:       c.UpgradeVersionTag,
+	}
 }
@@ -28,6 +28,8 @@ const (
 	ServiceIntentions  string = "service-intentions"
 	MeshConfig         string = "mesh"
 	ExportedServices   string = "exported-services"
+	JWTProvider        string = "jwt-provider"
+	Canary             string = "canary"
 
 	ProxyConfigGlobal string = "global"
 	MeshConfigMesh    string = "mesh"
@@ -46,6 +48,8 @@ var AllConfigEntryKinds = []string{
 	ServiceIntentions,
 	MeshConfig,
 	ExportedServices,
+	JWTProvider,
+	Canary,
 }
 
 // ConfigEntry is the interface for centralized configuration stored in Raft.
@@ -93,6 +97,7 @@ type ServiceConfigEntry struct {
 	Expose           ExposeConfig           `json:",omitempty"`
 	ExternalSNI      string                 `json:",omitempty" alias:"external_sni"`
 	UpstreamConfig   *UpstreamConfiguration `json:",omitempty" alias:"upstream_config"`
+	AccessLogs       AccessLogsConfig       `json:",omitempty" alias:"access_logs"`
 
 	Meta           map[string]string `json:",omitempty"`
 	EnterpriseMeta `hcl:",squash" mapstructure:",squash"`
@@ -180,6 +185,10 @@ func (e *ServiceConfigEntry) Validate() error {
 		}
 	}
 
+	if err := e.AccessLogs.Validate(); err != nil {
+		validationErr = multierror.Append(validationErr, fmt.Errorf("error in access logs: %v", err))
+	}
+
 	return validationErr
 }
 
@@ -252,6 +261,7 @@ type ProxyConfigEntry struct {
 	TransparentProxy TransparentProxyConfig `json:",omitempty" alias:"transparent_proxy"`
 	MeshGateway      MeshGatewayConfig      `json:",omitempty" alias:"mesh_gateway"`
 	Expose           ExposeConfig           `json:",omitempty"`
+	AccessLogs       AccessLogsConfig       `json:",omitempty" alias:"access_logs"`
 
 	Meta           map[string]string `json:",omitempty"`
 	EnterpriseMeta `hcl:",squash" mapstructure:",squash"`
@@ -303,6 +313,10 @@ func (e *ProxyConfigEntry) Validate() error {
 		return err
 	}
 
+	if err := e.AccessLogs.Validate(); err != nil {
+		return fmt.Errorf("AccessLogs %v", err)
+	}
+
 	return e.validateEnterpriseMeta()
 }
 
@@ -535,6 +549,10 @@ func MakeConfigEntry(kind, name string) (ConfigEntry, error) {
 		return &MeshConfigEntry{}, nil
 	case ExportedServices:
 		return &ExportedServicesConfigEntry{Name: name}, nil
+	case JWTProvider:
+		return &JWTProviderConfigEntry{Name: name}, nil
+	case Canary:
+		return &CanaryConfigEntry{Name: name}, nil
 	default:
 		return nil, fmt.Errorf("invalid config entry kind: %s", kind)
 	}
@@ -985,6 +1003,7 @@ type ServiceConfigResponse struct {
 	Expose            ExposeConfig           `json:",omitempty"`
 	TransparentProxy  TransparentProxyConfig `json:",omitempty"`
 	Mode              ProxyMode              `json:",omitempty"`
+	AccessLogs        AccessLogsConfig       `json:",omitempty"`
 	QueryMeta
 }
 
@@ -0,0 +1,278 @@
+package structs
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/lib"
+)
+
+// CanaryConfigEntry describes a progressive rollout of a new version of a
+// service. The canary controller running on the leader shifts traffic from
+// the stable subset to the canary subset one step at a time by managing the
+// service-splitter config entry of the service, and rolls the canary back
+// if any of its instances become unhealthy. The service-splitter is kept
+// once the rollout is promoted or rolled back, and deleted along with the
+// canary.
+type CanaryConfigEntry struct {
+	// Name is the name of the service being rolled out.
+	Name string
+
+	// StableSubset is the service-resolver subset currently serving traffic.
+	StableSubset string `alias:"stable_subset"`
+
+	// CanarySubset is the service-resolver subset traffic is shifted to.
+	CanarySubset string `alias:"canary_subset"`
+
+	// Steps are the percentages of traffic sent to the canary subset at each
+	// step of the rollout. They must be increasing and the last step must be
+	// 100, at which point the canary is promoted.
+	Steps []float32
+
+	// StepInterval is how long each step must remain healthy before the
+	// rollout advances to the next step.
+	StepInterval time.Duration `alias:"step_interval"`
+
+	// HealthChecks restricts health gating to the checks with these IDs or
+	// names on the canary instances. All checks are considered when empty.
+	HealthChecks []string `json:",omitempty" alias:"health_checks"`
+
+	// Status is the progress of the rollout. It is managed by the canary
+	// controller and any value provided when writing the entry is ignored.
+	Status CanaryStatus
+
+	Meta           map[string]string `json:",omitempty"`
+	EnterpriseMeta `hcl:",squash" mapstructure:",squash"`
+	RaftIndex
+}
+
+type CanaryState string
+
+const (
+	// CanaryStateProgressing means traffic is being shifted to the canary.
+	CanaryStateProgressing CanaryState = "progressing"
+
+	// CanaryStatePromoted means the canary receives all traffic.
+	CanaryStatePromoted CanaryState = "promoted"
+
+	// CanaryStateRolledBack means the canary was unhealthy and all traffic
+	// was returned to the stable subset.
+	CanaryStateRolledBack CanaryState = "rolled-back"
+)
+
+// CanaryStatus records the progress of a rollout.
+type CanaryStatus struct {
+	// State is empty until the controller starts the rollout.
+	State CanaryState `json:",omitempty"`
+
+	// Step is the index of the current step in Steps.
+	Step int
+
+	// CanaryWeight is the percentage of traffic currently sent to the
+	// canary subset.
+	CanaryWeight float32
+
+	// Reason describes the last transition.
+	Reason string `json:",omitempty"`
+
+	// LastTransitionTime is when the rollout last changed step or state.
+	LastTransitionTime time.Time `json:",omitempty"`
+}
+
+func (e *CanaryConfigEntry) GetKind() string {
+	return Canary
+}
+
+func (e *CanaryConfigEntry) GetName() string {
+	if e == nil {
+		return ""
+	}
+
+	return e.Name
+}
+
+func (e *CanaryConfigEntry) GetMeta() map[string]string {
+	if e == nil {
+		return nil
+	}
+	return e.Meta
+}
+
+func (e *CanaryConfigEntry) Normalize() error {
+	if e == nil {
+		return fmt.Errorf("config entry is nil")
+	}
+
+	e.EnterpriseMeta.Normalize()
+
+	for i, weight := range e.Steps {
+		e.Steps[i] = NormalizeServiceSplitWeight(weight)
+	}
+	return nil
+}
+
+func (e *CanaryConfigEntry) Validate() error {
+	if e == nil {
+		return fmt.Errorf("config entry is nil")
+	}
+	if e.Name == "" {
+		return fmt.Errorf("Name is required")
+	}
+	if err := validateConfigEntryMeta(e.Meta); err != nil {
+		return err
+	}
+
+	if e.StableSubset == "" {
+		return fmt.Errorf("StableSubset is required")
+	}
+	if e.CanarySubset == "" {
+		return fmt.Errorf("CanarySubset is required")
+	}
+	if e.StableSubset == e.CanarySubset {
+		return fmt.Errorf("StableSubset and CanarySubset must be different")
+	}
+
+	if len(e.Steps) == 0 {
+		return fmt.Errorf("Steps must contain at least one step")
+	}
+	var prev float32
+	for i, weight := range e.Steps {
+		if weight <= prev || weight > 100 {
+			return fmt.Errorf("Steps[%d] must be greater than the previous step and at most 100", i)
+		}
+		prev = weight
+	}
+	if prev != 100 {
+		return fmt.Errorf("the last step must be 100, not %f", prev)
+	}
+
+	if e.StepInterval <= 0 {
+		return fmt.Errorf("StepInterval must be greater than 0")
+	}
+
+	for i, check := range e.HealthChecks {
+		if check == "" {
+			return fmt.Errorf("HealthChecks[%d] must not be empty", i)
+		}
+	}
+
+	return e.validateEnterpriseMeta()
+}
+
+// SameRollout reports whether both entries describe the same rollout,
+// ignoring the status and metadata. Changing the rollout restarts it.
+func (e *CanaryConfigEntry) SameRollout(other *CanaryConfigEntry) bool {
+	if e == nil || other == nil {
+		return e == other
+	}
+	if e.StableSubset != other.StableSubset ||
+		e.CanarySubset != other.CanarySubset ||
+		e.StepInterval != other.StepInterval ||
+		len(e.Steps) != len(other.Steps) ||
+		len(e.HealthChecks) != len(other.HealthChecks) {
+		return false
+	}
+	for i := range e.Steps {
+		if e.Steps[i] != other.Steps[i] {
+			return false
+		}
+	}
+	for i := range e.HealthChecks {
+		if e.HealthChecks[i] != other.HealthChecks[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Splitter returns the service-splitter config entry that sends the given
+// percentage of traffic to the canary subset and the rest to the stable
+// subset.
+func (e *CanaryConfigEntry) Splitter(canaryWeight float32) *ServiceSplitterConfigEntry {
+	return &ServiceSplitterConfigEntry{
+		Kind: ServiceSplitter,
+		Name: e.Name,
+		Splits: []ServiceSplit{
+			{Weight: 100 - canaryWeight, ServiceSubset: e.StableSubset},
+			{Weight: canaryWeight, ServiceSubset: e.CanarySubset},
+		},
+		Meta: map[string]string{
+			CanaryManagedByMetaKey: Canary,
+		},
+		EnterpriseMeta: e.EnterpriseMeta,
+	}
+}
+
+// CanaryManagedByMetaKey is set on the service-splitter config entries
+// written by the canary controller. Other service-splitters are never
+// overwritten by the controller.
+const CanaryManagedByMetaKey = "managed-by"
+
+func (e *CanaryConfigEntry) CanRead(authz acl.Authorizer) bool {
+	var authzContext acl.AuthorizerContext
+	e.FillAuthzContext(&authzContext)
+	return authz.ServiceRead(e.Name, &authzContext) == acl.Allow
+}
+
+func (e *CanaryConfigEntry) CanWrite(authz acl.Authorizer) bool {
+	var authzContext acl.AuthorizerContext
+	e.FillAuthzContext(&authzContext)
+	return authz.ServiceWrite(e.Name, &authzContext) == acl.Allow
+}
+
+func (e *CanaryConfigEntry) GetRaftIndex() *RaftIndex {
+	if e == nil {
+		return &RaftIndex{}
+	}
+
+	return &e.RaftIndex
+}
+
+func (e *CanaryConfigEntry) GetEnterpriseMeta() *EnterpriseMeta {
+	if e == nil {
+		return nil
+	}
+
+	return &e.EnterpriseMeta
+}
+
+// MarshalJSON adds the Kind field so that the JSON can be decoded back into the
+// correct type.
+func (e *CanaryConfigEntry) MarshalJSON() ([]byte, error) {
+	type Alias CanaryConfigEntry
+	source := &struct {
+		Kind         string
+		StepInterval string `json:",omitempty"`
+		*Alias
+	}{
+		Kind:         Canary,
+		StepInterval: e.StepInterval.String(),
+		Alias:        (*Alias)(e),
+	}
+	if e.StepInterval == 0 {
+		source.StepInterval = ""
+	}
+	return json.Marshal(source)
+}
+
+func (e *CanaryConfigEntry) UnmarshalJSON(data []byte) error {
+	type Alias CanaryConfigEntry
+	aux := &struct {
+		StepInterval string
+		*Alias
+	}{
+		Alias: (*Alias)(e),
+	}
+	if err := lib.UnmarshalJSON(data, &aux); err != nil {
+		return err
+	}
+	var err error
+	if aux.StepInterval != "" {
+		if e.StepInterval, err = time.ParseDuration(aux.StepInterval); err != nil {
+			return err
+		}
+	}
+	return nil
+}
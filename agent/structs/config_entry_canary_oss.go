@@ -0,0 +1,8 @@
+//go:build !consulent
+// +build !consulent
+
+package structs
+
+func (e *CanaryConfigEntry) validateEnterpriseMeta() error {
+	return nil
+}
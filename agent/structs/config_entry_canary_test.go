@@ -0,0 +1,153 @@
+package structs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanaryConfigEntry(t *testing.T) {
+	cases := map[string]configEntryTestcase{
+		"valid": {
+			entry: &CanaryConfigEntry{
+				Name:         "web",
+				StableSubset: "v1",
+				CanarySubset: "v2",
+				Steps:        []float32{10, 50, 100},
+				StepInterval: time.Minute,
+				HealthChecks: []string{"service:web-v2"},
+			},
+			expectUnchanged: true,
+		},
+		"step weights are normalized": {
+			entry: &CanaryConfigEntry{
+				Name:         "web",
+				StableSubset: "v1",
+				CanarySubset: "v2",
+				Steps:        []float32{33.33333, 100},
+				StepInterval: time.Minute,
+			},
+			expected: &CanaryConfigEntry{
+				Name:         "web",
+				StableSubset: "v1",
+				CanarySubset: "v2",
+				Steps:        []float32{33.33, 100},
+				StepInterval: time.Minute,
+			},
+		},
+		"missing name": {
+			entry: &CanaryConfigEntry{
+				StableSubset: "v1",
+				CanarySubset: "v2",
+				Steps:        []float32{100},
+				StepInterval: time.Minute,
+			},
+			validateErr: "Name is required",
+		},
+		"missing stable subset": {
+			entry: &CanaryConfigEntry{
+				Name:         "web",
+				CanarySubset: "v2",
+				Steps:        []float32{100},
+				StepInterval: time.Minute,
+			},
+			validateErr: "StableSubset is required",
+		},
+		"missing canary subset": {
+			entry: &CanaryConfigEntry{
+				Name:         "web",
+				StableSubset: "v1",
+				Steps:        []float32{100},
+				StepInterval: time.Minute,
+			},
+			validateErr: "CanarySubset is required",
+		},
+		"same subsets": {
+			entry: &CanaryConfigEntry{
+				Name:         "web",
+				StableSubset: "v1",
+				CanarySubset: "v1",
+				Steps:        []float32{100},
+				StepInterval: time.Minute,
+			},
+			validateErr: "StableSubset and CanarySubset must be different",
+		},
+		"no steps": {
+			entry: &CanaryConfigEntry{
+				Name:         "web",
+				StableSubset: "v1",
+				CanarySubset: "v2",
+				StepInterval: time.Minute,
+			},
+			validateErr: "Steps must contain at least one step",
+		},
+		"decreasing steps": {
+			entry: &CanaryConfigEntry{
+				Name:         "web",
+				StableSubset: "v1",
+				CanarySubset: "v2",
+				Steps:        []float32{50, 10, 100},
+				StepInterval: time.Minute,
+			},
+			validateErr: "Steps[1] must be greater than the previous step and at most 100",
+		},
+		"last step not 100": {
+			entry: &CanaryConfigEntry{
+				Name:         "web",
+				StableSubset: "v1",
+				CanarySubset: "v2",
+				Steps:        []float32{10, 50},
+				StepInterval: time.Minute,
+			},
+			validateErr: "the last step must be 100",
+		},
+		"missing step interval": {
+			entry: &CanaryConfigEntry{
+				Name:         "web",
+				StableSubset: "v1",
+				CanarySubset: "v2",
+				Steps:        []float32{100},
+			},
+			validateErr: "StepInterval must be greater than 0",
+		},
+		"empty health check": {
+			entry: &CanaryConfigEntry{
+				Name:         "web",
+				StableSubset: "v1",
+				CanarySubset: "v2",
+				Steps:        []float32{100},
+				StepInterval: time.Minute,
+				HealthChecks: []string{""},
+			},
+			validateErr: "HealthChecks[0] must not be empty",
+		},
+	}
+
+	testConfigEntryNormalizeAndValidate(t, cases)
+}
+
+func TestCanaryConfigEntry_SameRollout(t *testing.T) {
+	base := func() *CanaryConfigEntry {
+		return &CanaryConfigEntry{
+			Name:         "web",
+			StableSubset: "v1",
+			CanarySubset: "v2",
+			Steps:        []float32{10, 100},
+			StepInterval: time.Minute,
+		}
+	}
+
+	other := base()
+	other.Status = CanaryStatus{State: CanaryStateProgressing}
+	other.Meta = map[string]string{"owner": "team"}
+	require.True(t, base().SameRollout(other))
+
+	other = base()
+	other.Steps = []float32{20, 100}
+	require.False(t, base().SameRollout(other))
+
+	other = base()
+	other.HealthChecks = []string{"service:web"}
+	require.False(t, base().SameRollout(other))
+}
@@ -107,13 +107,14 @@ func (e *ServiceRouterConfigEntry) Normalize() error {
 	e.EnterpriseMeta.Normalize()
 
 	for _, route := range e.Routes {
-		if route.Match == nil || route.Match.HTTP == nil {
+		if route.Match == nil || (route.Match.HTTP == nil && route.Match.GRPC == nil) {
 			continue
 		}
 
-		httpMatch := route.Match.HTTP
-		for j := 0; j < len(httpMatch.Methods); j++ {
-			httpMatch.Methods[j] = strings.ToUpper(httpMatch.Methods[j])
+		if httpMatch := route.Match.HTTP; httpMatch != nil {
+			for j := 0; j < len(httpMatch.Methods); j++ {
+				httpMatch.Methods[j] = strings.ToUpper(httpMatch.Methods[j])
+			}
 		}
 
 		if route.Destination != nil && route.Destination.Namespace == "" {
@@ -122,6 +123,9 @@ func (e *ServiceRouterConfigEntry) Normalize() error {
 		if route.Destination != nil && route.Destination.Partition == "" {
 			route.Destination.Partition = e.EnterpriseMeta.PartitionOrEmpty()
 		}
+		if route.Destination != nil {
+			route.Destination.Mirror.normalize(&e.EnterpriseMeta)
+		}
 	}
 
 	return nil
@@ -142,6 +146,14 @@ func (e *ServiceRouterConfigEntry) Validate() error {
 
 	for i, route := range e.Routes {
 		eligibleForPrefixRewrite := false
+		if route.Match != nil && route.Match.HTTP != nil && route.Match.GRPC != nil {
+			return fmt.Errorf("Route[%d] cannot specify both HTTP and GRPC match criteria", i)
+		}
+		if route.Match != nil && route.Match.GRPC != nil {
+			if err := route.Match.GRPC.validate(); err != nil {
+				return fmt.Errorf("Route[%d] GRPC %v", i, err)
+			}
+		}
 		if route.Match != nil && route.Match.HTTP != nil {
 			pathParts := 0
 			if route.Match.HTTP.PathExact != "" {
@@ -166,27 +178,8 @@ func (e *ServiceRouterConfigEntry) Validate() error {
 			}
 
 			for j, hdr := range route.Match.HTTP.Header {
-				if hdr.Name == "" {
-					return fmt.Errorf("Route[%d] Header[%d] missing required Name field", i, j)
-				}
-				hdrParts := 0
-				if hdr.Present {
-					hdrParts++
-				}
-				if hdr.Exact != "" {
-					hdrParts++
-				}
-				if hdr.Regex != "" {
-					hdrParts++
-				}
-				if hdr.Prefix != "" {
-					hdrParts++
-				}
-				if hdr.Suffix != "" {
-					hdrParts++
-				}
-				if hdrParts != 1 {
-					return fmt.Errorf("Route[%d] Header[%d] should only contain one of Present, Exact, Prefix, Suffix, or Regex", i, j)
+				if err := hdr.validate(); err != nil {
+					return fmt.Errorf("Route[%d] Header[%d] %v", i, j, err)
 				}
 			}
 
@@ -228,6 +221,18 @@ func (e *ServiceRouterConfigEntry) Validate() error {
 			if route.Destination.PrefixRewrite != "" && !eligibleForPrefixRewrite {
 				return fmt.Errorf("Route[%d] cannot make use of PrefixRewrite without configuring either PathExact or PathPrefix", i)
 			}
+
+			if route.Destination.Fault != nil {
+				if err := route.Destination.Fault.validate(); err != nil {
+					return fmt.Errorf("Route[%d] Fault %v", i, err)
+				}
+			}
+
+			if route.Destination.Mirror != nil {
+				if err := route.Destination.Mirror.validate(); err != nil {
+					return fmt.Errorf("Route[%d] Mirror %v", i, err)
+				}
+			}
 		}
 	}
 
@@ -280,6 +285,13 @@ func (e *ServiceRouterConfigEntry) ListRelatedServices() []ServiceID {
 			if destID != svcID {
 				found[destID] = struct{}{}
 			}
+
+			if mirror := route.Destination.Mirror; mirror != nil {
+				mirrorID := NewServiceID(defaultIfEmpty(mirror.Service, e.Name), mirror.GetEnterpriseMeta(&e.EnterpriseMeta))
+				if mirrorID != svcID {
+					found[mirrorID] = struct{}{}
+				}
+			}
 		}
 	}
 
@@ -317,12 +329,17 @@ type ServiceRoute struct {
 type ServiceRouteMatch struct {
 	HTTP *ServiceRouteHTTPMatch `json:",omitempty"`
 
+	// GRPC is a set of gRPC-specific match criteria. It may only be used
+	// when the service protocol is "grpc".
+	GRPC *ServiceRouteGRPCMatch `json:",omitempty"`
+
 	// If we have non-http match criteria for other protocols in the future
-	// (gRPC, redis, etc) they can go here.
+	// (redis, etc) they can go here.
 }
 
 func (m *ServiceRouteMatch) IsEmpty() bool {
-	return m.HTTP == nil || m.HTTP.IsEmpty()
+	return (m.HTTP == nil || m.HTTP.IsEmpty()) &&
+		(m.GRPC == nil || m.GRPC.IsEmpty())
 }
 
 // ServiceRouteHTTPMatch is a set of http-specific match criteria.
@@ -355,6 +372,71 @@ type ServiceRouteHTTPMatchHeader struct {
 	Invert  bool   `json:",omitempty"`
 }
 
+func (h ServiceRouteHTTPMatchHeader) validate() error {
+	if h.Name == "" {
+		return fmt.Errorf("missing required Name field")
+	}
+	hdrParts := 0
+	if h.Present {
+		hdrParts++
+	}
+	if h.Exact != "" {
+		hdrParts++
+	}
+	if h.Regex != "" {
+		hdrParts++
+	}
+	if h.Prefix != "" {
+		hdrParts++
+	}
+	if h.Suffix != "" {
+		hdrParts++
+	}
+	if hdrParts != 1 {
+		return fmt.Errorf("should only contain one of Present, Exact, Prefix, Suffix, or Regex")
+	}
+	return nil
+}
+
+// ServiceRouteGRPCMatch is a set of gRPC-specific match criteria.
+type ServiceRouteGRPCMatch struct {
+	// Service is the fully qualified name of the gRPC service to match, such
+	// as "pkg.Service". If empty, requests to any service are matched.
+	Service string `json:",omitempty"`
+
+	// Method is the name of the gRPC method to match. It requires Service to
+	// also be set. If empty, requests to any method of Service are matched.
+	Method string `json:",omitempty"`
+
+	// Metadata is a set of matchers for the gRPC request metadata, which is
+	// carried in the HTTP/2 request headers.
+	Metadata []ServiceRouteHTTPMatchHeader `json:",omitempty"`
+}
+
+func (m *ServiceRouteGRPCMatch) IsEmpty() bool {
+	return m.Service == "" &&
+		m.Method == "" &&
+		len(m.Metadata) == 0
+}
+
+func (m *ServiceRouteGRPCMatch) validate() error {
+	if strings.Contains(m.Service, "/") {
+		return fmt.Errorf("Service %q must not contain '/'", m.Service)
+	}
+	if strings.Contains(m.Method, "/") {
+		return fmt.Errorf("Method %q must not contain '/'", m.Method)
+	}
+	if m.Method != "" && m.Service == "" {
+		return fmt.Errorf("Method requires Service to be set")
+	}
+	for j, md := range m.Metadata {
+		if err := md.validate(); err != nil {
+			return fmt.Errorf("Metadata[%d] %v", j, err)
+		}
+	}
+	return nil
+}
+
 type ServiceRouteHTTPMatchQueryParam struct {
 	Name    string
 	Present bool   `json:",omitempty"`
@@ -416,6 +498,15 @@ type ServiceRouteDestination struct {
 	// Allow HTTP header manipulation to be configured.
 	RequestHeaders  *HTTPHeaderModifiers `json:",omitempty" alias:"request_headers"`
 	ResponseHeaders *HTTPHeaderModifiers `json:",omitempty" alias:"response_headers"`
+
+	// Fault configures failures that are deliberately injected into requests
+	// matching this route, for testing how downstreams cope with a degraded
+	// destination.
+	Fault *ServiceRouteFault `json:",omitempty"`
+
+	// Mirror sends a copy of a share of the requests matching this route to
+	// another service. Responses to mirrored requests are discarded.
+	Mirror *RequestMirror `json:",omitempty"`
 }
 
 func (e *ServiceRouteDestination) MarshalJSON() ([]byte, error) {
@@ -458,6 +549,184 @@ func (d *ServiceRouteDestination) HasRetryFeatures() bool {
 	return d.NumRetries > 0 || d.RetryOnConnectFailure || len(d.RetryOnStatusCodes) > 0
 }
 
+// ServiceRouteFault describes the faults to inject into requests matching a
+// route. At least one of Delay or Abort must be set.
+type ServiceRouteFault struct {
+	// Delay holds the request for a fixed amount of time before it is
+	// forwarded to the destination.
+	Delay *ServiceRouteFaultDelay `json:",omitempty"`
+
+	// Abort terminates the request with the configured status instead of
+	// forwarding it to the destination.
+	Abort *ServiceRouteFaultAbort `json:",omitempty"`
+
+	// Header restricts fault injection to requests that match all of the
+	// given header criteria. If empty, every request matching the route is
+	// eligible.
+	Header []ServiceRouteHTTPMatchHeader `json:",omitempty"`
+}
+
+func (f *ServiceRouteFault) validate() error {
+	if f.Delay == nil && f.Abort == nil {
+		return fmt.Errorf("must configure at least one of Delay or Abort")
+	}
+
+	if f.Delay != nil {
+		if f.Delay.Duration <= 0 {
+			return fmt.Errorf("Delay.Duration must be greater than zero")
+		}
+		if !isValidFaultPercentage(f.Delay.Percentage) {
+			return fmt.Errorf("Delay.Percentage must be between 0 and 100: %v", f.Delay.Percentage)
+		}
+	}
+
+	if f.Abort != nil {
+		switch {
+		case f.Abort.GRPCStatus != 0:
+			// The version of go-control-plane we use predates the grpc_status
+			// field of the fault filter, so there is no way to configure it.
+			return fmt.Errorf("Abort.GRPCStatus is not supported yet, use HTTPStatus instead")
+		case f.Abort.HTTPStatus != 0:
+			if f.Abort.HTTPStatus < 200 || f.Abort.HTTPStatus > 599 {
+				return fmt.Errorf("Abort.HTTPStatus must be between 200 and 599: %d", f.Abort.HTTPStatus)
+			}
+		default:
+			return fmt.Errorf("Abort must contain HTTPStatus")
+		}
+		if !isValidFaultPercentage(f.Abort.Percentage) {
+			return fmt.Errorf("Abort.Percentage must be between 0 and 100: %v", f.Abort.Percentage)
+		}
+	}
+
+	for j, hdr := range f.Header {
+		if err := hdr.validate(); err != nil {
+			return fmt.Errorf("Header[%d] %v", j, err)
+		}
+	}
+
+	return nil
+}
+
+func isValidFaultPercentage(pct float32) bool {
+	return pct >= 0 && pct <= 100
+}
+
+// ServiceRouteFaultDelay injects a fixed delay into matching requests.
+type ServiceRouteFaultDelay struct {
+	// Duration is how long to hold the request before forwarding it.
+	Duration time.Duration `json:",omitempty"`
+
+	// Percentage is the share of eligible requests, from 0 to 100, that are
+	// delayed. If zero, every eligible request is delayed.
+	Percentage float32 `json:",omitempty"`
+}
+
+func (d *ServiceRouteFaultDelay) MarshalJSON() ([]byte, error) {
+	type Alias ServiceRouteFaultDelay
+	exported := &struct {
+		Duration string `json:",omitempty"`
+		*Alias
+	}{
+		Duration: d.Duration.String(),
+		Alias:    (*Alias)(d),
+	}
+	if d.Duration == 0 {
+		exported.Duration = ""
+	}
+
+	return json.Marshal(exported)
+}
+
+func (d *ServiceRouteFaultDelay) UnmarshalJSON(data []byte) error {
+	type Alias ServiceRouteFaultDelay
+	aux := &struct {
+		Duration string
+		*Alias
+	}{
+		Alias: (*Alias)(d),
+	}
+	if err := lib.UnmarshalJSON(data, &aux); err != nil {
+		return err
+	}
+	var err error
+	if aux.Duration != "" {
+		if d.Duration, err = time.ParseDuration(aux.Duration); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ServiceRouteFaultAbort short-circuits matching requests with an error.
+type ServiceRouteFaultAbort struct {
+	// HTTPStatus is the HTTP status code returned to the caller. It is
+	// required.
+	HTTPStatus uint32 `json:",omitempty" alias:"http_status"`
+
+	// GRPCStatus is reserved for aborting gRPC requests with a gRPC status
+	// code. It is rejected until the Envoy API we build against supports it.
+	GRPCStatus uint32 `json:",omitempty" alias:"grpc_status"`
+
+	// Percentage is the share of eligible requests, from 0 to 100, that are
+	// aborted. If zero, every eligible request is aborted.
+	Percentage float32 `json:",omitempty"`
+}
+
+// RequestMirror describes a shadow destination that receives a copy of the
+// requests sent to a route or split. The original request is unaffected and
+// any response from the shadow destination is discarded, which makes it
+// suitable for exercising a new version of a service with live traffic.
+type RequestMirror struct {
+	// Service is the service to mirror requests to. If empty the service that
+	// owns the router or splitter is used.
+	Service string `json:",omitempty"`
+
+	// ServiceSubset is a named subset of the given service to mirror requests
+	// to. If empty the default subset is used.
+	ServiceSubset string `json:",omitempty" alias:"service_subset"`
+
+	// Namespace is the namespace to resolve the mirror service from instead
+	// of the current namespace. If empty the current namespace is assumed.
+	Namespace string `json:",omitempty"`
+
+	// Partition is the partition to resolve the mirror service from instead
+	// of the current partition. If empty the current partition is assumed.
+	Partition string `json:",omitempty"`
+
+	// Percentage is the share of requests, from 0 to 100, that are mirrored.
+	// If zero, every request is mirrored.
+	Percentage float32 `json:",omitempty"`
+}
+
+func (m *RequestMirror) normalize(entMeta *EnterpriseMeta) {
+	if m == nil {
+		return
+	}
+	if m.Namespace == "" {
+		m.Namespace = entMeta.NamespaceOrEmpty()
+	}
+	if m.Partition == "" {
+		m.Partition = entMeta.PartitionOrEmpty()
+	}
+}
+
+func (m *RequestMirror) equal(other *RequestMirror) bool {
+	if m == nil || other == nil {
+		return m == other
+	}
+	return *m == *other
+}
+
+func (m *RequestMirror) validate() error {
+	if m.Service == "" && m.ServiceSubset == "" {
+		return fmt.Errorf("must specify at least one of Service or ServiceSubset")
+	}
+	if m.Percentage < 0 || m.Percentage > 100 {
+		return fmt.Errorf("Percentage must be between 0 and 100: %v", m.Percentage)
+	}
+	return nil
+}
+
 // ServiceSplitterConfigEntry defines how incoming requests are split across
 // different subsets of a single service (like during staged canary rollouts),
 // or perhaps across different services (like during a v2 rewrite or other type
@@ -523,6 +792,10 @@ func (e *ServiceSplitterConfigEntry) Normalize() error {
 
 	if len(e.Splits) > 0 {
 		for i, split := range e.Splits {
+			// Every split must carry the same Mirror, so normalize it on all of
+			// them regardless of their weight before Validate compares them.
+			e.Splits[i].Mirror.normalize(&e.EnterpriseMeta)
+
 			if split.Namespace == "" {
 				split.Namespace = e.EnterpriseMeta.NamespaceOrDefault()
 			}
@@ -574,6 +847,18 @@ func (e *ServiceSplitterConfigEntry) Validate() error {
 		found[splitKey] = struct{}{}
 	}
 
+	for i, split := range e.Splits {
+		if split.Mirror != nil {
+			if err := split.Mirror.validate(); err != nil {
+				return fmt.Errorf("Splits[%d] Mirror %v", i, err)
+			}
+		}
+		if !split.Mirror.equal(e.Splits[0].Mirror) {
+			return fmt.Errorf("Splits[%d] Mirror must be the same as the Mirror of the other splits, "+
+				"requests are mirrored regardless of the split they are sent to", i)
+		}
+	}
+
 	sumScaled := 0
 	for _, split := range e.Splits {
 		sumScaled += scaleWeight(split.Weight)
@@ -628,6 +913,13 @@ func (e *ServiceSplitterConfigEntry) ListRelatedServices() []ServiceID {
 		if splitID != svcID {
 			found[splitID] = struct{}{}
 		}
+
+		if split.Mirror != nil {
+			mirrorID := NewServiceID(defaultIfEmpty(split.Mirror.Service, e.Name), split.Mirror.GetEnterpriseMeta(&e.EnterpriseMeta))
+			if mirrorID != svcID {
+				found[mirrorID] = struct{}{}
+			}
+		}
 	}
 
 	if len(found) == 0 {
@@ -688,6 +980,13 @@ type ServiceSplit struct {
 	// Allow HTTP header manipulation to be configured.
 	RequestHeaders  *HTTPHeaderModifiers `json:",omitempty" alias:"request_headers"`
 	ResponseHeaders *HTTPHeaderModifiers `json:",omitempty" alias:"response_headers"`
+
+	// Mirror sends a copy of a share of the requests to another service.
+	// Responses to mirrored requests are discarded. Envoy can only mirror
+	// all the requests of a route rather than the ones assigned to a split,
+	// so every split of a splitter must have the same Mirror, including the
+	// splits of nested splitters.
+	Mirror *RequestMirror `json:",omitempty"`
 }
 
 // MergeParent is called by the discovery chain compiler when a split directs to
@@ -755,6 +1054,12 @@ func (s *ServiceSplit) MergeParent(parent *ServiceSplit) (*ServiceSplit, error)
 	if err != nil {
 		return nil, err
 	}
+
+	// Requests reaching the child passed through the parent first, so they
+	// are mirrored by the parent unless the child mirrors them itself.
+	if copy.Mirror == nil && parent != nil {
+		copy.Mirror = parent.Mirror
+	}
 	return &copy, nil
 }
 
@@ -255,6 +255,44 @@ func TestConfigEntries_ListRelatedServices_AndACLs(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "router: mirror",
+			entry: &ServiceRouterConfigEntry{
+				Kind: ServiceRouter,
+				Name: "test",
+				Routes: []ServiceRoute{
+					{
+						Match: &ServiceRouteMatch{HTTP: &ServiceRouteHTTPMatch{
+							PathPrefix: "/foo",
+						}},
+						Destination: &ServiceRouteDestination{
+							Service: "foo",
+							Mirror: &RequestMirror{
+								Service: "shadow",
+							},
+						},
+					},
+				},
+			},
+			expectServices: []ServiceID{NewServiceID("foo", nil), NewServiceID("shadow", nil), NewServiceID("test", nil)},
+			expectACLs: []testACL{
+				defaultDenyCase,
+				readTestCase,
+				writeTestCaseDenied,
+				{
+					name:       "cannot write test (with foo:read only)",
+					authorizer: newServiceACL(t, []string{"foo"}, []string{"test"}),
+					canRead:    true,
+					canWrite:   false,
+				},
+				{
+					name:       "can write test (with foo:read and shadow:read)",
+					authorizer: newServiceACL(t, []string{"foo", "shadow"}, []string{"test"}),
+					canRead:    true,
+					canWrite:   true,
+				},
+			},
+		},
 		{
 			name:  "ingress-gateway",
 			entry: &IngressGatewayConfigEntry{Name: "test"},
@@ -1310,6 +1348,74 @@ func TestServiceSplitterConfigEntry(t *testing.T) {
 			},
 			validateErr: "split destination occurs more than once",
 		},
+		{
+			name: "split with mirror",
+			entry: makesplitter(
+				ServiceSplit{
+					Weight:        100,
+					ServiceSubset: "v1",
+					Mirror: &RequestMirror{
+						ServiceSubset: "v2",
+						Percentage:    10,
+					},
+				},
+			),
+		},
+		{
+			name: "split with mirror on some splits",
+			entry: makesplitter(
+				ServiceSplit{
+					Weight:        50,
+					ServiceSubset: "v1",
+					Mirror:        &RequestMirror{Service: "shadow"},
+				},
+				ServiceSplit{
+					Weight:        50,
+					ServiceSubset: "v2",
+					Mirror:        &RequestMirror{Service: "shadow", Percentage: 10},
+				},
+			),
+			validateErr: "Splits[1] Mirror must be the same as the Mirror of the other splits",
+		},
+		{
+			name: "split with mirror on a zero weight split",
+			entry: makesplitter(
+				ServiceSplit{
+					Weight:        0,
+					ServiceSubset: "v1",
+					Mirror:        &RequestMirror{Service: "shadow", Percentage: 10},
+				},
+				ServiceSplit{
+					Weight:        100,
+					ServiceSubset: "v2",
+					Mirror:        &RequestMirror{Service: "shadow", Percentage: 10},
+				},
+			),
+			check: func(t *testing.T, entry *ServiceSplitterConfigEntry) {
+				require.Equal(t, float32(0), entry.Splits[0].Weight)
+				require.Equal(t, entry.Splits[1].Mirror, entry.Splits[0].Mirror)
+			},
+		},
+		{
+			name: "split with mirror missing service and subset",
+			entry: makesplitter(
+				ServiceSplit{
+					Weight: 100,
+					Mirror: &RequestMirror{Percentage: 10},
+				},
+			),
+			validateErr: "Splits[0] Mirror must specify at least one of Service or ServiceSubset",
+		},
+		{
+			name: "split with mirror percentage out of range",
+			entry: makesplitter(
+				ServiceSplit{
+					Weight: 100,
+					Mirror: &RequestMirror{Service: "shadow", Percentage: 150},
+				},
+			),
+			validateErr: "Splits[0] Mirror Percentage must be between 0 and 100",
+		},
 	} {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
@@ -1480,6 +1586,41 @@ func TestServiceSplitMergeParent(t *testing.T) {
 				Service: "foo",
 			},
 		},
+		{
+			name: "mirror inherited from parent",
+			split: &ServiceSplit{
+				Weight:  50,
+				Service: "foo",
+			},
+			parent: &ServiceSplit{
+				Weight:  50,
+				Service: "bar",
+				Mirror:  &RequestMirror{Service: "shadow"},
+			},
+			want: &ServiceSplit{
+				Weight:  50,
+				Service: "foo",
+				Mirror:  &RequestMirror{Service: "shadow"},
+			},
+		},
+		{
+			name: "mirror overridden by child",
+			split: &ServiceSplit{
+				Weight:  50,
+				Service: "foo",
+				Mirror:  &RequestMirror{Service: "child-shadow"},
+			},
+			parent: &ServiceSplit{
+				Weight:  50,
+				Service: "bar",
+				Mirror:  &RequestMirror{Service: "shadow"},
+			},
+			want: &ServiceSplit{
+				Weight:  50,
+				Service: "foo",
+				Mirror:  &RequestMirror{Service: "child-shadow"},
+			},
+		},
 		{
 			name:   "both nil",
 			split:  nil,
@@ -1879,6 +2020,213 @@ func TestServiceRouterConfigEntry(t *testing.T) {
 			}))),
 			validateErr: "Methods contains \"GET\" more than once",
 		},
+		////////////////
+		{
+			name: "route with fault delay and abort",
+			entry: makerouter(ServiceRoute{
+				Match: httpMatch(&ServiceRouteHTTPMatch{
+					PathPrefix: "/api",
+				}),
+				Destination: &ServiceRouteDestination{
+					Service: "other",
+					Fault: &ServiceRouteFault{
+						Delay: &ServiceRouteFaultDelay{
+							Duration:   5 * time.Second,
+							Percentage: 50,
+						},
+						Abort: &ServiceRouteFaultAbort{
+							HTTPStatus: 503,
+							Percentage: 10.5,
+						},
+						Header: []ServiceRouteHTTPMatchHeader{
+							{Name: "x-chaos", Present: true},
+						},
+					},
+				},
+			}),
+		},
+		{
+			name: "route with empty fault",
+			entry: makerouter(ServiceRoute{
+				Destination: &ServiceRouteDestination{
+					Service: "other",
+					Fault:   &ServiceRouteFault{},
+				},
+			}),
+			validateErr: "Route[0] Fault must configure at least one of Delay or Abort",
+		},
+		{
+			name: "route with fault delay missing duration",
+			entry: makerouter(ServiceRoute{
+				Destination: &ServiceRouteDestination{
+					Service: "other",
+					Fault: &ServiceRouteFault{
+						Delay: &ServiceRouteFaultDelay{Percentage: 50},
+					},
+				},
+			}),
+			validateErr: "Delay.Duration must be greater than zero",
+		},
+		{
+			name: "route with fault delay percentage out of range",
+			entry: makerouter(ServiceRoute{
+				Destination: &ServiceRouteDestination{
+					Service: "other",
+					Fault: &ServiceRouteFault{
+						Delay: &ServiceRouteFaultDelay{Duration: time.Second, Percentage: 101},
+					},
+				},
+			}),
+			validateErr: "Delay.Percentage must be between 0 and 100",
+		},
+		{
+			name: "route with fault abort missing status",
+			entry: makerouter(ServiceRoute{
+				Destination: &ServiceRouteDestination{
+					Service: "other",
+					Fault: &ServiceRouteFault{
+						Abort: &ServiceRouteFaultAbort{Percentage: 50},
+					},
+				},
+			}),
+			validateErr: "Abort must contain HTTPStatus",
+		},
+		{
+			name: "route with fault abort both statuses",
+			entry: makerouter(ServiceRoute{
+				Destination: &ServiceRouteDestination{
+					Service: "other",
+					Fault: &ServiceRouteFault{
+						Abort: &ServiceRouteFaultAbort{HTTPStatus: 503, GRPCStatus: 14},
+					},
+				},
+			}),
+			validateErr: "Abort.GRPCStatus is not supported yet",
+		},
+		{
+			name: "route with fault abort invalid http status",
+			entry: makerouter(ServiceRoute{
+				Destination: &ServiceRouteDestination{
+					Service: "other",
+					Fault: &ServiceRouteFault{
+						Abort: &ServiceRouteFaultAbort{HTTPStatus: 700},
+					},
+				},
+			}),
+			validateErr: "Abort.HTTPStatus must be between 200 and 599",
+		},
+		{
+			name: "route with fault abort grpc status",
+			entry: makerouter(ServiceRoute{
+				Destination: &ServiceRouteDestination{
+					Service: "other",
+					Fault: &ServiceRouteFault{
+						Abort: &ServiceRouteFaultAbort{GRPCStatus: 14},
+					},
+				},
+			}),
+			validateErr: "Abort.GRPCStatus is not supported yet",
+		},
+		{
+			name: "route with mirror",
+			entry: makerouter(ServiceRoute{
+				Destination: &ServiceRouteDestination{
+					Service: "other",
+					Mirror: &RequestMirror{
+						Service:    "shadow",
+						Percentage: 5,
+					},
+				},
+			}),
+		},
+		{
+			name: "route with mirror percentage out of range",
+			entry: makerouter(ServiceRoute{
+				Destination: &ServiceRouteDestination{
+					Service: "other",
+					Mirror: &RequestMirror{
+						Service:    "shadow",
+						Percentage: -1,
+					},
+				},
+			}),
+			validateErr: "Route[0] Mirror Percentage must be between 0 and 100",
+		},
+		{
+			name: "route with fault header missing name",
+			entry: makerouter(ServiceRoute{
+				Destination: &ServiceRouteDestination{
+					Service: "other",
+					Fault: &ServiceRouteFault{
+						Abort:  &ServiceRouteFaultAbort{HTTPStatus: 500},
+						Header: []ServiceRouteHTTPMatchHeader{{Exact: "yes"}},
+					},
+				},
+			}),
+			validateErr: "Route[0] Fault Header[0] missing required Name field",
+		},
+		////////////////
+		{
+			name: "route with grpc service and method",
+			entry: makerouter(routeMatch(&ServiceRouteMatch{
+				GRPC: &ServiceRouteGRPCMatch{
+					Service: "pkg.Service",
+					Method:  "Method",
+				},
+			})),
+		},
+		{
+			name: "route with grpc metadata",
+			entry: makerouter(routeMatch(&ServiceRouteMatch{
+				GRPC: &ServiceRouteGRPCMatch{
+					Metadata: []ServiceRouteHTTPMatchHeader{
+						{Name: "x-tenant", Exact: "acme"},
+					},
+				},
+			})),
+		},
+		{
+			name: "route with grpc method and no service",
+			entry: makerouter(routeMatch(&ServiceRouteMatch{
+				GRPC: &ServiceRouteGRPCMatch{
+					Method: "Method",
+				},
+			})),
+			validateErr: "Route[0] GRPC Method requires Service to be set",
+		},
+		{
+			name: "route with grpc service containing a slash",
+			entry: makerouter(routeMatch(&ServiceRouteMatch{
+				GRPC: &ServiceRouteGRPCMatch{
+					Service: "/pkg.Service",
+				},
+			})),
+			validateErr: "Route[0] GRPC Service \"/pkg.Service\" must not contain '/'",
+		},
+		{
+			name: "route with grpc metadata missing name",
+			entry: makerouter(routeMatch(&ServiceRouteMatch{
+				GRPC: &ServiceRouteGRPCMatch{
+					Service: "pkg.Service",
+					Metadata: []ServiceRouteHTTPMatchHeader{
+						{Exact: "acme"},
+					},
+				},
+			})),
+			validateErr: "Route[0] GRPC Metadata[0] missing required Name field",
+		},
+		{
+			name: "route with both http and grpc match",
+			entry: makerouter(routeMatch(&ServiceRouteMatch{
+				HTTP: &ServiceRouteHTTPMatch{
+					PathPrefix: "/",
+				},
+				GRPC: &ServiceRouteGRPCMatch{
+					Service: "pkg.Service",
+				},
+			})),
+			validateErr: "Route[0] cannot specify both HTTP and GRPC match criteria",
+		},
 	}
 
 	for _, tc := range cases {
@@ -127,6 +127,7 @@ func (e *ServiceIntentionsConfigEntry) ToIntention(src *SourceIntention) *Intent
 		SourceType:           src.Type,
 		Action:               src.Action,
 		Permissions:          src.Permissions,
+		JWT:                  src.JWT,
 		Meta:                 meta,
 		Precedence:           src.Precedence,
 		DestinationPartition: e.PartitionOrEmpty(),
@@ -214,6 +215,11 @@ type SourceIntention struct {
 	//   ]
 	Permissions []*IntentionPermission `json:",omitempty"`
 
+	// JWT specifies a JWT that must be presented and verified by one of the
+	// named jwt-provider config entries. When set, the requirement applies to
+	// the intention's Action or to each of its Permissions.
+	JWT *IntentionJWTRequirement `json:",omitempty"`
+
 	// Precedence is the order that the intention will be applied, with
 	// larger numbers being applied first. This is a read-only field, on
 	// any intention update it is updated.
@@ -264,12 +270,12 @@ type IntentionPermission struct {
 
 	HTTP *IntentionHTTPPermission `json:",omitempty"`
 
+	// JWT requires a verified JWT in addition to the HTTP match criteria.
+	JWT *IntentionJWTRequirement `json:",omitempty"`
+
 	// If we have non-http match criteria for other protocols
 	// in the future (gRPC, redis, etc) they can go here.
 
-	// Support for edge-decoded JWTs would likely be configured
-	// in a new top level section here.
-
 	// If we ever add Sentinel support, this is one place we may
 	// wish to add it.
 }
@@ -279,6 +285,9 @@ func (p *IntentionPermission) Clone() *IntentionPermission {
 	if p.HTTP != nil {
 		p2.HTTP = p.HTTP.Clone()
 	}
+	if p.JWT != nil {
+		p2.JWT = p.JWT.Clone()
+	}
 	return &p2
 }
 
@@ -318,6 +327,64 @@ type IntentionHTTPHeaderPermission struct {
 	Invert  bool   `json:",omitempty"`
 }
 
+// IntentionJWTRequirement requires a request to carry a JWT verified by a
+// jwt-provider config entry, and optionally for that token to contain
+// specific claims.
+type IntentionJWTRequirement struct {
+	// Provider is the name of the jwt-provider config entry that must have
+	// verified the request's token.
+	Provider string
+
+	// Claims are claims that must be present in the verified token. All
+	// claims must match.
+	Claims []IntentionJWTClaimVerification `json:",omitempty"`
+}
+
+func (r *IntentionJWTRequirement) Clone() *IntentionJWTRequirement {
+	r2 := *r
+	if len(r.Claims) > 0 {
+		r2.Claims = make([]IntentionJWTClaimVerification, 0, len(r.Claims))
+		for _, c := range r.Claims {
+			r2.Claims = append(r2.Claims, IntentionJWTClaimVerification{
+				Path:  CloneStringSlice(c.Path),
+				Value: c.Value,
+			})
+		}
+	}
+	return &r2
+}
+
+// IntentionJWTClaimVerification matches a single claim of a verified JWT.
+type IntentionJWTClaimVerification struct {
+	// Path is the path to the claim in the token payload, e.g. ["roles"] or
+	// ["perms", "role"] for nested claims.
+	Path []string
+
+	// Value is the expected value of the claim. If the claim is a list, one
+	// of its elements must equal Value.
+	Value string
+}
+
+func (r *IntentionJWTRequirement) validate() error {
+	if r.Provider == "" {
+		return fmt.Errorf("Provider is required")
+	}
+	for i, c := range r.Claims {
+		if len(c.Path) == 0 {
+			return fmt.Errorf("Claims[%d].Path is required", i)
+		}
+		for _, seg := range c.Path {
+			if seg == "" {
+				return fmt.Errorf("Claims[%d].Path cannot contain empty segments", i)
+			}
+		}
+		if c.Value == "" {
+			return fmt.Errorf("Claims[%d].Value is required", i)
+		}
+	}
+	return nil
+}
+
 func cloneStringStringMap(m map[string]string) map[string]string {
 	if m == nil {
 		return nil
@@ -345,6 +412,10 @@ func (x *SourceIntention) Clone() *SourceIntention {
 		}
 	}
 
+	if x.JWT != nil {
+		x2.JWT = x.JWT.Clone()
+	}
+
 	return &x2
 }
 
@@ -646,6 +717,18 @@ func (e *ServiceIntentionsConfigEntry) validate(legacyWrite bool) error {
 			return fmt.Errorf("Sources[%d].Permissions cannot be specified on intentions with wildcarded destinations", i)
 		}
 
+		if src.JWT != nil {
+			if legacyWrite {
+				return fmt.Errorf("Sources[%d].JWT must be omitted", i)
+			}
+			if destIsWild {
+				return fmt.Errorf("Sources[%d].JWT cannot be specified on intentions with wildcarded destinations", i)
+			}
+			if err := src.JWT.validate(); err != nil {
+				return fmt.Errorf("Sources[%d].JWT %v", i, err)
+			}
+		}
+
 		switch src.Type {
 		case IntentionSourceConsul:
 		default:
@@ -659,8 +742,18 @@ func (e *ServiceIntentionsConfigEntry) validate(legacyWrite bool) error {
 				return fmt.Errorf("Sources[%d].Permissions[%d].Action must be set to 'allow' or 'deny'", i, j)
 			}
 
+			if perm.JWT != nil {
+				if err := perm.JWT.validate(); err != nil {
+					return fmt.Errorf("Sources[%d].Permissions[%d].JWT %v", i, j, err)
+				}
+			}
+
 			errorPrefix := "Sources[%d].Permissions[%d].HTTP"
 			if perm.HTTP == nil {
+				if perm.JWT != nil {
+					// A JWT requirement on its own is a complete permission.
+					continue
+				}
 				return fmt.Errorf(errorPrefix+" is required", i, j)
 			}
 
@@ -1226,6 +1226,104 @@ func TestServiceIntentionsConfigEntry(t *testing.T) {
 				}, entry.Sources)
 			},
 		},
+		"jwt requirement without provider": {
+			entry: &ServiceIntentionsConfigEntry{
+				Kind: ServiceIntentions,
+				Name: "test",
+				Sources: []*SourceIntention{
+					{
+						Name:   "foo",
+						Action: IntentionActionAllow,
+						JWT:    &IntentionJWTRequirement{},
+					},
+				},
+			},
+			validateErr: "Sources[0].JWT Provider is required",
+		},
+		"jwt claim without path": {
+			entry: &ServiceIntentionsConfigEntry{
+				Kind: ServiceIntentions,
+				Name: "test",
+				Sources: []*SourceIntention{
+					{
+						Name:   "foo",
+						Action: IntentionActionAllow,
+						JWT: &IntentionJWTRequirement{
+							Provider: "okta",
+							Claims:   []IntentionJWTClaimVerification{{Value: "admin"}},
+						},
+					},
+				},
+			},
+			validateErr: "Sources[0].JWT Claims[0].Path is required",
+		},
+		"jwt with wildcard destination": {
+			entry: &ServiceIntentionsConfigEntry{
+				Kind: ServiceIntentions,
+				Name: "*",
+				Sources: []*SourceIntention{
+					{
+						Name:   "foo",
+						Action: IntentionActionAllow,
+						JWT:    &IntentionJWTRequirement{Provider: "okta"},
+					},
+				},
+			},
+			validateErr: "Sources[0].JWT cannot be specified on intentions with wildcarded destinations",
+		},
+		"jwt permission without claim value": {
+			entry: &ServiceIntentionsConfigEntry{
+				Kind: ServiceIntentions,
+				Name: "test",
+				Sources: []*SourceIntention{
+					{
+						Name: "foo",
+						Permissions: []*IntentionPermission{
+							{
+								Action: IntentionActionAllow,
+								JWT: &IntentionJWTRequirement{
+									Provider: "okta",
+									Claims:   []IntentionJWTClaimVerification{{Path: []string{"roles"}}},
+								},
+							},
+						},
+					},
+				},
+			},
+			validateErr: "Sources[0].Permissions[0].JWT Claims[0].Value is required",
+		},
+		"jwt on intention and permission": {
+			entry: &ServiceIntentionsConfigEntry{
+				Kind: ServiceIntentions,
+				Name: "test",
+				Sources: []*SourceIntention{
+					{
+						Name: "foo",
+						JWT: &IntentionJWTRequirement{
+							Provider: "okta",
+							Claims: []IntentionJWTClaimVerification{
+								{Path: []string{"roles"}, Value: "admin"},
+							},
+						},
+						Permissions: []*IntentionPermission{
+							{
+								Action: IntentionActionAllow,
+								HTTP: &IntentionHTTPPermission{
+									PathPrefix: "/v1",
+								},
+							},
+							{
+								// A JWT on its own does not need HTTP criteria.
+								Action: IntentionActionAllow,
+								JWT: &IntentionJWTRequirement{
+									Provider: "auth0",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 	for name, tc := range cases {
 		tc := tc
@@ -0,0 +1,360 @@
+package structs
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/consul/acl"
+	"github.com/hashicorp/consul/lib"
+)
+
+// JWTProviderConfigEntry describes an issuer of JSON Web Tokens that
+// intentions can require requests to present.
+type JWTProviderConfigEntry struct {
+	Name string
+
+	// Issuer is the entity that must have issued the JWT. This value must
+	// match the "iss" claim of the token.
+	Issuer string `json:",omitempty"`
+
+	// JSONWebKeySet defines a JSON Web Key Set, its location on disk, or the
+	// means with which to fetch a key set from a remote server.
+	JSONWebKeySet *JSONWebKeySet `json:",omitempty" alias:"json_web_key_set"`
+
+	// Audiences is the set of audiences the JWT is allowed to access. If
+	// specified, the "aud" claim of the token must match at least one of
+	// these values.
+	Audiences []string `json:",omitempty"`
+
+	// Forwarding defines rules for forwarding verified JWTs to the backend.
+	Forwarding *JWTForwardingConfig `json:",omitempty"`
+
+	Meta           map[string]string `json:",omitempty"`
+	EnterpriseMeta `hcl:",squash" mapstructure:",squash"`
+	RaftIndex
+}
+
+// JSONWebKeySet defines a key set, its location on disk, or the means with
+// which to fetch a key set from a remote server. Exactly one of Local or
+// Remote must be set.
+type JSONWebKeySet struct {
+	Local  *LocalJWKS  `json:",omitempty"`
+	Remote *RemoteJWKS `json:",omitempty"`
+}
+
+// LocalJWKS specifies a key set available to the proxy locally. Exactly one
+// of JWKS or Filename must be set.
+type LocalJWKS struct {
+	// JWKS contains a base64 encoded JWKS.
+	JWKS string `json:",omitempty"`
+
+	// Filename configures a location on disk where the JWKS can be found.
+	Filename string `json:",omitempty"`
+}
+
+// RemoteJWKS specifies how to fetch a key set from a remote server.
+type RemoteJWKS struct {
+	// URI is the URI of the server to query for the JWKS.
+	URI string `json:",omitempty"`
+
+	// RequestTimeoutMs is the number of milliseconds to time out when making
+	// a request for the JWKS.
+	RequestTimeoutMs int `json:",omitempty" alias:"request_timeout_ms"`
+
+	// CacheDuration is the duration after which cached keys should be
+	// expired. Envoy's default of 5 minutes is used when unset.
+	CacheDuration time.Duration `json:",omitempty" alias:"cache_duration"`
+
+	// JWKSCluster defines how the specified Remote JWKS URI is to be
+	// fetched.
+	JWKSCluster *JWKSCluster `json:",omitempty" alias:"jwks_cluster"`
+}
+
+// JWKSCluster configures the cluster the proxy uses to fetch a remote key
+// set.
+type JWKSCluster struct {
+	// DiscoveryType refers to the service discovery type to use for
+	// resolving the cluster. Defaults to STRICT_DNS.
+	DiscoveryType ClusterDiscoveryType `json:",omitempty" alias:"discovery_type"`
+
+	// ConnectTimeout is the timeout for new network connections to hosts in
+	// the cluster. Defaults to 5 seconds.
+	ConnectTimeout time.Duration `json:",omitempty" alias:"connect_timeout"`
+
+	// TrustedCA is the certificate authority bundle used to verify the
+	// certificate of the server serving the JWKS over https. The system
+	// bundle of the proxy is used when unset.
+	TrustedCA *JWKSTrustedCA `json:",omitempty" alias:"trusted_ca"`
+}
+
+// JWKSTrustedCA specifies a PEM encoded certificate authority bundle.
+// Exactly one of Filename or InlineString must be set.
+type JWKSTrustedCA struct {
+	// Filename configures a location on the proxy's disk where the bundle
+	// can be found.
+	Filename string `json:",omitempty"`
+
+	// InlineString contains the PEM encoded bundle.
+	InlineString string `json:",omitempty" alias:"inline_string"`
+}
+
+type ClusterDiscoveryType string
+
+const (
+	DiscoveryTypeStrictDNS  ClusterDiscoveryType = "STRICT_DNS"
+	DiscoveryTypeStatic     ClusterDiscoveryType = "STATIC"
+	DiscoveryTypeLogicalDNS ClusterDiscoveryType = "LOGICAL_DNS"
+)
+
+// JWTForwardingConfig defines rules for forwarding verified JWTs to the
+// backend.
+type JWTForwardingConfig struct {
+	// HeaderName is a header name to use when forwarding a verified JWT
+	// payload to the backend. The verified payload is base64url encoded.
+	HeaderName string `json:",omitempty" alias:"header_name"`
+
+	// KeepToken retains the original token in the request instead of
+	// removing it once it has been verified.
+	KeepToken bool `json:",omitempty" alias:"keep_token"`
+}
+
+func (e *JWTProviderConfigEntry) GetKind() string {
+	return JWTProvider
+}
+
+func (e *JWTProviderConfigEntry) GetName() string {
+	if e == nil {
+		return ""
+	}
+
+	return e.Name
+}
+
+func (e *JWTProviderConfigEntry) GetMeta() map[string]string {
+	if e == nil {
+		return nil
+	}
+	return e.Meta
+}
+
+func (e *JWTProviderConfigEntry) Normalize() error {
+	if e == nil {
+		return fmt.Errorf("config entry is nil")
+	}
+
+	e.EnterpriseMeta.Normalize()
+
+	if e.JSONWebKeySet != nil && e.JSONWebKeySet.Remote != nil {
+		if c := e.JSONWebKeySet.Remote.JWKSCluster; c != nil && c.DiscoveryType == "" {
+			c.DiscoveryType = DiscoveryTypeStrictDNS
+		}
+	}
+	return nil
+}
+
+func (e *JWTProviderConfigEntry) Validate() error {
+	if e == nil {
+		return fmt.Errorf("config entry is nil")
+	}
+	if e.Name == "" {
+		return fmt.Errorf("Name is required")
+	}
+	if err := validateConfigEntryMeta(e.Meta); err != nil {
+		return err
+	}
+
+	if e.Issuer == "" {
+		return fmt.Errorf("Issuer is required")
+	}
+
+	if e.JSONWebKeySet == nil {
+		return fmt.Errorf("JSONWebKeySet is required")
+	}
+	if err := e.JSONWebKeySet.validate(); err != nil {
+		return fmt.Errorf("JSONWebKeySet %v", err)
+	}
+
+	if e.Forwarding != nil && e.Forwarding.HeaderName != "" {
+		if strings.ContainsAny(e.Forwarding.HeaderName, " \t:") {
+			return fmt.Errorf("Forwarding.HeaderName %q is not a valid header name", e.Forwarding.HeaderName)
+		}
+	}
+
+	return e.validateEnterpriseMeta()
+}
+
+func (ks *JSONWebKeySet) validate() error {
+	if (ks.Local != nil) == (ks.Remote != nil) {
+		return fmt.Errorf("must specify exactly one of Local or Remote")
+	}
+	if ks.Local != nil {
+		return ks.Local.validate()
+	}
+	return ks.Remote.validate()
+}
+
+func (ks *LocalJWKS) validate() error {
+	if (ks.JWKS != "") == (ks.Filename != "") {
+		return fmt.Errorf("Local must specify exactly one of JWKS or Filename")
+	}
+	if ks.JWKS != "" {
+		if _, err := base64.StdEncoding.DecodeString(ks.JWKS); err != nil {
+			return fmt.Errorf("Local.JWKS must be valid base64 encoded string")
+		}
+	}
+	return nil
+}
+
+func (ks *RemoteJWKS) validate() error {
+	if ks.URI == "" {
+		return fmt.Errorf("Remote.URI is required")
+	}
+	u, err := url.ParseRequestURI(ks.URI)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("Remote.URI %q is not a valid http(s) URI", ks.URI)
+	}
+	if ks.RequestTimeoutMs < 0 {
+		return fmt.Errorf("Remote.RequestTimeoutMs must be greater than or equal to 0")
+	}
+	if ks.CacheDuration < 0 {
+		return fmt.Errorf("Remote.CacheDuration must be greater than or equal to 0")
+	}
+	if c := ks.JWKSCluster; c != nil {
+		switch c.DiscoveryType {
+		case "", DiscoveryTypeStrictDNS, DiscoveryTypeStatic, DiscoveryTypeLogicalDNS:
+		default:
+			return fmt.Errorf("Remote.JWKSCluster.DiscoveryType %q is not one of %q, %q, or %q",
+				c.DiscoveryType, DiscoveryTypeStrictDNS, DiscoveryTypeStatic, DiscoveryTypeLogicalDNS)
+		}
+		if c.ConnectTimeout < 0 {
+			return fmt.Errorf("Remote.JWKSCluster.ConnectTimeout must be greater than or equal to 0")
+		}
+		if ca := c.TrustedCA; ca != nil {
+			if u.Scheme != "https" {
+				return fmt.Errorf("Remote.JWKSCluster.TrustedCA can only be set for https URIs")
+			}
+			if (ca.Filename == "") == (ca.InlineString == "") {
+				return fmt.Errorf("Remote.JWKSCluster.TrustedCA must set exactly one of Filename or InlineString")
+			}
+		}
+	}
+	return nil
+}
+
+// CanRead always allows reading jwt-provider entries, since proxies must be
+// able to fetch them and they only reference public key material.
+func (e *JWTProviderConfigEntry) CanRead(authz acl.Authorizer) bool {
+	return true
+}
+
+func (e *JWTProviderConfigEntry) CanWrite(authz acl.Authorizer) bool {
+	var authzContext acl.AuthorizerContext
+	e.FillAuthzContext(&authzContext)
+	return authz.MeshWrite(&authzContext) == acl.Allow
+}
+
+func (e *JWTProviderConfigEntry) GetRaftIndex() *RaftIndex {
+	if e == nil {
+		return &RaftIndex{}
+	}
+
+	return &e.RaftIndex
+}
+
+func (e *JWTProviderConfigEntry) GetEnterpriseMeta() *EnterpriseMeta {
+	if e == nil {
+		return nil
+	}
+
+	return &e.EnterpriseMeta
+}
+
+// MarshalJSON adds the Kind field so that the JSON can be decoded back into the
+// correct type.
+func (e *JWTProviderConfigEntry) MarshalJSON() ([]byte, error) {
+	type Alias JWTProviderConfigEntry
+	source := &struct {
+		Kind string
+		*Alias
+	}{
+		Kind:  JWTProvider,
+		Alias: (*Alias)(e),
+	}
+	return json.Marshal(source)
+}
+
+func (r *RemoteJWKS) MarshalJSON() ([]byte, error) {
+	type Alias RemoteJWKS
+	exported := &struct {
+		CacheDuration string `json:",omitempty"`
+		*Alias
+	}{
+		CacheDuration: r.CacheDuration.String(),
+		Alias:         (*Alias)(r),
+	}
+	if r.CacheDuration == 0 {
+		exported.CacheDuration = ""
+	}
+
+	return json.Marshal(exported)
+}
+
+func (r *RemoteJWKS) UnmarshalJSON(data []byte) error {
+	type Alias RemoteJWKS
+	aux := &struct {
+		CacheDuration string
+		*Alias
+	}{
+		Alias: (*Alias)(r),
+	}
+	if err := lib.UnmarshalJSON(data, &aux); err != nil {
+		return err
+	}
+	var err error
+	if aux.CacheDuration != "" {
+		if r.CacheDuration, err = time.ParseDuration(aux.CacheDuration); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *JWKSCluster) MarshalJSON() ([]byte, error) {
+	type Alias JWKSCluster
+	exported := &struct {
+		ConnectTimeout string `json:",omitempty"`
+		*Alias
+	}{
+		ConnectTimeout: c.ConnectTimeout.String(),
+		Alias:          (*Alias)(c),
+	}
+	if c.ConnectTimeout == 0 {
+		exported.ConnectTimeout = ""
+	}
+
+	return json.Marshal(exported)
+}
+
+func (c *JWKSCluster) UnmarshalJSON(data []byte) error {
+	type Alias JWKSCluster
+	aux := &struct {
+		ConnectTimeout string
+		*Alias
+	}{
+		Alias: (*Alias)(c),
+	}
+	if err := lib.UnmarshalJSON(data, &aux); err != nil {
+		return err
+	}
+	var err error
+	if aux.ConnectTimeout != "" {
+		if c.ConnectTimeout, err = time.ParseDuration(aux.ConnectTimeout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
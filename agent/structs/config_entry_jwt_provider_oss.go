@@ -0,0 +1,8 @@
+//go:build !consulent
+// +build !consulent
+
+package structs
+
+func (e *JWTProviderConfigEntry) validateEnterpriseMeta() error {
+	return nil
+}
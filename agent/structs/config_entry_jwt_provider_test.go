@@ -0,0 +1,165 @@
+package structs
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+)
+
+func TestJWTProviderConfigEntry(t *testing.T) {
+	localJWKS := base64.StdEncoding.EncodeToString([]byte(`{"keys":[]}`))
+
+	cases := map[string]configEntryTestcase{
+		"valid local jwks": {
+			entry: &JWTProviderConfigEntry{
+				Name:   "okta",
+				Issuer: "https://okta.example.com",
+				JSONWebKeySet: &JSONWebKeySet{
+					Local: &LocalJWKS{JWKS: localJWKS},
+				},
+			},
+			expectUnchanged: true,
+		},
+		"valid remote jwks defaults discovery type": {
+			entry: &JWTProviderConfigEntry{
+				Name:   "okta",
+				Issuer: "https://okta.example.com",
+				JSONWebKeySet: &JSONWebKeySet{
+					Remote: &RemoteJWKS{
+						URI:         "https://okta.example.com/.well-known/jwks.json",
+						JWKSCluster: &JWKSCluster{ConnectTimeout: time.Second},
+					},
+				},
+			},
+			expected: &JWTProviderConfigEntry{
+				Name:   "okta",
+				Issuer: "https://okta.example.com",
+				JSONWebKeySet: &JSONWebKeySet{
+					Remote: &RemoteJWKS{
+						URI: "https://okta.example.com/.well-known/jwks.json",
+						JWKSCluster: &JWKSCluster{
+							DiscoveryType:  DiscoveryTypeStrictDNS,
+							ConnectTimeout: time.Second,
+						},
+					},
+				},
+			},
+		},
+		"missing name": {
+			entry: &JWTProviderConfigEntry{
+				Issuer: "https://okta.example.com",
+			},
+			validateErr: "Name is required",
+		},
+		"missing issuer": {
+			entry: &JWTProviderConfigEntry{
+				Name: "okta",
+			},
+			validateErr: "Issuer is required",
+		},
+		"missing key set": {
+			entry: &JWTProviderConfigEntry{
+				Name:   "okta",
+				Issuer: "https://okta.example.com",
+			},
+			validateErr: "JSONWebKeySet is required",
+		},
+		"local and remote key set": {
+			entry: &JWTProviderConfigEntry{
+				Name:   "okta",
+				Issuer: "https://okta.example.com",
+				JSONWebKeySet: &JSONWebKeySet{
+					Local:  &LocalJWKS{JWKS: localJWKS},
+					Remote: &RemoteJWKS{URI: "https://okta.example.com/.well-known/jwks.json"},
+				},
+			},
+			validateErr: "JSONWebKeySet must specify exactly one of Local or Remote",
+		},
+		"local jwks and filename": {
+			entry: &JWTProviderConfigEntry{
+				Name:   "okta",
+				Issuer: "https://okta.example.com",
+				JSONWebKeySet: &JSONWebKeySet{
+					Local: &LocalJWKS{JWKS: localJWKS, Filename: "/etc/jwks.json"},
+				},
+			},
+			validateErr: "Local must specify exactly one of JWKS or Filename",
+		},
+		"local jwks not base64": {
+			entry: &JWTProviderConfigEntry{
+				Name:   "okta",
+				Issuer: "https://okta.example.com",
+				JSONWebKeySet: &JSONWebKeySet{
+					Local: &LocalJWKS{JWKS: "{not-base64}"},
+				},
+			},
+			validateErr: "Local.JWKS must be valid base64 encoded string",
+		},
+		"remote invalid uri": {
+			entry: &JWTProviderConfigEntry{
+				Name:   "okta",
+				Issuer: "https://okta.example.com",
+				JSONWebKeySet: &JSONWebKeySet{
+					Remote: &RemoteJWKS{URI: "okta.example.com/jwks"},
+				},
+			},
+			validateErr: `Remote.URI "okta.example.com/jwks" is not a valid http(s) URI`,
+		},
+		"remote invalid discovery type": {
+			entry: &JWTProviderConfigEntry{
+				Name:   "okta",
+				Issuer: "https://okta.example.com",
+				JSONWebKeySet: &JSONWebKeySet{
+					Remote: &RemoteJWKS{
+						URI:         "https://okta.example.com/.well-known/jwks.json",
+						JWKSCluster: &JWKSCluster{DiscoveryType: "EDS"},
+					},
+				},
+			},
+			validateErr: `Remote.JWKSCluster.DiscoveryType "EDS" is not one of`,
+		},
+		"remote trusted ca for http uri": {
+			entry: &JWTProviderConfigEntry{
+				Name:   "okta",
+				Issuer: "https://okta.example.com",
+				JSONWebKeySet: &JSONWebKeySet{
+					Remote: &RemoteJWKS{
+						URI: "http://okta.example.com/.well-known/jwks.json",
+						JWKSCluster: &JWKSCluster{
+							TrustedCA: &JWKSTrustedCA{Filename: "/etc/ca.pem"},
+						},
+					},
+				},
+			},
+			validateErr: "Remote.JWKSCluster.TrustedCA can only be set for https URIs",
+		},
+		"remote trusted ca filename and inline string": {
+			entry: &JWTProviderConfigEntry{
+				Name:   "okta",
+				Issuer: "https://okta.example.com",
+				JSONWebKeySet: &JSONWebKeySet{
+					Remote: &RemoteJWKS{
+						URI: "https://okta.example.com/.well-known/jwks.json",
+						JWKSCluster: &JWKSCluster{
+							TrustedCA: &JWKSTrustedCA{Filename: "/etc/ca.pem", InlineString: "pem"},
+						},
+					},
+				},
+			},
+			validateErr: "Remote.JWKSCluster.TrustedCA must set exactly one of Filename or InlineString",
+		},
+		"invalid forwarding header": {
+			entry: &JWTProviderConfigEntry{
+				Name:   "okta",
+				Issuer: "https://okta.example.com",
+				JSONWebKeySet: &JSONWebKeySet{
+					Local: &LocalJWKS{Filename: "/etc/jwks.json"},
+				},
+				Forwarding: &JWTForwardingConfig{HeaderName: "x jwt"},
+			},
+			validateErr: `Forwarding.HeaderName "x jwt" is not a valid header name`,
+		},
+	}
+
+	testConfigEntryNormalizeAndValidate(t, cases)
+}
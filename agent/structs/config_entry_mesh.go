@@ -12,6 +12,14 @@ type MeshConfigEntry struct {
 	// when enabled.
 	TransparentProxy TransparentProxyMeshConfig `alias:"transparent_proxy"`
 
+	// Intentions contains cluster-wide options pertaining to how intentions
+	// are enforced.
+	Intentions IntentionsMeshConfig `alias:"intentions"`
+
+	// Locality contains cluster-wide options pertaining to locality-aware
+	// load balancing of upstream endpoints.
+	Locality LocalityMeshConfig `alias:"locality"`
+
 	Meta           map[string]string `json:",omitempty"`
 	EnterpriseMeta `hcl:",squash" mapstructure:",squash"`
 	RaftIndex
@@ -25,6 +33,25 @@ type TransparentProxyMeshConfig struct {
 	MeshDestinationsOnly bool `alias:"mesh_destinations_only"`
 }
 
+// IntentionsMeshConfig contains cluster-wide options pertaining to how
+// intentions are enforced.
+type IntentionsMeshConfig struct {
+	// AuditMode evaluates intentions without enforcing them. Connections that
+	// intentions would deny are logged and counted, but allowed through. This
+	// can be used to find missing intentions before enforcing default-deny.
+	AuditMode bool `alias:"audit_mode"`
+}
+
+// LocalityMeshConfig contains cluster-wide options pertaining to
+// locality-aware load balancing.
+type LocalityMeshConfig struct {
+	// ZoneMetaKey is the service or node meta key holding the zone of an
+	// instance. When set, proxies prefer upstream instances in their own zone
+	// and only send traffic to other zones when too few local instances are
+	// healthy. Locality-aware load balancing is disabled if it is empty.
+	ZoneMetaKey string `alias:"zone_meta_key"`
+}
+
 func (e *MeshConfigEntry) GetKind() string {
 	return MeshConfig
 }
@@ -64,6 +91,12 @@ func (e *MeshConfigEntry) Validate() error {
 	return e.validateEnterpriseMeta()
 }
 
+// IntentionsAuditMode returns whether intentions should be evaluated in audit
+// mode rather than enforced. It is safe to call on a nil entry.
+func (e *MeshConfigEntry) IntentionsAuditMode() bool {
+	return e != nil && e.Intentions.AuditMode
+}
+
 func (e *MeshConfigEntry) CanRead(authz acl.Authorizer) bool {
 	return true
 }
@@ -155,6 +155,43 @@ func TestConfigEntries_ACLs(t *testing.T) {
 				},
 			},
 		},
+		// =================== jwt-provider ===================
+		{
+			name: "jwt-provider",
+			entry: &JWTProviderConfigEntry{
+				Name:   "okta",
+				Issuer: "https://okta.example.com",
+				JSONWebKeySet: &JSONWebKeySet{
+					Local: &LocalJWKS{Filename: "/etc/jwks.json"},
+				},
+			},
+			expectACLs: []testACL{
+				{
+					name:       "no-authz",
+					authorizer: newAuthz(t, ``),
+					canRead:    true, // unauthenticated
+					canWrite:   false,
+				},
+				{
+					name:       "jwt-provider: mesh read",
+					authorizer: newAuthz(t, `mesh = "read"`),
+					canRead:    true, // unauthenticated
+					canWrite:   false,
+				},
+				{
+					name:       "jwt-provider: mesh write",
+					authorizer: newAuthz(t, `mesh = "write"`),
+					canRead:    true, // unauthenticated
+					canWrite:   true,
+				},
+				{
+					name:       "jwt-provider: operator write",
+					authorizer: newAuthz(t, `operator = "write"`),
+					canRead:    true, // unauthenticated
+					canWrite:   true,
+				},
+			},
+		},
 	}
 
 	testConfigEntries_ListRelatedServices_AndACLs(t, cases)
@@ -290,6 +327,42 @@ func TestDecodeConfigEntry(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "proxy-defaults with access logs",
+			snake: `
+				kind = "proxy-defaults"
+				name = "global"
+				access_logs {
+					enabled = true
+					disable_listener_logs = true
+					type = "file"
+					path = "/var/log/envoy.log"
+					text_format = "[%START_TIME%] %RESPONSE_CODE%"
+				}
+			`,
+			camel: `
+				Kind = "proxy-defaults"
+				Name = "global"
+				AccessLogs {
+					Enabled = true
+					DisableListenerLogs = true
+					Type = "file"
+					Path = "/var/log/envoy.log"
+					TextFormat = "[%START_TIME%] %RESPONSE_CODE%"
+				}
+			`,
+			expect: &ProxyConfigEntry{
+				Kind: "proxy-defaults",
+				Name: "global",
+				AccessLogs: AccessLogsConfig{
+					Enabled:             true,
+					DisableListenerLogs: true,
+					Type:                FileLogSinkType,
+					Path:                "/var/log/envoy.log",
+					TextFormat:          "[%START_TIME%] %RESPONSE_CODE%",
+				},
+			},
+		},
 		{
 			name: "service-defaults",
 			snake: `
@@ -484,6 +557,22 @@ func TestDecodeConfigEntry(t *testing.T) {
 								}
 								remove = ["qux"]
 							}
+							fault {
+								delay {
+									duration   = "2s"
+									percentage = 25
+								}
+								abort {
+									http_status = 503
+									percentage  = 5
+								}
+								header = [
+									{
+										name    = "x-chaos"
+										present = true
+									},
+								]
+							}
 						}
 					},
 					{
@@ -585,6 +674,22 @@ func TestDecodeConfigEntry(t *testing.T) {
 								}
 								Remove = ["qux"]
 							}
+							Fault {
+								Delay {
+									Duration   = "2s"
+									Percentage = 25
+								}
+								Abort {
+									HTTPStatus = 503
+									Percentage = 5
+								}
+								Header = [
+									{
+										Name    = "x-chaos"
+										Present = true
+									},
+								]
+							}
 						}
 					},
 					{
@@ -678,6 +783,22 @@ func TestDecodeConfigEntry(t *testing.T) {
 								Set:    map[string]string{"bar": "baz"},
 								Remove: []string{"qux"},
 							},
+							Fault: &ServiceRouteFault{
+								Delay: &ServiceRouteFaultDelay{
+									Duration:   2 * time.Second,
+									Percentage: 25,
+								},
+								Abort: &ServiceRouteFaultAbort{
+									HTTPStatus: 503,
+									Percentage: 5,
+								},
+								Header: []ServiceRouteHTTPMatchHeader{
+									{
+										Name:    "x-chaos",
+										Present: true,
+									},
+								},
+							},
 						},
 					},
 					{
@@ -1663,6 +1784,12 @@ func TestDecodeConfigEntry(t *testing.T) {
 				transparent_proxy {
 					mesh_destinations_only = true
 				}
+				intentions {
+					audit_mode = true
+				}
+				locality {
+					zone_meta_key = "zone"
+				}
 			`,
 			camel: `
 				Kind = "mesh"
@@ -1673,6 +1800,12 @@ func TestDecodeConfigEntry(t *testing.T) {
 				TransparentProxy {
 					MeshDestinationsOnly = true
 				}
+				Intentions {
+					AuditMode = true
+				}
+				Locality {
+					ZoneMetaKey = "zone"
+				}
 			`,
 			expect: &MeshConfigEntry{
 				Meta: map[string]string{
@@ -1682,6 +1815,12 @@ func TestDecodeConfigEntry(t *testing.T) {
 				TransparentProxy: TransparentProxyMeshConfig{
 					MeshDestinationsOnly: true,
 				},
+				Intentions: IntentionsMeshConfig{
+					AuditMode: true,
+				},
+				Locality: LocalityMeshConfig{
+					ZoneMetaKey: "zone",
+				},
 			},
 		},
 		{
@@ -1779,6 +1918,118 @@ func TestDecodeConfigEntry(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "jwt-provider",
+			snake: `
+				kind = "jwt-provider"
+				name = "okta"
+				meta {
+					"foo" = "bar"
+				}
+				issuer = "https://okta.example.com"
+				audiences = ["api"]
+				json_web_key_set {
+					remote {
+						uri = "https://okta.example.com/.well-known/jwks.json"
+						request_timeout_ms = 1500
+						cache_duration = "10m"
+						jwks_cluster {
+							discovery_type = "LOGICAL_DNS"
+							connect_timeout = "3s"
+							trusted_ca {
+								filename = "/etc/ssl/okta.pem"
+							}
+						}
+					}
+				}
+				forwarding {
+					header_name = "x-jwt-payload"
+					keep_token = true
+				}
+			`,
+			camel: `
+				Kind = "jwt-provider"
+				Name = "okta"
+				Meta {
+					"foo" = "bar"
+				}
+				Issuer = "https://okta.example.com"
+				Audiences = ["api"]
+				JSONWebKeySet {
+					Remote {
+						URI = "https://okta.example.com/.well-known/jwks.json"
+						RequestTimeoutMs = 1500
+						CacheDuration = "10m"
+						JWKSCluster {
+							DiscoveryType = "LOGICAL_DNS"
+							ConnectTimeout = "3s"
+							TrustedCA {
+								Filename = "/etc/ssl/okta.pem"
+							}
+						}
+					}
+				}
+				Forwarding {
+					HeaderName = "x-jwt-payload"
+					KeepToken = true
+				}
+			`,
+			expect: &JWTProviderConfigEntry{
+				Name: "okta",
+				Meta: map[string]string{
+					"foo": "bar",
+				},
+				Issuer:    "https://okta.example.com",
+				Audiences: []string{"api"},
+				JSONWebKeySet: &JSONWebKeySet{
+					Remote: &RemoteJWKS{
+						URI:              "https://okta.example.com/.well-known/jwks.json",
+						RequestTimeoutMs: 1500,
+						CacheDuration:    10 * time.Minute,
+						JWKSCluster: &JWKSCluster{
+							DiscoveryType:  DiscoveryTypeLogicalDNS,
+							ConnectTimeout: 3 * time.Second,
+							TrustedCA: &JWKSTrustedCA{
+								Filename: "/etc/ssl/okta.pem",
+							},
+						},
+					},
+				},
+				Forwarding: &JWTForwardingConfig{
+					HeaderName: "x-jwt-payload",
+					KeepToken:  true,
+				},
+			},
+		},
+		{
+			name: "canary",
+			snake: `
+				kind = "canary"
+				name = "web"
+				stable_subset = "v1"
+				canary_subset = "v2"
+				steps = [10, 50, 100]
+				step_interval = "5m"
+				health_checks = ["service:web-v2"]
+			`,
+			camel: `
+				Kind = "canary"
+				Name = "web"
+				StableSubset = "v1"
+				CanarySubset = "v2"
+				Steps = [10, 50, 100]
+				StepInterval = "5m"
+				HealthChecks = ["service:web-v2"]
+			`,
+			expect: &CanaryConfigEntry{
+				Name:         "web",
+				StableSubset: "v1",
+				CanarySubset: "v2",
+				Steps:        []float32{10, 50, 100},
+				StepInterval: 5 * time.Minute,
+				HealthChecks: []string{"service:web-v2"},
+			},
+		},
 	} {
 		tc := tc
 
@@ -2265,6 +2516,58 @@ func TestServiceConfigEntry(t *testing.T) {
 			},
 			validateErr: `error in upstream defaults: Name must be empty`,
 		},
+		"access logs path without file type": {
+			entry: &ServiceConfigEntry{
+				Name: "web",
+				AccessLogs: AccessLogsConfig{
+					Enabled: true,
+					Type:    StdOutLogSinkType,
+					Path:    "/tmp/access.log",
+				},
+			},
+			validateErr: `error in access logs: path is only valid for file type access logs`,
+		},
+		"access logs file type without path": {
+			entry: &ServiceConfigEntry{
+				Name: "web",
+				AccessLogs: AccessLogsConfig{
+					Enabled: true,
+					Type:    FileLogSinkType,
+				},
+			},
+			validateErr: `error in access logs: path must be specified when using file type access logs`,
+		},
+		"access logs invalid type": {
+			entry: &ServiceConfigEntry{
+				Name: "web",
+				AccessLogs: AccessLogsConfig{
+					Enabled: true,
+					Type:    "syslog",
+				},
+			},
+			validateErr: `error in access logs: invalid access log type "syslog"`,
+		},
+		"access logs both formats": {
+			entry: &ServiceConfigEntry{
+				Name: "web",
+				AccessLogs: AccessLogsConfig{
+					Enabled:    true,
+					JSONFormat: `{"method": "%REQ(:METHOD)%"}`,
+					TextFormat: "%REQ(:METHOD)%",
+				},
+			},
+			validateErr: `error in access logs: cannot specify both JSONFormat and TextFormat`,
+		},
+		"access logs invalid json format": {
+			entry: &ServiceConfigEntry{
+				Name: "web",
+				AccessLogs: AccessLogsConfig{
+					Enabled:    true,
+					JSONFormat: `{"method": `,
+				},
+			},
+			validateErr: `error in access logs: invalid JSONFormat`,
+		},
 		"connect-kitchen-sink": {
 			entry: &ServiceConfigEntry{
 				Kind: ServiceDefaults,
@@ -2310,6 +2613,44 @@ func TestServiceConfigEntry(t *testing.T) {
 	testConfigEntryNormalizeAndValidate(t, cases)
 }
 
+func TestProxyConfigEntry(t *testing.T) {
+	cases := map[string]configEntryTestcase{
+		"access logs to file": {
+			entry: &ProxyConfigEntry{
+				Name: ProxyConfigGlobal,
+				AccessLogs: AccessLogsConfig{
+					Enabled:    true,
+					Type:       FileLogSinkType,
+					Path:       "/var/log/envoy.log",
+					JSONFormat: `{"method": "%REQ(:METHOD)%"}`,
+				},
+			},
+			expected: &ProxyConfigEntry{
+				Name: ProxyConfigGlobal,
+				Kind: ProxyDefaults,
+				AccessLogs: AccessLogsConfig{
+					Enabled:    true,
+					Type:       FileLogSinkType,
+					Path:       "/var/log/envoy.log",
+					JSONFormat: `{"method": "%REQ(:METHOD)%"}`,
+				},
+				EnterpriseMeta: *DefaultEnterpriseMetaInDefaultPartition(),
+			},
+		},
+		"access logs file type without path": {
+			entry: &ProxyConfigEntry{
+				Name: ProxyConfigGlobal,
+				AccessLogs: AccessLogsConfig{
+					Enabled: true,
+					Type:    FileLogSinkType,
+				},
+			},
+			validateErr: `AccessLogs path must be specified when using file type access logs`,
+		},
+	}
+	testConfigEntryNormalizeAndValidate(t, cases)
+}
+
 func TestUpstreamConfig_MergeInto(t *testing.T) {
 	tt := []struct {
 		name        string
@@ -1,5 +1,7 @@
 package structs
 
+import "time"
+
 // ConnectAuthorizeRequest is the structure of a request to authorize
 // a connection.
 type ConnectAuthorizeRequest struct {
@@ -22,3 +24,27 @@ type ConnectAuthorizeRequest struct {
 func (req *ConnectAuthorizeRequest) TargetPartition() string {
 	return req.PartitionOrDefault()
 }
+
+// ConnectAuthorizeAuditDenial summarizes the connections from a source to a
+// destination service that intentions would have denied while they were in
+// audit mode.
+type ConnectAuthorizeAuditDenial struct {
+	SourceName      string
+	SourceNS        string `json:",omitempty"`
+	SourcePartition string `json:",omitempty"`
+
+	DestinationName      string
+	DestinationNS        string `json:",omitempty"`
+	DestinationPartition string `json:",omitempty"`
+
+	// Reason is why the last of the connections would have been denied.
+	Reason string
+
+	// Count is the number of connections that would have been denied.
+	Count uint64
+
+	// FirstDenied and LastDenied are when the first and the last of the
+	// connections were authorized.
+	FirstDenied time.Time
+	LastDenied  time.Time
+}
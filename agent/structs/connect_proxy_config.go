@@ -151,6 +151,85 @@ func (c *TransparentProxyConfig) IsZero() bool {
 	return *c == zeroVal
 }
 
+type LogSinkType string
+
+const (
+	// DefaultLogSinkType represents no specific sink and writes logs to
+	// stdout.
+	DefaultLogSinkType LogSinkType = ""
+	FileLogSinkType    LogSinkType = "file"
+	StdErrLogSinkType  LogSinkType = "stderr"
+	StdOutLogSinkType  LogSinkType = "stdout"
+)
+
+// AccessLogsConfig contains the associated default settings for all Envoy
+// instances within the datacenter or partition.
+type AccessLogsConfig struct {
+	// Enabled turns on all access logging.
+	Enabled bool `json:",omitempty"`
+
+	// DisableListenerLogs turns off just listener logs for connections
+	// rejected by Envoy because they don't have a matching listener filter.
+	DisableListenerLogs bool `json:",omitempty" alias:"disable_listener_logs"`
+
+	// Type selects the output for logs: "file", "stderr" or "stdout".
+	Type LogSinkType `json:",omitempty"`
+
+	// Path is the output file to write logs for the "file" type.
+	Path string `json:",omitempty"`
+
+	// The presence of one format string or the other implies the access log
+	// string encoding. Defining both is invalid.
+	JSONFormat string `json:",omitempty" alias:"json_format"`
+	TextFormat string `json:",omitempty" alias:"text_format"`
+}
+
+func (c *AccessLogsConfig) IsZero() bool {
+	zeroVal := AccessLogsConfig{}
+	return *c == zeroVal
+}
+
+func (c AccessLogsConfig) ToAPI() *api.AccessLogsConfig {
+	if c.IsZero() {
+		return nil
+	}
+	return &api.AccessLogsConfig{
+		Enabled:             c.Enabled,
+		DisableListenerLogs: c.DisableListenerLogs,
+		Type:                api.LogSinkType(c.Type),
+		Path:                c.Path,
+		JSONFormat:          c.JSONFormat,
+		TextFormat:          c.TextFormat,
+	}
+}
+
+func (c *AccessLogsConfig) Validate() error {
+	switch c.Type {
+	case DefaultLogSinkType, StdOutLogSinkType, StdErrLogSinkType:
+		if c.Path != "" {
+			return fmt.Errorf("path is only valid for file type access logs")
+		}
+	case FileLogSinkType:
+		if c.Path == "" {
+			return fmt.Errorf("path must be specified when using file type access logs")
+		}
+	default:
+		return fmt.Errorf("invalid access log type %q, must be one of %q, %q or %q",
+			c.Type, FileLogSinkType, StdErrLogSinkType, StdOutLogSinkType)
+	}
+
+	if c.JSONFormat != "" && c.TextFormat != "" {
+		return fmt.Errorf("cannot specify both JSONFormat and TextFormat")
+	}
+	if c.JSONFormat != "" {
+		var format map[string]interface{}
+		if err := json.Unmarshal([]byte(c.JSONFormat), &format); err != nil {
+			return fmt.Errorf("invalid JSONFormat: %v", err)
+		}
+	}
+	return nil
+}
+
 // ConnectProxyConfig describes the configuration needed for any proxy managed
 // or unmanaged. It describes a single logical service's listener and optionally
 // upstreams and sidecar-related config for a single instance. To describe a
@@ -205,6 +284,9 @@ type ConnectProxyConfig struct {
 	// TransparentProxy defines configuration for when the proxy is in
 	// transparent mode.
 	TransparentProxy TransparentProxyConfig `json:",omitempty" alias:"transparent_proxy"`
+
+	// AccessLogs configures the output and format of Envoy access logs
+	AccessLogs AccessLogsConfig `json:",omitempty" alias:"access_logs"`
 }
 
 func (t *ConnectProxyConfig) UnmarshalJSON(data []byte) (err error) {
@@ -217,6 +299,7 @@ func (t *ConnectProxyConfig) UnmarshalJSON(data []byte) (err error) {
 		LocalServiceSocketPathSnake string                 `json:"local_service_socket_path"`
 		MeshGatewaySnake            MeshGatewayConfig      `json:"mesh_gateway"`
 		TransparentProxySnake       TransparentProxyConfig `json:"transparent_proxy"`
+		AccessLogsSnake             AccessLogsConfig       `json:"access_logs"`
 		*Alias
 	}{
 		Alias: (*Alias)(t),
@@ -248,6 +331,9 @@ func (t *ConnectProxyConfig) UnmarshalJSON(data []byte) (err error) {
 	if !t.TransparentProxy.DialedDirectly {
 		t.TransparentProxy.DialedDirectly = aux.TransparentProxySnake.DialedDirectly
 	}
+	if t.AccessLogs.IsZero() {
+		t.AccessLogs = aux.AccessLogsSnake
+	}
 
 	return nil
 }
@@ -256,6 +342,7 @@ func (c *ConnectProxyConfig) MarshalJSON() ([]byte, error) {
 	type Alias ConnectProxyConfig
 	out := struct {
 		TransparentProxy *TransparentProxyConfig `json:",omitempty"`
+		AccessLogs       *AccessLogsConfig       `json:",omitempty"`
 		Alias
 	}{
 		Alias: (Alias)(*c),
@@ -270,6 +357,9 @@ func (c *ConnectProxyConfig) MarshalJSON() ([]byte, error) {
 	if !c.TransparentProxy.IsZero() {
 		out.TransparentProxy = &out.Alias.TransparentProxy
 	}
+	if !c.AccessLogs.IsZero() {
+		out.AccessLogs = &out.Alias.AccessLogs
+	}
 
 	return json.Marshal(&out)
 }
@@ -287,6 +377,7 @@ func (c *ConnectProxyConfig) ToAPI() *api.AgentServiceConnectProxyConfig {
 		LocalServiceSocketPath: c.LocalServiceSocketPath,
 		Mode:                   api.ProxyMode(c.Mode),
 		TransparentProxy:       c.TransparentProxy.ToAPI(),
+		AccessLogs:             c.AccessLogs.ToAPI(),
 		Config:                 c.Config,
 		Upstreams:              c.Upstreams.ToAPI(),
 		MeshGateway:            c.MeshGateway.ToAPI(),
@@ -62,6 +62,22 @@ func (c *CompiledDiscoveryChain) WillFailoverThroughMeshGateway(node *DiscoveryG
 	return false
 }
 
+// HasFaultInjection returns true if any route in the chain injects faults
+// into the requests it matches.
+func (c *CompiledDiscoveryChain) HasFaultInjection() bool {
+	for _, node := range c.Nodes {
+		if node.Type != DiscoveryGraphNodeTypeRouter {
+			continue
+		}
+		for _, route := range node.Routes {
+			if dest := route.Definition.Destination; dest != nil && dest.Fault != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // IsDefault returns true if the compiled chain represents no routing, no
 // splitting, and only the default resolution.  We have to be careful here to
 // avoid returning "yep this is default" when the only resolver action being
@@ -188,6 +204,10 @@ func (r *DiscoveryResolver) UnmarshalJSON(data []byte) error {
 type DiscoveryRoute struct {
 	Definition *ServiceRoute `json:",omitempty"`
 	NextNode   string        `json:",omitempty"`
+
+	// MirrorNode is the resolver node for the destination that receives a
+	// copy of the requests matching this route, if any.
+	MirrorNode string `json:",omitempty"`
 }
 
 // compiled form of ServiceSplit
@@ -201,6 +221,10 @@ type DiscoverySplit struct {
 	// to that leg of the split.
 	Weight   float32 `json:",omitempty"`
 	NextNode string  `json:",omitempty"`
+
+	// MirrorNode is the resolver node for the destination that receives a
+	// copy of the requests sent to this leg of the split, if any.
+	MirrorNode string `json:",omitempty"`
 }
 
 // compiled form of ServiceResolverFailover
@@ -70,6 +70,13 @@ type Intention struct {
 	// service-intentions config entry directly.
 	Permissions []*IntentionPermission `bexpr:"-" json:",omitempty"`
 
+	// JWT specifies a JWT that must be presented and verified for the
+	// intention to match.
+	//
+	// NOTE: This field is not editable unless editing the underlying
+	// service-intentions config entry directly.
+	JWT *IntentionJWTRequirement `bexpr:"-" json:",omitempty"`
+
 	// DefaultAddr is not used.
 	// Deprecated: DefaultAddr is not used and may be removed in a future version.
 	DefaultAddr string `bexpr:"-" codec:",omitempty" json:",omitempty"`
@@ -109,6 +116,9 @@ func (t *Intention) Clone() *Intention {
 			t2.Permissions = append(t2.Permissions, perm.Clone())
 		}
 	}
+	if t.JWT != nil {
+		t2.JWT = t.JWT.Clone()
+	}
 	t2.Meta = cloneStringStringMap(t.Meta)
 	t2.Hash = nil
 	return &t2
@@ -294,6 +304,11 @@ func (x *Intention) Validate() error {
 			"Permissions must not be set when using the legacy APIs"))
 	}
 
+	if x.JWT != nil {
+		result = multierror.Append(result, fmt.Errorf(
+			"JWT must not be set when using the legacy APIs"))
+	}
+
 	switch x.SourceType {
 	case IntentionSourceConsul:
 	default:
@@ -473,6 +488,7 @@ func (x *Intention) ToSourceIntention(legacy bool) *SourceIntention {
 	}
 	if !legacy {
 		src.Permissions = x.Permissions
+		src.JWT = x.JWT
 	}
 	return src
 }
@@ -681,6 +697,10 @@ func (q *IntentionQueryCheck) GetACLPrefix() (string, bool) {
 // IntentionQueryCheckResponse is the response for a test request.
 type IntentionQueryCheckResponse struct {
 	Allowed bool
+
+	// AuditMode is set when intentions are audited rather than enforced, a
+	// connection that isn't allowed is then let through and only logged.
+	AuditMode bool `json:",omitempty"`
 }
 
 // IntentionDecisionSummary contains a summary of a set of intentions between two services
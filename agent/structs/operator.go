@@ -64,6 +64,25 @@ func (op *RaftRemovePeerRequest) RequestDatacenter() string {
 	return op.Datacenter
 }
 
+// RaftLeaderTransferRequest is used by the Operator endpoint to transfer the
+// Raft leadership away from the current leader.
+type RaftLeaderTransferRequest struct {
+	// Datacenter is the target this request is intended for.
+	Datacenter string
+
+	// ID is the optional ID of the server to transfer the leadership to. Raft
+	// picks the most up to date voter when it is empty.
+	ID raft.ServerID
+
+	// WriteRequest holds the ACL token to go along with this request.
+	WriteRequest
+}
+
+// RequestDatacenter returns the datacenter for a given request.
+func (op *RaftLeaderTransferRequest) RequestDatacenter() string {
+	return op.Datacenter
+}
+
 // AutopilotSetConfigRequest is used by the Operator endpoint to update the
 // current Autopilot configuration of the cluster.
 type AutopilotSetConfigRequest struct {
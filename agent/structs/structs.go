@@ -73,6 +73,7 @@ const (
 	ServiceVirtualIPRequestType                 = 32
 	FreeVirtualIPRequestType                    = 33
 	KindServiceNamesType                        = 34
+	ACLTokenUsageSetRequestType                 = 35
 )
 
 // if a new request type is added above it must be
@@ -116,6 +117,7 @@ var requestTypeStrings = map[MessageType]string{
 	ServiceVirtualIPRequestType:     "ServiceVirtualIP",
 	FreeVirtualIPRequestType:        "FreeVirtualIP",
 	KindServiceNamesType:            "KindServiceName",
+	ACLTokenUsageSetRequestType:     "ACLTokenUsageSet",
 }
 
 const (
@@ -154,6 +156,11 @@ const (
 	// MetaExternalSource is the metadata key used when a resource is managed by a source outside Consul like nomad/k8s
 	MetaExternalSource = "external-source"
 
+	// MetaNodeTTL is the node metadata key holding the TTL of a node
+	// registered through the catalog. The node is deregistered along with its
+	// services and checks if it isn't registered again or renewed in time.
+	MetaNodeTTL = "consul-node-ttl"
+
 	// TaggedAddressVirtualIP is the key used to store tagged virtual IPs generated by Consul.
 	TaggedAddressVirtualIP = "consul-virtual"
 
@@ -2340,6 +2347,12 @@ const (
 	SessionTTLMultiplier = 2
 )
 
+const (
+	NodeTTLMin        = time.Second
+	NodeTTLMax        = 24 * time.Hour
+	NodeTTLMultiplier = 2
+)
+
 type Sessions []*Session
 
 // Session is used to represent an open session in the KV store.
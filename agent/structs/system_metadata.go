@@ -30,6 +30,7 @@ const (
 	SystemMetadataIntentionFormatLegacyValue   = "legacy"
 	SystemMetadataVirtualIPsEnabled            = "virtual-ips"
 	SystemMetadataTermGatewayVirtualIPsEnabled = "virtual-ips-term-gateway"
+	SystemMetadataLoginChallengeKey            = "acl-login-challenge-key"
 )
 
 type SystemMetadataEntry struct {
@@ -0,0 +1,59 @@
+package structs
+
+import (
+	"bytes"
+
+	"github.com/hashicorp/go-msgpack/codec"
+)
+
+// UserEventMaxVersion is the maximum protocol version of the user events we
+// understand.
+const UserEventMaxVersion = 1
+
+// UserEvent is the payload of the user events sent through Serf. It is fired
+// by the agents, and by the servers for the events they fire themselves.
+type UserEvent struct {
+	// ID of the user event. Automatically generated.
+	ID string
+
+	// Name of the event
+	Name string `codec:"n"`
+
+	// Optional payload
+	Payload []byte `codec:"p,omitempty"`
+
+	// NodeFilter is a regular expression to filter on nodes
+	NodeFilter string `codec:"nf,omitempty"`
+
+	// ServiceFilter is a regular expression to filter on services
+	ServiceFilter string `codec:"sf,omitempty"`
+
+	// TagFilter is a regular expression to filter on tags of a service,
+	// must be provided with ServiceFilter
+	TagFilter string `codec:"tf,omitempty"`
+
+	// Version of the user event. Automatically generated.
+	Version int `codec:"v"`
+
+	// LTime is the lamport time. Automatically generated.
+	LTime uint64 `codec:"-"`
+}
+
+// msgpackHandleUserEvent is a shared handle for encoding/decoding of
+// messages for user events
+var msgpackHandleUserEvent = &codec.MsgpackHandle{
+	RawToString: true,
+	WriteExt:    true,
+}
+
+// DecodeMsgPackUserEvent is used to decode a MsgPack encoded user event
+func DecodeMsgPackUserEvent(buf []byte, out interface{}) error {
+	return codec.NewDecoder(bytes.NewReader(buf), msgpackHandleUserEvent).Decode(out)
+}
+
+// EncodeMsgPackUserEvent is used to encode a user event with msgpack
+func EncodeMsgPackUserEvent(msg interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	err := codec.NewEncoder(&buf, msgpackHandleUserEvent).Encode(msg)
+	return buf.Bytes(), err
+}
@@ -1,11 +1,9 @@
 package agent
 
 import (
-	"bytes"
 	"fmt"
 	"regexp"
 
-	"github.com/hashicorp/go-msgpack/codec"
 	"github.com/hashicorp/go-uuid"
 
 	"github.com/hashicorp/consul/agent/structs"
@@ -13,39 +11,15 @@ import (
 
 const (
 	// userEventMaxVersion is the maximum protocol version we understand
-	userEventMaxVersion = 1
+	userEventMaxVersion = structs.UserEventMaxVersion
 
 	// remoteExecName is the event name for a remote exec command
 	remoteExecName = "_rexec"
 )
 
-// UserEventParam is used to parameterize a user event
-type UserEvent struct {
-	// ID of the user event. Automatically generated.
-	ID string
-
-	// Name of the event
-	Name string `codec:"n"`
-
-	// Optional payload
-	Payload []byte `codec:"p,omitempty"`
-
-	// NodeFilter is a regular expression to filter on nodes
-	NodeFilter string `codec:"nf,omitempty"`
-
-	// ServiceFilter is a regular expression to filter on services
-	ServiceFilter string `codec:"sf,omitempty"`
-
-	// TagFilter is a regular expression to filter on tags of a service,
-	// must be provided with ServiceFilter
-	TagFilter string `codec:"tf,omitempty"`
-
-	// Version of the user event. Automatically generated.
-	Version int `codec:"v"`
-
-	// LTime is the lamport time. Automatically generated.
-	LTime uint64 `codec:"-"`
-}
+// UserEvent is used to parameterize a user event, its wire format is shared
+// with the servers.
+type UserEvent = structs.UserEvent
 
 // validateUserEventParams is used to sanity check the inputs
 func validateUserEventParams(params *UserEvent) error {
@@ -285,21 +259,12 @@ func (a *Agent) LastUserEvent() *UserEvent {
 	return a.eventBuf[idx]
 }
 
-// msgpackHandleUserEvent is a shared handle for encoding/decoding of
-// messages for user events
-var msgpackHandleUserEvent = &codec.MsgpackHandle{
-	RawToString: true,
-	WriteExt:    true,
-}
-
 // decodeMsgPackUserEvent is used to decode a MsgPack encoded object
 func decodeMsgPackUserEvent(buf []byte, out interface{}) error {
-	return codec.NewDecoder(bytes.NewReader(buf), msgpackHandleUserEvent).Decode(out)
+	return structs.DecodeMsgPackUserEvent(buf, out)
 }
 
 // encodeMsgPackUserEvent is used to encode an object with msgpack
 func encodeMsgPackUserEvent(msg interface{}) ([]byte, error) {
-	var buf bytes.Buffer
-	err := codec.NewEncoder(&buf, msgpackHandleUserEvent).Encode(msg)
-	return buf.Bytes(), err
+	return structs.EncodeMsgPackUserEvent(msg)
 }
@@ -0,0 +1,82 @@
+package xds
+
+import (
+	"fmt"
+
+	envoy_accesslog_v3 "github.com/envoyproxy/go-control-plane/envoy/config/accesslog/v3"
+	envoy_fileaccesslog_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/access_loggers/file/v3"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/ptypes"
+	pstruct "github.com/golang/protobuf/ptypes/struct"
+
+	"github.com/hashicorp/consul/agent/structs"
+)
+
+const (
+	fileAccessLogName = "envoy.access_loggers.file"
+
+	// The file access logger in this version of the data plane API has no
+	// dedicated stream sinks, so standard output and error are written to as
+	// files instead.
+	stdoutAccessLogPath = "/dev/stdout"
+	stderrAccessLogPath = "/dev/stderr"
+
+	// noRouteResponseFlag is the response flag set by Envoy on connections
+	// that are rejected because they did not match any filter chain.
+	noRouteResponseFlag = "NR"
+)
+
+// makeAccessLogs returns the Envoy access log configuration for the given
+// config, or nil if access logs are not enabled. Listener logs only record
+// connections that were rejected because they did not match any filter
+// chain, since all others are logged by the filters themselves.
+func makeAccessLogs(logs *structs.AccessLogsConfig, isListener bool) ([]*envoy_accesslog_v3.AccessLog, error) {
+	if logs == nil || !logs.Enabled {
+		return nil, nil
+	}
+	if isListener && logs.DisableListenerLogs {
+		return nil, nil
+	}
+
+	fileLog := &envoy_fileaccesslog_v3.FileAccessLog{}
+	switch logs.Type {
+	case structs.FileLogSinkType:
+		fileLog.Path = logs.Path
+	case structs.StdErrLogSinkType:
+		fileLog.Path = stderrAccessLogPath
+	default:
+		fileLog.Path = stdoutAccessLogPath
+	}
+
+	switch {
+	case logs.JSONFormat != "":
+		var format pstruct.Struct
+		if err := jsonpb.UnmarshalString(logs.JSONFormat, &format); err != nil {
+			return nil, fmt.Errorf("failed to parse JSONFormat for access logs: %v", err)
+		}
+		fileLog.AccessLogFormat = &envoy_fileaccesslog_v3.FileAccessLog_JsonFormat{JsonFormat: &format}
+	case logs.TextFormat != "":
+		fileLog.AccessLogFormat = &envoy_fileaccesslog_v3.FileAccessLog_Format{Format: logs.TextFormat}
+	}
+
+	any, err := ptypes.MarshalAny(fileLog)
+	if err != nil {
+		return nil, err
+	}
+
+	accessLog := &envoy_accesslog_v3.AccessLog{
+		Name:       fileAccessLogName,
+		ConfigType: &envoy_accesslog_v3.AccessLog_TypedConfig{TypedConfig: any},
+	}
+	if isListener {
+		accessLog.Filter = &envoy_accesslog_v3.AccessLogFilter{
+			FilterSpecifier: &envoy_accesslog_v3.AccessLogFilter_ResponseFlagFilter{
+				ResponseFlagFilter: &envoy_accesslog_v3.ResponseFlagFilter{
+					Flags: []string{noRouteResponseFlag},
+				},
+			},
+		}
+	}
+
+	return []*envoy_accesslog_v3.AccessLog{accessLog}, nil
+}
@@ -3,7 +3,9 @@ package xds
 import (
 	"errors"
 	"fmt"
+	"net/url"
 	"sort"
+	"strconv"
 	"time"
 
 	envoy_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
@@ -114,6 +116,20 @@ func (s *ResourceGenerator) clustersFromSnapshotConnectProxy(cfgSnap *proxycfg.C
 		clusters = append(clusters, upstreamCluster)
 	}
 
+	// Add clusters used by the jwt_authn filter to fetch remote JWKS.
+	for _, name := range referencedJWTProviders(cfgSnap.ConnectProxy.Intentions) {
+		provider, ok := cfgSnap.ConnectProxy.JWTProviders[name]
+		if !ok || provider.JSONWebKeySet == nil || provider.JSONWebKeySet.Remote == nil {
+			continue
+		}
+		c, err := makeJWKSCluster(provider)
+		if err != nil {
+			s.Logger.Warn("failed to make JWKS cluster", "provider", name, "error", err)
+			continue
+		}
+		clusters = append(clusters, c)
+	}
+
 	cfgSnap.Proxy.Expose.Finalize()
 	paths := cfgSnap.Proxy.Expose.Paths
 
@@ -149,6 +165,102 @@ func makeExposeClusterName(destinationPort int) string {
 	return fmt.Sprintf("exposed_cluster_%d", destinationPort)
 }
 
+// systemTrustedCAFile is the CA bundle used to verify the servers serving
+// remote JWKS when the jwt-provider does not configure one. It is where most
+// distributions, including the Envoy images, install the system bundle.
+const systemTrustedCAFile = "/etc/ssl/certs/ca-certificates.crt"
+
+// makeJWKSCluster returns the cluster used to fetch the remote JWKS of a
+// jwt-provider. Its single endpoint is the host of the JWKS URI.
+func makeJWKSCluster(p *structs.JWTProviderConfigEntry) (*envoy_cluster_v3.Cluster, error) {
+	remote := p.JSONWebKeySet.Remote
+	u, err := url.Parse(remote.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	port := 80
+	if u.Scheme == "https" {
+		port = 443
+	}
+	if u.Port() != "" {
+		port, err = strconv.Atoi(u.Port())
+		if err != nil {
+			return nil, fmt.Errorf("invalid port in JWKS URI %q: %v", remote.URI, err)
+		}
+	}
+
+	discoveryType := envoy_cluster_v3.Cluster_STRICT_DNS
+	connectTimeout := 5 * time.Second
+	if c := remote.JWKSCluster; c != nil {
+		switch c.DiscoveryType {
+		case structs.DiscoveryTypeStatic:
+			discoveryType = envoy_cluster_v3.Cluster_STATIC
+		case structs.DiscoveryTypeLogicalDNS:
+			discoveryType = envoy_cluster_v3.Cluster_LOGICAL_DNS
+		}
+		if c.ConnectTimeout > 0 {
+			connectTimeout = c.ConnectTimeout
+		}
+	}
+
+	name := jwksClusterPrefix + p.Name
+	cluster := &envoy_cluster_v3.Cluster{
+		Name:                 name,
+		ConnectTimeout:       ptypes.DurationProto(connectTimeout),
+		ClusterDiscoveryType: &envoy_cluster_v3.Cluster_Type{Type: discoveryType},
+		LoadAssignment: &envoy_endpoint_v3.ClusterLoadAssignment{
+			ClusterName: name,
+			Endpoints: []*envoy_endpoint_v3.LocalityLbEndpoints{
+				{
+					LbEndpoints: []*envoy_endpoint_v3.LbEndpoint{
+						makeEndpoint(u.Hostname(), port),
+					},
+				},
+			},
+		},
+	}
+
+	if u.Scheme == "https" {
+		trustedCA := &envoy_core_v3.DataSource{
+			Specifier: &envoy_core_v3.DataSource_Filename{Filename: systemTrustedCAFile},
+		}
+		if c := remote.JWKSCluster; c != nil && c.TrustedCA != nil {
+			if c.TrustedCA.Filename != "" {
+				trustedCA.Specifier = &envoy_core_v3.DataSource_Filename{Filename: c.TrustedCA.Filename}
+			} else {
+				trustedCA.Specifier = &envoy_core_v3.DataSource_InlineString{InlineString: c.TrustedCA.InlineString}
+			}
+		}
+
+		// Envoy does not verify the certificate of upstreams unless it is
+		// given a validation context so the server must present a
+		// certificate for the host of the URI, signed by a trusted CA.
+		transportSocket, err := makeUpstreamTLSTransportSocket(&envoy_tls_v3.UpstreamTlsContext{
+			CommonTlsContext: &envoy_tls_v3.CommonTlsContext{
+				ValidationContextType: &envoy_tls_v3.CommonTlsContext_ValidationContext{
+					ValidationContext: &envoy_tls_v3.CertificateValidationContext{
+						TrustedCa: trustedCA,
+						MatchSubjectAltNames: []*envoy_matcher_v3.StringMatcher{
+							{
+								MatchPattern: &envoy_matcher_v3.StringMatcher_Exact{
+									Exact: u.Hostname(),
+								},
+							},
+						},
+					},
+				},
+			},
+			Sni: u.Hostname(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		cluster.TransportSocket = transportSocket
+	}
+	return cluster, nil
+}
+
 // In transparent proxy mode there are potentially multiple passthrough clusters added.
 // The first is for destinations outside of Consul's catalog. This is for a plain TCP proxy.
 // All of these use Envoy's ORIGINAL_DST listener filter, which forwards to the original
@@ -51,6 +51,25 @@ func TestClustersFromSnapshot(t *testing.T) {
 					})
 			},
 		},
+		{
+			name:   "jwt-provider-remote-jwks",
+			create: proxycfg.TestConfigSnapshot,
+			setup: func(snap *proxycfg.ConfigSnapshot) {
+				snap.Proxy.Config["protocol"] = "http"
+				setupJWTProviderIntentions(snap)
+			},
+		},
+		{
+			name:   "jwt-provider-remote-jwks-trusted-ca",
+			create: proxycfg.TestConfigSnapshot,
+			setup: func(snap *proxycfg.ConfigSnapshot) {
+				snap.Proxy.Config["protocol"] = "http"
+				setupJWTProviderIntentions(snap)
+				snap.ConnectProxy.JWTProviders["okta"].JSONWebKeySet.Remote.JWKSCluster = &structs.JWKSCluster{
+					TrustedCA: &structs.JWKSTrustedCA{Filename: "/etc/ssl/okta.pem"},
+				}
+			},
+		},
 		{
 			name:   "custom-upstream",
 			create: proxycfg.TestConfigSnapshot,
@@ -221,6 +240,11 @@ func TestClustersFromSnapshot(t *testing.T) {
 			create: proxycfg.TestConfigSnapshotDiscoveryChain_SplitterWithResolverRedirectMultiDC,
 			setup:  nil,
 		},
+		{
+			name:   "connect-proxy-with-chain-and-mirror",
+			create: proxycfg.TestConfigSnapshotDiscoveryChainWithMirror,
+			setup:  nil,
+		},
 		{
 			name:   "connect-proxy-lb-in-resolver",
 			create: proxycfg.TestConfigSnapshotDiscoveryChainWithLB,
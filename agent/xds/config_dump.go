@@ -0,0 +1,82 @@
+package xds
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/hashicorp/consul/agent/proxycfg"
+)
+
+// ConfigDump is the full set of xDS resources generated for a single proxy,
+// with each resource encoded as the JSON form of its protobuf message.
+type ConfigDump struct {
+	Listeners []json.RawMessage
+	Routes    []json.RawMessage
+	Clusters  []json.RawMessage
+	Endpoints []json.RawMessage
+}
+
+// MakeConfigDump runs the snapshot through the same resource generators used
+// by the xDS server and returns the resulting resources. This shows exactly
+// what Consul intends to push to the proxy without requiring a connected
+// Envoy. Resources of each type are sorted by name.
+func MakeConfigDump(
+	logger hclog.Logger,
+	checkFetcher HTTPCheckFetcher,
+	cfgFetcher ConfigFetcher,
+	cfgSnap *proxycfg.ConfigSnapshot,
+) (*ConfigDump, error) {
+	g := newResourceGenerator(logger, checkFetcher, cfgFetcher, false)
+
+	all, err := g.allResourcesFromSnapshot(cfgSnap)
+	if err != nil {
+		return nil, err
+	}
+
+	var dump ConfigDump
+	for typeUrl, dst := range map[string]*[]json.RawMessage{
+		ListenerType: &dump.Listeners,
+		RouteType:    &dump.Routes,
+		ClusterType:  &dump.Clusters,
+		EndpointType: &dump.Endpoints,
+	} {
+		*dst, err = resourcesToJSON(all[typeUrl])
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode xDS resources for %q: %v", typeUrl, err)
+		}
+	}
+	return &dump, nil
+}
+
+func resourcesToJSON(resources []proto.Message) ([]json.RawMessage, error) {
+	sort.SliceStable(resources, func(i, j int) bool {
+		return resourceName(resources[i]) < resourceName(resources[j])
+	})
+
+	m := jsonpb.Marshaler{}
+	out := make([]json.RawMessage, 0, len(resources))
+	for _, res := range resources {
+		encoded, err := m.MarshalToString(res)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, json.RawMessage(encoded))
+	}
+	return out, nil
+}
+
+// resourceName returns the name xDS uses to identify a resource.
+func resourceName(res proto.Message) string {
+	switch v := res.(type) {
+	case interface{ GetClusterName() string }:
+		return v.GetClusterName()
+	case interface{ GetName() string }:
+		return v.GetName()
+	}
+	return ""
+}
@@ -0,0 +1,45 @@
+package xds
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent/proxycfg"
+	"github.com/hashicorp/consul/sdk/testutil"
+)
+
+func TestMakeConfigDump(t *testing.T) {
+	snap := proxycfg.TestConfigSnapshot(t)
+	setupTLSRootsAndLeaf(t, snap)
+
+	dump, err := MakeConfigDump(testutil.Logger(t), nil, nil, snap)
+	require.NoError(t, err)
+
+	names := func(resources []json.RawMessage, field string) []string {
+		var out []string
+		for _, res := range resources {
+			var decoded map[string]interface{}
+			require.NoError(t, json.Unmarshal(res, &decoded))
+			out = append(out, decoded[field].(string))
+		}
+		return out
+	}
+
+	require.Equal(t, []string{
+		"db:127.0.0.1:9191",
+		"prepared_query:geo-cache:127.10.10.10:8181",
+		"public_listener:0.0.0.0:9999",
+	}, names(dump.Listeners, "name"))
+	require.Equal(t, []string{
+		"db.default.dc1.internal.11111111-2222-3333-4444-555555555555.consul",
+		"geo-cache.default.dc1.query.11111111-2222-3333-4444-555555555555.consul",
+		"local_app",
+	}, names(dump.Clusters, "name"))
+	require.Equal(t, []string{
+		"db.default.dc1.internal.11111111-2222-3333-4444-555555555555.consul",
+		"geo-cache.default.dc1.query.11111111-2222-3333-4444-555555555555.consul",
+	}, names(dump.Endpoints, "clusterName"))
+	require.Empty(t, dump.Routes)
+}
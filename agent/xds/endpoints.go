@@ -3,6 +3,7 @@ package xds
 import (
 	"errors"
 	"fmt"
+	"sort"
 
 	envoy_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
 	envoy_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
@@ -47,6 +48,8 @@ func (s *ResourceGenerator) endpointsFromSnapshotConnectProxy(cfgSnap *proxycfg.
 	resources := make([]proto.Message, 0,
 		len(cfgSnap.ConnectProxy.PreparedQueryEndpoints)+len(cfgSnap.ConnectProxy.WatchedUpstreamEndpoints))
 
+	zones := makeZoneLocality(cfgSnap)
+
 	for uid, chain := range cfgSnap.ConnectProxy.DiscoveryChain {
 		upstreamCfg := cfgSnap.ConnectProxy.UpstreamConfig[uid]
 
@@ -63,6 +66,7 @@ func (s *ResourceGenerator) endpointsFromSnapshotConnectProxy(cfgSnap *proxycfg.
 			upstreamCfg,
 			cfgSnap.ConnectProxy.WatchedUpstreamEndpoints[uid],
 			cfgSnap.ConnectProxy.WatchedGatewayEndpoints[uid],
+			zones,
 		)
 		resources = append(resources, es...)
 	}
@@ -88,6 +92,7 @@ func (s *ResourceGenerator) endpointsFromSnapshotConnectProxy(cfgSnap *proxycfg.
 					{Endpoints: endpoints},
 				},
 				cfgSnap.Locality,
+				zones,
 			)
 			resources = append(resources, la)
 		}
@@ -192,6 +197,7 @@ func (s *ResourceGenerator) endpointsFromSnapshotMeshGateway(cfgSnap *proxycfg.C
 					{Endpoints: endpoints},
 				},
 				cfgSnap.Locality,
+				nil,
 			)
 			resources = append(resources, la)
 		}
@@ -207,6 +213,7 @@ func (s *ResourceGenerator) endpointsFromSnapshotMeshGateway(cfgSnap *proxycfg.C
 					{Endpoints: endpoints},
 				},
 				cfgSnap.Locality,
+				nil,
 			)
 			resources = append(resources, la)
 		}
@@ -308,6 +315,7 @@ func (s *ResourceGenerator) endpointsFromServicesAndResolvers(
 				clusterName,
 				groups,
 				cfgSnap.Locality,
+				nil,
 			)
 			resources = append(resources, la)
 		}
@@ -336,6 +344,7 @@ func (s *ResourceGenerator) endpointsFromSnapshotIngressGateway(cfgSnap *proxycf
 				&u,
 				cfgSnap.IngressGateway.WatchedUpstreamEndpoints[uid],
 				cfgSnap.IngressGateway.WatchedGatewayEndpoints[uid],
+				nil,
 			)
 			resources = append(resources, es...)
 			createdClusters[uid] = true
@@ -372,6 +381,7 @@ func (s *ResourceGenerator) endpointsFromDiscoveryChain(
 	upstream *structs.Upstream,
 	upstreamEndpoints map[string]structs.CheckServiceNodes,
 	gatewayEndpoints map[string]structs.CheckServiceNodes,
+	zones *zoneLocality,
 ) []proto.Message {
 	var resources []proto.Message
 
@@ -479,6 +489,7 @@ func (s *ResourceGenerator) endpointsFromDiscoveryChain(
 			clusterName,
 			endpointGroups,
 			gatewayKey,
+			zones,
 		)
 		resources = append(resources, la)
 	}
@@ -492,7 +503,80 @@ type loadAssignmentEndpointGroup struct {
 	OverrideHealth envoy_core_v3.HealthStatus
 }
 
-func makeLoadAssignment(clusterName string, endpointGroups []loadAssignmentEndpointGroup, localKey proxycfg.GatewayKey) *envoy_endpoint_v3.ClusterLoadAssignment {
+// zoneLocality describes how upstream endpoints are grouped into Envoy
+// localities by zone so that a proxy prefers instances in its own zone.
+type zoneLocality struct {
+	// MetaKey is the service or node meta key holding the zone of an
+	// instance.
+	MetaKey string
+
+	// LocalZone is the zone of the proxy itself.
+	LocalZone string
+}
+
+// makeZoneLocality returns the zone locality configuration for the proxy, or
+// nil if locality-aware load balancing is disabled in the mesh config entry
+// or the zone of the proxy is unknown.
+func makeZoneLocality(cfgSnap *proxycfg.ConfigSnapshot) *zoneLocality {
+	meshConf := cfgSnap.ConnectProxy.MeshConfig
+	if meshConf == nil || meshConf.Locality.ZoneMetaKey == "" {
+		return nil
+	}
+	key := meshConf.Locality.ZoneMetaKey
+
+	localZone := cfgSnap.ServiceMeta[key]
+	if localZone == "" {
+		localZone = cfgSnap.NodeMeta[key]
+	}
+	if localZone == "" {
+		return nil
+	}
+	return &zoneLocality{MetaKey: key, LocalZone: localZone}
+}
+
+// zoneOf returns the zone of the endpoint, preferring the service meta over
+// the node meta.
+func (z *zoneLocality) zoneOf(ep structs.CheckServiceNode) string {
+	if zone := ep.Service.Meta[z.MetaKey]; zone != "" {
+		return zone
+	}
+	if ep.Node != nil {
+		return ep.Node.Meta[z.MetaKey]
+	}
+	return ""
+}
+
+// splitByZone splits the endpoints into those in the local zone and those in
+// each other zone, keyed and sorted by zone name.
+func (z *zoneLocality) splitByZone(endpoints structs.CheckServiceNodes) (structs.CheckServiceNodes, []string, map[string]structs.CheckServiceNodes) {
+	var local structs.CheckServiceNodes
+	remote := make(map[string]structs.CheckServiceNodes)
+	for _, ep := range endpoints {
+		zone := z.zoneOf(ep)
+		if zone == z.LocalZone {
+			local = append(local, ep)
+		} else {
+			remote[zone] = append(remote[zone], ep)
+		}
+	}
+
+	zones := make([]string, 0, len(remote))
+	for zone := range remote {
+		zones = append(zones, zone)
+	}
+	sort.Strings(zones)
+	return local, zones, remote
+}
+
+// makeLoadAssignment returns the load assignment for the given endpoint
+// groups, where each group is at a lower priority than the previous one.
+//
+// If zones is not nil, the endpoints of each group are further split into
+// localities by zone. Endpoints in the local zone keep the priority of their
+// group and the endpoints of all other zones are placed at the next priority.
+// Envoy then only sends traffic to other zones once the share of healthy
+// local endpoints drops below its overprovisioning threshold.
+func makeLoadAssignment(clusterName string, endpointGroups []loadAssignmentEndpointGroup, localKey proxycfg.GatewayKey, zones *zoneLocality) *envoy_endpoint_v3.ClusterLoadAssignment {
 	cla := &envoy_endpoint_v3.ClusterLoadAssignment{
 		ClusterName: clusterName,
 		Endpoints:   make([]*envoy_endpoint_v3.LocalityLbEndpoints, 0, len(endpointGroups)),
@@ -506,37 +590,69 @@ func makeLoadAssignment(clusterName string, endpointGroups []loadAssignmentEndpo
 		}
 	}
 
-	for priority, endpointGroup := range endpointGroups {
-		endpoints := endpointGroup.Endpoints
-		es := make([]*envoy_endpoint_v3.LbEndpoint, 0, len(endpoints))
-
-		for _, ep := range endpoints {
-			// TODO (mesh-gateway) - should we respect the translate_wan_addrs configuration here or just always use the wan for cross-dc?
-			addr, port := ep.BestAddress(!localKey.Matches(ep.Node.Datacenter, ep.Node.PartitionOrDefault()))
-			healthStatus, weight := calculateEndpointHealthAndWeight(ep, endpointGroup.OnlyPassing)
+	var priority uint32
+	for _, endpointGroup := range endpointGroups {
+		// Groups that route through mesh gateways contain the gateway
+		// endpoints, so their zones are not meaningful.
+		if zones == nil || endpointGroup.OverrideHealth != envoy_core_v3.HealthStatus_UNKNOWN {
+			cla.Endpoints = append(cla.Endpoints, &envoy_endpoint_v3.LocalityLbEndpoints{
+				Priority:    priority,
+				LbEndpoints: makeLbEndpoints(endpointGroup, endpointGroup.Endpoints, localKey),
+			})
+			priority++
+			continue
+		}
 
-			if endpointGroup.OverrideHealth != envoy_core_v3.HealthStatus_UNKNOWN {
-				healthStatus = endpointGroup.OverrideHealth
+		local, remoteZones, remote := zones.splitByZone(endpointGroup.Endpoints)
+		if len(local) > 0 {
+			cla.Endpoints = append(cla.Endpoints, &envoy_endpoint_v3.LocalityLbEndpoints{
+				Locality:    &envoy_core_v3.Locality{Zone: zones.LocalZone},
+				Priority:    priority,
+				LbEndpoints: makeLbEndpoints(endpointGroup, local, localKey),
+			})
+			if len(remote) > 0 {
+				priority++
+			}
+		}
+		for _, zone := range remoteZones {
+			es := &envoy_endpoint_v3.LocalityLbEndpoints{
+				Priority:    priority,
+				LbEndpoints: makeLbEndpoints(endpointGroup, remote[zone], localKey),
+			}
+			if zone != "" {
+				es.Locality = &envoy_core_v3.Locality{Zone: zone}
 			}
+			cla.Endpoints = append(cla.Endpoints, es)
+		}
+		priority++
+	}
 
-			es = append(es, &envoy_endpoint_v3.LbEndpoint{
-				HostIdentifier: &envoy_endpoint_v3.LbEndpoint_Endpoint{
-					Endpoint: &envoy_endpoint_v3.Endpoint{
-						Address: makeAddress(addr, port),
-					},
-				},
-				HealthStatus:        healthStatus,
-				LoadBalancingWeight: makeUint32Value(weight),
-			})
+	return cla
+}
+
+func makeLbEndpoints(endpointGroup loadAssignmentEndpointGroup, endpoints structs.CheckServiceNodes, localKey proxycfg.GatewayKey) []*envoy_endpoint_v3.LbEndpoint {
+	es := make([]*envoy_endpoint_v3.LbEndpoint, 0, len(endpoints))
+
+	for _, ep := range endpoints {
+		// TODO (mesh-gateway) - should we respect the translate_wan_addrs configuration here or just always use the wan for cross-dc?
+		addr, port := ep.BestAddress(!localKey.Matches(ep.Node.Datacenter, ep.Node.PartitionOrDefault()))
+		healthStatus, weight := calculateEndpointHealthAndWeight(ep, endpointGroup.OnlyPassing)
+
+		if endpointGroup.OverrideHealth != envoy_core_v3.HealthStatus_UNKNOWN {
+			healthStatus = endpointGroup.OverrideHealth
 		}
 
-		cla.Endpoints = append(cla.Endpoints, &envoy_endpoint_v3.LocalityLbEndpoints{
-			Priority:    uint32(priority),
-			LbEndpoints: es,
+		es = append(es, &envoy_endpoint_v3.LbEndpoint{
+			HostIdentifier: &envoy_endpoint_v3.LbEndpoint_Endpoint{
+				Endpoint: &envoy_endpoint_v3.Endpoint{
+					Address: makeAddress(addr, port),
+				},
+			},
+			HealthStatus:        healthStatus,
+			LoadBalancingWeight: makeUint32Value(weight),
 		})
 	}
-
-	return cla
+	return es
 }
 
 func makeLoadAssignmentEndpointGroup(
@@ -93,11 +93,19 @@ func Test_makeLoadAssignment(t *testing.T) {
 	testWarningCheckServiceNodes[0].Checks[0].Status = "warning"
 	testWarningCheckServiceNodes[1].Checks[0].Status = "warning"
 
+	testZonedCheckServiceNodesRaw, err := copystructure.Copy(testCheckServiceNodes)
+	require.NoError(t, err)
+	testZonedCheckServiceNodes := testZonedCheckServiceNodesRaw.(structs.CheckServiceNodes)
+
+	testZonedCheckServiceNodes[0].Node.Meta = map[string]string{"zone": "us-east-1b"}
+	testZonedCheckServiceNodes[1].Service.Meta = map[string]string{"zone": "us-east-1a"}
+
 	// TODO(rb): test onlypassing
 	tests := []struct {
 		name        string
 		clusterName string
 		endpoints   []loadAssignmentEndpointGroup
+		zones       *zoneLocality
 		want        *envoy_endpoint_v3.ClusterLoadAssignment
 	}{
 		{
@@ -203,6 +211,47 @@ func Test_makeLoadAssignment(t *testing.T) {
 				}},
 			},
 		},
+		{
+			name:        "instances, zones",
+			clusterName: "service:test",
+			endpoints: []loadAssignmentEndpointGroup{
+				{Endpoints: testZonedCheckServiceNodes},
+			},
+			zones: &zoneLocality{MetaKey: "zone", LocalZone: "us-east-1a"},
+			want: &envoy_endpoint_v3.ClusterLoadAssignment{
+				ClusterName: "service:test",
+				Endpoints: []*envoy_endpoint_v3.LocalityLbEndpoints{
+					{
+						Locality: &envoy_core_v3.Locality{Zone: "us-east-1a"},
+						Priority: 0,
+						LbEndpoints: []*envoy_endpoint_v3.LbEndpoint{
+							{
+								HostIdentifier: &envoy_endpoint_v3.LbEndpoint_Endpoint{
+									Endpoint: &envoy_endpoint_v3.Endpoint{
+										Address: makeAddress("10.10.10.20", 1234),
+									}},
+								HealthStatus:        envoy_core_v3.HealthStatus_HEALTHY,
+								LoadBalancingWeight: makeUint32Value(1),
+							},
+						},
+					},
+					{
+						Locality: &envoy_core_v3.Locality{Zone: "us-east-1b"},
+						Priority: 1,
+						LbEndpoints: []*envoy_endpoint_v3.LbEndpoint{
+							{
+								HostIdentifier: &envoy_endpoint_v3.LbEndpoint_Endpoint{
+									Endpoint: &envoy_endpoint_v3.Endpoint{
+										Address: makeAddress("10.10.10.10", 1234),
+									}},
+								HealthStatus:        envoy_core_v3.HealthStatus_HEALTHY,
+								LoadBalancingWeight: makeUint32Value(1),
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -210,6 +259,7 @@ func Test_makeLoadAssignment(t *testing.T) {
 				tt.clusterName,
 				tt.endpoints,
 				proxycfg.GatewayKey{Datacenter: "dc1"},
+				tt.zones,
 			)
 			require.Equal(t, tt.want, got)
 		})
@@ -235,6 +285,29 @@ func TestEndpointsFromSnapshot(t *testing.T) {
 			create: proxycfg.TestConfigSnapshot,
 			setup:  nil, // Default snapshot
 		},
+		{
+			name:   "zone-aware-load-balancing",
+			create: proxycfg.TestConfigSnapshot,
+			setup: func(snap *proxycfg.ConfigSnapshot) {
+				snap.ConnectProxy.MeshConfig = &structs.MeshConfigEntry{
+					Locality: structs.LocalityMeshConfig{
+						ZoneMetaKey: "zone",
+					},
+				}
+				snap.NodeMeta = map[string]string{"zone": "us-east-1a"}
+				for _, endpoints := range snap.ConnectProxy.WatchedUpstreamEndpoints {
+					for _, nodes := range endpoints {
+						for i, node := range nodes {
+							zone := "us-east-1a"
+							if i%2 == 1 {
+								zone = "us-east-1b"
+							}
+							node.Node.Meta = map[string]string{"zone": zone}
+						}
+					}
+				}
+			},
+		},
 		{
 			name:   "mesh-gateway",
 			create: proxycfg.TestConfigSnapshotMeshGateway,
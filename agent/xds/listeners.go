@@ -16,6 +16,7 @@ import (
 	envoy_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	envoy_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	envoy_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_fault_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/fault/v3"
 	envoy_grpc_stats_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/grpc_stats/v3"
 	envoy_http_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
 	envoy_tcp_proxy_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
@@ -80,6 +81,10 @@ func (s *ResourceGenerator) listenersFromSnapshotConnectProxy(cfgSnap *proxycfg.
 
 		outboundListener = makePortListener(OutboundListenerName, "127.0.0.1", port, envoy_core_v3.TrafficDirection_OUTBOUND)
 		outboundListener.FilterChains = make([]*envoy_listener_v3.FilterChain, 0)
+		outboundListener.AccessLog, err = makeAccessLogs(&cfgSnap.Proxy.AccessLogs, true)
+		if err != nil {
+			return nil, err
+		}
 		outboundListener.ListenerFilters = []*envoy_listener_v3.ListenerFilter{
 			{
 				// The original_dst filter is a listener filter that recovers the original destination
@@ -133,11 +138,13 @@ func (s *ResourceGenerator) listenersFromSnapshotConnectProxy(cfgSnap *proxycfg.
 		// Generate the upstream listeners for when they are explicitly set with a local bind port or socket path
 		if upstreamCfg != nil && upstreamCfg.HasLocalPortOrSocket() {
 			filterChain, err := s.makeUpstreamFilterChain(filterChainOpts{
-				routeName:   uid.EnvoyID(),
-				clusterName: clusterName,
-				filterName:  filterName,
-				protocol:    cfg.Protocol,
-				useRDS:      useRDS,
+				routeName:      uid.EnvoyID(),
+				clusterName:    clusterName,
+				filterName:     filterName,
+				protocol:       cfg.Protocol,
+				useRDS:         useRDS,
+				faultInjection: useRDS && chain.HasFaultInjection(),
+				accessLogs:     &cfgSnap.Proxy.AccessLogs,
 			})
 			if err != nil {
 				return nil, err
@@ -147,6 +154,10 @@ func (s *ResourceGenerator) listenersFromSnapshotConnectProxy(cfgSnap *proxycfg.
 			upstreamListener.FilterChains = []*envoy_listener_v3.FilterChain{
 				filterChain,
 			}
+			upstreamListener.AccessLog, err = makeAccessLogs(&cfgSnap.Proxy.AccessLogs, true)
+			if err != nil {
+				return nil, err
+			}
 			resources = append(resources, upstreamListener)
 
 			// Avoid creating filter chains below for upstreams that have dedicated listeners
@@ -158,11 +169,13 @@ func (s *ResourceGenerator) listenersFromSnapshotConnectProxy(cfgSnap *proxycfg.
 		// as we do for explicit upstreams above.
 
 		filterChain, err := s.makeUpstreamFilterChain(filterChainOpts{
-			routeName:   uid.EnvoyID(),
-			clusterName: clusterName,
-			filterName:  filterName,
-			protocol:    cfg.Protocol,
-			useRDS:      useRDS,
+			routeName:      uid.EnvoyID(),
+			clusterName:    clusterName,
+			filterName:     filterName,
+			protocol:       cfg.Protocol,
+			useRDS:         useRDS,
+			faultInjection: useRDS && chain.HasFaultInjection(),
+			accessLogs:     &cfgSnap.Proxy.AccessLogs,
 		})
 		if err != nil {
 			return nil, err
@@ -231,6 +244,7 @@ func (s *ResourceGenerator) listenersFromSnapshotConnectProxy(cfgSnap *proxycfg.
 				clusterName: "passthrough~" + passthrough.SNI,
 				filterName:  filterName,
 				protocol:    "tcp",
+				accessLogs:  &cfgSnap.Proxy.AccessLogs,
 			})
 			if err != nil {
 				return nil, err
@@ -256,6 +270,7 @@ func (s *ResourceGenerator) listenersFromSnapshotConnectProxy(cfgSnap *proxycfg.
 				clusterName: OriginalDestinationClusterName,
 				filterName:  OriginalDestinationClusterName,
 				protocol:    "tcp",
+				accessLogs:  &cfgSnap.Proxy.AccessLogs,
 			})
 			if err != nil {
 				return nil, err
@@ -303,6 +318,7 @@ func (s *ResourceGenerator) listenersFromSnapshotConnectProxy(cfgSnap *proxycfg.
 			filterName:  uid.EnvoyID(),
 			routeName:   uid.EnvoyID(),
 			protocol:    cfg.Protocol,
+			accessLogs:  &cfgSnap.Proxy.AccessLogs,
 		})
 		if err != nil {
 			return nil, err
@@ -310,6 +326,10 @@ func (s *ResourceGenerator) listenersFromSnapshotConnectProxy(cfgSnap *proxycfg.
 		upstreamListener.FilterChains = []*envoy_listener_v3.FilterChain{
 			filterChain,
 		}
+		upstreamListener.AccessLog, err = makeAccessLogs(&cfgSnap.Proxy.AccessLogs, true)
+		if err != nil {
+			return nil, err
+		}
 		resources = append(resources, upstreamListener)
 	}
 
@@ -616,6 +636,7 @@ func (s *ResourceGenerator) injectConnectFilters(cfgSnap *proxycfg.ConfigSnapsho
 	authzFilter, err := makeRBACNetworkFilter(
 		cfgSnap.ConnectProxy.Intentions,
 		cfgSnap.IntentionDefaultAllow,
+		cfgSnap.IntentionsAuditMode(),
 	)
 	if err != nil {
 		return err
@@ -634,6 +655,8 @@ func (s *ResourceGenerator) injectConnectFilters(cfgSnap *proxycfg.ConfigSnapsho
 const (
 	httpConnectionManagerOldName = "envoy.http_connection_manager"
 	httpConnectionManagerNewName = "envoy.filters.network.http_connection_manager"
+
+	faultFilterName = "envoy.filters.http.fault"
 )
 
 func extractRdsResourceNames(listener *envoy_listener_v3.Listener) ([]string, error) {
@@ -786,6 +809,7 @@ func (s *ResourceGenerator) makeInboundListener(cfgSnap *proxycfg.ConfigSnapshot
 			httpAuthzFilter, err := makeRBACHTTPFilter(
 				cfgSnap.ConnectProxy.Intentions,
 				cfgSnap.IntentionDefaultAllow,
+				cfgSnap.IntentionsAuditMode(),
 			)
 			if err != nil {
 				return nil, err
@@ -805,6 +829,23 @@ func (s *ResourceGenerator) makeInboundListener(cfgSnap *proxycfg.ConfigSnapshot
 			}
 		}
 
+		if useHTTPFilter {
+			jwtAuthnFilter, err := makeJWTAuthnFilter(
+				cfgSnap.ConnectProxy.JWTProviders,
+				cfgSnap.ConnectProxy.Intentions,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			// The RBAC filter was injected above, so this lands in front of it.
+			if jwtAuthnFilter != nil {
+				if err := injectHTTPFilterOnFilterChains(l, jwtAuthnFilter); err != nil {
+					return nil, err
+				}
+			}
+		}
+
 		err := s.finalizePublicListenerFromConfig(l, cfgSnap, useHTTPFilter)
 		if err != nil {
 			return nil, fmt.Errorf("failed to attach Consul filters and TLS context to custom public listener: %v", err)
@@ -830,6 +871,10 @@ func (s *ResourceGenerator) makeInboundListener(cfgSnap *proxycfg.ConfigSnapshot
 	}
 
 	l = makePortListener(name, addr, port, envoy_core_v3.TrafficDirection_INBOUND)
+	l.AccessLog, err = makeAccessLogs(&cfgSnap.Proxy.AccessLogs, true)
+	if err != nil {
+		return nil, err
+	}
 
 	filterOpts := listenerFilterOpts{
 		protocol:         cfg.Protocol,
@@ -837,11 +882,21 @@ func (s *ResourceGenerator) makeInboundListener(cfgSnap *proxycfg.ConfigSnapshot
 		routeName:        name,
 		cluster:          LocalAppClusterName,
 		requestTimeoutMs: cfg.LocalRequestTimeoutMs,
+		accessLogs:       &cfgSnap.Proxy.AccessLogs,
 	}
 	if useHTTPFilter {
 		filterOpts.httpAuthzFilter, err = makeRBACHTTPFilter(
 			cfgSnap.ConnectProxy.Intentions,
 			cfgSnap.IntentionDefaultAllow,
+			cfgSnap.IntentionsAuditMode(),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		filterOpts.jwtAuthnFilter, err = makeJWTAuthnFilter(
+			cfgSnap.ConnectProxy.JWTProviders,
+			cfgSnap.ConnectProxy.Intentions,
 		)
 		if err != nil {
 			return nil, err
@@ -974,6 +1029,12 @@ func (s *ResourceGenerator) makeTerminatingGatewayListener(
 ) (*envoy_listener_v3.Listener, error) {
 	l := makePortListener(name, addr, port, envoy_core_v3.TrafficDirection_INBOUND)
 
+	var err error
+	l.AccessLog, err = makeAccessLogs(&cfgSnap.Proxy.AccessLogs, true)
+	if err != nil {
+		return nil, err
+	}
+
 	tlsInspector, err := makeTLSInspectorListenerFilter()
 	if err != nil {
 		return nil, err
@@ -1047,7 +1108,7 @@ func (s *ResourceGenerator) makeTerminatingGatewayListener(
 
 	// This fallback catch-all filter ensures a listener will be present for health checks to pass
 	// Envoy will reset these connections since known endpoints are caught by filter chain matches above
-	tcpProxy, err := makeTCPProxyFilter(name, "", "terminating_gateway.")
+	tcpProxy, err := makeTCPProxyFilter(name, "", "terminating_gateway.", &cfgSnap.Proxy.AccessLogs)
 	if err != nil {
 		return nil, err
 	}
@@ -1092,6 +1153,7 @@ func (s *ResourceGenerator) makeFilterChainTerminatingGateway(
 		authFilter, err := makeRBACNetworkFilter(
 			intentions,
 			cfgSnap.IntentionDefaultAllow,
+			cfgSnap.IntentionsAuditMode(),
 		)
 		if err != nil {
 			return nil, err
@@ -1109,6 +1171,7 @@ func (s *ResourceGenerator) makeFilterChainTerminatingGateway(
 		cluster:    cluster,
 		statPrefix: "upstream.",
 		routePath:  "",
+		accessLogs: &cfgSnap.Proxy.AccessLogs,
 	}
 
 	if useHTTPFilter {
@@ -1116,6 +1179,7 @@ func (s *ResourceGenerator) makeFilterChainTerminatingGateway(
 		opts.httpAuthzFilter, err = makeRBACHTTPFilter(
 			intentions,
 			cfgSnap.IntentionDefaultAllow,
+			cfgSnap.IntentionsAuditMode(),
 		)
 		if err != nil {
 			return nil, err
@@ -1147,7 +1211,7 @@ func (s *ResourceGenerator) makeMeshGatewayListener(name, addr string, port int,
 
 	// The cluster name here doesn't matter as the sni_cluster
 	// filter will fill it in for us.
-	tcpProxy, err := makeTCPProxyFilter(name, "", "mesh_gateway_local.")
+	tcpProxy, err := makeTCPProxyFilter(name, "", "mesh_gateway_local.", &cfgSnap.Proxy.AccessLogs)
 	if err != nil {
 		return nil, err
 	}
@@ -1161,6 +1225,10 @@ func (s *ResourceGenerator) makeMeshGatewayListener(name, addr string, port int,
 
 	l := makePortListener(name, addr, port, envoy_core_v3.TrafficDirection_UNSPECIFIED)
 	l.ListenerFilters = []*envoy_listener_v3.ListenerFilter{tlsInspector}
+	l.AccessLog, err = makeAccessLogs(&cfgSnap.Proxy.AccessLogs, true)
+	if err != nil {
+		return nil, err
+	}
 
 	// We need 1 Filter Chain per remote cluster
 	keys := cfgSnap.MeshGateway.GatewayKeys()
@@ -1171,7 +1239,7 @@ func (s *ResourceGenerator) makeMeshGatewayListener(name, addr string, port int,
 
 		clusterName := connect.GatewaySNI(key.Datacenter, key.Partition, cfgSnap.Roots.TrustDomain)
 		filterName := fmt.Sprintf("%s.%s", name, key.String())
-		dcTCPProxy, err := makeTCPProxyFilter(filterName, clusterName, "mesh_gateway_remote.")
+		dcTCPProxy, err := makeTCPProxyFilter(filterName, clusterName, "mesh_gateway_remote.", &cfgSnap.Proxy.AccessLogs)
 		if err != nil {
 			return nil, err
 		}
@@ -1196,7 +1264,7 @@ func (s *ResourceGenerator) makeMeshGatewayListener(name, addr string, port int,
 			}
 			clusterName := cfgSnap.ServerSNIFn(key.Datacenter, "")
 			filterName := fmt.Sprintf("%s.%s", name, key.String())
-			dcTCPProxy, err := makeTCPProxyFilter(filterName, clusterName, "mesh_gateway_remote.")
+			dcTCPProxy, err := makeTCPProxyFilter(filterName, clusterName, "mesh_gateway_remote.", &cfgSnap.Proxy.AccessLogs)
 			if err != nil {
 				return nil, err
 			}
@@ -1216,7 +1284,7 @@ func (s *ResourceGenerator) makeMeshGatewayListener(name, addr string, port int,
 			clusterName := cfgSnap.ServerSNIFn(cfgSnap.Datacenter, srv.Node.Node)
 
 			filterName := fmt.Sprintf("%s.%s", name, cfgSnap.Datacenter)
-			dcTCPProxy, err := makeTCPProxyFilter(filterName, clusterName, "mesh_gateway_local_server.")
+			dcTCPProxy, err := makeTCPProxyFilter(filterName, clusterName, "mesh_gateway_local_server.", &cfgSnap.Proxy.AccessLogs)
 			if err != nil {
 				return nil, err
 			}
@@ -1246,16 +1314,22 @@ type filterChainOpts struct {
 	protocol    string
 	useRDS      bool
 	tlsContext  *envoy_tls_v3.DownstreamTlsContext
+	// faultInjection adds the HTTP fault filter so that routes of the
+	// upstream's discovery chain can inject faults.
+	faultInjection bool
+	accessLogs     *structs.AccessLogsConfig
 }
 
 func (s *ResourceGenerator) makeUpstreamFilterChain(opts filterChainOpts) (*envoy_listener_v3.FilterChain, error) {
 	filter, err := makeListenerFilter(listenerFilterOpts{
-		useRDS:     opts.useRDS,
-		protocol:   opts.protocol,
-		filterName: opts.filterName,
-		routeName:  opts.routeName,
-		cluster:    opts.clusterName,
-		statPrefix: "upstream.",
+		useRDS:         opts.useRDS,
+		protocol:       opts.protocol,
+		filterName:     opts.filterName,
+		routeName:      opts.routeName,
+		cluster:        opts.clusterName,
+		statPrefix:     "upstream.",
+		faultInjection: opts.faultInjection,
+		accessLogs:     opts.accessLogs,
 	})
 	if err != nil {
 		return nil, err
@@ -1353,6 +1427,9 @@ type listenerFilterOpts struct {
 	requestTimeoutMs *int
 	ingressGateway   bool
 	httpAuthzFilter  *envoy_http_v3.HttpFilter
+	jwtAuthnFilter   *envoy_http_v3.HttpFilter
+	faultInjection   bool
+	accessLogs       *structs.AccessLogsConfig
 }
 
 func makeListenerFilter(opts listenerFilterOpts) (*envoy_listener_v3.Filter, error) {
@@ -1367,7 +1444,7 @@ func makeListenerFilter(opts listenerFilterOpts) (*envoy_listener_v3.Filter, err
 		} else if opts.cluster == "" {
 			return nil, fmt.Errorf("cluster name is required for a tcp proxy filter")
 		}
-		return makeTCPProxyFilter(opts.filterName, opts.cluster, opts.statPrefix)
+		return makeTCPProxyFilter(opts.filterName, opts.cluster, opts.statPrefix, opts.accessLogs)
 	}
 }
 
@@ -1386,11 +1463,17 @@ func makeSNIClusterFilter() (*envoy_listener_v3.Filter, error) {
 	return &envoy_listener_v3.Filter{Name: "envoy.filters.network.sni_cluster"}, nil
 }
 
-func makeTCPProxyFilter(filterName, cluster, statPrefix string) (*envoy_listener_v3.Filter, error) {
+func makeTCPProxyFilter(filterName, cluster, statPrefix string, accessLogs *structs.AccessLogsConfig) (*envoy_listener_v3.Filter, error) {
 	cfg := &envoy_tcp_proxy_v3.TcpProxy{
 		StatPrefix:       makeStatPrefix(statPrefix, filterName),
 		ClusterSpecifier: &envoy_tcp_proxy_v3.TcpProxy_Cluster{Cluster: cluster},
 	}
+
+	var err error
+	cfg.AccessLog, err = makeAccessLogs(accessLogs, false)
+	if err != nil {
+		return nil, err
+	}
 	return makeFilter("envoy.filters.network.tcp_proxy", cfg)
 }
 
@@ -1418,6 +1501,12 @@ func makeHTTPFilter(opts listenerFilterOpts) (*envoy_listener_v3.Filter, error)
 		},
 	}
 
+	var err error
+	cfg.AccessLog, err = makeAccessLogs(opts.accessLogs, false)
+	if err != nil {
+		return nil, err
+	}
+
 	if opts.useRDS {
 		if opts.cluster != "" {
 			return nil, fmt.Errorf("cannot specify cluster name when using RDS")
@@ -1488,6 +1577,17 @@ func makeHTTPFilter(opts listenerFilterOpts) (*envoy_listener_v3.Filter, error)
 		cfg.Http2ProtocolOptions = &envoy_core_v3.Http2ProtocolOptions{}
 	}
 
+	// The fault filter is a no-op on its own. Routes that inject faults
+	// override its configuration through their per-filter config, so it only
+	// has to sit in front of the router.
+	if opts.faultInjection {
+		faultFilter, err := makeEnvoyHTTPFilter(faultFilterName, &envoy_fault_v3.HTTPFault{})
+		if err != nil {
+			return nil, err
+		}
+		cfg.HttpFilters = append([]*envoy_http_v3.HttpFilter{faultFilter}, cfg.HttpFilters...)
+	}
+
 	// Like injectConnectFilters for L4, here we ensure that the first filter
 	// (other than the "envoy.grpc_http1_bridge" filter) in the http filter
 	// chain of a public listener is the authz filter to prevent unauthorized
@@ -1496,6 +1596,12 @@ func makeHTTPFilter(opts listenerFilterOpts) (*envoy_listener_v3.Filter, error)
 		cfg.HttpFilters = append([]*envoy_http_v3.HttpFilter{opts.httpAuthzFilter}, cfg.HttpFilters...)
 	}
 
+	// The JWT authn filter has to run ahead of the authz filter so that the
+	// verified token payloads are available to the RBAC metadata matchers.
+	if opts.jwtAuthnFilter != nil {
+		cfg.HttpFilters = append([]*envoy_http_v3.HttpFilter{opts.jwtAuthnFilter}, cfg.HttpFilters...)
+	}
+
 	if opts.protocol == "grpc" {
 		// Add grpc bridge before router and authz
 		cfg.HttpFilters = append([]*envoy_http_v3.HttpFilter{{
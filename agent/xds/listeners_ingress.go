@@ -65,12 +65,14 @@ func (s *ResourceGenerator) makeIngressGatewayListeners(address string, cfgSnap
 
 			l := makePortListenerWithDefault(uid.EnvoyID(), address, u.LocalBindPort, envoy_core_v3.TrafficDirection_OUTBOUND)
 			filterChain, err := s.makeUpstreamFilterChain(filterChainOpts{
-				routeName:   uid.EnvoyID(),
-				useRDS:      useRDS,
-				clusterName: clusterName,
-				filterName:  filterName,
-				protocol:    cfg.Protocol,
-				tlsContext:  tlsContext,
+				routeName:      uid.EnvoyID(),
+				useRDS:         useRDS,
+				clusterName:    clusterName,
+				filterName:     filterName,
+				protocol:       cfg.Protocol,
+				tlsContext:     tlsContext,
+				faultInjection: useRDS && chain.HasFaultInjection(),
+				accessLogs:     &cfgSnap.Proxy.AccessLogs,
 			})
 			if err != nil {
 				return nil, err
@@ -78,11 +80,19 @@ func (s *ResourceGenerator) makeIngressGatewayListeners(address string, cfgSnap
 			l.FilterChains = []*envoy_listener_v3.FilterChain{
 				filterChain,
 			}
+			l.AccessLog, err = makeAccessLogs(&cfgSnap.Proxy.AccessLogs, true)
+			if err != nil {
+				return nil, err
+			}
 			resources = append(resources, l)
 
 		} else {
 			// If multiple upstreams share this port, make a special listener for the protocol.
 			listener := makePortListener(listenerKey.Protocol, address, listenerKey.Port, envoy_core_v3.TrafficDirection_OUTBOUND)
+			listener.AccessLog, err = makeAccessLogs(&cfgSnap.Proxy.AccessLogs, true)
+			if err != nil {
+				return nil, err
+			}
 			opts := listenerFilterOpts{
 				useRDS:          true,
 				protocol:        listenerKey.Protocol,
@@ -92,6 +102,15 @@ func (s *ResourceGenerator) makeIngressGatewayListeners(address string, cfgSnap
 				statPrefix:      "ingress_upstream_",
 				routePath:       "",
 				httpAuthzFilter: nil,
+				accessLogs:      &cfgSnap.Proxy.AccessLogs,
+			}
+
+			for _, u := range upstreams {
+				chain := cfgSnap.IngressGateway.DiscoveryChain[proxycfg.NewUpstreamID(&u)]
+				if chain != nil && chain.HasFaultInjection() {
+					opts.faultInjection = true
+					break
+				}
 			}
 
 			// Generate any filter chains needed for services with custom TLS certs
@@ -83,6 +83,71 @@ func TestListenersFromSnapshot(t *testing.T) {
 				snap.Proxy.Config["protocol"] = "http"
 			},
 		},
+		{
+			name:   "http-public-listener-with-jwt",
+			create: proxycfg.TestConfigSnapshot,
+			setup: func(snap *proxycfg.ConfigSnapshot) {
+				snap.Proxy.Config["protocol"] = "http"
+				setupJWTProviderIntentions(snap)
+			},
+		},
+		{
+			name:   "intentions-audit-mode",
+			create: proxycfg.TestConfigSnapshot,
+			setup: func(snap *proxycfg.ConfigSnapshot) {
+				snap.ConnectProxy.MeshConfig = &structs.MeshConfigEntry{
+					Intentions: structs.IntentionsMeshConfig{
+						AuditMode: true,
+					},
+				}
+			},
+		},
+		{
+			name:   "intentions-audit-mode-http",
+			create: proxycfg.TestConfigSnapshot,
+			setup: func(snap *proxycfg.ConfigSnapshot) {
+				snap.Proxy.Config["protocol"] = "http"
+				snap.ConnectProxy.MeshConfig = &structs.MeshConfigEntry{
+					Intentions: structs.IntentionsMeshConfig{
+						AuditMode: true,
+					},
+				}
+			},
+		},
+		{
+			name:   "access-logs-defaults",
+			create: proxycfg.TestConfigSnapshot,
+			setup: func(snap *proxycfg.ConfigSnapshot) {
+				snap.Proxy.AccessLogs = structs.AccessLogsConfig{
+					Enabled: true,
+				}
+			},
+		},
+		{
+			name:   "access-logs-json-file",
+			create: proxycfg.TestConfigSnapshot,
+			setup: func(snap *proxycfg.ConfigSnapshot) {
+				snap.Proxy.Config["protocol"] = "http"
+				snap.Proxy.AccessLogs = structs.AccessLogsConfig{
+					Enabled:    true,
+					Type:       structs.FileLogSinkType,
+					Path:       "/var/log/envoy/access.log",
+					JSONFormat: `{"method": "%REQ(:METHOD)%", "path": "%REQ(X-ENVOY-ORIGINAL-PATH?:PATH)%"}`,
+				}
+			},
+		},
+		{
+			name:   "access-logs-text-stderr-no-listener-logs",
+			create: proxycfg.TestConfigSnapshot,
+			setup: func(snap *proxycfg.ConfigSnapshot) {
+				snap.Proxy.AccessLogs = structs.AccessLogsConfig{
+					Enabled:             true,
+					DisableListenerLogs: true,
+					Type:                structs.StdErrLogSinkType,
+					TextFormat:          "[%START_TIME%] %DOWNSTREAM_REMOTE_ADDRESS% %RESPONSE_FLAGS%\n",
+				}
+			},
+		},
 		{
 			name:   "http-listener-with-timeouts",
 			create: proxycfg.TestConfigSnapshot,
@@ -258,6 +323,11 @@ func TestListenersFromSnapshot(t *testing.T) {
 			},
 			setup: nil,
 		},
+		{
+			name:   "connect-proxy-with-chain-and-fault-injection",
+			create: proxycfg.TestConfigSnapshotDiscoveryChainWithFaultInjection,
+			setup:  nil,
+		},
 		{
 			name:   "connect-proxy-with-chain-external-sni",
 			create: proxycfg.TestConfigSnapshotDiscoveryChainExternalSNI,
@@ -392,6 +462,15 @@ func TestListenersFromSnapshot(t *testing.T) {
 			create: proxycfg.TestConfigSnapshotIngressGateway,
 			setup:  nil,
 		},
+		{
+			name:   "ingress-gateway-with-access-logs",
+			create: proxycfg.TestConfigSnapshotIngressGateway,
+			setup: func(snap *proxycfg.ConfigSnapshot) {
+				snap.Proxy.AccessLogs = structs.AccessLogsConfig{
+					Enabled: true,
+				}
+			},
+		},
 		{
 			name:   "ingress-gateway-bind-addrs",
 			create: proxycfg.TestConfigSnapshotIngressGateway,
@@ -444,6 +523,26 @@ func TestListenersFromSnapshot(t *testing.T) {
 			create: proxycfg.TestConfigSnapshotTerminatingGateway,
 			setup:  nil,
 		},
+		{
+			name:   "terminating-gateway-with-access-logs",
+			create: proxycfg.TestConfigSnapshotTerminatingGateway,
+			setup: func(snap *proxycfg.ConfigSnapshot) {
+				snap.Proxy.AccessLogs = structs.AccessLogsConfig{
+					Enabled: true,
+				}
+			},
+		},
+		{
+			name:   "terminating-gateway-intentions-audit-mode",
+			create: proxycfg.TestConfigSnapshotTerminatingGateway,
+			setup: func(snap *proxycfg.ConfigSnapshot) {
+				snap.TerminatingGateway.MeshConfig = &structs.MeshConfigEntry{
+					Intentions: structs.IntentionsMeshConfig{
+						AuditMode: true,
+					},
+				}
+			},
+		},
 		{
 			name:   "terminating-gateway-no-services",
 			create: proxycfg.TestConfigSnapshotTerminatingGatewayNoServices,
@@ -1149,6 +1248,18 @@ func TestListenersFromSnapshot(t *testing.T) {
 				snap.ConnectProxy.DiscoveryChain[UID("no-endpoints")] = discoverychain.TestCompileConfigEntries(t, "no-endpoints", "default", "default", "dc1", connect.TestClusterID+".consul", nil)
 			},
 		},
+		{
+			name:   "transparent-proxy-with-access-logs",
+			create: proxycfg.TestConfigSnapshot,
+			setup: func(snap *proxycfg.ConfigSnapshot) {
+				snap.Proxy.Mode = structs.ProxyModeTransparent
+				snap.ConnectProxy.MeshConfigSet = true
+				snap.Proxy.AccessLogs = structs.AccessLogsConfig{
+					Enabled: true,
+					Type:    structs.StdOutLogSinkType,
+				}
+			},
+		},
 		{
 			name:   "transparent-proxy-catalog-destinations-only",
 			create: proxycfg.TestConfigSnapshot,
@@ -1638,3 +1749,34 @@ func TestResolveListenerSDSConfig(t *testing.T) {
 	}
 
 }
+
+// setupJWTProviderIntentions configures the snapshot with a remote jwt-provider
+// and an intention requiring a token issued by it.
+func setupJWTProviderIntentions(snap *proxycfg.ConfigSnapshot) {
+	snap.ConnectProxy.JWTProviders = map[string]*structs.JWTProviderConfigEntry{
+		"okta": {
+			Name:   "okta",
+			Issuer: "https://okta.example.com",
+			JSONWebKeySet: &structs.JSONWebKeySet{
+				Remote: &structs.RemoteJWKS{
+					URI: "https://okta.example.com/.well-known/jwks.json",
+				},
+			},
+		},
+	}
+	snap.ConnectProxy.Intentions = structs.Intentions{
+		{
+			SourceNS:        structs.IntentionDefaultNamespace,
+			SourceName:      "web",
+			DestinationNS:   structs.IntentionDefaultNamespace,
+			DestinationName: "db",
+			Action:          structs.IntentionActionAllow,
+			JWT: &structs.IntentionJWTRequirement{
+				Provider: "okta",
+				Claims: []structs.IntentionJWTClaimVerification{
+					{Path: []string{"roles"}, Value: "admin"},
+				},
+			},
+		},
+	}
+}
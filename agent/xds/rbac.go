@@ -1,23 +1,34 @@
 package xds
 
 import (
+	"encoding/base64"
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
+	envoy_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	envoy_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	envoy_rbac_v3 "github.com/envoyproxy/go-control-plane/envoy/config/rbac/v3"
 	envoy_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_http_jwt_authn_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/jwt_authn/v3"
 	envoy_http_rbac_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/rbac/v3"
 	envoy_http_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
 	envoy_network_rbac_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/rbac/v3"
 	envoy_matcher_v3 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/empty"
 
 	"github.com/hashicorp/consul/agent/connect"
 	"github.com/hashicorp/consul/agent/structs"
 )
 
-func makeRBACNetworkFilter(intentions structs.Intentions, intentionDefaultAllow bool) (*envoy_listener_v3.Filter, error) {
+// makeRBACNetworkFilter returns the RBAC network filter enforcing the given
+// intentions. In audit mode the rules are installed as shadow rules instead,
+// which Envoy evaluates and counts in the "connect_authz.rbac.shadow_denied"
+// stat without rejecting any connections. The stat is per listener, it
+// doesn't tell which source would have been denied.
+func makeRBACNetworkFilter(intentions structs.Intentions, intentionDefaultAllow bool, auditMode bool) (*envoy_listener_v3.Filter, error) {
 	rules, err := makeRBACRules(intentions, intentionDefaultAllow, false)
 	if err != nil {
 		return nil, err
@@ -25,23 +36,188 @@ func makeRBACNetworkFilter(intentions structs.Intentions, intentionDefaultAllow
 
 	cfg := &envoy_network_rbac_v3.RBAC{
 		StatPrefix: "connect_authz",
-		Rules:      rules,
+	}
+	if auditMode {
+		cfg.ShadowRules = rules
+	} else {
+		cfg.Rules = rules
 	}
 	return makeFilter("envoy.filters.network.rbac", cfg)
 }
 
-func makeRBACHTTPFilter(intentions structs.Intentions, intentionDefaultAllow bool) (*envoy_http_v3.HttpFilter, error) {
+// makeRBACHTTPFilter returns the RBAC HTTP filter enforcing the given
+// intentions. As with the network filter, audit mode installs the rules as
+// shadow rules that are only counted in the "rbac.shadow_denied" stat of the
+// HTTP connection manager.
+func makeRBACHTTPFilter(intentions structs.Intentions, intentionDefaultAllow bool, auditMode bool) (*envoy_http_v3.HttpFilter, error) {
 	rules, err := makeRBACRules(intentions, intentionDefaultAllow, true)
 	if err != nil {
 		return nil, err
 	}
 
-	cfg := &envoy_http_rbac_v3.RBAC{
-		Rules: rules,
+	cfg := &envoy_http_rbac_v3.RBAC{}
+	if auditMode {
+		cfg.ShadowRules = rules
+	} else {
+		cfg.Rules = rules
 	}
 	return makeEnvoyHTTPFilter("envoy.filters.http.rbac", cfg)
 }
 
+const (
+	jwtAuthnFilterName = "envoy.filters.http.jwt_authn"
+
+	// jwtPayloadPrefix prefixes the dynamic metadata key under which the
+	// jwt_authn filter stores the verified payload of each provider.
+	jwtPayloadPrefix = "jwt_payload_"
+
+	// jwksClusterPrefix prefixes the clusters used to fetch remote JWKS.
+	jwksClusterPrefix = "jwks_cluster_"
+
+	defaultJWKSRequestTimeout = 1000 * time.Millisecond
+)
+
+// makeJWTAuthnFilter returns the jwt_authn HTTP filter that verifies tokens
+// for every jwt-provider referenced by the given intentions. It returns nil
+// if no intention requires a JWT.
+//
+// The filter never rejects a request by itself. Verified payloads are
+// written to dynamic metadata where the RBAC filter matches them, and the
+// RBAC rules explicitly deny the requests without a valid token for the
+// intentions that require one, see makeRBACPermissions.
+func makeJWTAuthnFilter(providers map[string]*structs.JWTProviderConfigEntry, intentions structs.Intentions) (*envoy_http_v3.HttpFilter, error) {
+	names := referencedJWTProviders(intentions)
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	cfg := &envoy_http_jwt_authn_v3.JwtAuthentication{
+		Providers: make(map[string]*envoy_http_jwt_authn_v3.JwtProvider),
+	}
+	requirements := make([]*envoy_http_jwt_authn_v3.JwtRequirement, 0, len(names)+1)
+	for _, name := range names {
+		provider, ok := providers[name]
+		if !ok {
+			// Intentions referencing an unknown provider can never match
+			// since no payload is written for them.
+			continue
+		}
+		p, err := makeJWTProvider(provider)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Providers[name] = p
+		requirements = append(requirements, &envoy_http_jwt_authn_v3.JwtRequirement{
+			RequiresType: &envoy_http_jwt_authn_v3.JwtRequirement_ProviderName{
+				ProviderName: name,
+			},
+		})
+	}
+	if len(cfg.Providers) == 0 {
+		return nil, nil
+	}
+	requirements = append(requirements, &envoy_http_jwt_authn_v3.JwtRequirement{
+		RequiresType: &envoy_http_jwt_authn_v3.JwtRequirement_AllowMissingOrFailed{
+			AllowMissingOrFailed: &empty.Empty{},
+		},
+	})
+
+	cfg.Rules = []*envoy_http_jwt_authn_v3.RequirementRule{
+		{
+			Match: &envoy_route_v3.RouteMatch{
+				PathSpecifier: &envoy_route_v3.RouteMatch_Prefix{Prefix: "/"},
+			},
+			Requires: &envoy_http_jwt_authn_v3.JwtRequirement{
+				RequiresType: &envoy_http_jwt_authn_v3.JwtRequirement_RequiresAny{
+					RequiresAny: &envoy_http_jwt_authn_v3.JwtRequirementOrList{
+						Requirements: requirements,
+					},
+				},
+			},
+		},
+	}
+	return makeEnvoyHTTPFilter(jwtAuthnFilterName, cfg)
+}
+
+// referencedJWTProviders returns the sorted names of all providers referenced
+// by intentions or their permissions.
+func referencedJWTProviders(intentions structs.Intentions) []string {
+	seen := make(map[string]struct{})
+	for _, ixn := range intentions {
+		if ixn.JWT != nil {
+			seen[ixn.JWT.Provider] = struct{}{}
+		}
+		for _, perm := range ixn.Permissions {
+			if perm.JWT != nil {
+				seen[perm.JWT.Provider] = struct{}{}
+			}
+		}
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func makeJWTProvider(p *structs.JWTProviderConfigEntry) (*envoy_http_jwt_authn_v3.JwtProvider, error) {
+	provider := &envoy_http_jwt_authn_v3.JwtProvider{
+		Issuer:            p.Issuer,
+		Audiences:         p.Audiences,
+		PayloadInMetadata: jwtPayloadPrefix + p.Name,
+	}
+	if p.Forwarding != nil {
+		provider.Forward = p.Forwarding.KeepToken
+		provider.ForwardPayloadHeader = p.Forwarding.HeaderName
+	}
+
+	if p.JSONWebKeySet == nil {
+		return nil, fmt.Errorf("jwt-provider %q has no JSONWebKeySet", p.Name)
+	}
+	switch {
+	case p.JSONWebKeySet.Local != nil:
+		local := p.JSONWebKeySet.Local
+		ds := &envoy_core_v3.DataSource{}
+		if local.Filename != "" {
+			ds.Specifier = &envoy_core_v3.DataSource_Filename{Filename: local.Filename}
+		} else {
+			jwks, err := base64.StdEncoding.DecodeString(local.JWKS)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode JWKS for jwt-provider %q: %v", p.Name, err)
+			}
+			ds.Specifier = &envoy_core_v3.DataSource_InlineString{InlineString: string(jwks)}
+		}
+		provider.JwksSourceSpecifier = &envoy_http_jwt_authn_v3.JwtProvider_LocalJwks{
+			LocalJwks: ds,
+		}
+
+	case p.JSONWebKeySet.Remote != nil:
+		remote := p.JSONWebKeySet.Remote
+		timeout := defaultJWKSRequestTimeout
+		if remote.RequestTimeoutMs > 0 {
+			timeout = time.Duration(remote.RequestTimeoutMs) * time.Millisecond
+		}
+		rj := &envoy_http_jwt_authn_v3.RemoteJwks{
+			HttpUri: &envoy_core_v3.HttpUri{
+				Uri: remote.URI,
+				HttpUpstreamType: &envoy_core_v3.HttpUri_Cluster{
+					Cluster: jwksClusterPrefix + p.Name,
+				},
+				Timeout: ptypes.DurationProto(timeout),
+			},
+		}
+		if remote.CacheDuration > 0 {
+			rj.CacheDuration = ptypes.DurationProto(remote.CacheDuration)
+		}
+		provider.JwksSourceSpecifier = &envoy_http_jwt_authn_v3.JwtProvider_RemoteJwks{
+			RemoteJwks: rj,
+		}
+	}
+	return provider, nil
+}
+
 func intentionListToIntermediateRBACForm(intentions structs.Intentions, isHTTP bool) []*rbacIntention {
 	sort.Sort(structs.IntentionPrecedenceSorter(intentions))
 
@@ -198,23 +374,74 @@ func intentionToIntermediateRBACForm(ixn *structs.Intention, isHTTP bool) *rbacI
 			rixn.Action = intentionActionLayer7
 			rixn.Permissions = make([]*rbacPermission, 0, len(ixn.Permissions))
 			for _, perm := range ixn.Permissions {
-				rixn.Permissions = append(rixn.Permissions, &rbacPermission{
-					Definition: perm,
-					Action:     intentionActionFromString(perm.Action),
-					Perm:       convertPermission(perm),
-				})
+				// A JWT required by the intention applies to every permission.
+				jwt := append(jwtPermissions(ixn.JWT), jwtPermissions(perm.JWT)...)
+				rixn.Permissions = append(rixn.Permissions, makeRBACPermissions(
+					perm,
+					intentionActionFromString(perm.Action),
+					httpPermissions(perm.HTTP),
+					jwt,
+				)...)
 			}
 		} else {
 			// In case L7 intentions slip through to here, treat them as deny intentions.
 			rixn.Action = intentionActionDeny
 		}
+	} else if ixn.JWT != nil && ixn.Action == structs.IntentionActionAllow {
+		if isHTTP {
+			// A JWT requirement on the intention itself behaves like a single
+			// permission that matches any request.
+			rixn.Action = intentionActionLayer7
+			rixn.Permissions = makeRBACPermissions(nil, intentionActionAllow, nil, jwtPermissions(ixn.JWT))
+		} else {
+			// JWTs cannot be verified for TCP services, so treat them as deny intentions.
+			rixn.Action = intentionActionDeny
+		}
 	} else {
+		// Deny intentions apply whether or not a JWT was presented.
 		rixn.Action = intentionActionFromString(ixn.Action)
 	}
 
 	return rixn
 }
 
+// makeRBACPermissions returns the permissions applying the action to the
+// requests matching all the given HTTP permissions.
+//
+// The JWT permissions are only required to allow a request: deny rules apply
+// whether or not a token was presented. Since the jwt_authn filter lets
+// requests without a valid token through, allow rules requiring a JWT are
+// preceded by an explicit deny of the matching requests missing the token, so
+// that they are rejected even if the default intention action is allow.
+func makeRBACPermissions(
+	def *structs.IntentionPermission,
+	action intentionAction,
+	match []*envoy_rbac_v3.Permission,
+	jwt []*envoy_rbac_v3.Permission,
+) []*rbacPermission {
+	if action != intentionActionAllow || len(jwt) == 0 {
+		return []*rbacPermission{{
+			Definition: def,
+			Action:     action,
+			Perm:       andPermissions(match),
+		}}
+	}
+
+	missingJWT := append(match[:len(match):len(match)], notPermission(andPermissions(jwt)))
+	return []*rbacPermission{
+		{
+			Definition: def,
+			Action:     intentionActionDeny,
+			Perm:       andPermissions(missingJWT),
+		},
+		{
+			Definition: def,
+			Action:     intentionActionAllow,
+			Perm:       andPermissions(append(match, jwt...)),
+		},
+	}
+}
+
 type intentionAction int
 
 const (
@@ -622,51 +849,52 @@ func anyPermission() *envoy_rbac_v3.Permission {
 	}
 }
 
-func convertPermission(perm *structs.IntentionPermission) *envoy_rbac_v3.Permission {
-	// NOTE: this does not do anything with perm.Action
-	if perm.HTTP == nil {
-		return anyPermission()
+// httpPermissions returns the permissions matching the requests described by
+// the HTTP permission, which must all match.
+func httpPermissions(perm *structs.IntentionHTTPPermission) []*envoy_rbac_v3.Permission {
+	if perm == nil {
+		return nil
 	}
 
 	var parts []*envoy_rbac_v3.Permission
 
 	switch {
-	case perm.HTTP.PathExact != "":
+	case perm.PathExact != "":
 		parts = append(parts, &envoy_rbac_v3.Permission{
 			Rule: &envoy_rbac_v3.Permission_UrlPath{
 				UrlPath: &envoy_matcher_v3.PathMatcher{
 					Rule: &envoy_matcher_v3.PathMatcher_Path{
 						Path: &envoy_matcher_v3.StringMatcher{
 							MatchPattern: &envoy_matcher_v3.StringMatcher_Exact{
-								Exact: perm.HTTP.PathExact,
+								Exact: perm.PathExact,
 							},
 						},
 					},
 				},
 			},
 		})
-	case perm.HTTP.PathPrefix != "":
+	case perm.PathPrefix != "":
 		parts = append(parts, &envoy_rbac_v3.Permission{
 			Rule: &envoy_rbac_v3.Permission_UrlPath{
 				UrlPath: &envoy_matcher_v3.PathMatcher{
 					Rule: &envoy_matcher_v3.PathMatcher_Path{
 						Path: &envoy_matcher_v3.StringMatcher{
 							MatchPattern: &envoy_matcher_v3.StringMatcher_Prefix{
-								Prefix: perm.HTTP.PathPrefix,
+								Prefix: perm.PathPrefix,
 							},
 						},
 					},
 				},
 			},
 		})
-	case perm.HTTP.PathRegex != "":
+	case perm.PathRegex != "":
 		parts = append(parts, &envoy_rbac_v3.Permission{
 			Rule: &envoy_rbac_v3.Permission_UrlPath{
 				UrlPath: &envoy_matcher_v3.PathMatcher{
 					Rule: &envoy_matcher_v3.PathMatcher_Path{
 						Path: &envoy_matcher_v3.StringMatcher{
 							MatchPattern: &envoy_matcher_v3.StringMatcher_SafeRegex{
-								SafeRegex: makeEnvoyRegexMatch(perm.HTTP.PathRegex),
+								SafeRegex: makeEnvoyRegexMatch(perm.PathRegex),
 							},
 						},
 					},
@@ -675,7 +903,7 @@ func convertPermission(perm *structs.IntentionPermission) *envoy_rbac_v3.Permiss
 		})
 	}
 
-	for _, hdr := range perm.HTTP.Header {
+	for _, hdr := range perm.Header {
 		eh := &envoy_route_v3.HeaderMatcher{
 			Name: hdr.Name,
 		}
@@ -716,8 +944,8 @@ func convertPermission(perm *structs.IntentionPermission) *envoy_rbac_v3.Permiss
 		})
 	}
 
-	if len(perm.HTTP.Methods) > 0 {
-		methodHeaderRegex := strings.Join(perm.HTTP.Methods, "|")
+	if len(perm.Methods) > 0 {
+		methodHeaderRegex := strings.Join(perm.Methods, "|")
 
 		eh := &envoy_route_v3.HeaderMatcher{
 			Name: ":method",
@@ -735,7 +963,7 @@ func convertPermission(perm *structs.IntentionPermission) *envoy_rbac_v3.Permiss
 
 	// NOTE: if for some reason we errantly allow a permission to be defined
 	// with a body of "http{}" then we'll end up treating that like "ANY" here.
-	return andPermissions(parts)
+	return parts
 }
 
 func notPermission(perm *envoy_rbac_v3.Permission) *envoy_rbac_v3.Permission {
@@ -760,3 +988,72 @@ func andPermissions(perms []*envoy_rbac_v3.Permission) *envoy_rbac_v3.Permission
 		}
 	}
 }
+
+// jwtPermissions returns the permissions matching the dynamic metadata that
+// the jwt_authn filter writes for a verified token: one for the presence of
+// the provider's payload and one per required claim.
+func jwtPermissions(req *structs.IntentionJWTRequirement) []*envoy_rbac_v3.Permission {
+	if req == nil {
+		return nil
+	}
+
+	payloadKey := jwtPayloadPrefix + req.Provider
+	perms := []*envoy_rbac_v3.Permission{
+		metadataPermission(makeJWTMetadataMatcher(payloadKey, nil, &envoy_matcher_v3.ValueMatcher{
+			MatchPattern: &envoy_matcher_v3.ValueMatcher_PresentMatch{PresentMatch: true},
+		})),
+	}
+
+	for _, claim := range req.Claims {
+		value := &envoy_matcher_v3.ValueMatcher{
+			MatchPattern: &envoy_matcher_v3.ValueMatcher_StringMatch{
+				StringMatch: &envoy_matcher_v3.StringMatcher{
+					MatchPattern: &envoy_matcher_v3.StringMatcher_Exact{Exact: claim.Value},
+				},
+			},
+		}
+		// Claims such as "roles" are commonly lists, so match either a
+		// scalar claim equal to the value or a list claim containing it.
+		perms = append(perms, orPermissions([]*envoy_rbac_v3.Permission{
+			metadataPermission(makeJWTMetadataMatcher(payloadKey, claim.Path, value)),
+			metadataPermission(makeJWTMetadataMatcher(payloadKey, claim.Path, &envoy_matcher_v3.ValueMatcher{
+				MatchPattern: &envoy_matcher_v3.ValueMatcher_ListMatch{
+					ListMatch: &envoy_matcher_v3.ListMatcher{
+						MatchPattern: &envoy_matcher_v3.ListMatcher_OneOf{OneOf: value},
+					},
+				},
+			})),
+		}))
+	}
+	return perms
+}
+
+func makeJWTMetadataMatcher(payloadKey string, path []string, value *envoy_matcher_v3.ValueMatcher) *envoy_matcher_v3.MetadataMatcher {
+	segments := make([]*envoy_matcher_v3.MetadataMatcher_PathSegment, 0, len(path)+1)
+	for _, key := range append([]string{payloadKey}, path...) {
+		segments = append(segments, &envoy_matcher_v3.MetadataMatcher_PathSegment{
+			Segment: &envoy_matcher_v3.MetadataMatcher_PathSegment_Key{Key: key},
+		})
+	}
+	return &envoy_matcher_v3.MetadataMatcher{
+		Filter: jwtAuthnFilterName,
+		Path:   segments,
+		Value:  value,
+	}
+}
+
+func metadataPermission(m *envoy_matcher_v3.MetadataMatcher) *envoy_rbac_v3.Permission {
+	return &envoy_rbac_v3.Permission{
+		Rule: &envoy_rbac_v3.Permission_Metadata{Metadata: m},
+	}
+}
+
+func orPermissions(perms []*envoy_rbac_v3.Permission) *envoy_rbac_v3.Permission {
+	return &envoy_rbac_v3.Permission{
+		Rule: &envoy_rbac_v3.Permission_OrRules{
+			OrRules: &envoy_rbac_v3.Permission_Set{
+				Rules: perms,
+			},
+		},
+	}
+}
@@ -1,10 +1,14 @@
 package xds
 
 import (
+	"encoding/base64"
 	"fmt"
 	"path/filepath"
+	"regexp"
 	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	envoy_rbac_v3 "github.com/envoyproxy/go-control-plane/envoy/config/rbac/v3"
 	envoy_matcher_v3 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
@@ -12,6 +16,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/hashicorp/consul/agent/connect"
 	"github.com/hashicorp/consul/agent/structs"
 )
 
@@ -400,6 +405,11 @@ func TestMakeRBACNetworkAndHTTPFilters(t *testing.T) {
 		ixn.Permissions = perms
 		return ixn
 	}
+	testSourceJWTIntention := func(src string, action structs.IntentionAction, jwt *structs.IntentionJWTRequirement) *structs.Intention {
+		ixn := testIntention(t, src, "api", action)
+		ixn.JWT = jwt
+		return ixn
+	}
 	sorted := func(ixns ...*structs.Intention) structs.Intentions {
 		sort.SliceStable(ixns, func(i, j int) bool {
 			return ixns[j].Precedence < ixns[i].Precedence
@@ -424,6 +434,7 @@ func TestMakeRBACNetworkAndHTTPFilters(t *testing.T) {
 
 	tests := map[string]struct {
 		intentionDefaultAllow bool
+		auditMode             bool
 		intentions            structs.Intentions
 	}{
 		"default-deny-mixed-precedence": {
@@ -440,6 +451,27 @@ func TestMakeRBACNetworkAndHTTPFilters(t *testing.T) {
 				testSourceIntention("*", structs.IntentionActionAllow),
 			),
 		},
+		"audit-mode-default-deny-one-allow": {
+			intentionDefaultAllow: false,
+			auditMode:             true,
+			intentions: sorted(
+				testSourceIntention("web", structs.IntentionActionAllow),
+			),
+		},
+		"audit-mode-default-deny-path-allow": {
+			intentionDefaultAllow: false,
+			auditMode:             true,
+			intentions: sorted(
+				testSourcePermIntention("web",
+					&structs.IntentionPermission{
+						Action: structs.IntentionActionAllow,
+						HTTP: &structs.IntentionHTTPPermission{
+							PathExact: "/v1/secret",
+						},
+					},
+				),
+			),
+		},
 		"default-allow-service-wildcard-deny": {
 			intentionDefaultAllow: true,
 			intentions: sorted(
@@ -661,6 +693,44 @@ func TestMakeRBACNetworkAndHTTPFilters(t *testing.T) {
 				),
 			),
 		},
+		"default-deny-allow-with-jwt": {
+			intentionDefaultAllow: false,
+			intentions: sorted(
+				testSourceJWTIntention("web", structs.IntentionActionAllow, &structs.IntentionJWTRequirement{
+					Provider: "okta",
+					Claims: []structs.IntentionJWTClaimVerification{
+						{Path: []string{"roles"}, Value: "admin"},
+					},
+				}),
+			),
+		},
+		"default-allow-allow-with-jwt": {
+			intentionDefaultAllow: true,
+			intentions: sorted(
+				testSourceJWTIntention("web", structs.IntentionActionAllow, &structs.IntentionJWTRequirement{
+					Provider: "okta",
+				}),
+			),
+		},
+		"default-deny-path-allow-with-jwt": {
+			intentionDefaultAllow: false,
+			intentions: sorted(
+				testSourcePermIntention("web",
+					&structs.IntentionPermission{
+						Action: structs.IntentionActionAllow,
+						HTTP: &structs.IntentionHTTPPermission{
+							PathPrefix: "/v1",
+						},
+						JWT: &structs.IntentionJWTRequirement{
+							Provider: "okta",
+							Claims: []structs.IntentionJWTClaimVerification{
+								{Path: []string{"perms", "role"}, Value: "reader"},
+							},
+						},
+					},
+				),
+			),
+		},
 		"default-allow-single-intention-with-kitchen-sink-perms": {
 			intentionDefaultAllow: true,
 			intentions: sorted(
@@ -710,7 +780,7 @@ func TestMakeRBACNetworkAndHTTPFilters(t *testing.T) {
 		tt := tt
 		t.Run(name, func(t *testing.T) {
 			t.Run("network filter", func(t *testing.T) {
-				filter, err := makeRBACNetworkFilter(tt.intentions, tt.intentionDefaultAllow)
+				filter, err := makeRBACNetworkFilter(tt.intentions, tt.intentionDefaultAllow, tt.auditMode)
 				require.NoError(t, err)
 
 				t.Run("current", func(t *testing.T) {
@@ -720,7 +790,7 @@ func TestMakeRBACNetworkAndHTTPFilters(t *testing.T) {
 				})
 			})
 			t.Run("http filter", func(t *testing.T) {
-				filter, err := makeRBACHTTPFilter(tt.intentions, tt.intentionDefaultAllow)
+				filter, err := makeRBACHTTPFilter(tt.intentions, tt.intentionDefaultAllow, tt.auditMode)
 				require.NoError(t, err)
 
 				t.Run("current", func(t *testing.T) {
@@ -733,6 +803,208 @@ func TestMakeRBACNetworkAndHTTPFilters(t *testing.T) {
 	}
 }
 
+func TestMakeJWTAuthnFilter(t *testing.T) {
+	providers := map[string]*structs.JWTProviderConfigEntry{
+		"okta": {
+			Name:      "okta",
+			Issuer:    "https://okta.example.com",
+			Audiences: []string{"api"},
+			JSONWebKeySet: &structs.JSONWebKeySet{
+				Remote: &structs.RemoteJWKS{
+					URI:              "https://okta.example.com/.well-known/jwks.json",
+					RequestTimeoutMs: 1500,
+					CacheDuration:    10 * time.Minute,
+				},
+			},
+			Forwarding: &structs.JWTForwardingConfig{
+				HeaderName: "x-jwt-payload",
+			},
+		},
+		"local": {
+			Name:   "local",
+			Issuer: "test-issuer",
+			JSONWebKeySet: &structs.JSONWebKeySet{
+				Local: &structs.LocalJWKS{
+					JWKS: base64.StdEncoding.EncodeToString([]byte(`{"keys":[]}`)),
+				},
+			},
+		},
+		"unused": {
+			Name:   "unused",
+			Issuer: "unused",
+			JSONWebKeySet: &structs.JSONWebKeySet{
+				Local: &structs.LocalJWKS{Filename: "/etc/jwks.json"},
+			},
+		},
+	}
+
+	ixn := func(jwt *structs.IntentionJWTRequirement, perms ...*structs.IntentionPermission) *structs.Intention {
+		ixn := structs.TestIntention(t)
+		ixn.SourceName = "web"
+		ixn.DestinationName = "api"
+		ixn.JWT = jwt
+		ixn.Permissions = perms
+		if len(perms) > 0 {
+			ixn.Action = ""
+		}
+		return ixn
+	}
+
+	tests := map[string]struct {
+		intentions structs.Intentions
+		expectNil  bool
+	}{
+		"no-jwt": {
+			intentions: structs.Intentions{ixn(nil)},
+			expectNil:  true,
+		},
+		"unknown-provider": {
+			intentions: structs.Intentions{ixn(&structs.IntentionJWTRequirement{Provider: "missing"})},
+			expectNil:  true,
+		},
+		"intention-and-permission-providers": {
+			intentions: structs.Intentions{
+				ixn(&structs.IntentionJWTRequirement{Provider: "okta"},
+					&structs.IntentionPermission{
+						Action: structs.IntentionActionAllow,
+						JWT:    &structs.IntentionJWTRequirement{Provider: "local"},
+					},
+				),
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			filter, err := makeJWTAuthnFilter(providers, tt.intentions)
+			require.NoError(t, err)
+			if tt.expectNil {
+				require.Nil(t, filter)
+				return
+			}
+
+			gotJSON := protoToJSON(t, filter)
+			require.JSONEq(t, goldenSimple(t, filepath.Join("jwt_authn", name), gotJSON), gotJSON)
+		})
+	}
+}
+
+func TestMakeRBACRules_JWT(t *testing.T) {
+	testIntention := func(t *testing.T, src string, action structs.IntentionAction) *structs.Intention {
+		t.Helper()
+		ixn := structs.TestIntention(t)
+		ixn.SourceName = src
+		ixn.DestinationName = "api"
+		ixn.Action = action
+		//nolint:staticcheck
+		ixn.UpdatePrecedence()
+		return ixn
+	}
+	okta := &structs.IntentionJWTRequirement{
+		Provider: "okta",
+		Claims: []structs.IntentionJWTClaimVerification{
+			{Path: []string{"roles"}, Value: "admin"},
+		},
+	}
+	pathPerm := func(action structs.IntentionAction, prefix string, jwt *structs.IntentionJWTRequirement) *structs.IntentionPermission {
+		return &structs.IntentionPermission{
+			Action: action,
+			HTTP:   &structs.IntentionHTTPPermission{PathPrefix: prefix},
+			JWT:    jwt,
+		}
+	}
+
+	allowWithJWT := testIntention(t, "web", structs.IntentionActionAllow)
+	allowWithJWT.JWT = okta
+
+	denyWithJWT := testIntention(t, "web", structs.IntentionActionDeny)
+	denyWithJWT.JWT = okta
+
+	permsWithJWT := testIntention(t, "web", "")
+	permsWithJWT.JWT = okta
+	permsWithJWT.Permissions = []*structs.IntentionPermission{
+		pathPerm(structs.IntentionActionDeny, "/admin", nil),
+		pathPerm(structs.IntentionActionAllow, "/", nil),
+	}
+
+	denyPermWithJWT := testIntention(t, "web", "")
+	denyPermWithJWT.Permissions = []*structs.IntentionPermission{
+		pathPerm(structs.IntentionActionDeny, "/admin", okta),
+		pathPerm(structs.IntentionActionAllow, "/", nil),
+	}
+
+	validToken := map[string]interface{}{"roles": []interface{}{"admin"}}
+	wrongClaims := map[string]interface{}{"roles": "reader"}
+
+	type request struct {
+		source string
+		path   string
+		token  map[string]interface{}
+		allow  bool
+	}
+	tests := map[string]struct {
+		intention *structs.Intention
+		requests  []request
+	}{
+		"allow intention": {
+			intention: allowWithJWT,
+			requests: []request{
+				{source: "web", path: "/", token: nil, allow: false},
+				{source: "web", path: "/", token: wrongClaims, allow: false},
+				{source: "web", path: "/", token: validToken, allow: true},
+			},
+		},
+		"deny intention": {
+			intention: denyWithJWT,
+			requests: []request{
+				{source: "web", path: "/", token: nil, allow: false},
+				{source: "web", path: "/", token: validToken, allow: false},
+			},
+		},
+		"permissions": {
+			intention: permsWithJWT,
+			requests: []request{
+				{source: "web", path: "/admin", token: nil, allow: false},
+				{source: "web", path: "/admin", token: validToken, allow: false},
+				{source: "web", path: "/", token: nil, allow: false},
+				{source: "web", path: "/", token: validToken, allow: true},
+			},
+		},
+		"deny permission": {
+			intention: denyPermWithJWT,
+			requests: []request{
+				{source: "web", path: "/admin", token: nil, allow: false},
+				{source: "web", path: "/admin", token: validToken, allow: false},
+				{source: "web", path: "/", token: nil, allow: true},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		for _, defaultAllow := range []bool{true, false} {
+			rules, err := makeRBACRules(structs.Intentions{tt.intention}, defaultAllow, true)
+			require.NoError(t, err)
+
+			for _, req := range tt.requests {
+				desc := fmt.Sprintf("%s: default allow %t: %s %s with token %v", name, defaultAllow, req.source, req.path, req.token)
+				var metadata map[string]interface{}
+				if req.token != nil {
+					metadata = map[string]interface{}{
+						jwtAuthnFilterName: map[string]interface{}{jwtPayloadPrefix + "okta": req.token},
+					}
+				}
+				allowed := evalRBAC(t, rules, structs.NewServiceName(req.source, nil), req.path, metadata)
+				require.Equal(t, req.allow, allowed, desc)
+			}
+
+			// Other sources are not subject to the intention.
+			allowed := evalRBAC(t, rules, structs.NewServiceName("other", nil), "/admin", nil)
+			require.Equal(t, defaultAllow, allowed, "%s: default allow %t: other source", name, defaultAllow)
+		}
+	}
+}
+
 func TestRemoveSameSourceIntentions(t *testing.T) {
 	testIntention := func(t *testing.T, src, dst string) *structs.Intention {
 		t.Helper()
@@ -869,3 +1141,137 @@ func makeServiceNameSlice(slice []string) []structs.ServiceName {
 	}
 	return out
 }
+
+// evalRBAC evaluates the subset of the RBAC rules generated from intentions
+// against a request from the given source, returning whether it is allowed.
+func evalRBAC(t *testing.T, rules *envoy_rbac_v3.RBAC, source structs.ServiceName, path string, metadata map[string]interface{}) bool {
+	t.Helper()
+
+	principal := connect.SpiffeIDService{
+		Host:       "11111111-2222-3333-4444-555555555555.consul",
+		Namespace:  source.NamespaceOrDefault(),
+		Partition:  source.PartitionOrDefault(),
+		Datacenter: "dc1",
+		Service:    source.Name,
+	}
+	name := principal.URI().String()
+
+	matched := false
+	for _, policy := range rules.Policies {
+		principalMatches := false
+		for _, p := range policy.Principals {
+			if evalRBACPrincipal(t, p, name) {
+				principalMatches = true
+				break
+			}
+		}
+		if !principalMatches {
+			continue
+		}
+		for _, perm := range policy.Permissions {
+			if evalRBACPermission(t, perm, path, metadata) {
+				matched = true
+			}
+		}
+	}
+
+	if rules.Action == envoy_rbac_v3.RBAC_ALLOW {
+		return matched
+	}
+	return !matched
+}
+
+func evalRBACPrincipal(t *testing.T, p *envoy_rbac_v3.Principal, name string) bool {
+	switch id := p.Identifier.(type) {
+	case *envoy_rbac_v3.Principal_Any:
+		return id.Any
+	case *envoy_rbac_v3.Principal_AndIds:
+		for _, p := range id.AndIds.Ids {
+			if !evalRBACPrincipal(t, p, name) {
+				return false
+			}
+		}
+		return true
+	case *envoy_rbac_v3.Principal_NotId:
+		return !evalRBACPrincipal(t, id.NotId, name)
+	case *envoy_rbac_v3.Principal_Authenticated_:
+		regex := id.Authenticated.PrincipalName.GetSafeRegex().GetRegex()
+		return regexp.MustCompile(regex).MatchString(name)
+	default:
+		t.Fatalf("unsupported principal %T", id)
+		return false
+	}
+}
+
+func evalRBACPermission(t *testing.T, p *envoy_rbac_v3.Permission, path string, metadata map[string]interface{}) bool {
+	switch rule := p.Rule.(type) {
+	case *envoy_rbac_v3.Permission_Any:
+		return rule.Any
+	case *envoy_rbac_v3.Permission_AndRules:
+		for _, p := range rule.AndRules.Rules {
+			if !evalRBACPermission(t, p, path, metadata) {
+				return false
+			}
+		}
+		return true
+	case *envoy_rbac_v3.Permission_OrRules:
+		for _, p := range rule.OrRules.Rules {
+			if evalRBACPermission(t, p, path, metadata) {
+				return true
+			}
+		}
+		return false
+	case *envoy_rbac_v3.Permission_NotRule:
+		return !evalRBACPermission(t, rule.NotRule, path, metadata)
+	case *envoy_rbac_v3.Permission_UrlPath:
+		return evalStringMatcher(t, rule.UrlPath.GetPath(), path)
+	case *envoy_rbac_v3.Permission_Metadata:
+		var value interface{} = metadata[rule.Metadata.Filter]
+		for _, segment := range rule.Metadata.Path {
+			m, ok := value.(map[string]interface{})
+			if !ok {
+				return false
+			}
+			if value, ok = m[segment.GetKey()]; !ok {
+				return false
+			}
+		}
+		return evalValueMatcher(t, rule.Metadata.Value, value)
+	default:
+		t.Fatalf("unsupported permission %T", rule)
+		return false
+	}
+}
+
+func evalValueMatcher(t *testing.T, m *envoy_matcher_v3.ValueMatcher, value interface{}) bool {
+	switch pattern := m.MatchPattern.(type) {
+	case *envoy_matcher_v3.ValueMatcher_PresentMatch:
+		return pattern.PresentMatch
+	case *envoy_matcher_v3.ValueMatcher_StringMatch:
+		s, ok := value.(string)
+		return ok && evalStringMatcher(t, pattern.StringMatch, s)
+	case *envoy_matcher_v3.ValueMatcher_ListMatch:
+		list, _ := value.([]interface{})
+		for _, v := range list {
+			if evalValueMatcher(t, pattern.ListMatch.GetOneOf(), v) {
+				return true
+			}
+		}
+		return false
+	default:
+		t.Fatalf("unsupported value matcher %T", pattern)
+		return false
+	}
+}
+
+func evalStringMatcher(t *testing.T, m *envoy_matcher_v3.StringMatcher, s string) bool {
+	switch pattern := m.MatchPattern.(type) {
+	case *envoy_matcher_v3.StringMatcher_Exact:
+		return s == pattern.Exact
+	case *envoy_matcher_v3.StringMatcher_Prefix:
+		return strings.HasPrefix(s, pattern.Prefix)
+	default:
+		t.Fatalf("unsupported string matcher %T", pattern)
+		return false
+	}
+}
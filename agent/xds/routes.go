@@ -9,10 +9,14 @@ import (
 
 	envoy_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	envoy_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_common_fault_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/common/fault/v3"
+	envoy_fault_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/fault/v3"
 	envoy_matcher_v3 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	envoy_type_v3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
 
 	"github.com/hashicorp/consul/agent/connect"
 	"github.com/hashicorp/consul/agent/proxycfg"
@@ -383,6 +387,14 @@ func makeUpstreamRouteForDiscoveryChain(
 				return nil, fmt.Errorf("failed to apply load balancer configuration to route action: %v", err)
 			}
 
+			if discoveryRoute.MirrorNode != "" {
+				policy, err := makeRequestMirrorPolicy(discoveryRoute.MirrorNode, discoveryRoute.Definition.Destination.Mirror, chain)
+				if err != nil {
+					return nil, err
+				}
+				routeAction.Route.RequestMirrorPolicies = append(routeAction.Route.RequestMirrorPolicies, policy)
+			}
+
 			// TODO(rb): Better help handle the envoy case where you need (prefix=/foo/,rewrite=/) and (exact=/foo,rewrite=/) to do a full rewrite
 
 			destination := discoveryRoute.Definition.Destination
@@ -423,6 +435,10 @@ func makeUpstreamRouteForDiscoveryChain(
 				if err := injectHeaderManipToRoute(destination, route); err != nil {
 					return nil, fmt.Errorf("failed to apply header manipulation configuration to route: %v", err)
 				}
+
+				if err := injectFaultToRoute(destination.Fault, route); err != nil {
+					return nil, fmt.Errorf("failed to apply fault injection configuration to route: %v", err)
+				}
 			}
 
 			route.Match = routeMatch
@@ -489,6 +505,10 @@ func makeRouteMatchForDiscoveryRoute(discoveryRoute *structs.DiscoveryRoute) *en
 		return makeDefaultRouteMatch()
 	}
 
+	if match.GRPC != nil {
+		return makeRouteMatchForGRPC(match.GRPC)
+	}
+
 	em := &envoy_route_v3.RouteMatch{}
 
 	switch {
@@ -511,43 +531,7 @@ func makeRouteMatchForDiscoveryRoute(discoveryRoute *structs.DiscoveryRoute) *en
 	}
 
 	if len(match.HTTP.Header) > 0 {
-		em.Headers = make([]*envoy_route_v3.HeaderMatcher, 0, len(match.HTTP.Header))
-		for _, hdr := range match.HTTP.Header {
-			eh := &envoy_route_v3.HeaderMatcher{
-				Name: hdr.Name,
-			}
-
-			switch {
-			case hdr.Exact != "":
-				eh.HeaderMatchSpecifier = &envoy_route_v3.HeaderMatcher_ExactMatch{
-					ExactMatch: hdr.Exact,
-				}
-			case hdr.Regex != "":
-				eh.HeaderMatchSpecifier = &envoy_route_v3.HeaderMatcher_SafeRegexMatch{
-					SafeRegexMatch: makeEnvoyRegexMatch(hdr.Regex),
-				}
-			case hdr.Prefix != "":
-				eh.HeaderMatchSpecifier = &envoy_route_v3.HeaderMatcher_PrefixMatch{
-					PrefixMatch: hdr.Prefix,
-				}
-			case hdr.Suffix != "":
-				eh.HeaderMatchSpecifier = &envoy_route_v3.HeaderMatcher_SuffixMatch{
-					SuffixMatch: hdr.Suffix,
-				}
-			case hdr.Present:
-				eh.HeaderMatchSpecifier = &envoy_route_v3.HeaderMatcher_PresentMatch{
-					PresentMatch: true,
-				}
-			default:
-				continue // skip this impossible situation
-			}
-
-			if hdr.Invert {
-				eh.InvertMatch = true
-			}
-
-			em.Headers = append(em.Headers, eh)
-		}
+		em.Headers = makeHeaderMatchers(match.HTTP.Header)
 	}
 
 	if len(match.HTTP.Methods) > 0 {
@@ -602,6 +586,76 @@ func makeRouteMatchForDiscoveryRoute(discoveryRoute *structs.DiscoveryRoute) *en
 	return em
 }
 
+// makeRouteMatchForGRPC matches the service and method of a gRPC request by
+// its HTTP/2 path, which has the form "/<service>/<method>".
+func makeRouteMatchForGRPC(match *structs.ServiceRouteGRPCMatch) *envoy_route_v3.RouteMatch {
+	em := &envoy_route_v3.RouteMatch{
+		Grpc: &envoy_route_v3.RouteMatch_GrpcRouteMatchOptions{},
+	}
+
+	switch {
+	case match.Method != "":
+		em.PathSpecifier = &envoy_route_v3.RouteMatch_Path{
+			Path: "/" + match.Service + "/" + match.Method,
+		}
+	case match.Service != "":
+		em.PathSpecifier = &envoy_route_v3.RouteMatch_Prefix{
+			Prefix: "/" + match.Service + "/",
+		}
+	default:
+		em.PathSpecifier = &envoy_route_v3.RouteMatch_Prefix{
+			Prefix: "/",
+		}
+	}
+
+	if len(match.Metadata) > 0 {
+		em.Headers = makeHeaderMatchers(match.Metadata)
+	}
+
+	return em
+}
+
+func makeHeaderMatchers(hdrs []structs.ServiceRouteHTTPMatchHeader) []*envoy_route_v3.HeaderMatcher {
+	out := make([]*envoy_route_v3.HeaderMatcher, 0, len(hdrs))
+	for _, hdr := range hdrs {
+		eh := &envoy_route_v3.HeaderMatcher{
+			Name: hdr.Name,
+		}
+
+		switch {
+		case hdr.Exact != "":
+			eh.HeaderMatchSpecifier = &envoy_route_v3.HeaderMatcher_ExactMatch{
+				ExactMatch: hdr.Exact,
+			}
+		case hdr.Regex != "":
+			eh.HeaderMatchSpecifier = &envoy_route_v3.HeaderMatcher_SafeRegexMatch{
+				SafeRegexMatch: makeEnvoyRegexMatch(hdr.Regex),
+			}
+		case hdr.Prefix != "":
+			eh.HeaderMatchSpecifier = &envoy_route_v3.HeaderMatcher_PrefixMatch{
+				PrefixMatch: hdr.Prefix,
+			}
+		case hdr.Suffix != "":
+			eh.HeaderMatchSpecifier = &envoy_route_v3.HeaderMatcher_SuffixMatch{
+				SuffixMatch: hdr.Suffix,
+			}
+		case hdr.Present:
+			eh.HeaderMatchSpecifier = &envoy_route_v3.HeaderMatcher_PresentMatch{
+				PresentMatch: true,
+			}
+		default:
+			continue // skip this impossible situation
+		}
+
+		if hdr.Invert {
+			eh.InvertMatch = true
+		}
+
+		out = append(out, eh)
+	}
+	return out
+}
+
 func makeDefaultRouteMatch() *envoy_route_v3.RouteMatch {
 	return &envoy_route_v3.RouteMatch{
 		PathSpecifier: &envoy_route_v3.RouteMatch_Prefix{
@@ -657,6 +711,17 @@ func makeRouteActionForSplitter(splits []*structs.DiscoverySplit, chain *structs
 		clusters = append(clusters, cw)
 	}
 
+	// Envoy can only mirror at the route level, the compiler ensures that all
+	// the splits have the same mirror so it applies to every request.
+	var mirrorPolicies []*envoy_route_v3.RouteAction_RequestMirrorPolicy
+	if len(splits) > 0 && splits[0].MirrorNode != "" {
+		policy, err := makeRequestMirrorPolicy(splits[0].MirrorNode, splits[0].Definition.Mirror, chain)
+		if err != nil {
+			return nil, err
+		}
+		mirrorPolicies = append(mirrorPolicies, policy)
+	}
+
 	return &envoy_route_v3.Route_Route{
 		Route: &envoy_route_v3.RouteAction{
 			ClusterSpecifier: &envoy_route_v3.RouteAction_WeightedClusters{
@@ -665,6 +730,33 @@ func makeRouteActionForSplitter(splits []*structs.DiscoverySplit, chain *structs
 					TotalWeight: makeUint32Value(10000), // scaled up 100%
 				},
 			},
+			RequestMirrorPolicies: mirrorPolicies,
+		},
+	}, nil
+}
+
+// makeRequestMirrorPolicy builds a mirror policy for the cluster of the
+// resolver node named by mirrorNode.
+func makeRequestMirrorPolicy(
+	mirrorNode string,
+	mirror *structs.RequestMirror,
+	chain *structs.CompiledDiscoveryChain,
+) (*envoy_route_v3.RouteAction_RequestMirrorPolicy, error) {
+	node := chain.Nodes[mirrorNode]
+	if node == nil || node.Type != structs.DiscoveryGraphNodeTypeResolver {
+		return nil, fmt.Errorf("missing mirror resolver node %q in compiled discovery chain for: %s", mirrorNode, chain.ServiceName)
+	}
+	target := chain.Targets[node.Resolver.Target]
+
+	pct := float32(100)
+	if mirror != nil && mirror.Percentage != 0 {
+		pct = mirror.Percentage
+	}
+
+	return &envoy_route_v3.RouteAction_RequestMirrorPolicy{
+		Cluster: CustomizeClusterName(target.Name, chain),
+		RuntimeFraction: &envoy_core_v3.RuntimeFractionalPercent{
+			DefaultValue: makeFractionalPercent(pct),
 		},
 	}, nil
 }
@@ -770,6 +862,66 @@ func injectHeaderManipToRoute(dest *structs.ServiceRouteDestination, r *envoy_ro
 	return nil
 }
 
+// injectFaultToRoute overrides the configuration of the fault filter for a
+// single route. The filter itself is only present in the HTTP filter chain of
+// listeners whose discovery chain uses fault injection.
+func injectFaultToRoute(fault *structs.ServiceRouteFault, r *envoy_route_v3.Route) error {
+	if fault == nil {
+		return nil
+	}
+
+	cfg := &envoy_fault_v3.HTTPFault{}
+	if fault.Delay != nil {
+		cfg.Delay = &envoy_common_fault_v3.FaultDelay{
+			FaultDelaySecifier: &envoy_common_fault_v3.FaultDelay_FixedDelay{
+				FixedDelay: ptypes.DurationProto(fault.Delay.Duration),
+			},
+			Percentage: makeFractionalPercent(fault.Delay.Percentage),
+		}
+	}
+	if fault.Abort != nil {
+		cfg.Abort = makeFaultAbort(fault.Abort)
+	}
+	if len(fault.Header) > 0 {
+		cfg.Headers = makeHeaderMatchers(fault.Header)
+	}
+
+	faultAny, err := ptypes.MarshalAny(cfg)
+	if err != nil {
+		return err
+	}
+	if r.TypedPerFilterConfig == nil {
+		r.TypedPerFilterConfig = make(map[string]*any.Any)
+	}
+	r.TypedPerFilterConfig[faultFilterName] = faultAny
+	return nil
+}
+
+// makeFaultAbort returns the abort configuration of the fault filter.
+func makeFaultAbort(abort *structs.ServiceRouteFaultAbort) *envoy_fault_v3.FaultAbort {
+	return &envoy_fault_v3.FaultAbort{
+		Percentage: makeFractionalPercent(abort.Percentage),
+		ErrorType: &envoy_fault_v3.FaultAbort_HttpStatus{
+			HttpStatus: abort.HTTPStatus,
+		},
+	}
+}
+
+// makeFractionalPercent converts a 0-100 percentage from a config entry into
+// the fractional form used by Envoy. In config entries a zero percentage means
+// that every request is affected.
+func makeFractionalPercent(pct float32) *envoy_type_v3.FractionalPercent {
+	if pct == 0 {
+		pct = 100
+	}
+	// Scale by 10000 so that fractional percentages down to 0.0001% survive
+	// the conversion to an integer numerator.
+	return &envoy_type_v3.FractionalPercent{
+		Numerator:   uint32(pct * 10000),
+		Denominator: envoy_type_v3.FractionalPercent_MILLION,
+	}
+}
+
 func injectHeaderManipToVirtualHost(dest *structs.IngressService, vh *envoy_route_v3.VirtualHost) error {
 	if !dest.RequestHeaders.IsZero() {
 		vh.RequestHeadersToAdd = append(
@@ -66,11 +66,26 @@ func TestRoutesFromSnapshot(t *testing.T) {
 			create: proxycfg.TestConfigSnapshotDiscoveryChainWithGRPCRouter,
 			setup:  nil,
 		},
+		{
+			name:   "connect-proxy-with-grpc-match",
+			create: proxycfg.TestConfigSnapshotDiscoveryChainWithGRPCMatch,
+			setup:  nil,
+		},
 		{
 			name:   "connect-proxy-with-chain-and-router",
 			create: proxycfg.TestConfigSnapshotDiscoveryChainWithRouter,
 			setup:  nil,
 		},
+		{
+			name:   "connect-proxy-with-chain-and-fault-injection",
+			create: proxycfg.TestConfigSnapshotDiscoveryChainWithFaultInjection,
+			setup:  nil,
+		},
+		{
+			name:   "connect-proxy-with-chain-and-mirror",
+			create: proxycfg.TestConfigSnapshotDiscoveryChainWithMirror,
+			setup:  nil,
+		},
 		{
 			name:   "connect-proxy-lb-in-resolver",
 			create: proxycfg.TestConfigSnapshotDiscoveryChainWithLB,
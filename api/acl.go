@@ -1,6 +1,12 @@
 package api
 
 import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -34,10 +40,11 @@ type ACLToken struct {
 	AccessorID        string
 	SecretID          string
 	Description       string
-	Policies          []*ACLTokenPolicyLink `json:",omitempty"`
-	Roles             []*ACLTokenRoleLink   `json:",omitempty"`
-	ServiceIdentities []*ACLServiceIdentity `json:",omitempty"`
-	NodeIdentities    []*ACLNodeIdentity    `json:",omitempty"`
+	Policies          []*ACLTokenPolicyLink    `json:",omitempty"`
+	Roles             []*ACLTokenRoleLink      `json:",omitempty"`
+	ServiceIdentities []*ACLServiceIdentity    `json:",omitempty"`
+	NodeIdentities    []*ACLNodeIdentity       `json:",omitempty"`
+	PolicyTemplates   []*ACLPolicyTemplateLink `json:",omitempty"`
 	Local             bool
 	AuthMethod        string        `json:",omitempty"`
 	ExpirationTTL     time.Duration `json:",omitempty"`
@@ -45,6 +52,12 @@ type ACLToken struct {
 	CreateTime        time.Time     `json:",omitempty"`
 	Hash              []byte        `json:",omitempty"`
 
+	// LastUsedTime is the approximate time when the token was last used in
+	// the datacenter. In the primary datacenter, it accounts for the usage of
+	// global tokens in all the datacenters. It is nil when the token was never
+	// used.
+	LastUsedTime *time.Time `json:",omitempty"`
+
 	// DEPRECATED (ACL-Legacy-Compat)
 	// Rules will only be present for legacy tokens returned via the new APIs
 	Rules string `json:",omitempty"`
@@ -68,14 +81,16 @@ type ACLTokenListEntry struct {
 	AccessorID        string
 	SecretID          string
 	Description       string
-	Policies          []*ACLTokenPolicyLink `json:",omitempty"`
-	Roles             []*ACLTokenRoleLink   `json:",omitempty"`
-	ServiceIdentities []*ACLServiceIdentity `json:",omitempty"`
-	NodeIdentities    []*ACLNodeIdentity    `json:",omitempty"`
+	Policies          []*ACLTokenPolicyLink    `json:",omitempty"`
+	Roles             []*ACLTokenRoleLink      `json:",omitempty"`
+	ServiceIdentities []*ACLServiceIdentity    `json:",omitempty"`
+	NodeIdentities    []*ACLNodeIdentity       `json:",omitempty"`
+	PolicyTemplates   []*ACLPolicyTemplateLink `json:",omitempty"`
 	Local             bool
 	AuthMethod        string     `json:",omitempty"`
 	ExpirationTime    *time.Time `json:",omitempty"`
 	CreateTime        time.Time
+	LastUsedTime      *time.Time `json:",omitempty"`
 	Hash              []byte
 	Legacy            bool
 
@@ -132,6 +147,21 @@ type ACLNodeIdentity struct {
 	Datacenter string
 }
 
+// ACLPolicyTemplateLink links a token or a role to a policy template and sets
+// the values of the variables of the template.
+type ACLPolicyTemplateLink struct {
+	ID       string
+	Template string
+	Vars     map[string]string `json:",omitempty"`
+}
+
+// ACLPolicyVariable declares a variable of a policy template. Type is
+// "string", "int" or "bool".
+type ACLPolicyVariable struct {
+	Name string
+	Type string
+}
+
 // ACLPolicy represents an ACL Policy.
 type ACLPolicy struct {
 	ID          string
@@ -139,6 +169,12 @@ type ACLPolicy struct {
 	Description string
 	Rules       string
 	Datacenters []string
+
+	// Variables makes the policy a template. Its rules reference the
+	// variables as ${name} and it only applies through the PolicyTemplates
+	// links of tokens and roles.
+	Variables []*ACLPolicyVariable `json:",omitempty"`
+
 	Hash        []byte
 	CreateIndex uint64
 	ModifyIndex uint64
@@ -157,6 +193,7 @@ type ACLPolicyListEntry struct {
 	Name        string
 	Description string
 	Datacenters []string
+	Template    bool `json:",omitempty"`
 	Hash        []byte
 	CreateIndex uint64
 	ModifyIndex uint64
@@ -177,9 +214,10 @@ type ACLRole struct {
 	ID                string
 	Name              string
 	Description       string
-	Policies          []*ACLRolePolicyLink  `json:",omitempty"`
-	ServiceIdentities []*ACLServiceIdentity `json:",omitempty"`
-	NodeIdentities    []*ACLNodeIdentity    `json:",omitempty"`
+	Policies          []*ACLRolePolicyLink     `json:",omitempty"`
+	ServiceIdentities []*ACLServiceIdentity    `json:",omitempty"`
+	NodeIdentities    []*ACLNodeIdentity       `json:",omitempty"`
+	PolicyTemplates   []*ACLPolicyTemplateLink `json:",omitempty"`
 	Hash              []byte
 	CreateIndex       uint64
 	ModifyIndex       uint64
@@ -462,12 +500,115 @@ func (c *OIDCAuthMethodConfig) RenderToConfig() map[string]interface{} {
 	}
 }
 
+// LDAPAuthMethodConfig is the config for the built-in Consul auth method for
+// LDAP. Logging in with this method uses "username:password" as the bearer
+// token.
+type LDAPAuthMethodConfig struct {
+	URL          string `json:",omitempty"`
+	CACert       string `json:",omitempty"`
+	StartTLS     bool   `json:",omitempty"`
+	BindDN       string `json:",omitempty"`
+	BindPassword string `json:",omitempty"`
+	UserDN       string `json:",omitempty"`
+	UserAttr     string `json:",omitempty"`
+	UserFilter   string `json:",omitempty"`
+	GroupDN      string `json:",omitempty"`
+	GroupFilter  string `json:",omitempty"`
+	GroupAttr    string `json:",omitempty"`
+}
+
+// RenderToConfig converts this into a map[string]interface{} suitable for use
+// in the ACLAuthMethod.Config field.
+func (c *LDAPAuthMethodConfig) RenderToConfig() map[string]interface{} {
+	return map[string]interface{}{
+		"URL":          c.URL,
+		"CACert":       c.CACert,
+		"StartTLS":     c.StartTLS,
+		"BindDN":       c.BindDN,
+		"BindPassword": c.BindPassword,
+		"UserDN":       c.UserDN,
+		"UserAttr":     c.UserAttr,
+		"UserFilter":   c.UserFilter,
+		"GroupDN":      c.GroupDN,
+		"GroupFilter":  c.GroupFilter,
+		"GroupAttr":    c.GroupAttr,
+	}
+}
+
+// X509AuthMethodConfig is the config for the built-in Consul auth method for
+// TLS client certificates. Logging in with this method requires a bearer token
+// created by NewX509LoginToken.
+type X509AuthMethodConfig struct {
+	CACerts []string `json:",omitempty"`
+}
+
+// RenderToConfig converts this into a map[string]interface{} suitable for use
+// in the ACLAuthMethod.Config field.
+func (c *X509AuthMethodConfig) RenderToConfig() map[string]interface{} {
+	return map[string]interface{}{
+		"CACerts": c.CACerts,
+	}
+}
+
 type ACLLoginParams struct {
 	AuthMethod  string
 	BearerToken string
 	Meta        map[string]string `json:",omitempty"`
 }
 
+type ACLLoginChallengeParams struct {
+	AuthMethod string
+}
+
+// x509ChallengeSignaturePrefix is prepended to the challenge before it is
+// signed.
+const x509ChallengeSignaturePrefix = "consul-x509-login:"
+
+// x509LoginToken is the bearer token used to log in with an x509 auth method.
+type x509LoginToken struct {
+	Challenge    string
+	Certificates [][]byte
+	Signature    []byte
+}
+
+// NewX509LoginToken returns the bearer token used to log in with an x509 auth
+// method. It proves that the client holds the private key of the certificate
+// by signing the challenge returned by LoginChallenge.
+func NewX509LoginToken(challenge string, cert tls.Certificate) (string, error) {
+	if len(cert.Certificate) == 0 {
+		return "", fmt.Errorf("no client certificate was provided")
+	}
+	signer, ok := cert.PrivateKey.(crypto.Signer)
+	if !ok {
+		return "", fmt.Errorf("unsupported client key type %T", cert.PrivateKey)
+	}
+
+	var (
+		signature []byte
+		err       error
+	)
+	message := []byte(x509ChallengeSignaturePrefix + challenge)
+	if _, ok := signer.Public().(ed25519.PublicKey); ok {
+		signature, err = signer.Sign(rand.Reader, message, crypto.Hash(0))
+	} else {
+		digest := sha256.Sum256(message)
+		signature, err = signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to sign login challenge: %v", err)
+	}
+
+	buf, err := json.Marshal(&x509LoginToken{
+		Challenge:    challenge,
+		Certificates: cert.Certificate,
+		Signature:    signature,
+	})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
 type ACLOIDCAuthURLParams struct {
 	AuthMethod  string
 	RedirectURI string
@@ -475,6 +616,55 @@ type ACLOIDCAuthURLParams struct {
 	Meta        map[string]string `json:",omitempty"`
 }
 
+// ACLAuthorizationRequest is an authorization to explain with
+// ACL.Explain.
+type ACLAuthorizationRequest struct {
+	Resource  string
+	Segment   string `json:",omitempty"`
+	Access    string
+	Namespace string `json:",omitempty"`
+	Partition string `json:",omitempty"`
+}
+
+// ACLAuthorizationExplanation describes how an authorization decision was
+// reached.
+type ACLAuthorizationExplanation struct {
+	ACLAuthorizationRequest
+	Allow bool
+
+	// Rule is the policy rule that rendered the decision. It is nil when no
+	// single rule did, for example when the default policy applies.
+	Rule *ACLExplainedRule `json:",omitempty"`
+
+	// Sources are the policies and identities of the token, directly or
+	// through a role, that define Rule.
+	Sources []ACLExplainSource `json:",omitempty"`
+
+	// DefaultPolicy is set to the ACL default policy when it rendered the
+	// decision.
+	DefaultPolicy string `json:",omitempty"`
+}
+
+// ACLExplainedRule identifies the policy rule that rendered an authorization
+// decision.
+type ACLExplainedRule struct {
+	Resource string
+	Segment  string
+	Prefix   bool
+	Access   string
+}
+
+// ACLExplainSource is a policy or an identity of a token defining the rule
+// that rendered an authorization decision. Type is "policy",
+// "service-identity", "node-identity" or "policy-template".
+type ACLExplainSource struct {
+	Type     string
+	ID       string `json:",omitempty"`
+	Name     string
+	RoleID   string `json:",omitempty"`
+	RoleName string `json:",omitempty"`
+}
+
 // ACL can be used to query the ACL endpoints
 type ACL struct {
 	c *Client
@@ -814,6 +1004,35 @@ func (a *ACL) TokenReadSelf(q *QueryOptions) (*ACLToken, *QueryMeta, error) {
 	return &out, qm, nil
 }
 
+// Explain performs the authorizations and reports the rule, policies, roles
+// and identities that rendered each decision. The token of the request is
+// explained unless accessorID names another token, which requires acl:read.
+func (a *ACL) Explain(accessorID string, requests []ACLAuthorizationRequest, q *QueryOptions) ([]ACLAuthorizationExplanation, *QueryMeta, error) {
+	r := a.c.newRequest("POST", "/v1/internal/acl/explain")
+	r.setQueryOptions(q)
+	if accessorID != "" {
+		r.params.Set("accessor", accessorID)
+	}
+	r.obj = requests
+	rtt, resp, err := a.c.doRequest(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return nil, nil, err
+	}
+	qm := &QueryMeta{}
+	parseQueryMeta(resp, qm)
+	qm.RequestTime = rtt
+
+	var out []ACLAuthorizationExplanation
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, nil, err
+	}
+	return out, qm, nil
+}
+
 // TokenList lists all tokens. The listing does not contain any SecretIDs as those
 // may only be retrieved by a call to TokenRead.
 func (a *ACL) TokenList(q *QueryOptions) ([]*ACLTokenListEntry, *QueryMeta, error) {
@@ -1480,6 +1699,39 @@ func (a *ACL) Login(auth *ACLLoginParams, q *WriteOptions) (*ACLToken, *WriteMet
 	return &out, wm, nil
 }
 
+// LoginChallenge requests a challenge to sign when logging in with an auth
+// method that requires one, such as the x509 auth method. The challenge can
+// only be used once and expires after a few minutes.
+func (a *ACL) LoginChallenge(auth *ACLLoginChallengeParams, q *WriteOptions) (string, *WriteMeta, error) {
+	if auth.AuthMethod == "" {
+		return "", nil, fmt.Errorf("Must specify an auth method name")
+	}
+
+	r := a.c.newRequest("POST", "/v1/acl/login/challenge")
+	r.setWriteOptions(q)
+	r.obj = auth
+
+	rtt, resp, err := a.c.doRequest(r)
+	if err != nil {
+		return "", nil, err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return "", nil, err
+	}
+
+	wm := &WriteMeta{RequestTime: rtt}
+	var out aclLoginChallengeResponse
+	if err := decodeBody(resp, &out); err != nil {
+		return "", nil, err
+	}
+	return out.Challenge, wm, nil
+}
+
+type aclLoginChallengeResponse struct {
+	Challenge string
+}
+
 // Logout is used to destroy a Consul Token created via Login().
 func (a *ACL) Logout(q *WriteOptions) (*WriteMeta, error) {
 	r := a.c.newRequest("POST", "/v1/acl/logout")
@@ -3,10 +3,12 @@ package api
 import (
 	"bufio"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 )
 
 // ServiceKind is the kind of service being registered.
@@ -128,6 +130,7 @@ type AgentServiceConnectProxyConfig struct {
 	Upstreams              []Upstream              `json:",omitempty"`
 	MeshGateway            MeshGatewayConfig       `json:",omitempty"`
 	Expose                 ExposeConfig            `json:",omitempty"`
+	AccessLogs             *AccessLogsConfig       `json:",omitempty"`
 }
 
 const (
@@ -410,6 +413,32 @@ type AgentAuthorize struct {
 	Reason     string
 }
 
+// AgentAuthorizeAuditDenial summarizes the connections from a source to a
+// destination service that intentions would have denied while they were in
+// audit mode.
+type AgentAuthorizeAuditDenial struct {
+	SourceName           string
+	SourceNS             string `json:",omitempty"`
+	SourcePartition      string `json:",omitempty"`
+	DestinationName      string
+	DestinationNS        string `json:",omitempty"`
+	DestinationPartition string `json:",omitempty"`
+	Reason               string
+	Count                uint64
+	FirstDenied          time.Time
+	LastDenied           time.Time
+}
+
+// AgentProxyXDS is the response structure for the xDS resources rendered
+// for a local proxy. Each resource is the JSON encoding of its Envoy
+// protobuf message.
+type AgentProxyXDS struct {
+	Listeners []json.RawMessage
+	Routes    []json.RawMessage
+	Clusters  []json.RawMessage
+	Endpoints []json.RawMessage
+}
+
 // ConnectProxyConfig is the response structure for agent-local proxy
 // configuration.
 type ConnectProxyConfig struct {
@@ -1081,6 +1110,55 @@ func (a *Agent) ConnectAuthorize(auth *AgentAuthorizeParams) (*AgentAuthorize, e
 	return &out, nil
 }
 
+// ConnectAuthorizeAudit returns the summary of the connections authorized by
+// the agent that intentions would have denied while in audit mode, per source
+// and destination service.
+func (a *Agent) ConnectAuthorizeAudit(q *QueryOptions) ([]*AgentAuthorizeAuditDenial, *QueryMeta, error) {
+	r := a.c.newRequest("GET", "/v1/agent/connect/authorize/audit")
+	r.setQueryOptions(q)
+	rtt, resp, err := a.c.doRequest(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return nil, nil, err
+	}
+	qm := &QueryMeta{}
+	parseQueryMeta(resp, qm)
+	qm.RequestTime = rtt
+
+	var out []*AgentAuthorizeAuditDenial
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, nil, err
+	}
+	return out, qm, nil
+}
+
+// ConnectProxyXDS returns the listeners, routes, clusters and endpoints that
+// the agent would send to the given local proxy over xDS.
+func (a *Agent) ConnectProxyXDS(proxyID string, q *QueryOptions) (*AgentProxyXDS, *QueryMeta, error) {
+	r := a.c.newRequest("GET", "/v1/agent/connect/proxy/"+proxyID+"/xds")
+	r.setQueryOptions(q)
+	rtt, resp, err := a.c.doRequest(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return nil, nil, err
+	}
+	qm := &QueryMeta{}
+	parseQueryMeta(resp, qm)
+	qm.RequestTime = rtt
+
+	var out AgentProxyXDS
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, nil, err
+	}
+	return &out, qm, nil
+}
+
 // ConnectCARoots returns the list of roots.
 func (a *Agent) ConnectCARoots(q *QueryOptions) (*CARootList, *QueryMeta, error) {
 	r := a.c.newRequest("GET", "/v1/agent/connect/ca/roots")
@@ -1,10 +1,17 @@
 package api
 
 import (
+	"fmt"
 	"net"
 	"strconv"
 )
 
+// MetaNodeTTL is the node metadata key holding the TTL of a node registered
+// through the catalog, as a duration string. The node is deregistered along
+// with its services and checks if it isn't registered again or renewed in
+// time.
+const MetaNodeTTL = "consul-node-ttl"
+
 type Weights struct {
 	Passing int
 	Warning int
@@ -158,6 +165,35 @@ func (c *Catalog) Deregister(dereg *CatalogDeregistration, q *WriteOptions) (*Wr
 	return wm, nil
 }
 
+// RenewNode is used to renew the TTL of a node registered with one, see
+// MetaNodeTTL. A nil node is returned if it doesn't exist.
+func (c *Catalog) RenewNode(node string, q *WriteOptions) (*Node, *WriteMeta, error) {
+	r := c.c.newRequest("PUT", "/v1/catalog/renew/"+node)
+	r.setWriteOptions(q)
+	rtt, resp, err := c.c.doRequest(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeResponseBody(resp)
+
+	wm := &WriteMeta{RequestTime: rtt}
+
+	if resp.StatusCode == 404 {
+		return nil, wm, nil
+	} else if err := requireOK(resp); err != nil {
+		return nil, nil, err
+	}
+
+	var nodes []*Node
+	if err := decodeBody(resp, &nodes); err != nil {
+		return nil, nil, fmt.Errorf("Failed to read response: %v", err)
+	}
+	if len(nodes) > 0 {
+		return nodes[0], wm, nil
+	}
+	return nil, wm, nil
+}
+
 // Datacenters is used to query for all the known datacenters
 func (c *Catalog) Datacenters() ([]string, error) {
 	r := c.c.newRequest("GET", "/v1/catalog/datacenters")
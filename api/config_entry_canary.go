@@ -0,0 +1,138 @@
+package api
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// CanaryConfigEntry describes a progressive rollout of a new version of a
+// service. The canary controller shifts traffic from the stable subset to the
+// canary subset one step at a time by managing the service-splitter config
+// entry of the service, and rolls the canary back if any of its instances
+// become unhealthy.
+type CanaryConfigEntry struct {
+	// Kind is the kind of configuration entry and must be "canary".
+	Kind string `json:",omitempty"`
+
+	// Name is the name of the service being rolled out.
+	Name string `json:",omitempty"`
+
+	// StableSubset is the service-resolver subset currently serving traffic.
+	StableSubset string `json:",omitempty" alias:"stable_subset"`
+
+	// CanarySubset is the service-resolver subset traffic is shifted to.
+	CanarySubset string `json:",omitempty" alias:"canary_subset"`
+
+	// Steps are the percentages of traffic sent to the canary subset at each
+	// step of the rollout. They must be increasing and the last step must be
+	// 100, at which point the canary is promoted.
+	Steps []float32 `json:",omitempty"`
+
+	// StepInterval is how long each step must remain healthy before the
+	// rollout advances to the next step.
+	StepInterval time.Duration `json:",omitempty" alias:"step_interval"`
+
+	// HealthChecks restricts health gating to the checks with these IDs or
+	// names on the canary instances. All checks are considered when empty.
+	HealthChecks []string `json:",omitempty" alias:"health_checks"`
+
+	// Status is the progress of the rollout. This is a read-only field
+	// managed by the canary controller.
+	Status CanaryStatus
+
+	Meta map[string]string `json:",omitempty"`
+
+	// Partition is the partition the CanaryConfigEntry applies to.
+	// Partitioning is a Consul Enterprise feature.
+	Partition string `json:",omitempty"`
+
+	// Namespace is the namespace the CanaryConfigEntry applies to.
+	// Namespacing is a Consul Enterprise feature.
+	Namespace string `json:",omitempty"`
+
+	// CreateIndex is the Raft index this entry was created at. This is a
+	// read-only field.
+	CreateIndex uint64
+
+	// ModifyIndex is used for the Check-And-Set operations and can also be fed
+	// back into the WaitIndex of the QueryOptions in order to perform blocking
+	// queries.
+	ModifyIndex uint64
+}
+
+type CanaryState string
+
+const (
+	// CanaryStateProgressing means traffic is being shifted to the canary.
+	CanaryStateProgressing CanaryState = "progressing"
+
+	// CanaryStatePromoted means the canary receives all traffic.
+	CanaryStatePromoted CanaryState = "promoted"
+
+	// CanaryStateRolledBack means the canary was unhealthy and all traffic
+	// was returned to the stable subset.
+	CanaryStateRolledBack CanaryState = "rolled-back"
+)
+
+// CanaryStatus records the progress of a rollout.
+type CanaryStatus struct {
+	// State is empty until the controller starts the rollout.
+	State CanaryState `json:",omitempty"`
+
+	// Step is the index of the current step in Steps.
+	Step int
+
+	// CanaryWeight is the percentage of traffic currently sent to the
+	// canary subset.
+	CanaryWeight float32
+
+	// Reason describes the last transition.
+	Reason string `json:",omitempty"`
+
+	// LastTransitionTime is when the rollout last changed step or state.
+	LastTransitionTime time.Time `json:",omitempty"`
+}
+
+func (e *CanaryConfigEntry) GetKind() string            { return Canary }
+func (e *CanaryConfigEntry) GetName() string            { return e.Name }
+func (e *CanaryConfigEntry) GetPartition() string       { return e.Partition }
+func (e *CanaryConfigEntry) GetNamespace() string       { return e.Namespace }
+func (e *CanaryConfigEntry) GetMeta() map[string]string { return e.Meta }
+func (e *CanaryConfigEntry) GetCreateIndex() uint64     { return e.CreateIndex }
+func (e *CanaryConfigEntry) GetModifyIndex() uint64     { return e.ModifyIndex }
+
+func (e *CanaryConfigEntry) MarshalJSON() ([]byte, error) {
+	type Alias CanaryConfigEntry
+	exported := &struct {
+		StepInterval string `json:",omitempty"`
+		*Alias
+	}{
+		StepInterval: e.StepInterval.String(),
+		Alias:        (*Alias)(e),
+	}
+	if e.StepInterval == 0 {
+		exported.StepInterval = ""
+	}
+
+	return json.Marshal(exported)
+}
+
+func (e *CanaryConfigEntry) UnmarshalJSON(data []byte) error {
+	type Alias CanaryConfigEntry
+	aux := &struct {
+		StepInterval string
+		*Alias
+	}{
+		Alias: (*Alias)(e),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	var err error
+	if aux.StepInterval != "" {
+		if e.StepInterval, err = time.ParseDuration(aux.StepInterval); err != nil {
+			return err
+		}
+	}
+	return nil
+}
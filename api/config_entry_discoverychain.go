@@ -33,6 +33,7 @@ type ServiceRoute struct {
 
 type ServiceRouteMatch struct {
 	HTTP *ServiceRouteHTTPMatch `json:",omitempty"`
+	GRPC *ServiceRouteGRPCMatch `json:",omitempty"`
 }
 
 type ServiceRouteHTTPMatch struct {
@@ -55,6 +56,12 @@ type ServiceRouteHTTPMatchHeader struct {
 	Invert  bool   `json:",omitempty"`
 }
 
+type ServiceRouteGRPCMatch struct {
+	Service  string                        `json:",omitempty"`
+	Method   string                        `json:",omitempty"`
+	Metadata []ServiceRouteHTTPMatchHeader `json:",omitempty"`
+}
+
 type ServiceRouteHTTPMatchQueryParam struct {
 	Name    string
 	Present bool   `json:",omitempty"`
@@ -74,6 +81,8 @@ type ServiceRouteDestination struct {
 	RetryOnStatusCodes    []uint32             `json:",omitempty" alias:"retry_on_status_codes"`
 	RequestHeaders        *HTTPHeaderModifiers `json:",omitempty" alias:"request_headers"`
 	ResponseHeaders       *HTTPHeaderModifiers `json:",omitempty" alias:"response_headers"`
+	Fault                 *ServiceRouteFault   `json:",omitempty"`
+	Mirror                *RequestMirror       `json:",omitempty"`
 }
 
 func (e *ServiceRouteDestination) MarshalJSON() ([]byte, error) {
@@ -112,6 +121,67 @@ func (e *ServiceRouteDestination) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+type ServiceRouteFault struct {
+	Delay  *ServiceRouteFaultDelay       `json:",omitempty"`
+	Abort  *ServiceRouteFaultAbort       `json:",omitempty"`
+	Header []ServiceRouteHTTPMatchHeader `json:",omitempty"`
+}
+
+type ServiceRouteFaultDelay struct {
+	Duration   time.Duration `json:",omitempty"`
+	Percentage float32       `json:",omitempty"`
+}
+
+func (d *ServiceRouteFaultDelay) MarshalJSON() ([]byte, error) {
+	type Alias ServiceRouteFaultDelay
+	exported := &struct {
+		Duration string `json:",omitempty"`
+		*Alias
+	}{
+		Duration: d.Duration.String(),
+		Alias:    (*Alias)(d),
+	}
+	if d.Duration == 0 {
+		exported.Duration = ""
+	}
+
+	return json.Marshal(exported)
+}
+
+func (d *ServiceRouteFaultDelay) UnmarshalJSON(data []byte) error {
+	type Alias ServiceRouteFaultDelay
+	aux := &struct {
+		Duration string
+		*Alias
+	}{
+		Alias: (*Alias)(d),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	var err error
+	if aux.Duration != "" {
+		if d.Duration, err = time.ParseDuration(aux.Duration); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type ServiceRouteFaultAbort struct {
+	HTTPStatus uint32  `json:",omitempty" alias:"http_status"`
+	GRPCStatus uint32  `json:",omitempty" alias:"grpc_status"`
+	Percentage float32 `json:",omitempty"`
+}
+
+type RequestMirror struct {
+	Service       string  `json:",omitempty"`
+	ServiceSubset string  `json:",omitempty" alias:"service_subset"`
+	Namespace     string  `json:",omitempty"`
+	Partition     string  `json:",omitempty"`
+	Percentage    float32 `json:",omitempty"`
+}
+
 type ServiceSplitterConfigEntry struct {
 	Kind      string
 	Name      string
@@ -141,6 +211,7 @@ type ServiceSplit struct {
 	Partition       string               `json:",omitempty"`
 	RequestHeaders  *HTTPHeaderModifiers `json:",omitempty" alias:"request_headers"`
 	ResponseHeaders *HTTPHeaderModifiers `json:",omitempty" alias:"response_headers"`
+	Mirror          *RequestMirror       `json:",omitempty"`
 }
 
 type ServiceResolverConfigEntry struct {
@@ -18,10 +18,11 @@ type ServiceIntentionsConfigEntry struct {
 
 type SourceIntention struct {
 	Name        string
-	Partition   string                 `json:",omitempty"`
-	Namespace   string                 `json:",omitempty"`
-	Action      IntentionAction        `json:",omitempty"`
-	Permissions []*IntentionPermission `json:",omitempty"`
+	Partition   string                   `json:",omitempty"`
+	Namespace   string                   `json:",omitempty"`
+	Action      IntentionAction          `json:",omitempty"`
+	Permissions []*IntentionPermission   `json:",omitempty"`
+	JWT         *IntentionJWTRequirement `json:",omitempty"`
 	Precedence  int
 	Type        IntentionSourceType
 	Description string `json:",omitempty"`
@@ -43,6 +44,7 @@ func (e *ServiceIntentionsConfigEntry) GetModifyIndex() uint64     { return e.Mo
 type IntentionPermission struct {
 	Action IntentionAction
 	HTTP   *IntentionHTTPPermission `json:",omitempty"`
+	JWT    *IntentionJWTRequirement `json:",omitempty"`
 }
 
 type IntentionHTTPPermission struct {
@@ -64,3 +66,13 @@ type IntentionHTTPHeaderPermission struct {
 	Regex   string `json:",omitempty"`
 	Invert  bool   `json:",omitempty"`
 }
+
+type IntentionJWTRequirement struct {
+	Provider string
+	Claims   []IntentionJWTClaimVerification `json:",omitempty"`
+}
+
+type IntentionJWTClaimVerification struct {
+	Path  []string
+	Value string
+}
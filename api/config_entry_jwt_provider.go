@@ -0,0 +1,214 @@
+package api
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// JWTProviderConfigEntry describes an issuer of JSON Web Tokens that
+// intentions can require requests to present.
+type JWTProviderConfigEntry struct {
+	// Kind is the kind of configuration entry and must be "jwt-provider".
+	Kind string `json:",omitempty"`
+
+	// Name is the name of the provider being configured.
+	Name string `json:",omitempty"`
+
+	// Issuer is the entity that must have issued the JWT. This value must
+	// match the "iss" claim of the token.
+	Issuer string `json:",omitempty"`
+
+	// JSONWebKeySet defines a JSON Web Key Set, its location on disk, or the
+	// means with which to fetch a key set from a remote server.
+	JSONWebKeySet *JSONWebKeySet `json:",omitempty" alias:"json_web_key_set"`
+
+	// Audiences is the set of audiences the JWT is allowed to access. If
+	// specified, the "aud" claim of the token must match at least one of
+	// these values.
+	Audiences []string `json:",omitempty"`
+
+	// Forwarding defines rules for forwarding verified JWTs to the backend.
+	Forwarding *JWTForwardingConfig `json:",omitempty"`
+
+	Meta map[string]string `json:",omitempty"`
+
+	// Partition is the partition the JWTProviderConfigEntry applies to.
+	// Partitioning is a Consul Enterprise feature.
+	Partition string `json:",omitempty"`
+
+	// Namespace is the namespace the JWTProviderConfigEntry applies to.
+	// Namespacing is a Consul Enterprise feature.
+	Namespace string `json:",omitempty"`
+
+	// CreateIndex is the Raft index this entry was created at. This is a
+	// read-only field.
+	CreateIndex uint64
+
+	// ModifyIndex is used for the Check-And-Set operations and can also be fed
+	// back into the WaitIndex of the QueryOptions in order to perform blocking
+	// queries.
+	ModifyIndex uint64
+}
+
+// JSONWebKeySet defines a key set, its location on disk, or the means with
+// which to fetch a key set from a remote server. Exactly one of Local or
+// Remote must be set.
+type JSONWebKeySet struct {
+	Local  *LocalJWKS  `json:",omitempty"`
+	Remote *RemoteJWKS `json:",omitempty"`
+}
+
+// LocalJWKS specifies a key set available to the proxy locally.
+type LocalJWKS struct {
+	// JWKS contains a base64 encoded JWKS.
+	JWKS string `json:",omitempty"`
+
+	// Filename configures a location on disk where the JWKS can be found.
+	Filename string `json:",omitempty"`
+}
+
+// RemoteJWKS specifies how to fetch a key set from a remote server.
+type RemoteJWKS struct {
+	// URI is the URI of the server to query for the JWKS.
+	URI string `json:",omitempty"`
+
+	// RequestTimeoutMs is the number of milliseconds to time out when making
+	// a request for the JWKS.
+	RequestTimeoutMs int `json:",omitempty" alias:"request_timeout_ms"`
+
+	// CacheDuration is the duration after which cached keys should be
+	// expired.
+	CacheDuration time.Duration `json:",omitempty" alias:"cache_duration"`
+
+	// JWKSCluster defines how the specified Remote JWKS URI is to be
+	// fetched.
+	JWKSCluster *JWKSCluster `json:",omitempty" alias:"jwks_cluster"`
+}
+
+// JWKSCluster configures the cluster the proxy uses to fetch a remote key
+// set.
+type JWKSCluster struct {
+	// DiscoveryType refers to the service discovery type to use for
+	// resolving the cluster. One of STRICT_DNS, STATIC, or LOGICAL_DNS.
+	DiscoveryType ClusterDiscoveryType `json:",omitempty" alias:"discovery_type"`
+
+	// ConnectTimeout is the timeout for new network connections to hosts in
+	// the cluster.
+	ConnectTimeout time.Duration `json:",omitempty" alias:"connect_timeout"`
+
+	// TrustedCA is the certificate authority bundle used to verify the
+	// certificate of the server serving the JWKS over https. The system
+	// bundle of the proxy is used when unset.
+	TrustedCA *JWKSTrustedCA `json:",omitempty" alias:"trusted_ca"`
+}
+
+// JWKSTrustedCA specifies a PEM encoded certificate authority bundle.
+// Exactly one of Filename or InlineString must be set.
+type JWKSTrustedCA struct {
+	// Filename configures a location on the proxy's disk where the bundle
+	// can be found.
+	Filename string `json:",omitempty"`
+
+	// InlineString contains the PEM encoded bundle.
+	InlineString string `json:",omitempty" alias:"inline_string"`
+}
+
+type ClusterDiscoveryType string
+
+const (
+	DiscoveryTypeStrictDNS  ClusterDiscoveryType = "STRICT_DNS"
+	DiscoveryTypeStatic     ClusterDiscoveryType = "STATIC"
+	DiscoveryTypeLogicalDNS ClusterDiscoveryType = "LOGICAL_DNS"
+)
+
+// JWTForwardingConfig defines rules for forwarding verified JWTs to the
+// backend.
+type JWTForwardingConfig struct {
+	// HeaderName is a header name to use when forwarding a verified JWT
+	// payload to the backend.
+	HeaderName string `json:",omitempty" alias:"header_name"`
+
+	// KeepToken retains the original token in the request instead of
+	// removing it once it has been verified.
+	KeepToken bool `json:",omitempty" alias:"keep_token"`
+}
+
+func (e *JWTProviderConfigEntry) GetKind() string            { return JWTProvider }
+func (e *JWTProviderConfigEntry) GetName() string            { return e.Name }
+func (e *JWTProviderConfigEntry) GetPartition() string       { return e.Partition }
+func (e *JWTProviderConfigEntry) GetNamespace() string       { return e.Namespace }
+func (e *JWTProviderConfigEntry) GetMeta() map[string]string { return e.Meta }
+func (e *JWTProviderConfigEntry) GetCreateIndex() uint64     { return e.CreateIndex }
+func (e *JWTProviderConfigEntry) GetModifyIndex() uint64     { return e.ModifyIndex }
+
+func (r *RemoteJWKS) MarshalJSON() ([]byte, error) {
+	type Alias RemoteJWKS
+	exported := &struct {
+		CacheDuration string `json:",omitempty"`
+		*Alias
+	}{
+		CacheDuration: r.CacheDuration.String(),
+		Alias:         (*Alias)(r),
+	}
+	if r.CacheDuration == 0 {
+		exported.CacheDuration = ""
+	}
+
+	return json.Marshal(exported)
+}
+
+func (r *RemoteJWKS) UnmarshalJSON(data []byte) error {
+	type Alias RemoteJWKS
+	aux := &struct {
+		CacheDuration string
+		*Alias
+	}{
+		Alias: (*Alias)(r),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	var err error
+	if aux.CacheDuration != "" {
+		if r.CacheDuration, err = time.ParseDuration(aux.CacheDuration); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *JWKSCluster) MarshalJSON() ([]byte, error) {
+	type Alias JWKSCluster
+	exported := &struct {
+		ConnectTimeout string `json:",omitempty"`
+		*Alias
+	}{
+		ConnectTimeout: c.ConnectTimeout.String(),
+		Alias:          (*Alias)(c),
+	}
+	if c.ConnectTimeout == 0 {
+		exported.ConnectTimeout = ""
+	}
+
+	return json.Marshal(exported)
+}
+
+func (c *JWKSCluster) UnmarshalJSON(data []byte) error {
+	type Alias JWKSCluster
+	aux := &struct {
+		ConnectTimeout string
+		*Alias
+	}{
+		Alias: (*Alias)(c),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	var err error
+	if aux.ConnectTimeout != "" {
+		if c.ConnectTimeout, err = time.ParseDuration(aux.ConnectTimeout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
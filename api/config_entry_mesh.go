@@ -17,6 +17,12 @@ type MeshConfigEntry struct {
 	// in transparent mode.
 	TransparentProxy TransparentProxyMeshConfig `alias:"transparent_proxy"`
 
+	// Intentions applies configuration for how intentions are enforced.
+	Intentions IntentionsMeshConfig `alias:"intentions"`
+
+	// Locality applies configuration for locality-aware load balancing.
+	Locality LocalityMeshConfig `alias:"locality"`
+
 	Meta map[string]string `json:",omitempty"`
 
 	// CreateIndex is the Raft index this entry was created at. This is a
@@ -33,6 +39,14 @@ type TransparentProxyMeshConfig struct {
 	MeshDestinationsOnly bool `alias:"mesh_destinations_only"`
 }
 
+type IntentionsMeshConfig struct {
+	AuditMode bool `alias:"audit_mode"`
+}
+
+type LocalityMeshConfig struct {
+	ZoneMetaKey string `alias:"zone_meta_key"`
+}
+
 func (e *MeshConfigEntry) GetKind() string            { return MeshConfig }
 func (e *MeshConfigEntry) GetName() string            { return MeshConfigMesh }
 func (e *MeshConfigEntry) GetPartition() string       { return e.Partition }
@@ -391,6 +391,13 @@ func TestDecodeConfigEntry(t *testing.T) {
 				"TransparentProxy": {
 					"OutboundListenerPort": 808,
 					"DialedDirectly": true
+				},
+				"AccessLogs": {
+					"Enabled": true,
+					"DisableListenerLogs": true,
+					"Type": "file",
+					"Path": "/var/log/envoy.logs",
+					"TextFormat": "[%START_TIME%]"
 				}
 			}
 			`,
@@ -416,6 +423,13 @@ func TestDecodeConfigEntry(t *testing.T) {
 					OutboundListenerPort: 808,
 					DialedDirectly:       true,
 				},
+				AccessLogs: &AccessLogsConfig{
+					Enabled:             true,
+					DisableListenerLogs: true,
+					Type:                FileLogSinkType,
+					Path:                "/var/log/envoy.logs",
+					TextFormat:          "[%START_TIME%]",
+				},
 			},
 		},
 		{
@@ -1273,6 +1287,94 @@ func TestDecodeConfigEntry(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "jwt-provider",
+			body: `
+			{
+				"Kind": "jwt-provider",
+				"Name": "okta",
+				"Issuer": "https://okta.example.com",
+				"Audiences": ["api"],
+				"JSONWebKeySet": {
+					"Remote": {
+						"URI": "https://okta.example.com/.well-known/jwks.json",
+						"RequestTimeoutMs": 1500,
+						"CacheDuration": "10m",
+						"JWKSCluster": {
+							"DiscoveryType": "STRICT_DNS",
+							"ConnectTimeout": "3s",
+							"TrustedCA": {
+								"InlineString": "-----BEGIN CERTIFICATE-----"
+							}
+						}
+					}
+				},
+				"Forwarding": {
+					"HeaderName": "x-jwt-payload"
+				}
+			}
+			`,
+			expect: &JWTProviderConfigEntry{
+				Kind:      "jwt-provider",
+				Name:      "okta",
+				Issuer:    "https://okta.example.com",
+				Audiences: []string{"api"},
+				JSONWebKeySet: &JSONWebKeySet{
+					Remote: &RemoteJWKS{
+						URI:              "https://okta.example.com/.well-known/jwks.json",
+						RequestTimeoutMs: 1500,
+						CacheDuration:    10 * time.Minute,
+						JWKSCluster: &JWKSCluster{
+							DiscoveryType:  DiscoveryTypeStrictDNS,
+							ConnectTimeout: 3 * time.Second,
+							TrustedCA: &JWKSTrustedCA{
+								InlineString: "-----BEGIN CERTIFICATE-----",
+							},
+						},
+					},
+				},
+				Forwarding: &JWTForwardingConfig{
+					HeaderName: "x-jwt-payload",
+				},
+			},
+		},
+		{
+			name: "canary",
+			body: `
+			{
+				"Kind": "canary",
+				"Name": "web",
+				"StableSubset": "v1",
+				"CanarySubset": "v2",
+				"Steps": [10, 50, 100],
+				"StepInterval": "5m",
+				"HealthChecks": ["service:web-v2"],
+				"Status": {
+					"State": "progressing",
+					"Step": 1,
+					"CanaryWeight": 50,
+					"Reason": "advanced to step 2 of 3",
+					"LastTransitionTime": "2021-01-01T00:00:00Z"
+				}
+			}
+			`,
+			expect: &CanaryConfigEntry{
+				Kind:         "canary",
+				Name:         "web",
+				StableSubset: "v1",
+				CanarySubset: "v2",
+				Steps:        []float32{10, 50, 100},
+				StepInterval: 5 * time.Minute,
+				HealthChecks: []string{"service:web-v2"},
+				Status: CanaryStatus{
+					State:              CanaryStateProgressing,
+					Step:               1,
+					CanaryWeight:       50,
+					Reason:             "advanced to step 2 of 3",
+					LastTransitionTime: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+				},
+			},
+		},
 	} {
 		tc := tc
 
@@ -48,6 +48,13 @@ type Intention struct {
 	// service-intentions config entry directly.
 	Permissions []*IntentionPermission `json:",omitempty"`
 
+	// JWT specifies a JWT that must be presented and verified for the
+	// intention to match.
+	//
+	// NOTE: This field is not editable unless editing the underlying
+	// service-intentions config entry directly.
+	JWT *IntentionJWTRequirement `json:",omitempty"`
+
 	// DefaultAddr is not used.
 	// Deprecated: DefaultAddr is not used and may be removed in a future version.
 	DefaultAddr string `json:",omitempty"`
@@ -38,13 +38,14 @@ type AutopilotConfiguration struct {
 	// servers into zones for redundancy. If left blank, this feature will be disabled.
 	RedundancyZoneTag string
 
-	// (Enterprise-only) DisableUpgradeMigration will disable Autopilot's upgrade migration
+	// DisableUpgradeMigration will disable Autopilot's upgrade migration
 	// strategy of waiting until enough newer-versioned servers have been added to the
 	// cluster before promoting them to voters.
 	DisableUpgradeMigration bool
 
-	// (Enterprise-only) UpgradeVersionTag is the node tag to use for version info when
-	// performing upgrade migrations. If left blank, the Consul version will be used.
+	// UpgradeVersionTag is the node tag to use for version info when
+	// performing upgrade migrations. If left blank, upgrade migrations are
+	// disabled.
 	UpgradeVersionTag string
 
 	// CreateIndex holds the index corresponding the creation of this configuration.
@@ -155,6 +156,7 @@ type AutopilotServer struct {
 	RedundancyZone string `json:",omitempty"`
 	UpgradeVersion string `json:",omitempty"`
 	ReadReplica    bool
+	ReplicationLag uint64
 	Status         AutopilotServerStatus
 	Meta           map[string]string
 	NodeType       AutopilotServerType
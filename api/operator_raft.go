@@ -94,3 +94,25 @@ func (op *Operator) RaftRemovePeerByID(id string, q *WriteOptions) error {
 	}
 	return nil
 }
+
+// RaftLeaderTransfer is used to transfer the Raft leadership away from the
+// current leader. The leadership is transferred to the server with the given
+// ID, or to the most up to date voter if id is empty.
+func (op *Operator) RaftLeaderTransfer(id string, q *WriteOptions) error {
+	r := op.c.newRequest("POST", "/v1/operator/raft/transfer-leader")
+	r.setWriteOptions(q)
+
+	if id != "" {
+		r.params.Set("id", id)
+	}
+
+	_, resp, err := op.c.doRequest(r)
+	if err != nil {
+		return err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return err
+	}
+	return nil
+}
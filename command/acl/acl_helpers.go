@@ -2,6 +2,7 @@ package acl
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/consul/agent/structs"
@@ -237,6 +238,65 @@ func ExtractNodeIdentities(nodeIdents []string) ([]*api.ACLNodeIdentity, error)
 	return out, nil
 }
 
+// ExtractPolicyTemplates parses the -policy-template arguments, formatted as
+// TEMPLATE or TEMPLATE:VAR1=VALUE1,VAR2=VALUE2,...
+func ExtractPolicyTemplates(templateLinks []string) ([]*api.ACLPolicyTemplateLink, error) {
+	var out []*api.ACLPolicyTemplateLink
+	for _, linkRaw := range templateLinks {
+		parts := strings.SplitN(linkRaw, ":", 2)
+		if parts[0] == "" {
+			return nil, fmt.Errorf("Malformed -policy-template argument: %q", linkRaw)
+		}
+		link := &api.ACLPolicyTemplateLink{Template: parts[0]}
+		if len(parts) == 2 {
+			link.Vars = make(map[string]string)
+			for _, varRaw := range strings.Split(parts[1], ",") {
+				kv := strings.SplitN(varRaw, "=", 2)
+				if len(kv) != 2 || kv[0] == "" {
+					return nil, fmt.Errorf("Malformed -policy-template argument: %q", linkRaw)
+				}
+				link.Vars[kv[0]] = kv[1]
+			}
+		}
+		out = append(out, link)
+	}
+	return out, nil
+}
+
+// ExtractPolicyVariables parses the -variable arguments, formatted as NAME or
+// NAME:TYPE. The type defaults to string.
+func ExtractPolicyVariables(variables []string) ([]*api.ACLPolicyVariable, error) {
+	var out []*api.ACLPolicyVariable
+	for _, varRaw := range variables {
+		parts := strings.Split(varRaw, ":")
+		switch {
+		case len(parts) == 1 && parts[0] != "":
+			out = append(out, &api.ACLPolicyVariable{Name: parts[0], Type: "string"})
+		case len(parts) == 2 && parts[0] != "" && parts[1] != "":
+			out = append(out, &api.ACLPolicyVariable{Name: parts[0], Type: parts[1]})
+		default:
+			return nil, fmt.Errorf("Malformed -variable argument: %q", varRaw)
+		}
+	}
+	return out, nil
+}
+
+// FormatPolicyTemplateVars formats the variables of a policy template link
+// like the -policy-template argument, sorted by name.
+func FormatPolicyTemplateVars(vars map[string]string) string {
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, name+"="+vars[name])
+	}
+	return strings.Join(pairs, ",")
+}
+
 // TestKubernetesJWT_A is a valid service account jwt extracted from a minikube setup.
 //
 // {
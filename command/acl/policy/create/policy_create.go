@@ -31,6 +31,7 @@ type cmd struct {
 	description string
 	datacenters []string
 	rules       string
+	variables   []string
 
 	fromToken     string
 	tokenIsSecret bool
@@ -51,6 +52,10 @@ func (c *cmd) init() {
 	c.flags.StringVar(&c.rules, "rules", "", "The policy rules. May be prefixed with '@' "+
 		"to indicate that the value is a file path to load the rules from. '-' may also be "+
 		"given to indicate that the rules are available on stdin")
+	c.flags.Var((*flags.AppendSliceValue)(&c.variables), "variable", "Variable of the "+
+		"policy, which makes it a policy template referencing the variable as ${NAME} "+
+		"within its rules. May be specified multiple times. Format is NAME or NAME:TYPE "+
+		"where TYPE is string (the default), int or bool")
 	c.flags.StringVar(&c.fromToken, "from-token", "", "The legacy token to retrieve the rules "+
 		"for when creating this policy. When this is specified no other rules should be given. "+
 		"Similar to the -rules option the token to use can be loaded from stdin or from a file")
@@ -116,11 +121,18 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	variables, err := aclhelpers.ExtractPolicyVariables(c.variables)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
 	newPolicy := &api.ACLPolicy{
 		Name:        c.name,
 		Description: c.description,
 		Datacenters: c.datacenters,
 		Rules:       rules,
+		Variables:   variables,
 	}
 
 	p, _, err := client.ACL().PolicyCreate(newPolicy, nil)
@@ -177,5 +189,11 @@ Usage: consul acl policy create -name NAME [options]
         $ consul acl policy create -name "legacy-policy" \
                                    -description "Token Converted to policy" \
                                    -from-token "c1e34113-e7ab-4451-b1a6-336ddcc58fc6"
+
+    Create a policy template for tokens and roles to link with -policy-template:
+
+        $ consul acl policy create -name "team" \
+                                   -variable "team" \
+                                   -rules 'key_prefix "teams/${team}/" { policy = "write" }'
 `
 )
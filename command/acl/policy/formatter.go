@@ -60,6 +60,13 @@ func (f *prettyFormatter) FormatPolicy(policy *api.ACLPolicy) (string, error) {
 	}
 	buffer.WriteString(fmt.Sprintf("Description:  %s\n", policy.Description))
 	buffer.WriteString(fmt.Sprintf("Datacenters:  %s\n", strings.Join(policy.Datacenters, ", ")))
+	if len(policy.Variables) > 0 {
+		variables := make([]string, 0, len(policy.Variables))
+		for _, v := range policy.Variables {
+			variables = append(variables, fmt.Sprintf("%s (%s)", v.Name, v.Type))
+		}
+		buffer.WriteString(fmt.Sprintf("Variables:    %s\n", strings.Join(variables, ", ")))
+	}
 	if f.showMeta {
 		buffer.WriteString(fmt.Sprintf("Hash:         %x\n", policy.Hash))
 		buffer.WriteString(fmt.Sprintf("Create Index: %d\n", policy.CreateIndex))
@@ -94,6 +101,9 @@ func (f *prettyFormatter) formatPolicyListEntry(policy *api.ACLPolicyListEntry)
 	}
 	buffer.WriteString(fmt.Sprintf("   Description:  %s\n", policy.Description))
 	buffer.WriteString(fmt.Sprintf("   Datacenters:  %s\n", strings.Join(policy.Datacenters, ", ")))
+	if policy.Template {
+		buffer.WriteString(fmt.Sprintln("   Template:     true"))
+	}
 	if f.showMeta {
 		buffer.WriteString(fmt.Sprintf("   Hash:         %x\n", policy.Hash))
 		buffer.WriteString(fmt.Sprintf("   Create Index: %d\n", policy.CreateIndex))
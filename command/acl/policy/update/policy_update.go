@@ -34,6 +34,7 @@ type cmd struct {
 	datacenters    []string
 	rulesSet       bool
 	rules          string
+	variables      []string
 	noMerge        bool
 	showMeta       bool
 	format         string
@@ -54,6 +55,10 @@ func (c *cmd) init() {
 	c.flags.StringVar(&c.rules, "rules", "", "The policy rules. May be prefixed with '@' "+
 		"to indicate that the value is a file path to load the rules from. '-' may also be "+
 		"given to indicate that the rules are available on stdin")
+	c.flags.Var((*flags.AppendSliceValue)(&c.variables), "variable", "Variable of the "+
+		"policy template. When specified, the variables replace those of the template. "+
+		"May be specified multiple times. Format is NAME or NAME:TYPE where TYPE is "+
+		"string (the default), int or bool")
 	c.flags.BoolVar(&c.noMerge, "no-merge", false, "Do not merge the current policy "+
 		"information with what is provided to the command. Instead overwrite all fields "+
 		"with the exception of the policy ID which is immutable.")
@@ -116,6 +121,12 @@ func (c *cmd) Run(args []string) int {
 		c.UI.Error(fmt.Sprintf("Error loading data source: %v", err))
 		return 1
 	}
+	variables, err := acl.ExtractPolicyVariables(c.variables)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
 	var updated *api.ACLPolicy
 	if c.noMerge {
 		updated = &api.ACLPolicy{
@@ -124,6 +135,7 @@ func (c *cmd) Run(args []string) int {
 			Description: c.description,
 			Datacenters: c.datacenters,
 			Rules:       rules,
+			Variables:   variables,
 		}
 	} else {
 		p, _, err := client.ACL().PolicyRead(policyID, nil)
@@ -138,6 +150,7 @@ func (c *cmd) Run(args []string) int {
 			Description: p.Description,
 			Datacenters: p.Datacenters,
 			Rules:       p.Rules,
+			Variables:   p.Variables,
 		}
 
 		if c.nameSet {
@@ -152,6 +165,9 @@ func (c *cmd) Run(args []string) int {
 		if c.datacenters != nil {
 			updated.Datacenters = c.datacenters
 		}
+		if variables != nil {
+			updated.Variables = variables
+		}
 	}
 
 	p, _, err := client.ACL().PolicyUpdate(updated, nil)
@@ -30,6 +30,7 @@ type cmd struct {
 	policyNames   []string
 	serviceIdents []string
 	nodeIdents    []string
+	templates     []string
 
 	showMeta bool
 	format   string
@@ -51,6 +52,9 @@ func (c *cmd) init() {
 	c.flags.Var((*flags.AppendSliceValue)(&c.nodeIdents), "node-identity", "Name of a "+
 		"node identity to use for this role. May be specified multiple times. Format is "+
 		"NODENAME:DATACENTER")
+	c.flags.Var((*flags.AppendSliceValue)(&c.templates), "policy-template", "Name of a "+
+		"policy template to use for this role along with the values of its variables. "+
+		"May be specified multiple times. Format is TEMPLATE or TEMPLATE:VAR1=VALUE1,VAR2=VALUE2,...")
 	c.flags.StringVar(
 		&c.format,
 		"format",
@@ -75,8 +79,9 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
-	if len(c.policyNames) == 0 && len(c.policyIDs) == 0 && len(c.serviceIdents) == 0 && len(c.nodeIdents) == 0 {
-		c.UI.Error(fmt.Sprintf("Cannot create a role without specifying -policy-name, -policy-id, -service-identity, or -node-identity at least once"))
+	if len(c.policyNames) == 0 && len(c.policyIDs) == 0 && len(c.serviceIdents) == 0 &&
+		len(c.nodeIdents) == 0 && len(c.templates) == 0 {
+		c.UI.Error(fmt.Sprintf("Cannot create a role without specifying -policy-name, -policy-id, -service-identity, -node-identity, or -policy-template at least once"))
 		return 1
 	}
 
@@ -120,6 +125,13 @@ func (c *cmd) Run(args []string) int {
 	}
 	newRole.NodeIdentities = parsedNodeIdents
 
+	parsedTemplates, err := acl.ExtractPolicyTemplates(c.templates)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	newRole.PolicyTemplates = parsedTemplates
+
 	r, _, err := client.ACL().RoleCreate(newRole, nil)
 	if err != nil {
 		c.UI.Error(fmt.Sprintf("Failed to create new role: %v", err))
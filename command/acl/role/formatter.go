@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/acl"
 )
 
 const (
@@ -86,6 +87,12 @@ func (f *prettyFormatter) FormatRole(role *api.ACLRole) (string, error) {
 			buffer.WriteString(fmt.Sprintf("   %s (Datacenter: %s)\n", nodeid.NodeName, nodeid.Datacenter))
 		}
 	}
+	if len(role.PolicyTemplates) > 0 {
+		buffer.WriteString(fmt.Sprintln("Policy Templates:"))
+		for _, link := range role.PolicyTemplates {
+			buffer.WriteString(fmt.Sprintf("   %s - %s (%s)\n", link.ID, link.Template, acl.FormatPolicyTemplateVars(link.Vars)))
+		}
+	}
 
 	return buffer.String(), nil
 }
@@ -140,6 +147,12 @@ func (f *prettyFormatter) formatRoleListEntry(role *api.ACLRole) string {
 			buffer.WriteString(fmt.Sprintf("      %s (Datacenter: %s)\n", nodeid.NodeName, nodeid.Datacenter))
 		}
 	}
+	if len(role.PolicyTemplates) > 0 {
+		buffer.WriteString(fmt.Sprintln("   Policy Templates:"))
+		for _, link := range role.PolicyTemplates {
+			buffer.WriteString(fmt.Sprintf("      %s - %s (%s)\n", link.ID, link.Template, acl.FormatPolicyTemplateVars(link.Vars)))
+		}
+	}
 
 	return buffer.String()
 }
@@ -80,6 +80,13 @@ func TestFormatRole(t *testing.T) {
 						Datacenter: "middleearth-northwest",
 					},
 				},
+				PolicyTemplates: []*api.ACLPolicyTemplateLink{
+					{
+						ID:       "5e52a099-4c90-c067-5478-980f06be9af5",
+						Template: "team",
+						Vars:     map[string]string{"team": "hobbits", "region": "shire"},
+					},
+				},
 			},
 		},
 	}
@@ -162,6 +169,13 @@ func TestFormatTokenList(t *testing.T) {
 							Datacenter: "middleearth-northwest",
 						},
 					},
+					PolicyTemplates: []*api.ACLPolicyTemplateLink{
+						{
+							ID:       "5e52a099-4c90-c067-5478-980f06be9af5",
+							Template: "team",
+							Vars:     map[string]string{"team": "hobbits", "region": "shire"},
+						},
+					},
 				},
 			},
 		},
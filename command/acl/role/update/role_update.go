@@ -31,6 +31,7 @@ type cmd struct {
 	policyNames   []string
 	serviceIdents []string
 	nodeIdents    []string
+	templates     []string
 
 	noMerge  bool
 	showMeta bool
@@ -56,6 +57,9 @@ func (c *cmd) init() {
 	c.flags.Var((*flags.AppendSliceValue)(&c.nodeIdents), "node-identity", "Name of a "+
 		"node identity to use for this role. May be specified multiple times. Format is "+
 		"NODENAME:DATACENTER")
+	c.flags.Var((*flags.AppendSliceValue)(&c.templates), "policy-template", "Name of a "+
+		"policy template to use for this role along with the values of its variables. "+
+		"May be specified multiple times. Format is TEMPLATE or TEMPLATE:VAR1=VALUE1,VAR2=VALUE2,...")
 	c.flags.BoolVar(&c.noMerge, "no-merge", false, "Do not merge the current role "+
 		"information with what is provided to the command. Instead overwrite all fields "+
 		"with the exception of the role ID which is immutable.")
@@ -107,6 +111,12 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	parsedTemplates, err := acl.ExtractPolicyTemplates(c.templates)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
 	// Read the current role in both cases so we can fail better if not found.
 	currentRole, _, err := client.ACL().RoleRead(roleID, nil)
 	if err != nil {
@@ -125,6 +135,7 @@ func (c *cmd) Run(args []string) int {
 			Description:       c.description,
 			ServiceIdentities: parsedServiceIdents,
 			NodeIdentities:    parsedNodeIdents,
+			PolicyTemplates:   parsedTemplates,
 		}
 
 		for _, policyName := range c.policyNames {
@@ -217,6 +228,9 @@ func (c *cmd) Run(args []string) int {
 				r.NodeIdentities = append(r.NodeIdentities, nodeid)
 			}
 		}
+
+		// The servers deduplicate identical policy template links.
+		r.PolicyTemplates = append(r.PolicyTemplates, parsedTemplates...)
 	}
 
 	r, _, err = client.ACL().RoleUpdate(r, nil)
@@ -34,6 +34,7 @@ type cmd struct {
 	roleNames     []string
 	serviceIdents []string
 	nodeIdents    []string
+	templates     []string
 	expirationTTL time.Duration
 	local         bool
 	showMeta      bool
@@ -64,6 +65,9 @@ func (c *cmd) init() {
 	c.flags.Var((*flags.AppendSliceValue)(&c.nodeIdents), "node-identity", "Name of a "+
 		"node identity to use for this token. May be specified multiple times. Format is "+
 		"NODENAME:DATACENTER")
+	c.flags.Var((*flags.AppendSliceValue)(&c.templates), "policy-template", "Name of a "+
+		"policy template to use for this token along with the values of its variables. "+
+		"May be specified multiple times. Format is TEMPLATE or TEMPLATE:VAR1=VALUE1,VAR2=VALUE2,...")
 	c.flags.DurationVar(&c.expirationTTL, "expires-ttl", 0, "Duration of time this "+
 		"token should be valid for")
 	c.flags.StringVar(
@@ -86,8 +90,8 @@ func (c *cmd) Run(args []string) int {
 
 	if len(c.policyNames) == 0 && len(c.policyIDs) == 0 &&
 		len(c.roleNames) == 0 && len(c.roleIDs) == 0 &&
-		len(c.serviceIdents) == 0 && len(c.nodeIdents) == 0 {
-		c.UI.Error(fmt.Sprintf("Cannot create a token without specifying -policy-name, -policy-id, -role-name, -role-id, -service-identity, or -node-identity at least once"))
+		len(c.serviceIdents) == 0 && len(c.nodeIdents) == 0 && len(c.templates) == 0 {
+		c.UI.Error(fmt.Sprintf("Cannot create a token without specifying -policy-name, -policy-id, -role-name, -role-id, -service-identity, -node-identity, or -policy-template at least once"))
 		return 1
 	}
 
@@ -121,6 +125,13 @@ func (c *cmd) Run(args []string) int {
 	}
 	newToken.NodeIdentities = parsedNodeIdents
 
+	parsedTemplates, err := acl.ExtractPolicyTemplates(c.templates)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	newToken.PolicyTemplates = parsedTemplates
+
 	for _, policyName := range c.policyNames {
 		// We could resolve names to IDs here but there isn't any reason why its would be better
 		// than allowing the agent to do it.
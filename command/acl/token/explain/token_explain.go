@@ -0,0 +1,218 @@
+package tokenexplain
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/acl"
+	"github.com/hashicorp/consul/command/acl/token"
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/mitchellh/cli"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	tokenID  string
+	resource string
+	segment  string
+	access   string
+	format   string
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.StringVar(&c.tokenID, "id", "", "The Accessor ID of the token to explain. "+
+		"It may be specified as a unique ID prefix but will error if the prefix "+
+		"matches multiple token Accessor IDs. Explaining another token requires "+
+		"acl:read. Defaults to the token of the request")
+	c.flags.StringVar(&c.resource, "resource", "", "The resource to authorize, "+
+		"such as key, service, node or operator")
+	c.flags.StringVar(&c.segment, "segment", "", "The name of the resource to "+
+		"authorize, such as the key or the service name. Not used for resources "+
+		"without names such as operator")
+	c.flags.StringVar(&c.access, "access", "", "The access level to authorize, "+
+		"such as read, write or list")
+	c.flags.StringVar(
+		&c.format,
+		"format",
+		token.PrettyFormat,
+		fmt.Sprintf("Output format {%s}", strings.Join(token.GetSupportedFormats(), "|")),
+	)
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	flags.Merge(c.flags, c.http.MultiTenancyFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if c.resource == "" {
+		c.UI.Error("Missing required '-resource' flag")
+		return 1
+	}
+	if c.access == "" {
+		c.UI.Error("Missing required '-access' flag")
+		return 1
+	}
+	if c.format != token.PrettyFormat && c.format != token.JSONFormat {
+		c.UI.Error(fmt.Sprintf("Unknown format: %s", c.format))
+		return 1
+	}
+
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	var tokenID string
+	if c.tokenID != "" {
+		tokenID, err = acl.GetTokenIDFromPartial(client, c.tokenID)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error determining token ID: %v", err))
+			return 1
+		}
+	}
+
+	explanations, _, err := client.ACL().Explain(tokenID, []api.ACLAuthorizationRequest{{
+		Resource: c.resource,
+		Segment:  c.segment,
+		Access:   c.access,
+	}}, nil)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error explaining authorization: %v", err))
+		return 1
+	}
+	if len(explanations) != 1 {
+		c.UI.Error(fmt.Sprintf("Expected one explanation, got %d", len(explanations)))
+		return 1
+	}
+
+	if c.format == token.JSONFormat {
+		b, err := json.MarshalIndent(explanations[0], "", "    ")
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Failed to marshal explanation: %v", err))
+			return 1
+		}
+		c.UI.Output(string(b))
+		return 0
+	}
+
+	c.UI.Output(formatExplanation(explanations[0]))
+	return 0
+}
+
+func formatExplanation(e api.ACLAuthorizationExplanation) string {
+	var buffer bytes.Buffer
+
+	decision := "deny"
+	if e.Allow {
+		decision = "allow"
+	}
+	buffer.WriteString(fmt.Sprintf("Decision:          %s\n", decision))
+	buffer.WriteString(fmt.Sprintf("Resource:          %s\n", e.Resource))
+	if e.Segment != "" {
+		buffer.WriteString(fmt.Sprintf("Segment:           %s\n", e.Segment))
+	}
+	buffer.WriteString(fmt.Sprintf("Access:            %s\n", e.Access))
+
+	switch {
+	case e.Rule != nil:
+		buffer.WriteString(fmt.Sprintf("Rule:              %s\n", formatRule(e.Rule)))
+	case e.DefaultPolicy != "":
+		buffer.WriteString(fmt.Sprintf("Default Policy:    %s\n", e.DefaultPolicy))
+	default:
+		buffer.WriteString("Rule:              <none>\n")
+	}
+
+	if len(e.Sources) > 0 {
+		buffer.WriteString("Sources:\n")
+		for _, source := range e.Sources {
+			buffer.WriteString(fmt.Sprintf("   %s\n", formatSource(source)))
+		}
+	}
+
+	return buffer.String()
+}
+
+// formatRule renders the rule as it is written in policies.
+func formatRule(rule *api.ACLExplainedRule) string {
+	switch rule.Resource {
+	case "acl", "keyring", "mesh", "operator":
+		return fmt.Sprintf("%s = %q", rule.Resource, rule.Access)
+	}
+
+	resource, field := rule.Resource, "policy"
+	if resource == "intention" {
+		// Intention rules are defined by service rules.
+		resource, field = "service", "intentions"
+	}
+	if rule.Prefix {
+		resource += "_prefix"
+	}
+	return fmt.Sprintf("%s %q { %s = %q }", resource, rule.Segment, field, rule.Access)
+}
+
+func formatSource(source api.ACLExplainSource) string {
+	var out string
+	switch source.Type {
+	case "policy":
+		out = fmt.Sprintf("Policy %q (%s)", source.Name, source.ID)
+	case "service-identity":
+		out = fmt.Sprintf("Service Identity %q", source.Name)
+	case "node-identity":
+		out = fmt.Sprintf("Node Identity %q", source.Name)
+	default:
+		out = fmt.Sprintf("%s %q", source.Type, source.Name)
+	}
+	if source.RoleName != "" {
+		out += fmt.Sprintf(" via Role %q (%s)", source.RoleName, source.RoleID)
+	}
+	return out
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return flags.Usage(c.help, nil)
+}
+
+const (
+	synopsis = "Explain which ACL rule grants or denies access"
+	help     = `
+Usage: consul acl token explain [options] -resource RESOURCE -access ACCESS
+
+  This command authorizes an access with a token, like the HTTP API would,
+  and prints the rule that granted or denied it along with the policies,
+  roles and identities of the token defining that rule.
+
+  Explain why the token of the request can or cannot write a key:
+
+          $ consul acl token explain -resource=key -segment=app/x -access=write
+
+  Explain the decision for another token:
+
+          $ consul acl token explain -id 4be56c77-82 -resource=service \
+                                     -segment=web -access=read
+`
+)
@@ -0,0 +1,110 @@
+package tokenexplain
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/testrpc"
+)
+
+func TestTokenExplainCommand_noTabs(t *testing.T) {
+	t.Parallel()
+
+	if strings.ContainsRune(New(cli.NewMockUi()).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestTokenExplainCommand(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := agent.NewTestAgent(t, `
+	primary_datacenter = "dc1"
+	acl {
+		enabled = true
+		default_policy = "deny"
+		tokens {
+			initial_management = "root"
+		}
+	}`)
+
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	client := a.Client()
+
+	policy, _, err := client.ACL().PolicyCreate(
+		&api.ACLPolicy{Name: "kv-deny", Rules: `key "app/x" { policy = "deny" }`},
+		&api.WriteOptions{Token: "root"},
+	)
+	require.NoError(t, err)
+
+	role, _, err := client.ACL().RoleCreate(
+		&api.ACLRole{Name: "app", Policies: []*api.ACLRolePolicyLink{{ID: policy.ID}}},
+		&api.WriteOptions{Token: "root"},
+	)
+	require.NoError(t, err)
+
+	token, _, err := client.ACL().TokenCreate(
+		&api.ACLToken{Roles: []*api.ACLTokenRoleLink{{ID: role.ID}}},
+		&api.WriteOptions{Token: "root"},
+	)
+	require.NoError(t, err)
+
+	t.Run("rule of a role policy", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		code := New(ui).Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-token=" + token.SecretID,
+			"-resource=key",
+			"-segment=app/x",
+			"-access=write",
+		})
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+		output := ui.OutputWriter.String()
+		require.Contains(t, output, "Decision:          deny")
+		require.Contains(t, output, `Rule:              key "app/x" { policy = "deny" }`)
+		require.Contains(t, output, `Policy "kv-deny" (`+policy.ID+`) via Role "app" (`+role.ID+`)`)
+	})
+
+	t.Run("default policy of another token", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		code := New(ui).Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-token=root",
+			"-id=" + token.AccessorID,
+			"-resource=service",
+			"-segment=web",
+			"-access=read",
+			"-format=json",
+		})
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+		var explanation api.ACLAuthorizationExplanation
+		require.NoError(t, json.Unmarshal([]byte(ui.OutputWriter.String()), &explanation))
+		require.False(t, explanation.Allow)
+		require.Nil(t, explanation.Rule)
+		require.Equal(t, "deny", explanation.DefaultPolicy)
+	})
+
+	t.Run("missing access", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		code := New(ui).Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-resource=key",
+		})
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.ErrorWriter.String(), "Missing required '-access' flag")
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/acl"
 )
 
 const (
@@ -67,6 +68,9 @@ func (f *prettyFormatter) FormatToken(token *api.ACLToken) (string, error) {
 	if token.ExpirationTime != nil && !token.ExpirationTime.IsZero() {
 		buffer.WriteString(fmt.Sprintf("Expiration Time:  %v\n", *token.ExpirationTime))
 	}
+	if token.LastUsedTime != nil && !token.LastUsedTime.IsZero() {
+		buffer.WriteString(fmt.Sprintf("Last Used Time:   %v\n", *token.LastUsedTime))
+	}
 	if f.showMeta {
 		buffer.WriteString(fmt.Sprintf("Hash:             %x\n", token.Hash))
 		buffer.WriteString(fmt.Sprintf("Create Index:     %d\n", token.CreateIndex))
@@ -100,6 +104,12 @@ func (f *prettyFormatter) FormatToken(token *api.ACLToken) (string, error) {
 			buffer.WriteString(fmt.Sprintf("   %s (Datacenter: %s)\n", nodeid.NodeName, nodeid.Datacenter))
 		}
 	}
+	if len(token.PolicyTemplates) > 0 {
+		buffer.WriteString(fmt.Sprintln("Policy Templates:"))
+		for _, link := range token.PolicyTemplates {
+			buffer.WriteString(fmt.Sprintf("   %s - %s (%s)\n", link.ID, link.Template, acl.FormatPolicyTemplateVars(link.Vars)))
+		}
+	}
 	if token.Rules != "" {
 		buffer.WriteString(fmt.Sprintln("Rules:"))
 		buffer.WriteString(fmt.Sprintln(token.Rules))
@@ -144,6 +154,9 @@ func (f *prettyFormatter) formatTokenListEntry(token *api.ACLTokenListEntry) str
 	if token.ExpirationTime != nil && !token.ExpirationTime.IsZero() {
 		buffer.WriteString(fmt.Sprintf("Expiration Time:  %v\n", *token.ExpirationTime))
 	}
+	if token.LastUsedTime != nil && !token.LastUsedTime.IsZero() {
+		buffer.WriteString(fmt.Sprintf("Last Used Time:   %v\n", *token.LastUsedTime))
+	}
 	buffer.WriteString(fmt.Sprintf("Legacy:           %t\n", token.Legacy))
 	if f.showMeta {
 		buffer.WriteString(fmt.Sprintf("Hash:             %x\n", token.Hash))
@@ -178,6 +191,12 @@ func (f *prettyFormatter) formatTokenListEntry(token *api.ACLTokenListEntry) str
 			buffer.WriteString(fmt.Sprintf("   %s (Datacenter: %s)\n", nodeid.NodeName, nodeid.Datacenter))
 		}
 	}
+	if len(token.PolicyTemplates) > 0 {
+		buffer.WriteString(fmt.Sprintln("Policy Templates:"))
+		for _, link := range token.PolicyTemplates {
+			buffer.WriteString(fmt.Sprintf("   %s - %s (%s)\n", link.ID, link.Template, acl.FormatPolicyTemplateVars(link.Vars)))
+		}
+	}
 	return buffer.String()
 }
 
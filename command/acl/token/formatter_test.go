@@ -75,6 +75,7 @@ func TestFormatToken(t *testing.T) {
 				AuthMethodNamespace: "baz",
 				CreateTime:          time.Date(2020, 5, 22, 18, 52, 31, 0, time.UTC),
 				ExpirationTime:      timeRef(time.Date(2020, 5, 22, 19, 52, 31, 0, time.UTC)),
+				LastUsedTime:        timeRef(time.Date(2020, 5, 22, 19, 2, 31, 0, time.UTC)),
 				Hash:                []byte{'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h'},
 				CreateIndex:         5,
 				ModifyIndex:         10,
@@ -110,6 +111,13 @@ func TestFormatToken(t *testing.T) {
 						Datacenter: "middleearth-northwest",
 					},
 				},
+				PolicyTemplates: []*api.ACLPolicyTemplateLink{
+					{
+						ID:       "5e52a099-4c90-c067-5478-980f06be9af5",
+						Template: "team",
+						Vars:     map[string]string{"team": "hobbits", "region": "shire"},
+					},
+				},
 			},
 		},
 	}
@@ -188,6 +196,7 @@ func TestFormatTokenList(t *testing.T) {
 					AuthMethodNamespace: "baz",
 					CreateTime:          time.Date(2020, 5, 22, 18, 52, 31, 0, time.UTC),
 					ExpirationTime:      timeRef(time.Date(2020, 5, 22, 19, 52, 31, 0, time.UTC)),
+					LastUsedTime:        timeRef(time.Date(2020, 5, 22, 19, 2, 31, 0, time.UTC)),
 					Hash:                []byte{'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h'},
 					CreateIndex:         5,
 					ModifyIndex:         10,
@@ -223,6 +232,13 @@ func TestFormatTokenList(t *testing.T) {
 							Datacenter: "middleearth-northwest",
 						},
 					},
+					PolicyTemplates: []*api.ACLPolicyTemplateLink{
+						{
+							ID:       "5e52a099-4c90-c067-5478-980f06be9af5",
+							Template: "team",
+							Vars:     map[string]string{"team": "hobbits", "region": "shire"},
+						},
+					},
 				},
 			},
 		},
@@ -4,7 +4,9 @@ import (
 	"flag"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/command/acl/token"
 	"github.com/hashicorp/consul/command/flags"
 	"github.com/mitchellh/cli"
@@ -22,8 +24,9 @@ type cmd struct {
 	http  *flags.HTTPFlags
 	help  string
 
-	showMeta bool
-	format   string
+	showMeta    bool
+	format      string
+	unusedSince time.Duration
 }
 
 func (c *cmd) init() {
@@ -36,6 +39,9 @@ func (c *cmd) init() {
 		token.PrettyFormat,
 		fmt.Sprintf("Output format {%s}", strings.Join(token.GetSupportedFormats(), "|")),
 	)
+	c.flags.DurationVar(&c.unusedSince, "unused-since", 0, "Only list the tokens that "+
+		"were not used for at least this long, such as \"720h\". Tokens that were never "+
+		"used are listed when they were created before that.")
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
 	flags.Merge(c.flags, c.http.ServerFlags())
@@ -60,6 +66,10 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	if c.unusedSince > 0 {
+		tokens = unusedSince(tokens, time.Now().Add(-c.unusedSince))
+	}
+
 	formatter, err := token.NewFormatter(c.format, c.showMeta)
 	if err != nil {
 		c.UI.Error(err.Error())
@@ -77,6 +87,22 @@ func (c *cmd) Run(args []string) int {
 	return 0
 }
 
+// unusedSince returns the tokens that were last used before the cutoff, or
+// that were never used and created before the cutoff.
+func unusedSince(tokens []*api.ACLTokenListEntry, cutoff time.Time) []*api.ACLTokenListEntry {
+	var unused []*api.ACLTokenListEntry
+	for _, t := range tokens {
+		lastUsed := t.CreateTime
+		if t.LastUsedTime != nil && !t.LastUsedTime.IsZero() {
+			lastUsed = *t.LastUsedTime
+		}
+		if lastUsed.Before(cutoff) {
+			unused = append(unused, t)
+		}
+	}
+	return unused
+}
+
 func (c *cmd) Synopsis() string {
 	return synopsis
 }
@@ -93,5 +119,14 @@ Usage: consul acl token list [options]
   List all the ACL tokens
 
           $ consul acl token list
+
+  List the tokens that were not used in the last 30 days:
+
+          $ consul acl token list -unused-since=720h
+
+  The last use of tokens is tracked approximately by the servers. For local
+  tokens it reflects their use in the datacenter that is queried. For global
+  tokens it reflects their use in every datacenter when the primary datacenter
+  is queried, and only their use in the queried datacenter otherwise.
 `
 )
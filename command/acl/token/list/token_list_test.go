@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/consul/agent"
 	"github.com/hashicorp/consul/api"
@@ -134,3 +135,23 @@ func TestTokenListCommand_JSON(t *testing.T) {
 	}
 	require.Subset(t, respIDs, tokenIds)
 }
+
+func TestTokenListCommand_unusedSince(t *testing.T) {
+	now := time.Now()
+	timeRef := func(in time.Time) *time.Time {
+		return &in
+	}
+
+	tokens := []*api.ACLTokenListEntry{
+		{AccessorID: "recently-used", CreateTime: now.Add(-90 * 24 * time.Hour), LastUsedTime: timeRef(now.Add(-time.Hour))},
+		{AccessorID: "unused", CreateTime: now.Add(-90 * 24 * time.Hour), LastUsedTime: timeRef(now.Add(-60 * 24 * time.Hour))},
+		{AccessorID: "never-used-old", CreateTime: now.Add(-90 * 24 * time.Hour)},
+		{AccessorID: "never-used-new", CreateTime: now.Add(-time.Hour)},
+	}
+
+	var accessors []string
+	for _, t := range unusedSince(tokens, now.Add(-720*time.Hour)) {
+		accessors = append(accessors, t.AccessorID)
+	}
+	require.Equal(t, []string{"unused", "never-used-old"}, accessors)
+}
@@ -52,5 +52,9 @@ Usage: consul acl token <subcommand> [options] [args]
 
     $ consul acl token delete -id 986193
 
+  Explain why a token can or cannot write a key
+
+    $ consul acl token explain -resource=key -segment=app/x -access=write
+
   For more examples, ask for subcommand help or view the documentation.
 `
@@ -31,11 +31,13 @@ type cmd struct {
 	roleNames          []string
 	serviceIdents      []string
 	nodeIdents         []string
+	policyTemplates    []string
 	description        string
 	mergePolicies      bool
 	mergeRoles         bool
 	mergeServiceIdents bool
 	mergeNodeIdents    bool
+	mergeTemplates     bool
 	showMeta           bool
 	upgradeLegacy      bool
 	format             string
@@ -53,6 +55,8 @@ func (c *cmd) init() {
 		"with the existing service identities")
 	c.flags.BoolVar(&c.mergeNodeIdents, "merge-node-identities", false, "Merge the new node identities "+
 		"with the existing node identities")
+	c.flags.BoolVar(&c.mergeTemplates, "merge-policy-templates", false, "Merge the new policy templates "+
+		"with the existing policy templates")
 	c.flags.StringVar(&c.tokenID, "id", "", "The Accessor ID of the token to update. "+
 		"It may be specified as a unique ID prefix but will error if the prefix "+
 		"matches multiple token Accessor IDs")
@@ -71,6 +75,9 @@ func (c *cmd) init() {
 	c.flags.Var((*flags.AppendSliceValue)(&c.nodeIdents), "node-identity", "Name of a "+
 		"node identity to use for this token. May be specified multiple times. Format is "+
 		"NODENAME:DATACENTER")
+	c.flags.Var((*flags.AppendSliceValue)(&c.policyTemplates), "policy-template", "Name of a "+
+		"policy template to use for this token along with the values of its variables. "+
+		"May be specified multiple times. Format is TEMPLATE or TEMPLATE:VAR1=VALUE1,VAR2=VALUE2,...")
 	c.flags.BoolVar(&c.upgradeLegacy, "upgrade-legacy", false, "Add new polices "+
 		"to a legacy token replacing all existing rules. This will cause the legacy "+
 		"token to behave exactly like a new token but keep the same Secret.\n"+
@@ -152,6 +159,12 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	parsedTemplates, err := acl.ExtractPolicyTemplates(c.policyTemplates)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
 	if c.mergePolicies {
 		for _, policyName := range c.policyNames {
 			found := false
@@ -300,6 +313,14 @@ func (c *cmd) Run(args []string) int {
 		t.NodeIdentities = parsedNodeIdents
 	}
 
+	if c.mergeTemplates {
+		// A template may be linked several times with different variables,
+		// the servers deduplicate the identical links.
+		t.PolicyTemplates = append(t.PolicyTemplates, parsedTemplates...)
+	} else {
+		t.PolicyTemplates = parsedTemplates
+	}
+
 	t, _, err = client.ACL().TokenUpdate(t, nil)
 	if err != nil {
 		c.UI.Error(fmt.Sprintf("Failed to update token %s: %v", tokenID, err))
@@ -0,0 +1,217 @@
+package chain
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/command/flags"
+)
+
+const (
+	formatTree = "tree"
+	formatDot  = "dot"
+	formatJSON = "json"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	// flags
+	compileDC string
+	format    string
+	simulate  bool
+	method    string
+	path      string
+	headers   map[string]string
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+
+	c.flags.StringVar(&c.compileDC, "compile-dc", "",
+		"The datacenter to compile the discovery chain in, as if the request "+
+			"came from a proxy in that datacenter. Defaults to the datacenter "+
+			"of the agent being queried.")
+
+	c.flags.StringVar(&c.format, "format", formatTree,
+		"Output format. Must be one of \"tree\", \"dot\" or \"json\". The dot "+
+			"format is a Graphviz digraph and can not be used with -simulate.")
+
+	c.flags.BoolVar(&c.simulate, "simulate", false,
+		"Simulate routing a request through the chain instead of displaying "+
+			"it. Reports the matched route, split probabilities and the final "+
+			"targets with their failover order.")
+
+	c.flags.StringVar(&c.method, "method", http.MethodGet,
+		"The HTTP method of the simulated request.")
+
+	c.flags.StringVar(&c.path, "path", "/",
+		"The path of the simulated request, optionally including a query string. "+
+			"For gRPC requests this is /<service>/<method>.")
+
+	c.flags.Var((*flags.FlagMapValue)(&c.headers), "header",
+		"A header of the simulated request in the form of NAME=VALUE. This "+
+			"flag may be specified multiple times to set multiple headers.")
+
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	flags.Merge(c.flags, c.http.MultiTenancyFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		c.UI.Error(fmt.Sprintf("Failed to parse args: %v", err))
+		return 1
+	}
+
+	args = c.flags.Args()
+	if len(args) != 1 {
+		c.UI.Error("Must specify exactly one service name")
+		return 1
+	}
+	service := args[0]
+
+	switch c.format {
+	case formatTree, formatJSON:
+	case formatDot:
+		if c.simulate {
+			c.UI.Error("The dot format can not be used with -simulate")
+			return 1
+		}
+	default:
+		c.UI.Error(fmt.Sprintf("Invalid -format %q, must be one of tree, dot or json", c.format))
+		return 1
+	}
+
+	var req *simulatedRequest
+	if c.simulate {
+		var err error
+		req, err = newSimulatedRequest(c.method, c.path, c.headers)
+		if err != nil {
+			c.UI.Error(err.Error())
+			return 1
+		}
+	}
+
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	opts := &api.DiscoveryChainOptions{EvaluateInDatacenter: c.compileDC}
+	resp, _, err := client.DiscoveryChain().Get(service, opts, &api.QueryOptions{
+		AllowStale: c.http.Stale(),
+	})
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error compiling discovery chain for %q: %s", service, err))
+		return 1
+	}
+	chain := resp.Chain
+
+	if req != nil {
+		sim, err := simulate(chain, req)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error simulating request: %s", err))
+			return 1
+		}
+		if c.format == formatJSON {
+			return c.outputJSON(sim)
+		}
+		c.UI.Output(sim.String())
+		return 0
+	}
+
+	switch c.format {
+	case formatJSON:
+		return c.outputJSON(chain)
+	case formatDot:
+		c.UI.Output(renderDot(chain))
+	default:
+		c.UI.Output(renderTree(chain))
+	}
+	return 0
+}
+
+func (c *cmd) outputJSON(v interface{}) int {
+	b, err := json.MarshalIndent(v, "", "    ")
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error formatting output: %s", err))
+		return 1
+	}
+	c.UI.Output(string(b))
+	return 0
+}
+
+// newSimulatedRequest builds the request to route through the chain. The
+// path may include a query string, which is matched against any query
+// parameter criteria of the routes.
+func newSimulatedRequest(method, path string, headers map[string]string) (*simulatedRequest, error) {
+	u, err := url.ParseRequestURI(path)
+	if err != nil || !strings.HasPrefix(u.Path, "/") {
+		return nil, fmt.Errorf("Invalid -path %q, must be an absolute path", path)
+	}
+
+	req := &simulatedRequest{
+		Method: strings.ToUpper(method),
+		Path:   u.Path,
+		Query:  u.Query(),
+		Header: make(http.Header),
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	return req, nil
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+const synopsis = "Display or simulate the compiled discovery chain for a service"
+const help = `
+Usage: consul connect chain [options] <service>
+
+  Compiles the discovery chain for a service from its service-router,
+  service-splitter and service-resolver config entries and displays the
+  resulting graph.
+
+  Display the chain as a tree:
+
+      $ consul connect chain web
+
+  Render the chain as a Graphviz graph:
+
+      $ consul connect chain -format dot web | dot -Tsvg > web.svg
+
+  Simulate routing a request through the chain, reporting the matched route,
+  the split probabilities and the final targets with their failover order:
+
+      $ consul connect chain -simulate -method POST -path /admin \
+          -header x-debug=1 web
+`
@@ -0,0 +1,383 @@
+package chain
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/testrpc"
+)
+
+func TestConnectChainCommand_noTabs(t *testing.T) {
+	t.Parallel()
+	if strings.ContainsRune(New(cli.NewMockUi()).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestConnectChainCommand_validation(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		args   []string
+		expect string
+	}{
+		"no service": {
+			args:   nil,
+			expect: "Must specify exactly one service name",
+		},
+		"invalid format": {
+			args:   []string{"-format", "yaml", "web"},
+			expect: `Invalid -format "yaml"`,
+		},
+		"dot with simulate": {
+			args:   []string{"-format", "dot", "-simulate", "web"},
+			expect: "The dot format can not be used with -simulate",
+		},
+		"relative path": {
+			args:   []string{"-simulate", "-path", "admin", "web"},
+			expect: `Invalid -path "admin"`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ui := cli.NewMockUi()
+			c := New(ui)
+			require.Equal(t, 1, c.Run(tc.args))
+			require.Contains(t, ui.ErrorWriter.String(), tc.expect)
+		})
+	}
+}
+
+// testChain returns a chain that routes /admin to the admin service, splits
+// all other traffic between two subsets of web, and fails web over to dc2.
+func testChain() *api.CompiledDiscoveryChain {
+	return &api.CompiledDiscoveryChain{
+		ServiceName: "web",
+		Namespace:   "default",
+		Datacenter:  "dc1",
+		Protocol:    "http",
+		StartNode:   "router:web.default.default",
+		Nodes: map[string]*api.DiscoveryGraphNode{
+			"router:web.default.default": {
+				Type: api.DiscoveryGraphNodeTypeRouter,
+				Name: "web.default.default",
+				Routes: []*api.DiscoveryRoute{
+					{
+						Definition: &api.ServiceRoute{
+							Match: &api.ServiceRouteMatch{
+								HTTP: &api.ServiceRouteHTTPMatch{
+									PathPrefix: "/admin",
+									Header: []api.ServiceRouteHTTPMatchHeader{
+										{Name: "x-debug", Present: true},
+									},
+									Methods: []string{"GET", "POST"},
+								},
+							},
+						},
+						NextNode: "resolver:admin.default.default.dc1",
+					},
+					{
+						Definition: &api.ServiceRoute{
+							Match: &api.ServiceRouteMatch{
+								HTTP: &api.ServiceRouteHTTPMatch{PathPrefix: "/"},
+							},
+						},
+						NextNode: "splitter:web.default.default",
+					},
+				},
+			},
+			"splitter:web.default.default": {
+				Type: api.DiscoveryGraphNodeTypeSplitter,
+				Name: "web.default.default",
+				Splits: []*api.DiscoverySplit{
+					{Weight: 90, NextNode: "resolver:v1.web.default.default.dc1"},
+					{Weight: 10, NextNode: "resolver:v2.web.default.default.dc1"},
+				},
+			},
+			"resolver:admin.default.default.dc1": {
+				Type: api.DiscoveryGraphNodeTypeResolver,
+				Name: "admin.default.default.dc1",
+				Resolver: &api.DiscoveryResolver{
+					Default: true,
+					Target:  "admin.default.default.dc1",
+				},
+			},
+			"resolver:v1.web.default.default.dc1": {
+				Type: api.DiscoveryGraphNodeTypeResolver,
+				Name: "v1.web.default.default.dc1",
+				Resolver: &api.DiscoveryResolver{
+					Target: "v1.web.default.default.dc1",
+					Failover: &api.DiscoveryFailover{
+						Targets: []string{"v1.web.default.default.dc2"},
+					},
+				},
+			},
+			"resolver:v2.web.default.default.dc1": {
+				Type: api.DiscoveryGraphNodeTypeResolver,
+				Name: "v2.web.default.default.dc1",
+				Resolver: &api.DiscoveryResolver{
+					Target: "v2.web.default.default.dc1",
+				},
+			},
+		},
+		Targets: map[string]*api.DiscoveryTarget{
+			"admin.default.default.dc1":  {ID: "admin.default.default.dc1"},
+			"v1.web.default.default.dc1": {ID: "v1.web.default.default.dc1"},
+			"v1.web.default.default.dc2": {
+				ID:          "v1.web.default.default.dc2",
+				MeshGateway: api.MeshGatewayConfig{Mode: api.MeshGatewayModeRemote},
+			},
+			"v2.web.default.default.dc1": {ID: "v2.web.default.default.dc1"},
+		},
+	}
+}
+
+func TestRenderTree(t *testing.T) {
+	expect := `web (protocol http, datacenter dc1)
+└── router:web.default.default
+    ├── route 0: PathPrefix "/admin", Header x-debug present, Methods GET,POST
+    │   └── resolver:admin.default.default.dc1
+    │       └── target: admin.default.default.dc1
+    └── route 1: PathPrefix "/"
+        └── splitter:web.default.default
+            ├── split 90%
+            │   └── resolver:v1.web.default.default.dc1
+            │       ├── target: v1.web.default.default.dc1
+            │       └── failover 1: v1.web.default.default.dc2 (mesh gateway remote)
+            └── split 10%
+                └── resolver:v2.web.default.default.dc1
+                    └── target: v2.web.default.default.dc1`
+	require.Equal(t, expect, renderTree(testChain()))
+}
+
+func TestRenderDot(t *testing.T) {
+	expect := `digraph "web" {
+  rankdir=LR;
+  "resolver:admin.default.default.dc1" [shape=box];
+  "resolver:admin.default.default.dc1" -> "target:admin.default.default.dc1";
+  "resolver:v1.web.default.default.dc1" [shape=box];
+  "resolver:v1.web.default.default.dc1" -> "target:v1.web.default.default.dc1";
+  "resolver:v1.web.default.default.dc1" -> "target:v1.web.default.default.dc2" [label="failover 1", style=dashed];
+  "resolver:v2.web.default.default.dc1" [shape=box];
+  "resolver:v2.web.default.default.dc1" -> "target:v2.web.default.default.dc1";
+  "router:web.default.default" [shape=diamond, penwidth=2];
+  "router:web.default.default" -> "resolver:admin.default.default.dc1" [label="0: PathPrefix \"/admin\", Header x-debug present, Methods GET,POST"];
+  "router:web.default.default" -> "splitter:web.default.default" [label="1: PathPrefix \"/\""];
+  "splitter:web.default.default" [shape=trapezium];
+  "splitter:web.default.default" -> "resolver:v1.web.default.default.dc1" [label="90%"];
+  "splitter:web.default.default" -> "resolver:v2.web.default.default.dc1" [label="10%"];
+  "target:admin.default.default.dc1" [shape=ellipse, label="admin.default.default.dc1"];
+  "target:v1.web.default.default.dc1" [shape=ellipse, label="v1.web.default.default.dc1"];
+  "target:v1.web.default.default.dc2" [shape=ellipse, label="v1.web.default.default.dc2"];
+  "target:v2.web.default.default.dc1" [shape=ellipse, label="v2.web.default.default.dc1"];
+}`
+	require.Equal(t, expect, renderDot(testChain()))
+}
+
+func TestSimulate(t *testing.T) {
+	cases := map[string]struct {
+		method  string
+		path    string
+		headers map[string]string
+		expect  *Simulation
+	}{
+		"admin route": {
+			method:  "post",
+			path:    "/admin/users",
+			headers: map[string]string{"X-Debug": "1"},
+			expect: &Simulation{
+				Service: "web",
+				Routes: []SimulatedRoute{{
+					Router: "router:web.default.default",
+					Index:  0,
+					Match:  `PathPrefix "/admin", Header x-debug present, Methods GET,POST`,
+				}},
+				Targets: []SimulatedTarget{{
+					Probability: 1,
+					Resolver:    "resolver:admin.default.default.dc1",
+					Target:      "admin.default.default.dc1",
+				}},
+			},
+		},
+		"admin path without header falls through to split": {
+			method: "GET",
+			path:   "/admin?verbose=1",
+			expect: &Simulation{
+				Service: "web",
+				Routes: []SimulatedRoute{{
+					Router: "router:web.default.default",
+					Index:  1,
+					Match:  `PathPrefix "/"`,
+				}},
+				Targets: []SimulatedTarget{
+					{
+						Probability: 0.9,
+						Splits:      []string{"splitter:web.default.default 90%"},
+						Resolver:    "resolver:v1.web.default.default.dc1",
+						Target:      "v1.web.default.default.dc1",
+						Failover:    []string{"v1.web.default.default.dc2"},
+					},
+					{
+						Probability: 0.1,
+						Splits:      []string{"splitter:web.default.default 10%"},
+						Resolver:    "resolver:v2.web.default.default.dc1",
+						Target:      "v2.web.default.default.dc1",
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			req, err := newSimulatedRequest(tc.method, tc.path, tc.headers)
+			require.NoError(t, err)
+
+			sim, err := simulate(testChain(), req)
+			require.NoError(t, err)
+
+			require.Len(t, sim.Targets, len(tc.expect.Targets))
+			for i := range sim.Targets {
+				require.InDelta(t, tc.expect.Targets[i].Probability, sim.Targets[i].Probability, 0.0001)
+				sim.Targets[i].Probability = tc.expect.Targets[i].Probability
+			}
+			require.Equal(t, tc.expect, sim)
+		})
+	}
+}
+
+func TestRouteMatches(t *testing.T) {
+	cases := map[string]struct {
+		match   *api.ServiceRouteMatch
+		path    string
+		headers map[string]string
+		expect  bool
+	}{
+		"path exact": {
+			match:  &api.ServiceRouteMatch{HTTP: &api.ServiceRouteHTTPMatch{PathExact: "/foo"}},
+			path:   "/foo/bar",
+			expect: false,
+		},
+		"path regex is a full match": {
+			match:  &api.ServiceRouteMatch{HTTP: &api.ServiceRouteHTTPMatch{PathRegex: "/v[0-9]+"}},
+			path:   "/v1/users",
+			expect: false,
+		},
+		"header suffix": {
+			match: &api.ServiceRouteMatch{HTTP: &api.ServiceRouteHTTPMatch{
+				Header: []api.ServiceRouteHTTPMatchHeader{{Name: "user-agent", Suffix: "bot"}},
+			}},
+			path:    "/",
+			headers: map[string]string{"User-Agent": "crawlbot"},
+			expect:  true,
+		},
+		"inverted header": {
+			match: &api.ServiceRouteMatch{HTTP: &api.ServiceRouteHTTPMatch{
+				Header: []api.ServiceRouteHTTPMatchHeader{{Name: "x-env", Exact: "prod", Invert: true}},
+			}},
+			path:    "/",
+			headers: map[string]string{"x-env": "prod"},
+			expect:  false,
+		},
+		"query param regex": {
+			match: &api.ServiceRouteMatch{HTTP: &api.ServiceRouteHTTPMatch{
+				QueryParam: []api.ServiceRouteHTTPMatchQueryParam{{Name: "page", Regex: "[0-9]+"}},
+			}},
+			path:   "/?page=12",
+			expect: true,
+		},
+		"grpc service": {
+			match:  &api.ServiceRouteMatch{GRPC: &api.ServiceRouteGRPCMatch{Service: "pkg.Users"}},
+			path:   "/pkg.Users/Get",
+			expect: true,
+		},
+		"grpc method": {
+			match:  &api.ServiceRouteMatch{GRPC: &api.ServiceRouteGRPCMatch{Service: "pkg.Users", Method: "List"}},
+			path:   "/pkg.Users/Get",
+			expect: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			req, err := newSimulatedRequest("GET", tc.path, tc.headers)
+			require.NoError(t, err)
+
+			matched, err := routeMatches(&api.ServiceRoute{Match: tc.match}, req)
+			require.NoError(t, err)
+			require.Equal(t, tc.expect, matched)
+		})
+	}
+}
+
+func TestConnectChainCommand(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	client := a.Client()
+	for _, entry := range []api.ConfigEntry{
+		&api.ServiceConfigEntry{
+			Kind:     api.ServiceDefaults,
+			Name:     "web",
+			Protocol: "http",
+		},
+		&api.ServiceResolverConfigEntry{
+			Kind: api.ServiceResolver,
+			Name: "web",
+			Subsets: map[string]api.ServiceResolverSubset{
+				"v1": {Filter: "Service.Meta.version == v1"},
+				"v2": {Filter: "Service.Meta.version == v2"},
+			},
+		},
+		&api.ServiceSplitterConfigEntry{
+			Kind: api.ServiceSplitter,
+			Name: "web",
+			Splits: []api.ServiceSplit{
+				{Weight: 75, ServiceSubset: "v1"},
+				{Weight: 25, ServiceSubset: "v2"},
+			},
+		},
+	} {
+		_, _, err := client.ConfigEntries().Set(entry, nil)
+		require.NoError(t, err)
+	}
+
+	t.Run("tree", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := New(ui)
+		require.Equal(t, 0, c.Run([]string{"-http-addr=" + a.HTTPAddr(), "web"}), ui.ErrorWriter.String())
+
+		output := ui.OutputWriter.String()
+		require.Contains(t, output, "web (protocol http, datacenter dc1)")
+		require.Contains(t, output, "split 75%")
+		require.Contains(t, output, "split 25%")
+	})
+
+	t.Run("simulate", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := New(ui)
+		args := []string{"-http-addr=" + a.HTTPAddr(), "-simulate", "-format=json", "-path=/", "web"}
+		require.Equal(t, 0, c.Run(args), ui.ErrorWriter.String())
+
+		var sim Simulation
+		require.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &sim))
+		require.Len(t, sim.Targets, 2)
+		require.Equal(t, "v1.web.default.default.dc1", sim.Targets[0].Target)
+		require.InDelta(t, 0.75, sim.Targets[0].Probability, 0.0001)
+		require.Equal(t, "v2.web.default.default.dc1", sim.Targets[1].Target)
+		require.InDelta(t, 0.25, sim.Targets[1].Probability, 0.0001)
+	})
+}
@@ -0,0 +1,260 @@
+package chain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/consul/api"
+)
+
+// treeNode is a single line of the tree output along with the lines nested
+// beneath it.
+type treeNode struct {
+	label    string
+	children []*treeNode
+}
+
+func (n *treeNode) add(label string) *treeNode {
+	child := &treeNode{label: label}
+	n.children = append(n.children, child)
+	return child
+}
+
+func (n *treeNode) render(b *strings.Builder, prefix string) {
+	for i, child := range n.children {
+		branch, indent := "├── ", "│   "
+		if i == len(n.children)-1 {
+			branch, indent = "└── ", "    "
+		}
+		b.WriteString(prefix + branch + child.label + "\n")
+		child.render(b, prefix+indent)
+	}
+}
+
+// renderTree displays the chain starting at its start node, with each
+// router, splitter and resolver nested beneath the node that leads to it.
+func renderTree(chain *api.CompiledDiscoveryChain) string {
+	root := &treeNode{
+		label: fmt.Sprintf("%s (protocol %s, datacenter %s)", chain.ServiceName, chain.Protocol, chain.Datacenter),
+	}
+	addChainNode(chain, root, chain.StartNode)
+
+	var b strings.Builder
+	b.WriteString(root.label + "\n")
+	root.render(&b, "")
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func addChainNode(chain *api.CompiledDiscoveryChain, parent *treeNode, key string) {
+	node, ok := chain.Nodes[key]
+	if !ok {
+		parent.add(key + " (missing)")
+		return
+	}
+
+	n := parent.add(key)
+	switch node.Type {
+	case api.DiscoveryGraphNodeTypeRouter:
+		for i, route := range node.Routes {
+			r := n.add(fmt.Sprintf("route %d: %s", i, describeMatch(route.Definition)))
+			addChainNode(chain, r, route.NextNode)
+		}
+	case api.DiscoveryGraphNodeTypeSplitter:
+		for _, split := range node.Splits {
+			s := n.add(fmt.Sprintf("split %s", formatWeight(split.Weight)))
+			addChainNode(chain, s, split.NextNode)
+		}
+	case api.DiscoveryGraphNodeTypeResolver:
+		if node.Resolver == nil {
+			return
+		}
+		if node.Resolver.ConnectTimeout != 0 {
+			n.label += fmt.Sprintf(" (connect timeout %s)", node.Resolver.ConnectTimeout)
+		}
+		n.add("target: " + describeTarget(chain, node.Resolver.Target))
+		if node.Resolver.Failover != nil {
+			for i, target := range node.Resolver.Failover.Targets {
+				n.add(fmt.Sprintf("failover %d: %s", i+1, describeTarget(chain, target)))
+			}
+		}
+	}
+}
+
+func describeTarget(chain *api.CompiledDiscoveryChain, id string) string {
+	t, ok := chain.Targets[id]
+	if !ok {
+		return id
+	}
+
+	var details []string
+	if t.External {
+		details = append(details, "external")
+	}
+	if t.MeshGateway.Mode != "" {
+		details = append(details, fmt.Sprintf("mesh gateway %s", t.MeshGateway.Mode))
+	}
+	if t.Subset.Filter != "" {
+		details = append(details, fmt.Sprintf("filter %q", t.Subset.Filter))
+	}
+	if len(details) == 0 {
+		return id
+	}
+	return fmt.Sprintf("%s (%s)", id, strings.Join(details, ", "))
+}
+
+func formatWeight(weight float32) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.2f", weight), "0"), ".") + "%"
+}
+
+// describeMatch summarizes the match criteria of a route.
+func describeMatch(route *api.ServiceRoute) string {
+	if route == nil || route.Match == nil {
+		return "default"
+	}
+
+	var parts []string
+	if m := route.Match.HTTP; m != nil {
+		switch {
+		case m.PathExact != "":
+			parts = append(parts, fmt.Sprintf("PathExact %q", m.PathExact))
+		case m.PathPrefix != "":
+			parts = append(parts, fmt.Sprintf("PathPrefix %q", m.PathPrefix))
+		case m.PathRegex != "":
+			parts = append(parts, fmt.Sprintf("PathRegex %q", m.PathRegex))
+		}
+		for _, h := range m.Header {
+			parts = append(parts, "Header "+describeHeaderMatch(h))
+		}
+		for _, q := range m.QueryParam {
+			parts = append(parts, "QueryParam "+describeQueryParamMatch(q))
+		}
+		if len(m.Methods) > 0 {
+			parts = append(parts, "Methods "+strings.Join(m.Methods, ","))
+		}
+	}
+	if m := route.Match.GRPC; m != nil {
+		switch {
+		case m.Method != "":
+			parts = append(parts, fmt.Sprintf("gRPC %s/%s", m.Service, m.Method))
+		case m.Service != "":
+			parts = append(parts, fmt.Sprintf("gRPC %s/*", m.Service))
+		default:
+			parts = append(parts, "gRPC */*")
+		}
+		for _, h := range m.Metadata {
+			parts = append(parts, "Metadata "+describeHeaderMatch(h))
+		}
+	}
+
+	if len(parts) == 0 {
+		return "default"
+	}
+	return strings.Join(parts, ", ")
+}
+
+func describeHeaderMatch(h api.ServiceRouteHTTPMatchHeader) string {
+	var desc string
+	switch {
+	case h.Exact != "":
+		desc = fmt.Sprintf("%s == %q", h.Name, h.Exact)
+	case h.Prefix != "":
+		desc = fmt.Sprintf("%s prefix %q", h.Name, h.Prefix)
+	case h.Suffix != "":
+		desc = fmt.Sprintf("%s suffix %q", h.Name, h.Suffix)
+	case h.Regex != "":
+		desc = fmt.Sprintf("%s =~ %q", h.Name, h.Regex)
+	default:
+		desc = fmt.Sprintf("%s present", h.Name)
+	}
+	if h.Invert {
+		return "not " + desc
+	}
+	return desc
+}
+
+func describeQueryParamMatch(q api.ServiceRouteHTTPMatchQueryParam) string {
+	switch {
+	case q.Exact != "":
+		return fmt.Sprintf("%s == %q", q.Name, q.Exact)
+	case q.Regex != "":
+		return fmt.Sprintf("%s =~ %q", q.Name, q.Regex)
+	default:
+		return fmt.Sprintf("%s present", q.Name)
+	}
+}
+
+// renderDot renders the chain as a Graphviz digraph. Targets are drawn as
+// separate nodes so that targets shared by several resolvers, such as a
+// failover datacenter, are only drawn once.
+func renderDot(chain *api.CompiledDiscoveryChain) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %s {\n", dotQuote(chain.ServiceName))
+	b.WriteString("  rankdir=LR;\n")
+
+	keys := make([]string, 0, len(chain.Nodes))
+	for key := range chain.Nodes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		node := chain.Nodes[key]
+
+		shape := "box"
+		switch node.Type {
+		case api.DiscoveryGraphNodeTypeRouter:
+			shape = "diamond"
+		case api.DiscoveryGraphNodeTypeSplitter:
+			shape = "trapezium"
+		}
+		attrs := fmt.Sprintf("shape=%s", shape)
+		if key == chain.StartNode {
+			attrs += ", penwidth=2"
+		}
+		fmt.Fprintf(&b, "  %s [%s];\n", dotQuote(key), attrs)
+
+		switch node.Type {
+		case api.DiscoveryGraphNodeTypeRouter:
+			for i, route := range node.Routes {
+				label := fmt.Sprintf("%d: %s", i, describeMatch(route.Definition))
+				fmt.Fprintf(&b, "  %s -> %s [label=%s];\n", dotQuote(key), dotQuote(route.NextNode), dotQuote(label))
+			}
+		case api.DiscoveryGraphNodeTypeSplitter:
+			for _, split := range node.Splits {
+				fmt.Fprintf(&b, "  %s -> %s [label=%s];\n", dotQuote(key), dotQuote(split.NextNode), dotQuote(formatWeight(split.Weight)))
+			}
+		case api.DiscoveryGraphNodeTypeResolver:
+			if node.Resolver == nil {
+				continue
+			}
+			fmt.Fprintf(&b, "  %s -> %s;\n", dotQuote(key), dotQuote(dotTargetKey(node.Resolver.Target)))
+			if node.Resolver.Failover != nil {
+				for i, target := range node.Resolver.Failover.Targets {
+					fmt.Fprintf(&b, "  %s -> %s [label=%s, style=dashed];\n",
+						dotQuote(key), dotQuote(dotTargetKey(target)), dotQuote(fmt.Sprintf("failover %d", i+1)))
+				}
+			}
+		}
+	}
+
+	targets := make([]string, 0, len(chain.Targets))
+	for id := range chain.Targets {
+		targets = append(targets, id)
+	}
+	sort.Strings(targets)
+	for _, id := range targets {
+		fmt.Fprintf(&b, "  %s [shape=ellipse, label=%s];\n", dotQuote(dotTargetKey(id)), dotQuote(id))
+	}
+
+	b.WriteString("}")
+	return b.String()
+}
+
+func dotTargetKey(id string) string {
+	return "target:" + id
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
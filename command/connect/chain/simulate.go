@@ -0,0 +1,292 @@
+package chain
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/consul/api"
+)
+
+// simulatedRequest is the synthetic request routed through the chain.
+type simulatedRequest struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+}
+
+// Simulation is the result of routing a request through a discovery chain.
+type Simulation struct {
+	Service string
+
+	// Routes holds the route matched by each router the request passed
+	// through. It is empty for chains without a router.
+	Routes []SimulatedRoute `json:",omitempty"`
+
+	// Targets holds every target the request may be sent to along with the
+	// probability of it being chosen, ordered by decreasing probability.
+	Targets []SimulatedTarget `json:",omitempty"`
+}
+
+// SimulatedRoute is the route of a router node that matched the request.
+type SimulatedRoute struct {
+	Router string
+	Index  int
+	Match  string
+}
+
+// SimulatedTarget is a final destination of the request.
+type SimulatedTarget struct {
+	// Probability of the request being sent to the target, between 0 and 1.
+	Probability float64
+
+	// Splits lists the splitter nodes and weights the request passed
+	// through to reach the resolver.
+	Splits []string `json:",omitempty"`
+
+	Resolver string
+	Target   string
+
+	// Failover lists the targets used, in order, if the target has no
+	// healthy instances.
+	Failover []string `json:",omitempty"`
+}
+
+// simulate walks the chain from its start node as a proxy would route the
+// request: the first matching route of a router is taken and every split of
+// a splitter is followed with its weight applied to the probability.
+func simulate(chain *api.CompiledDiscoveryChain, req *simulatedRequest) (*Simulation, error) {
+	sim := &Simulation{Service: chain.ServiceName}
+	if err := sim.walk(chain, req, chain.StartNode, 1, nil); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(sim.Targets, func(i, j int) bool {
+		return sim.Targets[i].Probability > sim.Targets[j].Probability
+	})
+	return sim, nil
+}
+
+func (s *Simulation) walk(chain *api.CompiledDiscoveryChain, req *simulatedRequest, key string, probability float64, splits []string) error {
+	node, ok := chain.Nodes[key]
+	if !ok {
+		return fmt.Errorf("chain references unknown node %q", key)
+	}
+
+	switch node.Type {
+	case api.DiscoveryGraphNodeTypeRouter:
+		for i, route := range node.Routes {
+			matched, err := routeMatches(route.Definition, req)
+			if err != nil {
+				return fmt.Errorf("route %d of %s: %v", i, key, err)
+			}
+			if !matched {
+				continue
+			}
+			s.Routes = append(s.Routes, SimulatedRoute{
+				Router: key,
+				Index:  i,
+				Match:  describeMatch(route.Definition),
+			})
+			return s.walk(chain, req, route.NextNode, probability, splits)
+		}
+		// No route matched, so the proxy will not route the request anywhere.
+		return nil
+
+	case api.DiscoveryGraphNodeTypeSplitter:
+		for _, split := range node.Splits {
+			if split.Weight == 0 {
+				continue
+			}
+			next := append(append([]string(nil), splits...), fmt.Sprintf("%s %s", key, formatWeight(split.Weight)))
+			if err := s.walk(chain, req, split.NextNode, probability*float64(split.Weight)/100, next); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case api.DiscoveryGraphNodeTypeResolver:
+		if node.Resolver == nil {
+			return fmt.Errorf("resolver node %q has no resolver", key)
+		}
+		target := SimulatedTarget{
+			Probability: probability,
+			Splits:      splits,
+			Resolver:    key,
+			Target:      node.Resolver.Target,
+		}
+		if node.Resolver.Failover != nil {
+			target.Failover = node.Resolver.Failover.Targets
+		}
+		s.Targets = append(s.Targets, target)
+		return nil
+	}
+
+	return fmt.Errorf("node %q has unknown type %q", key, node.Type)
+}
+
+func (s *Simulation) String() string {
+	var b strings.Builder
+
+	if len(s.Routes) == 0 && len(s.Targets) == 0 {
+		fmt.Fprintf(&b, "No route of %q matched the request", s.Service)
+		return b.String()
+	}
+
+	for _, route := range s.Routes {
+		fmt.Fprintf(&b, "Matched route %d of %s: %s\n", route.Index, route.Router, route.Match)
+	}
+	if len(s.Targets) == 0 {
+		b.WriteString("No targets are reachable for the request")
+		return b.String()
+	}
+
+	b.WriteString("Targets:\n")
+	for _, t := range s.Targets {
+		fmt.Fprintf(&b, "  %7.2f%%  %s\n", t.Probability*100, t.Target)
+		for _, split := range t.Splits {
+			fmt.Fprintf(&b, "            via split %s\n", split)
+		}
+		for i, failover := range t.Failover {
+			fmt.Fprintf(&b, "            failover %d: %s\n", i+1, failover)
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// routeMatches reports whether the request matches the route's criteria,
+// following the semantics of the Envoy route match they are translated to.
+func routeMatches(route *api.ServiceRoute, req *simulatedRequest) (bool, error) {
+	if route == nil || route.Match == nil {
+		return true, nil
+	}
+
+	if m := route.Match.HTTP; m != nil {
+		return httpMatches(m, req)
+	}
+	if m := route.Match.GRPC; m != nil {
+		return grpcMatches(m, req)
+	}
+	return true, nil
+}
+
+func httpMatches(m *api.ServiceRouteHTTPMatch, req *simulatedRequest) (bool, error) {
+	switch {
+	case m.PathExact != "":
+		if req.Path != m.PathExact {
+			return false, nil
+		}
+	case m.PathPrefix != "":
+		if !strings.HasPrefix(req.Path, m.PathPrefix) {
+			return false, nil
+		}
+	case m.PathRegex != "":
+		ok, err := fullMatch(m.PathRegex, req.Path)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+
+	for _, h := range m.Header {
+		ok, err := headerMatches(h, req.Header)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+
+	for _, q := range m.QueryParam {
+		values, present := req.Query[q.Name]
+		if !present {
+			return false, nil
+		}
+		switch {
+		case q.Exact != "":
+			if values[0] != q.Exact {
+				return false, nil
+			}
+		case q.Regex != "":
+			ok, err := fullMatch(q.Regex, values[0])
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+	}
+
+	if len(m.Methods) > 0 {
+		found := false
+		for _, method := range m.Methods {
+			if strings.EqualFold(method, req.Method) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func grpcMatches(m *api.ServiceRouteGRPCMatch, req *simulatedRequest) (bool, error) {
+	switch {
+	case m.Method != "":
+		if req.Path != "/"+m.Service+"/"+m.Method {
+			return false, nil
+		}
+	case m.Service != "":
+		if !strings.HasPrefix(req.Path, "/"+m.Service+"/") {
+			return false, nil
+		}
+	}
+
+	for _, h := range m.Metadata {
+		ok, err := headerMatches(h, req.Header)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func headerMatches(h api.ServiceRouteHTTPMatchHeader, header http.Header) (bool, error) {
+	values, present := header[http.CanonicalHeaderKey(h.Name)]
+
+	var matched bool
+	switch {
+	case !present:
+		matched = false
+	case h.Exact != "":
+		matched = values[0] == h.Exact
+	case h.Prefix != "":
+		matched = strings.HasPrefix(values[0], h.Prefix)
+	case h.Suffix != "":
+		matched = strings.HasSuffix(values[0], h.Suffix)
+	case h.Regex != "":
+		var err error
+		if matched, err = fullMatch(h.Regex, values[0]); err != nil {
+			return false, err
+		}
+	default:
+		matched = true
+	}
+
+	if h.Invert {
+		return !matched, nil
+	}
+	return matched, nil
+}
+
+// fullMatch reports whether the whole value matches the pattern, as Envoy
+// requires for its safe regex matchers.
+func fullMatch(pattern, value string) (bool, error) {
+	re, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		return false, fmt.Errorf("invalid regex %q: %v", pattern, err)
+	}
+	return re.MatchString(value), nil
+}
@@ -0,0 +1,151 @@
+package proxyconfig
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/mitchellh/cli"
+
+	proxyCmd "github.com/hashicorp/consul/command/connect/proxy"
+	"github.com/hashicorp/consul/command/flags"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	// flags
+	proxyID    string
+	sidecarFor string
+	resource   string
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+
+	c.flags.StringVar(&c.proxyID, "proxy-id", "",
+		"The proxy's ID on the local agent.")
+
+	c.flags.StringVar(&c.sidecarFor, "sidecar-for", "",
+		"The ID of a service instance on the local agent that this proxy should "+
+			"become a sidecar for. It requires that the proxy service is registered "+
+			"with the agent as a connect-proxy with Proxy.DestinationServiceID set "+
+			"to this value. If more than one such proxy is registered it will fail.")
+
+	c.flags.StringVar(&c.resource, "type", "",
+		"Only output resources of the given type. Must be one of \"listeners\", "+
+			"\"routes\", \"clusters\" or \"endpoints\". Defaults to all types.")
+
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.MultiTenancyFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		c.UI.Error(fmt.Sprintf("Failed to parse args: %v", err))
+		return 1
+	}
+
+	if (c.proxyID == "") == (c.sidecarFor == "") {
+		c.UI.Error("Exactly one of -proxy-id or -sidecar-for must be specified")
+		return 1
+	}
+
+	switch strings.ToLower(c.resource) {
+	case "", "listeners", "routes", "clusters", "endpoints":
+	default:
+		c.UI.Error(fmt.Sprintf("Invalid -type %q, must be one of listeners, routes, clusters or endpoints", c.resource))
+		return 1
+	}
+
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	proxyID := c.proxyID
+	if c.sidecarFor != "" {
+		proxyID, err = proxyCmd.LookupProxyIDForSidecar(client, c.sidecarFor)
+		if err != nil {
+			c.UI.Error(err.Error())
+			return 1
+		}
+	}
+
+	config, _, err := client.Agent().ConnectProxyXDS(proxyID, nil)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error reading xDS config for proxy %q: %s", proxyID, err))
+		return 1
+	}
+
+	var out interface{} = config
+	switch strings.ToLower(c.resource) {
+	case "listeners":
+		out = config.Listeners
+	case "routes":
+		out = config.Routes
+	case "clusters":
+		out = config.Clusters
+	case "endpoints":
+		out = config.Endpoints
+	}
+
+	output, err := formatJSON(out)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error formatting xDS config: %s", err))
+		return 1
+	}
+	c.UI.Output(output)
+	return 0
+}
+
+// formatJSON indents the resources, which the agent returns as compact JSON.
+func formatJSON(v interface{}) (string, error) {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+const synopsis = "Display the xDS configuration Consul generates for a proxy"
+const help = `
+Usage: consul connect proxy-config [options]
+
+  Displays the listeners, routes, clusters and endpoints that the local agent
+  would send to a proxy over xDS, rendered from the proxy's current
+  configuration snapshot. Envoy does not need to be running. Private keys of
+  leaf certificates are hidden.
+
+  Display the config for a sidecar proxy by its ID:
+
+      $ consul connect proxy-config -proxy-id web-sidecar-proxy
+
+  Display only the clusters of the sidecar proxy for a service:
+
+      $ consul connect proxy-config -sidecar-for web -type clusters
+`
@@ -0,0 +1,99 @@
+package proxyconfig
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/agent"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
+	"github.com/hashicorp/consul/testrpc"
+)
+
+func TestConnectProxyConfigCommand_noTabs(t *testing.T) {
+	t.Parallel()
+	if strings.ContainsRune(New(cli.NewMockUi()).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestConnectProxyConfigCommand_validation(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		args   []string
+		expect string
+	}{
+		"no proxy": {
+			args:   nil,
+			expect: "Exactly one of -proxy-id or -sidecar-for must be specified",
+		},
+		"both proxy-id and sidecar-for": {
+			args:   []string{"-proxy-id", "web-sidecar-proxy", "-sidecar-for", "web"},
+			expect: "Exactly one of -proxy-id or -sidecar-for must be specified",
+		},
+		"invalid type": {
+			args:   []string{"-proxy-id", "web-sidecar-proxy", "-type", "secrets"},
+			expect: `Invalid -type "secrets"`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ui := cli.NewMockUi()
+			c := New(ui)
+			require.Equal(t, 1, c.Run(tc.args))
+			require.Contains(t, ui.ErrorWriter.String(), tc.expect)
+		})
+	}
+}
+
+func TestConnectProxyConfigCommand(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	client := a.Client()
+	require.NoError(t, client.Agent().ServiceRegister(&api.AgentServiceRegistration{
+		Name: "web",
+		Port: 8080,
+		Connect: &api.AgentServiceConnect{
+			SidecarService: &api.AgentServiceRegistration{
+				Proxy: &api.AgentServiceConnectProxyConfig{
+					Upstreams: []api.Upstream{
+						{DestinationName: "db", LocalBindPort: 9191},
+					},
+				},
+			},
+		},
+	}))
+
+	retry.Run(t, func(r *retry.R) {
+		ui := cli.NewMockUi()
+		c := New(ui)
+		args := []string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-sidecar-for=web",
+			"-type=listeners",
+		}
+		require.Equal(r, 0, c.Run(args), ui.ErrorWriter.String())
+
+		var listeners []struct{ Name string }
+		require.NoError(r, json.Unmarshal(ui.OutputWriter.Bytes(), &listeners))
+
+		var names []string
+		for _, l := range listeners {
+			names = append(names, l.Name)
+		}
+		require.Contains(r, names, "db:127.0.0.1:9191")
+	})
+}
@@ -46,6 +46,7 @@ type cmd struct {
 	excludeOutboundCIDRs []string
 	excludeUIDs          []string
 	netNS                string
+	backend              string
 }
 
 func (c *cmd) init() {
@@ -67,6 +68,8 @@ func (c *cmd) init() {
 		"Additional user ID to exclude from traffic redirection. May be provided multiple times.")
 	c.flags.StringVar(&c.netNS, "netns", "", "The network namespace where traffic redirection rules should apply."+
 		"This must be a path to the network namespace, e.g. /var/run/netns/foo.")
+	c.flags.StringVar(&c.backend, "backend", "", "The firewall framework used to apply traffic redirection rules. "+
+		"Must be one of \"iptables\" or \"nftables\". Defaults to iptables.")
 
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
@@ -96,6 +99,13 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	switch iptables.Backend(c.backend) {
+	case "", iptables.BackendIptables, iptables.BackendNftables:
+	default:
+		c.UI.Error(fmt.Sprintf("-backend must be one of %q or %q", iptables.BackendIptables, iptables.BackendNftables))
+		return 1
+	}
+
 	cfg, err := c.generateConfigFromFlags()
 	if err != nil {
 		c.UI.Error(fmt.Sprintf("Failed to create configuration to apply traffic redirection rules: %s", err))
@@ -137,6 +147,7 @@ func (c *cmd) generateConfigFromFlags() (iptables.Config, error) {
 		ProxyInboundPort:  c.proxyInboundPort,
 		ProxyOutboundPort: c.proxyOutboundPort,
 		NetNS:             c.netNS,
+		Backend:           iptables.Backend(c.backend),
 	}
 
 	// When proxyID is provided, we set up cfg with values
@@ -241,18 +252,22 @@ func (c *cmd) generateConfigFromFlags() (iptables.Config, error) {
 }
 
 const (
-	synopsis = "Applies iptables rules for traffic redirection"
+	synopsis = "Applies iptables or nftables rules for traffic redirection"
 	help     = `
 Usage: consul connect redirect-traffic [options]
 
-  Applies iptables rules for inbound and outbound traffic redirection.
+  Applies iptables or nftables rules for inbound and outbound traffic
+  redirection.
 
-  Requires that the iptables command line utility is installed.
+  Requires that the iptables command line utility is installed, or the nft
+  command line utility when -backend=nftables is used.
 
   Examples:
 
     $ consul connect redirect-traffic -proxy-uid 1234 -proxy-id web
 
     $ consul connect redirect-traffic -proxy-uid 1234 -proxy-inbound-port 20000
+
+    $ consul connect redirect-traffic -proxy-uid 1234 -proxy-id web -backend nftables
 `
 )
@@ -43,6 +43,11 @@ func TestRun_FlagValidation(t *testing.T) {
 			[]string{"-proxy-uid=1234", "-proxy-id=test", "-proxy-inbound-port=15000", "-proxy-outbound-port=15001"},
 			"-proxy-inbound-port or -proxy-outbound-port cannot be provided together with -proxy-id.",
 		},
+		{
+			"-backend is invalid",
+			[]string{"-proxy-uid=1234", "-proxy-inbound-port=15000", "-backend=pf"},
+			`-backend must be one of "iptables" or "nftables"`,
+		},
 	}
 
 	for _, c := range cases {
@@ -371,6 +376,23 @@ func TestGenerateConfigFromFlags(t *testing.T) {
 				ExcludeUIDs:       []string{"2345", "3456"},
 			},
 		},
+		{
+			name: "nftables backend is provided",
+			command: func() cmd {
+				var c cmd
+				c.init()
+				c.proxyUID = "1234"
+				c.proxyInboundPort = 15000
+				c.backend = "nftables"
+				return c
+			},
+			expCfg: iptables.Config{
+				ProxyUserID:       "1234",
+				ProxyInboundPort:  15000,
+				ProxyOutboundPort: 15001,
+				Backend:           iptables.BackendNftables,
+			},
+		},
 		{
 			name: "proxy config has envoy_prometheus_bind_addr set",
 			command: func() cmd {
@@ -13,7 +13,7 @@ import (
 )
 
 func New(ui cli.Ui) *cmd {
-	c := &cmd{UI: ui}
+	c := &cmd{UI: ui, openBrowser: openURL}
 	c.init()
 	return c
 }
@@ -36,6 +36,13 @@ type cmd struct {
 	tokenSinkFile   string
 	meta            map[string]string
 
+	oidcCallbackListenAddr string
+	oidcNoBrowser          bool
+
+	// openBrowser opens the OIDC provider's login page, it is replaced in
+	// tests.
+	openBrowser func(url string) error
+
 	enterpriseCmd
 }
 
@@ -46,7 +53,10 @@ func (c *cmd) init() {
 		"Name of the auth method to login to.")
 
 	c.flags.StringVar(&c.authMethodType, "type", "",
-		"Type of the auth method to login to. This field is optional and defaults to no type.")
+		"Type of the auth method to login to. Set to \"oidc\" to log in with an OIDC "+
+			"provider in a browser, or to \"x509\" to log in with the client certificate "+
+			"set with -client-cert and -client-key. This field is optional and defaults "+
+			"to no type.")
 
 	c.flags.StringVar(&c.bearerTokenFile, "bearer-token-file", "",
 		"Path to a file containing a secret bearer token to use with this auth method.")
@@ -58,6 +68,14 @@ func (c *cmd) init() {
 		"Metadata to set on the token, formatted as key=value. This flag "+
 			"may be specified multiple times to set multiple meta fields.")
 
+	c.flags.StringVar(&c.oidcCallbackListenAddr, "oidc-callback-listen-addr", "localhost:8550",
+		"The address to listen on for the browser to be redirected to once logged in "+
+			"with the OIDC provider. Only used with -type=oidc.")
+
+	c.flags.BoolVar(&c.oidcNoBrowser, "oidc-no-browser", false,
+		"Do not open the OIDC provider's login page in a browser, only print its URL. "+
+			"Only used with -type=oidc.")
+
 	c.initEnterpriseFlags()
 
 	c.http = &flags.HTTPFlags{}
@@ -159,4 +177,18 @@ Usage: consul login [options]
   requested auth method for a newly minted Consul ACL token. The companion
   command 'consul logout' should be used to destroy any tokens created this way
   to avoid a resource leak.
+
+  Login with a bearer token:
+
+      $ consul login -method=kubernetes -bearer-token-file=/path/to/jwt \
+          -token-sink-file=consul.token
+
+  Login with an OIDC provider in a browser:
+
+      $ consul login -method=oidc -type=oidc -token-sink-file=consul.token
+
+  Login with a TLS client certificate:
+
+      $ consul login -method=x509 -type=x509 -client-cert=client.pem \
+          -client-key=client-key.pem -token-sink-file=consul.token
 `
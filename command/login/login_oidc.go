@@ -0,0 +1,197 @@
+package login
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net"
+	"net/http"
+	"os/exec"
+	"runtime"
+	"time"
+
+	"github.com/hashicorp/go-uuid"
+
+	"github.com/hashicorp/consul/api"
+)
+
+const (
+	// oidcCallbackPath is the path of the local listener the OIDC provider
+	// redirects the browser to once the user logged in.
+	oidcCallbackPath = "/oidc/callback"
+
+	// oidcCallbackTimeout is how long to wait for the user to log in with
+	// the OIDC provider. It matches how long the servers keep the state of
+	// a login.
+	oidcCallbackTimeout = 10 * time.Minute
+)
+
+// oidcCallbackResult holds the query parameters the OIDC provider redirected
+// the browser to the callback listener with.
+type oidcCallbackResult struct {
+	state string
+	code  string
+	err   error
+}
+
+// oidcLogin logs in through the OIDC authorization code flow. The user logs in
+// with the OIDC provider in a browser, which is then redirected to a local
+// listener receiving the authorization code that is exchanged for a token.
+func (c *cmd) oidcLogin() int {
+	// Ensure that we don't try to use a token when performing a login
+	// operation.
+	c.http.SetToken("")
+	c.http.SetTokenFile("")
+
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	clientNonce, err := uuid.GenerateUUID()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error generating client nonce: %s", err))
+		return 1
+	}
+
+	ln, err := net.Listen("tcp", c.oidcCallbackListenAddr)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error starting OIDC callback listener: %s", err))
+		return 1
+	}
+	defer ln.Close()
+
+	redirectURI, err := oidcRedirectURI(c.oidcCallbackListenAddr, ln.Addr())
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error starting OIDC callback listener: %s", err))
+		return 1
+	}
+
+	authURL, _, err := client.ACL().OIDCAuthURL(&api.ACLOIDCAuthURLParams{
+		AuthMethod:  c.authMethodName,
+		RedirectURI: redirectURI,
+		ClientNonce: clientNonce,
+		Meta:        c.meta,
+	}, nil)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error fetching OIDC auth URL: %s", err))
+		return 1
+	}
+
+	resultCh := make(chan oidcCallbackResult, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc(oidcCallbackPath, func(w http.ResponseWriter, req *http.Request) {
+		result := oidcCallbackResult{
+			state: req.FormValue("state"),
+			code:  req.FormValue("code"),
+		}
+		if e := req.FormValue("error"); e != "" {
+			if desc := req.FormValue("error_description"); desc != "" {
+				e += ": " + desc
+			}
+			result.err = fmt.Errorf("OIDC provider returned an error: %s", e)
+		}
+
+		w.Header().Set("Content-Type", "text/html")
+		if result.err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, oidcCallbackPage, "Login failed", html.EscapeString(result.err.Error()))
+		} else {
+			fmt.Fprintf(w, oidcCallbackPage, "Login successful", "You can close this window and return to the terminal.")
+		}
+
+		select {
+		case resultCh <- result:
+		default:
+		}
+	})
+
+	srv := &http.Server{Handler: mux}
+	go srv.Serve(ln)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		srv.Shutdown(ctx)
+	}()
+
+	c.UI.Output(fmt.Sprintf("Complete the login via your OIDC provider. Launching browser to:\n\n    %s\n", authURL))
+	if !c.oidcNoBrowser {
+		if err := c.openBrowser(authURL); err != nil {
+			c.UI.Warn(fmt.Sprintf("Error opening browser, open the URL above manually: %s", err))
+		}
+	}
+	c.UI.Output("Waiting for OIDC authentication to complete...")
+
+	var result oidcCallbackResult
+	select {
+	case result = <-resultCh:
+	case <-c.shutdownCh:
+		c.UI.Error("Interrupted while waiting for OIDC authentication")
+		return 1
+	case <-time.After(oidcCallbackTimeout):
+		c.UI.Error("Timed out waiting for OIDC authentication")
+		return 1
+	}
+	if result.err != nil {
+		c.UI.Error(fmt.Sprintf("Error logging in: %s", result.err))
+		return 1
+	}
+
+	tok, _, err := client.ACL().OIDCCallback(&api.ACLOIDCCallbackParams{
+		AuthMethod:  c.authMethodName,
+		State:       result.state,
+		Code:        result.code,
+		ClientNonce: clientNonce,
+	}, nil)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error logging in: %s", err))
+		return 1
+	}
+
+	if err := c.writeToSink(tok); err != nil {
+		c.UI.Error(fmt.Sprintf("Error writing token to file sink: %s", err))
+		return 1
+	}
+
+	return 0
+}
+
+// oidcRedirectURI returns the URI the OIDC provider must redirect the browser
+// to. The host is kept as configured so that it matches the redirect URIs
+// allowed by the auth method, but the port is the one actually listened on.
+func oidcRedirectURI(listenAddr string, addr net.Addr) (string, error) {
+	host, _, err := net.SplitHostPort(listenAddr)
+	if err != nil {
+		return "", err
+	}
+	_, port, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return "", err
+	}
+	return "http://" + net.JoinHostPort(host, port) + oidcCallbackPath, nil
+}
+
+// openURL opens the URL in the default browser of the user.
+func openURL(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	return cmd.Start()
+}
+
+const oidcCallbackPage = `<!DOCTYPE html>
+<html>
+<head><title>Consul OIDC Login</title></head>
+<body>
+<h1>%s</h1>
+<p>%s</p>
+</body>
+</html>
+`
@@ -10,5 +10,11 @@ func (c *cmd) initEnterpriseFlags() {
 }
 
 func (c *cmd) login() int {
+	switch c.authMethodType {
+	case "oidc":
+		return c.oidcLogin()
+	case "x509":
+		return c.x509Login()
+	}
 	return c.bearerTokenLogin()
 }
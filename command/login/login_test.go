@@ -1,7 +1,12 @@
 package login
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"io/ioutil"
+	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -15,6 +20,7 @@ import (
 	"github.com/hashicorp/consul/agent"
 	"github.com/hashicorp/consul/agent/consul/authmethod/kubeauth"
 	"github.com/hashicorp/consul/agent/consul/authmethod/testauth"
+	"github.com/hashicorp/consul/agent/consul/authmethod/x509auth"
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/command/acl"
 	"github.com/hashicorp/consul/internal/go-sso/oidcauth/oidcauthtest"
@@ -470,3 +476,225 @@ func TestLoginCommand_jwt(t *testing.T) {
 		})
 	}
 }
+
+func TestLoginCommand_x509(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	testDir := testutil.TempDir(t, "acl")
+
+	a := agent.NewTestAgent(t, `
+	primary_datacenter = "dc1"
+	acl {
+		enabled = true
+		tokens {
+			initial_management = "root"
+		}
+	}`)
+
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	client := a.Client()
+
+	ca := x509auth.NewTestCA(t)
+	_, _, err := client.ACL().AuthMethodCreate(&api.ACLAuthMethod{
+		Name:   "x509",
+		Type:   "x509",
+		Config: (&api.X509AuthMethodConfig{CACerts: []string{ca.CertPEM}}).RenderToConfig(),
+	}, &api.WriteOptions{Token: "root"})
+	require.NoError(t, err)
+
+	_, _, err = client.ACL().BindingRuleCreate(&api.ACLBindingRule{
+		AuthMethod: "x509",
+		BindType:   api.BindingRuleBindTypeService,
+		BindName:   "${subject.common_name}",
+	}, &api.WriteOptions{Token: "root"})
+	require.NoError(t, err)
+
+	leaf := ca.Issue(t, &x509.Certificate{
+		Subject: pkix.Name{CommonName: "web"},
+	})
+	certFile := filepath.Join(testDir, "client.pem")
+	keyFile := filepath.Join(testDir, "client-key.pem")
+	require.NoError(t, ioutil.WriteFile(certFile, []byte(leaf.CertPEM), 0600))
+	require.NoError(t, ioutil.WriteFile(keyFile, []byte(leaf.KeyPEM), 0600))
+
+	tokenSinkFile := filepath.Join(testDir, "test.token")
+
+	t.Run("missing client certificate", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		code := cmd.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-method=x509",
+			"-type=x509",
+			"-token-sink-file", tokenSinkFile,
+		})
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.ErrorWriter.String(), "Missing required '-client-cert' and '-client-key' flags")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		defer os.Remove(tokenSinkFile)
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		// The certificate is not used for TLS, the agent is reached over
+		// plain HTTP, only to sign the login challenge.
+		code := cmd.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-method=x509",
+			"-type=x509",
+			"-client-cert", certFile,
+			"-client-key", keyFile,
+			"-token-sink-file", tokenSinkFile,
+		})
+		require.Equal(t, 0, code, "err: %s", ui.ErrorWriter.String())
+		require.Empty(t, ui.ErrorWriter.String())
+
+		raw, err := ioutil.ReadFile(tokenSinkFile)
+		require.NoError(t, err)
+
+		token := strings.TrimSpace(string(raw))
+		require.Len(t, token, 36, "must be a valid uid: %s", token)
+	})
+}
+
+func TestLoginCommand_oidc(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	testDir := testutil.TempDir(t, "acl")
+
+	a := agent.NewTestAgent(t, `
+	primary_datacenter = "dc1"
+	acl {
+		enabled = true
+		tokens {
+			initial_management = "root"
+		}
+	}`)
+
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	client := a.Client()
+
+	tokenSinkFile := filepath.Join(testDir, "test.token")
+
+	// spin up a fake oidc server
+	oidcServer := oidcauthtest.Start(t)
+	oidcServer.SetClientCreds("consul", "secret")
+	oidcServer.SetExpectedAuthCode("code")
+
+	_, _, err := client.ACL().AuthMethodCreate(&api.ACLAuthMethod{
+		Name: "oidc",
+		Type: "oidc",
+		Config: map[string]interface{}{
+			"OIDCDiscoveryURL":    oidcServer.Addr(),
+			"OIDCDiscoveryCACert": oidcServer.CACert(),
+			"OIDCClientID":        "consul",
+			"OIDCClientSecret":    "secret",
+			"AllowedRedirectURIs": []string{"http://127.0.0.1:8550/oidc/callback"},
+			"BoundAudiences":      []string{"consul"},
+			"JWTSupportedAlgs":    []string{"ES256"},
+			"ClaimMappings": map[string]string{
+				"color": "color",
+			},
+		},
+	}, &api.WriteOptions{Token: "root"})
+	require.NoError(t, err)
+
+	_, _, err = client.ACL().BindingRuleCreate(&api.ACLBindingRule{
+		AuthMethod: "oidc",
+		BindType:   api.BindingRuleBindTypeService,
+		BindName:   "test--${value.color}",
+	}, &api.WriteOptions{Token: "root"})
+	require.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	require.True(t, pool.AppendCertsFromPEM([]byte(oidcServer.CACert())))
+	browser := &http.Client{
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}},
+	}
+
+	// browse acts as the browser of the user: the fake provider logs the user
+	// in and immediately redirects to the callback listener of the command.
+	browse := func(authURL string) error {
+		u, err := url.Parse(authURL)
+		if err != nil {
+			return err
+		}
+		oidcServer.SetAllowedRedirectURIs([]string{u.Query().Get("redirect_uri")})
+		oidcServer.SetCustomClaims(map[string]interface{}{
+			"nonce": u.Query().Get("nonce"),
+		})
+
+		resp, err := browser.Get(authURL)
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+
+	t.Run("success", func(t *testing.T) {
+		defer os.Remove(tokenSinkFile)
+
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+		cmd.openBrowser = browse
+
+		code := cmd.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-method=oidc",
+			"-type=oidc",
+			"-oidc-callback-listen-addr=127.0.0.1:0",
+			"-token-sink-file", tokenSinkFile,
+		})
+		require.Equal(t, 0, code, "err: %s", ui.ErrorWriter.String())
+		require.Contains(t, ui.OutputWriter.String(), oidcServer.Addr()+"/auth")
+
+		raw, err := ioutil.ReadFile(tokenSinkFile)
+		require.NoError(t, err)
+
+		token, _, err := client.ACL().TokenReadSelf(&api.QueryOptions{Token: strings.TrimSpace(string(raw))})
+		require.NoError(t, err)
+		require.Equal(t, "oidc", token.AuthMethod)
+		require.Len(t, token.ServiceIdentities, 1)
+		require.Equal(t, "test--red", token.ServiceIdentities[0].ServiceName)
+	})
+
+	t.Run("provider error", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+		cmd.openBrowser = func(authURL string) error {
+			u, err := url.Parse(authURL)
+			if err != nil {
+				return err
+			}
+			resp, err := http.Get(u.Query().Get("redirect_uri") + "?error=access_denied&error_description=denied+by+user")
+			if err != nil {
+				return err
+			}
+			return resp.Body.Close()
+		}
+
+		code := cmd.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-method=oidc",
+			"-type=oidc",
+			"-oidc-callback-listen-addr=127.0.0.1:0",
+			"-token-sink-file", tokenSinkFile,
+		})
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.ErrorWriter.String(), "access_denied: denied by user")
+	})
+}
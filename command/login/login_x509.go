@@ -0,0 +1,68 @@
+package login
+
+import (
+	"crypto/tls"
+	"fmt"
+
+	"github.com/hashicorp/consul/api"
+)
+
+// x509Login logs in with the client certificate set with the -client-cert and
+// -client-key flags or the matching environment variables. The private key
+// never leaves the client, it is used to sign a challenge issued by the
+// servers which proves that the certificate belongs to the client.
+func (c *cmd) x509Login() int {
+	// Ensure that we don't try to use a token when performing a login
+	// operation.
+	c.http.SetToken("")
+	c.http.SetTokenFile("")
+
+	config := api.DefaultConfig()
+	c.http.MergeOntoConfig(config)
+	if config.TLSConfig.CertFile == "" || config.TLSConfig.KeyFile == "" {
+		c.UI.Error("Missing required '-client-cert' and '-client-key' flags")
+		return 1
+	}
+	cert, err := tls.LoadX509KeyPair(config.TLSConfig.CertFile, config.TLSConfig.KeyFile)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error loading client certificate: %s", err))
+		return 1
+	}
+
+	client, err := api.NewClient(config)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	challenge, _, err := client.ACL().LoginChallenge(&api.ACLLoginChallengeParams{
+		AuthMethod: c.authMethodName,
+	}, nil)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error requesting login challenge: %s", err))
+		return 1
+	}
+
+	bearerToken, err := api.NewX509LoginToken(challenge, cert)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error signing login challenge: %s", err))
+		return 1
+	}
+
+	tok, _, err := client.ACL().Login(&api.ACLLoginParams{
+		AuthMethod:  c.authMethodName,
+		BearerToken: bearerToken,
+		Meta:        c.meta,
+	}, nil)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error logging in: %s", err))
+		return 1
+	}
+
+	if err := c.writeToSink(tok); err != nil {
+		c.UI.Error(fmt.Sprintf("Error writing token to file sink: %s", err))
+		return 1
+	}
+
+	return 0
+}
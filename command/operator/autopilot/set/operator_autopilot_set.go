@@ -57,10 +57,10 @@ func (c *cmd) init() {
 		"(Enterprise-only) Controls the node_meta tag name used for separating servers into "+
 			"different redundancy zones.")
 	c.flags.Var(&c.disableUpgradeMigration, "disable-upgrade-migration",
-		"(Enterprise-only) Controls whether Consul will avoid promoting new servers until "+
+		"Controls whether Consul will avoid promoting new servers until "+
 			"it can perform a migration. Must be one of `true|false`.")
 	c.flags.Var(&c.upgradeVersionTag, "upgrade-version-tag",
-		"(Enterprise-only) The node_meta tag to use for version info when performing upgrade "+
+		"The node_meta tag to use for version info when performing upgrade "+
 			"migrations. If left blank, the Consul version will be used.")
 
 	c.http = &flags.HTTPFlags{}
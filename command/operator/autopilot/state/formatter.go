@@ -91,6 +91,7 @@ func formatServer(srv *api.AutopilotServer) string {
 	}
 	if srv.ReadReplica {
 		buffer.WriteString(fmt.Sprintf("      Read Replica:    %t\n", srv.ReadReplica))
+		buffer.WriteString(fmt.Sprintf("      Replication Lag: %d\n", srv.ReplicationLag))
 	}
 	if len(srv.Meta) > 0 {
 		buffer.WriteString(fmt.Sprintf("      Meta\n"))
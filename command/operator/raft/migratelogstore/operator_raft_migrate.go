@@ -0,0 +1,95 @@
+package migratelogstore
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/hashicorp/consul/agent/consul"
+	"github.com/hashicorp/consul/command/flags"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	help  string
+
+	// flags
+	dataDir string
+	from    string
+	to      string
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.StringVar(&c.dataDir, "data-dir", "",
+		"The data directory of the stopped server. This flag is required.")
+	c.flags.StringVar(&c.from, "from", consul.RaftLogStoreBackendBoltDB,
+		"The Raft log store backend to copy the logs from, \"boltdb\" or \"wal\".")
+	c.flags.StringVar(&c.to, "to", consul.RaftLogStoreBackendWAL,
+		"The Raft log store backend to copy the logs to, \"boltdb\" or \"wal\".")
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		c.UI.Error(fmt.Sprintf("Failed to parse args: %v", err))
+		return 1
+	}
+
+	if c.dataDir == "" {
+		c.UI.Error("Missing required '-data-dir' flag")
+		c.UI.Error(c.Help())
+		return 1
+	}
+
+	// The Raft state lives in the raft directory of the data directory.
+	raftDir := filepath.Join(c.dataDir, "raft")
+	n, err := consul.MigrateRaftLogStore(raftDir, c.from, c.to)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error migrating the Raft log store: %v", err))
+		return 1
+	}
+
+	c.UI.Output(fmt.Sprintf("Copied %d logs from %s to %s", n,
+		consul.RaftLogStorePath(raftDir, c.from), consul.RaftLogStorePath(raftDir, c.to)))
+	c.UI.Output(fmt.Sprintf("Set raft_logstore.backend to %q before starting the server. "+
+		"%s can be removed once the server has rejoined the cluster.",
+		c.to, consul.RaftLogStorePath(raftDir, c.from)))
+	return 0
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+const synopsis = "Copy the Raft logs of a stopped server to another log store backend"
+const help = `
+Usage: consul operator raft migrate-logstore -data-dir=<path> [options]
+
+  Copy the Raft logs and the Raft stable state of a stopped server from the
+  store of one backend to a new store of another backend, by default from
+  the BoltDB raft.db file to the write-ahead log.
+
+  The server must be stopped while its logs are copied. The source store is
+  left untouched, and the new backend is recorded as the one holding the Raft
+  state: the server refuses to start until raft_logstore.backend is set to the
+  new backend.
+
+      $ consul operator raft migrate-logstore -data-dir=/opt/consul
+`
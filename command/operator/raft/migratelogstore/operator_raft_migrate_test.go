@@ -0,0 +1,57 @@
+package migratelogstore
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/raft"
+	raftboltdb "github.com/hashicorp/raft-boltdb/v2"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/sdk/testutil"
+)
+
+func TestOperatorRaftMigrateLogStoreCommand_noTabs(t *testing.T) {
+	t.Parallel()
+	if strings.ContainsRune(New(cli.NewMockUi()).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestOperatorRaftMigrateLogStoreCommand(t *testing.T) {
+	t.Parallel()
+	dataDir := testutil.TempDir(t, "migrate")
+	raftDir := filepath.Join(dataDir, "raft")
+	require.NoError(t, os.MkdirAll(raftDir, 0700))
+
+	store, err := raftboltdb.NewBoltStore(filepath.Join(raftDir, "raft.db"))
+	require.NoError(t, err)
+	require.NoError(t, store.StoreLogs([]*raft.Log{
+		{Index: 1, Term: 1, Type: raft.LogConfiguration},
+		{Index: 2, Term: 1, Type: raft.LogCommand, Data: []byte("foo")},
+	}))
+	require.NoError(t, store.Close())
+
+	t.Run("missing data dir", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		require.Equal(t, 1, New(ui).Run(nil))
+		require.Contains(t, ui.ErrorWriter.String(), "Missing required '-data-dir' flag")
+	})
+
+	t.Run("migrate", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		code := New(ui).Run([]string{"-data-dir=" + dataDir})
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+		require.Contains(t, ui.OutputWriter.String(), "Copied 2 logs")
+		require.DirExists(t, filepath.Join(raftDir, "wal"))
+	})
+
+	t.Run("destination exists", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		require.Equal(t, 1, New(ui).Run([]string{"-data-dir=" + dataDir}))
+		require.Contains(t, ui.ErrorWriter.String(), "already exists")
+	})
+}
@@ -28,6 +28,7 @@ const help = `
 Usage: consul operator raft <subcommand> [options]
 
 The Raft operator command is used to interact with Consul's Raft subsystem. The
-command can be used to verify Raft peers or in rare cases to recover quorum by
-removing invalid peers.
+command can be used to verify Raft peers, to transfer the leadership before
+restarting the leader, or in rare cases to recover quorum by removing invalid
+peers.
 `
@@ -0,0 +1,86 @@
+package transferleader
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/mitchellh/cli"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	// flags
+	id string
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.StringVar(&c.id, "id", "",
+		"The ID of the server to transfer the leadership to. If not provided, "+
+			"the leadership is transferred to the most up to date voter.")
+
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		c.UI.Error(fmt.Sprintf("Failed to parse args: %v", err))
+		return 1
+	}
+
+	// Set up a client.
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	if err := client.Operator().RaftLeaderTransfer(c.id, nil); err != nil {
+		c.UI.Error(fmt.Sprintf("Error transferring leadership: %v", err))
+		return 1
+	}
+	if c.id != "" {
+		c.UI.Output(fmt.Sprintf("Transferred leadership to the server with id %q", c.id))
+	} else {
+		c.UI.Output("Transferred leadership")
+	}
+
+	return 0
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+const synopsis = "Transfer the Raft leadership to another Consul server"
+const help = `
+Usage: consul operator raft transfer-leader [options]
+
+  Transfer the Raft leadership from the current leader to another voter, the
+  one with the given -id if provided.
+
+  This can be used before restarting the leader, for example during a rolling
+  upgrade, so that the cluster goes through a single election instead of
+  waiting for the leader to be lost.
+`
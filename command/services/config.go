@@ -71,6 +71,13 @@ func serviceToAgentService(svc *structs.ServiceDefinition) (*api.AgentServiceReg
 		result.Check = nil
 	}
 
+	// Same for the proxy access logs, which are omitted from the API
+	// struct when they are not configured, see ConnectProxyConfig.ToAPI.
+	if result.Proxy != nil && result.Proxy.AccessLogs != nil &&
+		reflect.DeepEqual(*result.Proxy.AccessLogs, api.AccessLogsConfig{}) {
+		result.Proxy.AccessLogs = nil
+	}
+
 	return &result, nil
 }
 
@@ -41,10 +41,42 @@ type LogFile struct {
 	// Max rotated files to keep before removing them.
 	MaxFiles int
 
+	// Mode is the permission of the log files, 0640 when unset.
+	Mode os.FileMode
+
 	//acquire is the mutex utilized to ensure we have no concurrency issues
 	acquire sync.Mutex
 }
 
+// NewLogFile opens a LogFile writing to path, after pruning the files rotated
+// previously. When path is a directory, the file is named after
+// defaultName. A zero duration uses the default rotation duration and a zero
+// mode the default file permission.
+func NewLogFile(path, defaultName string, duration time.Duration, maxBytes, maxFiles int, mode os.FileMode) (*LogFile, error) {
+	dir, fileName := filepath.Split(path)
+	if fileName == "" {
+		fileName = defaultName
+	}
+	if duration == 0 {
+		duration = defaultRotateDuration
+	}
+	logFile := &LogFile{
+		fileName: fileName,
+		logPath:  dir,
+		duration: duration,
+		MaxBytes: maxBytes,
+		MaxFiles: maxFiles,
+		Mode:     mode,
+	}
+	if err := logFile.pruneFiles(); err != nil {
+		return nil, fmt.Errorf("failed to prune log files: %w", err)
+	}
+	if err := logFile.openNew(); err != nil {
+		return nil, err
+	}
+	return logFile, nil
+}
+
 func (l *LogFile) fileNamePattern() string {
 	// Extract the file extension
 	fileExt := filepath.Ext(l.fileName)
@@ -64,7 +96,11 @@ func (l *LogFile) openNew() error {
 	newfilePath := filepath.Join(l.logPath, newfileName)
 
 	// Try creating a file. We truncate the file because we are the only authority to write the logs
-	filePointer, err := os.OpenFile(newfilePath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0640)
+	mode := l.Mode
+	if mode == 0 {
+		mode = 0640
+	}
+	filePointer, err := os.OpenFile(newfilePath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
 	if err != nil {
 		return err
 	}
@@ -122,6 +158,19 @@ func removeFiles(files []string) error {
 	return nil
 }
 
+// Close closes the current log file.
+func (l *LogFile) Close() error {
+	l.acquire.Lock()
+	defer l.acquire.Unlock()
+
+	if l.FileInfo == nil {
+		return nil
+	}
+	err := l.FileInfo.Close()
+	l.FileInfo = nil
+	return err
+}
+
 // Write is used to implement io.Writer
 func (l *LogFile) Write(b []byte) (n int, err error) {
 	l.acquire.Lock()
@@ -3,7 +3,6 @@ package logging
 import (
 	"fmt"
 	"io"
-	"path/filepath"
 	"time"
 
 	"github.com/hashicorp/go-hclog"
@@ -92,24 +91,8 @@ func Setup(config Config, out io.Writer) (hclog.InterceptLogger, error) {
 
 	// Create a file logger if the user has specified the path to the log file
 	if config.LogFilePath != "" {
-		dir, fileName := filepath.Split(config.LogFilePath)
-		if fileName == "" {
-			fileName = "consul.log"
-		}
-		if config.LogRotateDuration == 0 {
-			config.LogRotateDuration = defaultRotateDuration
-		}
-		logFile := &LogFile{
-			fileName: fileName,
-			logPath:  dir,
-			duration: config.LogRotateDuration,
-			MaxBytes: config.LogRotateBytes,
-			MaxFiles: config.LogRotateMaxFiles,
-		}
-		if err := logFile.pruneFiles(); err != nil {
-			return nil, fmt.Errorf("Failed to prune log files: %w", err)
-		}
-		if err := logFile.openNew(); err != nil {
+		logFile, err := NewLogFile(config.LogFilePath, "consul.log", config.LogRotateDuration, config.LogRotateBytes, config.LogRotateMaxFiles, 0)
+		if err != nil {
 			return nil, fmt.Errorf("Failed to setup logging: %w", err)
 		}
 		writers = append(writers, logFile)
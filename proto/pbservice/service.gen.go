@@ -4,6 +4,26 @@ package pbservice
 
 import structs "github.com/hashicorp/consul/agent/structs"
 
+func AccessLogsConfigToStructs(s AccessLogsConfig) structs.AccessLogsConfig {
+	var t structs.AccessLogsConfig
+	t.Enabled = s.Enabled
+	t.DisableListenerLogs = s.DisableListenerLogs
+	t.Type = s.Type
+	t.Path = s.Path
+	t.JSONFormat = s.JSONFormat
+	t.TextFormat = s.TextFormat
+	return t
+}
+func NewAccessLogsConfigFromStructs(t structs.AccessLogsConfig) AccessLogsConfig {
+	var s AccessLogsConfig
+	s.Enabled = t.Enabled
+	s.DisableListenerLogs = t.DisableListenerLogs
+	s.Type = t.Type
+	s.Path = t.Path
+	s.JSONFormat = t.JSONFormat
+	s.TextFormat = t.TextFormat
+	return s
+}
 func ConnectProxyConfigToStructs(s ConnectProxyConfig) structs.ConnectProxyConfig {
 	var t structs.ConnectProxyConfig
 	t.DestinationServiceName = s.DestinationServiceName
@@ -17,6 +37,7 @@ func ConnectProxyConfigToStructs(s ConnectProxyConfig) structs.ConnectProxyConfi
 	t.MeshGateway = MeshGatewayConfigToStructs(s.MeshGateway)
 	t.Expose = ExposeConfigToStructs(s.Expose)
 	t.TransparentProxy = TransparentProxyConfigToStructs(s.TransparentProxy)
+	t.AccessLogs = AccessLogsConfigToStructs(s.AccessLogs)
 	return t
 }
 func NewConnectProxyConfigFromStructs(t structs.ConnectProxyConfig) ConnectProxyConfig {
@@ -32,6 +53,7 @@ func NewConnectProxyConfigFromStructs(t structs.ConnectProxyConfig) ConnectProxy
 	s.MeshGateway = NewMeshGatewayConfigFromStructs(t.MeshGateway)
 	s.Expose = NewExposeConfigFromStructs(t.Expose)
 	s.TransparentProxy = NewTransparentProxyConfigFromStructs(t.TransparentProxy)
+	s.AccessLogs = NewAccessLogsConfigFromStructs(t.AccessLogs)
 	return s
 }
 func ExposeConfigToStructs(s ExposeConfig) structs.ExposeConfig {
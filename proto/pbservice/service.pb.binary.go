@@ -106,3 +106,13 @@ func (msg *Weights) MarshalBinary() ([]byte, error) {
 func (msg *Weights) UnmarshalBinary(b []byte) error {
 	return proto.Unmarshal(b, msg)
 }
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (msg *AccessLogsConfig) MarshalBinary() ([]byte, error) {
+	return proto.Marshal(msg)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (msg *AccessLogsConfig) UnmarshalBinary(b []byte) error {
+	return proto.Unmarshal(b, msg)
+}
@@ -78,6 +78,8 @@ type ConnectProxyConfig struct {
 	TransparentProxy TransparentProxyConfig `protobuf:"bytes,10,opt,name=TransparentProxy,proto3" json:"TransparentProxy"`
 	// LocalServiceSocketPath is the path to the unix domain socket for the local service instance
 	LocalServiceSocketPath string `protobuf:"bytes,11,opt,name=LocalServiceSocketPath,proto3" json:"LocalServiceSocketPath,omitempty"`
+	// AccessLogs configures the output and format of Envoy access logs
+	AccessLogs AccessLogsConfig `protobuf:"bytes,12,opt,name=AccessLogs,proto3" json:"AccessLogs"`
 }
 
 func (m *ConnectProxyConfig) Reset()         { *m = ConnectProxyConfig{} }
@@ -602,6 +604,63 @@ func (m *Weights) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_Weights proto.InternalMessageInfo
 
+// AccessLogsConfig contains the associated default settings for all Envoy
+// instances within the datacenter or partition
+//
+// mog annotation:
+//
+// target=github.com/hashicorp/consul/agent/structs.AccessLogsConfig
+// output=service.gen.go
+// name=Structs
+type AccessLogsConfig struct {
+	// Enabled turns on all access logging
+	Enabled bool `protobuf:"varint,1,opt,name=Enabled,proto3" json:"Enabled,omitempty"`
+	// DisableListenerLogs turns off just listener logs for connections rejected by Envoy because they don't
+	// have a matching listener filter.
+	DisableListenerLogs bool `protobuf:"varint,2,opt,name=DisableListenerLogs,proto3" json:"DisableListenerLogs,omitempty"`
+	// Type selects the output for logs: "file", "stderr". "stdout"
+	Type github_com_hashicorp_consul_agent_structs.LogSinkType `protobuf:"bytes,3,opt,name=Type,proto3,casttype=github.com/hashicorp/consul/agent/structs.LogSinkType" json:"Type,omitempty"`
+	// Path is the output file to write logs
+	Path string `protobuf:"bytes,4,opt,name=Path,proto3" json:"Path,omitempty"`
+	// The presence of one format string or the other implies the access log string encoding.
+	// Defining both is invalid.
+	JSONFormat string `protobuf:"bytes,5,opt,name=JSONFormat,proto3" json:"JSONFormat,omitempty"`
+	TextFormat string `protobuf:"bytes,6,opt,name=TextFormat,proto3" json:"TextFormat,omitempty"`
+}
+
+func (m *AccessLogsConfig) Reset()         { *m = AccessLogsConfig{} }
+func (m *AccessLogsConfig) String() string { return proto.CompactTextString(m) }
+func (*AccessLogsConfig) ProtoMessage()    {}
+func (*AccessLogsConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cbb99233b75fb80b, []int{10}
+}
+func (m *AccessLogsConfig) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *AccessLogsConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_AccessLogsConfig.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *AccessLogsConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AccessLogsConfig.Merge(m, src)
+}
+func (m *AccessLogsConfig) XXX_Size() int {
+	return m.Size()
+}
+func (m *AccessLogsConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_AccessLogsConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AccessLogsConfig proto.InternalMessageInfo
+
 func init() {
 	proto.RegisterType((*ConnectProxyConfig)(nil), "pbservice.ConnectProxyConfig")
 	proto.RegisterType((*Upstream)(nil), "pbservice.Upstream")
@@ -615,88 +674,95 @@ func init() {
 	proto.RegisterMapType((map[string]ServiceAddress)(nil), "pbservice.ServiceDefinition.TaggedAddressesEntry")
 	proto.RegisterType((*ServiceAddress)(nil), "pbservice.ServiceAddress")
 	proto.RegisterType((*Weights)(nil), "pbservice.Weights")
+	proto.RegisterType((*AccessLogsConfig)(nil), "pbservice.AccessLogsConfig")
 }
 
 func init() { proto.RegisterFile("proto/pbservice/service.proto", fileDescriptor_cbb99233b75fb80b) }
 
 var fileDescriptor_cbb99233b75fb80b = []byte{
-	// 1212 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x96, 0xcd, 0x6e, 0x1b, 0x37,
-	0x10, 0xc7, 0xb5, 0xfa, 0xb0, 0x24, 0xda, 0xf5, 0x07, 0xad, 0xba, 0x5b, 0x37, 0x91, 0x1d, 0xa1,
-	0x08, 0x8c, 0xd6, 0x90, 0x12, 0x1b, 0xa9, 0x9b, 0x00, 0x29, 0x50, 0x5b, 0x6e, 0x11, 0x24, 0x4e,
-	0xd4, 0xb5, 0x8a, 0xa0, 0x05, 0x7a, 0xa0, 0x56, 0xf4, 0x6a, 0x61, 0x69, 0x29, 0x90, 0x94, 0x1b,
-	0xbd, 0x45, 0x8f, 0xbd, 0xf6, 0xd0, 0x7b, 0x1f, 0xc3, 0x40, 0x2f, 0x39, 0xf6, 0x64, 0xb4, 0xf6,
-	0x5b, 0xf8, 0x54, 0x70, 0xc8, 0x5d, 0xaf, 0x76, 0xb7, 0x46, 0x9a, 0x93, 0xc8, 0xf9, 0xcf, 0x0c,
-	0xb9, 0x9c, 0x1f, 0x87, 0x42, 0x77, 0xc7, 0x9c, 0x49, 0xd6, 0x1a, 0xf7, 0x04, 0xe5, 0x67, 0xbe,
-	0x4b, 0x5b, 0xe6, 0xb7, 0x09, 0x76, 0x5c, 0x8d, 0x84, 0xf5, 0x3b, 0x1e, 0x63, 0xde, 0x90, 0xb6,
-	0x40, 0xe8, 0x4d, 0x4e, 0x5a, 0x42, 0xf2, 0x89, 0x2b, 0xb5, 0xe3, 0xfa, 0x27, 0x61, 0x1e, 0x97,
-	0x8d, 0x46, 0x2c, 0x68, 0xe9, 0x1f, 0x23, 0xde, 0x4b, 0x2e, 0x32, 0xa0, 0x64, 0x28, 0x07, 0xee,
-	0x80, 0xba, 0xa7, 0xc6, 0xa5, 0xe6, 0x31, 0x8f, 0x69, 0x37, 0x35, 0xd2, 0xd6, 0xc6, 0xef, 0x25,
-	0x84, 0x0f, 0x58, 0x10, 0x50, 0x57, 0x76, 0x38, 0x7b, 0x33, 0x3d, 0x60, 0xc1, 0x89, 0xef, 0xe1,
-	0x2f, 0xd0, 0x5a, 0x9b, 0x0a, 0xe9, 0x07, 0x44, 0xfa, 0x2c, 0x38, 0xd6, 0x49, 0x5f, 0x92, 0x11,
-	0xb5, 0xad, 0x4d, 0x6b, 0xab, 0xea, 0xfc, 0x87, 0x8a, 0x77, 0x50, 0x2d, 0xad, 0x3c, 0x6b, 0xdb,
-	0x79, 0x88, 0xca, 0xd4, 0xf0, 0x03, 0xb4, 0xfa, 0x82, 0xb9, 0x64, 0x68, 0x2c, 0x5f, 0xf7, 0xfb,
-	0x9c, 0x0a, 0x61, 0x17, 0x20, 0x24, 0x4b, 0xc2, 0x9f, 0xa1, 0xe5, 0xb8, 0xb9, 0xc3, 0xb8, 0xb4,
-	0x8b, 0x9b, 0xd6, 0x56, 0xc9, 0x49, 0xd9, 0xf1, 0x23, 0x34, 0xa7, 0xbf, 0xc9, 0x2e, 0x6d, 0x5a,
-	0x5b, 0xf3, 0x3b, 0x1f, 0x35, 0xf5, 0x29, 0x37, 0xc3, 0x53, 0x6e, 0x1e, 0xc3, 0x29, 0xef, 0x17,
-	0xcf, 0x2f, 0x36, 0x2c, 0xc7, 0x38, 0xe3, 0x3d, 0x54, 0xfd, 0x7e, 0x2c, 0x24, 0xa7, 0x64, 0x24,
-	0xec, 0xb9, 0xcd, 0xc2, 0xd6, 0xfc, 0xce, 0x6a, 0x33, 0x3a, 0xde, 0x66, 0xa8, 0x41, 0x54, 0xce,
-	0xb9, 0xf1, 0xc5, 0x6d, 0x34, 0x7f, 0x44, 0xc5, 0xe0, 0x5b, 0x22, 0xe9, 0xcf, 0x64, 0x6a, 0x97,
-	0x61, 0xd1, 0x3b, 0xb1, 0xd0, 0x98, 0xaa, 0xd7, 0x32, 0x39, 0xe2, 0x61, 0x6a, 0xd7, 0x87, 0x6f,
-	0xc6, 0x4c, 0x50, 0xbb, 0x62, 0x76, 0x7d, 0x93, 0x40, 0x0b, 0x33, 0xb1, 0xc6, 0x19, 0x3f, 0x47,
-	0xc5, 0x23, 0xd6, 0xa7, 0x76, 0x55, 0x9d, 0xdd, 0xfe, 0xde, 0xf5, 0xc5, 0xc6, 0xae, 0xe7, 0xcb,
-	0xc1, 0xa4, 0xd7, 0x74, 0xd9, 0xa8, 0x35, 0x20, 0x62, 0xe0, 0xbb, 0x8c, 0x8f, 0x5b, 0x2e, 0x0b,
-	0xc4, 0x64, 0xd8, 0x22, 0x1e, 0x0d, 0xa4, 0xa1, 0x4c, 0x34, 0xa1, 0xfe, 0x2a, 0xdc, 0x81, 0x24,
-	0xf8, 0x18, 0x2d, 0x77, 0x39, 0x09, 0xc4, 0x98, 0x70, 0x1a, 0x68, 0x3a, 0x6c, 0x04, 0xbb, 0xb9,
-	0x17, 0xdb, 0x4d, 0xd2, 0x65, 0x66, 0x5f, 0xa9, 0x04, 0x0a, 0xac, 0x78, 0x89, 0x8e, 0x99, 0x7b,
-	0x4a, 0x65, 0x87, 0xc8, 0x81, 0x3d, 0xaf, 0xc1, 0xca, 0x56, 0x1b, 0x7f, 0x16, 0x51, 0x25, 0x3c,
-	0x64, 0xbc, 0x85, 0x96, 0x62, 0x24, 0x75, 0xa7, 0xe3, 0x10, 0xcb, 0xa4, 0x39, 0xc1, 0xa3, 0x42,
-	0x54, 0x8c, 0x89, 0x4b, 0x33, 0x78, 0x8c, 0xb4, 0x44, 0x76, 0x80, 0xbe, 0x90, 0xca, 0x0e, 0xb4,
-	0xd7, 0x11, 0x6a, 0x13, 0x49, 0x5c, 0x1a, 0x48, 0xca, 0x81, 0xc0, 0xaa, 0x13, 0xb3, 0x44, 0x9c,
-	0xee, 0xfb, 0x41, 0x3f, 0xc4, 0xba, 0x04, 0x5e, 0x29, 0x3b, 0xfe, 0x14, 0x7d, 0x10, 0xd9, 0x00,
-	0xe8, 0x39, 0x00, 0x7a, 0xd6, 0x18, 0xa3, 0xb9, 0xfc, 0x7f, 0x68, 0x4e, 0x40, 0x59, 0x79, 0x3f,
-	0x28, 0x1f, 0xa0, 0xd5, 0x03, 0x1a, 0x48, 0x4e, 0x86, 0x43, 0xe3, 0x35, 0xe1, 0xb4, 0x0f, 0xb0,
-	0x55, 0x9c, 0x2c, 0x29, 0xba, 0xda, 0x6a, 0xff, 0xb1, 0x52, 0xa3, 0xd8, 0xd5, 0x9e, 0x95, 0x32,
-	0x22, 0x00, 0xe8, 0xf9, 0xcc, 0x08, 0xc0, 0x74, 0xb6, 0xc4, 0x1d, 0xc2, 0xa5, 0xaf, 0x06, 0xf6,
-	0x42, 0xaa, 0xc4, 0x91, 0xd6, 0x08, 0xd0, 0xa2, 0x41, 0xcc, 0xf4, 0x3e, 0xbc, 0x86, 0xe6, 0x5e,
-	0x12, 0xe9, 0x9f, 0x69, 0x92, 0x2a, 0x8e, 0x99, 0xe1, 0x36, 0x5a, 0x3c, 0xf6, 0xfb, 0xd4, 0x25,
-	0xdc, 0x04, 0x00, 0x0b, 0xb3, 0x87, 0x67, 0x94, 0x36, 0x3d, 0xf1, 0x03, 0xc8, 0xef, 0x24, 0x62,
-	0x1a, 0x3f, 0xa0, 0x85, 0xf8, 0xad, 0x55, 0xab, 0x1d, 0xa8, 0xd6, 0x2c, 0xc2, 0xd5, 0xf4, 0x0c,
-	0x3f, 0x44, 0x25, 0x75, 0x0a, 0xc2, 0xce, 0x43, 0xc7, 0xf9, 0x30, 0x75, 0xeb, 0x95, 0x6a, 0x4a,
-	0xa3, 0x3d, 0x1b, 0x7f, 0x58, 0x08, 0xdd, 0x68, 0xb8, 0x81, 0x16, 0x5e, 0xf8, 0x42, 0xd2, 0x80,
-	0x72, 0xa0, 0xc8, 0x02, 0x8a, 0x66, 0x6c, 0x18, 0xa3, 0x22, 0x94, 0x41, 0x5f, 0x02, 0x18, 0x47,
-	0xf8, 0xa9, 0x09, 0x04, 0x16, 0x62, 0xf8, 0x85, 0x46, 0xbc, 0x8e, 0x2a, 0x1d, 0x05, 0x9a, 0xcb,
-	0x86, 0x06, 0xf7, 0x68, 0xae, 0xae, 0x4d, 0x87, 0x70, 0x41, 0xfb, 0xdf, 0x70, 0x36, 0x82, 0xef,
-	0x01, 0xd6, 0x2b, 0x4e, 0xd2, 0xdc, 0x38, 0x41, 0x2b, 0x29, 0xde, 0xf0, 0x77, 0xa6, 0x75, 0xc1,
-	0x45, 0xde, 0x7f, 0x7a, 0x7d, 0xb1, 0xf1, 0xf8, 0xdd, 0x5b, 0x57, 0x2c, 0xdd, 0x4d, 0x03, 0x6b,
-	0x48, 0xb4, 0x96, 0xdd, 0x9d, 0x14, 0x33, 0xaf, 0x26, 0xb2, 0xc7, 0x26, 0x41, 0x3f, 0xe3, 0xb4,
-	0x32, 0x35, 0x7c, 0x1f, 0x2d, 0xb6, 0x7d, 0x32, 0xa4, 0xfd, 0xb6, 0xcf, 0xa9, 0x2b, 0x87, 0x53,
-	0x38, 0xbf, 0x8a, 0x93, 0xb0, 0x36, 0x7e, 0x2b, 0xa3, 0x95, 0x14, 0x11, 0xf8, 0x08, 0x15, 0x9f,
-	0xfb, 0x41, 0xdf, 0x7c, 0xde, 0xe3, 0xeb, 0x8b, 0x8d, 0x47, 0xef, 0xfe, 0x79, 0x26, 0x9d, 0x4a,
-	0xe0, 0x40, 0x1a, 0xbc, 0x88, 0xf2, 0xd1, 0xab, 0x9a, 0x7f, 0xd6, 0x56, 0x25, 0x8d, 0x35, 0x2a,
-	0x18, 0x2b, 0x5b, 0x97, 0x78, 0xc2, 0x2e, 0x6e, 0x16, 0x94, 0x4d, 0x8d, 0xb1, 0x8d, 0xca, 0xb3,
-	0x8d, 0x28, 0x9c, 0x62, 0x82, 0x96, 0xba, 0xc4, 0xf3, 0x68, 0xd8, 0x90, 0xa8, 0xb0, 0x97, 0x01,
-	0xc2, 0x87, 0xb7, 0x91, 0xde, 0x4c, 0xc4, 0x1c, 0x06, 0x92, 0x4f, 0x0d, 0xa0, 0xc9, 0x7c, 0xf8,
-	0x09, 0x2a, 0x1e, 0x51, 0x49, 0xcc, 0x73, 0x7a, 0xff, 0xd6, 0xbc, 0xca, 0x11, 0x92, 0x39, 0x10,
-	0x03, 0xcc, 0xaa, 0x0a, 0x95, 0xa1, 0x42, 0x30, 0x56, 0xed, 0x37, 0xd6, 0x54, 0xb0, 0x6e, 0xbf,
-	0x33, 0xbd, 0xa4, 0xa4, 0x39, 0xd4, 0xfd, 0xae, 0x16, 0x5b, 0x10, 0xec, 0xea, 0x85, 0x08, 0x2f,
-	0x13, 0x18, 0xf0, 0x76, 0x74, 0x2f, 0xab, 0xb0, 0xc7, 0xcc, 0x90, 0xe8, 0xb6, 0x6e, 0xa3, 0xf2,
-	0x6b, 0xea, 0x7b, 0x03, 0x29, 0xcc, 0xbb, 0x88, 0x63, 0xee, 0x46, 0x71, 0x42, 0x17, 0x5c, 0x43,
-	0xa5, 0x2e, 0x3b, 0xa5, 0x81, 0xe9, 0x65, 0x7a, 0x82, 0xb7, 0xd1, 0xca, 0x61, 0x40, 0x7a, 0x43,
-	0xda, 0x25, 0xde, 0xab, 0x33, 0xca, 0xb9, 0xdf, 0xa7, 0xd0, 0xba, 0x2a, 0x4e, 0x5a, 0xc0, 0xbb,
-	0xa8, 0xa4, 0xdf, 0xe1, 0x45, 0x58, 0xef, 0x6e, 0x7c, 0x7b, 0xa9, 0x3f, 0x71, 0x8e, 0xf6, 0x55,
-	0x2d, 0xec, 0x50, 0x3d, 0x47, 0x63, 0xee, 0x0b, 0x0a, 0x05, 0x58, 0x81, 0xe8, 0xb5, 0xa6, 0xf9,
-	0x0b, 0x39, 0xab, 0x9a, 0x13, 0x49, 0xc4, 0xe0, 0x5d, 0x54, 0x36, 0x4b, 0xd8, 0x4b, 0x10, 0xfe,
-	0x71, 0xba, 0x7e, 0xc6, 0xc1, 0x09, 0x3d, 0xd7, 0x7f, 0x42, 0xb5, 0x2c, 0x40, 0xf0, 0x32, 0x2a,
-	0x9c, 0xd2, 0xa9, 0x79, 0xb4, 0xd5, 0x10, 0xb7, 0x50, 0xe9, 0x8c, 0x0c, 0x27, 0xfa, 0x65, 0xce,
-	0x4c, 0x6e, 0x52, 0x38, 0xda, 0xef, 0x49, 0xfe, 0x4b, 0x6b, 0x7d, 0x0f, 0x55, 0x23, 0x4e, 0x32,
-	0x72, 0xd6, 0xe2, 0x39, 0xab, 0xb1, 0xc0, 0xc6, 0x57, 0x51, 0xff, 0x0f, 0xf1, 0x8f, 0x5d, 0x0c,
-	0x6b, 0xf6, 0x62, 0x84, 0xe4, 0xe5, 0x6f, 0xc8, 0x6b, 0x3c, 0x8d, 0x2a, 0xaf, 0x02, 0x3b, 0x44,
-	0x08, 0x3f, 0xf0, 0x4c, 0xf7, 0x08, 0xa7, 0x4a, 0x79, 0x4d, 0x78, 0xa0, 0x14, 0x1d, 0x1b, 0x4e,
-	0xf7, 0x8f, 0xce, 0xff, 0xa9, 0xe7, 0xce, 0x2f, 0xeb, 0xd6, 0xdb, 0xcb, 0xba, 0xf5, 0xf7, 0x65,
-	0xdd, 0xfa, 0xe5, 0xaa, 0x9e, 0xfb, 0xf5, 0xaa, 0x9e, 0x7b, 0x7b, 0x55, 0xcf, 0xfd, 0x75, 0x55,
-	0xcf, 0xfd, 0xf8, 0xf9, 0x6d, 0xcd, 0x21, 0xf1, 0x5f, 0xbf, 0x37, 0x07, 0x86, 0xdd, 0x7f, 0x03,
-	0x00, 0x00, 0xff, 0xff, 0x93, 0xfb, 0xcc, 0xfb, 0x6a, 0x0c, 0x00, 0x00,
+	// 1312 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x57, 0x4f, 0x6f, 0x13, 0x47,
+	0x14, 0x8f, 0xff, 0xc5, 0xf6, 0x4b, 0x1a, 0x92, 0x21, 0x4d, 0xb7, 0x01, 0x9c, 0x60, 0x55, 0x28,
+	0x6a, 0x91, 0x0d, 0x44, 0x94, 0x82, 0x44, 0x25, 0x12, 0x87, 0x8a, 0x92, 0x80, 0xbb, 0x76, 0x85,
+	0x5a, 0xa9, 0x87, 0xc9, 0xee, 0x64, 0xbd, 0x8a, 0xbd, 0x63, 0xcd, 0x8c, 0x53, 0x72, 0xec, 0x37,
+	0xe8, 0xb1, 0xd7, 0x7e, 0x83, 0x7e, 0x0c, 0xa4, 0x5e, 0x38, 0xf6, 0x14, 0xb5, 0xe4, 0xd2, 0xcf,
+	0xc0, 0xa9, 0x9a, 0x3f, 0xbb, 0x1e, 0xef, 0x2e, 0x11, 0xe5, 0x94, 0x99, 0xf7, 0x7b, 0xef, 0xcd,
+	0x78, 0xde, 0xef, 0xfd, 0xde, 0x06, 0xae, 0x8d, 0x19, 0x15, 0xb4, 0x3d, 0x3e, 0xe4, 0x84, 0x9d,
+	0x84, 0x1e, 0x69, 0x9b, 0xbf, 0x2d, 0x65, 0x47, 0xf5, 0x04, 0x58, 0xbf, 0x1a, 0x50, 0x1a, 0x0c,
+	0x49, 0x5b, 0x01, 0x87, 0x93, 0xa3, 0x36, 0x17, 0x6c, 0xe2, 0x09, 0xed, 0xb8, 0x7e, 0x25, 0xce,
+	0xe3, 0xd1, 0xd1, 0x88, 0x46, 0x6d, 0xfd, 0xc7, 0x80, 0xd7, 0xd3, 0x87, 0x0c, 0x08, 0x1e, 0x8a,
+	0x81, 0x37, 0x20, 0xde, 0xb1, 0x71, 0x59, 0x0d, 0x68, 0x40, 0xb5, 0x9b, 0x5c, 0x69, 0x6b, 0xf3,
+	0xdf, 0x0a, 0xa0, 0x5d, 0x1a, 0x45, 0xc4, 0x13, 0x5d, 0x46, 0x5f, 0x9e, 0xee, 0xd2, 0xe8, 0x28,
+	0x0c, 0xd0, 0x97, 0xb0, 0xd6, 0x21, 0x5c, 0x84, 0x11, 0x16, 0x21, 0x8d, 0x7a, 0x3a, 0xe9, 0x33,
+	0x3c, 0x22, 0x4e, 0x61, 0xb3, 0xb0, 0x55, 0x77, 0xdf, 0x81, 0xa2, 0x3b, 0xb0, 0x9a, 0x45, 0x9e,
+	0x74, 0x9c, 0xa2, 0x8a, 0xca, 0xc5, 0xd0, 0x2d, 0xb8, 0xbc, 0x4f, 0x3d, 0x3c, 0x34, 0x96, 0x47,
+	0xbe, 0xcf, 0x08, 0xe7, 0x4e, 0x49, 0x85, 0xe4, 0x41, 0xe8, 0x73, 0x58, 0xb6, 0xcd, 0x5d, 0xca,
+	0x84, 0x53, 0xde, 0x2c, 0x6c, 0x55, 0xdc, 0x8c, 0x1d, 0xdd, 0x85, 0x79, 0xfd, 0x9b, 0x9c, 0xca,
+	0x66, 0x61, 0x6b, 0xe1, 0xce, 0x27, 0x2d, 0xfd, 0xca, 0xad, 0xf8, 0x95, 0x5b, 0x3d, 0xf5, 0xca,
+	0x3b, 0xe5, 0x57, 0x67, 0x1b, 0x05, 0xd7, 0x38, 0xa3, 0x7b, 0x50, 0xff, 0x7e, 0xcc, 0x05, 0x23,
+	0x78, 0xc4, 0x9d, 0xf9, 0xcd, 0xd2, 0xd6, 0xc2, 0x9d, 0xcb, 0xad, 0xe4, 0x79, 0x5b, 0x31, 0xa6,
+	0xa2, 0xe6, 0xdc, 0xa9, 0x2f, 0xea, 0xc0, 0xc2, 0x01, 0xe1, 0x83, 0x6f, 0xb0, 0x20, 0x3f, 0xe3,
+	0x53, 0xa7, 0xaa, 0x0e, 0xbd, 0x6a, 0x85, 0x5a, 0xa8, 0x3e, 0xcb, 0xe4, 0xb0, 0xc3, 0xe4, 0xad,
+	0xf7, 0x5e, 0x8e, 0x29, 0x27, 0x4e, 0xcd, 0xdc, 0x7a, 0x9a, 0x40, 0x03, 0x33, 0xb1, 0xc6, 0x19,
+	0x3d, 0x85, 0xf2, 0x01, 0xf5, 0x89, 0x53, 0x97, 0x6f, 0xb7, 0x73, 0xef, 0xed, 0xd9, 0xc6, 0x76,
+	0x10, 0x8a, 0xc1, 0xe4, 0xb0, 0xe5, 0xd1, 0x51, 0x7b, 0x80, 0xf9, 0x20, 0xf4, 0x28, 0x1b, 0xb7,
+	0x3d, 0x1a, 0xf1, 0xc9, 0xb0, 0x8d, 0x03, 0x12, 0x09, 0xc3, 0x32, 0xde, 0x52, 0xf5, 0x97, 0xe1,
+	0xae, 0x4a, 0x82, 0x7a, 0xb0, 0xdc, 0x67, 0x38, 0xe2, 0x63, 0xcc, 0x48, 0xa4, 0xd9, 0xe1, 0x80,
+	0xba, 0xcd, 0x75, 0xeb, 0x36, 0x69, 0x97, 0x99, 0x7b, 0x65, 0x12, 0x48, 0x62, 0xd9, 0x25, 0xea,
+	0x51, 0xef, 0x98, 0x88, 0x2e, 0x16, 0x03, 0x67, 0x41, 0x13, 0x2b, 0x1f, 0x45, 0x8f, 0x00, 0x1e,
+	0x79, 0x1e, 0xe1, 0x7c, 0x9f, 0x06, 0xdc, 0x59, 0x54, 0xd7, 0xb8, 0x62, 0x5d, 0x63, 0x0a, 0xce,
+	0x5c, 0xc0, 0x0a, 0x6a, 0xfe, 0x59, 0x86, 0x5a, 0x5c, 0x27, 0xb4, 0x05, 0x97, 0x2c, 0x32, 0xf6,
+	0x4f, 0xc7, 0x31, 0xb3, 0xd3, 0xe6, 0x14, 0xa5, 0x25, 0xcb, 0xf9, 0x18, 0x7b, 0x24, 0x87, 0xd2,
+	0x09, 0x96, 0xca, 0xae, 0xfa, 0xa6, 0x94, 0xc9, 0x2e, 0xcd, 0xa8, 0x01, 0xd0, 0xc1, 0x02, 0x7b,
+	0x24, 0x12, 0x84, 0x29, 0x12, 0xd7, 0x5d, 0xcb, 0x92, 0x50, 0x7d, 0x27, 0x8c, 0xfc, 0xb8, 0x33,
+	0x2a, 0xca, 0x2b, 0x63, 0x47, 0x9f, 0xc1, 0x47, 0x89, 0x4d, 0xf5, 0xc4, 0xbc, 0xea, 0x89, 0x59,
+	0xa3, 0xd5, 0x10, 0xd5, 0xff, 0xd3, 0x10, 0x29, 0x5e, 0xd7, 0x3e, 0x8c, 0xd7, 0xb7, 0xe0, 0xf2,
+	0x2e, 0x89, 0x04, 0xc3, 0xc3, 0xa1, 0xf1, 0x9a, 0x30, 0xe2, 0x2b, 0xbe, 0xd6, 0xdc, 0x3c, 0x28,
+	0x51, 0x07, 0x79, 0x7f, 0x8b, 0x2d, 0x60, 0xa9, 0xc3, 0x2c, 0x94, 0x13, 0xa1, 0x7a, 0x62, 0x21,
+	0x37, 0x42, 0x42, 0xa9, 0x12, 0x77, 0x31, 0x13, 0xa1, 0x5c, 0x38, 0x8b, 0x99, 0x12, 0x27, 0x58,
+	0x33, 0x82, 0x25, 0xc3, 0x52, 0x23, 0x9f, 0x68, 0x0d, 0xe6, 0x9f, 0x61, 0x11, 0x9e, 0x68, 0x26,
+	0xd5, 0x5c, 0xb3, 0x43, 0x1d, 0x58, 0xea, 0x85, 0x3e, 0xf1, 0x30, 0x33, 0x01, 0x4e, 0x29, 0xf3,
+	0x78, 0x06, 0xe9, 0x90, 0xa3, 0x30, 0x52, 0xf9, 0xdd, 0x54, 0x4c, 0xf3, 0x07, 0x58, 0xb4, 0x1b,
+	0x5f, 0x9e, 0xb6, 0x2b, 0xd5, 0x9d, 0xc7, 0xa7, 0xe9, 0x1d, 0xba, 0x0d, 0x15, 0xf9, 0x0a, 0xdc,
+	0x29, 0x2a, 0xd1, 0xfa, 0x38, 0x23, 0x1c, 0x12, 0x35, 0xa5, 0xd1, 0x9e, 0xcd, 0x3f, 0x0a, 0x00,
+	0x53, 0x0c, 0x35, 0x61, 0x71, 0x3f, 0xe4, 0x82, 0x44, 0x84, 0x29, 0x16, 0x15, 0x14, 0x8b, 0x66,
+	0x6c, 0x08, 0x41, 0x59, 0x95, 0x41, 0x37, 0x81, 0x5a, 0x27, 0xf4, 0x93, 0x1b, 0x15, 0x58, 0xb2,
+	0xe8, 0x17, 0x1b, 0xd1, 0x3a, 0xd4, 0xba, 0x92, 0x68, 0x1e, 0x1d, 0x1a, 0xba, 0x27, 0x7b, 0xd9,
+	0x36, 0x5d, 0xcc, 0x38, 0xf1, 0x1f, 0x33, 0x3a, 0x52, 0xbf, 0x47, 0x71, 0xbd, 0xe6, 0xa6, 0xcd,
+	0xcd, 0x23, 0x58, 0xc9, 0xf0, 0x0d, 0x7d, 0x67, 0xd4, 0x4f, 0x35, 0xf2, 0xce, 0xc3, 0xb7, 0x67,
+	0x1b, 0xf7, 0xdf, 0x5f, 0xfd, 0xac, 0x74, 0x53, 0x0d, 0x6c, 0x0a, 0x58, 0xcb, 0x17, 0x38, 0xc9,
+	0x99, 0xe7, 0x13, 0x71, 0x48, 0x27, 0x91, 0x9f, 0xf3, 0x5a, 0xb9, 0x18, 0xba, 0x01, 0x4b, 0x9d,
+	0x10, 0x0f, 0x89, 0xdf, 0x09, 0x19, 0xf1, 0xc4, 0xf0, 0x54, 0xbd, 0x5f, 0xcd, 0x4d, 0x59, 0x9b,
+	0xbf, 0x57, 0x61, 0x25, 0xc3, 0x08, 0x74, 0x00, 0xe5, 0xa7, 0x61, 0xe4, 0x9b, 0x9f, 0x77, 0xff,
+	0xed, 0xd9, 0xc6, 0xdd, 0xf7, 0xff, 0x79, 0x26, 0x9d, 0x4c, 0xe0, 0xaa, 0x34, 0x68, 0x09, 0x8a,
+	0xc9, 0x60, 0x2e, 0x3e, 0xe9, 0xc8, 0x92, 0x5a, 0x42, 0xa5, 0xd6, 0xd2, 0xd6, 0xc7, 0x01, 0x77,
+	0xca, 0x9b, 0x25, 0x69, 0x93, 0x6b, 0xe4, 0x40, 0x75, 0x56, 0x88, 0xe2, 0x2d, 0xc2, 0x70, 0xa9,
+	0x8f, 0x83, 0x80, 0xc4, 0x82, 0x44, 0xb8, 0xb3, 0xac, 0x48, 0x78, 0xfb, 0x22, 0xa6, 0xb7, 0x52,
+	0x31, 0x7b, 0x91, 0x60, 0xa7, 0x86, 0xa0, 0xe9, 0x7c, 0xe8, 0x01, 0x94, 0x0f, 0x88, 0xc0, 0x66,
+	0x22, 0xdf, 0xb8, 0x30, 0xaf, 0x74, 0x54, 0xc9, 0x5c, 0x15, 0xa3, 0x38, 0x2b, 0x2b, 0x54, 0x55,
+	0x15, 0x52, 0x6b, 0x29, 0xbf, 0x96, 0xa8, 0x20, 0x2d, 0xbf, 0x33, 0x5a, 0x52, 0xd1, 0x3c, 0xd4,
+	0x7a, 0xb7, 0x6a, 0x1d, 0xa8, 0xec, 0x72, 0x42, 0xc4, 0xcd, 0xa4, 0x0c, 0xe8, 0x66, 0xd2, 0x97,
+	0xf5, 0xcd, 0xd2, 0xbb, 0x42, 0x92, 0x6e, 0xbd, 0x09, 0xd5, 0x17, 0x24, 0x0c, 0x06, 0x82, 0x9b,
+	0xd1, 0x8a, 0x2c, 0x77, 0x83, 0xb8, 0xb1, 0x0b, 0x5a, 0x85, 0x4a, 0x9f, 0x1e, 0x93, 0xc8, 0x68,
+	0x99, 0xde, 0xa0, 0x9b, 0xb0, 0xb2, 0x17, 0xe1, 0xc3, 0x21, 0xe9, 0xe3, 0xe0, 0xf9, 0x09, 0x61,
+	0x2c, 0xf4, 0x89, 0x92, 0xae, 0x9a, 0x9b, 0x05, 0xd0, 0x36, 0x54, 0xf4, 0x28, 0x5f, 0x52, 0xe7,
+	0x5d, 0xb3, 0xaf, 0x97, 0xf9, 0x0e, 0x74, 0xb5, 0xaf, 0x94, 0xb0, 0x3d, 0x39, 0x8e, 0xc6, 0x2c,
+	0xe4, 0x44, 0x15, 0x60, 0x45, 0x45, 0xaf, 0xb5, 0xcc, 0x57, 0xe8, 0x2c, 0x6a, 0x5e, 0x24, 0x15,
+	0x83, 0xb6, 0xa1, 0x6a, 0x8e, 0x70, 0x2e, 0xa9, 0xf0, 0x4f, 0xb3, 0xf5, 0x33, 0x0e, 0x6e, 0xec,
+	0xb9, 0xfe, 0x13, 0xac, 0xe6, 0x11, 0x04, 0x2d, 0x43, 0xe9, 0x98, 0x9c, 0x9a, 0xa1, 0x2d, 0x97,
+	0xa8, 0x0d, 0x95, 0x13, 0x3c, 0x9c, 0xe8, 0xc9, 0x9c, 0x9b, 0xdc, 0xa4, 0x70, 0xb5, 0xdf, 0x83,
+	0xe2, 0x57, 0x85, 0xf5, 0x7b, 0x50, 0x4f, 0x78, 0x92, 0x93, 0x73, 0xd5, 0xce, 0x59, 0xb7, 0x02,
+	0x9b, 0x5f, 0xc3, 0xd2, 0x6c, 0x56, 0xbb, 0x31, 0x0a, 0xb3, 0x8d, 0x11, 0x33, 0xaf, 0x38, 0x65,
+	0x5e, 0xf3, 0x61, 0x52, 0x79, 0x19, 0xd8, 0xc5, 0x9c, 0x87, 0x51, 0x60, 0xd4, 0x23, 0xde, 0x4a,
+	0xe4, 0x05, 0x66, 0x91, 0x44, 0x74, 0x6c, 0xbc, 0x6d, 0xfe, 0x52, 0x84, 0xe5, 0xf4, 0x37, 0x8f,
+	0x74, 0xd7, 0x05, 0xf7, 0xcd, 0x50, 0x88, 0xb7, 0x72, 0x26, 0x76, 0x42, 0x2e, 0xd7, 0xb1, 0x20,
+	0xc9, 0x30, 0x23, 0x3f, 0x79, 0x90, 0x54, 0x1b, 0xf5, 0x55, 0x54, 0xfa, 0x10, 0xb5, 0xd9, 0xa7,
+	0x41, 0x2f, 0x8c, 0x34, 0xd5, 0x55, 0x9a, 0x64, 0x60, 0x94, 0xad, 0x81, 0xd1, 0x00, 0xf8, 0xb6,
+	0xf7, 0xfc, 0xd9, 0x63, 0xca, 0x46, 0x58, 0x18, 0x31, 0xb1, 0x2c, 0x12, 0xef, 0x93, 0x97, 0xc2,
+	0xe0, 0xf3, 0x1a, 0x9f, 0x5a, 0x76, 0x0e, 0x5e, 0xfd, 0xd3, 0x98, 0x7b, 0xf5, 0xa6, 0x51, 0x78,
+	0xfd, 0xa6, 0x51, 0xf8, 0xfb, 0x4d, 0xa3, 0xf0, 0xeb, 0x79, 0x63, 0xee, 0xb7, 0xf3, 0xc6, 0xdc,
+	0xeb, 0xf3, 0xc6, 0xdc, 0x5f, 0xe7, 0x8d, 0xb9, 0x1f, 0xbf, 0xb8, 0xe8, 0xca, 0xa9, 0x7f, 0x99,
+	0x0e, 0xe7, 0x95, 0x61, 0xfb, 0xbf, 0x01, 0x00, 0xe0, 0x27, 0x75, 0x0a, 0xb1, 0x0d, 0x00, 0x00,
 }
 
 func (m *ConnectProxyConfig) Marshal() (dAtA []byte, err error) {
@@ -719,6 +785,16 @@ func (m *ConnectProxyConfig) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	{
+		size, err := m.AccessLogs.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintService(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x62
 	if len(m.LocalServiceSocketPath) > 0 {
 		i -= len(m.LocalServiceSocketPath)
 		copy(dAtA[i:], m.LocalServiceSocketPath)
@@ -1427,6 +1503,77 @@ func (m *Weights) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *AccessLogsConfig) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *AccessLogsConfig) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *AccessLogsConfig) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.TextFormat) > 0 {
+		i -= len(m.TextFormat)
+		copy(dAtA[i:], m.TextFormat)
+		i = encodeVarintService(dAtA, i, uint64(len(m.TextFormat)))
+		i--
+		dAtA[i] = 0x32
+	}
+	if len(m.JSONFormat) > 0 {
+		i -= len(m.JSONFormat)
+		copy(dAtA[i:], m.JSONFormat)
+		i = encodeVarintService(dAtA, i, uint64(len(m.JSONFormat)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if len(m.Path) > 0 {
+		i -= len(m.Path)
+		copy(dAtA[i:], m.Path)
+		i = encodeVarintService(dAtA, i, uint64(len(m.Path)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.Type) > 0 {
+		i -= len(m.Type)
+		copy(dAtA[i:], m.Type)
+		i = encodeVarintService(dAtA, i, uint64(len(m.Type)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.DisableListenerLogs {
+		i--
+		if m.DisableListenerLogs {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Enabled {
+		i--
+		if m.Enabled {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintService(dAtA []byte, offset int, v uint64) int {
 	offset -= sovService(v)
 	base := offset
@@ -1483,6 +1630,8 @@ func (m *ConnectProxyConfig) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovService(uint64(l))
 	}
+	l = m.AccessLogs.Size()
+	n += 1 + l + sovService(uint64(l))
 	return n
 }
 
@@ -1742,6 +1891,37 @@ func (m *Weights) Size() (n int) {
 	return n
 }
 
+func (m *AccessLogsConfig) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Enabled {
+		n += 2
+	}
+	if m.DisableListenerLogs {
+		n += 2
+	}
+	l = len(m.Type)
+	if l > 0 {
+		n += 1 + l + sovService(uint64(l))
+	}
+	l = len(m.Path)
+	if l > 0 {
+		n += 1 + l + sovService(uint64(l))
+	}
+	l = len(m.JSONFormat)
+	if l > 0 {
+		n += 1 + l + sovService(uint64(l))
+	}
+	l = len(m.TextFormat)
+	if l > 0 {
+		n += 1 + l + sovService(uint64(l))
+	}
+	return n
+}
+
 func sovService(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -2125,6 +2305,39 @@ func (m *ConnectProxyConfig) Unmarshal(dAtA []byte) error {
 			}
 			m.LocalServiceSocketPath = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AccessLogs", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowService
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthService
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthService
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.AccessLogs.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipService(dAtA[iNdEx:])
@@ -4079,6 +4292,224 @@ func (m *Weights) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *AccessLogsConfig) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowService
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: AccessLogsConfig: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: AccessLogsConfig: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Enabled", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowService
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Enabled = bool(v != 0)
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DisableListenerLogs", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowService
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.DisableListenerLogs = bool(v != 0)
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowService
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthService
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthService
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Type = github_com_hashicorp_consul_agent_structs.LogSinkType(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Path", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowService
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthService
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthService
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Path = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field JSONFormat", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowService
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthService
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthService
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.JSONFormat = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TextFormat", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowService
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthService
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthService
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TextFormat = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipService(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthService
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipService(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
@@ -2,6 +2,7 @@ package iptables
 
 import (
 	"errors"
+	"fmt"
 	"strconv"
 )
 
@@ -24,6 +25,28 @@ const (
 	DefaultTProxyOutboundPort = 15001
 )
 
+// Backend is the firewall framework used to apply the traffic redirection
+// rules.
+type Backend string
+
+const (
+	// BackendIptables applies the rules with the legacy iptables binary.
+	BackendIptables Backend = "iptables"
+
+	// BackendNftables applies the rules with the nft binary, for systems
+	// that only ship nftables.
+	BackendNftables Backend = "nftables"
+)
+
+// binary returns the command line utility used to apply rules for the
+// backend.
+func (b Backend) binary() string {
+	if b == BackendNftables {
+		return "nft"
+	}
+	return "iptables"
+}
+
 // Config is used to configure which traffic interception and redirection
 // rules should be applied with the iptables commands.
 type Config struct {
@@ -60,11 +83,16 @@ type Config struct {
 	// e.g. /var/run/netns/foo.
 	NetNS string
 
-	// IptablesProvider is the Provider that will apply iptables rules.
+	// Backend is the firewall framework used to apply the rules. Defaults
+	// to BackendIptables.
+	Backend Backend
+
+	// IptablesProvider is the Provider that will apply the rules. It is
+	// used for both backends.
 	IptablesProvider Provider
 }
 
-// Provider is an interface for executing iptables rules.
+// Provider is an interface for executing iptables or nftables rules.
 type Provider interface {
 	// AddRule adds a rule without executing it.
 	AddRule(name string, args ...string)
@@ -95,6 +123,10 @@ func Setup(cfg Config) error {
 		cfg.ProxyOutboundPort = DefaultTProxyOutboundPort
 	}
 
+	if cfg.Backend == BackendNftables {
+		return setupNftables(cfg)
+	}
+
 	// Create chains we will use for redirection.
 	chains := []string{ProxyInboundChain, ProxyInboundRedirectChain, ProxyOutputChain, ProxyOutputRedirectChain, DNSChain}
 	for _, chain := range chains {
@@ -171,5 +203,11 @@ func validateConfig(cfg Config) error {
 		return errors.New("ProxyInboundPort is required to set up traffic redirection")
 	}
 
+	switch cfg.Backend {
+	case "", BackendIptables, BackendNftables:
+	default:
+		return fmt.Errorf("Backend must be one of %q or %q, got %q", BackendIptables, BackendNftables, cfg.Backend)
+	}
+
 	return nil
 }
@@ -9,7 +9,8 @@ import (
 	"os/exec"
 )
 
-// iptablesExecutor implements IptablesProvider using exec.Cmd.
+// iptablesExecutor implements IptablesProvider using exec.Cmd. It runs the
+// rules of either backend, since both are applied one command at a time.
 type iptablesExecutor struct {
 	commands []*exec.Cmd
 	cfg      Config
@@ -28,7 +29,7 @@ func (i *iptablesExecutor) AddRule(name string, args ...string) {
 }
 
 func (i *iptablesExecutor) ApplyRules() error {
-	_, err := exec.LookPath("iptables")
+	_, err := exec.LookPath(i.cfg.Backend.binary())
 	if err != nil {
 		return err
 	}
@@ -3,7 +3,7 @@
 
 package iptables
 
-import "errors"
+import "fmt"
 
 // iptablesExecutor implements IptablesProvider and errors out on any non-linux OS.
 type iptablesExecutor struct {
@@ -13,7 +13,7 @@ type iptablesExecutor struct {
 func (i *iptablesExecutor) AddRule(_ string, _ ...string) {}
 
 func (i *iptablesExecutor) ApplyRules() error {
-	return errors.New("applying traffic redirection rules with 'iptables' is not supported on this operating system; only linux OS is supported")
+	return fmt.Errorf("applying traffic redirection rules with '%s' is not supported on this operating system; only linux OS is supported", i.cfg.Backend.binary())
 }
 
 func (i *iptablesExecutor) Rules() []string {
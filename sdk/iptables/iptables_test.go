@@ -207,6 +207,91 @@ func TestSetup(t *testing.T) {
 	}
 }
 
+func TestSetup_nftables(t *testing.T) {
+	cases := []struct {
+		name          string
+		cfg           Config
+		expectedRules []string
+	}{
+		{
+			"no proxy outbound port provided",
+			Config{
+				ProxyUserID:      "123",
+				ProxyInboundPort: 20000,
+				Backend:          BackendNftables,
+				IptablesProvider: &fakeIptablesProvider{},
+			},
+			[]string{
+				"nft add table ip consul",
+				"nft -- add chain ip consul PREROUTING { type nat hook prerouting priority -100 ; }",
+				"nft -- add chain ip consul OUTPUT { type nat hook output priority -100 ; }",
+				"nft add chain ip consul CONSUL_PROXY_INBOUND",
+				"nft add chain ip consul CONSUL_PROXY_IN_REDIRECT",
+				"nft add chain ip consul CONSUL_PROXY_OUTPUT",
+				"nft add chain ip consul CONSUL_PROXY_REDIRECT",
+				"nft add chain ip consul CONSUL_DNS_REDIRECT",
+				"nft add rule ip consul CONSUL_PROXY_REDIRECT meta l4proto tcp redirect to :15001",
+				"nft add rule ip consul OUTPUT meta l4proto tcp jump CONSUL_PROXY_OUTPUT",
+				"nft add rule ip consul CONSUL_PROXY_OUTPUT meta skuid 123 return",
+				"nft add rule ip consul CONSUL_PROXY_OUTPUT ip daddr 127.0.0.1/32 return",
+				"nft add rule ip consul CONSUL_PROXY_OUTPUT jump CONSUL_PROXY_REDIRECT",
+				"nft add rule ip consul CONSUL_PROXY_IN_REDIRECT meta l4proto tcp redirect to :20000",
+				"nft add rule ip consul PREROUTING meta l4proto tcp jump CONSUL_PROXY_INBOUND",
+				"nft add rule ip consul CONSUL_PROXY_INBOUND meta l4proto tcp jump CONSUL_PROXY_IN_REDIRECT",
+			},
+		},
+		{
+			"all options provided",
+			Config{
+				ProxyUserID:          "123",
+				ProxyInboundPort:     20000,
+				ProxyOutboundPort:    21000,
+				ConsulDNSIP:          "10.0.34.16",
+				ExcludeInboundPorts:  []string{"22"},
+				ExcludeOutboundPorts: []string{"8500"},
+				ExcludeOutboundCIDRs: []string{"10.0.0.0/8"},
+				ExcludeUIDs:          []string{"456"},
+				Backend:              BackendNftables,
+				IptablesProvider:     &fakeIptablesProvider{},
+			},
+			[]string{
+				"nft add table ip consul",
+				"nft -- add chain ip consul PREROUTING { type nat hook prerouting priority -100 ; }",
+				"nft -- add chain ip consul OUTPUT { type nat hook output priority -100 ; }",
+				"nft add chain ip consul CONSUL_PROXY_INBOUND",
+				"nft add chain ip consul CONSUL_PROXY_IN_REDIRECT",
+				"nft add chain ip consul CONSUL_PROXY_OUTPUT",
+				"nft add chain ip consul CONSUL_PROXY_REDIRECT",
+				"nft add chain ip consul CONSUL_DNS_REDIRECT",
+				"nft add rule ip consul CONSUL_PROXY_REDIRECT meta l4proto tcp redirect to :21000",
+				"nft add rule ip consul CONSUL_DNS_REDIRECT udp dport 53 dnat to 10.0.34.16",
+				"nft add rule ip consul CONSUL_DNS_REDIRECT tcp dport 53 dnat to 10.0.34.16",
+				"nft add rule ip consul OUTPUT udp dport 53 jump CONSUL_DNS_REDIRECT",
+				"nft add rule ip consul OUTPUT tcp dport 53 jump CONSUL_DNS_REDIRECT",
+				"nft add rule ip consul OUTPUT meta l4proto tcp jump CONSUL_PROXY_OUTPUT",
+				"nft add rule ip consul CONSUL_PROXY_OUTPUT meta skuid 123 return",
+				"nft add rule ip consul CONSUL_PROXY_OUTPUT ip daddr 127.0.0.1/32 return",
+				"nft add rule ip consul CONSUL_PROXY_OUTPUT jump CONSUL_PROXY_REDIRECT",
+				"nft insert rule ip consul CONSUL_PROXY_OUTPUT tcp dport 8500 return",
+				"nft insert rule ip consul CONSUL_PROXY_OUTPUT ip daddr 10.0.0.0/8 return",
+				"nft insert rule ip consul CONSUL_PROXY_OUTPUT meta skuid 456 return",
+				"nft add rule ip consul CONSUL_PROXY_IN_REDIRECT meta l4proto tcp redirect to :20000",
+				"nft add rule ip consul PREROUTING meta l4proto tcp jump CONSUL_PROXY_INBOUND",
+				"nft add rule ip consul CONSUL_PROXY_INBOUND meta l4proto tcp jump CONSUL_PROXY_IN_REDIRECT",
+				"nft insert rule ip consul CONSUL_PROXY_INBOUND tcp dport 22 return",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := Setup(c.cfg)
+			require.NoError(t, err)
+			require.Equal(t, c.expectedRules, c.cfg.IptablesProvider.Rules())
+		})
+	}
+}
+
 func TestSetup_errors(t *testing.T) {
 	cases := []struct {
 		name   string
@@ -229,6 +314,16 @@ func TestSetup_errors(t *testing.T) {
 			},
 			"ProxyInboundPort is required to set up traffic redirection",
 		},
+		{
+			"invalid backend",
+			Config{
+				ProxyUserID:      "123",
+				ProxyInboundPort: 20000,
+				Backend:          "pf",
+				IptablesProvider: &iptablesExecutor{},
+			},
+			`Backend must be one of "iptables" or "nftables", got "pf"`,
+		},
 	}
 
 	for _, c := range cases {
@@ -0,0 +1,112 @@
+package iptables
+
+import (
+	"strconv"
+)
+
+const (
+	// NftablesTable is the nftables table holding all traffic redirection
+	// chains when the nftables backend is used.
+	NftablesTable = "consul"
+
+	// nftablesPreroutingChain and nftablesOutputChain are the base chains
+	// hooked into the nat prerouting and output hooks. nftables has no
+	// built-in chains, so these stand in for iptables' PREROUTING and OUTPUT.
+	nftablesPreroutingChain = "PREROUTING"
+	nftablesOutputChain     = "OUTPUT"
+
+	// nftablesNatPriority is the priority of iptables' destination NAT
+	// chains, so that rules are evaluated at the same point as they would be
+	// with the iptables backend.
+	nftablesNatPriority = "-100"
+)
+
+// setupNftables adds the nftables equivalent of the rules set up by Setup
+// for the iptables backend. All chains are created in a dedicated table so
+// that the rules do not interfere with any other nftables configuration.
+func setupNftables(cfg Config) error {
+	p := cfg.IptablesProvider
+
+	addRule := func(chain string, expr ...string) {
+		p.AddRule("nft", append([]string{"add", "rule", "ip", NftablesTable, chain}, expr...)...)
+	}
+	// insertRule prepends the rule to the chain so that it takes precedence
+	// over the rules added with addRule, like iptables -I.
+	insertRule := func(chain string, expr ...string) {
+		p.AddRule("nft", append([]string{"insert", "rule", "ip", NftablesTable, chain}, expr...)...)
+	}
+
+	p.AddRule("nft", "add", "table", "ip", NftablesTable)
+
+	// Create the base chains. The "--" stops nft from parsing the negative
+	// priority as a command line option.
+	p.AddRule("nft", "--", "add", "chain", "ip", NftablesTable, nftablesPreroutingChain,
+		"{", "type", "nat", "hook", "prerouting", "priority", nftablesNatPriority, ";", "}")
+	p.AddRule("nft", "--", "add", "chain", "ip", NftablesTable, nftablesOutputChain,
+		"{", "type", "nat", "hook", "output", "priority", nftablesNatPriority, ";", "}")
+
+	// Create chains we will use for redirection.
+	chains := []string{ProxyInboundChain, ProxyInboundRedirectChain, ProxyOutputChain, ProxyOutputRedirectChain, DNSChain}
+	for _, chain := range chains {
+		p.AddRule("nft", "add", "chain", "ip", NftablesTable, chain)
+	}
+
+	// Configure outbound rules.
+	{
+		// Redirects outbound TCP traffic hitting PROXY_REDIRECT chain to Envoy's outbound listener port.
+		addRule(ProxyOutputRedirectChain, "meta", "l4proto", "tcp", "redirect", "to", ":"+strconv.Itoa(cfg.ProxyOutboundPort))
+
+		// The DNS rules are applied before the rules that directs all TCP traffic, so that the traffic going to port 53 goes through this rule first.
+		if cfg.ConsulDNSIP != "" {
+			// Traffic in the DNSChain is directed to the Consul DNS Service IP.
+			addRule(DNSChain, "udp", "dport", "53", "dnat", "to", cfg.ConsulDNSIP)
+			addRule(DNSChain, "tcp", "dport", "53", "dnat", "to", cfg.ConsulDNSIP)
+
+			// For outbound TCP and UDP traffic going to port 53 (DNS), jump to the DNSChain.
+			addRule(nftablesOutputChain, "udp", "dport", "53", "jump", DNSChain)
+			addRule(nftablesOutputChain, "tcp", "dport", "53", "jump", DNSChain)
+		}
+
+		// For outbound TCP traffic jump from OUTPUT chain to PROXY_OUTPUT chain.
+		addRule(nftablesOutputChain, "meta", "l4proto", "tcp", "jump", ProxyOutputChain)
+
+		// Don't redirect proxy traffic back to itself, return it to the next chain for processing.
+		addRule(ProxyOutputChain, "meta", "skuid", cfg.ProxyUserID, "return")
+
+		// Skip localhost traffic, doesn't need to be routed via the proxy.
+		addRule(ProxyOutputChain, "ip", "daddr", "127.0.0.1/32", "return")
+
+		// Redirect remaining outbound traffic to Envoy.
+		addRule(ProxyOutputChain, "jump", ProxyOutputRedirectChain)
+
+		for _, outboundPort := range cfg.ExcludeOutboundPorts {
+			insertRule(ProxyOutputChain, "tcp", "dport", outboundPort, "return")
+		}
+
+		for _, outboundIP := range cfg.ExcludeOutboundCIDRs {
+			insertRule(ProxyOutputChain, "ip", "daddr", outboundIP, "return")
+		}
+
+		for _, uid := range cfg.ExcludeUIDs {
+			insertRule(ProxyOutputChain, "meta", "skuid", uid, "return")
+		}
+	}
+
+	// Configure inbound rules.
+	{
+		// Redirects inbound TCP traffic hitting the PROXY_IN_REDIRECT chain to Envoy's inbound listener port.
+		addRule(ProxyInboundRedirectChain, "meta", "l4proto", "tcp", "redirect", "to", ":"+strconv.Itoa(cfg.ProxyInboundPort))
+
+		// For inbound traffic jump from PREROUTING chain to PROXY_INBOUND chain.
+		addRule(nftablesPreroutingChain, "meta", "l4proto", "tcp", "jump", ProxyInboundChain)
+
+		// Redirect remaining inbound traffic to Envoy.
+		addRule(ProxyInboundChain, "meta", "l4proto", "tcp", "jump", ProxyInboundRedirectChain)
+
+		for _, inboundPort := range cfg.ExcludeInboundPorts {
+			insertRule(ProxyInboundChain, "tcp", "dport", inboundPort, "return")
+		}
+	}
+
+	return p.ApplyRules()
+}